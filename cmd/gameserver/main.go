@@ -11,14 +11,27 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/udisondev/la2go/internal/admin"
 	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/clan"
 	"github.com/udisondev/la2go/internal/config"
 	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/friend"
 	"github.com/udisondev/la2go/internal/gameserver"
+	"github.com/udisondev/la2go/internal/geo"
 	"github.com/udisondev/la2go/internal/gslistener"
+	"github.com/udisondev/la2go/internal/karma"
 	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/privatestore"
+	"github.com/udisondev/la2go/internal/punishment"
+	"github.com/udisondev/la2go/internal/quest"
+	"github.com/udisondev/la2go/internal/ratelimit"
+	"github.com/udisondev/la2go/internal/script"
 	"github.com/udisondev/la2go/internal/spawn"
+	"github.com/udisondev/la2go/internal/teleport"
 	"github.com/udisondev/la2go/internal/world"
+	"github.com/udisondev/la2go/internal/zone"
 )
 
 const (
@@ -107,8 +120,16 @@ func run(ctx context.Context) error {
 	gsTable := gameserver.NewGameServerTable(database)
 	slog.Info("GameServer table initialized")
 
+	// Bans, chat bans, trade bans and jail, enforced by the login and game servers
+	punishments := punishment.NewManager(db.NewPunishmentRepository(database.Pool()))
+	punishmentCount, err := punishments.Load(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("punishments loaded", "active", punishmentCount)
+
 	// Create login server (clients on :2106)
-	loginServer, err := login.NewServer(loginCfg, database)
+	loginServer, err := login.NewServer(loginCfg, database, login.WithBans(punishments))
 	if err != nil {
 		return fmt.Errorf("creating login server: %w", err)
 	}
@@ -118,20 +139,189 @@ func run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("creating gslistener server: %w", err)
 	}
+	// Logging in again kicks the account from remote game servers
+	loginServer.AddKicker(gsListener)
+
+	// Private stores (+ offline trade)
+	itemRepo := db.NewItemRepository(database.Pool())
+	charRepo := db.NewCharacterRepository(database.Pool())
+	// Items created in memory (split stacks, rewards) take their IDs from the items sequence
+	model.SetItemIDSource(db.NewItemIDAllocator(database.Pool(), 0))
+	storeSvc := privatestore.NewService(gameCfg.MaxPvtStoreSlots)
+	clans := clan.NewManager(db.NewClanRepository(database.Pool()), clan.DefaultConfig())
+	if err := clans.Load(ctx); err != nil {
+		return fmt.Errorf("loading clans: %w", err)
+	}
+	slog.Info("clans loaded", "count", clans.Count())
+
+	// Create AI tick manager and Spawn manager (the game handler looks up spawned NPCs)
+	aiMgr := ai.NewShardedTickManager(ai.TickConfig{Shards: gameCfg.AIShards})
+	spawnMgr := spawn.NewManager(npcRepo, spawnRepo, worldInstance, aiMgr)
+	if err := spawnMgr.LoadSpawns(ctx); err != nil {
+		return fmt.Errorf("loading spawns: %w", err)
+	}
+	respawnMgr := spawn.NewRespawnTaskManager(spawnMgr)
+
+	// Geodata (terrain heights, walls); regions without files stay passthrough
+	var paths *geo.Pathfinder
+	if gameCfg.GeoDataDir != "" {
+		geoData := geo.NewEngine()
+		if _, err := geoData.LoadDir(gameCfg.GeoDataDir); err != nil {
+			return fmt.Errorf("loading geodata: %w", err)
+		}
+		spawnMgr.SetGeo(geoData)
+		paths = geo.NewPathfinder(geoData, geo.PathConfig{
+			MaxNodes: gameCfg.PathfindingMaxNodes,
+			Timeout:  time.Duration(gameCfg.PathfindingTimeout) * time.Millisecond,
+		})
+	}
+
+	// World zones: peace, PvP and siege fields, towns, swamps, damage zones
+	zones := zone.NewManager()
+	zoneCount, err := zones.LoadFile(gameCfg.ZonesFile)
+	if err != nil {
+		return fmt.Errorf("loading zones: %w", err)
+	}
+	mapRegions, err := zone.LoadMapRegionsFile(gameCfg.MapRegionsFile)
+	if err != nil {
+		return fmt.Errorf("loading map regions: %w", err)
+	}
+	zones.SetMapRegions(mapRegions)
+	slog.Info("zones loaded", "file", gameCfg.ZonesFile, "count", zoneCount, "map_regions", mapRegions.Len())
+
+	// Gatekeeper teleport lists
+	teleports, err := teleport.LoadTableFile(gameCfg.TeleportsFile)
+	if err != nil {
+		return fmt.Errorf("loading teleports: %w", err)
+	}
+	slog.Info("teleports loaded", "file", gameCfg.TeleportsFile, "gatekeepers", teleports.Len())
+
+	// GM access levels and the audit log of GM actions
+	access, err := admin.LoadAccessFile(gameCfg.AccessLevelsFile)
+	if err != nil {
+		return fmt.Errorf("loading access levels: %w", err)
+	}
+	audit, err := admin.OpenAuditLog(gameCfg.GMAuditLogFile)
+	if err != nil {
+		return fmt.Errorf("opening GM audit log: %w", err)
+	}
+	defer audit.Close()
+	slog.Info("access levels loaded", "file", gameCfg.AccessLevelsFile, "gm_levels", access.Len(), "audit_log", gameCfg.GMAuditLogFile)
+
+	// Packet rate limits, reloaded when the file changes
+	rateLimits, err := ratelimit.LoadRulesFile(gameCfg.RateLimitsFile, gameserver.DefaultRateLimits())
+	if err != nil {
+		return fmt.Errorf("loading rate limits: %w", err)
+	}
+	limiter := ratelimit.NewLimiter(rateLimits)
+	slog.Info("rate limits loaded", "file", gameCfg.RateLimitsFile, "rules", len(rateLimits.ByOpcode))
+
+	gameOpts := []gameserver.Option{
+		gameserver.WithPrivateStores(storeSvc),
+		gameserver.WithInventoryStore(itemRepo),
+		gameserver.WithClans(clans),
+		gameserver.WithFriends(friend.NewManager(db.NewFriendRepository(database.Pool()), friend.DefaultConfig())),
+		gameserver.WithQuests(quest.NewManager(db.NewQuestRepository(database.Pool()))),
+		gameserver.WithNpcs(spawnMgr),
+		gameserver.WithNpcSpawner(spawnMgr),
+		gameserver.WithNpcCombat(aiMgr, respawnMgr),
+		gameserver.WithZones(zones),
+		gameserver.WithTeleports(teleports, teleport.Discounts{
+			FreeLevel:    int32(gameCfg.TeleportFreeLevel),
+			WeekendNight: gameCfg.TeleportWeekendDiscount,
+		}),
+		gameserver.WithDelevel(gameCfg.DeathDelevel),
+		gameserver.WithKarma(karmaConfig(gameCfg)),
+		gameserver.WithAccounts(database),
+		gameserver.WithCharacters(newAccountCharacterLoader(charRepo, itemRepo)),
+		gameserver.WithAdmin(access, audit),
+		gameserver.WithPunishments(punishments),
+		gameserver.WithLoginBlocks(loginServer.FailedLogins()),
+		gameserver.WithRateLimits(limiter),
+	}
+	if paths != nil {
+		gameOpts = append(gameOpts, gameserver.WithPathfinder(paths))
+	}
+
+	// Scripted NPCs (datapack scripts, hot-reloaded while the server runs)
+	var scripts *script.Runtime
+	if gameCfg.ScriptsDir != "" {
+		limits := script.DefaultLimits()
+		limits.Steps = gameCfg.ScriptStepLimit
+		limits.Time = time.Duration(gameCfg.ScriptTimeLimit) * time.Millisecond
+		scripts = script.NewRuntime(gameCfg.ScriptsDir, spawnMgr, limits)
+		if _, err := scripts.Load(ctx); err != nil {
+			return fmt.Errorf("loading scripts: %w", err)
+		}
+		slog.Info("scripts loaded", "dir", gameCfg.ScriptsDir, "count", scripts.Count())
+		spawnMgr.SetControllerFactory(scripts.NewController)
+		gameOpts = append(gameOpts, gameserver.WithScripts(scripts))
+	}
+
+	if gameCfg.OfflineTradeEnable {
+		offlineStores := privatestore.NewOfflineStores(
+			db.NewOfflineTradeRepository(database.Pool()),
+			newStorePlayerLoader(charRepo, itemRepo),
+		)
+		if gameCfg.RestoreOffliners {
+			restored, err := offlineStores.Restore(ctx, storeSvc, worldInstance)
+			if err != nil {
+				return fmt.Errorf("restoring offline stores: %w", err)
+			}
+			slog.Info("offline stores restored", "count", restored)
+		}
+		gameOpts = append(gameOpts, gameserver.WithOfflineStores(offlineStores))
+	}
 
 	// Create game server (game clients on :7777)
-	gameServer, err := gameserver.NewServer(gameCfg, loginServer.SessionManager())
+	gameServer, err := gameserver.NewServer(gameCfg, loginServer.SessionManager(), gameOpts...)
 	if err != nil {
 		return fmt.Errorf("creating game server: %w", err)
 	}
+	loginServer.AddKicker(gameServer.Handler())
+
+	// Monsters hunt players: aggro, hate list, chase and return home
+	monsterCfg := gameServer.Handler().AttackableConfig()
+	spawnMgr.SetMonsterControllerFactory(func(m *model.Monster) ai.Controller {
+		return ai.NewAttackableAI(m, monsterCfg)
+	})
+
+	// Spawn territories: the data file is imported into the database, which is the source of truth
+	territoryRepo := db.NewTerritoryRepository(database.Pool())
+	imported, err := spawn.LoadTerritoriesFile(gameCfg.TerritoriesFile)
+	if err != nil {
+		return fmt.Errorf("loading spawn territories: %w", err)
+	}
+	if len(imported) > 0 {
+		if err := territoryRepo.Import(ctx, imported); err != nil {
+			return fmt.Errorf("importing spawn territories: %w", err)
+		}
+	}
+	territories, err := territoryRepo.LoadAll(ctx)
+	if err != nil {
+		return fmt.Errorf("loading spawn territories: %w", err)
+	}
+	slog.Info("spawn territories loaded", "imported", len(imported), "count", len(territories))
+	spawnMgr.SetTerritories(territories)
+
+	// Idle NPCs random walk around their spawn points or patrol their routes
+	routes, err := spawn.LoadRoutesFile(gameCfg.RoutesFile)
+	if err != nil {
+		return fmt.Errorf("loading patrol routes: %w", err)
+	}
+	slog.Info("patrol routes loaded", "file", gameCfg.RoutesFile, "count", len(routes))
+	walkCfg := gameServer.Handler().WalkConfig()
+	walkCfg.Radius = int32(gameCfg.RandomWalkRadius)
+	walkCfg.Rate = gameCfg.RandomWalkRate
+	spawnMgr.SetRoutes(routes)
+	spawnMgr.SetWalking(walkCfg)
 
 	// Run all three servers + AI/Respawn managers in parallel
 	g, gctx := errgroup.WithContext(ctx)
 
-	// Create AI tick manager
-	aiMgr := ai.NewTickManager()
+	// Start AI tick manager
 	g.Go(func() error {
-		slog.Info("starting AI tick manager", "interval", "1s")
+		slog.Info("starting AI tick manager", "shards", len(aiMgr.Stats()))
 		if err := aiMgr.Start(gctx); err != nil {
 			return fmt.Errorf("AI tick manager: %w", err)
 		}
@@ -148,14 +338,17 @@ func run(ctx context.Context) error {
 		return nil
 	})
 
-	// Create Spawn manager
-	spawnMgr := spawn.NewManager(npcRepo, spawnRepo, worldInstance, aiMgr)
-	if err := spawnMgr.LoadSpawns(ctx); err != nil {
-		return fmt.Errorf("loading spawns: %w", err)
+	if scripts != nil {
+		g.Go(func() error {
+			slog.Info("watching scripts", "dir", gameCfg.ScriptsDir, "interval", "2s")
+			return scripts.Watch(gctx, 2*time.Second)
+		})
 	}
+	g.Go(func() error {
+		return limiter.Watch(gctx, gameCfg.RateLimitsFile, gameserver.DefaultRateLimits(), 2*time.Second)
+	})
 
-	// Create Respawn task manager
-	respawnMgr := spawn.NewRespawnTaskManager(spawnMgr)
+	// Respawn task manager brings killed NPCs back
 	g.Go(func() error {
 		slog.Info("starting respawn task manager", "interval", "1s")
 		if err := respawnMgr.Start(gctx); err != nil {
@@ -211,6 +404,46 @@ func run(ctx context.Context) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		slog.Info("starting party position updates", "interval", "1s")
+		if err := gameServer.Handler().RunPartyUpdates(gctx); err != nil {
+			return fmt.Errorf("party updates: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		slog.Info("starting player movement", "interval", "100ms")
+		if err := gameServer.Handler().RunMovement(gctx); err != nil {
+			return fmt.Errorf("player movement: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		slog.Info("starting zone effects")
+		if err := gameServer.Handler().RunZoneEffects(gctx); err != nil {
+			return fmt.Errorf("zone effects: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		slog.Info("starting punishment expiry")
+		if err := gameServer.Handler().RunPunishments(gctx); err != nil {
+			return fmt.Errorf("punishments: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		slog.Info("starting clan dissolution checks")
+		if err := gameServer.Handler().RunClanUpdates(gctx); err != nil {
+			return fmt.Errorf("clan updates: %w", err)
+		}
+		return nil
+	})
+
 	// Wait for all servers to finish
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("server error: %w", err)
@@ -219,6 +452,78 @@ func run(ctx context.Context) error {
 	return nil
 }
 
+// newStorePlayerLoader loads a character with its inventory for offline store restore.
+func newStorePlayerLoader(chars *db.CharacterRepository, items *db.ItemRepository) privatestore.PlayerLoaderFunc {
+	return func(ctx context.Context, characterID int64) (*model.Player, error) {
+		player, err := chars.LoadByID(ctx, characterID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil {
+			return nil, fmt.Errorf("character %d not found", characterID)
+		}
+
+		inv, err := items.LoadInventory(ctx, characterID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range inv {
+			player.Inventory().AddItem(item)
+		}
+		return player, nil
+	}
+}
+
+// newAccountCharacterLoader loads the characters of an account with their inventories
+// and equipment for the character selection screen.
+func newAccountCharacterLoader(chars *db.CharacterRepository, items *db.ItemRepository) gameserver.CharacterLoaderFunc {
+	return func(ctx context.Context, accountID int64) ([]*model.Player, error) {
+		players, err := chars.LoadByAccountID(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		for _, player := range players {
+			inv, err := items.LoadInventory(ctx, player.CharacterID())
+			if err != nil {
+				return nil, err
+			}
+			equipped, err := items.LoadPaperdoll(ctx, player.CharacterID())
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range append(inv, equipped...) {
+				player.Inventory().AddItem(item)
+			}
+		}
+		return players, nil
+	}
+}
+
+// karmaConfig builds the PvP and karma rules from the game server config.
+func karmaConfig(cfg config.GameServer) karma.Config {
+	nonDroppable := make([]int32, 0, len(cfg.KarmaNonDroppable))
+	for _, id := range cfg.KarmaNonDroppable {
+		nonDroppable = append(nonDroppable, int32(id))
+	}
+	return karma.Config{
+		NormalFlagTime:      time.Duration(cfg.PvPNormalTime) * time.Millisecond,
+		PvPFlagTime:         time.Duration(cfg.PvPPvPTime) * time.Millisecond,
+		MinKarma:            int32(cfg.KarmaMinKarma),
+		MaxKarma:            int32(cfg.KarmaMaxKarma),
+		XPDivider:           int64(cfg.KarmaXPDivider),
+		LostBase:            int32(cfg.KarmaLostBase),
+		AwardPKKill:         cfg.KarmaAwardPKKill,
+		ExpLostRate:         cfg.KarmaExpLostRate,
+		PKLimit:             int32(cfg.KarmaPKLimit),
+		DropChance:          int32(cfg.KarmaDropChance),
+		ItemDropChance:      int32(cfg.KarmaItemDropChance),
+		EquipmentDropChance: int32(cfg.KarmaEquipmentDropChance),
+		WeaponDropChance:    int32(cfg.KarmaWeaponDropChance),
+		DropLimit:           cfg.KarmaDropLimit,
+		NonDroppable:        nonDroppable,
+	}
+}
+
 // parseLogLevel converts string log level to slog.Level.
 // Defaults to Info if invalid or empty.
 func parseLogLevel(level string) slog.Level {
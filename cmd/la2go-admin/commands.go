@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/passhash"
+	"github.com/udisondev/la2go/internal/punishment"
+)
+
+// punishedBy is recorded as the author of bans given with this tool.
+const punishedBy = "la2go-admin"
+
+// characterName is the form of character names the client accepts.
+var characterName = regexp.MustCompile(`^[A-Za-z0-9]{2,16}$`)
+
+// env is what commands work with; every change goes through one transaction.
+type env struct {
+	repo        *db.AdminRepository
+	punishments *db.PunishmentRepository
+	passwords   *passhash.Hasher
+	password    string // read from stdin for commands that set one
+}
+
+// action applies a command to the database.
+type action func(ctx context.Context, e *env) (result, error)
+
+// command is a la2go-admin subcommand. parse checks the arguments before anything
+// connects to the database. Commands that set a password read it from stdin, so it
+// never shows in the process list or the shell history.
+type command struct {
+	usage    string
+	help     string
+	parse    func(args []string) (action, error)
+	password bool
+}
+
+func commands() map[string]command {
+	return map[string]command{
+		"create-account": {"[-access level] <login>", "create an account; the password is read from stdin", createAccount, true},
+		"set-password":   {"<login>", "change the password of an account; the new one is read from stdin", setPassword, true},
+		"set-access":     {"<login> <level>", "set the access level of an account (GM levels are positive)", setAccess, false},
+		"ban":            {"[-ip] [-minutes n] [-reason text] <login|ip>", "ban an account or IP address; no -minutes is permanent", ban, false},
+		"unban":          {"[-ip] <login|ip>", "lift the bans of an account or IP address", unban, false},
+		"characters":     {"<login>", "list the characters of an account", characters, false},
+		"rename":         {"<name> <new name>", "rename an offline character", rename, false},
+		"move":           {"<name> <x> <y> <z>", "move an offline character", move, false},
+		"register-gs":    {"<server id> <hexid> [host]", "register a game server", registerGameServer, false},
+		"unregister-gs":  {"<server id>", "remove a game server registration", unregisterGameServer, false},
+	}
+}
+
+// parseFlags parses the flags of a command and checks the number of arguments left.
+func parseFlags(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil || fs.NArg() != want {
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+// readPassword reads a password: from a hidden prompt, asked twice, when stdin is a terminal,
+// otherwise from the first line of stdin.
+func readPassword(stdin io.Reader, prompt io.Writer) (string, error) {
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		password, err := promptPassword(f, prompt, "Password: ")
+		if err != nil {
+			return "", err
+		}
+		repeated, err := promptPassword(f, prompt, "Repeat password: ")
+		if err != nil {
+			return "", err
+		}
+		if password != repeated {
+			return "", errors.New("passwords do not match")
+		}
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return password, nil
+}
+
+// promptPassword reads a password from the terminal without echoing it.
+func promptPassword(tty *os.File, prompt io.Writer, text string) (string, error) {
+	fmt.Fprint(prompt, text)
+	password, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(prompt)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	if len(password) == 0 {
+		return "", errors.New("empty password")
+	}
+	return string(password), nil
+}
+
+func parseInt32(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, errUsage
+	}
+	return int32(v), nil
+}
+
+func createAccount(args []string) (action, error) {
+	fs := flag.NewFlagSet("create-account", flag.ContinueOnError)
+	access := fs.Int("access", 0, "access level")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	login := strings.ToLower(args[0])
+	return func(ctx context.Context, e *env) (result, error) {
+		hash, err := e.passwords.Hash(ctx, e.password)
+		if err != nil {
+			return result{}, err
+		}
+		if err := e.repo.CreateAccount(ctx, login, hash, int32(*access)); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("account %s created with access level %d", login, *access)}, nil
+	}, nil
+}
+
+func setPassword(args []string) (action, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	login := strings.ToLower(args[0])
+	return func(ctx context.Context, e *env) (result, error) {
+		hash, err := e.passwords.Hash(ctx, e.password)
+		if err != nil {
+			return result{}, err
+		}
+		if err := e.repo.SetPassword(ctx, login, hash); err != nil {
+			return result{}, err
+		}
+		return result{Message: "password of " + login + " changed"}, nil
+	}, nil
+}
+
+func setAccess(args []string) (action, error) {
+	if len(args) != 2 {
+		return nil, errUsage
+	}
+	login := strings.ToLower(args[0])
+	level, err := parseInt32(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		if err := e.repo.SetAccessLevel(ctx, login, level); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("access level of %s set to %d", login, level)}, nil
+	}, nil
+}
+
+// banTarget returns what a ban or unban applies to.
+func banTarget(ip bool, key string) (punishment.Affect, string) {
+	if ip {
+		return punishment.IP, punishment.NormalizeKey(punishment.IP, key)
+	}
+	return punishment.Account, punishment.NormalizeKey(punishment.Account, key)
+}
+
+func ban(args []string) (action, error) {
+	fs := flag.NewFlagSet("ban", flag.ContinueOnError)
+	ip := fs.Bool("ip", false, "ban an IP address")
+	minutes := fs.Int("minutes", 0, "ban length; 0 = permanent")
+	reason := fs.String("reason", "", "reason shown to GMs")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil || *minutes < 0 {
+		return nil, errUsage
+	}
+
+	affect, key := banTarget(*ip, args[0])
+	p := punishment.Punishment{Affect: affect, Key: key, Type: punishment.Ban, Reason: *reason, PunishedBy: punishedBy}
+	until := "permanently"
+	if *minutes > 0 {
+		p.Expires = time.Now().Add(time.Duration(*minutes) * time.Minute)
+		until = "until " + p.Expires.Format("2006-01-02 15:04")
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		id, err := e.punishments.Add(ctx, p)
+		if err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("%s %s banned %s (punishment %d)", affect, key, until, id)}, nil
+	}, nil
+}
+
+func unban(args []string) (action, error) {
+	fs := flag.NewFlagSet("unban", flag.ContinueOnError)
+	ip := fs.Bool("ip", false, "unban an IP address")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	affect, key := banTarget(*ip, args[0])
+	return func(ctx context.Context, e *env) (result, error) {
+		n, err := e.repo.RemovePunishments(ctx, affect, key, punishment.Ban)
+		if err != nil {
+			return result{}, err
+		}
+		if n == 0 {
+			return result{}, fmt.Errorf("%s %s is not banned", affect, key)
+		}
+		return result{Message: fmt.Sprintf("%s %s unbanned (%d bans lifted)", affect, key, n)}, nil
+	}, nil
+}
+
+func characters(args []string) (action, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	login := strings.ToLower(args[0])
+	return func(ctx context.Context, e *env) (result, error) {
+		list, err := e.repo.Characters(ctx, login)
+		if err != nil {
+			return result{}, err
+		}
+		return result{
+			Message:    fmt.Sprintf("account %s has %d characters", login, len(list)),
+			Characters: list,
+		}, nil
+	}, nil
+}
+
+func rename(args []string) (action, error) {
+	if len(args) != 2 {
+		return nil, errUsage
+	}
+	name, newName := args[0], args[1]
+	if !characterName.MatchString(newName) {
+		return nil, fmt.Errorf("%q is not a valid character name: 2-16 letters and digits", newName)
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		if err := e.repo.RenameCharacter(ctx, name, newName); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("%s renamed to %s", name, newName)}, nil
+	}, nil
+}
+
+func move(args []string) (action, error) {
+	if len(args) != 4 {
+		return nil, errUsage
+	}
+	name := args[0]
+	var xyz [3]int32
+	for i, s := range args[1:] {
+		v, err := parseInt32(s)
+		if err != nil {
+			return nil, err
+		}
+		xyz[i] = v
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		if err := e.repo.MoveCharacter(ctx, name, xyz[0], xyz[1], xyz[2]); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("%s moved to %d %d %d", name, xyz[0], xyz[1], xyz[2])}, nil
+	}, nil
+}
+
+func registerGameServer(args []string) (action, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errUsage
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return nil, errUsage
+	}
+	hexID := strings.ToLower(args[1])
+	if _, err := hex.DecodeString(hexID); err != nil || hexID == "" {
+		return nil, fmt.Errorf("hexid %q is not a hex string", args[1])
+	}
+	host := ""
+	if len(args) == 3 {
+		host = args[2]
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		if err := e.repo.RegisterGameServer(ctx, id, hexID, host); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("game server %d registered with hexid %s", id, hexID)}, nil
+	}, nil
+}
+
+func unregisterGameServer(args []string) (action, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, errUsage
+	}
+	return func(ctx context.Context, e *env) (result, error) {
+		if err := e.repo.UnregisterGameServer(ctx, id); err != nil {
+			return result{}, err
+		}
+		return result{Message: fmt.Sprintf("game server %d unregistered", id)}, nil
+	}, nil
+}
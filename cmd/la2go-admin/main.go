@@ -0,0 +1,186 @@
+// Command la2go-admin edits accounts, characters and game server registrations in the
+// database shared by the login and game servers.
+//
+//	la2go-admin [-config path] [-dry-run] [-json] <command> [arguments]
+//
+// Every command runs in one transaction; with -dry-run it is rolled back, so the output
+// shows what would change. With -json the result is printed as one JSON object.
+// Passwords are never arguments: they are read from stdin, or asked for when stdin is
+// a terminal.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/udisondev/la2go/internal/config"
+	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/passhash"
+)
+
+const ConfigPath = "config/loginserver.yaml"
+
+// errUsage reports wrong command arguments; the usage of the command is printed.
+var errUsage = errors.New("wrong arguments")
+
+// result is the outcome of a command.
+type result struct {
+	Command    string                `json:"command"`
+	DryRun     bool                  `json:"dry_run"`
+	OK         bool                  `json:"ok"`
+	Message    string                `json:"message,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	Characters []db.CharacterSummary `json:"characters,omitempty"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfgPath := ConfigPath
+	if p := os.Getenv("LA2GO_CONFIG"); p != "" {
+		cfgPath = p
+	}
+
+	fs := flag.NewFlagSet("la2go-admin", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfgPath, "config", cfgPath, "login server config with the database settings")
+	dryRun := fs.Bool("dry-run", false, "show what would change without saving it")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	name, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	cmd, ok := commands()[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		fs.Usage()
+		return 2
+	}
+
+	var res result
+	act, err := cmd.parse(cmdArgs)
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(stderr, "usage: la2go-admin %s %s\n", name, cmd.usage)
+		return 2
+	}
+	var password string
+	if err == nil && cmd.password {
+		password, err = readPassword(stdin, stderr)
+	}
+	if err == nil {
+		res, err = execute(ctx, cfgPath, *dryRun, act, password)
+	}
+	res.Command, res.DryRun = name, *dryRun
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.OK = true
+	}
+	report(stdout, stderr, res, *asJSON)
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// execute runs act in a transaction that is committed unless dryRun is set;
+// password is what the command read from stdin.
+func execute(ctx context.Context, cfgPath string, dryRun bool, act action, password string) (result, error) {
+	cfg, err := config.LoadLoginServer(cfgPath)
+	if err != nil {
+		return result{}, fmt.Errorf("loading config: %w", err)
+	}
+
+	database, err := db.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		return result{}, fmt.Errorf("connecting to database: %w", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Pool().Begin(ctx)
+	if err != nil {
+		return result{}, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // no-op after Commit
+
+	env := &env{
+		repo:        db.NewAdminRepository(tx),
+		punishments: db.NewPunishmentRepository(tx),
+		passwords: passhash.NewHasher(passhash.Params{
+			Memory:      uint32(cfg.PasswordHashMemory),
+			Iterations:  uint32(cfg.PasswordHashIterations),
+			Parallelism: uint8(cfg.PasswordHashParallelism),
+		}, 1),
+		password: password,
+	}
+	res, err := act(ctx, env)
+	if err != nil || dryRun {
+		return res, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return res, fmt.Errorf("committing: %w", err)
+	}
+	return res, nil
+}
+
+// report prints the result as text or JSON.
+func report(stdout, stderr io.Writer, res result, asJSON bool) {
+	if asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+		return
+	}
+
+	if res.Error != "" {
+		fmt.Fprintln(stderr, "error:", res.Error)
+		return
+	}
+	if res.DryRun {
+		fmt.Fprint(stdout, "dry run, nothing saved: ")
+	}
+	fmt.Fprintln(stdout, res.Message)
+	for _, c := range res.Characters {
+		last := "never"
+		if c.LastLogin != nil {
+			last = c.LastLogin.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(stdout, "  %-8d %-16s level %-2d class %-3d at %d %d %d, last login %s\n",
+			c.ID, c.Name, c.Level, c.ClassID, c.X, c.Y, c.Z, last)
+	}
+}
+
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "usage: la2go-admin [flags] <command> [arguments]")
+	fmt.Fprintln(out, "\nflags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(out, "\ncommands:")
+	cmds := commands()
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s %s\n    \t%s\n", name, cmds[name].usage, cmds[name].help)
+	}
+}
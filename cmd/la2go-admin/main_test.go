@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCommands_Parse(t *testing.T) {
+	cmds := commands()
+	tests := []struct {
+		cmd  string
+		args []string
+		ok   bool
+	}{
+		{"create-account", []string{"alice"}, true},
+		{"create-account", []string{"-access", "100", "alice"}, true},
+		{"create-account", []string{"alice", "secret"}, false},
+		{"set-password", []string{"alice"}, true},
+		{"set-password", []string{"alice", "secret"}, false},
+		{"set-access", []string{"alice", "gm"}, false},
+		{"ban", []string{"-minutes", "60", "-reason", "botting", "alice"}, true},
+		{"ban", []string{"-minutes", "-5", "alice"}, false},
+		{"unban", []string{"-ip", "10.0.0.1"}, true},
+		{"characters", []string{"alice"}, true},
+		{"characters", []string{"alice", "bob"}, false},
+		{"rename", []string{"Old", "New"}, true},
+		{"rename", []string{"Old", "No spaces"}, false},
+		{"move", []string{"Hero", "17000", "170000", "-3500"}, true},
+		{"move", []string{"Hero", "17000", "170000"}, false},
+		{"register-gs", []string{"2", "C0A80001", "10.0.0.2"}, true},
+		{"register-gs", []string{"2", "not-hex"}, false},
+		{"unregister-gs", []string{"two"}, false},
+	}
+	for _, tc := range tests {
+		act, err := cmds[tc.cmd].parse(tc.args)
+		if ok := err == nil && act != nil; ok != tc.ok {
+			t.Errorf("%s %v: parse error = %v, want ok %v", tc.cmd, tc.args, err, tc.ok)
+		}
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	ctx := context.Background()
+	var stdout, stderr bytes.Buffer
+
+	if code := run(ctx, []string{"drop-tables"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("unknown command: exit %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "create-account") {
+		t.Errorf("unknown command did not list commands: %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run(ctx, []string{"set-password", "alice", "secret"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("password argument: exit %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "usage: la2go-admin set-password <login>") {
+		t.Errorf("password argument: %q", stderr.String())
+	}
+
+	// A missing password is reported before the database is touched
+	stdout.Reset()
+	if code := run(ctx, []string{"-json", "create-account", "alice"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("no password: exit %d, want 1", code)
+	}
+	if !strings.Contains(stdout.String(), "no password on stdin") {
+		t.Errorf("no password: %q", stdout.String())
+	}
+
+	// Invalid values are reported before the database is touched
+	stdout.Reset()
+	if code := run(ctx, []string{"-json", "-dry-run", "rename", "Old", "x"}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("invalid name: exit %d, want 1", code)
+	}
+	var res result
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		t.Fatalf("JSON output: %v in %q", err, stdout.String())
+	}
+	if res.OK || res.Command != "rename" || !res.DryRun || !strings.Contains(res.Error, "not a valid character name") {
+		t.Errorf("JSON result = %+v", res)
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	tests := []struct {
+		stdin string
+		want  string
+		ok    bool
+	}{
+		{"secret\n", "secret", true},
+		{"secret\r\nignored\n", "secret", true},
+		{"with spaces", "with spaces", true},
+		{"\n", "", false},
+		{"", "", false},
+	}
+	for _, tc := range tests {
+		got, err := readPassword(strings.NewReader(tc.stdin), io.Discard)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("readPassword(%q) = %q, %v; want %q, ok %v", tc.stdin, got, err, tc.want, tc.ok)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -15,10 +16,14 @@ import (
 	"github.com/udisondev/la2go/internal/gameserver"
 	"github.com/udisondev/la2go/internal/gslistener"
 	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/punishment"
 )
 
 const ConfigPath = "config/loginserver.yaml"
 
+// punishmentReloadInterval is how often bans given by game servers and tools are picked up.
+const punishmentReloadInterval = 30 * time.Second
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -74,8 +79,16 @@ func run(ctx context.Context) error {
 	gsTable := gameserver.NewGameServerTable(database)
 	slog.Info("GameServer table initialized")
 
+	// Bans of accounts and IP addresses
+	punishments := punishment.NewManager(db.NewPunishmentRepository(database.Pool()))
+	punishmentCount, err := punishments.Load(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("punishments loaded", "active", punishmentCount)
+
 	// Create login server (clients on :2106)
-	loginServer, err := login.NewServer(cfg, database)
+	loginServer, err := login.NewServer(cfg, database, login.WithBans(punishments))
 	if err != nil {
 		return fmt.Errorf("creating login server: %w", err)
 	}
@@ -85,6 +98,8 @@ func run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("creating gslistener server: %w", err)
 	}
+	// Logging in again kicks the account from the game server it plays on
+	loginServer.AddKicker(gsListener)
 
 	// Run both servers in parallel
 	g, gctx := errgroup.WithContext(ctx)
@@ -105,6 +120,10 @@ func run(ctx context.Context) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return punishments.Run(gctx, punishmentReloadInterval, nil)
+	})
+
 	// Wait for both servers to finish
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("server error: %w", err)
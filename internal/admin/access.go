@@ -0,0 +1,125 @@
+// Package admin holds what GM commands need besides the game world: access levels with
+// the commands they allow and the audit log of GM actions.
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidAccess is returned for access level data that cannot be used
+var ErrInvalidAccess = errors.New("invalid access levels")
+
+// allCommands in a command list grants every command
+const allCommands = "*"
+
+// Level is a GM access level and the commands it allows.
+type Level struct {
+	Level    int32
+	Name     string
+	commands map[string]bool // nil = every command
+}
+
+// Allows reports whether GMs of this level may run command.
+func (l *Level) Allows(command string) bool {
+	return l.commands == nil || l.commands[command]
+}
+
+// Access maps account access levels to GM permissions. Immutable after loading.
+// Accounts with levels not listed (0 for players, negative for bans) are not GMs.
+type Access struct {
+	levels map[int32]*Level
+}
+
+// Level returns the GM level of an account access level.
+func (a *Access) Level(level int32) (*Level, bool) {
+	if a == nil {
+		return nil, false
+	}
+	l, ok := a.levels[level]
+	return l, ok
+}
+
+// Allowed reports whether accounts of access level may run command.
+func (a *Access) Allowed(level int32, command string) bool {
+	l, ok := a.Level(level)
+	return ok && l.Allows(command)
+}
+
+// Len returns the number of GM levels.
+func (a *Access) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.levels)
+}
+
+// accessFile is the YAML layout of access levels:
+//
+//	levels:
+//	  - level: 100
+//	    name: Administrator
+//	    commands: ["*"]
+//	  - level: 30
+//	    name: Support
+//	    commands: [admin, teleportto, recall, heal, kick, announce]
+type accessFile struct {
+	Levels []struct {
+		Level    int32    `yaml:"level"`
+		Name     string   `yaml:"name"`
+		Commands []string `yaml:"commands"`
+	} `yaml:"levels"`
+}
+
+// LoadAccess parses access levels.
+func LoadAccess(r io.Reader) (*Access, error) {
+	var f accessFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing access levels: %w", err)
+	}
+
+	a := &Access{levels: make(map[int32]*Level, len(f.Levels))}
+	for _, l := range f.Levels {
+		if l.Level <= 0 {
+			return nil, fmt.Errorf("access level %d: GM levels must be positive: %w", l.Level, ErrInvalidAccess)
+		}
+		if _, dup := a.levels[l.Level]; dup {
+			return nil, fmt.Errorf("access level %d defined twice: %w", l.Level, ErrInvalidAccess)
+		}
+		level := &Level{Level: l.Level, Name: l.Name, commands: make(map[string]bool, len(l.Commands))}
+		for _, cmd := range l.Commands {
+			if cmd == allCommands {
+				level.commands = nil
+				break
+			}
+			level.commands[cmd] = true
+		}
+		a.levels[l.Level] = level
+	}
+	return a, nil
+}
+
+// LoadAccessFile loads access levels from a YAML file.
+// A missing file means nobody is a GM.
+func LoadAccessFile(path string) (*Access, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("access levels file not found, GM commands are disabled", "path", path)
+			return &Access{}, nil
+		}
+		return nil, fmt.Errorf("opening access levels %s: %w", path, err)
+	}
+	defer f.Close()
+
+	a, err := LoadAccess(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading access levels %s: %w", path, err)
+	}
+	return a, nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAccess(t *testing.T) {
+	a, err := LoadAccess(strings.NewReader(`
+levels:
+  - level: 100
+    name: Administrator
+    commands: ["*"]
+  - level: 30
+    name: Support
+    commands: [admin, recall, kick]
+`))
+	if err != nil {
+		t.Fatalf("LoadAccess: %v", err)
+	}
+	if a.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", a.Len())
+	}
+	if !a.Allowed(100, "spawn") || !a.Allowed(30, "recall") {
+		t.Error("granted commands are not allowed")
+	}
+	if a.Allowed(30, "spawn") {
+		t.Error("support may not spawn")
+	}
+	if a.Allowed(0, "admin") || a.Allowed(-1, "admin") {
+		t.Error("players and banned accounts are not GMs")
+	}
+	if l, ok := a.Level(30); !ok || l.Name != "Support" {
+		t.Errorf("Level(30) = %+v, %v", l, ok)
+	}
+
+	var none *Access
+	if none.Allowed(100, "admin") {
+		t.Error("nil access allows nothing")
+	}
+}
+
+func TestLoadAccess_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"duplicate": "levels:\n  - {level: 10}\n  - {level: 10}\n",
+		"player":    "levels:\n  - {level: 0, commands: [admin]}\n",
+	} {
+		if _, err := LoadAccess(strings.NewReader(data)); !errors.Is(err, ErrInvalidAccess) {
+			t.Errorf("%s: err = %v, want ErrInvalidAccess", name, err)
+		}
+	}
+}
+
+func TestLoadAccessFile_Missing(t *testing.T) {
+	a, err := LoadAccessFile(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil || a.Len() != 0 {
+		t.Fatalf("LoadAccessFile = %v, %v; want no GM levels", a, err)
+	}
+}
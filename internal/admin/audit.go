@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Action is a GM command run, as it is written to the audit log.
+type Action struct {
+	GM      string // character name
+	Account string
+	Level   int32 // access level of the account
+	Command string
+	Args    []string
+	Target  string // who or what the command affected ("" = nothing in particular)
+	Err     error  // nil = done
+}
+
+// AuditLog writes every GM action to a log of its own, one JSON line per action.
+// A nil AuditLog records nothing.
+type AuditLog struct {
+	logger *slog.Logger
+	closer io.Closer
+}
+
+// NewAuditLog creates an audit log writing to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{logger: slog.New(slog.NewJSONHandler(w, nil))}
+}
+
+// OpenAuditLog appends the audit log to the file at path, creating it and its directory.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log %s: %w", path, err)
+	}
+	l := NewAuditLog(f)
+	l.closer = f
+	return l, nil
+}
+
+// Record writes a GM action.
+func (l *AuditLog) Record(a Action) {
+	if l == nil {
+		return
+	}
+	attrs := []any{
+		"gm", a.GM,
+		"account", a.Account,
+		"access_level", a.Level,
+		"command", a.Command,
+		"args", strings.Join(a.Args, " "),
+	}
+	if a.Target != "" {
+		attrs = append(attrs, "target", a.Target)
+	}
+	if a.Err != nil {
+		l.logger.Warn("gm command failed", append(attrs, "error", a.Err.Error())...)
+		return
+	}
+	l.logger.Info("gm command", attrs...)
+}
+
+// Close closes the log file opened by OpenAuditLog.
+func (l *AuditLog) Close() error {
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
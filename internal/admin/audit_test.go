@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewAuditLog(&buf)
+	l.Record(Action{GM: "Admin", Account: "root", Level: 100, Command: "recall", Args: []string{"Bob"}, Target: "Bob"})
+	l.Record(Action{GM: "Admin", Account: "root", Level: 100, Command: "spawn", Err: errors.New("unknown NPC")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if rec["gm"] != "Admin" || rec["command"] != "recall" || rec["args"] != "Bob" || rec["target"] != "Bob" {
+		t.Errorf("record = %v", rec)
+	}
+	if !strings.Contains(lines[1], `"error":"unknown NPC"`) {
+		t.Errorf("failed action = %s", lines[1])
+	}
+
+	var none *AuditLog
+	none.Record(Action{GM: "x"})
+	if err := none.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "gmaudit.log")
+	l, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	l.Record(Action{GM: "Admin", Command: "announce"})
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
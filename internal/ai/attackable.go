@@ -0,0 +1,342 @@
+package ai
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+const (
+	// AttackRange is the melee reach of monsters (L2J base attack range plus collision radii).
+	AttackRange = 60
+
+	// LeashRange is how far from its spawn point a monster chases before it gives up,
+	// heals and returns home.
+	LeashRange = 1500
+
+	// quietScanInterval is how many tick intervals a monster without players around
+	// sleeps before looking for players again.
+	quietScanInterval = 5
+
+	// aggroHate is the hate for noticing a player in aggro range (L2J adds 1 per scan).
+	aggroHate = 1
+)
+
+// PlayerFinder resolves objects found in the world to online players (implemented by the game handler).
+type PlayerFinder interface {
+	FindPlayer(objectID uint32) (*model.Player, bool)
+}
+
+// Combat performs NPC attacks: damage and Attack packets (implemented by the game handler).
+type Combat interface {
+	Attack(npc *model.Npc, target *model.Player)
+}
+
+// ZoneChecker tells which zones an object is in (implemented by zone.Manager).
+type ZoneChecker interface {
+	InsideZone(obj *model.WorldObject, t zone.Type) bool
+}
+
+// AttackableConfig holds the dependencies of monster AI.
+type AttackableConfig struct {
+	Players      PlayerFinder
+	Combat       Combat        // nil = attacks deal no damage
+	Zones        ZoneChecker   // nil = no peace zones
+	Paths        Pathfinder    // nil = chase in straight lines
+	Listener     MoveListener  // nil = movement is not broadcast
+	TickInterval time.Duration // time between idle ticks, converts move speed to distance
+
+	// CombatTickInterval is the time between ticks while chasing, fighting or returning home
+	// (default TickInterval); monsters still hit at most once per TickInterval.
+	CombatTickInterval time.Duration
+}
+
+// AttackableAI is the AI of monsters and guards: it notices players in aggro range, keeps
+// a hate list, chases and attacks the most hated player, and returns home when dragged too
+// far from its spawn point. Guards only notice players with karma. It ticks fast while fighting; without players around it goes quiet
+// and only checks for players every few intervals.
+type AttackableAI struct {
+	monster *model.Monster
+	cfg     AttackableConfig
+	hate    *HateList
+	mover   *Mover
+	walker  atomic.Pointer[Walker]
+
+	isRunning  atomic.Bool
+	returning  atomic.Bool
+	quiet      atomic.Bool
+	tickCount  atomic.Int32
+	nextAttack atomic.Int32 // first tick at which the monster may hit again
+}
+
+// NewAttackableAI creates the AI of a monster.
+func NewAttackableAI(monster *model.Monster, cfg AttackableConfig) *AttackableAI {
+	if cfg.TickInterval <= 0 {
+		cfg.TickInterval = time.Second
+	}
+	if cfg.CombatTickInterval <= 0 || cfg.CombatTickInterval > cfg.TickInterval {
+		cfg.CombatTickInterval = cfg.TickInterval
+	}
+	return &AttackableAI{
+		monster: monster,
+		cfg:     cfg,
+		hate:    NewHateList(),
+		mover:   NewMover(monster.WorldObject, cfg.Paths, cfg.Listener),
+	}
+}
+
+// Start starts AI controller
+func (ai *AttackableAI) Start() {
+	ai.isRunning.Store(true)
+	ai.SetIntention(model.IntentionActive)
+}
+
+// SetWalker makes the monster random walk or patrol while it has no target
+func (ai *AttackableAI) SetWalker(w *Walker) {
+	ai.walker.Store(w)
+}
+
+// Stop stops AI controller
+func (ai *AttackableAI) Stop() {
+	ai.isRunning.Store(false)
+	ai.mover.Stop()
+	ai.stopWalking()
+	ai.hate.Clear()
+	ai.SetIntention(model.IntentionIdle)
+}
+
+// SetIntention sets AI intention
+func (ai *AttackableAI) SetIntention(intention model.Intention) {
+	old := ai.monster.Intention()
+	ai.monster.SetIntention(intention)
+	if old != intention && IsDebugEnabled() {
+		slog.Debug("AI intention changed",
+			"npc", ai.monster.Name(),
+			"objectID", ai.monster.ObjectID(),
+			"from", old,
+			"to", intention)
+	}
+}
+
+// CurrentIntention returns current AI intention
+func (ai *AttackableAI) CurrentIntention() model.Intention {
+	return ai.monster.Intention()
+}
+
+// HateList returns the hate list of the monster.
+func (ai *AttackableAI) HateList() *HateList {
+	return ai.hate
+}
+
+// IsQuiet reports whether the AI sleeps because no players are around.
+func (ai *AttackableAI) IsQuiet() bool {
+	return ai.quiet.Load()
+}
+
+// OnAttacked adds the damage of an attacker to the hate list; the monster fights back
+// even if it is not aggressive.
+func (ai *AttackableAI) OnAttacked(attacker *model.Player, damage int32) {
+	if ai.returning.Load() || ai.monster.IsDead() {
+		return
+	}
+	ai.hate.Add(attacker.ObjectID(), int64(damage), 0)
+	ai.quiet.Store(false)
+}
+
+// NextTick implements Paced: fast ticks in combat, rare ones while quiet.
+func (ai *AttackableAI) NextTick() time.Duration {
+	switch {
+	case ai.quiet.Load():
+		return quietScanInterval * ai.cfg.TickInterval
+	case ai.returning.Load() || ai.monster.Intention() == model.IntentionAttack:
+		return ai.cfg.CombatTickInterval
+	default:
+		return ai.cfg.TickInterval
+	}
+}
+
+// home returns the spawn point of the monster.
+func (ai *AttackableAI) home() model.Location {
+	return ai.monster.SpawnLocation()
+}
+
+// Tick performs AI tick
+func (ai *AttackableAI) Tick() {
+	if !ai.isRunning.Load() || ai.monster.IsDead() {
+		return
+	}
+	ticks := ai.tickCount.Add(1)
+
+	if ai.returning.Load() {
+		ai.walkHome()
+		return
+	}
+
+	if ai.quiet.Load() {
+		if !ai.playersAround() {
+			return
+		}
+		ai.quiet.Store(false)
+		ai.SetIntention(model.IntentionActive)
+	}
+
+	if ai.hate.Len() == 0 {
+		ai.scanAggro()
+	}
+	target := ai.target()
+	if target == nil {
+		ai.mover.Stop()
+		if !ai.playersAround() {
+			ai.quiet.Store(true)
+			ai.SetIntention(model.IntentionIdle)
+			return
+		}
+		ai.SetIntention(model.IntentionActive)
+		if w := ai.walker.Load(); w != nil {
+			w.Tick()
+		}
+		return
+	}
+	ai.stopWalking()
+
+	if distance(ai.monster.Location(), ai.home()) > LeashRange {
+		ai.startReturn()
+		return
+	}
+	ai.SetIntention(model.IntentionAttack)
+	ai.chaseAndAttack(target, ticks)
+}
+
+// stepDistance is how far the monster walks in one combat tick.
+func (ai *AttackableAI) stepDistance() float64 {
+	return float64(ai.monster.MoveSpeed()) * ai.cfg.CombatTickInterval.Seconds()
+}
+
+// chaseAndAttack walks to the target and hits it once in reach
+// (one hit per TickInterval; attack speed is not modelled yet).
+func (ai *AttackableAI) chaseAndAttack(target *model.Player, ticks int32) {
+	if distance(ai.monster.Location(), target.Location()) > AttackRange {
+		if !ai.mover.Follow(target.Location()) {
+			// Unreachable (e.g. behind a wall): pick another target next tick
+			ai.hate.Remove(target.ObjectID())
+			return
+		}
+		ai.mover.Step(ai.stepDistance())
+		if distance(ai.monster.Location(), target.Location()) > AttackRange {
+			return
+		}
+	}
+	ai.mover.Stop()
+	if ticks < ai.nextAttack.Load() {
+		return
+	}
+	ai.nextAttack.Store(ticks + int32(ai.cfg.TickInterval/ai.cfg.CombatTickInterval))
+	if ai.cfg.Combat != nil {
+		ai.cfg.Combat.Attack(ai.monster.Npc, target)
+	}
+}
+
+// target returns the most hated player that can still be fought,
+// dropping attackers that are gone or dead.
+func (ai *AttackableAI) target() *model.Player {
+	for {
+		id, ok := ai.hate.Top()
+		if !ok {
+			return nil
+		}
+		p, found := ai.cfg.Players.FindPlayer(id)
+		if found && ai.canFight(p) {
+			return p
+		}
+		ai.hate.Remove(id)
+	}
+}
+
+// scanAggro adds hate for players within aggro range of an aggressive monster.
+func (ai *AttackableAI) scanAggro() {
+	if !ai.monster.IsAggressive() {
+		return
+	}
+	pos := ai.monster.Location()
+	rng := float64(ai.monster.AggroRange())
+	guard := ai.monster.Template().IsGuard()
+	ai.forEachPlayerAround(func(p *model.Player) {
+		if guard && p.Karma() == 0 {
+			return
+		}
+		if ai.canFight(p) && distance(pos, p.Location()) <= rng {
+			ai.hate.Add(p.ObjectID(), 0, aggroHate)
+		}
+	})
+}
+
+// canFight reports whether a player can be attacked: alive, online, visible and out of
+// peace zones. Guards hunt players with karma in peace zones too.
+func (ai *AttackableAI) canFight(p *model.Player) bool {
+	if p.IsDead() || p.IsOffline() || p.IsInvisible() {
+		return false
+	}
+	if ai.monster.Template().IsGuard() && p.Karma() > 0 {
+		return true
+	}
+	return ai.cfg.Zones == nil || !ai.cfg.Zones.InsideZone(p.WorldObject, zone.Peace)
+}
+
+// playersAround reports whether any player is in the visible regions around the monster.
+func (ai *AttackableAI) playersAround() bool {
+	found := false
+	loc := ai.monster.Location()
+	world.ForEachVisibleObject(world.Instance(), loc.X, loc.Y, func(obj *model.WorldObject) bool {
+		_, found = ai.cfg.Players.FindPlayer(obj.ObjectID())
+		return !found
+	})
+	return found
+}
+
+func (ai *AttackableAI) forEachPlayerAround(fn func(p *model.Player)) {
+	loc := ai.monster.Location()
+	world.ForEachVisibleObject(world.Instance(), loc.X, loc.Y, func(obj *model.WorldObject) bool {
+		if p, ok := ai.cfg.Players.FindPlayer(obj.ObjectID()); ok {
+			fn(p)
+		}
+		return true
+	})
+}
+
+// stopWalking interrupts the idle walk (fight, return home or stop).
+func (ai *AttackableAI) stopWalking() {
+	if w := ai.walker.Load(); w != nil {
+		w.Stop()
+	}
+}
+
+// startReturn drops all hate, heals the monster and sends it back to its spawn point.
+func (ai *AttackableAI) startReturn() {
+	ai.hate.Clear()
+	ai.monster.SetCurrentHP(ai.monster.MaxHP())
+	ai.monster.SetCurrentMP(ai.monster.MaxMP())
+	ai.returning.Store(true)
+	ai.SetIntention(model.IntentionMoveTo)
+
+	// Home is always reachable in principle; without a route the monster is put back there
+	if !ai.mover.MoveTo(ai.home()) {
+		world.Instance().MoveObject(ai.monster.WorldObject, ai.home())
+	}
+	slog.Debug("monster returns home",
+		"npc", ai.monster.Name(),
+		"objectID", ai.monster.ObjectID())
+}
+
+// walkHome moves a returning monster; it ignores players until it is back.
+func (ai *AttackableAI) walkHome() {
+	if ai.mover.Step(ai.stepDistance()) {
+		return
+	}
+	ai.returning.Store(false)
+	ai.hate.Clear()
+	ai.SetIntention(model.IntentionActive)
+}
@@ -0,0 +1,342 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// Monsters of these tests live around (50000, 50000), away from other tests' objects.
+const (
+	homeX = 50000
+	homeY = 50000
+)
+
+type playerMap struct {
+	players map[uint32]*model.Player
+	lookups int
+}
+
+func (m *playerMap) FindPlayer(objectID uint32) (*model.Player, bool) {
+	m.lookups++
+	p, ok := m.players[objectID]
+	return p, ok
+}
+
+type hitRecorder struct {
+	hits []uint32
+}
+
+func (r *hitRecorder) Attack(_ *model.Npc, target *model.Player) {
+	r.hits = append(r.hits, target.ObjectID())
+}
+
+// newTestMonster creates a monster spawned at home (aggroRange 0 = passive).
+func newTestMonster(objectID uint32, aggroRange int32) *model.Monster {
+	tmpl := model.NewNpcTemplate(2000, "Orc", "", 10, 1000, 200,
+		50, 40, 20, 20, aggroRange, 100, 253, 30, 60)
+	m := model.NewMonster(objectID, 2000, tmpl)
+	m.SetSpawn(model.NewSpawn(int64(objectID), 2000, homeX, homeY, 0, 0, 1, true))
+	m.SetLocation(model.NewLocation(homeX, homeY, 0, 0))
+	return m
+}
+
+// addTestPlayer puts a player into the world and the player map.
+func addTestPlayer(t *testing.T, players *playerMap, objectID int64, x, y int32) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(objectID, 1, "Hero", 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLocation(model.NewLocation(x, y, 0, 0))
+	if err := world.Instance().AddObject(p.WorldObject); err != nil {
+		t.Fatalf("AddObject: %v", err)
+	}
+	t.Cleanup(func() { world.Instance().RemoveObject(p.ObjectID()) })
+	players.players[p.ObjectID()] = p
+	return p
+}
+
+func newTestAI(monster *model.Monster, players *playerMap, hits *hitRecorder) *AttackableAI {
+	ai := NewAttackableAI(monster, AttackableConfig{Players: players, Combat: hits})
+	ai.Start()
+	return ai
+}
+
+func TestHateList(t *testing.T) {
+	l := NewHateList()
+	if _, ok := l.Top(); ok {
+		t.Error("empty list should have no top")
+	}
+
+	l.Add(10, 0, 1)
+	l.Add(20, 0, 1)
+	if top, _ := l.Top(); top != 10 {
+		t.Errorf("tie: Top() = %d, want the lower objectID 10", top)
+	}
+
+	l.Add(20, 300, 0)
+	l.Add(10, 100, 50)
+	if top, _ := l.Top(); top != 20 {
+		t.Errorf("Top() = %d, want 20", top)
+	}
+	if l.Hate(10) != 151 || l.Damage(10) != 100 || l.Hate(20) != 301 || l.Damage(20) != 300 {
+		t.Errorf("hate/damage: 10=%d/%d 20=%d/%d", l.Hate(10), l.Damage(10), l.Hate(20), l.Damage(20))
+	}
+
+	l.Remove(20)
+	if top, _ := l.Top(); top != 10 || l.Len() != 1 {
+		t.Errorf("after Remove: Top() = %d, Len() = %d", top, l.Len())
+	}
+	l.Clear()
+	if l.Len() != 0 || l.Hate(10) != 0 {
+		t.Error("Clear() should forget all attackers")
+	}
+}
+
+func TestAttackableAI_AggroChaseAttack(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	hits := &hitRecorder{}
+	monster := newTestMonster(300001, 300)
+	ai := newTestAI(monster, players, hits)
+
+	// Outside aggro range: the monster ignores the player
+	far := addTestPlayer(t, players, 1001, homeX+500, homeY)
+	ai.Tick()
+	if ai.HateList().Len() != 0 || monster.Intention() != model.IntentionActive {
+		t.Fatalf("player out of aggro range noticed: hate=%d intention=%v", ai.HateList().Len(), monster.Intention())
+	}
+
+	// Entering aggro range starts the chase
+	far.SetLocation(model.NewLocation(homeX+250, homeY, 0, 0))
+	ai.Tick()
+	if monster.Intention() != model.IntentionAttack || ai.HateList().Hate(far.ObjectID()) != aggroHate {
+		t.Fatalf("aggro: intention=%v hate=%d", monster.Intention(), ai.HateList().Hate(far.ObjectID()))
+	}
+	if monster.X() != homeX+100 || len(hits.hits) != 0 {
+		t.Errorf("first chase step: at %v, hits %v", monster.Location(), hits.hits)
+	}
+
+	// Second tick brings it within reach and it hits
+	ai.Tick()
+	if len(hits.hits) != 1 || hits.hits[0] != far.ObjectID() {
+		t.Errorf("hits = %v, want one hit on the player", hits.hits)
+	}
+
+	// A player dealing damage becomes the most hated target
+	other := addTestPlayer(t, players, 1002, monster.X()-30, homeY)
+	ai.OnAttacked(other, 500)
+	ai.Tick()
+	if last := hits.hits[len(hits.hits)-1]; last != other.ObjectID() {
+		t.Errorf("last hit on %d, want the attacker %d", last, other.ObjectID())
+	}
+
+	// A dead target is dropped from the hate list
+	other.SetCurrentHP(0)
+	ai.Tick()
+	if ai.HateList().Hate(other.ObjectID()) != 0 {
+		t.Error("dead player should be removed from the hate list")
+	}
+	if last := hits.hits[len(hits.hits)-1]; last != far.ObjectID() {
+		t.Errorf("after the target died hit %d, want %d", last, far.ObjectID())
+	}
+}
+
+func TestAttackableAI_PassiveFightsBack(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	hits := &hitRecorder{}
+	monster := newTestMonster(300002, 0)
+	ai := newTestAI(monster, players, hits)
+	p := addTestPlayer(t, players, 1003, homeX+40, homeY)
+
+	ai.Tick()
+	if len(hits.hits) != 0 || ai.HateList().Len() != 0 {
+		t.Fatal("passive monster should not attack first")
+	}
+
+	ai.OnAttacked(p, 10)
+	ai.Tick()
+	if len(hits.hits) != 1 {
+		t.Errorf("passive monster should fight back, hits = %v", hits.hits)
+	}
+}
+
+func TestAttackableAI_ReturnHome(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	hits := &hitRecorder{}
+	monster := newTestMonster(300003, 300)
+	ai := newTestAI(monster, players, hits)
+
+	// The monster was dragged beyond the leash and is hurt
+	p := addTestPlayer(t, players, 1004, homeX+LeashRange+200, homeY)
+	monster.SetLocation(model.NewLocation(homeX+LeashRange+150, homeY, 0, 0))
+	monster.SetCurrentHP(100)
+	ai.OnAttacked(p, 100)
+
+	ai.Tick()
+	if monster.Intention() != model.IntentionMoveTo || ai.HateList().Len() != 0 {
+		t.Fatalf("leash: intention=%v hate=%d", monster.Intention(), ai.HateList().Len())
+	}
+	if monster.CurrentHP() != monster.MaxHP() {
+		t.Errorf("HP after reset = %d, want %d", monster.CurrentHP(), monster.MaxHP())
+	}
+
+	// Attacks while walking home are ignored
+	ai.OnAttacked(p, 100)
+	for range 20 {
+		ai.Tick()
+	}
+	if loc := monster.Location(); loc.X != homeX || loc.Y != homeY {
+		t.Errorf("monster at %v, want home", loc)
+	}
+	if monster.Intention() == model.IntentionMoveTo || len(hits.hits) != 0 {
+		t.Errorf("after return: intention=%v hits=%v", monster.Intention(), hits.hits)
+	}
+}
+
+func TestAttackableAI_QuietMode(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	monster := newTestMonster(300004, 300)
+	ai := newTestAI(monster, players, &hitRecorder{})
+
+	ai.Tick()
+	if !ai.IsQuiet() || monster.Intention() != model.IntentionIdle {
+		t.Fatalf("without players: quiet=%v intention=%v", ai.IsQuiet(), monster.Intention())
+	}
+
+	// A quiet monster sleeps for several intervals between scans
+	if d := ai.NextTick(); d != quietScanInterval*time.Second {
+		t.Errorf("quiet NextTick() = %v, want %v", d, quietScanInterval*time.Second)
+	}
+
+	// A player arrives: the next scan wakes the monster up
+	addTestPlayer(t, players, 1005, homeX+100, homeY)
+	ai.Tick()
+	if ai.IsQuiet() || monster.Intention() != model.IntentionAttack {
+		t.Errorf("after scan: quiet=%v intention=%v", ai.IsQuiet(), monster.Intention())
+	}
+}
+
+func TestAttackableAI_CombatPace(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	hits := &hitRecorder{}
+	monster := newTestMonster(300006, 300)
+	ai := NewAttackableAI(monster, AttackableConfig{
+		Players:            players,
+		Combat:             hits,
+		CombatTickInterval: 250 * time.Millisecond,
+	})
+	ai.Start()
+	addTestPlayer(t, players, 1007, homeX+100, homeY)
+
+	if d := ai.NextTick(); d != time.Second {
+		t.Errorf("idle NextTick() = %v, want 1s", d)
+	}
+
+	// Fighting: four ticks a second, a quarter of the way per tick, one hit a second
+	ai.Tick()
+	if d := ai.NextTick(); d != 250*time.Millisecond {
+		t.Errorf("combat NextTick() = %v, want 250ms", d)
+	}
+	if monster.X() != homeX+25 {
+		t.Errorf("after one combat tick at %v, want %d", monster.Location(), homeX+25)
+	}
+	ai.Tick()
+	if len(hits.hits) != 1 {
+		t.Fatalf("hits after reaching the player = %d, want 1", len(hits.hits))
+	}
+	for range 3 {
+		ai.Tick()
+	}
+	if len(hits.hits) != 1 {
+		t.Errorf("hits within a second = %d, want 1", len(hits.hits))
+	}
+	ai.Tick()
+	if len(hits.hits) != 2 {
+		t.Errorf("hits a second later = %d, want 2", len(hits.hits))
+	}
+}
+
+func TestAttackableAI_GuardHuntsKarma(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	zones := zone.NewManager()
+	if err := zones.Add(&zone.Zone{ID: 1, Type: zone.Peace,
+		Shape: zone.Cylinder{X: homeX, Y: homeY, Radius: 1000, MinZ: -100, MaxZ: 100}}); err != nil {
+		t.Fatal(err)
+	}
+	guard := newTestMonster(300008, 300)
+	guard.Template().SetGuard(true)
+	ai := NewAttackableAI(guard, AttackableConfig{Players: players, Combat: &hitRecorder{}, Zones: zones})
+	ai.Start()
+
+	p := addTestPlayer(t, players, 1009, homeX+100, homeY)
+	zones.Revalidate(p.WorldObject)
+	ai.Tick()
+	if ai.HateList().Len() != 0 {
+		t.Fatal("guard noticed a peaceful player")
+	}
+
+	// Karma players are hunted even in town
+	p.SetKarma(240)
+	ai.Tick()
+	if guard.Intention() != model.IntentionAttack {
+		t.Errorf("guard ignores a PK: intention=%v", guard.Intention())
+	}
+}
+
+func TestAttackableAI_IgnoresInvisible(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	monster := newTestMonster(300009, 300)
+	ai := newTestAI(monster, players, &hitRecorder{})
+
+	gm := addTestPlayer(t, players, 1010, homeX+100, homeY)
+	gm.SetInvisible(true)
+	ai.Tick()
+	if ai.HateList().Len() != 0 {
+		t.Fatal("monster noticed an invisible GM")
+	}
+
+	gm.SetInvisible(false)
+	ai.Tick()
+	if monster.Intention() != model.IntentionAttack {
+		t.Errorf("visible player ignored: intention=%v", monster.Intention())
+	}
+}
+
+func TestAttackableAI_PeaceZone(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	zones := zone.NewManager()
+	if err := zones.Add(&zone.Zone{ID: 1, Type: zone.Peace,
+		Shape: zone.Cylinder{X: homeX + 260, Y: homeY, Radius: 40, MinZ: -100, MaxZ: 100}}); err != nil {
+		t.Fatal(err)
+	}
+	monster := newTestMonster(300007, 300)
+	ai := NewAttackableAI(monster, AttackableConfig{Players: players, Combat: &hitRecorder{}, Zones: zones})
+	ai.Start()
+
+	// In aggro range, but in the peace zone
+	p := addTestPlayer(t, players, 1008, homeX+260, homeY)
+	zones.Revalidate(p.WorldObject)
+	ai.Tick()
+	if ai.HateList().Len() != 0 {
+		t.Fatalf("player in peace zone noticed: hate=%d", ai.HateList().Len())
+	}
+
+	p.SetLocation(model.NewLocation(homeX+200, homeY, 0, 0))
+	zones.Revalidate(p.WorldObject)
+	ai.Tick()
+	if monster.Intention() != model.IntentionAttack {
+		t.Fatalf("player out of peace zone: intention=%v", monster.Intention())
+	}
+
+	// Fleeing into the peace zone ends the chase
+	p.SetLocation(model.NewLocation(homeX+260, homeY, 0, 0))
+	zones.Revalidate(p.WorldObject)
+	ai.Tick()
+	if monster.Intention() == model.IntentionAttack || ai.HateList().Len() != 0 {
+		t.Errorf("player in peace zone still hunted: intention=%v hate=%d", monster.Intention(), ai.HateList().Len())
+	}
+}
@@ -10,6 +10,7 @@ import (
 // BasicNpcAI implements basic NPC AI (MVP: IDLE → ACTIVE state machine)
 type BasicNpcAI struct {
 	npc       *model.Npc
+	walker    atomic.Pointer[Walker]
 	isRunning atomic.Bool
 	tickCount atomic.Int32
 }
@@ -31,9 +32,17 @@ func (ai *BasicNpcAI) Start() {
 		"intention", model.IntentionActive)
 }
 
+// SetWalker makes the NPC random walk or patrol on every tick
+func (ai *BasicNpcAI) SetWalker(w *Walker) {
+	ai.walker.Store(w)
+}
+
 // Stop stops AI controller
 func (ai *BasicNpcAI) Stop() {
 	ai.isRunning.Store(false)
+	if w := ai.walker.Load(); w != nil {
+		w.Stop()
+	}
 	ai.SetIntention(model.IntentionIdle)
 	slog.Debug("basic AI stopped",
 		"npc", ai.npc.Name(),
@@ -80,4 +89,8 @@ func (ai *BasicNpcAI) Tick() {
 			ai.SetIntention(model.IntentionActive)
 		}
 	}
+
+	if w := ai.walker.Load(); w != nil {
+		w.Tick()
+	}
 }
@@ -16,6 +16,6 @@ type Controller interface {
 	// CurrentIntention returns current AI intention
 	CurrentIntention() model.Intention
 
-	// Tick performs AI tick (called every second)
+	// Tick performs AI tick (every second, or at the controller's own pace if it is Paced)
 	Tick()
 }
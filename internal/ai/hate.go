@@ -0,0 +1,94 @@
+package ai
+
+import "sync"
+
+// hateEntry is what an attackable NPC remembers about one attacker (L2J AggroInfo).
+type hateEntry struct {
+	damage int64
+	hate   int64
+}
+
+// HateList tracks how much an attackable NPC hates each attacker: damage dealt plus aggro
+// from noticing the player. The most hated attacker is the NPC's target.
+// Safe for concurrent use: hate comes from packet handlers while the AI tick reads it.
+type HateList struct {
+	mu      sync.Mutex
+	entries map[uint32]*hateEntry
+}
+
+// NewHateList creates an empty hate list.
+func NewHateList() *HateList {
+	return &HateList{entries: make(map[uint32]*hateEntry)}
+}
+
+// Add adds damage and aggro of an attacker; both increase hate.
+func (l *HateList) Add(objectID uint32, damage, aggro int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[objectID]
+	if !ok {
+		e = &hateEntry{}
+		l.entries[objectID] = e
+	}
+	e.damage += damage
+	e.hate += damage + aggro
+}
+
+// Hate returns the hate towards an attacker (0 if not on the list).
+func (l *HateList) Hate(objectID uint32) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.entries[objectID]; ok {
+		return e.hate
+	}
+	return 0
+}
+
+// Damage returns the total damage dealt by an attacker.
+func (l *HateList) Damage(objectID uint32) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.entries[objectID]; ok {
+		return e.damage
+	}
+	return 0
+}
+
+// Top returns the most hated attacker. Ties go to the lower objectID so that
+// the choice does not depend on map order.
+func (l *HateList) Top() (uint32, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var (
+		top   uint32
+		best  int64 = -1
+		found bool
+	)
+	for id, e := range l.entries {
+		if e.hate > best || e.hate == best && id < top {
+			top, best, found = id, e.hate, true
+		}
+	}
+	return top, found
+}
+
+// Remove forgets an attacker (dead, gone or out of reach).
+func (l *HateList) Remove(objectID uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, objectID)
+}
+
+// Clear forgets all attackers.
+func (l *HateList) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.entries)
+}
+
+// Len returns the number of attackers on the list.
+func (l *HateList) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
@@ -1,34 +1,104 @@
 package ai
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
-// TickManager manages AI ticks for all registered NPCs
+// Sleep is returned by Paced.NextTick when the controller needs no ticks
+// until it is woken with TickManager.Wake.
+const Sleep time.Duration = -1
+
+// Paced is optionally implemented by controllers that choose when they tick next:
+// fast while fighting, slow or never while idle. Other controllers tick every Interval.
+type Paced interface {
+	// NextTick returns the delay before the next tick (0 = default interval, Sleep = until woken).
+	NextTick() time.Duration
+}
+
+// TickConfig configures the AI scheduler.
+type TickConfig struct {
+	Shards     int           // tick workers; controllers are partitioned by objectID
+	Interval   time.Duration // tick period of controllers without their own pace
+	Resolution time.Duration // how often a shard runs due controllers; a longer pass is an overrun
+}
+
+// DefaultTickConfig returns one shard per CPU, 1s ticks checked every 50ms.
+func DefaultTickConfig() TickConfig {
+	return TickConfig{
+		Shards:     runtime.GOMAXPROCS(0),
+		Interval:   time.Second,
+		Resolution: 50 * time.Millisecond,
+	}
+}
+
+// ShardStats are the tick metrics of one shard.
+type ShardStats struct {
+	Controllers  int
+	Ticks        uint64        // controller ticks run
+	LastDuration time.Duration // duration of the last pass over due controllers
+	MaxDuration  time.Duration
+	Overruns     uint64 // passes longer than Resolution
+}
+
+// TickManager schedules AI ticks of all registered NPCs. Controllers are spread over shards,
+// each with its own worker goroutine and a timer queue, so a slow shard does not delay the others.
 type TickManager struct {
-	controllers     sync.Map // map[uint32]Controller — objectID → controller
-	ticker          *time.Ticker
+	cfg             TickConfig
+	shards          []*tickShard
 	stopCh          chan struct{}
+	stopOnce        sync.Once
 	controllerCount atomic.Int32 // cached count of controllers (O(1) access)
 }
 
-// NewTickManager creates new AI tick manager
+// NewTickManager creates new AI tick manager with default settings
 func NewTickManager() *TickManager {
-	return &TickManager{
+	return NewShardedTickManager(DefaultTickConfig())
+}
+
+// NewShardedTickManager creates an AI tick manager; zero fields of cfg take defaults.
+func NewShardedTickManager(cfg TickConfig) *TickManager {
+	def := DefaultTickConfig()
+	if cfg.Shards <= 0 {
+		cfg.Shards = def.Shards
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = def.Interval
+	}
+	if cfg.Resolution <= 0 {
+		cfg.Resolution = def.Resolution
+	}
+
+	m := &TickManager{
+		cfg:    cfg,
+		shards: make([]*tickShard, cfg.Shards),
 		stopCh: make(chan struct{}),
 	}
+	for i := range m.shards {
+		m.shards[i] = newTickShard(i, cfg)
+	}
+	return m
+}
+
+func (m *TickManager) shard(objectID uint32) *tickShard {
+	return m.shards[objectID%uint32(len(m.shards))]
 }
 
-// Register registers AI controller for NPC
+// Register registers AI controller for NPC. The first tick comes within one interval,
+// spread randomly so that NPCs spawned together do not tick together.
 func (m *TickManager) Register(objectID uint32, controller Controller) {
-	m.controllers.Store(objectID, controller)
-	m.controllerCount.Add(1) // Update cached count
 	controller.Start()
+	first := time.Now().Add(rand.N(m.cfg.Interval))
+	if m.shard(objectID).add(objectID, controller, first) {
+		m.controllerCount.Add(1) // Update cached count
+	}
 
 	slog.Debug("AI controller registered",
 		"objectID", objectID,
@@ -37,57 +107,68 @@ func (m *TickManager) Register(objectID uint32, controller Controller) {
 
 // Unregister unregisters AI controller
 func (m *TickManager) Unregister(objectID uint32) {
-	value, ok := m.controllers.LoadAndDelete(objectID)
+	controller, ok := m.shard(objectID).remove(objectID)
 	if !ok {
 		return
 	}
 
 	m.controllerCount.Add(-1) // Update cached count
-
-	controller := value.(Controller)
 	controller.Stop()
 
 	slog.Debug("AI controller unregistered", "objectID", objectID)
 }
 
-// Start starts AI tick loop (blocks until context is canceled)
+// Wake schedules an immediate tick of a controller, e.g. a sleeping monster that was attacked.
+func (m *TickManager) Wake(objectID uint32) {
+	m.shard(objectID).wake(objectID, time.Now())
+}
+
+// Start runs the shard workers (blocks until context is canceled or Stop is called)
 func (m *TickManager) Start(ctx context.Context) error {
-	m.ticker = time.NewTicker(1 * time.Second)
-	defer m.ticker.Stop()
+	slog.Info("AI tick manager started",
+		"shards", len(m.shards),
+		"interval", m.cfg.Interval,
+		"resolution", m.cfg.Resolution)
 
-	slog.Info("AI tick manager started", "interval", "1s")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	for {
-		select {
-		case <-ctx.Done():
-			slog.Info("AI tick manager stopping")
-			return ctx.Err()
+	var wg sync.WaitGroup
+	for _, s := range m.shards {
+		wg.Go(func() { s.run(ctx) })
+	}
 
-		case <-m.stopCh:
-			slog.Info("AI tick manager stopped")
-			return nil
+	select {
+	case <-ctx.Done():
+		wg.Wait()
+		slog.Info("AI tick manager stopping")
+		return ctx.Err()
 
-		case <-m.ticker.C:
-			m.tickAll()
-		}
+	case <-m.stopCh:
+		cancel()
+		wg.Wait()
+		slog.Info("AI tick manager stopped")
+		return nil
 	}
 }
 
 // Stop stops AI tick loop
 func (m *TickManager) Stop() {
-	close(m.stopCh)
+	m.stopOnce.Do(func() { close(m.stopCh) })
 }
 
-// tickAll ticks all registered controllers
+// tickAll ticks all registered controllers once, whether due or not (not while Start runs)
 func (m *TickManager) tickAll() {
 	count := 0
-
-	m.controllers.Range(func(key, value any) bool {
-		controller := value.(Controller)
-		controller.Tick()
-		count++
-		return true
-	})
+	now := time.Now()
+	for _, s := range m.shards {
+		s.mu.Lock()
+		for id := range s.entries {
+			s.wakeLocked(id, now)
+		}
+		s.mu.Unlock()
+		count += s.runDue(now)
+	}
 
 	if count > 0 && IsDebugEnabled() {
 		slog.Debug("AI tick completed", "controllers", count)
@@ -96,16 +177,229 @@ func (m *TickManager) tickAll() {
 
 // Count returns number of registered controllers (O(1) cached count)
 // IMPORTANT: Count is cached atomically and updated when controllers are registered/unregistered.
-// This is a performance optimization to avoid O(N) Range() on sync.Map.
+// This is a performance optimization to avoid walking all shards.
 func (m *TickManager) Count() int {
 	return int(m.controllerCount.Load())
 }
 
 // GetController returns controller for NPC
 func (m *TickManager) GetController(objectID uint32) (Controller, error) {
-	value, ok := m.controllers.Load(objectID)
+	s := m.shard(objectID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[objectID]
 	if !ok {
 		return nil, fmt.Errorf("controller not found for objectID %d", objectID)
 	}
-	return value.(Controller), nil
+	return e.controller, nil
+}
+
+// Stats returns the tick metrics of every shard.
+func (m *TickManager) Stats() []ShardStats {
+	stats := make([]ShardStats, len(m.shards))
+	for i, s := range m.shards {
+		stats[i] = s.stats()
+	}
+	return stats
+}
+
+// tickEntry is a registered controller. seq changes on every reschedule and is zeroed
+// on unregister, so queue items of older schedules are recognised as stale.
+type tickEntry struct {
+	controller Controller
+	seq        uint64
+	queued     bool // a current queue item exists (false while sleeping or ticking)
+}
+
+type queueItem struct {
+	due   int64 // UnixNano: cheaper to compare and swap than time.Time
+	seq   uint64
+	entry *tickEntry
+}
+
+type tickQueue []queueItem
+
+func (q tickQueue) Len() int           { return len(q) }
+func (q tickQueue) Less(i, j int) bool { return q[i].due < q[j].due }
+func (q tickQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *tickQueue) Push(x any)        { *q = append(*q, x.(queueItem)) }
+func (q *tickQueue) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
+
+// tickShard ticks its controllers from a timer queue ordered by due time.
+type tickShard struct {
+	id  int
+	cfg TickConfig
+
+	mu      sync.Mutex
+	entries map[uint32]*tickEntry
+	queue   tickQueue
+	nextSeq uint64
+	due     []dueTick // reused by runDue, worker goroutine only
+
+	ticks        atomic.Uint64
+	lastDuration atomic.Int64
+	maxDuration  atomic.Int64
+	overruns     atomic.Uint64
+	lastWarn     time.Time // worker goroutine only
+}
+
+func newTickShard(id int, cfg TickConfig) *tickShard {
+	return &tickShard{
+		id:      id,
+		cfg:     cfg,
+		entries: make(map[uint32]*tickEntry),
+	}
+}
+
+// add registers a controller; false if it replaced one with the same objectID.
+func (s *tickShard) add(objectID uint32, c Controller, due time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, replaced := s.entries[objectID]
+	if replaced {
+		old.seq = 0
+	}
+	e := &tickEntry{controller: c}
+	s.entries[objectID] = e
+	s.scheduleLocked(e, due)
+	return !replaced
+}
+
+func (s *tickShard) remove(objectID uint32) (Controller, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[objectID]
+	if !ok {
+		return nil, false
+	}
+	delete(s.entries, objectID)
+	e.seq = 0
+	return e.controller, true
+}
+
+func (s *tickShard) wake(objectID uint32, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wakeLocked(objectID, now)
+}
+
+func (s *tickShard) wakeLocked(objectID uint32, now time.Time) {
+	if e, ok := s.entries[objectID]; ok {
+		s.scheduleLocked(e, now)
+	}
+}
+
+// scheduleLocked queues the next tick of a registered controller, replacing its previous schedule.
+func (s *tickShard) scheduleLocked(e *tickEntry, due time.Time) {
+	s.nextSeq++
+	e.seq = s.nextSeq
+	e.queued = true
+	heap.Push(&s.queue, queueItem{due: due.UnixNano(), seq: e.seq, entry: e})
+}
+
+// dueTick is a controller popped from the queue for ticking.
+type dueTick struct {
+	entry *tickEntry
+	seq   uint64
+	delay time.Duration
+}
+
+// popDue removes the controllers due at now from the queue.
+func (s *tickShard) popDue(now time.Time, due []dueTick) []dueTick {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := now.UnixNano()
+	for len(s.queue) > 0 && s.queue[0].due <= limit {
+		it := heap.Pop(&s.queue).(queueItem)
+		if e := it.entry; e.seq == it.seq {
+			e.queued = false
+			due = append(due, dueTick{entry: e, seq: it.seq})
+		}
+	}
+	return due
+}
+
+// runDue ticks the due controllers outside the lock (a tick may register, wake or
+// unregister NPCs) and schedules their next ticks. Returns the number of ticks.
+func (s *tickShard) runDue(now time.Time) int {
+	s.due = s.popDue(now, s.due[:0])
+	for i := range s.due {
+		d := &s.due[i]
+		d.entry.controller.Tick()
+		d.delay = s.cfg.Interval
+		if p, ok := d.entry.controller.(Paced); ok {
+			if next := p.NextTick(); next != 0 {
+				d.delay = next
+			}
+		}
+	}
+
+	s.mu.Lock()
+	for _, d := range s.due {
+		// Not rescheduled if unregistered, woken during the tick or sleeping
+		if e := d.entry; e.seq == d.seq && !e.queued && d.delay > 0 {
+			s.scheduleLocked(e, now.Add(d.delay))
+		}
+	}
+	s.mu.Unlock()
+
+	n := len(s.due)
+	clear(s.due) // do not keep unregistered controllers alive
+	s.ticks.Add(uint64(n))
+	return n
+}
+
+// run is the shard worker: every Resolution it ticks the due controllers.
+func (s *tickShard) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Resolution)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n := s.runDue(now)
+			s.record(time.Since(now), n)
+		}
+	}
+}
+
+// record updates the metrics of one pass and reports overruns (at most once a second).
+func (s *tickShard) record(d time.Duration, ticked int) {
+	s.lastDuration.Store(int64(d))
+	if int64(d) > s.maxDuration.Load() {
+		s.maxDuration.Store(int64(d))
+	}
+	if d <= s.cfg.Resolution {
+		return
+	}
+	overruns := s.overruns.Add(1)
+	if time.Since(s.lastWarn) >= time.Second {
+		s.lastWarn = time.Now()
+		slog.Warn("AI tick overrun",
+			"shard", s.id,
+			"duration", d,
+			"budget", s.cfg.Resolution,
+			"controllers", ticked,
+			"overruns", overruns)
+	}
+}
+
+func (s *tickShard) stats() ShardStats {
+	s.mu.Lock()
+	n := len(s.entries)
+	s.mu.Unlock()
+	return ShardStats{
+		Controllers:  n,
+		Ticks:        s.ticks.Load(),
+		LastDuration: time.Duration(s.lastDuration.Load()),
+		MaxDuration:  time.Duration(s.maxDuration.Load()),
+		Overruns:     s.overruns.Load(),
+	}
 }
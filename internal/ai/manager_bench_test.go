@@ -0,0 +1,25 @@
+package ai
+
+import (
+	"testing"
+	"time"
+)
+
+// BenchmarkTickShard_RunDue measures one shard pass over 50K due controllers
+// (the whole world of NPCs in a single shard: the worst case for a 1-CPU server).
+func BenchmarkTickShard_RunDue(b *testing.B) {
+	const controllers = 50_000
+	mgr := NewShardedTickManager(TickConfig{Shards: 1, Interval: time.Second})
+	for id := range uint32(controllers) {
+		mgr.Register(id, &pacedController{})
+	}
+	s := mgr.shards[0]
+	now := time.Now().Add(time.Second)
+
+	b.ResetTimer()
+	for range b.N {
+		s.runDue(now)
+		now = now.Add(time.Second)
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*controllers), "ns/controller")
+}
@@ -115,3 +115,122 @@ func TestTickManager_MultipleControllers(t *testing.T) {
 		t.Errorf("Count() after unregistering all = %d, want 0", mgr.Count())
 	}
 }
+
+// pacedController counts ticks and asks for its next tick after next.
+type pacedController struct {
+	intention model.Intention
+	ticks     int
+	next      time.Duration
+}
+
+func (c *pacedController) Start()                                 {}
+func (c *pacedController) Stop()                                  {}
+func (c *pacedController) SetIntention(intention model.Intention) { c.intention = intention }
+func (c *pacedController) CurrentIntention() model.Intention      { return c.intention }
+func (c *pacedController) Tick()                                  { c.ticks++ }
+func (c *pacedController) NextTick() time.Duration                { return c.next }
+
+func TestTickManager_Pace(t *testing.T) {
+	mgr := NewShardedTickManager(TickConfig{Shards: 2, Interval: time.Second, Resolution: 10 * time.Millisecond})
+	c := &pacedController{next: 200 * time.Millisecond}
+	mgr.Register(1, c)
+	s := mgr.shard(1)
+
+	// The first tick comes within one interval
+	base := time.Now().Add(time.Second)
+	s.runDue(base)
+	if c.ticks != 1 {
+		t.Fatalf("ticks after one interval = %d, want 1", c.ticks)
+	}
+
+	// Then at the controller's own pace
+	s.runDue(base.Add(100 * time.Millisecond))
+	if c.ticks != 1 {
+		t.Errorf("ticked before its delay: %d", c.ticks)
+	}
+	s.runDue(base.Add(200 * time.Millisecond))
+	if c.ticks != 2 {
+		t.Errorf("ticks after 200ms = %d, want 2", c.ticks)
+	}
+
+	// A sleeping controller ticks again only when woken
+	c.next = Sleep
+	s.runDue(base.Add(400 * time.Millisecond))
+	s.runDue(base.Add(10 * time.Second))
+	if c.ticks != 3 {
+		t.Errorf("sleeping controller ticks = %d, want 3", c.ticks)
+	}
+	c.next = 0
+	mgr.Wake(1)
+	s.runDue(base.Add(10 * time.Second))
+	if c.ticks != 4 {
+		t.Errorf("ticks after Wake = %d, want 4", c.ticks)
+	}
+
+	// Without its own pace the default interval applies
+	s.runDue(base.Add(10*time.Second + 999*time.Millisecond))
+	s.runDue(base.Add(11 * time.Second))
+	if c.ticks != 5 {
+		t.Errorf("ticks after the default interval = %d, want 5", c.ticks)
+	}
+
+	// Unregistered controllers never tick again
+	mgr.Unregister(1)
+	s.runDue(base.Add(time.Minute))
+	if c.ticks != 5 {
+		t.Errorf("unregistered controller ticked: %d", c.ticks)
+	}
+	if got := mgr.Stats()[1].Ticks; got != 5 {
+		t.Errorf("shard ticks = %d, want 5", got)
+	}
+}
+
+func TestTickManager_Shards(t *testing.T) {
+	mgr := NewShardedTickManager(TickConfig{Shards: 4, Resolution: 10 * time.Millisecond})
+	for id := range uint32(10) {
+		mgr.Register(id, &pacedController{})
+	}
+	stats := mgr.Stats()
+	if len(stats) != 4 {
+		t.Fatalf("shards = %d, want 4", len(stats))
+	}
+	total := 0
+	for _, st := range stats {
+		total += st.Controllers
+	}
+	if total != 10 || stats[0].Controllers != 3 || stats[3].Controllers != 2 {
+		t.Errorf("controllers per shard = %+v", stats)
+	}
+
+	// A pass longer than the resolution is an overrun
+	s := mgr.shards[0]
+	s.record(5*time.Millisecond, 3)
+	s.record(25*time.Millisecond, 3)
+	st := s.stats()
+	if st.Overruns != 1 || st.MaxDuration != 25*time.Millisecond || st.LastDuration != 25*time.Millisecond {
+		t.Errorf("stats = %+v, want 1 overrun of 25ms", st)
+	}
+}
+
+func TestTickManager_StartStop(t *testing.T) {
+	mgr := NewShardedTickManager(TickConfig{Shards: 2, Interval: 20 * time.Millisecond, Resolution: 5 * time.Millisecond})
+	c := &pacedController{}
+	mgr.Register(7, c)
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Start(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+	mgr.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start() after Stop() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start() did not return after Stop()")
+	}
+	if mgr.Stats()[1].Ticks == 0 {
+		t.Error("shard workers did not tick")
+	}
+}
@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"math"
+	"sync"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// Pathfinder finds walkable routes around obstacles (implemented by geo.Pathfinder).
+type Pathfinder interface {
+	// FindPath returns waypoints excluding the start and ending at the destination.
+	FindPath(from, to model.Location) ([]model.Location, error)
+}
+
+// MoveListener is notified when a creature starts walking towards its next waypoint,
+// e.g. to broadcast MoveToLocation to nearby players.
+type MoveListener interface {
+	MoveStarted(obj *model.WorldObject, from, to model.Location)
+}
+
+// repathDistance is how far a followed target may move away from the current destination
+// before the route is planned again (L2J: the chase re-plans only on a noticeable move).
+const repathDistance = 64
+
+// Mover walks a creature along a route around obstacles: the route is planned once per
+// destination, and every Step advances the creature by its speed, moving it between world
+// regions as needed.
+type Mover struct {
+	obj      *model.WorldObject
+	paths    Pathfinder   // nil = straight lines
+	listener MoveListener // nil = no notifications
+
+	mu   sync.Mutex
+	path []model.Location // remaining waypoints, path[0] is the current leg's target
+	dest model.Location
+}
+
+// NewMover creates a mover for obj. Without a pathfinder creatures walk in straight lines.
+func NewMover(obj *model.WorldObject, paths Pathfinder, listener MoveListener) *Mover {
+	return &Mover{obj: obj, paths: paths, listener: listener}
+}
+
+// MoveTo plans a route to dest and starts walking. Returns false if dest is unreachable;
+// the creature then stops where it is.
+func (m *Mover) MoveTo(dest model.Location) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.moveTo(dest)
+}
+
+func (m *Mover) moveTo(dest model.Location) bool {
+	from := m.obj.Location()
+	path := []model.Location{dest}
+	if m.paths != nil {
+		p, err := m.paths.FindPath(from, dest)
+		if err != nil {
+			m.path = m.path[:0]
+			return false
+		}
+		path = p
+	}
+
+	m.path = append(m.path[:0], path...)
+	m.dest = m.path[len(m.path)-1]
+	m.startLeg(from)
+	return true
+}
+
+// Follow keeps walking towards a moving target, re-planning the route only when the target
+// has moved away from the current destination. Returns false if the target is unreachable.
+func (m *Mover) Follow(target model.Location) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.path) > 0 && distance(m.dest, target) <= repathDistance {
+		return true
+	}
+	return m.moveTo(target)
+}
+
+// Step advances the creature by dist game units along its route.
+// Returns true while the creature is still moving.
+func (m *Mover) Step(dist float64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for len(m.path) > 0 && dist > 0 {
+		pos := m.obj.Location()
+		wp := m.path[0]
+		left := distance(pos, wp)
+		if left > dist {
+			k := dist / left
+			next := model.NewLocation(
+				pos.X+int32(math.Round(float64(wp.X-pos.X)*k)),
+				pos.Y+int32(math.Round(float64(wp.Y-pos.Y)*k)),
+				pos.Z+int32(math.Round(float64(wp.Z-pos.Z)*k)),
+				pos.Heading,
+			)
+			world.Instance().MoveObject(m.obj, next)
+			return true
+		}
+
+		// Waypoint reached: continue with the next leg in the same step
+		wp.Heading = pos.Heading
+		world.Instance().MoveObject(m.obj, wp)
+		dist -= left
+		m.path = m.path[1:]
+		if len(m.path) > 0 {
+			m.startLeg(wp)
+		}
+	}
+	return len(m.path) > 0
+}
+
+// startLeg turns the creature towards the current waypoint and notifies the listener.
+func (m *Mover) startLeg(from model.Location) {
+	to := m.path[0]
+	from.Heading = heading(from, to)
+	m.obj.SetLocation(from)
+	if m.listener != nil {
+		m.listener.MoveStarted(m.obj, from, to)
+	}
+}
+
+// Stop drops the route; the creature stays where it is.
+func (m *Mover) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.path = m.path[:0]
+}
+
+// IsMoving reports whether the creature has a route to walk.
+func (m *Mover) IsMoving() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.path) > 0
+}
+
+// Waypoints returns a copy of the remaining route.
+func (m *Mover) Waypoints() []model.Location {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]model.Location(nil), m.path...)
+}
+
+// distance returns the 3D distance between two locations.
+func distance(a, b model.Location) float64 {
+	dx, dy, dz := float64(b.X-a.X), float64(b.Y-a.Y), float64(b.Z-a.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// heading returns the client heading (0..65535 for a full turn) from one point to another.
+func heading(from, to model.Location) uint16 {
+	angle := math.Atan2(float64(to.Y-from.Y), float64(to.X-from.X))
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return uint16(angle * 65536 / (2 * math.Pi))
+}
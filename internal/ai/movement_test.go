@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// cornerPathfinder routes every move through a corner at (dest.X, from.Y),
+// as if a wall blocked the straight line. Destinations with X < 0 are unreachable.
+type cornerPathfinder struct {
+	calls int
+}
+
+func (p *cornerPathfinder) FindPath(from, to model.Location) ([]model.Location, error) {
+	p.calls++
+	if to.X < 0 {
+		return nil, errors.New("no path")
+	}
+	return []model.Location{model.NewLocation(to.X, from.Y, from.Z, 0), to}, nil
+}
+
+type legRecorder struct {
+	legs []model.Location
+}
+
+func (r *legRecorder) MoveStarted(_ *model.WorldObject, _, to model.Location) {
+	r.legs = append(r.legs, to)
+}
+
+func TestMover_FollowsPath(t *testing.T) {
+	obj := model.NewWorldObject(1, "Wolf", model.NewLocation(1000, 1000, -100, 0))
+	paths := &cornerPathfinder{}
+	legs := &legRecorder{}
+	m := NewMover(obj, paths, legs)
+
+	dest := model.NewLocation(1300, 1400, -100, 0)
+	if !m.MoveTo(dest) {
+		t.Fatal("MoveTo() = false")
+	}
+	if len(legs.legs) != 1 || legs.legs[0] != model.NewLocation(1300, 1000, -100, 0) {
+		t.Errorf("first leg = %v, want the corner", legs.legs)
+	}
+	if obj.Heading() != 0 {
+		t.Errorf("heading towards +X = %d, want 0", obj.Heading())
+	}
+
+	// 200 units along the first leg
+	if !m.Step(200) || obj.X() != 1200 || obj.Y() != 1000 {
+		t.Errorf("after first step at %v", obj.Location())
+	}
+	// Through the corner: 100 units to it, 100 more on the second leg
+	if !m.Step(200) || obj.X() != 1300 || obj.Y() != 1100 {
+		t.Errorf("after second step at %v", obj.Location())
+	}
+	if len(legs.legs) != 2 || legs.legs[1] != dest {
+		t.Errorf("legs = %v, want the corner and the destination", legs.legs)
+	}
+	if obj.Heading() != 16384 {
+		t.Errorf("heading towards +Y = %d, want 16384", obj.Heading())
+	}
+
+	if m.Step(1000) || m.IsMoving() {
+		t.Error("mover should stop at the destination")
+	}
+	if loc := obj.Location(); loc.X != dest.X || loc.Y != dest.Y {
+		t.Errorf("stopped at %v, want %v", loc, dest)
+	}
+}
+
+func TestMover_Follow(t *testing.T) {
+	obj := model.NewWorldObject(2, "Wolf", model.NewLocation(0, 0, 0, 0))
+	paths := &cornerPathfinder{}
+	m := NewMover(obj, paths, nil)
+
+	target := model.NewLocation(500, 500, 0, 0)
+	if !m.Follow(target) || paths.calls != 1 {
+		t.Fatalf("Follow() did not plan a route (calls = %d)", paths.calls)
+	}
+
+	// A small move of the target keeps the route
+	m.Step(100)
+	if !m.Follow(model.NewLocation(530, 510, 0, 0)) || paths.calls != 1 {
+		t.Errorf("route re-planned for a small target move (calls = %d)", paths.calls)
+	}
+
+	// A large move re-plans from the current position
+	target = model.NewLocation(900, 200, 0, 0)
+	if !m.Follow(target) || paths.calls != 2 {
+		t.Errorf("route not re-planned (calls = %d)", paths.calls)
+	}
+	wps := m.Waypoints()
+	if len(wps) != 2 || wps[0] != model.NewLocation(900, 0, 0, 0) || wps[1] != target {
+		t.Errorf("waypoints = %v", wps)
+	}
+
+	// An unreachable target stops the chase
+	if m.Follow(model.NewLocation(-500, 0, 0, 0)) || m.IsMoving() {
+		t.Error("unreachable target should stop the mover")
+	}
+}
+
+func TestMover_StraightLineWithoutPathfinder(t *testing.T) {
+	obj := model.NewWorldObject(3, "Wolf", model.NewLocation(0, 0, 0, 0))
+	m := NewMover(obj, nil, nil)
+
+	dest := model.NewLocation(300, 400, 0, 0)
+	if !m.MoveTo(dest) {
+		t.Fatal("MoveTo() = false")
+	}
+	m.Step(250)
+	if obj.X() != 150 || obj.Y() != 200 {
+		t.Errorf("halfway at %v, want (150, 200)", obj.Location())
+	}
+
+	m.Stop()
+	if m.Step(250) || obj.X() != 150 {
+		t.Error("stopped mover should not move")
+	}
+}
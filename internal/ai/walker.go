@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// WalkConfig holds the settings of idle NPC movement.
+type WalkConfig struct {
+	Radius       int32         // random walk radius around the spawn point; 0 = NPCs stand still
+	Rate         int           // a random walk starts on 1 of Rate idle ticks (L2J RANDOM_WALK_RATE)
+	Paths        Pathfinder    // nil = walk in straight lines
+	Listener     MoveListener  // nil = movement is not broadcast
+	TickInterval time.Duration // time between ticks, converts move speed to distance
+}
+
+// Walkable is implemented by AI controllers that walk around while idle.
+type Walkable interface {
+	SetWalker(w *Walker)
+}
+
+// Walker moves an idle NPC: along its patrol route if it has one, otherwise to random
+// points around its spawn point. Tick is called by the AI controller on idle ticks.
+type Walker struct {
+	npc   *model.Npc
+	route *model.Route // nil = random walk
+	cfg   WalkConfig
+	mover *Mover
+
+	mu    sync.Mutex
+	point int // route point walked to (or stood at)
+	dir   int // 1 forwards, -1 backwards along the route
+	wait  int // ticks left to stand at the current point
+}
+
+// NewWalker creates a walker for npc; route may be nil.
+func NewWalker(npc *model.Npc, route *model.Route, cfg WalkConfig) *Walker {
+	if cfg.TickInterval <= 0 {
+		cfg.TickInterval = time.Second
+	}
+	return &Walker{
+		npc:   npc,
+		route: route,
+		cfg:   cfg,
+		mover: NewMover(npc.WorldObject, cfg.Paths, cfg.Listener),
+		dir:   1,
+	}
+}
+
+// Route returns the patrol route of the NPC (nil for random walk).
+func (w *Walker) Route() *model.Route {
+	return w.route
+}
+
+// Tick advances the NPC by one tick of walking.
+func (w *Walker) Tick() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.mover.IsMoving() && !w.start() {
+		return
+	}
+	if !w.mover.Step(w.stepDistance()) && w.route != nil {
+		w.arrived()
+	}
+}
+
+// start starts the next walk; false if the NPC stands this tick.
+func (w *Walker) start() bool {
+	if w.route != nil {
+		return w.patrol()
+	}
+	return w.randomWalk()
+}
+
+// Stop interrupts the current walk (e.g. when the NPC starts fighting).
+// A patrol continues from the point it was walking to.
+func (w *Walker) Stop() {
+	w.mover.Stop()
+}
+
+// IsMoving reports whether the NPC is walking.
+func (w *Walker) IsMoving() bool {
+	return w.mover.IsMoving()
+}
+
+func (w *Walker) stepDistance() float64 {
+	return float64(w.npc.MoveSpeed()) * w.cfg.TickInterval.Seconds()
+}
+
+// arrived starts the pause at the reached route point and picks the next one.
+func (w *Walker) arrived() {
+	w.wait = int(w.route.Points[w.point].Pause / w.cfg.TickInterval)
+	w.point, w.dir = w.route.Next(w.point, w.dir)
+}
+
+// patrol walks to the current route point once the pause is over.
+func (w *Walker) patrol() bool {
+	if w.wait > 0 {
+		w.wait--
+		return false
+	}
+	if distance(w.npc.Location(), w.route.Points[w.point].Location) < 1 {
+		w.arrived()
+		if w.wait > 0 {
+			return false
+		}
+	}
+	if !w.mover.MoveTo(w.route.Points[w.point].Location) {
+		// A blocked point must not stop the whole patrol
+		slog.Debug("route point unreachable",
+			"npc", w.npc.Name(),
+			"objectID", w.npc.ObjectID(),
+			"route", w.route.Name,
+			"point", w.point)
+		w.point, w.dir = w.route.Next(w.point, w.dir)
+		return false
+	}
+	return true
+}
+
+// randomWalk sometimes walks to a random point within Radius of the spawn point.
+func (w *Walker) randomWalk() bool {
+	if w.cfg.Radius <= 0 || w.cfg.Rate > 1 && rand.IntN(w.cfg.Rate) != 0 {
+		return false
+	}
+	home := w.npc.SpawnLocation()
+	angle := rand.Float64() * 2 * math.Pi
+	r := rand.Float64() * float64(w.cfg.Radius)
+	dest := model.NewLocation(
+		home.X+int32(r*math.Cos(angle)),
+		home.Y+int32(r*math.Sin(angle)),
+		home.Z,
+		home.Heading,
+	)
+	return w.mover.MoveTo(dest)
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// newWalkingNpc creates an NPC with move speed 100 spawned at (x, y, 0).
+func newWalkingNpc(objectID uint32, x, y int32) *model.Npc {
+	tmpl := model.NewNpcTemplate(2100, "Guard", "", 20, 1000, 200,
+		50, 40, 20, 20, 0, 100, 253, 0, 0)
+	npc := model.NewNpc(objectID, 2100, tmpl)
+	npc.SetSpawn(model.NewSpawn(int64(objectID), 2100, x, y, 0, 0, 1, true))
+	npc.SetLocation(model.NewLocation(x, y, 0, 0))
+	return npc
+}
+
+func TestWalker_Patrol(t *testing.T) {
+	npc := newWalkingNpc(400001, 60000, 60000)
+	route := &model.Route{
+		Name: "gate",
+		Mode: model.RouteReverse,
+		Points: []model.RoutePoint{
+			{Location: model.NewLocation(60000, 60000, 0, 0)},
+			{Location: model.NewLocation(60200, 60000, 0, 0), Pause: 2 * time.Second},
+			{Location: model.NewLocation(60200, 60100, 0, 0)},
+		},
+	}
+	legs := &legRecorder{}
+	w := NewWalker(npc, route, WalkConfig{Listener: legs, TickInterval: time.Second})
+
+	// Standing at the first point: the patrol heads for the second one at once
+	w.Tick()
+	if npc.X() != 60100 || !w.IsMoving() {
+		t.Fatalf("after 1 tick at %v, want half way to the second point", npc.Location())
+	}
+	w.Tick()
+	if npc.X() != 60200 || w.IsMoving() {
+		t.Fatalf("after 2 ticks at %v, want at the second point", npc.Location())
+	}
+
+	// Two seconds of pause, then on to the third point
+	w.Tick()
+	w.Tick()
+	if npc.X() != 60200 || npc.Y() != 60000 {
+		t.Fatalf("NPC left the second point during the pause: %v", npc.Location())
+	}
+	w.Tick()
+	if npc.Y() != 60100 {
+		t.Fatalf("at %v, want at the third point", npc.Location())
+	}
+
+	// Reverse: back to the second point, not to the first one
+	w.Tick()
+	last := legs.legs[len(legs.legs)-1]
+	if last != route.Points[1].Location {
+		t.Errorf("walking to %v after the last point, want the second point", last)
+	}
+	if len(legs.legs) != 3 {
+		t.Errorf("broadcast legs = %v, want 3", legs.legs)
+	}
+}
+
+func TestWalker_RandomWalk(t *testing.T) {
+	npc := newWalkingNpc(400002, 62000, 62000)
+	home := npc.Spawn().Location()
+	w := NewWalker(npc, nil, WalkConfig{Radius: 300, Rate: 1, TickInterval: time.Second})
+
+	moved := false
+	for range 50 {
+		w.Tick()
+		if d := distance(npc.Location(), home); d > 300 {
+			t.Fatalf("NPC at %v is %.0f from its spawn, radius 300", npc.Location(), d)
+		}
+		moved = moved || npc.Location() != home
+	}
+	if !moved {
+		t.Error("NPC never walked")
+	}
+}
+
+func TestWalker_StandStill(t *testing.T) {
+	npc := newWalkingNpc(400003, 64000, 64000)
+	w := NewWalker(npc, nil, WalkConfig{Rate: 1})
+	for range 10 {
+		w.Tick()
+	}
+	if npc.X() != 64000 || npc.Y() != 64000 || w.IsMoving() {
+		t.Errorf("NPC without walk radius moved to %v", npc.Location())
+	}
+}
+
+func TestAttackableAI_WalkStopsOnAttack(t *testing.T) {
+	players := &playerMap{players: make(map[uint32]*model.Player)}
+	monster := newTestMonster(300005, 0)
+	ai := newTestAI(monster, players, &hitRecorder{})
+	legs := &legRecorder{}
+	w := NewWalker(monster.Npc, nil, WalkConfig{Radius: 300, Rate: 1, Listener: legs})
+	ai.SetWalker(w)
+	p := addTestPlayer(t, players, 1006, homeX+800, homeY)
+
+	// A player in sight keeps the monster awake, so it wanders
+	ai.Tick()
+	if len(legs.legs) != 1 {
+		t.Fatalf("idle monster should random walk, legs = %v", legs.legs)
+	}
+
+	ai.OnAttacked(p, 10)
+	ai.Tick()
+	if w.IsMoving() || monster.Intention() != model.IntentionAttack {
+		t.Errorf("attacked monster: walking=%v intention=%v", w.IsMoving(), monster.Intention())
+	}
+}
@@ -0,0 +1,408 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// AllyPenalty — тип штрафа альянса (значения L2J).
+type AllyPenalty int32
+
+const (
+	AllyPenaltyNone          AllyPenalty = 0
+	AllyPenaltyClanLeaved    AllyPenalty = 1 // клан вышел из альянса
+	AllyPenaltyClanDismissed AllyPenalty = 2 // клан исключён из альянса
+	AllyPenaltyDismissClan   AllyPenalty = 3 // альянс исключил клан
+	AllyPenaltyDissolveAlly  AllyPenalty = 4 // лидер распустил альянс
+)
+
+const (
+	// AllyMinClanLevel — минимальный уровень клана для создания альянса.
+	AllyMinClanLevel = 5
+
+	// MaxAllyCrestSize — максимальный размер эмблемы альянса (8x12 DDS).
+	MaxAllyCrestSize = 192
+)
+
+var (
+	ErrInAlliance       = errors.New("clan is already in an alliance")
+	ErrNotInAlliance    = errors.New("clan is not in an alliance")
+	ErrNotAllyLeader    = errors.New("only the alliance leader can do this")
+	ErrAllyNameTaken    = errors.New("alliance name already exists")
+	ErrAllyPenalty      = errors.New("alliance penalty is active")
+	ErrAllyFull         = errors.New("alliance is full")
+	ErrAllyLeaderLeave  = errors.New("alliance leader cannot leave the alliance")
+	ErrClanNotFound     = errors.New("clan not found")
+	ErrAllyEnemyPresent = errors.New("clan is at war with an alliance member")
+)
+
+// allyInvitation — ожидающее ответа приглашение клана в альянс.
+type allyInvitation struct {
+	requester *model.Player
+	allyID    int32
+	expires   time.Time
+}
+
+// AllyClans возвращает кланы альянса allyID.
+func (m *Manager) AllyClans(allyID int32) []*Clan {
+	if allyID == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.allyClansLocked(allyID)
+}
+
+func (m *Manager) allyClansLocked(allyID int32) []*Clan {
+	var out []*Clan
+	for _, c := range m.clans {
+		if c.AllyID() == allyID {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// allyPenaltyActive возвращает true если у клана действует штраф одного из типов.
+func (m *Manager) allyPenaltyActive(c *Clan, types ...AllyPenalty) bool {
+	t, expiry := c.AllyPenalty()
+	if !m.now().Before(expiry) {
+		return false
+	}
+	for _, pt := range types {
+		if t == pt {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAlliance создаёт альянс во главе с кланом лидера leader.
+func (m *Manager) CreateAlliance(ctx context.Context, leader *model.Player, name string) (*Clan, error) {
+	if !nameTemplate.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := c.record()
+	switch {
+	case rec.AllyID != 0:
+		return nil, ErrInAlliance
+	case rec.Level < AllyMinClanLevel:
+		return nil, ErrClanLevelTooLow
+	case !rec.DissolvingExpiry.IsZero():
+		return nil, ErrDissolving
+	case m.allyPenaltyActive(c, AllyPenaltyDissolveAlly):
+		return nil, ErrAllyPenalty
+	}
+	for _, other := range m.clans {
+		if strings.EqualFold(other.AllyName(), name) {
+			return nil, ErrAllyNameTaken
+		}
+	}
+
+	rec.AllyID = c.id
+	rec.AllyName = name
+	rec.AllyCrestID = 0
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// InviteAlly регистрирует приглашение клана target в альянс requester.
+// requester — лидер альянса, target — лидер приглашаемого клана.
+func (m *Manager) InviteAlly(requester, target *model.Player) error {
+	if requester == target {
+		return ErrSelfInvite
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leaderClan, err := m.allyLeaderClanLocked(requester)
+	if err != nil {
+		return err
+	}
+	targetClan, err := m.leaderClanLocked(target)
+	if err != nil {
+		return err
+	}
+	if err := m.canJoinAllyLocked(leaderClan, targetClan); err != nil {
+		return err
+	}
+	if inv, ok := m.allyInvites[target.CharacterID()]; ok && m.now().Before(inv.expires) {
+		return ErrTargetBusy
+	}
+
+	m.allyInvites[target.CharacterID()] = allyInvitation{
+		requester: requester,
+		allyID:    leaderClan.id,
+		expires:   m.now().Add(InviteTimeout),
+	}
+	return nil
+}
+
+// canJoinAllyLocked проверяет, может ли клан target вступить в альянс leaderClan.
+func (m *Manager) canJoinAllyLocked(leaderClan, target *Clan) error {
+	allyID := leaderClan.id
+	switch {
+	case target == leaderClan:
+		return ErrSelfInvite
+	case target.AllyID() != 0:
+		return ErrInAlliance
+	case !target.DissolvingExpiry().IsZero():
+		return ErrDissolving
+	case m.allyPenaltyActive(leaderClan, AllyPenaltyDismissClan):
+		return ErrAllyPenalty
+	case m.allyPenaltyActive(target, AllyPenaltyClanLeaved, AllyPenaltyClanDismissed):
+		return ErrAllyPenalty
+	}
+
+	members := m.allyClansLocked(allyID)
+	if len(members) >= m.cfg.MaxAllyClans {
+		return ErrAllyFull
+	}
+	for _, c := range members {
+		if c.IsAtWarWith(target.id) || c.IsAttackedBy(target.id) {
+			return ErrAllyEnemyPresent
+		}
+	}
+	return nil
+}
+
+// PendingAllyRequester возвращает лидера альянса, пригласившего target (nil если приглашения нет).
+func (m *Manager) PendingAllyRequester(target *model.Player) *model.Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.allyInvites[target.CharacterID()]
+	if !ok {
+		return nil
+	}
+	return inv.requester
+}
+
+// AnswerAlly обрабатывает ответ лидера клана на приглашение в альянс.
+// При accept=true возвращает вступивший клан.
+func (m *Manager) AnswerAlly(ctx context.Context, target *model.Player, accept bool) (c *Clan, requester *model.Player, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inv, ok := m.allyInvites[target.CharacterID()]
+	if !ok {
+		return nil, nil, ErrNoInvitation
+	}
+	delete(m.allyInvites, target.CharacterID())
+
+	if !accept {
+		return nil, inv.requester, nil
+	}
+	if m.now().After(inv.expires) {
+		return nil, inv.requester, ErrInvitationExpired
+	}
+
+	leaderClan := m.clans[inv.allyID]
+	if leaderClan == nil || !leaderClan.IsAllyLeader() {
+		return nil, inv.requester, ErrNotInAlliance
+	}
+	c, err = m.leaderClanLocked(target)
+	if err != nil {
+		return nil, inv.requester, err
+	}
+	if err := m.canJoinAllyLocked(leaderClan, c); err != nil {
+		return nil, inv.requester, err
+	}
+
+	rec := c.record()
+	rec.AllyID = leaderClan.id
+	rec.AllyName = leaderClan.AllyName()
+	rec.AllyCrestID = leaderClan.AllyCrestID()
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, inv.requester, err
+	}
+	return c, inv.requester, nil
+}
+
+// LeaveAlliance выводит клан лидера leader из альянса.
+// Клан получает штраф на вступление в альянс.
+func (m *Manager) LeaveAlliance(ctx context.Context, leader *model.Player) (*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+	if c.AllyID() == 0 {
+		return nil, ErrNotInAlliance
+	}
+	if c.IsAllyLeader() {
+		return nil, ErrAllyLeaderLeave
+	}
+
+	rec := c.record()
+	clearAlly(&rec)
+	rec.AllyPenaltyType = AllyPenaltyClanLeaved
+	rec.AllyPenaltyExpiry = m.now().Add(m.cfg.AllyLeavePenalty)
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DismissAllyClan исключает клан clanName из альянса (только лидер альянса).
+// Исключённый клан получает штраф на вступление, альянс — на приём новых кланов.
+func (m *Manager) DismissAllyClan(ctx context.Context, leader *model.Player, clanName string) (*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leaderClan, err := m.allyLeaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+	c := m.byName[strings.ToLower(clanName)]
+	if c == nil {
+		return nil, ErrClanNotFound
+	}
+	if c == leaderClan || c.AllyID() != leaderClan.id {
+		return nil, ErrNotInAlliance
+	}
+
+	now := m.now()
+	rec := c.record()
+	clearAlly(&rec)
+	rec.AllyPenaltyType = AllyPenaltyClanDismissed
+	rec.AllyPenaltyExpiry = now.Add(m.cfg.AllyDismissPenalty)
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, err
+	}
+
+	leaderRec := leaderClan.record()
+	leaderRec.AllyPenaltyType = AllyPenaltyDismissClan
+	leaderRec.AllyPenaltyExpiry = now.Add(m.cfg.AllyAcceptPenalty)
+	if err := m.updateClanLocked(ctx, leaderClan, leaderRec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DissolveAlliance распускает альянс (только лидер альянса).
+// Возвращает кланы, бывшие в альянсе. Клан лидера получает штраф на создание альянса.
+func (m *Manager) DissolveAlliance(ctx context.Context, leader *model.Player) ([]*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leaderClan, err := m.allyLeaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+
+	clans := m.allyClansLocked(leaderClan.id)
+	for _, c := range clans {
+		if c == leaderClan {
+			continue
+		}
+		rec := c.record()
+		clearAlly(&rec)
+		if err := m.updateClanLocked(ctx, c, rec); err != nil {
+			return nil, err
+		}
+	}
+
+	rec := leaderClan.record()
+	crestID := rec.AllyCrestID
+	clearAlly(&rec)
+	rec.AllyPenaltyType = AllyPenaltyDissolveAlly
+	rec.AllyPenaltyExpiry = m.now().Add(m.cfg.AllyDissolvePenalty)
+	if err := m.updateClanLocked(ctx, leaderClan, rec); err != nil {
+		return nil, err
+	}
+	if err := m.deleteCrestLocked(ctx, crestID); err != nil {
+		return clans, err
+	}
+	return clans, nil
+}
+
+// SetAllyCrest регистрирует эмблему альянса (только лидер альянса).
+// Пустые data удаляют эмблему. Возвращает кланы альянса.
+func (m *Manager) SetAllyCrest(ctx context.Context, leader *model.Player, data []byte) ([]*Clan, error) {
+	if len(data) > MaxAllyCrestSize {
+		return nil, ErrInvalidCrest
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leaderClan, err := m.allyLeaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+
+	oldID := leaderClan.AllyCrestID()
+	var newID int32
+	if len(data) > 0 {
+		if newID, err = m.saveCrestLocked(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	clans := m.allyClansLocked(leaderClan.id)
+	for _, c := range clans {
+		rec := c.record()
+		rec.AllyCrestID = newID
+		if err := m.updateClanLocked(ctx, c, rec); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.deleteCrestLocked(ctx, oldID); err != nil {
+		return clans, err
+	}
+	return clans, nil
+}
+
+// allyLeaderClanLocked возвращает клан p, если p — лидер альянса.
+func (m *Manager) allyLeaderClanLocked(p *model.Player) (*Clan, error) {
+	c, err := m.leaderClanLocked(p)
+	if err != nil {
+		return nil, err
+	}
+	if c.AllyID() == 0 {
+		return nil, ErrNotInAlliance
+	}
+	if !c.IsAllyLeader() {
+		return nil, ErrNotAllyLeader
+	}
+	return c, nil
+}
+
+// deleteCrestLocked удаляет эмблему (0 — ничего не делает).
+func (m *Manager) deleteCrestLocked(ctx context.Context, crestID int32) error {
+	if crestID == 0 {
+		return nil
+	}
+	if m.repo != nil {
+		if err := m.repo.DeleteCrest(ctx, crestID); err != nil {
+			return fmt.Errorf("deleting crest %d: %w", crestID, err)
+		}
+	}
+	delete(m.crests, crestID)
+	return nil
+}
+
+func clearAlly(rec *Record) {
+	rec.AllyID = 0
+	rec.AllyName = ""
+	rec.AllyCrestID = 0
+}
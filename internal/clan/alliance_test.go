@@ -0,0 +1,165 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newLeveledClan создаёт клан заданного уровня с лидером id.
+func newLeveledClan(t *testing.T, m *Manager, id int64, leaderName, name string, level int32) *Clan {
+	t.Helper()
+	ctx := context.Background()
+	c, err := m.Create(ctx, newTestPlayer(t, id, leaderName, 40), name)
+	if err != nil {
+		t.Fatalf("Create %s: %v", name, err)
+	}
+	rec := c.record()
+	rec.Level = level
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+	return c
+}
+
+func joinAlly(t *testing.T, m *Manager, c *Clan, allyLeader *Clan) {
+	t.Helper()
+	leader := allyLeader.Leader().Player()
+	target := c.Leader().Player()
+	if err := m.InviteAlly(leader, target); err != nil {
+		t.Fatalf("InviteAlly %s: %v", c.Name(), err)
+	}
+	if _, _, err := m.AnswerAlly(context.Background(), target, true); err != nil {
+		t.Fatalf("AnswerAlly %s: %v", c.Name(), err)
+	}
+}
+
+func TestManager_CreateAlliance(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	low := newLeveledClan(t, m, 1, "LowLeader", "Lowbies", 4)
+	high := newLeveledClan(t, m, 2, "HighLeader", "Highborn", 5)
+
+	if _, err := m.CreateAlliance(ctx, low.Leader().Player(), "Union"); !errors.Is(err, ErrClanLevelTooLow) {
+		t.Errorf("low level clan: err = %v", err)
+	}
+	c, err := m.CreateAlliance(ctx, high.Leader().Player(), "Union")
+	if err != nil {
+		t.Fatalf("CreateAlliance: %v", err)
+	}
+	if c.AllyID() != c.ID() || c.AllyName() != "Union" || !c.IsAllyLeader() {
+		t.Errorf("ally fields: id=%d name=%q", c.AllyID(), c.AllyName())
+	}
+	if _, err := m.CreateAlliance(ctx, high.Leader().Player(), "Other"); !errors.Is(err, ErrInAlliance) {
+		t.Errorf("second alliance: err = %v", err)
+	}
+
+	other := newLeveledClan(t, m, 3, "OtherLeader", "Others", 5)
+	if _, err := m.CreateAlliance(ctx, other.Leader().Player(), "UNION"); !errors.Is(err, ErrAllyNameTaken) {
+		t.Errorf("duplicate ally name: err = %v", err)
+	}
+}
+
+func TestManager_AllianceJoinLeavePenalties(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestManager(t, nil)
+	lead := newLeveledClan(t, m, 1, "AllyLeader", "Leaders", 5)
+	member := newLeveledClan(t, m, 2, "MemberLeader", "Members", 1)
+	if _, err := m.CreateAlliance(ctx, lead.Leader().Player(), "Union"); err != nil {
+		t.Fatalf("CreateAlliance: %v", err)
+	}
+
+	joinAlly(t, m, member, lead)
+	if member.AllyID() != lead.ID() || member.AllyName() != "Union" {
+		t.Fatalf("member clan not in alliance: allyID=%d", member.AllyID())
+	}
+	if got := len(m.AllyClans(lead.ID())); got != 2 {
+		t.Errorf("AllyClans = %d, want 2", got)
+	}
+
+	if _, err := m.LeaveAlliance(ctx, lead.Leader().Player()); !errors.Is(err, ErrAllyLeaderLeave) {
+		t.Errorf("leader leave: err = %v", err)
+	}
+	if _, err := m.LeaveAlliance(ctx, member.Leader().Player()); err != nil {
+		t.Fatalf("LeaveAlliance: %v", err)
+	}
+	if member.AllyID() != 0 {
+		t.Error("clan should have left the alliance")
+	}
+
+	// Вышедший клан не может вступить до истечения штрафа
+	if err := m.InviteAlly(lead.Leader().Player(), member.Leader().Player()); !errors.Is(err, ErrAllyPenalty) {
+		t.Errorf("invite after leave: err = %v", err)
+	}
+	*now = now.Add(m.cfg.AllyLeavePenalty + time.Second)
+	joinAlly(t, m, member, lead)
+}
+
+func TestManager_DismissAllyClan(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestManager(t, nil)
+	lead := newLeveledClan(t, m, 1, "AllyLeader", "Leaders", 5)
+	member := newLeveledClan(t, m, 2, "MemberLeader", "Members", 1)
+	third := newLeveledClan(t, m, 3, "ThirdLeader", "Thirds", 1)
+	if _, err := m.CreateAlliance(ctx, lead.Leader().Player(), "Union"); err != nil {
+		t.Fatalf("CreateAlliance: %v", err)
+	}
+	joinAlly(t, m, member, lead)
+
+	if _, err := m.DismissAllyClan(ctx, member.Leader().Player(), "Leaders"); !errors.Is(err, ErrNotAllyLeader) {
+		t.Errorf("dismiss by member: err = %v", err)
+	}
+	if _, err := m.DismissAllyClan(ctx, lead.Leader().Player(), "members"); err != nil {
+		t.Fatalf("DismissAllyClan: %v", err)
+	}
+	if member.AllyID() != 0 {
+		t.Error("dismissed clan should leave the alliance")
+	}
+	if pt, _ := member.AllyPenalty(); pt != AllyPenaltyClanDismissed {
+		t.Errorf("dismissed clan penalty = %d", pt)
+	}
+
+	// Альянс не принимает новые кланы после исключения
+	if err := m.InviteAlly(lead.Leader().Player(), third.Leader().Player()); !errors.Is(err, ErrAllyPenalty) {
+		t.Errorf("invite after dismiss: err = %v", err)
+	}
+	*now = now.Add(m.cfg.AllyAcceptPenalty + time.Second)
+	joinAlly(t, m, third, lead)
+}
+
+func TestManager_DissolveAllianceAndCrest(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	lead := newLeveledClan(t, m, 1, "AllyLeader", "Leaders", 5)
+	member := newLeveledClan(t, m, 2, "MemberLeader", "Members", 1)
+	if _, err := m.CreateAlliance(ctx, lead.Leader().Player(), "Union"); err != nil {
+		t.Fatalf("CreateAlliance: %v", err)
+	}
+	joinAlly(t, m, member, lead)
+
+	if _, err := m.SetAllyCrest(ctx, lead.Leader().Player(), make([]byte, MaxAllyCrestSize+1)); !errors.Is(err, ErrInvalidCrest) {
+		t.Errorf("oversized crest: err = %v", err)
+	}
+	if _, err := m.SetAllyCrest(ctx, lead.Leader().Player(), []byte{1, 2, 3}); err != nil {
+		t.Fatalf("SetAllyCrest: %v", err)
+	}
+	crestID := member.AllyCrestID()
+	if crestID == 0 || crestID != lead.AllyCrestID() || m.Crest(crestID) == nil {
+		t.Fatalf("ally crest not shared: lead=%d member=%d", lead.AllyCrestID(), crestID)
+	}
+
+	clans, err := m.DissolveAlliance(ctx, lead.Leader().Player())
+	if err != nil {
+		t.Fatalf("DissolveAlliance: %v", err)
+	}
+	if len(clans) != 2 || lead.AllyID() != 0 || member.AllyID() != 0 {
+		t.Errorf("alliance not dissolved: clans=%d", len(clans))
+	}
+	if m.Crest(crestID) != nil {
+		t.Error("ally crest should be deleted")
+	}
+	if _, err := m.CreateAlliance(ctx, lead.Leader().Player(), "Union2"); !errors.Is(err, ErrAllyPenalty) {
+		t.Errorf("create after dissolve: err = %v", err)
+	}
+}
@@ -0,0 +1,493 @@
+// Package clan implements clans: membership, ranks and privileges,
+// sub-pledges, level-up, reputation and crests.
+package clan
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Подразделения клана (значения совпадают с клиентом).
+const (
+	PledgeMain         int32 = 0
+	PledgeAcademy      int32 = -1
+	PledgeRoyalGuard1  int32 = 100
+	PledgeRoyalGuard2  int32 = 200
+	PledgeOrderKnight1 int32 = 1001
+	PledgeOrderKnight2 int32 = 1002
+	PledgeOrderKnight3 int32 = 2001
+	PledgeOrderKnight4 int32 = 2002
+)
+
+// Ранги (power grade) клана.
+const (
+	RankLeader  int32 = 1
+	RankMin     int32 = 1
+	RankMax     int32 = 9
+	RankAcademy int32 = 9
+)
+
+// MaxLevel — максимальный уровень клана в Interlude.
+const MaxLevel = 8
+
+// IsValidPledgeType returns true for the main clan and all Interlude sub-pledges.
+func IsValidPledgeType(t int32) bool {
+	switch t {
+	case PledgeMain, PledgeAcademy,
+		PledgeRoyalGuard1, PledgeRoyalGuard2,
+		PledgeOrderKnight1, PledgeOrderKnight2, PledgeOrderKnight3, PledgeOrderKnight4:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRoyalGuard returns true for royal guard sub-pledges.
+func isRoyalGuard(t int32) bool {
+	return t == PledgeRoyalGuard1 || t == PledgeRoyalGuard2
+}
+
+// isOrderOfKnights returns true for order of knights sub-pledges.
+func isOrderOfKnights(t int32) bool {
+	return t >= PledgeOrderKnight1
+}
+
+// defaultPowerGrade возвращает ранг нового участника подразделения (как в L2J).
+func defaultPowerGrade(pledgeType int32) int32 {
+	switch {
+	case pledgeType == PledgeAcademy:
+		return RankAcademy
+	case isOrderOfKnights(pledgeType):
+		return 8
+	case isRoyalGuard(pledgeType):
+		return 7
+	default:
+		return 6
+	}
+}
+
+// maxMembers возвращает вместимость подразделения для клана уровня level.
+func maxMembers(level, pledgeType int32) int {
+	switch {
+	case pledgeType == PledgeAcademy, isRoyalGuard(pledgeType):
+		return 20
+	case isOrderOfKnights(pledgeType):
+		return 10
+	}
+	switch level {
+	case 0:
+		return 10
+	case 1:
+		return 15
+	case 2:
+		return 20
+	case 3:
+		return 30
+	default:
+		return 40
+	}
+}
+
+// Member — участник клана (онлайн или оффлайн).
+type Member struct {
+	CharacterID int64
+	Name        string
+	Level       int32
+	ClassID     int32
+	PledgeType  int32
+	PowerGrade  int32
+
+	player *model.Player // nil если оффлайн
+}
+
+// Player возвращает онлайн-персонажа участника (nil если оффлайн).
+func (m *Member) Player() *model.Player {
+	return m.player
+}
+
+func (m *Member) clone() *Member {
+	if m == nil {
+		return nil
+	}
+	cp := *m
+	return &cp
+}
+
+// IsOnline возвращает true если участник в игре.
+func (m *Member) IsOnline() bool {
+	return m.player != nil
+}
+
+// SubPledge — подразделение клана (академия, королевская гвардия, рыцарский орден).
+type SubPledge struct {
+	Type     int32
+	Name     string
+	LeaderID int64 // 0 = без командира
+}
+
+// Clan — клан. Изменения выполняются через Manager; методы чтения thread-safe.
+type Clan struct {
+	id   int32
+	name string
+
+	mu           sync.RWMutex
+	leaderID     int64
+	level        int32
+	reputation   int32
+	crestID      int32
+	largeCrestID int32
+	members      map[int64]*Member // characterID → member
+	subPledges   map[int32]*SubPledge
+	rankPrivs    [RankMax + 1]Privilege
+
+	dissolvingExpiry  time.Time // ненулевое — клан в процессе роспуска
+	charPenaltyExpiry time.Time // до этого времени клан не принимает новых участников
+
+	allyID            int32 // 0 = клан не в альянсе; ID альянса = ID клана-лидера
+	allyName          string
+	allyCrestID       int32
+	allyPenaltyExpiry time.Time
+	allyPenaltyType   AllyPenalty
+
+	wars      map[int32]struct{} // кланы, которым мы объявили войну
+	attackers map[int32]struct{} // кланы, объявившие войну нам
+}
+
+func newClan(id int32, name string, leaderID int64) *Clan {
+	return &Clan{
+		id:         id,
+		name:       name,
+		leaderID:   leaderID,
+		members:    make(map[int64]*Member),
+		subPledges: make(map[int32]*SubPledge),
+		wars:       make(map[int32]struct{}),
+		attackers:  make(map[int32]struct{}),
+	}
+}
+
+// ID возвращает ID клана.
+func (c *Clan) ID() int32 {
+	return c.id
+}
+
+// Name возвращает название клана.
+func (c *Clan) Name() string {
+	return c.name
+}
+
+// LeaderID возвращает characterID лидера.
+func (c *Clan) LeaderID() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.leaderID
+}
+
+// Leader возвращает копию участника-лидера.
+func (c *Clan) Leader() *Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.members[c.leaderID].clone()
+}
+
+// Level возвращает уровень клана.
+func (c *Clan) Level() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.level
+}
+
+// Reputation возвращает очки репутации клана.
+func (c *Clan) Reputation() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reputation
+}
+
+// CrestID возвращает ID эмблемы клана (0 = нет).
+func (c *Clan) CrestID() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.crestID
+}
+
+// LargeCrestID возвращает ID большой эмблемы клана (0 = нет).
+func (c *Clan) LargeCrestID() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.largeCrestID
+}
+
+// DissolvingExpiry возвращает время окончательного роспуска (нулевое если роспуск не запрошен).
+func (c *Clan) DissolvingExpiry() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dissolvingExpiry
+}
+
+// CharPenaltyExpiry возвращает время, до которого клан не может принимать участников.
+func (c *Clan) CharPenaltyExpiry() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.charPenaltyExpiry
+}
+
+// AllyID возвращает ID альянса (0 = клан не в альянсе).
+func (c *Clan) AllyID() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allyID
+}
+
+// AllyName возвращает название альянса.
+func (c *Clan) AllyName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allyName
+}
+
+// AllyCrestID возвращает ID эмблемы альянса (0 = нет).
+func (c *Clan) AllyCrestID() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allyCrestID
+}
+
+// IsAllyLeader возвращает true если клан возглавляет свой альянс.
+func (c *Clan) IsAllyLeader() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allyID != 0 && c.allyID == c.id
+}
+
+// AllyPenalty возвращает действующий штраф альянса и время его окончания.
+func (c *Clan) AllyPenalty() (AllyPenalty, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allyPenaltyType, c.allyPenaltyExpiry
+}
+
+// IsAtWarWith возвращает true если клан объявил войну клану clanID.
+func (c *Clan) IsAtWarWith(clanID int32) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.wars[clanID]
+	return ok
+}
+
+// IsAttackedBy возвращает true если клан clanID объявил войну этому клану.
+func (c *Clan) IsAttackedBy(clanID int32) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.attackers[clanID]
+	return ok
+}
+
+// IsMutualWar возвращает true если кланы объявили войну друг другу.
+func (c *Clan) IsMutualWar(clanID int32) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, declared := c.wars[clanID]
+	_, attacked := c.attackers[clanID]
+	return declared && attacked
+}
+
+// HasWars возвращает true если клан участвует хотя бы в одной войне.
+func (c *Clan) HasWars() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.wars) > 0 || len(c.attackers) > 0
+}
+
+// WarList возвращает ID кланов, которым объявлена война.
+func (c *Clan) WarList() []int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return sortedIDs(c.wars)
+}
+
+// AttackerList возвращает ID кланов, объявивших войну этому клану.
+func (c *Clan) AttackerList() []int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return sortedIDs(c.attackers)
+}
+
+func sortedIDs(set map[int32]struct{}) []int32 {
+	out := make([]int32, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Member возвращает копию участника по characterID (nil если не найден).
+func (c *Clan) Member(characterID int64) *Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.members[characterID].clone()
+}
+
+// MemberByName возвращает копию участника по имени (без учёта регистра).
+func (c *Clan) MemberByName(name string) *Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.memberByNameLocked(name).clone()
+}
+
+func (c *Clan) memberByNameLocked(name string) *Member {
+	for _, m := range c.members {
+		if strings.EqualFold(m.Name, name) {
+			return m
+		}
+	}
+	return nil
+}
+
+// Members возвращает копии участников, отсортированные по имени.
+func (c *Clan) Members() []*Member {
+	c.mu.RLock()
+	out := make([]*Member, 0, len(c.members))
+	for _, m := range c.members {
+		out = append(out, m.clone())
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(out, func(a, b *Member) int {
+		switch {
+		case a.Name < b.Name:
+			return -1
+		case a.Name > b.Name:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return out
+}
+
+// MembersOf возвращает участников подразделения.
+func (c *Clan) MembersOf(pledgeType int32) []*Member {
+	all := c.Members()
+	out := all[:0]
+	for _, m := range all {
+		if m.PledgeType == pledgeType {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// OnlineMembers возвращает онлайн-персонажей клана.
+func (c *Clan) OnlineMembers() []*model.Player {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*model.Player, 0, len(c.members))
+	for _, m := range c.members {
+		if m.player != nil {
+			out = append(out, m.player)
+		}
+	}
+	return out
+}
+
+// MemberCount возвращает общее количество участников.
+func (c *Clan) MemberCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.members)
+}
+
+// SubPledge возвращает подразделение (nil если не создано).
+func (c *Clan) SubPledge(pledgeType int32) *SubPledge {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.subPledges[pledgeType]
+}
+
+// SubPledges возвращает созданные подразделения, отсортированные по типу.
+func (c *Clan) SubPledges() []SubPledge {
+	c.mu.RLock()
+	out := make([]SubPledge, 0, len(c.subPledges))
+	for _, sp := range c.subPledges {
+		out = append(out, *sp)
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(out, func(a, b SubPledge) int { return int(a.Type - b.Type) })
+	return out
+}
+
+// RankPrivileges возвращает привилегии ранга.
+func (c *Clan) RankPrivileges(rank int32) Privilege {
+	if rank < RankMin || rank > RankMax {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rankPrivs[rank]
+}
+
+// HasPrivilege возвращает true если участник обладает привилегией.
+// Лидер обладает всеми привилегиями.
+func (c *Clan) HasPrivilege(characterID int64, priv Privilege) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if characterID == c.leaderID {
+		return true
+	}
+	m := c.members[characterID]
+	if m == nil || m.PowerGrade < RankMin || m.PowerGrade > RankMax {
+		return false
+	}
+	return c.rankPrivs[m.PowerGrade].Has(priv)
+}
+
+// pledgeCountLocked возвращает количество участников подразделения.
+func (c *Clan) pledgeCountLocked(pledgeType int32) int {
+	n := 0
+	for _, m := range c.members {
+		if m.PledgeType == pledgeType {
+			n++
+		}
+	}
+	return n
+}
+
+// record возвращает снимок клана для сохранения.
+func (c *Clan) record() Record {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Record{
+		ID:                c.id,
+		Name:              c.name,
+		LeaderID:          c.leaderID,
+		Level:             c.level,
+		Reputation:        c.reputation,
+		CrestID:           c.crestID,
+		LargeCrestID:      c.largeCrestID,
+		DissolvingExpiry:  c.dissolvingExpiry,
+		CharPenaltyExpiry: c.charPenaltyExpiry,
+		AllyID:            c.allyID,
+		AllyName:          c.allyName,
+		AllyCrestID:       c.allyCrestID,
+		AllyPenaltyExpiry: c.allyPenaltyExpiry,
+		AllyPenaltyType:   c.allyPenaltyType,
+	}
+}
+
+func memberRecord(clanID int32, m *Member) MemberRecord {
+	return MemberRecord{
+		ClanID:      clanID,
+		CharacterID: m.CharacterID,
+		Name:        m.Name,
+		Level:       m.Level,
+		ClassID:     m.ClassID,
+		PledgeType:  m.PledgeType,
+		PowerGrade:  m.PowerGrade,
+	}
+}
@@ -0,0 +1,93 @@
+package clan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	// MaxCrestSize — максимальный размер эмблемы клана (16x12 DDS).
+	MaxCrestSize = 256
+
+	// MaxLargeCrestSize — максимальный размер большой эмблемы клана.
+	MaxLargeCrestSize = 2176
+
+	// CrestMinClanLevel — минимальный уровень клана для регистрации эмблемы.
+	CrestMinClanLevel = 3
+)
+
+// SetCrest регистрирует эмблему клана (привилегия PrivRegisterCrest).
+// Пустые data удаляют эмблему. large=true — большая эмблема.
+func (m *Manager) SetCrest(ctx context.Context, by *model.Player, data []byte, large bool) (*Clan, error) {
+	limit := MaxCrestSize
+	if large {
+		limit = MaxLargeCrestSize
+	}
+	if len(data) > limit {
+		return nil, ErrInvalidCrest
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[by.CharacterID()]
+	if c == nil {
+		return nil, ErrNotInClan
+	}
+	if !c.HasPrivilege(by.CharacterID(), PrivRegisterCrest) {
+		return nil, ErrNoPrivilege
+	}
+	if !c.DissolvingExpiry().IsZero() {
+		return nil, ErrDissolving
+	}
+
+	rec := c.record()
+	if len(data) > 0 && rec.Level < CrestMinClanLevel {
+		return nil, ErrClanLevelTooLow
+	}
+
+	oldID := rec.CrestID
+	if large {
+		oldID = rec.LargeCrestID
+	}
+
+	var newID int32
+	if len(data) > 0 {
+		id, err := m.saveCrestLocked(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		newID = id
+	}
+
+	if large {
+		rec.LargeCrestID = newID
+	} else {
+		rec.CrestID = newID
+	}
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, err
+	}
+
+	if err := m.deleteCrestLocked(ctx, oldID); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func (m *Manager) saveCrestLocked(ctx context.Context, data []byte) (int32, error) {
+	data = append([]byte(nil), data...)
+	if m.repo == nil {
+		m.nextID++
+		m.crests[m.nextID] = data
+		return m.nextID, nil
+	}
+	id, err := m.repo.SaveCrest(ctx, data)
+	if err != nil {
+		return 0, fmt.Errorf("saving crest: %w", err)
+	}
+	m.crests[id] = data
+	return id, nil
+}
@@ -0,0 +1,68 @@
+package clan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManager_SetCrest(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	member := newTestPlayer(t, 2, "Member", 40)
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	crest := bytes.Repeat([]byte{0xAB}, MaxCrestSize)
+	if _, err := m.SetCrest(ctx, leader, crest, false); !errors.Is(err, ErrClanLevelTooLow) {
+		t.Fatalf("crest at level 0: err = %v", err)
+	}
+
+	rec := c.record()
+	rec.Level = CrestMinClanLevel
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+
+	if _, err := m.SetCrest(ctx, member, crest, false); !errors.Is(err, ErrNoPrivilege) {
+		t.Errorf("crest without privilege: err = %v", err)
+	}
+	if _, err := m.SetCrest(ctx, leader, append(crest, 0), false); !errors.Is(err, ErrInvalidCrest) {
+		t.Errorf("oversized crest: err = %v", err)
+	}
+
+	if _, err := m.SetCrest(ctx, leader, crest, false); err != nil {
+		t.Fatalf("SetCrest: %v", err)
+	}
+	first := c.CrestID()
+	if first == 0 || !bytes.Equal(m.Crest(first), crest) {
+		t.Fatal("crest not stored")
+	}
+
+	large := bytes.Repeat([]byte{0xCD}, MaxLargeCrestSize)
+	if _, err := m.SetCrest(ctx, leader, large, true); err != nil {
+		t.Fatalf("SetCrest large: %v", err)
+	}
+	if c.LargeCrestID() == 0 || c.LargeCrestID() == first {
+		t.Errorf("large crest id = %d", c.LargeCrestID())
+	}
+
+	// Замена удаляет старую эмблему, пустые данные — удаление
+	if _, err := m.SetCrest(ctx, leader, []byte{1, 2, 3}, false); err != nil {
+		t.Fatalf("SetCrest replace: %v", err)
+	}
+	if m.Crest(first) != nil {
+		t.Error("old crest should be deleted")
+	}
+	if _, err := m.SetCrest(ctx, leader, nil, false); err != nil {
+		t.Fatalf("SetCrest remove: %v", err)
+	}
+	if c.CrestID() != 0 {
+		t.Errorf("crest id = %d after removal", c.CrestID())
+	}
+}
@@ -0,0 +1,76 @@
+package clan
+
+import "github.com/udisondev/la2go/internal/model"
+
+// Предметы, требуемые для повышения уровня клана.
+const (
+	ItemBloodMark         int32 = 1419
+	ItemAllianceManifesto int32 = 3874
+	ItemSealOfAspiration  int32 = 3870
+)
+
+// LevelRequirement — условия повышения клана до уровня Level.
+// SP, адена и предметы списываются с лидера; репутация — с клана.
+type LevelRequirement struct {
+	Level      int32
+	SP         int64
+	Adena      int64
+	ItemID     int32
+	ItemCount  int64
+	Reputation int32
+	MinMembers int
+}
+
+// levelRequirements — таблица требований Interlude (L2J Clan.levelUpClan).
+var levelRequirements = [...]LevelRequirement{
+	{Level: 1, SP: 20000, Adena: 650000},
+	{Level: 2, SP: 100000, Adena: 2500000},
+	{Level: 3, SP: 350000, ItemID: ItemBloodMark, ItemCount: 1},
+	{Level: 4, SP: 1000000, ItemID: ItemAllianceManifesto, ItemCount: 1},
+	{Level: 5, SP: 2500000, ItemID: ItemSealOfAspiration, ItemCount: 1},
+	{Level: 6, Reputation: 10000, MinMembers: 30},
+	{Level: 7, Reputation: 20000, MinMembers: 50},
+	{Level: 8, Reputation: 40000, MinMembers: 80},
+}
+
+// RequirementFor возвращает требования для повышения до уровня level.
+func RequirementFor(level int32) (LevelRequirement, bool) {
+	if level < 1 || level > MaxLevel {
+		return LevelRequirement{}, false
+	}
+	return levelRequirements[level-1], true
+}
+
+// checkLeader проверяет ресурсы лидера, не изменяя их.
+func (r LevelRequirement) checkLeader(leader *model.Player) error {
+	if leader.SP() < r.SP {
+		return ErrNotEnoughSP
+	}
+	if r.Adena > 0 && leader.Inventory().Adena() < r.Adena {
+		return ErrNotEnoughAdena
+	}
+	if r.ItemID != 0 && leader.Inventory().CountOf(r.ItemID) < r.ItemCount {
+		return ErrMissingItem
+	}
+	return nil
+}
+
+// consumeLeader списывает ресурсы лидера. Вызывается после checkLeader.
+func (r LevelRequirement) consumeLeader(leader *model.Player) error {
+	if r.SP > 0 && !leader.SpendSP(r.SP) {
+		return ErrNotEnoughSP
+	}
+	if r.Adena > 0 {
+		if err := leader.Inventory().DestroyByType(model.AdenaItemID, r.Adena); err != nil {
+			leader.AddSP(r.SP)
+			return ErrNotEnoughAdena
+		}
+	}
+	if r.ItemID != 0 {
+		if err := leader.Inventory().DestroyByType(r.ItemID, r.ItemCount); err != nil {
+			leader.AddSP(r.SP)
+			return ErrMissingItem
+		}
+	}
+	return nil
+}
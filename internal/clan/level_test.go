@@ -0,0 +1,112 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func giveItem(t *testing.T, p *model.Player, itemID int64, itemType, count int32) {
+	t.Helper()
+	item, err := model.NewItem(p.CharacterID(), itemType, count)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	item.SetItemID(itemID)
+	p.Inventory().AddItem(item)
+}
+
+func TestManager_LevelUp(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// 0 → 1: 20000 SP и 650000 адены
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrNotEnoughSP) {
+		t.Fatalf("without SP: err = %v", err)
+	}
+	leader.SetSP(25000)
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrNotEnoughAdena) {
+		t.Fatalf("without adena: err = %v", err)
+	}
+	if leader.SP() != 25000 {
+		t.Fatalf("SP changed after failed level-up: %d", leader.SP())
+	}
+
+	giveItem(t, leader, 500, model.AdenaItemID, 700000)
+	level, err := m.LevelUp(ctx, leader)
+	if err != nil || level != 1 || c.Level() != 1 {
+		t.Fatalf("LevelUp = %d, %v", level, err)
+	}
+	if leader.SP() != 5000 || leader.Inventory().Adena() != 50000 {
+		t.Errorf("after level-up: sp=%d adena=%d", leader.SP(), leader.Inventory().Adena())
+	}
+
+	// 2 → 3 требует Blood Mark
+	rec := c.record()
+	rec.Level = 2
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+	leader.SetSP(400000)
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrMissingItem) {
+		t.Fatalf("without blood mark: err = %v", err)
+	}
+	giveItem(t, leader, 501, ItemBloodMark, 1)
+	if level, err := m.LevelUp(ctx, leader); err != nil || level != 3 {
+		t.Fatalf("LevelUp to 3 = %d, %v", level, err)
+	}
+	if leader.Inventory().CountOf(ItemBloodMark) != 0 {
+		t.Error("blood mark should be consumed")
+	}
+}
+
+func TestManager_LevelUpReputationAndMembers(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	rec := c.record()
+	rec.Level = 5
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrNotEnoughReputation) {
+		t.Fatalf("without reputation: err = %v", err)
+	}
+	if _, err := m.AddReputation(ctx, c, 12000); err != nil {
+		t.Fatalf("AddReputation: %v", err)
+	}
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrNotEnoughMembers) {
+		t.Fatalf("without members: err = %v", err)
+	}
+
+	for i := range 29 {
+		join(t, m, leader, newTestPlayer(t, int64(100+i), "Member"+string(rune('A'+i%26))+string(rune('a'+i/26)), 40), PledgeMain)
+	}
+	if level, err := m.LevelUp(ctx, leader); err != nil || level != 6 {
+		t.Fatalf("LevelUp to 6 = %d, %v", level, err)
+	}
+	if c.Reputation() != 2000 {
+		t.Errorf("reputation = %d, want 2000", c.Reputation())
+	}
+
+	rec = c.record()
+	rec.Level = MaxLevel
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+	if _, err := m.LevelUp(ctx, leader); !errors.Is(err, ErrMaxLevel) {
+		t.Errorf("at max level: err = %v", err)
+	}
+}
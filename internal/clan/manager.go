@@ -0,0 +1,940 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	// MinCreateLevel — минимальный уровень персонажа для создания клана.
+	MinCreateLevel = 10
+
+	// AcademyMaxLevel — максимальный уровень персонажа для вступления в академию.
+	AcademyMaxLevel = 39
+
+	// InviteTimeout — время ожидания ответа на приглашение.
+	InviteTimeout = 15 * time.Second
+
+	// RoyalGuardCost и KnightUnitCost — стоимость создания подразделений в репутации
+	// (L2J ROYAL_GUARD_COST, KNIGHT_UNIT_COST).
+	RoyalGuardCost = 5000
+	KnightUnitCost = 10000
+)
+
+var nameTemplate = regexp.MustCompile(`^[A-Za-z0-9]{2,16}$`)
+
+var (
+	ErrInvalidName         = errors.New("invalid clan name")
+	ErrNameTaken           = errors.New("clan name already exists")
+	ErrLevelTooLow         = errors.New("character level is too low")
+	ErrAlreadyInClan       = errors.New("player is already in a clan")
+	ErrNotInClan           = errors.New("player is not in a clan")
+	ErrNotLeader           = errors.New("only the clan leader can do this")
+	ErrLeaderCannotLeave   = errors.New("clan leader cannot leave the clan")
+	ErrNoPrivilege         = errors.New("not enough clan privileges")
+	ErrCreatePenalty       = errors.New("cannot create a clan yet")
+	ErrJoinPenalty         = errors.New("cannot join a clan yet")
+	ErrAcceptPenalty       = errors.New("clan cannot accept new members yet")
+	ErrDissolving          = errors.New("clan is being dissolved")
+	ErrNotDissolving       = errors.New("clan is not being dissolved")
+	ErrPledgeFull          = errors.New("pledge is full")
+	ErrNoSuchPledge        = errors.New("pledge does not exist")
+	ErrPledgeExists        = errors.New("pledge already exists")
+	ErrAcademyLevel        = errors.New("character level is too high for the academy")
+	ErrTargetBusy          = errors.New("player is answering another invitation")
+	ErrNoInvitation        = errors.New("no pending invitation")
+	ErrInvitationExpired   = errors.New("invitation expired")
+	ErrSelfInvite          = errors.New("cannot invite yourself")
+	ErrMemberNotFound      = errors.New("clan member not found")
+	ErrMaxLevel            = errors.New("clan is already at maximum level")
+	ErrClanLevelTooLow     = errors.New("clan level is too low")
+	ErrNotEnoughSP         = errors.New("not enough SP")
+	ErrNotEnoughAdena      = errors.New("not enough adena")
+	ErrMissingItem         = errors.New("required item is missing")
+	ErrNotEnoughReputation = errors.New("not enough clan reputation")
+	ErrNotEnoughMembers    = errors.New("not enough clan members")
+	ErrInvalidRank         = errors.New("invalid rank")
+	ErrInvalidCrest        = errors.New("invalid crest data")
+)
+
+// Config — штрафы и задержки клановой системы.
+type Config struct {
+	JoinPenalty   time.Duration // после выхода/исключения нельзя вступить (L2J ALT_CLAN_JOIN_DAYS)
+	CreatePenalty time.Duration // после роспуска лидер не может создать клан (ALT_CLAN_CREATE_DAYS)
+	AcceptPenalty time.Duration // после исключения клан не принимает участников (ALT_ACCEPT_CLAN_DAYS_WHEN_DISMISSED)
+	DissolveDelay time.Duration // задержка роспуска (ALT_CLAN_DISSOLVE_DAYS)
+
+	AllyLeavePenalty    time.Duration // после выхода из альянса клан не может вступить (ALT_ALLY_JOIN_DAYS_WHEN_LEAVED)
+	AllyDismissPenalty  time.Duration // исключённый клан не может вступить (ALT_ALLY_JOIN_DAYS_WHEN_DISMISSED)
+	AllyAcceptPenalty   time.Duration // после исключения альянс не принимает кланы (ALT_ACCEPT_CLAN_DAYS_WHEN_DISMISSED)
+	AllyDissolvePenalty time.Duration // после роспуска альянса нельзя создать новый (ALT_CREATE_ALLY_DAYS_WHEN_DISSOLVED)
+	MaxAllyClans        int           // максимум кланов в альянсе (ALT_MAX_NUM_OF_CLANS_IN_ALLY)
+
+	WarMinMembers       int   // минимум участников для объявления войны (ALT_CLAN_MEMBERS_FOR_WAR)
+	SurrenderReputation int32 // репутация, теряемая при капитуляции
+}
+
+// DefaultConfig возвращает значения по умолчанию L2J.
+func DefaultConfig() Config {
+	const day = 24 * time.Hour
+	return Config{
+		JoinPenalty:   1 * day,
+		CreatePenalty: 10 * day,
+		AcceptPenalty: 1 * day,
+		DissolveDelay: 7 * day,
+
+		AllyLeavePenalty:    1 * day,
+		AllyDismissPenalty:  1 * day,
+		AllyAcceptPenalty:   1 * day,
+		AllyDissolvePenalty: 1 * day,
+		MaxAllyClans:        3,
+
+		WarMinMembers:       15,
+		SurrenderReputation: 500,
+	}
+}
+
+// invitation — ожидающее ответа приглашение в клан.
+type invitation struct {
+	requester  *model.Player
+	clanID     int32
+	pledgeType int32
+	expires    time.Time
+}
+
+// Manager управляет кланами.
+// Thread-safe: изменения выполняются под одним mutex (операции с кланами редкие),
+// сохранение в repository выполняется до изменения состояния в памяти.
+type Manager struct {
+	repo Repository // nil = кланы не сохраняются
+	cfg  Config
+
+	mu          sync.Mutex
+	clans       map[int32]*Clan
+	byName      map[string]*Clan // lowercase name → clan
+	byMember    map[int64]*Clan  // characterID → clan
+	invites     map[int64]invitation
+	allyInvites map[int64]allyInvitation
+	crests      map[int32][]byte
+	penalties   map[int64]Penalty
+
+	nextID int32 // ID клана/эмблемы без repository
+	now    func() time.Time
+}
+
+// NewManager создаёт менеджер кланов. repo может быть nil (кланы только в памяти).
+func NewManager(repo Repository, cfg Config) *Manager {
+	return &Manager{
+		repo:        repo,
+		cfg:         cfg,
+		clans:       make(map[int32]*Clan),
+		byName:      make(map[string]*Clan),
+		byMember:    make(map[int64]*Clan),
+		invites:     make(map[int64]invitation),
+		allyInvites: make(map[int64]allyInvitation),
+		crests:      make(map[int32][]byte),
+		penalties:   make(map[int64]Penalty),
+		now:         time.Now,
+	}
+}
+
+// Load загружает кланы из repository.
+func (m *Manager) Load(ctx context.Context) error {
+	if m.repo == nil {
+		return nil
+	}
+	snap, err := m.repo.LoadClans(ctx)
+	if err != nil {
+		return fmt.Errorf("loading clans: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, rec := range snap.Clans {
+		c := newClan(rec.ID, rec.Name, rec.LeaderID)
+		c.level = rec.Level
+		c.reputation = rec.Reputation
+		c.crestID = rec.CrestID
+		c.largeCrestID = rec.LargeCrestID
+		c.dissolvingExpiry = rec.DissolvingExpiry
+		c.charPenaltyExpiry = rec.CharPenaltyExpiry
+		c.allyID = rec.AllyID
+		c.allyName = rec.AllyName
+		c.allyCrestID = rec.AllyCrestID
+		c.allyPenaltyExpiry = rec.AllyPenaltyExpiry
+		c.allyPenaltyType = rec.AllyPenaltyType
+		m.clans[c.id] = c
+		m.byName[strings.ToLower(c.name)] = c
+	}
+	for _, mr := range snap.Members {
+		c := m.clans[mr.ClanID]
+		if c == nil {
+			continue
+		}
+		c.members[mr.CharacterID] = &Member{
+			CharacterID: mr.CharacterID,
+			Name:        mr.Name,
+			Level:       mr.Level,
+			ClassID:     mr.ClassID,
+			PledgeType:  mr.PledgeType,
+			PowerGrade:  mr.PowerGrade,
+		}
+		m.byMember[mr.CharacterID] = c
+	}
+	for _, sp := range snap.SubPledges {
+		if c := m.clans[sp.ClanID]; c != nil {
+			s := sp.SubPledge
+			c.subPledges[s.Type] = &s
+		}
+	}
+	for _, r := range snap.Ranks {
+		if c := m.clans[r.ClanID]; c != nil && r.Rank >= RankMin && r.Rank <= RankMax {
+			c.rankPrivs[r.Rank] = r.Privileges
+		}
+	}
+	for _, w := range snap.Wars {
+		c, enemy := m.clans[w.ClanID], m.clans[w.EnemyID]
+		if c == nil || enemy == nil {
+			continue
+		}
+		c.wars[enemy.id] = struct{}{}
+		enemy.attackers[c.id] = struct{}{}
+	}
+	for id, data := range snap.Crests {
+		m.crests[id] = data
+	}
+	for id, p := range snap.Penalties {
+		m.penalties[id] = p
+	}
+	return nil
+}
+
+// Count возвращает количество кланов.
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.clans)
+}
+
+// Clan возвращает клан по ID (nil если не найден).
+func (m *Manager) Clan(id int32) *Clan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.clans[id]
+}
+
+// ClanByName возвращает клан по названию (без учёта регистра).
+func (m *Manager) ClanByName(name string) *Clan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.byName[strings.ToLower(name)]
+}
+
+// ClanOf возвращает клан игрока (nil если не в клане).
+func (m *Manager) ClanOf(p *model.Player) *Clan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.byMember[p.CharacterID()]
+}
+
+// Penalty возвращает штрафы персонажа.
+func (m *Manager) Penalty(characterID int64) Penalty {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.penalties[characterID]
+}
+
+// Crest возвращает данные эмблемы (nil если не найдена).
+func (m *Manager) Crest(crestID int32) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.crests[crestID]
+}
+
+// AttachPlayer связывает вошедшего в игру персонажа с его кланом.
+func (m *Manager) AttachPlayer(p *model.Player) *Clan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[p.CharacterID()]
+	if c == nil {
+		p.SetClan(0, 0, 0)
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mem := c.members[p.CharacterID()]
+	mem.player = p
+	mem.Level = p.Level()
+	mem.ClassID = p.ClassID()
+	p.SetClan(c.id, mem.PledgeType, mem.PowerGrade)
+	return c
+}
+
+// DetachPlayer помечает участника клана оффлайн и отменяет его приглашения.
+func (m *Manager) DetachPlayer(p *model.Player) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.invites, p.CharacterID())
+	for target, inv := range m.invites {
+		if inv.requester == p {
+			delete(m.invites, target)
+		}
+	}
+	delete(m.allyInvites, p.CharacterID())
+	for target, inv := range m.allyInvites {
+		if inv.requester == p {
+			delete(m.allyInvites, target)
+		}
+	}
+
+	c := m.byMember[p.CharacterID()]
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if mem := c.members[p.CharacterID()]; mem != nil && mem.player == p {
+		mem.player = nil
+	}
+}
+
+// Create создаёт клан с лидером leader.
+func (m *Manager) Create(ctx context.Context, leader *model.Player, name string) (*Clan, error) {
+	if !nameTemplate.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+	if leader.Level() < MinCreateLevel {
+		return nil, ErrLevelTooLow
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.byMember[leader.CharacterID()] != nil {
+		return nil, ErrAlreadyInClan
+	}
+	if m.now().Before(m.penalties[leader.CharacterID()].CreateExpiry) {
+		return nil, ErrCreatePenalty
+	}
+	if m.byName[strings.ToLower(name)] != nil {
+		return nil, ErrNameTaken
+	}
+
+	rec := Record{Name: name, LeaderID: leader.CharacterID()}
+	id, err := m.createClanRecord(ctx, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newClan(id, name, leader.CharacterID())
+	mem := &Member{
+		CharacterID: leader.CharacterID(),
+		Name:        leader.Name(),
+		Level:       leader.Level(),
+		ClassID:     leader.ClassID(),
+		PledgeType:  PledgeMain,
+		PowerGrade:  RankLeader,
+		player:      leader,
+	}
+	if m.repo != nil {
+		if err := m.repo.SaveMember(ctx, memberRecord(id, mem)); err != nil {
+			return nil, fmt.Errorf("saving clan leader: %w", err)
+		}
+	}
+
+	c.members[mem.CharacterID] = mem
+	m.clans[id] = c
+	m.byName[strings.ToLower(name)] = c
+	m.byMember[mem.CharacterID] = c
+	leader.SetClan(id, PledgeMain, RankLeader)
+	return c, nil
+}
+
+func (m *Manager) createClanRecord(ctx context.Context, rec Record) (int32, error) {
+	if m.repo == nil {
+		m.nextID++
+		return m.nextID, nil
+	}
+	id, err := m.repo.CreateClan(ctx, rec)
+	if err != nil {
+		return 0, fmt.Errorf("creating clan %q: %w", rec.Name, err)
+	}
+	return id, nil
+}
+
+// Dissolve запрашивает роспуск клана (только лидер).
+// Клан распускается по истечении Config.DissolveDelay в ProcessDissolutions.
+func (m *Manager) Dissolve(ctx context.Context, leader *model.Player) (*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return nil, err
+	}
+	if !c.DissolvingExpiry().IsZero() {
+		return nil, ErrDissolving
+	}
+	if c.AllyID() != 0 {
+		return nil, ErrInAlliance
+	}
+	if c.HasWars() {
+		return nil, ErrAtWar
+	}
+
+	rec := c.record()
+	rec.DissolvingExpiry = m.now().Add(m.cfg.DissolveDelay)
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CancelDissolve отменяет запрошенный роспуск клана.
+func (m *Manager) CancelDissolve(ctx context.Context, leader *model.Player) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return err
+	}
+	if c.DissolvingExpiry().IsZero() {
+		return ErrNotDissolving
+	}
+
+	rec := c.record()
+	rec.DissolvingExpiry = time.Time{}
+	return m.updateClanLocked(ctx, c, rec)
+}
+
+// ProcessDissolutions распускает кланы, у которых истекла задержка роспуска.
+// Лидер получает штраф на создание клана. Возвращает распущенные кланы
+// (их участники уже отвязаны от клана).
+func (m *Manager) ProcessDissolutions(ctx context.Context) ([]*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var dissolved []*Clan
+	for _, c := range m.clans {
+		expiry := c.DissolvingExpiry()
+		if expiry.IsZero() || now.Before(expiry) {
+			continue
+		}
+		if err := m.destroyLocked(ctx, c, now); err != nil {
+			return dissolved, err
+		}
+		dissolved = append(dissolved, c)
+	}
+	return dissolved, nil
+}
+
+func (m *Manager) destroyLocked(ctx context.Context, c *Clan, now time.Time) error {
+	leaderID := c.LeaderID()
+	pen := m.penalties[leaderID]
+	pen.CreateExpiry = now.Add(m.cfg.CreatePenalty)
+
+	if m.repo != nil {
+		if err := m.repo.DeleteClan(ctx, c.id); err != nil {
+			return fmt.Errorf("deleting clan %d: %w", c.id, err)
+		}
+		if err := m.repo.SavePenalty(ctx, leaderID, pen); err != nil {
+			return fmt.Errorf("saving dissolution penalty: %w", err)
+		}
+	}
+	m.penalties[leaderID] = pen
+
+	c.mu.Lock()
+	delete(m.crests, c.crestID)
+	delete(m.crests, c.largeCrestID)
+	for id, mem := range c.members {
+		if mem.player != nil {
+			mem.player.SetClan(0, 0, 0)
+		}
+		delete(m.byMember, id)
+	}
+	c.mu.Unlock()
+	m.dropWarsLocked(c)
+
+	for target, inv := range m.invites {
+		if inv.clanID == c.id {
+			delete(m.invites, target)
+		}
+	}
+	delete(m.clans, c.id)
+	delete(m.byName, strings.ToLower(c.name))
+	return nil
+}
+
+// Invite регистрирует приглашение target в подразделение pledgeType клана requester.
+func (m *Manager) Invite(requester, target *model.Player, pledgeType int32) error {
+	if requester == target {
+		return ErrSelfInvite
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[requester.CharacterID()]
+	if c == nil {
+		return ErrNotInClan
+	}
+	if !c.HasPrivilege(requester.CharacterID(), PrivJoinClan) {
+		return ErrNoPrivilege
+	}
+	if err := m.canAcceptLocked(c, target, pledgeType); err != nil {
+		return err
+	}
+	if inv, ok := m.invites[target.CharacterID()]; ok && m.now().Before(inv.expires) {
+		return ErrTargetBusy
+	}
+
+	m.invites[target.CharacterID()] = invitation{
+		requester:  requester,
+		clanID:     c.id,
+		pledgeType: pledgeType,
+		expires:    m.now().Add(InviteTimeout),
+	}
+	return nil
+}
+
+// canAcceptLocked проверяет, может ли target вступить в подразделение клана.
+func (m *Manager) canAcceptLocked(c *Clan, target *model.Player, pledgeType int32) error {
+	now := m.now()
+	if m.byMember[target.CharacterID()] != nil {
+		return ErrAlreadyInClan
+	}
+	if now.Before(m.penalties[target.CharacterID()].JoinExpiry) {
+		return ErrJoinPenalty
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.dissolvingExpiry.IsZero() {
+		return ErrDissolving
+	}
+	if now.Before(c.charPenaltyExpiry) {
+		return ErrAcceptPenalty
+	}
+	if pledgeType != PledgeMain && c.subPledges[pledgeType] == nil {
+		return ErrNoSuchPledge
+	}
+	if pledgeType == PledgeAcademy && target.Level() > AcademyMaxLevel {
+		return ErrAcademyLevel
+	}
+	if c.pledgeCountLocked(pledgeType) >= maxMembers(c.level, pledgeType) {
+		return ErrPledgeFull
+	}
+	return nil
+}
+
+// PendingRequester возвращает игрока, пригласившего target (nil если приглашения нет).
+func (m *Manager) PendingRequester(target *model.Player) *model.Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[target.CharacterID()]
+	if !ok {
+		return nil
+	}
+	return inv.requester
+}
+
+// Answer обрабатывает ответ на приглашение в клан.
+// При accept=true возвращает клан, в который вступил target.
+// requester возвращается всегда, если приглашение существовало.
+func (m *Manager) Answer(ctx context.Context, target *model.Player, accept bool) (c *Clan, requester *model.Player, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inv, ok := m.invites[target.CharacterID()]
+	if !ok {
+		return nil, nil, ErrNoInvitation
+	}
+	delete(m.invites, target.CharacterID())
+
+	if !accept {
+		return nil, inv.requester, nil
+	}
+	if m.now().After(inv.expires) {
+		return nil, inv.requester, ErrInvitationExpired
+	}
+	c = m.clans[inv.clanID]
+	if c == nil {
+		return nil, inv.requester, ErrNotInClan
+	}
+	if err := m.canAcceptLocked(c, target, inv.pledgeType); err != nil {
+		return nil, inv.requester, err
+	}
+
+	mem := &Member{
+		CharacterID: target.CharacterID(),
+		Name:        target.Name(),
+		Level:       target.Level(),
+		ClassID:     target.ClassID(),
+		PledgeType:  inv.pledgeType,
+		PowerGrade:  defaultPowerGrade(inv.pledgeType),
+		player:      target,
+	}
+	if m.repo != nil {
+		if err := m.repo.SaveMember(ctx, memberRecord(c.id, mem)); err != nil {
+			return nil, inv.requester, fmt.Errorf("saving clan member: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	c.members[mem.CharacterID] = mem
+	c.mu.Unlock()
+	m.byMember[mem.CharacterID] = c
+	target.SetClan(c.id, mem.PledgeType, mem.PowerGrade)
+	return c, inv.requester, nil
+}
+
+// Leave выводит игрока из клана. Игрок получает штраф на вступление.
+func (m *Manager) Leave(ctx context.Context, p *model.Player) (*Clan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[p.CharacterID()]
+	if c == nil {
+		return nil, ErrNotInClan
+	}
+	if c.LeaderID() == p.CharacterID() {
+		return nil, ErrLeaderCannotLeave
+	}
+	if err := m.removeMemberLocked(ctx, c, p.CharacterID()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Expel исключает участника по имени (привилегия PrivDismiss).
+// Исключённый получает штраф на вступление, клан — штраф на приём новых участников.
+func (m *Manager) Expel(ctx context.Context, by *model.Player, name string) (*Clan, *Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[by.CharacterID()]
+	if c == nil {
+		return nil, nil, ErrNotInClan
+	}
+	if !c.HasPrivilege(by.CharacterID(), PrivDismiss) {
+		return nil, nil, ErrNoPrivilege
+	}
+	mem := c.MemberByName(name)
+	if mem == nil || mem.CharacterID == c.LeaderID() || mem.CharacterID == by.CharacterID() {
+		return nil, nil, ErrMemberNotFound
+	}
+
+	rec := c.record()
+	rec.CharPenaltyExpiry = m.now().Add(m.cfg.AcceptPenalty)
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, nil, err
+	}
+	if err := m.removeMemberLocked(ctx, c, mem.CharacterID); err != nil {
+		return nil, nil, err
+	}
+	return c, mem, nil
+}
+
+// removeMemberLocked удаляет участника и назначает ему штраф на вступление.
+func (m *Manager) removeMemberLocked(ctx context.Context, c *Clan, characterID int64) error {
+	pen := m.penalties[characterID]
+	pen.JoinExpiry = m.now().Add(m.cfg.JoinPenalty)
+
+	if m.repo != nil {
+		if err := m.repo.DeleteMember(ctx, characterID); err != nil {
+			return fmt.Errorf("deleting clan member %d: %w", characterID, err)
+		}
+		if err := m.repo.SavePenalty(ctx, characterID, pen); err != nil {
+			return fmt.Errorf("saving clan penalty %d: %w", characterID, err)
+		}
+	}
+	m.penalties[characterID] = pen
+
+	c.mu.Lock()
+	if mem := c.members[characterID]; mem != nil && mem.player != nil {
+		mem.player.SetClan(0, 0, 0)
+	}
+	delete(c.members, characterID)
+	c.mu.Unlock()
+	delete(m.byMember, characterID)
+	return nil
+}
+
+// LevelUp повышает уровень клана лидера, списывая требуемые ресурсы.
+// Возвращает новый уровень.
+func (m *Manager) LevelUp(ctx context.Context, leader *model.Player) (int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return 0, err
+	}
+	if !c.DissolvingExpiry().IsZero() {
+		return 0, ErrDissolving
+	}
+
+	rec := c.record()
+	req, ok := RequirementFor(rec.Level + 1)
+	if !ok {
+		return 0, ErrMaxLevel
+	}
+	if rec.Reputation < req.Reputation {
+		return 0, ErrNotEnoughReputation
+	}
+	if c.MemberCount() < req.MinMembers {
+		return 0, ErrNotEnoughMembers
+	}
+	if err := req.checkLeader(leader); err != nil {
+		return 0, err
+	}
+
+	rec.Level = req.Level
+	rec.Reputation -= req.Reputation
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return 0, err
+	}
+	if err := req.consumeLeader(leader); err != nil {
+		return 0, err
+	}
+	return rec.Level, nil
+}
+
+// AddReputation изменяет репутацию клана на delta. Возвращает новое значение.
+func (m *Manager) AddReputation(ctx context.Context, c *Clan, delta int32) (int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec := c.record()
+	rec.Reputation += delta
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return 0, err
+	}
+	return rec.Reputation, nil
+}
+
+// CreateSubPledge создаёт подразделение клана (только лидер).
+// Академия требует 5 уровень клана, королевская гвардия — 6, рыцарские ордены — 7;
+// гвардия и ордены оплачиваются репутацией.
+func (m *Manager) CreateSubPledge(ctx context.Context, leader *model.Player, pledgeType int32, name string) error {
+	if pledgeType == PledgeMain || !IsValidPledgeType(pledgeType) {
+		return ErrNoSuchPledge
+	}
+	if !nameTemplate.MatchString(name) {
+		return ErrInvalidName
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.leaderClanLocked(leader)
+	if err != nil {
+		return err
+	}
+
+	minLevel, cost := int32(5), int32(0)
+	switch {
+	case isRoyalGuard(pledgeType):
+		minLevel, cost = 6, RoyalGuardCost
+	case isOrderOfKnights(pledgeType):
+		minLevel, cost = 7, KnightUnitCost
+	}
+
+	rec := c.record()
+	if rec.Level < minLevel {
+		return ErrClanLevelTooLow
+	}
+	if c.SubPledge(pledgeType) != nil {
+		return ErrPledgeExists
+	}
+	// Рыцарские ордены 1001/1002 подчиняются гвардии 100, 2001/2002 — гвардии 200
+	if isOrderOfKnights(pledgeType) && c.SubPledge(pledgeType/1000*100) == nil {
+		return ErrNoSuchPledge
+	}
+	for _, sp := range c.SubPledges() {
+		if strings.EqualFold(sp.Name, name) {
+			return ErrNameTaken
+		}
+	}
+	if rec.Reputation < cost {
+		return ErrNotEnoughReputation
+	}
+
+	sp := SubPledge{Type: pledgeType, Name: name}
+	if m.repo != nil {
+		if err := m.repo.SaveSubPledge(ctx, SubPledgeRecord{ClanID: c.id, SubPledge: sp}); err != nil {
+			return fmt.Errorf("saving sub-pledge: %w", err)
+		}
+	}
+	if cost > 0 {
+		rec.Reputation -= cost
+		if err := m.updateClanLocked(ctx, c, rec); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.subPledges[pledgeType] = &sp
+	c.mu.Unlock()
+	return nil
+}
+
+// SetRankPrivileges устанавливает привилегии ранга (привилегия PrivManageRanks).
+func (m *Manager) SetRankPrivileges(ctx context.Context, by *model.Player, rank int32, privs Privilege) error {
+	if rank < RankMin || rank > RankMax {
+		return ErrInvalidRank
+	}
+	privs &= PrivAll
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[by.CharacterID()]
+	if c == nil {
+		return ErrNotInClan
+	}
+	if !c.HasPrivilege(by.CharacterID(), PrivManageRanks) {
+		return ErrNoPrivilege
+	}
+	if m.repo != nil {
+		if err := m.repo.SaveRankPrivileges(ctx, RankRecord{ClanID: c.id, Rank: rank, Privileges: privs}); err != nil {
+			return fmt.Errorf("saving rank privileges: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	c.rankPrivs[rank] = privs
+	c.mu.Unlock()
+	return nil
+}
+
+// SetPowerGrade назначает участнику ранг (привилегия PrivManageRanks).
+func (m *Manager) SetPowerGrade(ctx context.Context, by *model.Player, name string, grade int32) (*Member, error) {
+	if grade <= RankLeader || grade > RankMax {
+		return nil, ErrInvalidRank
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.byMember[by.CharacterID()]
+	if c == nil {
+		return nil, ErrNotInClan
+	}
+	if !c.HasPrivilege(by.CharacterID(), PrivManageRanks) {
+		return nil, ErrNoPrivilege
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mem := c.memberByNameLocked(name)
+	if mem == nil || mem.CharacterID == c.leaderID {
+		return nil, ErrMemberNotFound
+	}
+	// Академики всегда имеют ранг академии
+	if mem.PledgeType == PledgeAcademy && grade != RankAcademy {
+		return nil, ErrInvalidRank
+	}
+
+	rec := memberRecord(c.id, mem)
+	rec.PowerGrade = grade
+	if m.repo != nil {
+		if err := m.repo.SaveMember(ctx, rec); err != nil {
+			return nil, fmt.Errorf("saving clan member: %w", err)
+		}
+	}
+	mem.PowerGrade = grade
+	if mem.player != nil {
+		mem.player.SetPowerGrade(grade)
+	}
+	return mem.clone(), nil
+}
+
+// leaderClanLocked возвращает клан, лидером которого является p.
+func (m *Manager) leaderClanLocked(p *model.Player) (*Clan, error) {
+	c := m.byMember[p.CharacterID()]
+	if c == nil {
+		return nil, ErrNotInClan
+	}
+	if c.LeaderID() != p.CharacterID() {
+		return nil, ErrNotLeader
+	}
+	return c, nil
+}
+
+// updateClanLocked сохраняет rec и применяет его к клану.
+func (m *Manager) updateClanLocked(ctx context.Context, c *Clan, rec Record) error {
+	if m.repo != nil {
+		if err := m.repo.UpdateClan(ctx, rec); err != nil {
+			return fmt.Errorf("updating clan %d: %w", c.id, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leaderID = rec.LeaderID
+	c.level = rec.Level
+	c.reputation = rec.Reputation
+	c.crestID = rec.CrestID
+	c.largeCrestID = rec.LargeCrestID
+	c.dissolvingExpiry = rec.DissolvingExpiry
+	c.charPenaltyExpiry = rec.CharPenaltyExpiry
+	c.allyID = rec.AllyID
+	c.allyName = rec.AllyName
+	c.allyCrestID = rec.AllyCrestID
+	c.allyPenaltyExpiry = rec.AllyPenaltyExpiry
+	c.allyPenaltyType = rec.AllyPenaltyType
+	return nil
+}
+
+// ForEach вызывает fn для каждого клана.
+func (m *Manager) ForEach(fn func(*Clan)) {
+	m.mu.Lock()
+	clans := make([]*Clan, 0, len(m.clans))
+	for _, c := range m.clans {
+		clans = append(clans, c)
+	}
+	m.mu.Unlock()
+
+	for _, c := range clans {
+		fn(c)
+	}
+}
+
+// Start периодически завершает роспуск кланов и вызывает fn для каждого распущенного.
+// Блокируется до отмены ctx.
+func (m *Manager) Start(ctx context.Context, interval time.Duration, fn func(*Clan)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			dissolved, err := m.ProcessDissolutions(ctx)
+			if err != nil {
+				slog.Error("failed to process clan dissolutions", "error", err)
+			}
+			for _, c := range dissolved {
+				fn(c)
+			}
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func newTestPlayer(t *testing.T, id int64, name string, level int32) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, name, level, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	return p
+}
+
+// newTestManager создаёт менеджер с управляемым временем.
+func newTestManager(t *testing.T, repo Repository) (*Manager, *time.Time) {
+	t.Helper()
+	m := NewManager(repo, DefaultConfig())
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func join(t *testing.T, m *Manager, by, target *model.Player, pledgeType int32) {
+	t.Helper()
+	if err := m.Invite(by, target, pledgeType); err != nil {
+		t.Fatalf("Invite %s: %v", target.Name(), err)
+	}
+	if _, _, err := m.Answer(context.Background(), target, true); err != nil {
+		t.Fatalf("Answer %s: %v", target.Name(), err)
+	}
+}
+
+func TestManager_Create(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+
+	if _, err := m.Create(ctx, leader, "bad name!"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("invalid name: err = %v", err)
+	}
+	if _, err := m.Create(ctx, newTestPlayer(t, 2, "Newbie", 5), "Newbies"); !errors.Is(err, ErrLevelTooLow) {
+		t.Errorf("low level: err = %v", err)
+	}
+
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if leader.ClanID() != c.ID() || leader.PowerGrade() != RankLeader {
+		t.Errorf("leader clan fields: clanID=%d grade=%d", leader.ClanID(), leader.PowerGrade())
+	}
+	if m.ClanByName("KNIGHTS") != c {
+		t.Error("ClanByName should be case-insensitive")
+	}
+
+	if _, err := m.Create(ctx, newTestPlayer(t, 3, "Other", 20), "knights"); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("duplicate name: err = %v", err)
+	}
+	if _, err := m.Create(ctx, leader, "Another"); !errors.Is(err, ErrAlreadyInClan) {
+		t.Errorf("leader already in clan: err = %v", err)
+	}
+}
+
+func TestManager_InviteRequiresPrivilege(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	member := newTestPlayer(t, 2, "Member", 20)
+	guest := newTestPlayer(t, 3, "Guest", 20)
+
+	if _, err := m.Create(ctx, leader, "Knights"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	if err := m.Invite(member, guest, PledgeMain); !errors.Is(err, ErrNoPrivilege) {
+		t.Fatalf("invite without privilege: err = %v", err)
+	}
+	if err := m.SetRankPrivileges(ctx, leader, member.PowerGrade(), PrivJoinClan); err != nil {
+		t.Fatalf("SetRankPrivileges: %v", err)
+	}
+	if err := m.Invite(member, guest, PledgeMain); err != nil {
+		t.Errorf("invite with privilege: %v", err)
+	}
+}
+
+func TestManager_LeavePenalty(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	member := newTestPlayer(t, 2, "Member", 20)
+
+	if _, err := m.Create(ctx, leader, "Knights"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	if _, err := m.Leave(ctx, leader); !errors.Is(err, ErrLeaderCannotLeave) {
+		t.Errorf("leader leave: err = %v", err)
+	}
+	if _, err := m.Leave(ctx, member); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	if member.ClanID() != 0 || m.ClanOf(member) != nil {
+		t.Error("member should be out of the clan")
+	}
+
+	if err := m.Invite(leader, member, PledgeMain); !errors.Is(err, ErrJoinPenalty) {
+		t.Errorf("rejoin during penalty: err = %v", err)
+	}
+	*now = now.Add(DefaultConfig().JoinPenalty + time.Minute)
+	if err := m.Invite(leader, member, PledgeMain); err != nil {
+		t.Errorf("rejoin after penalty: %v", err)
+	}
+}
+
+func TestManager_ExpelPenalties(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	member := newTestPlayer(t, 2, "Member", 20)
+	guest := newTestPlayer(t, 3, "Guest", 20)
+
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	if _, _, err := m.Expel(ctx, member, "Leader"); !errors.Is(err, ErrNoPrivilege) {
+		t.Errorf("expel without privilege: err = %v", err)
+	}
+	_, expelled, err := m.Expel(ctx, leader, "member")
+	if err != nil {
+		t.Fatalf("Expel: %v", err)
+	}
+	if expelled.CharacterID != member.CharacterID() || c.MemberCount() != 1 {
+		t.Errorf("expelled=%v members=%d", expelled.Name, c.MemberCount())
+	}
+
+	// Клан не может принимать участников до окончания штрафа
+	if err := m.Invite(leader, guest, PledgeMain); !errors.Is(err, ErrAcceptPenalty) {
+		t.Errorf("invite during accept penalty: err = %v", err)
+	}
+	*now = now.Add(DefaultConfig().AcceptPenalty + time.Minute)
+	if err := m.Invite(leader, guest, PledgeMain); err != nil {
+		t.Errorf("invite after accept penalty: %v", err)
+	}
+}
+
+func TestManager_DissolvePenalty(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	member := newTestPlayer(t, 2, "Member", 20)
+
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	if _, err := m.Dissolve(ctx, member); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("dissolve by member: err = %v", err)
+	}
+	if _, err := m.Dissolve(ctx, leader); err != nil {
+		t.Fatalf("Dissolve: %v", err)
+	}
+	if err := m.Invite(leader, newTestPlayer(t, 3, "Guest", 20), PledgeMain); !errors.Is(err, ErrDissolving) {
+		t.Errorf("invite while dissolving: err = %v", err)
+	}
+
+	// До истечения задержки клан существует
+	if dissolved, _ := m.ProcessDissolutions(ctx); len(dissolved) != 0 {
+		t.Fatal("clan dissolved before delay expired")
+	}
+
+	*now = now.Add(DefaultConfig().DissolveDelay)
+	dissolved, err := m.ProcessDissolutions(ctx)
+	if err != nil || len(dissolved) != 1 || dissolved[0] != c {
+		t.Fatalf("ProcessDissolutions = %v, %v", dissolved, err)
+	}
+	if m.Clan(c.ID()) != nil || m.ClanOf(member) != nil || member.ClanID() != 0 {
+		t.Error("clan and memberships should be removed")
+	}
+
+	if _, err := m.Create(ctx, leader, "Reborn"); !errors.Is(err, ErrCreatePenalty) {
+		t.Errorf("create during penalty: err = %v", err)
+	}
+	*now = now.Add(DefaultConfig().CreatePenalty)
+	if _, err := m.Create(ctx, leader, "Reborn"); err != nil {
+		t.Errorf("create after penalty: %v", err)
+	}
+}
+
+func TestManager_SubPledges(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	student := newTestPlayer(t, 2, "Student", 30)
+	veteran := newTestPlayer(t, 3, "Veteran", 50)
+
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := m.CreateSubPledge(ctx, leader, PledgeAcademy, "School"); !errors.Is(err, ErrClanLevelTooLow) {
+		t.Errorf("academy at level 0: err = %v", err)
+	}
+	if err := m.Invite(leader, student, PledgeAcademy); !errors.Is(err, ErrNoSuchPledge) {
+		t.Errorf("invite to missing academy: err = %v", err)
+	}
+
+	rec := c.record()
+	rec.Level = 7
+	rec.Reputation = RoyalGuardCost
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+
+	if err := m.CreateSubPledge(ctx, leader, PledgeAcademy, "School"); err != nil {
+		t.Fatalf("CreateSubPledge academy: %v", err)
+	}
+	if err := m.CreateSubPledge(ctx, leader, PledgeOrderKnight1, "Blades"); !errors.Is(err, ErrNoSuchPledge) {
+		t.Errorf("knights without royal guard: err = %v", err)
+	}
+	if err := m.CreateSubPledge(ctx, leader, PledgeRoyalGuard1, "Guards"); err != nil {
+		t.Fatalf("CreateSubPledge royal guard: %v", err)
+	}
+	if c.Reputation() != 0 {
+		t.Errorf("reputation = %d, want 0 after paying for royal guard", c.Reputation())
+	}
+	if err := m.CreateSubPledge(ctx, leader, PledgeOrderKnight1, "Blades"); !errors.Is(err, ErrNotEnoughReputation) {
+		t.Errorf("knights without reputation: err = %v", err)
+	}
+
+	join(t, m, leader, student, PledgeAcademy)
+	if student.PledgeType() != PledgeAcademy || student.PowerGrade() != RankAcademy {
+		t.Errorf("academy member: pledge=%d grade=%d", student.PledgeType(), student.PowerGrade())
+	}
+	if err := m.Invite(leader, veteran, PledgeAcademy); !errors.Is(err, ErrAcademyLevel) {
+		t.Errorf("high level academy invite: err = %v", err)
+	}
+	join(t, m, leader, veteran, PledgeRoyalGuard1)
+	if veteran.PowerGrade() != 7 {
+		t.Errorf("royal guard grade = %d, want 7", veteran.PowerGrade())
+	}
+}
+
+func TestManager_PledgeFull(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	if _, err := m.Create(ctx, leader, "Knights"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Клан 0 уровня вмещает 10 участников, включая лидера
+	for i := range 9 {
+		join(t, m, leader, newTestPlayer(t, int64(10+i), "Member"+string(rune('A'+i)), 20), PledgeMain)
+	}
+	if err := m.Invite(leader, newTestPlayer(t, 99, "Extra", 20), PledgeMain); !errors.Is(err, ErrPledgeFull) {
+		t.Errorf("err = %v, want ErrPledgeFull", err)
+	}
+}
+
+func TestManager_SetPowerGrade(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	member := newTestPlayer(t, 2, "Member", 20)
+
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	join(t, m, leader, member, PledgeMain)
+
+	if _, err := m.SetPowerGrade(ctx, leader, "Member", RankLeader); !errors.Is(err, ErrInvalidRank) {
+		t.Errorf("grade 1: err = %v", err)
+	}
+	if _, err := m.SetPowerGrade(ctx, leader, "Member", 3); err != nil {
+		t.Fatalf("SetPowerGrade: %v", err)
+	}
+	if member.PowerGrade() != 3 || c.Member(member.CharacterID()).PowerGrade != 3 {
+		t.Error("power grade not applied")
+	}
+
+	if err := m.SetRankPrivileges(ctx, leader, 3, PrivDismiss|PrivJoinClan); err != nil {
+		t.Fatalf("SetRankPrivileges: %v", err)
+	}
+	if !c.HasPrivilege(member.CharacterID(), PrivDismiss) || c.HasPrivilege(member.CharacterID(), PrivManageRanks) {
+		t.Error("rank privileges not applied")
+	}
+	if !c.HasPrivilege(leader.CharacterID(), PrivAll) {
+		t.Error("leader must have all privileges")
+	}
+}
+
+func TestManager_AttachDetach(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, nil)
+	leader := newTestPlayer(t, 1, "Leader", 20)
+	c, err := m.Create(ctx, leader, "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	m.DetachPlayer(leader)
+	if len(c.OnlineMembers()) != 0 {
+		t.Fatal("detached leader should be offline")
+	}
+
+	// Новый экземпляр персонажа при повторном входе
+	again := newTestPlayer(t, 1, "Leader", 21)
+	if got := m.AttachPlayer(again); got != c {
+		t.Fatalf("AttachPlayer = %v, want clan", got)
+	}
+	if again.ClanID() != c.ID() || c.Leader().Level != 21 {
+		t.Error("attached player should get clan fields and refresh member level")
+	}
+}
+
+// snapshotRepo — repository, возвращающий заранее заданный snapshot.
+type snapshotRepo struct {
+	snap    *Snapshot
+	members []MemberRecord
+}
+
+func (r *snapshotRepo) LoadClans(context.Context) (*Snapshot, error)         { return r.snap, nil }
+func (r *snapshotRepo) CreateClan(context.Context, Record) (int32, error)    { return 100, nil }
+func (r *snapshotRepo) UpdateClan(context.Context, Record) error             { return nil }
+func (r *snapshotRepo) DeleteClan(context.Context, int32) error              { return nil }
+func (r *snapshotRepo) DeleteMember(context.Context, int64) error            { return nil }
+func (r *snapshotRepo) SaveSubPledge(context.Context, SubPledgeRecord) error { return nil }
+func (r *snapshotRepo) SaveRankPrivileges(context.Context, RankRecord) error { return nil }
+func (r *snapshotRepo) SaveCrest(context.Context, []byte) (int32, error)     { return 1, nil }
+func (r *snapshotRepo) DeleteCrest(context.Context, int32) error             { return nil }
+func (r *snapshotRepo) SavePenalty(context.Context, int64, Penalty) error    { return nil }
+func (r *snapshotRepo) SaveWar(context.Context, WarRecord) error             { return nil }
+func (r *snapshotRepo) DeleteWar(context.Context, WarRecord) error           { return nil }
+
+func (r *snapshotRepo) SaveMember(_ context.Context, m MemberRecord) error {
+	r.members = append(r.members, m)
+	return nil
+}
+
+func TestManager_Load(t *testing.T) {
+	ctx := context.Background()
+	joinBan := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &snapshotRepo{snap: &Snapshot{
+		Clans: []Record{{ID: 7, Name: "Knights", LeaderID: 1, Level: 6, Reputation: 500, CrestID: 3}},
+		Members: []MemberRecord{
+			{ClanID: 7, CharacterID: 1, Name: "Leader", Level: 60, PowerGrade: RankLeader},
+			{ClanID: 7, CharacterID: 2, Name: "Student", Level: 20, PledgeType: PledgeAcademy, PowerGrade: RankAcademy},
+		},
+		SubPledges: []SubPledgeRecord{{ClanID: 7, SubPledge: SubPledge{Type: PledgeAcademy, Name: "School"}}},
+		Ranks:      []RankRecord{{ClanID: 7, Rank: 5, Privileges: PrivJoinClan}},
+		Crests:     map[int32][]byte{3: {1, 2, 3}},
+		Penalties:  map[int64]Penalty{9: {JoinExpiry: joinBan}},
+	}}
+
+	m, _ := newTestManager(t, repo)
+	if err := m.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	c := m.ClanByName("knights")
+	if c == nil || c.Level() != 6 || c.MemberCount() != 2 || c.SubPledge(PledgeAcademy) == nil {
+		t.Fatalf("clan not restored: %+v", c)
+	}
+	if c.RankPrivileges(5) != PrivJoinClan || len(m.Crest(3)) != 3 {
+		t.Error("ranks or crests not restored")
+	}
+	if !m.Penalty(9).JoinExpiry.Equal(joinBan) {
+		t.Error("penalties not restored")
+	}
+
+	student := newTestPlayer(t, 2, "Student", 20)
+	if m.AttachPlayer(student) != c || student.PledgeType() != PledgeAcademy {
+		t.Error("AttachPlayer should restore pledge type")
+	}
+
+	// Новые участники сохраняются в repository
+	guest := newTestPlayer(t, 3, "Guest", 20)
+	join(t, m, newTestPlayer(t, 1, "Leader", 60), guest, PledgeMain)
+	if len(repo.members) != 1 || repo.members[0].ClanID != 7 {
+		t.Errorf("saved members = %+v", repo.members)
+	}
+}
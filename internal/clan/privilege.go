@@ -0,0 +1,38 @@
+package clan
+
+// Privilege — битовая маска привилегий ранга (значения совпадают с клиентом Interlude).
+type Privilege int32
+
+const (
+	PrivNone             Privilege = 0
+	PrivJoinClan         Privilege = 1 << 1
+	PrivGiveTitle        Privilege = 1 << 2
+	PrivViewWarehouse    Privilege = 1 << 3
+	PrivManageRanks      Privilege = 1 << 4
+	PrivPledgeWar        Privilege = 1 << 5
+	PrivDismiss          Privilege = 1 << 6
+	PrivRegisterCrest    Privilege = 1 << 7
+	PrivMasterRights     Privilege = 1 << 8
+	PrivManageLevels     Privilege = 1 << 9
+	PrivHallOpenDoor     Privilege = 1 << 10
+	PrivHallOtherRights  Privilege = 1 << 11
+	PrivHallAuction      Privilege = 1 << 12
+	PrivHallDismiss      Privilege = 1 << 13
+	PrivHallSetFunctions Privilege = 1 << 14
+	PrivCastleOpenDoor   Privilege = 1 << 15
+	PrivCastleManor      Privilege = 1 << 16
+	PrivCastleSiege      Privilege = 1 << 17
+	PrivCastleFunctions  Privilege = 1 << 18
+	PrivCastleDismiss    Privilege = 1 << 19
+	PrivCastleTaxes      Privilege = 1 << 20
+	PrivCastleMercenary  Privilege = 1 << 21
+	PrivCastleSetFuncs   Privilege = 1 << 22
+
+	// PrivAll — все привилегии (L2J CP_ALL).
+	PrivAll Privilege = 8388606
+)
+
+// Has returns true if all bits of priv are set.
+func (p Privilege) Has(priv Privilege) bool {
+	return p&priv == priv
+}
@@ -0,0 +1,88 @@
+package clan
+
+import (
+	"context"
+	"time"
+)
+
+// Record — сохраняемое состояние клана.
+type Record struct {
+	ID                int32
+	Name              string
+	LeaderID          int64
+	Level             int32
+	Reputation        int32
+	CrestID           int32
+	LargeCrestID      int32
+	DissolvingExpiry  time.Time
+	CharPenaltyExpiry time.Time
+	AllyID            int32
+	AllyName          string
+	AllyCrestID       int32
+	AllyPenaltyExpiry time.Time
+	AllyPenaltyType   AllyPenalty
+}
+
+// MemberRecord — сохраняемое членство персонажа в клане.
+type MemberRecord struct {
+	ClanID      int32
+	CharacterID int64
+	Name        string
+	Level       int32
+	ClassID     int32
+	PledgeType  int32
+	PowerGrade  int32
+}
+
+// SubPledgeRecord — сохраняемое подразделение клана.
+type SubPledgeRecord struct {
+	ClanID int32
+	SubPledge
+}
+
+// RankRecord — привилегии ранга клана.
+type RankRecord struct {
+	ClanID     int32
+	Rank       int32
+	Privileges Privilege
+}
+
+// WarRecord — объявленная кланом ClanID война клану EnemyID.
+type WarRecord struct {
+	ClanID  int32
+	EnemyID int32
+}
+
+// Penalty — штрафы персонажа после выхода из клана или роспуска.
+type Penalty struct {
+	JoinExpiry   time.Time // до этого времени нельзя вступить в клан
+	CreateExpiry time.Time // до этого времени нельзя создать клан
+}
+
+// Snapshot — все данные кланов, загружаемые при старте сервера.
+type Snapshot struct {
+	Clans      []Record
+	Members    []MemberRecord
+	SubPledges []SubPledgeRecord
+	Ranks      []RankRecord
+	Crests     map[int32][]byte
+	Penalties  map[int64]Penalty
+	Wars       []WarRecord
+}
+
+// Repository хранит кланы между рестартами сервера.
+type Repository interface {
+	LoadClans(ctx context.Context) (*Snapshot, error)
+	CreateClan(ctx context.Context, rec Record) (int32, error)
+	UpdateClan(ctx context.Context, rec Record) error
+	DeleteClan(ctx context.Context, clanID int32) error
+	SaveMember(ctx context.Context, m MemberRecord) error
+	DeleteMember(ctx context.Context, characterID int64) error
+	SaveSubPledge(ctx context.Context, s SubPledgeRecord) error
+	SaveRankPrivileges(ctx context.Context, r RankRecord) error
+	SaveCrest(ctx context.Context, data []byte) (int32, error)
+	DeleteCrest(ctx context.Context, crestID int32) error
+	SavePenalty(ctx context.Context, characterID int64, p Penalty) error
+	SaveWar(ctx context.Context, w WarRecord) error
+	DeleteWar(ctx context.Context, w WarRecord) error
+}
@@ -0,0 +1,208 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// WarMinClanLevel — минимальный уровень клана для участия в войне.
+const WarMinClanLevel = 3
+
+var (
+	ErrAtWar        = errors.New("clan is at war")
+	ErrAlreadyAtWar = errors.New("war is already declared")
+	ErrNotAtWar     = errors.New("clans are not at war")
+	ErrWarOwnSide   = errors.New("cannot declare war on own clan or alliance")
+)
+
+// DeclareWar объявляет войну клану enemyName (привилегия PrivPledgeWar).
+// Война становится взаимной, когда противник объявит войну в ответ (AcceptWar).
+func (m *Manager) DeclareWar(ctx context.Context, by *model.Player, enemyName string) (c, enemy *Clan, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, enemy, err = m.warPartiesLocked(by, enemyName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, side := range []*Clan{c, enemy} {
+		if side.Level() < WarMinClanLevel {
+			return nil, nil, ErrClanLevelTooLow
+		}
+		if side.MemberCount() < m.cfg.WarMinMembers {
+			return nil, nil, ErrNotEnoughMembers
+		}
+	}
+	if err := m.declareWarLocked(ctx, c, enemy); err != nil {
+		return nil, nil, err
+	}
+	return c, enemy, nil
+}
+
+// AcceptWar принимает объявленную кланом enemyName войну: клан by объявляет войну в ответ,
+// и война становится взаимной.
+func (m *Manager) AcceptWar(ctx context.Context, by *model.Player, enemyName string) (c, enemy *Clan, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, enemy, err = m.warPartiesLocked(by, enemyName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !c.IsAttackedBy(enemy.id) {
+		return nil, nil, ErrNotAtWar
+	}
+	if err := m.declareWarLocked(ctx, c, enemy); err != nil {
+		return nil, nil, err
+	}
+	return c, enemy, nil
+}
+
+// StopWar отзывает объявление войны клану enemyName (привилегия PrivPledgeWar).
+func (m *Manager) StopWar(ctx context.Context, by *model.Player, enemyName string) (c, enemy *Clan, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, enemy, err = m.warPartiesLocked(by, enemyName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !c.IsAtWarWith(enemy.id) {
+		return nil, nil, ErrNotAtWar
+	}
+	if err := m.deleteWarLocked(ctx, c, enemy); err != nil {
+		return nil, nil, err
+	}
+	return c, enemy, nil
+}
+
+// SurrenderWar капитулирует в войне с кланом enemyName (только лидер клана).
+// Война прекращается с обеих сторон, клан теряет Config.SurrenderReputation репутации.
+func (m *Manager) SurrenderWar(ctx context.Context, by *model.Player, enemyName string) (c, enemy *Clan, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err = m.leaderClanLocked(by)
+	if err != nil {
+		return nil, nil, err
+	}
+	enemy = m.byName[strings.ToLower(enemyName)]
+	if enemy == nil {
+		return nil, nil, ErrClanNotFound
+	}
+	if !c.IsAtWarWith(enemy.id) && !c.IsAttackedBy(enemy.id) {
+		return nil, nil, ErrNotAtWar
+	}
+
+	if c.IsAtWarWith(enemy.id) {
+		if err := m.deleteWarLocked(ctx, c, enemy); err != nil {
+			return nil, nil, err
+		}
+	}
+	if enemy.IsAtWarWith(c.id) {
+		if err := m.deleteWarLocked(ctx, enemy, c); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rec := c.record()
+	rec.Reputation = max(rec.Reputation-m.cfg.SurrenderReputation, 0)
+	if err := m.updateClanLocked(ctx, c, rec); err != nil {
+		return nil, nil, err
+	}
+	return c, enemy, nil
+}
+
+// AtMutualWar возвращает true если кланы игроков воюют друг с другом взаимно.
+// Такие игроки могут атаковать друг друга без получения кармы.
+// Участники академии в войнах не участвуют.
+func (m *Manager) AtMutualWar(a, b *model.Player) bool {
+	if a.PledgeType() == PledgeAcademy || b.PledgeType() == PledgeAcademy {
+		return false
+	}
+	m.mu.Lock()
+	ca, cb := m.byMember[a.CharacterID()], m.byMember[b.CharacterID()]
+	m.mu.Unlock()
+	if ca == nil || cb == nil || ca == cb {
+		return false
+	}
+	return ca.IsMutualWar(cb.id)
+}
+
+// warPartiesLocked возвращает клан by (с привилегией PrivPledgeWar) и клан противника.
+func (m *Manager) warPartiesLocked(by *model.Player, enemyName string) (c, enemy *Clan, err error) {
+	c = m.byMember[by.CharacterID()]
+	if c == nil {
+		return nil, nil, ErrNotInClan
+	}
+	if !c.HasPrivilege(by.CharacterID(), PrivPledgeWar) {
+		return nil, nil, ErrNoPrivilege
+	}
+	enemy = m.byName[strings.ToLower(enemyName)]
+	if enemy == nil {
+		return nil, nil, ErrClanNotFound
+	}
+	if enemy == c || (c.AllyID() != 0 && c.AllyID() == enemy.AllyID()) {
+		return nil, nil, ErrWarOwnSide
+	}
+	return c, enemy, nil
+}
+
+func (m *Manager) declareWarLocked(ctx context.Context, c, enemy *Clan) error {
+	if c.IsAtWarWith(enemy.id) {
+		return ErrAlreadyAtWar
+	}
+	if !c.DissolvingExpiry().IsZero() || !enemy.DissolvingExpiry().IsZero() {
+		return ErrDissolving
+	}
+	if m.repo != nil {
+		if err := m.repo.SaveWar(ctx, WarRecord{ClanID: c.id, EnemyID: enemy.id}); err != nil {
+			return fmt.Errorf("saving clan war: %w", err)
+		}
+	}
+	c.mu.Lock()
+	c.wars[enemy.id] = struct{}{}
+	c.mu.Unlock()
+	enemy.mu.Lock()
+	enemy.attackers[c.id] = struct{}{}
+	enemy.mu.Unlock()
+	return nil
+}
+
+func (m *Manager) deleteWarLocked(ctx context.Context, c, enemy *Clan) error {
+	if m.repo != nil {
+		if err := m.repo.DeleteWar(ctx, WarRecord{ClanID: c.id, EnemyID: enemy.id}); err != nil {
+			return fmt.Errorf("deleting clan war: %w", err)
+		}
+	}
+	c.mu.Lock()
+	delete(c.wars, enemy.id)
+	c.mu.Unlock()
+	enemy.mu.Lock()
+	delete(enemy.attackers, c.id)
+	enemy.mu.Unlock()
+	return nil
+}
+
+// dropWarsLocked убирает распущенный клан из списков войн остальных кланов.
+// Записи в repository удаляются вместе с кланом.
+func (m *Manager) dropWarsLocked(c *Clan) {
+	for _, id := range c.WarList() {
+		if enemy := m.clans[id]; enemy != nil {
+			enemy.mu.Lock()
+			delete(enemy.attackers, c.id)
+			enemy.mu.Unlock()
+		}
+	}
+	for _, id := range c.AttackerList() {
+		if enemy := m.clans[id]; enemy != nil {
+			enemy.mu.Lock()
+			delete(enemy.wars, c.id)
+			enemy.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package clan
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newWarManager создаёт менеджер с двумя кланами, готовыми к войне.
+func newWarManager(t *testing.T) (*Manager, *Clan, *Clan) {
+	t.Helper()
+	m, _ := newTestManager(t, nil)
+	m.cfg.WarMinMembers = 1
+	a := newLeveledClan(t, m, 1, "AlphaLeader", "Alpha", WarMinClanLevel)
+	b := newLeveledClan(t, m, 2, "BetaLeader", "Beta", WarMinClanLevel)
+	return m, a, b
+}
+
+func TestManager_DeclareAndAcceptWar(t *testing.T) {
+	ctx := context.Background()
+	m, a, b := newWarManager(t)
+	alpha, beta := a.Leader().Player(), b.Leader().Player()
+
+	if _, _, err := m.AcceptWar(ctx, beta, "Alpha"); !errors.Is(err, ErrNotAtWar) {
+		t.Errorf("accept without declaration: err = %v", err)
+	}
+	if _, _, err := m.DeclareWar(ctx, alpha, "Alpha"); !errors.Is(err, ErrWarOwnSide) {
+		t.Errorf("war on own clan: err = %v", err)
+	}
+	if _, _, err := m.DeclareWar(ctx, alpha, "beta"); err != nil {
+		t.Fatalf("DeclareWar: %v", err)
+	}
+	if !a.IsAtWarWith(b.ID()) || !b.IsAttackedBy(a.ID()) || a.IsMutualWar(b.ID()) {
+		t.Fatal("one-sided war state is wrong")
+	}
+	if m.AtMutualWar(alpha, beta) {
+		t.Error("one-sided war must not allow karma-free attacks")
+	}
+	if _, _, err := m.DeclareWar(ctx, alpha, "Beta"); !errors.Is(err, ErrAlreadyAtWar) {
+		t.Errorf("second declaration: err = %v", err)
+	}
+
+	if _, _, err := m.AcceptWar(ctx, beta, "Alpha"); err != nil {
+		t.Fatalf("AcceptWar: %v", err)
+	}
+	if !a.IsMutualWar(b.ID()) || !b.IsMutualWar(a.ID()) || !m.AtMutualWar(alpha, beta) {
+		t.Error("war should be mutual")
+	}
+
+	if _, err := m.Dissolve(ctx, alpha); !errors.Is(err, ErrAtWar) {
+		t.Errorf("dissolve at war: err = %v", err)
+	}
+}
+
+func TestManager_WarRequirements(t *testing.T) {
+	ctx := context.Background()
+	m, a, b := newWarManager(t)
+
+	m.cfg.WarMinMembers = 2
+	if _, _, err := m.DeclareWar(ctx, a.Leader().Player(), "Beta"); !errors.Is(err, ErrNotEnoughMembers) {
+		t.Errorf("too few members: err = %v", err)
+	}
+	m.cfg.WarMinMembers = 1
+
+	rec := b.record()
+	rec.Level = WarMinClanLevel - 1
+	if err := m.updateClanLocked(ctx, b, rec); err != nil {
+		t.Fatalf("updateClanLocked: %v", err)
+	}
+	if _, _, err := m.DeclareWar(ctx, a.Leader().Player(), "Beta"); !errors.Is(err, ErrClanLevelTooLow) {
+		t.Errorf("low level enemy: err = %v", err)
+	}
+
+	member := newTestPlayer(t, 10, "Soldier", 20)
+	join(t, m, a.Leader().Player(), member, PledgeMain)
+	if _, _, err := m.DeclareWar(ctx, member, "Beta"); !errors.Is(err, ErrNoPrivilege) {
+		t.Errorf("declare without privilege: err = %v", err)
+	}
+}
+
+func TestManager_SurrenderAndStopWar(t *testing.T) {
+	ctx := context.Background()
+	m, a, b := newWarManager(t)
+	alpha, beta := a.Leader().Player(), b.Leader().Player()
+
+	if _, _, err := m.DeclareWar(ctx, alpha, "Beta"); err != nil {
+		t.Fatalf("DeclareWar: %v", err)
+	}
+	if _, _, err := m.StopWar(ctx, alpha, "Beta"); err != nil {
+		t.Fatalf("StopWar: %v", err)
+	}
+	if a.HasWars() || b.HasWars() {
+		t.Fatal("war should be stopped")
+	}
+
+	if _, _, err := m.DeclareWar(ctx, alpha, "Beta"); err != nil {
+		t.Fatalf("DeclareWar: %v", err)
+	}
+	if _, _, err := m.AcceptWar(ctx, beta, "Alpha"); err != nil {
+		t.Fatalf("AcceptWar: %v", err)
+	}
+	if _, err := m.AddReputation(ctx, b, 800); err != nil {
+		t.Fatalf("AddReputation: %v", err)
+	}
+
+	if _, _, err := m.SurrenderWar(ctx, beta, "Alpha"); err != nil {
+		t.Fatalf("SurrenderWar: %v", err)
+	}
+	if a.HasWars() || b.HasWars() {
+		t.Error("surrender should end the war on both sides")
+	}
+	if b.Reputation() != 800-m.cfg.SurrenderReputation {
+		t.Errorf("reputation after surrender = %d", b.Reputation())
+	}
+}
+
+func TestManager_LoadWars(t *testing.T) {
+	repo := &snapshotRepo{snap: &Snapshot{
+		Clans: []Record{
+			{ID: 1, Name: "Alpha", LeaderID: 1, Level: 5, AllyID: 1, AllyName: "Union"},
+			{ID: 2, Name: "Beta", LeaderID: 2, Level: 5},
+		},
+		Wars: []WarRecord{{ClanID: 1, EnemyID: 2}, {ClanID: 2, EnemyID: 1}},
+	}}
+	m, _ := newTestManager(t, repo)
+	if err := m.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a, b := m.Clan(1), m.Clan(2)
+	if !a.IsMutualWar(2) || !b.IsMutualWar(1) {
+		t.Error("wars were not restored")
+	}
+	if !a.IsAllyLeader() || a.AllyName() != "Union" {
+		t.Error("alliance was not restored")
+	}
+}
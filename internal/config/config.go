@@ -29,13 +29,16 @@ type LoginServer struct {
 	ShowLicence        bool `yaml:"show_licence"`
 	LoginTryBeforeBan  int  `yaml:"login_try_before_ban"`
 	LoginBlockAfterBan int  `yaml:"login_block_after_ban"` // seconds
+	LoginLockAccounts  bool `yaml:"login_lock_accounts"`   // also block accounts reaching login_try_before_ban
+
+	// Password hashing (argon2id); accounts with older hashes are rehashed on login
+	PasswordHashMemory      int `yaml:"password_hash_memory"`      // KiB
+	PasswordHashIterations  int `yaml:"password_hash_iterations"`  // passes over the memory
+	PasswordHashParallelism int `yaml:"password_hash_parallelism"` // threads per hash
+	PasswordHashWorkers     int `yaml:"password_hash_workers"`     // hashes computed at once; 0 = one per CPU
 
 	// Flood protection
-	FloodProtection     bool `yaml:"flood_protection"`
-	FastConnectionLimit int  `yaml:"fast_connection_limit"`
-	NormalConnectionTime int  `yaml:"normal_connection_time"` // ms
-	FastConnectionTime  int  `yaml:"fast_connection_time"`   // ms
-	MaxConnectionPerIP  int  `yaml:"max_connection_per_ip"`
+	FloodProtect `yaml:",inline"`
 
 	// Game servers (static list for Phase 2)
 	GameServers []GameServerEntry `yaml:"game_servers"`
@@ -90,6 +93,27 @@ func (d DatabaseConfig) DSN() string {
 	return base
 }
 
+// FloodProtect holds the connection flood limits shared by the login server,
+// the game server and the game server listener.
+type FloodProtect struct {
+	FloodProtection      bool `yaml:"flood_protection"`
+	FastConnectionLimit  int  `yaml:"fast_connection_limit"`
+	NormalConnectionTime int  `yaml:"normal_connection_time"` // ms
+	FastConnectionTime   int  `yaml:"fast_connection_time"`   // ms
+	MaxConnectionPerIP   int  `yaml:"max_connection_per_ip"`
+}
+
+// DefaultFloodProtect returns the L2J flood protection defaults.
+func DefaultFloodProtect() FloodProtect {
+	return FloodProtect{
+		FloodProtection:      true,
+		FastConnectionLimit:  15,
+		NormalConnectionTime: 700,
+		FastConnectionTime:   350,
+		MaxConnectionPerIP:   50,
+	}
+}
+
 // GameServerEntry represents a known game server in the config.
 type GameServerEntry struct {
 	ID   int    `yaml:"id"`
@@ -110,11 +134,10 @@ func DefaultLoginServer() LoginServer {
 		ShowLicence:         true,
 		LoginTryBeforeBan:   5,
 		LoginBlockAfterBan:  900,
-		FloodProtection:     true,
-		FastConnectionLimit: 15,
-		NormalConnectionTime: 700,
-		FastConnectionTime:  350,
-		MaxConnectionPerIP:  50,
+		PasswordHashMemory:      64 * 1024,
+		PasswordHashIterations:  2,
+		PasswordHashParallelism: 2,
+		FloodProtect:        DefaultFloodProtect(),
 		Database: DatabaseConfig{
 			Host:    "127.0.0.1",
 			Port:    5432,
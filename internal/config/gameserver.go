@@ -25,11 +25,70 @@ type GameServer struct {
 	Database DatabaseConfig `yaml:"database"`
 
 	// Flood protection
-	FloodProtection     bool `yaml:"flood_protection"`
-	FastConnectionLimit int  `yaml:"fast_connection_limit"`
-	NormalConnectionTime int  `yaml:"normal_connection_time"` // ms
-	FastConnectionTime  int  `yaml:"fast_connection_time"`   // ms
-	MaxConnectionPerIP  int  `yaml:"max_connection_per_ip"`
+	FloodProtect `yaml:",inline"`
+
+	// Packet rate limits per opcode and per client, reloaded when the file changes
+	RateLimitsFile string `yaml:"rate_limits_file"`
+
+	// Private stores
+	MaxPvtStoreSlots   int  `yaml:"max_pvt_store_slots"`
+	OfflineTradeEnable bool `yaml:"offline_trade_enable"` // магазин остаётся после выхода клиента
+	RestoreOffliners   bool `yaml:"restore_offliners"`    // восстанавливать offline-магазины после рестарта
+
+	// Scripts
+	ScriptsDir      string `yaml:"scripts_dir"`       // "" disables scripting
+	ScriptStepLimit int    `yaml:"script_step_limit"` // steps per hook call
+	ScriptTimeLimit int    `yaml:"script_time_limit"` // ms per hook call
+
+	// Geodata
+	GeoDataDir          string `yaml:"geodata_dir"`           // "" disables geodata
+	PathfindingMaxNodes int    `yaml:"pathfinding_max_nodes"` // cells expanded per path search
+	PathfindingTimeout  int    `yaml:"pathfinding_timeout"`   // ms per path search
+
+	// NPC AI
+	AIShards int `yaml:"ai_shards"` // AI tick workers; 0 = one per CPU
+
+	// NPC movement
+	RoutesFile       string `yaml:"routes_file"`        // patrol routes referenced by spawns.route
+	RandomWalkRadius int    `yaml:"random_walk_radius"` // 0 disables random walk
+	RandomWalkRate   int    `yaml:"random_walk_rate"`   // random walk starts on 1 of N idle AI ticks
+
+	// Spawn territories (polygons referenced by spawns.territory), imported into the database at startup
+	TerritoriesFile string `yaml:"territories_file"`
+
+	// World zones (peace, PvP, towns, swamps...)
+	ZonesFile      string `yaml:"zones_file"`
+	MapRegionsFile string `yaml:"map_regions_file"` // town players return to from each part of the world
+
+	// Gatekeepers
+	TeleportsFile           string `yaml:"teleports_file"`
+	TeleportFreeLevel       int    `yaml:"teleport_free_level"`       // players up to this level teleport for free; 0 = nobody
+	TeleportWeekendDiscount bool   `yaml:"teleport_weekend_discount"` // half price on weekend evenings
+
+	// Death
+	DeathDelevel bool `yaml:"death_delevel"` // the death exp penalty may take a level away
+
+	// PvP and karma
+	PvPNormalTime            int     `yaml:"pvp_normal_time"`             // ms a player stays flagged after attacking a peaceful player
+	PvPPvPTime               int     `yaml:"pvp_pvp_time"`                // ms a player stays flagged after attacking a flagged player
+	KarmaMinKarma            int     `yaml:"karma_min_karma"`             // karma for the first PK
+	KarmaMaxKarma            int     `yaml:"karma_max_karma"`             // most karma a single PK gives
+	KarmaXPDivider           int     `yaml:"karma_xp_divider"`            // experience that works off one point of karma
+	KarmaLostBase            int     `yaml:"karma_lost_base"`             // karma worked off by any experience gain at least
+	KarmaAwardPKKill         bool    `yaml:"karma_award_pk_kill"`         // killing a karma player counts as PvP
+	KarmaExpLostRate         float64 `yaml:"karma_exp_lost_rate"`         // death exp penalty multiplier of karma players
+	// Karma item drop: not applied until items can lie on the ground
+	KarmaPKLimit             int     `yaml:"karma_pk_limit"`              // PK count from which karma players drop items
+	KarmaDropChance          int     `yaml:"karma_drop_chance"`           // percent: a karma player drops anything at death
+	KarmaItemDropChance      int     `yaml:"karma_item_drop_chance"`      // percent per inventory item
+	KarmaEquipmentDropChance int     `yaml:"karma_equipment_drop_chance"` // percent per equipped item
+	KarmaWeaponDropChance    int     `yaml:"karma_weapon_drop_chance"`    // percent for the equipped weapon
+	KarmaDropLimit           int     `yaml:"karma_drop_limit"`            // most items dropped at one death
+	KarmaNonDroppable        []int   `yaml:"karma_non_droppable"`         // item IDs karma players never drop
+
+	// GM commands
+	AccessLevelsFile string `yaml:"access_levels_file"` // GM access levels and the commands they allow
+	GMAuditLogFile   string `yaml:"gm_audit_log_file"`  // every GM action is appended here
 }
 
 // DefaultGameServer returns GameServer config with sensible defaults.
@@ -41,11 +100,42 @@ func DefaultGameServer() GameServer {
 		LoginPort:           9013,
 		ServerID:            1,
 		HexID:               "c0a80001", // 192.168.0.1
-		FloodProtection:     true,
-		FastConnectionLimit: 15,
-		NormalConnectionTime: 700,
-		FastConnectionTime:  350,
-		MaxConnectionPerIP:  50,
+		FloodProtect:        DefaultFloodProtect(),
+		RateLimitsFile:      "config/rate_limits.yaml",
+		MaxPvtStoreSlots:    4,
+		OfflineTradeEnable:  false,
+		RestoreOffliners:    false,
+		ScriptsDir:          "data/scripts",
+		ScriptStepLimit:     100_000,
+		ScriptTimeLimit:     50,
+		GeoDataDir:          "data/geodata",
+		PathfindingMaxNodes: 20_000,
+		PathfindingTimeout:  20,
+		RoutesFile:          "data/routes.yaml",
+		RandomWalkRadius:    300,
+		RandomWalkRate:      30,
+		TerritoriesFile:     "data/territories.yaml",
+		ZonesFile:           "data/zones.yaml",
+		MapRegionsFile:      "data/mapregions.yaml",
+		TeleportsFile:       "data/teleports.yaml",
+		TeleportWeekendDiscount: true,
+		DeathDelevel:            true,
+		PvPNormalTime:            120_000,
+		PvPPvPTime:               60_000,
+		KarmaMinKarma:            240,
+		KarmaMaxKarma:            10_000,
+		KarmaXPDivider:           260,
+		KarmaAwardPKKill:         true,
+		KarmaExpLostRate:         1,
+		KarmaPKLimit:             5,
+		KarmaDropChance:          40,
+		KarmaItemDropChance:      50,
+		KarmaEquipmentDropChance: 40,
+		KarmaWeaponDropChance:    10,
+		KarmaDropLimit:           10,
+		KarmaNonDroppable:        []int{57}, // Adena
+		AccessLevelsFile:         "config/access_levels.yaml",
+		GMAuditLogFile:           "log/gmaudit.log",
 		Database: DatabaseConfig{
 			Host:     "127.0.0.1",
 			Port:     5432,
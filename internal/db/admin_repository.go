@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/udisondev/la2go/internal/punishment"
+)
+
+// Querier — общие методы *pgxpool.Pool и pgx.Tx.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var (
+	// ErrNotFound — изменяемой записи (аккаунта, персонажа, сервера) нет.
+	ErrNotFound = errors.New("not found")
+	// ErrExists — запись с таким ключом уже есть.
+	ErrExists = errors.New("already exists")
+)
+
+// uniqueViolation — код ошибки PostgreSQL при нарушении UNIQUE/PRIMARY KEY.
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// CharacterSummary — персонаж в списке администратора.
+type CharacterSummary struct {
+	ID        int64      `json:"id"`
+	AccountID int64      `json:"account_id"`
+	Name      string     `json:"name"`
+	Level     int32      `json:"level"`
+	ClassID   int32      `json:"class_id"`
+	X         int32      `json:"x"`
+	Y         int32      `json:"y"`
+	Z         int32      `json:"z"`
+	LastLogin *time.Time `json:"last_login"`
+}
+
+// AdminRepository — правка аккаунтов, персонажей и игровых серверов
+// вне игры (la2go-admin). Работает и в транзакции, и напрямую с пулом.
+type AdminRepository struct {
+	db Querier
+}
+
+// NewAdminRepository создаёт новый AdminRepository.
+func NewAdminRepository(db Querier) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// affected возвращает ErrNotFound, если команда не изменила ни одной строки.
+func affected(tag pgconn.CommandTag, what string) error {
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", what, ErrNotFound)
+	}
+	return nil
+}
+
+// CreateAccount создаёт аккаунт с уровнем доступа accessLevel.
+func (r *AdminRepository) CreateAccount(ctx context.Context, login, passwordHash string, accessLevel int32) error {
+	login = strings.ToLower(login)
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO accounts (login, password, last_active, access_level)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (login) DO NOTHING`,
+		login, passwordHash, time.Now(), accessLevel,
+	)
+	if err != nil {
+		return fmt.Errorf("creating account %q: %w", login, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("account %q: %w", login, ErrExists)
+	}
+	return nil
+}
+
+// SetPassword заменяет хеш пароля аккаунта.
+func (r *AdminRepository) SetPassword(ctx context.Context, login, passwordHash string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE accounts SET password = $1 WHERE login = $2`,
+		passwordHash, strings.ToLower(login),
+	)
+	if err != nil {
+		return fmt.Errorf("updating password for %q: %w", login, err)
+	}
+	return affected(tag, "account "+login)
+}
+
+// SetAccessLevel меняет уровень доступа аккаунта (больше 0 — GM, меньше 0 — бан).
+func (r *AdminRepository) SetAccessLevel(ctx context.Context, login string, level int32) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE accounts SET access_level = $1 WHERE login = $2`,
+		level, strings.ToLower(login),
+	)
+	if err != nil {
+		return fmt.Errorf("updating access level for %q: %w", login, err)
+	}
+	return affected(tag, "account "+login)
+}
+
+// RemovePunishments снимает все наказания типа t с ключа key; возвращает их число.
+func (r *AdminRepository) RemovePunishments(ctx context.Context, affect punishment.Affect, key string, t punishment.Type) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM punishments WHERE affect = $1 AND key = $2 AND type = $3`,
+		affect.String(), punishment.NormalizeKey(affect, key), t.String(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("deleting punishments of %s %s: %w", affect, key, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Characters возвращает персонажей аккаунта по его логину.
+func (r *AdminRepository) Characters(ctx context.Context, login string) ([]CharacterSummary, error) {
+	login = strings.ToLower(login)
+	var accountID int64
+	err := r.db.QueryRow(ctx, `SELECT account_id FROM accounts WHERE login = $1`, login).Scan(&accountID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("account %s: %w", login, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying account %q: %w", login, err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT character_id, account_id, name, level, class_id, x, y, z, last_login
+		FROM characters
+		WHERE account_id = $1
+		ORDER BY created_at ASC
+	`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("querying characters for account %q: %w", login, err)
+	}
+	defer rows.Close()
+
+	list := make([]CharacterSummary, 0, 8)
+	for rows.Next() {
+		var c CharacterSummary
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.Name, &c.Level, &c.ClassID, &c.X, &c.Y, &c.Z, &c.LastLogin); err != nil {
+			return nil, fmt.Errorf("scanning character row: %w", err)
+		}
+		list = append(list, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating character rows: %w", err)
+	}
+	return list, nil
+}
+
+// RenameCharacter переименовывает персонажа (имя без учёта регистра).
+func (r *AdminRepository) RenameCharacter(ctx context.Context, name, newName string) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE characters SET name = $1 WHERE LOWER(name) = LOWER($2)`,
+		newName, name,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("character %q: %w", newName, ErrExists)
+		}
+		return fmt.Errorf("renaming character %q: %w", name, err)
+	}
+	return affected(tag, "character "+name)
+}
+
+// MoveCharacter переносит персонажа в точку x, y, z.
+func (r *AdminRepository) MoveCharacter(ctx context.Context, name string, x, y, z int32) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE characters SET x = $1, y = $2, z = $3 WHERE LOWER(name) = LOWER($4)`,
+		x, y, z, name,
+	)
+	if err != nil {
+		return fmt.Errorf("moving character %q: %w", name, err)
+	}
+	return affected(tag, "character "+name)
+}
+
+// RegisterGameServer регистрирует игровой сервер с hexid.
+func (r *AdminRepository) RegisterGameServer(ctx context.Context, id int, hexID, host string) error {
+	var hostArg *string
+	if host != "" {
+		hostArg = &host
+	}
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO gameservers (server_id, hexid, host) VALUES ($1, $2, $3)`,
+		id, strings.ToLower(hexID), hostArg,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("game server %d or hexid %s: %w", id, hexID, ErrExists)
+		}
+		return fmt.Errorf("registering game server %d: %w", id, err)
+	}
+	return nil
+}
+
+// UnregisterGameServer удаляет регистрацию игрового сервера.
+func (r *AdminRepository) UnregisterGameServer(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM gameservers WHERE server_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("unregistering game server %d: %w", id, err)
+	}
+	return affected(tag, fmt.Sprintf("game server %d", id))
+}
@@ -27,7 +27,7 @@ func (r *CharacterRepository) LoadByID(ctx context.Context, characterID int64) (
 		SELECT character_id, account_id, name, level, race_id, class_id,
 		       x, y, z, heading,
 		       current_hp, max_hp, current_mp, max_mp, current_cp, max_cp,
-		       experience, created_at, last_login
+		       experience, sp, karma, pvp_kills, pk_kills, created_at, last_login
 		FROM characters
 		WHERE character_id = $1
 	`
@@ -49,6 +49,8 @@ func (r *CharacterRepository) LoadByID(ctx context.Context, characterID int64) (
 	var currentCP int32
 	var maxCP int32
 	var experience int64
+	var sp int64
+	var karma, pvpKills, pkKills int32
 	var createdAt time.Time
 	var lastLogin *time.Time // nullable
 
@@ -56,7 +58,7 @@ func (r *CharacterRepository) LoadByID(ctx context.Context, characterID int64) (
 		&characterIDDB, &accountIDDB, &name, &level, &raceID, &classID,
 		&x, &y, &z, &heading,
 		&currentHP, &maxHP, &currentMP, &maxMP, &currentCP, &maxCP,
-		&experience, &createdAt, &lastLogin,
+		&experience, &sp, &karma, &pvpKills, &pkKills, &createdAt, &lastLogin,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -86,6 +88,12 @@ func (r *CharacterRepository) LoadByID(ctx context.Context, characterID int64) (
 
 	// Устанавливаем Experience
 	player.SetExperience(experience)
+	player.SetSP(sp)
+
+	// Устанавливаем PvP
+	player.SetKarma(karma)
+	player.SetPvPKills(pvpKills)
+	player.SetPKKills(pkKills)
 
 	// Устанавливаем timestamps
 	player.SetCreatedAt(createdAt)
@@ -102,7 +110,7 @@ func (r *CharacterRepository) LoadByAccountID(ctx context.Context, accountID int
 		SELECT character_id, account_id, name, level, race_id, class_id,
 		       x, y, z, heading,
 		       current_hp, max_hp, current_mp, max_mp, current_cp, max_cp,
-		       experience, created_at, last_login
+		       experience, sp, karma, pvp_kills, pk_kills, created_at, last_login
 		FROM characters
 		WHERE account_id = $1
 		ORDER BY created_at ASC
@@ -136,6 +144,8 @@ func (r *CharacterRepository) LoadByAccountID(ctx context.Context, accountID int
 		var currentCP int32
 		var maxCP int32
 		var experience int64
+		var sp int64
+		var karma, pvpKills, pkKills int32
 		var createdAt time.Time
 		var lastLogin *time.Time // nullable
 
@@ -143,7 +153,7 @@ func (r *CharacterRepository) LoadByAccountID(ctx context.Context, accountID int
 			&characterIDDB, &accountIDDB, &name, &level, &raceID, &classID,
 			&x, &y, &z, &heading,
 			&currentHP, &maxHP, &currentMP, &maxMP, &currentCP, &maxCP,
-			&experience, &createdAt, &lastLogin,
+			&experience, &sp, &karma, &pvpKills, &pkKills, &createdAt, &lastLogin,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning character row: %w", err)
@@ -169,6 +179,12 @@ func (r *CharacterRepository) LoadByAccountID(ctx context.Context, accountID int
 
 		// Устанавливаем Experience
 		player.SetExperience(experience)
+		player.SetSP(sp)
+
+		// Устанавливаем PvP
+		player.SetKarma(karma)
+		player.SetPvPKills(pvpKills)
+		player.SetPKKills(pkKills)
 
 		// Устанавливаем timestamps
 		player.SetCreatedAt(createdAt)
@@ -193,8 +209,8 @@ func (r *CharacterRepository) Create(ctx context.Context, p *model.Player) error
 			account_id, name, level, race_id, class_id,
 			x, y, z, heading,
 			current_hp, max_hp, current_mp, max_mp, current_cp, max_cp,
-			experience
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			experience, sp, karma, pvp_kills, pk_kills
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		RETURNING character_id, created_at
 	`
 
@@ -207,7 +223,7 @@ func (r *CharacterRepository) Create(ctx context.Context, p *model.Player) error
 		p.AccountID(), p.Name(), p.Level(), p.RaceID(), p.ClassID(),
 		loc.X, loc.Y, loc.Z, loc.Heading,
 		p.CurrentHP(), p.MaxHP(), p.CurrentMP(), p.MaxMP(), p.CurrentCP(), p.MaxCP(),
-		p.Experience(),
+		p.Experience(), p.SP(), p.Karma(), p.PvPKills(), p.PKKills(),
 	).Scan(&characterID, &createdAt)
 
 	if err != nil {
@@ -227,7 +243,8 @@ func (r *CharacterRepository) Update(ctx context.Context, p *model.Player) error
 		UPDATE characters
 		SET level = $2, x = $3, y = $4, z = $5, heading = $6,
 		    current_hp = $7, max_hp = $8, current_mp = $9, max_mp = $10,
-		    current_cp = $11, max_cp = $12, experience = $13, last_login = $14
+		    current_cp = $11, max_cp = $12, experience = $13, last_login = $14, sp = $15,
+		    karma = $16, pvp_kills = $17, pk_kills = $18
 		WHERE character_id = $1
 	`
 
@@ -243,7 +260,8 @@ func (r *CharacterRepository) Update(ctx context.Context, p *model.Player) error
 		p.CharacterID(), p.Level(),
 		loc.X, loc.Y, loc.Z, loc.Heading,
 		p.CurrentHP(), p.MaxHP(), p.CurrentMP(), p.MaxMP(),
-		p.CurrentCP(), p.MaxCP(), p.Experience(), lastLogin,
+		p.CurrentCP(), p.MaxCP(), p.Experience(), lastLogin, p.SP(),
+		p.Karma(), p.PvPKills(), p.PKKills(),
 	)
 
 	if err != nil {
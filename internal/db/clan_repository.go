@@ -0,0 +1,410 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/udisondev/la2go/internal/clan"
+)
+
+// ClanRepository хранит кланы, участников, подразделения, привилегии рангов,
+// эмблемы, альянсы, войны и клановые штрафы персонажей.
+type ClanRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewClanRepository создаёт новый ClanRepository.
+func NewClanRepository(db *pgxpool.Pool) *ClanRepository {
+	return &ClanRepository{db: db}
+}
+
+// nullTime конвертирует нулевое время в NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// timeOrZero конвертирует NULL в нулевое время.
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// CreateClan создаёт клан и возвращает его ID.
+func (r *ClanRepository) CreateClan(ctx context.Context, rec clan.Record) (int32, error) {
+	var id int32
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO clans (name, leader_id, level, reputation)
+		VALUES ($1, $2, $3, $4)
+		RETURNING clan_id
+	`, rec.Name, rec.LeaderID, rec.Level, rec.Reputation).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting clan %q: %w", rec.Name, err)
+	}
+	return id, nil
+}
+
+// UpdateClan сохраняет изменяемые поля клана.
+func (r *ClanRepository) UpdateClan(ctx context.Context, rec clan.Record) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE clans
+		SET leader_id = $2, level = $3, reputation = $4,
+		    crest_id = NULLIF($5, 0), large_crest_id = NULLIF($6, 0),
+		    dissolving_expiry = $7, char_penalty_expiry = $8,
+		    ally_id = $9, ally_name = $10, ally_crest_id = NULLIF($11, 0),
+		    ally_penalty_expiry = $12, ally_penalty_type = $13
+		WHERE clan_id = $1
+	`, rec.ID, rec.LeaderID, rec.Level, rec.Reputation, rec.CrestID, rec.LargeCrestID,
+		nullTime(rec.DissolvingExpiry), nullTime(rec.CharPenaltyExpiry),
+		rec.AllyID, rec.AllyName, rec.AllyCrestID,
+		nullTime(rec.AllyPenaltyExpiry), int32(rec.AllyPenaltyType))
+	if err != nil {
+		return fmt.Errorf("updating clan %d: %w", rec.ID, err)
+	}
+	return nil
+}
+
+// DeleteClan удаляет клан (участники, подразделения и привилегии удаляются каскадом).
+func (r *ClanRepository) DeleteClan(ctx context.Context, clanID int32) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	var crestID, largeCrestID *int32
+	err = tx.QueryRow(ctx, `
+		DELETE FROM clans WHERE clan_id = $1 RETURNING crest_id, large_crest_id
+	`, clanID).Scan(&crestID, &largeCrestID)
+	if err != nil {
+		return fmt.Errorf("deleting clan %d: %w", clanID, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM clan_crests WHERE crest_id = ANY($1)`,
+		[]*int32{crestID, largeCrestID}); err != nil {
+		return fmt.Errorf("deleting crests of clan %d: %w", clanID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing clan %d deletion: %w", clanID, err)
+	}
+	return nil
+}
+
+// SaveMember сохраняет (перезаписывает) членство персонажа.
+func (r *ClanRepository) SaveMember(ctx context.Context, m clan.MemberRecord) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO clan_members (character_id, clan_id, pledge_type, power_grade)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (character_id) DO UPDATE
+		SET clan_id = EXCLUDED.clan_id, pledge_type = EXCLUDED.pledge_type, power_grade = EXCLUDED.power_grade
+	`, m.CharacterID, m.ClanID, m.PledgeType, m.PowerGrade)
+	if err != nil {
+		return fmt.Errorf("saving clan member %d: %w", m.CharacterID, err)
+	}
+	return nil
+}
+
+// DeleteMember удаляет членство персонажа.
+func (r *ClanRepository) DeleteMember(ctx context.Context, characterID int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM clan_members WHERE character_id = $1`, characterID)
+	if err != nil {
+		return fmt.Errorf("deleting clan member %d: %w", characterID, err)
+	}
+	return nil
+}
+
+// SaveSubPledge сохраняет подразделение клана.
+func (r *ClanRepository) SaveSubPledge(ctx context.Context, s clan.SubPledgeRecord) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO clan_subpledges (clan_id, pledge_type, name, leader_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (clan_id, pledge_type) DO UPDATE
+		SET name = EXCLUDED.name, leader_id = EXCLUDED.leader_id
+	`, s.ClanID, s.Type, s.Name, s.LeaderID)
+	if err != nil {
+		return fmt.Errorf("saving sub-pledge %d of clan %d: %w", s.Type, s.ClanID, err)
+	}
+	return nil
+}
+
+// SaveRankPrivileges сохраняет привилегии ранга.
+func (r *ClanRepository) SaveRankPrivileges(ctx context.Context, rr clan.RankRecord) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO clan_privileges (clan_id, rank, privileges)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (clan_id, rank) DO UPDATE SET privileges = EXCLUDED.privileges
+	`, rr.ClanID, rr.Rank, int32(rr.Privileges))
+	if err != nil {
+		return fmt.Errorf("saving privileges of rank %d, clan %d: %w", rr.Rank, rr.ClanID, err)
+	}
+	return nil
+}
+
+// SaveCrest сохраняет эмблему и возвращает её ID.
+func (r *ClanRepository) SaveCrest(ctx context.Context, data []byte) (int32, error) {
+	var id int32
+	if err := r.db.QueryRow(ctx, `INSERT INTO clan_crests (data) VALUES ($1) RETURNING crest_id`, data).Scan(&id); err != nil {
+		return 0, fmt.Errorf("inserting crest: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteCrest удаляет эмблему.
+func (r *ClanRepository) DeleteCrest(ctx context.Context, crestID int32) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM clan_crests WHERE crest_id = $1`, crestID); err != nil {
+		return fmt.Errorf("deleting crest %d: %w", crestID, err)
+	}
+	return nil
+}
+
+// SavePenalty сохраняет клановые штрафы персонажа.
+func (r *ClanRepository) SavePenalty(ctx context.Context, characterID int64, p clan.Penalty) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO clan_penalties (character_id, join_expiry, create_expiry)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (character_id) DO UPDATE
+		SET join_expiry = EXCLUDED.join_expiry, create_expiry = EXCLUDED.create_expiry
+	`, characterID, nullTime(p.JoinExpiry), nullTime(p.CreateExpiry))
+	if err != nil {
+		return fmt.Errorf("saving clan penalty %d: %w", characterID, err)
+	}
+	return nil
+}
+
+// SaveWar сохраняет объявление войны.
+func (r *ClanRepository) SaveWar(ctx context.Context, w clan.WarRecord) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO clan_wars (clan_id, enemy_clan_id)
+		VALUES ($1, $2)
+		ON CONFLICT (clan_id, enemy_clan_id) DO NOTHING
+	`, w.ClanID, w.EnemyID)
+	if err != nil {
+		return fmt.Errorf("saving war %d -> %d: %w", w.ClanID, w.EnemyID, err)
+	}
+	return nil
+}
+
+// DeleteWar удаляет объявление войны.
+func (r *ClanRepository) DeleteWar(ctx context.Context, w clan.WarRecord) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM clan_wars WHERE clan_id = $1 AND enemy_clan_id = $2
+	`, w.ClanID, w.EnemyID)
+	if err != nil {
+		return fmt.Errorf("deleting war %d -> %d: %w", w.ClanID, w.EnemyID, err)
+	}
+	return nil
+}
+
+// LoadClans загружает все кланы.
+// Имя, уровень и класс участников берутся из таблицы characters.
+func (r *ClanRepository) LoadClans(ctx context.Context) (*clan.Snapshot, error) {
+	snap := &clan.Snapshot{
+		Crests:    make(map[int32][]byte),
+		Penalties: make(map[int64]clan.Penalty),
+	}
+
+	if err := r.loadClans(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadMembers(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadSubPledges(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadRanks(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadCrests(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadPenalties(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := r.loadWars(ctx, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+func (r *ClanRepository) loadClans(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT clan_id, name, leader_id, level, reputation,
+		       COALESCE(crest_id, 0), COALESCE(large_crest_id, 0),
+		       dissolving_expiry, char_penalty_expiry,
+		       ally_id, ally_name, COALESCE(ally_crest_id, 0),
+		       ally_penalty_expiry, ally_penalty_type
+		FROM clans
+	`)
+	if err != nil {
+		return fmt.Errorf("querying clans: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rec                              clan.Record
+			dissolving, penalty, allyPenalty *time.Time
+			allyPenaltyType                  int32
+		)
+		if err := rows.Scan(&rec.ID, &rec.Name, &rec.LeaderID, &rec.Level, &rec.Reputation,
+			&rec.CrestID, &rec.LargeCrestID, &dissolving, &penalty,
+			&rec.AllyID, &rec.AllyName, &rec.AllyCrestID, &allyPenalty, &allyPenaltyType); err != nil {
+			return fmt.Errorf("scanning clan row: %w", err)
+		}
+		rec.DissolvingExpiry = timeOrZero(dissolving)
+		rec.CharPenaltyExpiry = timeOrZero(penalty)
+		rec.AllyPenaltyExpiry = timeOrZero(allyPenalty)
+		rec.AllyPenaltyType = clan.AllyPenalty(allyPenaltyType)
+		snap.Clans = append(snap.Clans, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating clan rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadMembers(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.clan_id, m.character_id, c.name, c.level, c.class_id, m.pledge_type, m.power_grade
+		FROM clan_members m
+		JOIN characters c ON c.character_id = m.character_id
+	`)
+	if err != nil {
+		return fmt.Errorf("querying clan members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m clan.MemberRecord
+		if err := rows.Scan(&m.ClanID, &m.CharacterID, &m.Name, &m.Level, &m.ClassID,
+			&m.PledgeType, &m.PowerGrade); err != nil {
+			return fmt.Errorf("scanning clan member row: %w", err)
+		}
+		snap.Members = append(snap.Members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating clan member rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadSubPledges(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `SELECT clan_id, pledge_type, name, leader_id FROM clan_subpledges`)
+	if err != nil {
+		return fmt.Errorf("querying sub-pledges: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s clan.SubPledgeRecord
+		if err := rows.Scan(&s.ClanID, &s.Type, &s.Name, &s.LeaderID); err != nil {
+			return fmt.Errorf("scanning sub-pledge row: %w", err)
+		}
+		snap.SubPledges = append(snap.SubPledges, s)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating sub-pledge rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadRanks(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `SELECT clan_id, rank, privileges FROM clan_privileges`)
+	if err != nil {
+		return fmt.Errorf("querying clan privileges: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rr    clan.RankRecord
+			privs int32
+		)
+		if err := rows.Scan(&rr.ClanID, &rr.Rank, &privs); err != nil {
+			return fmt.Errorf("scanning clan privileges row: %w", err)
+		}
+		rr.Privileges = clan.Privilege(privs)
+		snap.Ranks = append(snap.Ranks, rr)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating clan privileges rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadCrests(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `SELECT crest_id, data FROM clan_crests`)
+	if err != nil {
+		return fmt.Errorf("querying crests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id   int32
+			data []byte
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return fmt.Errorf("scanning crest row: %w", err)
+		}
+		snap.Crests[id] = data
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating crest rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadPenalties(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT character_id, join_expiry, create_expiry
+		FROM clan_penalties
+		WHERE join_expiry > NOW() OR create_expiry > NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("querying clan penalties: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			characterID    int64
+			join, creation *time.Time
+		)
+		if err := rows.Scan(&characterID, &join, &creation); err != nil {
+			return fmt.Errorf("scanning clan penalty row: %w", err)
+		}
+		snap.Penalties[characterID] = clan.Penalty{JoinExpiry: timeOrZero(join), CreateExpiry: timeOrZero(creation)}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating clan penalty rows: %w", err)
+	}
+	return nil
+}
+
+func (r *ClanRepository) loadWars(ctx context.Context, snap *clan.Snapshot) error {
+	rows, err := r.db.Query(ctx, `SELECT clan_id, enemy_clan_id FROM clan_wars`)
+	if err != nil {
+		return fmt.Errorf("querying clan wars: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w clan.WarRecord
+		if err := rows.Scan(&w.ClanID, &w.EnemyID); err != nil {
+			return fmt.Errorf("scanning clan war row: %w", err)
+		}
+		snap.Wars = append(snap.Wars, w)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating clan war rows: %w", err)
+	}
+	return nil
+}
@@ -2,8 +2,6 @@ package db
 
 import (
 	"context"
-	"crypto/sha1"
-	"encoding/base64"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -11,6 +9,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/passhash"
 )
 
 // DB wraps a pgx connection pool for account operations.
@@ -58,10 +57,10 @@ func (d *DB) Pool() *pgxpool.Pool {
 
 // HashPassword hashes a password with SHA-1 and returns Base64 encoding.
 // This matches the L2J algorithm: SHA1(password) -> Base64.
+// It is the legacy format: new passwords are hashed with passhash.Hasher, and
+// accounts still holding SHA-1 hashes are migrated on their next login.
 func HashPassword(password string) string {
-	h := sha1.New()
-	h.Write([]byte(password))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return passhash.Legacy(password)
 }
 
 // GetAccount retrieves an account by login.
@@ -70,9 +69,9 @@ func (d *DB) GetAccount(ctx context.Context, login string) (*model.Account, erro
 	login = strings.ToLower(login)
 	var acc model.Account
 	err := d.pool.QueryRow(ctx,
-		`SELECT login, password, access_level, last_server, last_ip, last_active
+		`SELECT account_id, login, password, access_level, last_server, last_ip, last_active
 		 FROM accounts WHERE login = $1`, login,
-	).Scan(&acc.Login, &acc.PasswordHash, &acc.AccessLevel, &acc.LastServer, &acc.LastIP, &acc.LastActive)
+	).Scan(&acc.ID, &acc.Login, &acc.PasswordHash, &acc.AccessLevel, &acc.LastServer, &acc.LastIP, &acc.LastActive)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/udisondev/la2go/internal/friend"
+)
+
+// FriendRepository хранит списки друзей и блокировок персонажей.
+type FriendRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewFriendRepository создаёт новый FriendRepository.
+func NewFriendRepository(db *pgxpool.Pool) *FriendRepository {
+	return &FriendRepository{db: db}
+}
+
+// LoadContacts загружает друзей и заблокированных персонажей.
+func (r *FriendRepository) LoadContacts(ctx context.Context, characterID int64) (friends, blocks []friend.Contact, err error) {
+	friends, err = r.loadContacts(ctx, `
+		SELECT c.character_id, c.name
+		FROM character_friends f
+		JOIN characters c ON c.character_id = f.friend_id
+		WHERE f.character_id = $1
+	`, characterID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading friends of character %d: %w", characterID, err)
+	}
+
+	blocks, err = r.loadContacts(ctx, `
+		SELECT c.character_id, c.name
+		FROM character_blocks b
+		JOIN characters c ON c.character_id = b.blocked_id
+		WHERE b.character_id = $1
+	`, characterID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading blocks of character %d: %w", characterID, err)
+	}
+	return friends, blocks, nil
+}
+
+func (r *FriendRepository) loadContacts(ctx context.Context, query string, characterID int64) ([]friend.Contact, error) {
+	rows, err := r.db.Query(ctx, query, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("querying contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []friend.Contact
+	for rows.Next() {
+		var c friend.Contact
+		if err := rows.Scan(&c.CharacterID, &c.Name); err != nil {
+			return nil, fmt.Errorf("scanning contact row: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating contact rows: %w", err)
+	}
+	return contacts, nil
+}
+
+// FindCharacter ищет персонажа по имени без учёта регистра.
+func (r *FriendRepository) FindCharacter(ctx context.Context, name string) (friend.Contact, bool, error) {
+	var c friend.Contact
+	err := r.db.QueryRow(ctx, `
+		SELECT character_id, name FROM characters WHERE LOWER(name) = LOWER($1)
+	`, name).Scan(&c.CharacterID, &c.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return friend.Contact{}, false, nil
+	}
+	if err != nil {
+		return friend.Contact{}, false, fmt.Errorf("querying character %q: %w", name, err)
+	}
+	return c, true, nil
+}
+
+// SaveFriendship сохраняет взаимную дружбу персонажей a и b.
+func (r *FriendRepository) SaveFriendship(ctx context.Context, a, b int64) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO character_friends (character_id, friend_id)
+		VALUES ($1, $2), ($2, $1)
+		ON CONFLICT DO NOTHING
+	`, a, b)
+	if err != nil {
+		return fmt.Errorf("saving friendship %d-%d: %w", a, b, err)
+	}
+	return nil
+}
+
+// DeleteFriendship удаляет дружбу персонажей a и b с обеих сторон.
+func (r *FriendRepository) DeleteFriendship(ctx context.Context, a, b int64) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM character_friends
+		WHERE (character_id = $1 AND friend_id = $2) OR (character_id = $2 AND friend_id = $1)
+	`, a, b)
+	if err != nil {
+		return fmt.Errorf("deleting friendship %d-%d: %w", a, b, err)
+	}
+	return nil
+}
+
+// SaveBlock добавляет blockedID в список блокировки characterID.
+func (r *FriendRepository) SaveBlock(ctx context.Context, characterID, blockedID int64) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO character_blocks (character_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, characterID, blockedID)
+	if err != nil {
+		return fmt.Errorf("saving block %d-%d: %w", characterID, blockedID, err)
+	}
+	return nil
+}
+
+// DeleteBlock удаляет blockedID из списка блокировки characterID.
+func (r *FriendRepository) DeleteBlock(ctx context.Context, characterID, blockedID int64) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM character_blocks WHERE character_id = $1 AND blocked_id = $2
+	`, characterID, blockedID)
+	if err != nil {
+		return fmt.Errorf("deleting block %d-%d: %w", characterID, blockedID, err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -199,3 +200,109 @@ func (r *ItemRepository) Delete(ctx context.Context, itemID int64) error {
 
 	return nil
 }
+
+// SyncInventory сохраняет состояние инвентаря после обмена предметами
+// (private store, trade): upsert всех предметов и удаление тех, что ушли владельцу.
+func (r *ItemRepository) SyncInventory(ctx context.Context, ownerID int64, items []*model.Item) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		loc, slotID := item.Location()
+		_, err := tx.Exec(ctx, `
+			INSERT INTO items (item_id, owner_id, item_type, count, enchant, location, slot_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (item_id) DO UPDATE
+			SET owner_id = EXCLUDED.owner_id, count = EXCLUDED.count, enchant = EXCLUDED.enchant,
+			    location = EXCLUDED.location, slot_id = EXCLUDED.slot_id
+		`, item.ItemID(), ownerID, item.ItemType(), item.Count(), item.Enchant(), int32(loc), slotID)
+		if err != nil {
+			return fmt.Errorf("upserting item %d: %w", item.ItemID(), err)
+		}
+		ids = append(ids, item.ItemID())
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM items
+		WHERE owner_id = $1 AND location = $2 AND NOT (item_id = ANY($3))
+	`, ownerID, int32(model.ItemLocationInventory), ids)
+	if err != nil {
+		return fmt.Errorf("deleting transferred items of owner %d: %w", ownerID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing inventory of owner %d: %w", ownerID, err)
+	}
+	return nil
+}
+
+// itemIDTimeout ограничивает запрос очередного блока ID предметов.
+const itemIDTimeout = 5 * time.Second
+
+// ItemIDAllocator выдаёт ID предметам, созданным в памяти, из последовательности
+// items.item_id (model.ItemIDSource). ID берутся блоками по batch штук, чтобы не
+// ходить в БД за каждым новым стаком; неиспользованный остаток блока после
+// рестарта просто пропадает.
+type ItemIDAllocator struct {
+	db    *pgxpool.Pool
+	batch int
+
+	mu   sync.Mutex
+	free []int64
+}
+
+// NewItemIDAllocator создаёт ItemIDAllocator; batch <= 0 означает 100.
+func NewItemIDAllocator(db *pgxpool.Pool, batch int) *ItemIDAllocator {
+	if batch <= 0 {
+		batch = 100
+	}
+	return &ItemIDAllocator{db: db, batch: batch}
+}
+
+// NextItemID возвращает следующий свободный item_id.
+func (a *ItemIDAllocator) NextItemID() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.free) == 0 {
+		if err := a.refill(); err != nil {
+			return 0, err
+		}
+	}
+	id := a.free[0]
+	a.free = a.free[1:]
+	return id, nil
+}
+
+// refill резервирует следующий блок ID в последовательности.
+func (a *ItemIDAllocator) refill() error {
+	ctx, cancel := context.WithTimeout(context.Background(), itemIDTimeout)
+	defer cancel()
+
+	rows, err := a.db.Query(ctx,
+		`SELECT nextval(pg_get_serial_sequence('items', 'item_id')) FROM generate_series(1, $1)`,
+		a.batch,
+	)
+	if err != nil {
+		return fmt.Errorf("reserving item ids: %w", err)
+	}
+	defer rows.Close()
+
+	free := make([]int64, 0, a.batch)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("scanning item id: %w", err)
+		}
+		free = append(free, id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating item ids: %w", err)
+	}
+	a.free = free
+	return nil
+}
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS character_offline_trade (
+    character_id BIGINT PRIMARY KEY REFERENCES characters(character_id) ON DELETE CASCADE,
+    store_type INTEGER NOT NULL CHECK (store_type IN (1, 3, 5, 8)),
+    title VARCHAR(64) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS character_offline_trade_items (
+    character_id BIGINT NOT NULL REFERENCES character_offline_trade(character_id) ON DELETE CASCADE,
+    slot INTEGER NOT NULL,
+    object_id BIGINT NOT NULL DEFAULT 0,
+    item_type INTEGER NOT NULL DEFAULT 0,
+    enchant INTEGER NOT NULL DEFAULT 0,
+    count INTEGER NOT NULL CHECK (count > 0),
+    price INTEGER NOT NULL CHECK (price >= 0),
+    PRIMARY KEY (character_id, slot)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS character_offline_trade_items;
+DROP TABLE IF EXISTS character_offline_trade;
+-- +goose StatementEnd
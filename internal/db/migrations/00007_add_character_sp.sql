@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS sp BIGINT NOT NULL DEFAULT 0 CHECK (sp >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters DROP COLUMN IF EXISTS sp;
+-- +goose StatementEnd
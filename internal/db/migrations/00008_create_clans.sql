@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS clan_crests (
+    crest_id SERIAL PRIMARY KEY,
+    data BYTEA NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS clans (
+    clan_id SERIAL PRIMARY KEY,
+    name VARCHAR(16) NOT NULL,
+    leader_id BIGINT NOT NULL REFERENCES characters(character_id),
+    level INTEGER NOT NULL DEFAULT 0 CHECK (level >= 0 AND level <= 8),
+    reputation INTEGER NOT NULL DEFAULT 0,
+    crest_id INTEGER REFERENCES clan_crests(crest_id) ON DELETE SET NULL,
+    large_crest_id INTEGER REFERENCES clan_crests(crest_id) ON DELETE SET NULL,
+    dissolving_expiry TIMESTAMPTZ,
+    char_penalty_expiry TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clans_name ON clans (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS clan_members (
+    character_id BIGINT PRIMARY KEY REFERENCES characters(character_id) ON DELETE CASCADE,
+    clan_id INTEGER NOT NULL REFERENCES clans(clan_id) ON DELETE CASCADE,
+    pledge_type INTEGER NOT NULL DEFAULT 0,
+    power_grade INTEGER NOT NULL CHECK (power_grade >= 1 AND power_grade <= 9),
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_clan_members_clan ON clan_members (clan_id);
+
+CREATE TABLE IF NOT EXISTS clan_subpledges (
+    clan_id INTEGER NOT NULL REFERENCES clans(clan_id) ON DELETE CASCADE,
+    pledge_type INTEGER NOT NULL,
+    name VARCHAR(16) NOT NULL,
+    leader_id BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (clan_id, pledge_type)
+);
+
+CREATE TABLE IF NOT EXISTS clan_privileges (
+    clan_id INTEGER NOT NULL REFERENCES clans(clan_id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL CHECK (rank >= 1 AND rank <= 9),
+    privileges INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (clan_id, rank)
+);
+
+CREATE TABLE IF NOT EXISTS clan_penalties (
+    character_id BIGINT PRIMARY KEY REFERENCES characters(character_id) ON DELETE CASCADE,
+    join_expiry TIMESTAMPTZ,
+    create_expiry TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS clan_penalties;
+DROP TABLE IF EXISTS clan_privileges;
+DROP TABLE IF EXISTS clan_subpledges;
+DROP TABLE IF EXISTS clan_members;
+DROP TABLE IF EXISTS clans;
+DROP TABLE IF EXISTS clan_crests;
+-- +goose StatementEnd
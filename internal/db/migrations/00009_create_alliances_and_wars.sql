@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE clans
+    ADD COLUMN IF NOT EXISTS ally_id INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS ally_name VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ally_crest_id INTEGER REFERENCES clan_crests(crest_id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS ally_penalty_expiry TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS ally_penalty_type INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_clans_ally ON clans (ally_id) WHERE ally_id <> 0;
+
+CREATE TABLE IF NOT EXISTS clan_wars (
+    clan_id INTEGER NOT NULL REFERENCES clans(clan_id) ON DELETE CASCADE,
+    enemy_clan_id INTEGER NOT NULL REFERENCES clans(clan_id) ON DELETE CASCADE,
+    declared_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (clan_id, enemy_clan_id),
+    CHECK (clan_id <> enemy_clan_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS clan_wars;
+DROP INDEX IF EXISTS idx_clans_ally;
+ALTER TABLE clans
+    DROP COLUMN IF EXISTS ally_penalty_type,
+    DROP COLUMN IF EXISTS ally_penalty_expiry,
+    DROP COLUMN IF EXISTS ally_crest_id,
+    DROP COLUMN IF EXISTS ally_name,
+    DROP COLUMN IF EXISTS ally_id;
+-- +goose StatementEnd
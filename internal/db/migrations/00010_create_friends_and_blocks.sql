@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS character_friends (
+    character_id BIGINT NOT NULL REFERENCES characters(character_id) ON DELETE CASCADE,
+    friend_id BIGINT NOT NULL REFERENCES characters(character_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (character_id, friend_id),
+    CHECK (character_id <> friend_id)
+);
+
+CREATE TABLE IF NOT EXISTS character_blocks (
+    character_id BIGINT NOT NULL REFERENCES characters(character_id) ON DELETE CASCADE,
+    blocked_id BIGINT NOT NULL REFERENCES characters(character_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (character_id, blocked_id),
+    CHECK (character_id <> blocked_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS character_blocks;
+DROP TABLE IF EXISTS character_friends;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS character_quests (
+    character_id BIGINT NOT NULL REFERENCES characters(character_id) ON DELETE CASCADE,
+    quest_id INTEGER NOT NULL,
+    var VARCHAR(32) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (character_id, quest_id, var)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS character_quests;
+-- +goose StatementEnd
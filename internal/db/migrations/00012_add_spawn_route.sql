@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Name of the patrol route from data/routes.yaml; NULL = random walk around the spawn point
+ALTER TABLE spawns ADD COLUMN IF NOT EXISTS route VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE spawns DROP COLUMN IF EXISTS route;
+-- +goose StatementEnd
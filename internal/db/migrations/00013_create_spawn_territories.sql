@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS spawn_territories (
+    name VARCHAR(64) PRIMARY KEY,
+    min_z INTEGER NOT NULL,
+    max_z INTEGER NOT NULL,
+    CHECK (max_z >= min_z)
+);
+
+CREATE TABLE IF NOT EXISTS spawn_territory_points (
+    territory VARCHAR(64) NOT NULL REFERENCES spawn_territories(name) ON DELETE CASCADE,
+    idx SMALLINT NOT NULL CHECK (idx >= 0),
+    x INTEGER NOT NULL,
+    y INTEGER NOT NULL,
+    PRIMARY KEY (territory, idx)
+);
+
+-- Sub-areas cut out of a territory (lakes, camps of other NPCs)
+CREATE TABLE IF NOT EXISTS spawn_territory_bans (
+    territory VARCHAR(64) NOT NULL REFERENCES spawn_territories(name) ON DELETE CASCADE,
+    banned VARCHAR(64) NOT NULL REFERENCES spawn_territories(name) ON DELETE CASCADE,
+    PRIMARY KEY (territory, banned),
+    CHECK (territory <> banned)
+);
+
+-- NPCs of a spawn with a territory are scattered in it instead of standing at x, y, z
+ALTER TABLE spawns ADD COLUMN IF NOT EXISTS territory VARCHAR(64) REFERENCES spawn_territories(name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE spawns DROP COLUMN IF EXISTS territory;
+DROP TABLE IF EXISTS spawn_territory_bans;
+DROP TABLE IF EXISTS spawn_territory_points;
+DROP TABLE IF EXISTS spawn_territories;
+-- +goose StatementEnd
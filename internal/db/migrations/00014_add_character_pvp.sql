@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS karma INTEGER NOT NULL DEFAULT 0 CHECK (karma >= 0);
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS pvp_kills INTEGER NOT NULL DEFAULT 0 CHECK (pvp_kills >= 0);
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS pk_kills INTEGER NOT NULL DEFAULT 0 CHECK (pk_kills >= 0);
+-- Town guards attack players with karma
+ALTER TABLE npc_templates ADD COLUMN IF NOT EXISTS is_guard BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE npc_templates DROP COLUMN IF EXISTS is_guard;
+ALTER TABLE characters DROP COLUMN IF EXISTS pk_kills;
+ALTER TABLE characters DROP COLUMN IF EXISTS pvp_kills;
+ALTER TABLE characters DROP COLUMN IF EXISTS karma;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS punishments (
+    id BIGSERIAL PRIMARY KEY,
+    affect VARCHAR(16) NOT NULL CHECK (affect IN ('account', 'character', 'ip')),
+    key VARCHAR(64) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('ban', 'chat_ban', 'jail', 'trade_ban')),
+    expires_at TIMESTAMPTZ,
+    reason TEXT NOT NULL DEFAULT '',
+    punished_by VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Expired punishments are kept as history
+CREATE INDEX IF NOT EXISTS idx_punishments_expires_at ON punishments(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS punishments;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Experience and SP for killing the NPC
+ALTER TABLE npc_templates ADD COLUMN IF NOT EXISTS exp BIGINT NOT NULL DEFAULT 0 CHECK (exp >= 0);
+ALTER TABLE npc_templates ADD COLUMN IF NOT EXISTS sp BIGINT NOT NULL DEFAULT 0 CHECK (sp >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE npc_templates DROP COLUMN IF EXISTS sp;
+ALTER TABLE npc_templates DROP COLUMN IF EXISTS exp;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Numeric account ID: characters.account_id refers to it
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_id BIGSERIAL UNIQUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN IF EXISTS account_id;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Items an NPC may drop when killed; chance is per million, as in L2J drop lists
+CREATE TABLE IF NOT EXISTS npc_drops (
+    template_id INTEGER NOT NULL REFERENCES npc_templates(template_id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    min_count INTEGER NOT NULL CHECK (min_count > 0),
+    max_count INTEGER NOT NULL,
+    chance INTEGER NOT NULL CHECK (chance > 0 AND chance <= 1000000),
+    PRIMARY KEY (template_id, item_id),
+    CHECK (max_count >= min_count)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS npc_drops;
+-- +goose StatementEnd
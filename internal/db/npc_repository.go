@@ -23,7 +23,7 @@ func (r *NpcRepository) LoadTemplate(ctx context.Context, id int32) (*model.NpcT
 	query := `
 		SELECT template_id, name, title, level, max_hp, max_mp,
 		       p_atk, p_def, m_atk, m_def, aggro_range, move_speed, atk_speed,
-		       respawn_min, respawn_max
+		       respawn_min, respawn_max, is_guard, exp, sp
 		FROM npc_templates
 		WHERE template_id = $1
 	`
@@ -43,22 +43,33 @@ func (r *NpcRepository) LoadTemplate(ctx context.Context, id int32) (*model.NpcT
 		atkSpeed    int32
 		respawnMin  int32
 		respawnMax  int32
+		isGuard     bool
+		exp, sp     int64
 	)
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&templateID, &name, &title, &level, &maxHP, &maxMP,
 		&pAtk, &pDef, &mAtk, &mDef, &aggroRange, &moveSpeed, &atkSpeed,
-		&respawnMin, &respawnMax,
+		&respawnMin, &respawnMax, &isGuard, &exp, &sp,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("loading npc template %d: %w", id, err)
 	}
 
-	return model.NewNpcTemplate(
+	template := model.NewNpcTemplate(
 		templateID, name, title, level, maxHP, maxMP,
 		pAtk, pDef, mAtk, mDef, aggroRange, moveSpeed, atkSpeed,
 		respawnMin, respawnMax,
-	), nil
+	)
+	template.SetGuard(isGuard)
+	template.SetRewards(exp, sp)
+
+	drops, err := r.loadDrops(ctx, `WHERE template_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	template.SetDrops(drops[id])
+	return template, nil
 }
 
 // LoadAllTemplates loads all NPC templates
@@ -66,7 +77,7 @@ func (r *NpcRepository) LoadAllTemplates(ctx context.Context) ([]*model.NpcTempl
 	query := `
 		SELECT template_id, name, title, level, max_hp, max_mp,
 		       p_atk, p_def, m_atk, m_def, aggro_range, move_speed, atk_speed,
-		       respawn_min, respawn_max
+		       respawn_min, respawn_max, is_guard, exp, sp
 		FROM npc_templates
 		ORDER BY template_id
 	`
@@ -95,12 +106,14 @@ func (r *NpcRepository) LoadAllTemplates(ctx context.Context) ([]*model.NpcTempl
 			atkSpeed    int32
 			respawnMin  int32
 			respawnMax  int32
+			isGuard     bool
+			exp, sp     int64
 		)
 
 		if err := rows.Scan(
 			&templateID, &name, &title, &level, &maxHP, &maxMP,
 			&pAtk, &pDef, &mAtk, &mDef, &aggroRange, &moveSpeed, &atkSpeed,
-			&respawnMin, &respawnMax,
+			&respawnMin, &respawnMax, &isGuard, &exp, &sp,
 		); err != nil {
 			return nil, fmt.Errorf("scanning npc template row: %w", err)
 		}
@@ -110,6 +123,8 @@ func (r *NpcRepository) LoadAllTemplates(ctx context.Context) ([]*model.NpcTempl
 			pAtk, pDef, mAtk, mDef, aggroRange, moveSpeed, atkSpeed,
 			respawnMin, respawnMax,
 		)
+		template.SetGuard(isGuard)
+		template.SetRewards(exp, sp)
 
 		templates = append(templates, template)
 	}
@@ -118,18 +133,58 @@ func (r *NpcRepository) LoadAllTemplates(ctx context.Context) ([]*model.NpcTempl
 		return nil, fmt.Errorf("iterating npc template rows: %w", err)
 	}
 
+	drops, err := r.loadDrops(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		template.SetDrops(drops[template.TemplateID()])
+	}
+
 	return templates, nil
 }
 
+// loadDrops loads drop lists of NPC templates, keyed by template ID
+func (r *NpcRepository) loadDrops(ctx context.Context, where string, args ...any) (map[int32][]model.NpcDrop, error) {
+	query := `
+		SELECT template_id, item_id, min_count, max_count, chance
+		FROM npc_drops
+		` + where + `
+		ORDER BY template_id, item_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("loading npc drops: %w", err)
+	}
+	defer rows.Close()
+
+	drops := make(map[int32][]model.NpcDrop)
+	for rows.Next() {
+		var (
+			templateID int32
+			drop       model.NpcDrop
+		)
+		if err := rows.Scan(&templateID, &drop.ItemType, &drop.Min, &drop.Max, &drop.Chance); err != nil {
+			return nil, fmt.Errorf("scanning npc drop row: %w", err)
+		}
+		drops[templateID] = append(drops[templateID], drop)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating npc drop rows: %w", err)
+	}
+	return drops, nil
+}
+
 // Create creates new NPC template
 func (r *NpcRepository) Create(ctx context.Context, template *model.NpcTemplate) error {
 	query := `
 		INSERT INTO npc_templates (
 			template_id, name, title, level, max_hp, max_mp,
 			p_atk, p_def, m_atk, m_def, aggro_range, move_speed, atk_speed,
-			respawn_min, respawn_max
+			respawn_min, respawn_max, is_guard, exp, sp
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
 		)
 	`
 
@@ -149,10 +204,23 @@ func (r *NpcRepository) Create(ctx context.Context, template *model.NpcTemplate)
 		template.AtkSpeed(),
 		template.RespawnMin(),
 		template.RespawnMax(),
+		template.IsGuard(),
+		template.Exp(),
+		template.SP(),
 	)
 	if err != nil {
 		return fmt.Errorf("creating npc template %d: %w", template.TemplateID(), err)
 	}
 
+	for _, drop := range template.Drops() {
+		_, err := r.pool.Exec(ctx, `
+			INSERT INTO npc_drops (template_id, item_id, min_count, max_count, chance)
+			VALUES ($1, $2, $3, $4, $5)
+		`, template.TemplateID(), drop.ItemType, drop.Min, drop.Max, drop.Chance)
+		if err != nil {
+			return fmt.Errorf("creating drop %d of npc template %d: %w", drop.ItemType, template.TemplateID(), err)
+		}
+	}
+
 	return nil
 }
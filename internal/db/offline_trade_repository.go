@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/privatestore"
+)
+
+// OfflineTradeRepository хранит магазины offline-торговцев.
+// Рецепты manufacture магазина хранятся в той же таблице позиций:
+// item_type = recipeID, price = стоимость изготовления.
+type OfflineTradeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOfflineTradeRepository создаёт новый OfflineTradeRepository.
+func NewOfflineTradeRepository(db *pgxpool.Pool) *OfflineTradeRepository {
+	return &OfflineTradeRepository{db: db}
+}
+
+// SaveOfflineStore сохраняет (перезаписывает) магазин персонажа.
+func (r *OfflineTradeRepository) SaveOfflineStore(ctx context.Context, store privatestore.OfflineStore) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO character_offline_trade (character_id, store_type, title, started_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (character_id) DO UPDATE
+		SET store_type = EXCLUDED.store_type, title = EXCLUDED.title
+	`, store.CharacterID, int32(store.StoreType), store.Title, store.StartedAt)
+	if err != nil {
+		return fmt.Errorf("upserting offline store %d: %w", store.CharacterID, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM character_offline_trade_items WHERE character_id = $1`, store.CharacterID); err != nil {
+		return fmt.Errorf("clearing offline store items %d: %w", store.CharacterID, err)
+	}
+
+	batch := &pgx.Batch{}
+	const insertItem = `
+		INSERT INTO character_offline_trade_items (character_id, slot, object_id, item_type, enchant, count, price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for i, it := range store.Items {
+		batch.Queue(insertItem, store.CharacterID, i, int64(it.ObjectID), it.ItemType, it.Enchant, it.Count, it.Price)
+	}
+	for i, rec := range store.Recipes {
+		batch.Queue(insertItem, store.CharacterID, i, int64(0), rec.RecipeID, int32(0), int32(1), rec.Cost)
+	}
+	if batch.Len() > 0 {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("inserting offline store items %d: %w", store.CharacterID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing offline store %d: %w", store.CharacterID, err)
+	}
+	return nil
+}
+
+// DeleteOfflineStore удаляет магазин персонажа (позиции удаляются каскадом).
+func (r *OfflineTradeRepository) DeleteOfflineStore(ctx context.Context, characterID int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM character_offline_trade WHERE character_id = $1`, characterID)
+	if err != nil {
+		return fmt.Errorf("deleting offline store %d: %w", characterID, err)
+	}
+	return nil
+}
+
+// LoadOfflineStores загружает все сохранённые магазины.
+func (r *OfflineTradeRepository) LoadOfflineStores(ctx context.Context) ([]privatestore.OfflineStore, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT t.character_id, t.store_type, t.title, t.started_at,
+		       i.object_id, i.item_type, i.enchant, i.count, i.price
+		FROM character_offline_trade t
+		LEFT JOIN character_offline_trade_items i ON i.character_id = t.character_id
+		ORDER BY t.character_id, i.slot
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying offline stores: %w", err)
+	}
+	defer rows.Close()
+
+	stores := make([]privatestore.OfflineStore, 0, 16)
+	for rows.Next() {
+		var (
+			characterID int64
+			storeType   int32
+			title       string
+			startedAt   time.Time
+			objectID    *int64
+			itemType    *int32
+			enchant     *int32
+			count       *int32
+			price       *int32
+		)
+		if err := rows.Scan(&characterID, &storeType, &title, &startedAt,
+			&objectID, &itemType, &enchant, &count, &price); err != nil {
+			return nil, fmt.Errorf("scanning offline store row: %w", err)
+		}
+
+		if len(stores) == 0 || stores[len(stores)-1].CharacterID != characterID {
+			st := model.PrivateStoreType(storeType)
+			stores = append(stores, privatestore.OfflineStore{
+				CharacterID: characterID,
+				StoreType:   st,
+				Title:       title,
+				Packaged:    st == model.PrivateStorePackageSell,
+				StartedAt:   startedAt,
+			})
+		}
+		if objectID == nil {
+			continue // магазин без позиций (LEFT JOIN)
+		}
+
+		store := &stores[len(stores)-1]
+		if store.StoreType == model.PrivateStoreManufacture {
+			store.Recipes = append(store.Recipes, model.ManufactureItem{RecipeID: *itemType, Cost: *price})
+			continue
+		}
+		store.Items = append(store.Items, model.TradeItem{
+			ObjectID: uint32(*objectID),
+			ItemType: *itemType,
+			Enchant:  *enchant,
+			Count:    *count,
+			Price:    *price,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating offline store rows: %w", err)
+	}
+
+	return stores, nil
+}
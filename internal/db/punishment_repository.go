@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/udisondev/la2go/internal/punishment"
+)
+
+// PunishmentRepository хранит баны, баны чата, торговли и тюрьму.
+type PunishmentRepository struct {
+	db Querier
+}
+
+// NewPunishmentRepository создаёт новый PunishmentRepository (с пулом или в транзакции).
+func NewPunishmentRepository(db Querier) *PunishmentRepository {
+	return &PunishmentRepository{db: db}
+}
+
+// LoadActive загружает наказания, действующие на момент now.
+// Строки с неизвестным типом или целью пропускаются.
+func (r *PunishmentRepository) LoadActive(ctx context.Context, now time.Time) ([]punishment.Punishment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, affect, key, type, expires_at, reason, punished_by
+		FROM punishments
+		WHERE expires_at IS NULL OR expires_at > $1
+		ORDER BY id
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("querying punishments: %w", err)
+	}
+	defer rows.Close()
+
+	var list []punishment.Punishment
+	for rows.Next() {
+		var (
+			p           punishment.Punishment
+			affect, typ string
+			expires     *time.Time
+		)
+		if err := rows.Scan(&p.ID, &affect, &p.Key, &typ, &expires, &p.Reason, &p.PunishedBy); err != nil {
+			return nil, fmt.Errorf("scanning punishment row: %w", err)
+		}
+		var ok1, ok2 bool
+		p.Affect, ok1 = punishment.ParseAffect(affect)
+		p.Type, ok2 = punishment.ParseType(typ)
+		if !ok1 || !ok2 {
+			continue
+		}
+		if expires != nil {
+			p.Expires = *expires
+		}
+		list = append(list, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating punishment rows: %w", err)
+	}
+	return list, nil
+}
+
+// Add сохраняет наказание и возвращает его ID.
+func (r *PunishmentRepository) Add(ctx context.Context, p punishment.Punishment) (int64, error) {
+	var expires *time.Time
+	if !p.Permanent() {
+		expires = &p.Expires
+	}
+
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO punishments (affect, key, type, expires_at, reason, punished_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, p.Affect.String(), p.Key, p.Type.String(), expires, p.Reason, p.PunishedBy).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting punishment: %w", err)
+	}
+	return id, nil
+}
+
+// Remove удаляет наказание (снятие до истечения срока).
+func (r *PunishmentRepository) Remove(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM punishments WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("deleting punishment %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/udisondev/la2go/internal/quest"
+)
+
+// QuestRepository хранит переменные квестов персонажей.
+type QuestRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewQuestRepository создаёт новый QuestRepository.
+func NewQuestRepository(db *pgxpool.Pool) *QuestRepository {
+	return &QuestRepository{db: db}
+}
+
+// LoadVars загружает все переменные квестов персонажа.
+func (r *QuestRepository) LoadVars(ctx context.Context, characterID int64) ([]quest.Var, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT quest_id, var, value FROM character_quests WHERE character_id = $1
+	`, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("querying quests of character %d: %w", characterID, err)
+	}
+	defer rows.Close()
+
+	var vars []quest.Var
+	for rows.Next() {
+		var v quest.Var
+		if err := rows.Scan(&v.QuestID, &v.Name, &v.Value); err != nil {
+			return nil, fmt.Errorf("scanning quest variable row: %w", err)
+		}
+		vars = append(vars, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating quest variable rows: %w", err)
+	}
+	return vars, nil
+}
+
+// SaveVar сохраняет (перезаписывает) переменную квеста.
+func (r *QuestRepository) SaveVar(ctx context.Context, characterID int64, v quest.Var) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO character_quests (character_id, quest_id, var, value)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (character_id, quest_id, var) DO UPDATE SET value = EXCLUDED.value
+	`, characterID, v.QuestID, v.Name, v.Value)
+	if err != nil {
+		return fmt.Errorf("saving quest %d variable %q of character %d: %w", v.QuestID, v.Name, characterID, err)
+	}
+	return nil
+}
+
+// DeleteQuest удаляет все переменные квеста персонажа.
+func (r *QuestRepository) DeleteQuest(ctx context.Context, characterID int64, questID int32) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM character_quests WHERE character_id = $1 AND quest_id = $2
+	`, characterID, questID)
+	if err != nil {
+		return fmt.Errorf("deleting quest %d of character %d: %w", questID, characterID, err)
+	}
+	return nil
+}
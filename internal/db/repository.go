@@ -26,9 +26,9 @@ func (r *PostgresAccountRepository) GetAccount(ctx context.Context, login string
 	login = strings.ToLower(login)
 	var acc model.Account
 	err := r.pool.QueryRow(ctx,
-		`SELECT login, password, access_level, last_server, last_ip, last_active
+		`SELECT account_id, login, password, access_level, last_server, last_ip, last_active
 		 FROM accounts WHERE login = $1`, login,
-	).Scan(&acc.Login, &acc.PasswordHash, &acc.AccessLevel, &acc.LastServer, &acc.LastIP, &acc.LastActive)
+	).Scan(&acc.ID, &acc.Login, &acc.PasswordHash, &acc.AccessLevel, &acc.LastServer, &acc.LastIP, &acc.LastActive)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -81,6 +81,18 @@ func (r *PostgresAccountRepository) GetOrCreateAccount(ctx context.Context, logi
 	return acc, nil
 }
 
+// UpdatePassword заменяет хеш пароля аккаунта.
+func (r *PostgresAccountRepository) UpdatePassword(ctx context.Context, login, passwordHash string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE accounts SET password = $1 WHERE login = $2`,
+		passwordHash, strings.ToLower(login),
+	)
+	if err != nil {
+		return fmt.Errorf("updating password for %q: %w", login, err)
+	}
+	return nil
+}
+
 // UpdateLastLogin обновляет last_active и last_ip при успешном логине.
 func (r *PostgresAccountRepository) UpdateLastLogin(ctx context.Context, login, ip string) error {
 	_, err := r.pool.Exec(ctx,
@@ -21,7 +21,7 @@ func NewSpawnRepository(pool *pgxpool.Pool) *SpawnRepository {
 // LoadAll loads all spawns from database
 func (r *SpawnRepository) LoadAll(ctx context.Context) ([]*model.Spawn, error) {
 	query := `
-		SELECT spawn_id, template_id, x, y, z, heading, maximum_count, do_respawn
+		SELECT spawn_id, template_id, x, y, z, heading, maximum_count, do_respawn, COALESCE(route, ''), COALESCE(territory, '')
 		FROM spawns
 		ORDER BY spawn_id
 	`
@@ -42,13 +42,17 @@ func (r *SpawnRepository) LoadAll(ctx context.Context) ([]*model.Spawn, error) {
 			heading      uint16
 			maximumCount int32
 			doRespawn    bool
+			route        string
+			territory    string
 		)
 
-		if err := rows.Scan(&spawnID, &templateID, &x, &y, &z, &heading, &maximumCount, &doRespawn); err != nil {
+		if err := rows.Scan(&spawnID, &templateID, &x, &y, &z, &heading, &maximumCount, &doRespawn, &route, &territory); err != nil {
 			return nil, fmt.Errorf("scanning spawn row: %w", err)
 		}
 
 		spawn := model.NewSpawn(spawnID, templateID, x, y, z, heading, maximumCount, doRespawn)
+		spawn.SetRoute(route)
+		spawn.SetTerritory(territory)
 		spawns = append(spawns, spawn)
 	}
 
@@ -62,7 +66,7 @@ func (r *SpawnRepository) LoadAll(ctx context.Context) ([]*model.Spawn, error) {
 // LoadByID loads spawn by ID
 func (r *SpawnRepository) LoadByID(ctx context.Context, spawnID int64) (*model.Spawn, error) {
 	query := `
-		SELECT spawn_id, template_id, x, y, z, heading, maximum_count, do_respawn
+		SELECT spawn_id, template_id, x, y, z, heading, maximum_count, do_respawn, COALESCE(route, ''), COALESCE(territory, '')
 		FROM spawns
 		WHERE spawn_id = $1
 	`
@@ -74,23 +78,28 @@ func (r *SpawnRepository) LoadByID(ctx context.Context, spawnID int64) (*model.S
 		heading      uint16
 		maximumCount int32
 		doRespawn    bool
+		route        string
+		territory    string
 	)
 
 	err := r.pool.QueryRow(ctx, query, spawnID).Scan(
-		&id, &templateID, &x, &y, &z, &heading, &maximumCount, &doRespawn,
+		&id, &templateID, &x, &y, &z, &heading, &maximumCount, &doRespawn, &route, &territory,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("loading spawn %d: %w", spawnID, err)
 	}
 
-	return model.NewSpawn(id, templateID, x, y, z, heading, maximumCount, doRespawn), nil
+	spawn := model.NewSpawn(id, templateID, x, y, z, heading, maximumCount, doRespawn)
+	spawn.SetRoute(route)
+	spawn.SetTerritory(territory)
+	return spawn, nil
 }
 
 // Create creates new spawn
 func (r *SpawnRepository) Create(ctx context.Context, spawn *model.Spawn) (int64, error) {
 	query := `
-		INSERT INTO spawns (template_id, x, y, z, heading, maximum_count, do_respawn)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO spawns (template_id, x, y, z, heading, maximum_count, do_respawn, route, territory)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''))
 		RETURNING spawn_id
 	`
 
@@ -105,6 +114,8 @@ func (r *SpawnRepository) Create(ctx context.Context, spawn *model.Spawn) (int64
 		loc.Heading,
 		spawn.MaximumCount(),
 		spawn.DoRespawn(),
+		spawn.Route(),
+		spawn.Territory(),
 	).Scan(&spawnID)
 
 	if err != nil {
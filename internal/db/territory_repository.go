@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// TerritoryRepository handles spawn territories
+type TerritoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTerritoryRepository creates a new territory repository
+func NewTerritoryRepository(pool *pgxpool.Pool) *TerritoryRepository {
+	return &TerritoryRepository{pool: pool}
+}
+
+// LoadAll loads all territories with their polygons and banned areas, keyed by name
+func (r *TerritoryRepository) LoadAll(ctx context.Context) (map[string]*model.Territory, error) {
+	type header struct {
+		minZ, maxZ int32
+		points     []model.TerritoryPoint
+	}
+	headers := make(map[string]*header)
+
+	rows, err := r.pool.Query(ctx, `SELECT name, min_z, max_z FROM spawn_territories`)
+	if err != nil {
+		return nil, fmt.Errorf("loading territories: %w", err)
+	}
+	for rows.Next() {
+		var (
+			name string
+			h    header
+		)
+		if err := rows.Scan(&name, &h.minZ, &h.maxZ); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning territory row: %w", err)
+		}
+		headers[name] = &h
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating territory rows: %w", err)
+	}
+
+	rows, err = r.pool.Query(ctx, `SELECT territory, x, y FROM spawn_territory_points ORDER BY territory, idx`)
+	if err != nil {
+		return nil, fmt.Errorf("loading territory points: %w", err)
+	}
+	for rows.Next() {
+		var (
+			name string
+			p    model.TerritoryPoint
+		)
+		if err := rows.Scan(&name, &p.X, &p.Y); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning territory point row: %w", err)
+		}
+		if h, ok := headers[name]; ok {
+			h.points = append(h.points, p)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating territory point rows: %w", err)
+	}
+
+	territories := make(map[string]*model.Territory, len(headers))
+	for name, h := range headers {
+		t, err := model.NewTerritory(name, h.points, h.minZ, h.maxZ)
+		if err != nil {
+			return nil, fmt.Errorf("loading territory %q: %w", name, err)
+		}
+		territories[name] = t
+	}
+
+	rows, err = r.pool.Query(ctx, `SELECT territory, banned FROM spawn_territory_bans`)
+	if err != nil {
+		return nil, fmt.Errorf("loading territory bans: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, banned string
+		if err := rows.Scan(&name, &banned); err != nil {
+			return nil, fmt.Errorf("scanning territory ban row: %w", err)
+		}
+		if err := territories[name].AddBanned(territories[banned]); err != nil {
+			return nil, fmt.Errorf("loading territory %q: %w", name, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating territory ban rows: %w", err)
+	}
+
+	return territories, nil
+}
+
+// Import creates or replaces territories (e.g. loaded from data files) in one transaction.
+// Territories not in the list are left untouched.
+func (r *TerritoryRepository) Import(ctx context.Context, territories map[string]*model.Territory) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	for name, t := range territories {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO spawn_territories (name, min_z, max_z) VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO UPDATE SET min_z = EXCLUDED.min_z, max_z = EXCLUDED.max_z
+		`, name, t.MinZ(), t.MaxZ())
+		if err != nil {
+			return fmt.Errorf("saving territory %q: %w", name, err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM spawn_territory_points WHERE territory = $1`, name); err != nil {
+			return fmt.Errorf("clearing points of territory %q: %w", name, err)
+		}
+		for i, p := range t.Points() {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO spawn_territory_points (territory, idx, x, y) VALUES ($1, $2, $3, $4)
+			`, name, i, p.X, p.Y)
+			if err != nil {
+				return fmt.Errorf("saving point %d of territory %q: %w", i, name, err)
+			}
+		}
+	}
+
+	// Bans refer to other territories, so they are saved once all territories exist
+	for name, t := range territories {
+		if _, err := tx.Exec(ctx, `DELETE FROM spawn_territory_bans WHERE territory = $1`, name); err != nil {
+			return fmt.Errorf("clearing bans of territory %q: %w", name, err)
+		}
+		for _, b := range t.Banned() {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO spawn_territory_bans (territory, banned) VALUES ($1, $2)
+			`, name, b.Name())
+			if err != nil {
+				return fmt.Errorf("saving ban %q of territory %q: %w", b.Name(), name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing territories: %w", err)
+	}
+	return nil
+}
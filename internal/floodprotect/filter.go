@@ -0,0 +1,117 @@
+// Package floodprotect refuses connections from addresses that connect too often or
+// hold too many connections at once (L2J FloodProtectedListener).
+package floodprotect
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/config"
+)
+
+// Config holds the limits of a Filter (L2J FAST_CONNECTION_LIMIT, NORMAL_CONNECTION_TIME,
+// FAST_CONNECTION_TIME, MAX_CONNECTION_PER_IP).
+type Config struct {
+	FastConnectionLimit  int // connections of an IP after which it must wait NormalConnectionTime between connects
+	NormalConnectionTime time.Duration
+	FastConnectionTime   time.Duration // connects closer than this are always refused
+	MaxConnectionsPerIP  int           // open connections of one IP
+}
+
+// ConfigFrom converts the server configuration, where times are in milliseconds.
+func ConfigFrom(c config.FloodProtect) Config {
+	return Config{
+		FastConnectionLimit:  c.FastConnectionLimit,
+		NormalConnectionTime: time.Duration(c.NormalConnectionTime) * time.Millisecond,
+		FastConnectionTime:   time.Duration(c.FastConnectionTime) * time.Millisecond,
+		MaxConnectionsPerIP:  c.MaxConnectionPerIP,
+	}
+}
+
+// client is the connection history of one IP.
+type client struct {
+	connections int       // open connections
+	last        time.Time // last connect, refused ones included
+	flooding    bool
+}
+
+// Filter decides at accept time whether a connection is let in. Refused connects still
+// move the last connect time, so an IP that keeps reconnecting stays refused until it
+// pauses. Thread-safe.
+type Filter struct {
+	cfg Config
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+// NewFilter creates a filter with the given limits.
+func NewFilter(cfg Config) *Filter {
+	return &Filter{
+		cfg:     cfg,
+		clients: make(map[string]*client),
+		now:     time.Now,
+	}
+}
+
+// Accept reports whether a new connection from ip is let in. Every accepted connection
+// must be given back with Release when it closes.
+func (f *Filter) Accept(ip string) bool {
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sweep(now)
+
+	c, ok := f.clients[ip]
+	if !ok {
+		f.clients[ip] = &client{connections: 1, last: now}
+		return true
+	}
+
+	since := now.Sub(c.last)
+	c.last = now
+	c.connections++
+	if (c.connections > f.cfg.FastConnectionLimit && since < f.cfg.NormalConnectionTime) ||
+		since < f.cfg.FastConnectionTime ||
+		c.connections > f.cfg.MaxConnectionsPerIP {
+		c.connections--
+		if !c.flooding {
+			c.flooding = true
+			slog.Warn("connection flood, refusing connections", "ip", ip, "open", c.connections)
+		}
+		return false
+	}
+	if c.flooding {
+		c.flooding = false
+		slog.Info("connection flood ended", "ip", ip)
+	}
+	return true
+}
+
+// Release gives back an accepted connection of ip that has closed.
+func (f *Filter) Release(ip string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.clients[ip]; ok && c.connections > 0 {
+		c.connections--
+	}
+}
+
+// sweep forgets IPs without open connections that have not connected for a while.
+// Called with f.mu held.
+func (f *Filter) sweep(now time.Time) {
+	keep := max(f.cfg.NormalConnectionTime, f.cfg.FastConnectionTime)
+	if now.Sub(f.lastSweep) < keep {
+		return
+	}
+	f.lastSweep = now
+	for ip, c := range f.clients {
+		if c.connections == 0 && now.Sub(c.last) >= keep {
+			delete(f.clients, ip)
+		}
+	}
+}
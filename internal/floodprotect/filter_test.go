@@ -0,0 +1,172 @@
+package floodprotect
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/config"
+)
+
+func testFilter(now *time.Time) *Filter {
+	f := NewFilter(Config{
+		FastConnectionLimit:  3,
+		NormalConnectionTime: 700 * time.Millisecond,
+		FastConnectionTime:   350 * time.Millisecond,
+		MaxConnectionsPerIP:  5,
+	})
+	f.now = func() time.Time { return *now }
+	return f
+}
+
+func TestFilter_FastReconnect(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := testFilter(&now)
+
+	if !f.Accept("10.0.0.1") {
+		t.Fatal("first connection refused")
+	}
+	f.Release("10.0.0.1")
+
+	// Reconnecting faster than FastConnectionTime is refused even after the close
+	now = now.Add(100 * time.Millisecond)
+	if f.Accept("10.0.0.1") {
+		t.Error("fast reconnect accepted")
+	}
+	if !f.Accept("10.0.0.2") {
+		t.Error("other IP refused")
+	}
+
+	// Refused connects move the timer, so a pause is needed
+	now = now.Add(400 * time.Millisecond)
+	if !f.Accept("10.0.0.1") {
+		t.Error("connection after a pause refused")
+	}
+}
+
+func TestFilter_FastConnectionLimit(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := testFilter(&now)
+
+	for i := range 3 {
+		if !f.Accept("10.0.0.1") {
+			t.Fatalf("connection %d refused", i+1)
+		}
+		now = now.Add(500 * time.Millisecond)
+	}
+	// Above the limit connects must be NormalConnectionTime apart
+	if f.Accept("10.0.0.1") {
+		t.Error("connection above the fast limit accepted after 500ms")
+	}
+	now = now.Add(700 * time.Millisecond)
+	if !f.Accept("10.0.0.1") {
+		t.Error("connection after NormalConnectionTime refused")
+	}
+}
+
+func TestFilter_MaxConnectionsPerIP(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := testFilter(&now)
+
+	for i := range 5 {
+		if !f.Accept("10.0.0.1") {
+			t.Fatalf("connection %d refused", i+1)
+		}
+		now = now.Add(time.Second)
+	}
+	if f.Accept("10.0.0.1") {
+		t.Fatal("connection above the per-IP cap accepted")
+	}
+
+	now = now.Add(time.Second)
+	f.Release("10.0.0.1")
+	if !f.Accept("10.0.0.1") {
+		t.Error("connection refused after one was released")
+	}
+}
+
+func TestConfigFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	yaml := "flood_protection: true\nfast_connection_limit: 3\nnormal_connection_time: 700\nfast_connection_time: 350\nmax_connection_per_ip: 5\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	login, err := config.LoadLoginServer(path)
+	if err != nil {
+		t.Fatalf("LoadLoginServer: %v", err)
+	}
+	game, err := config.LoadGameServer(path)
+	if err != nil {
+		t.Fatalf("LoadGameServer: %v", err)
+	}
+
+	want := Config{
+		FastConnectionLimit:  3,
+		NormalConnectionTime: 700 * time.Millisecond,
+		FastConnectionTime:   350 * time.Millisecond,
+		MaxConnectionsPerIP:  5,
+	}
+	if got := ConfigFrom(login.FloodProtect); got != want {
+		t.Errorf("login server config = %+v, want %+v", got, want)
+	}
+	if got := ConfigFrom(game.FloodProtect); got != want {
+		t.Errorf("game server config = %+v, want %+v", got, want)
+	}
+}
+
+func TestListen(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if Listen(ln, nil) != ln {
+		t.Error("nil filter wrapped the listener")
+	}
+
+	// The second connect comes at the same instant as the first one and is dropped
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := []time.Time{start, start, start.Add(time.Second)}
+	f := testFilter(&start)
+	f.now = func() time.Time {
+		now := clock[0]
+		clock = clock[1:]
+		return now
+	}
+	fl := Listen(ln, f)
+
+	var dialed []net.Conn
+	for range 3 {
+		c, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		dialed = append(dialed, c)
+	}
+
+	first, err := fl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := fl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if first.RemoteAddr().String() != dialed[0].LocalAddr().String() ||
+		second.RemoteAddr().String() != dialed[2].LocalAddr().String() {
+		t.Errorf("accepted %s and %s, want the first and the third connection",
+			first.RemoteAddr(), second.RemoteAddr())
+	}
+
+	// Closing twice releases once
+	first.Close()
+	first.Close()
+	if open := f.clients["127.0.0.1"].connections; open != 1 {
+		t.Errorf("open connections = %d, want 1", open)
+	}
+}
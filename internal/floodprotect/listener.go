@@ -0,0 +1,59 @@
+package floodprotect
+
+import (
+	"net"
+	"sync"
+)
+
+// listener closes connections its filter refuses and releases the accepted ones on close.
+type listener struct {
+	net.Listener
+	filter *Filter
+}
+
+// Listen wraps ln so that Accept returns only connections f lets in.
+// A nil filter leaves ln as is (flood protection disabled).
+func Listen(ln net.Listener, f *Filter) net.Listener {
+	if f == nil {
+		return ln
+	}
+	return &listener{Listener: ln, filter: f}
+}
+
+// Accept waits for the next connection the filter lets in.
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		ip := hostOf(conn.RemoteAddr())
+		if l.filter.Accept(ip) {
+			return &releasingConn{Conn: conn, release: func() { l.filter.Release(ip) }}, nil
+		}
+		conn.Close()
+	}
+}
+
+func hostOf(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return tcp.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// releasingConn gives its connection back to the filter on the first Close.
+type releasingConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *releasingConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
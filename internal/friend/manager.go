@@ -0,0 +1,420 @@
+package friend
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// InviteTimeout — время ожидания ответа на приглашение в друзья.
+const InviteTimeout = 15 * time.Second
+
+var (
+	ErrNotAttached       = errors.New("player contacts are not loaded")
+	ErrSelfInvite        = errors.New("cannot add yourself to the friend list")
+	ErrAlreadyFriend     = errors.New("player is already a friend")
+	ErrNotFriend         = errors.New("player is not a friend")
+	ErrFriendListFull    = errors.New("friend list is full")
+	ErrTargetListFull    = errors.New("target friend list is full")
+	ErrBlocked           = errors.New("player has blocked you")
+	ErrTargetBusy        = errors.New("player is answering another invitation")
+	ErrNoInvitation      = errors.New("no pending invitation")
+	ErrInvitationExpired = errors.New("invitation expired")
+	ErrCharacterNotFound = errors.New("character not found")
+	ErrSelfBlock         = errors.New("cannot block yourself")
+	ErrBlockFriend       = errors.New("cannot block a friend")
+	ErrAlreadyBlocked    = errors.New("player is already blocked")
+	ErrNotBlocked        = errors.New("player is not blocked")
+	ErrBlockListFull     = errors.New("block list is full")
+)
+
+// Config — ограничения размеров списков (L2J FRIEND_LIST_LIMIT, BLOCK_LIST_LIMIT).
+type Config struct {
+	MaxFriends int
+	MaxBlocks  int
+}
+
+// DefaultConfig возвращает ограничения клиента Interlude.
+func DefaultConfig() Config {
+	return Config{MaxFriends: 128, MaxBlocks: 128}
+}
+
+// contacts — списки онлайн-персонажа.
+type contacts struct {
+	name     string
+	friends  map[int64]string // characterID → name
+	blocks   map[int64]string // characterID → name
+	blockAll bool             // режим отказа от всех сообщений (/allblock)
+}
+
+// invitation — ожидающее ответа приглашение в друзья.
+type invitation struct {
+	requester *model.Player
+	expires   time.Time
+}
+
+// Manager управляет списками друзей и блокировок онлайн-персонажей.
+// Списки загружаются при входе в мир (Attach) и выгружаются при выходе (Detach).
+// Thread-safe: изменения сохраняются в repository до изменения состояния в памяти.
+type Manager struct {
+	repo Repository // nil = списки не сохраняются
+	cfg  Config
+
+	mu      sync.Mutex
+	online  map[int64]*contacts  // characterID → списки
+	byName  map[string]int64     // lowercase name → characterID онлайн-персонажа
+	invites map[int64]invitation // target characterID → приглашение
+
+	now func() time.Time
+}
+
+// NewManager создаёт менеджер друзей. repo может быть nil (списки только в памяти).
+func NewManager(repo Repository, cfg Config) *Manager {
+	return &Manager{
+		repo:    repo,
+		cfg:     cfg,
+		online:  make(map[int64]*contacts),
+		byName:  make(map[string]int64),
+		invites: make(map[int64]invitation),
+		now:     time.Now,
+	}
+}
+
+// Attach загружает списки персонажа при входе в мир и возвращает его друзей
+// (для уведомления онлайн-друзей о входе).
+func (m *Manager) Attach(ctx context.Context, p *model.Player) ([]Contact, error) {
+	var friends, blocks []Contact
+	if m.repo != nil {
+		var err error
+		friends, blocks, err = m.repo.LoadContacts(ctx, p.CharacterID())
+		if err != nil {
+			return nil, fmt.Errorf("loading contacts of character %d: %w", p.CharacterID(), err)
+		}
+	}
+
+	c := &contacts{
+		name:    p.Name(),
+		friends: make(map[int64]string, len(friends)),
+		blocks:  make(map[int64]string, len(blocks)),
+	}
+	for _, f := range friends {
+		c.friends[f.CharacterID] = f.Name
+	}
+	for _, b := range blocks {
+		c.blocks[b.CharacterID] = b.Name
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.online[p.CharacterID()] = c
+	m.byName[strings.ToLower(p.Name())] = p.CharacterID()
+	return sortedContacts(c.friends), nil
+}
+
+// Detach выгружает списки персонажа при выходе из игры, отменяет его приглашения
+// и возвращает его друзей (для уведомления онлайн-друзей о выходе).
+func (m *Manager) Detach(p *model.Player) []Contact {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := p.CharacterID()
+	delete(m.invites, id)
+	for target, inv := range m.invites {
+		if inv.requester == p {
+			delete(m.invites, target)
+		}
+	}
+
+	c := m.online[id]
+	if c == nil {
+		return nil
+	}
+	delete(m.online, id)
+	delete(m.byName, strings.ToLower(c.name))
+	return sortedContacts(c.friends)
+}
+
+// Friends возвращает друзей персонажа, отсортированных по имени.
+func (m *Manager) Friends(p *model.Player) []Contact {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c := m.online[p.CharacterID()]; c != nil {
+		return sortedContacts(c.friends)
+	}
+	return nil
+}
+
+// Blocks возвращает заблокированных персонажей, отсортированных по имени.
+func (m *Manager) Blocks(p *model.Player) []Contact {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c := m.online[p.CharacterID()]; c != nil {
+		return sortedContacts(c.blocks)
+	}
+	return nil
+}
+
+// IsFriend возвращает true если characterID в списке друзей p.
+func (m *Manager) IsFriend(p *model.Player, characterID int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.online[p.CharacterID()]
+	if c == nil {
+		return false
+	}
+	_, ok := c.friends[characterID]
+	return ok
+}
+
+// IsBlocked возвращает true если owner не принимает сообщения и запросы от from:
+// from в списке блокировки owner или у owner включён режим отказа от всех сообщений.
+func (m *Manager) IsBlocked(owner, from *model.Player) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.online[owner.CharacterID()]
+	if c == nil {
+		return false
+	}
+	if c.blockAll {
+		return true
+	}
+	_, ok := c.blocks[from.CharacterID()]
+	return ok
+}
+
+// SetBlockAll включает или выключает режим отказа от всех сообщений (/allblock, /allunblock).
+// Режим не сохраняется между сессиями.
+func (m *Manager) SetBlockAll(p *model.Player, on bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.online[p.CharacterID()]
+	if c == nil {
+		return ErrNotAttached
+	}
+	c.blockAll = on
+	return nil
+}
+
+// Invite регистрирует приглашение target в друзья requester.
+func (m *Manager) Invite(requester, target *model.Player) error {
+	if requester == target {
+		return ErrSelfInvite
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rc, tc := m.online[requester.CharacterID()], m.online[target.CharacterID()]
+	if rc == nil || tc == nil {
+		return ErrNotAttached
+	}
+	if err := m.canBefriendLocked(rc, tc, target); err != nil {
+		return err
+	}
+	if tc.blockAll {
+		return ErrBlocked
+	}
+	if _, ok := tc.blocks[requester.CharacterID()]; ok {
+		return ErrBlocked
+	}
+	if inv, ok := m.invites[target.CharacterID()]; ok && m.now().Before(inv.expires) {
+		return ErrTargetBusy
+	}
+
+	m.invites[target.CharacterID()] = invitation{
+		requester: requester,
+		expires:   m.now().Add(InviteTimeout),
+	}
+	return nil
+}
+
+// PendingRequester возвращает игрока, пригласившего target (nil если приглашения нет).
+func (m *Manager) PendingRequester(target *model.Player) *model.Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[target.CharacterID()]
+	if !ok {
+		return nil
+	}
+	return inv.requester
+}
+
+// Answer обрабатывает ответ на приглашение в друзья.
+// requester возвращается всегда, если приглашение существовало.
+func (m *Manager) Answer(ctx context.Context, target *model.Player, accept bool) (requester *model.Player, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inv, ok := m.invites[target.CharacterID()]
+	if !ok {
+		return nil, ErrNoInvitation
+	}
+	delete(m.invites, target.CharacterID())
+	requester = inv.requester
+
+	if !accept {
+		return requester, nil
+	}
+	if m.now().After(inv.expires) {
+		return requester, ErrInvitationExpired
+	}
+
+	rc, tc := m.online[requester.CharacterID()], m.online[target.CharacterID()]
+	if rc == nil || tc == nil {
+		return requester, ErrNotAttached
+	}
+	if err := m.canBefriendLocked(rc, tc, target); err != nil {
+		return requester, err
+	}
+
+	if m.repo != nil {
+		if err := m.repo.SaveFriendship(ctx, requester.CharacterID(), target.CharacterID()); err != nil {
+			return requester, fmt.Errorf("saving friendship: %w", err)
+		}
+	}
+	rc.friends[target.CharacterID()] = target.Name()
+	tc.friends[requester.CharacterID()] = requester.Name()
+	return requester, nil
+}
+
+// Remove удаляет друга name из списка p (и p из списка друга).
+func (m *Manager) Remove(ctx context.Context, p *model.Player, name string) (Contact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.online[p.CharacterID()]
+	if c == nil {
+		return Contact{}, ErrNotAttached
+	}
+	friend, ok := findByName(c.friends, name)
+	if !ok {
+		return Contact{}, ErrNotFriend
+	}
+
+	if m.repo != nil {
+		if err := m.repo.DeleteFriendship(ctx, p.CharacterID(), friend.CharacterID); err != nil {
+			return Contact{}, fmt.Errorf("deleting friendship: %w", err)
+		}
+	}
+	delete(c.friends, friend.CharacterID)
+	if fc := m.online[friend.CharacterID]; fc != nil {
+		delete(fc.friends, p.CharacterID())
+	}
+	return friend, nil
+}
+
+// Block добавляет персонажа name в список блокировки p.
+// Персонаж ищется среди онлайн-игроков, затем в repository.
+func (m *Manager) Block(ctx context.Context, p *model.Player, name string) (Contact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.online[p.CharacterID()]
+	if c == nil {
+		return Contact{}, ErrNotAttached
+	}
+	target, err := m.findCharacterLocked(ctx, name)
+	if err != nil {
+		return Contact{}, err
+	}
+	if target.CharacterID == p.CharacterID() {
+		return Contact{}, ErrSelfBlock
+	}
+	if _, ok := c.friends[target.CharacterID]; ok {
+		return Contact{}, ErrBlockFriend
+	}
+	if _, ok := c.blocks[target.CharacterID]; ok {
+		return Contact{}, ErrAlreadyBlocked
+	}
+	if len(c.blocks) >= m.cfg.MaxBlocks {
+		return Contact{}, ErrBlockListFull
+	}
+
+	if m.repo != nil {
+		if err := m.repo.SaveBlock(ctx, p.CharacterID(), target.CharacterID); err != nil {
+			return Contact{}, fmt.Errorf("saving block: %w", err)
+		}
+	}
+	c.blocks[target.CharacterID] = target.Name
+	return target, nil
+}
+
+// Unblock удаляет персонажа name из списка блокировки p.
+func (m *Manager) Unblock(ctx context.Context, p *model.Player, name string) (Contact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.online[p.CharacterID()]
+	if c == nil {
+		return Contact{}, ErrNotAttached
+	}
+	blocked, ok := findByName(c.blocks, name)
+	if !ok {
+		return Contact{}, ErrNotBlocked
+	}
+
+	if m.repo != nil {
+		if err := m.repo.DeleteBlock(ctx, p.CharacterID(), blocked.CharacterID); err != nil {
+			return Contact{}, fmt.Errorf("deleting block: %w", err)
+		}
+	}
+	delete(c.blocks, blocked.CharacterID)
+	return blocked, nil
+}
+
+// canBefriendLocked проверяет, что игроки ещё не друзья и в обоих списках есть место.
+func (m *Manager) canBefriendLocked(rc, tc *contacts, target *model.Player) error {
+	if _, ok := rc.friends[target.CharacterID()]; ok {
+		return ErrAlreadyFriend
+	}
+	if len(rc.friends) >= m.cfg.MaxFriends {
+		return ErrFriendListFull
+	}
+	if len(tc.friends) >= m.cfg.MaxFriends {
+		return ErrTargetListFull
+	}
+	return nil
+}
+
+func (m *Manager) findCharacterLocked(ctx context.Context, name string) (Contact, error) {
+	if id, ok := m.byName[strings.ToLower(name)]; ok {
+		return Contact{CharacterID: id, Name: m.online[id].name}, nil
+	}
+	if m.repo == nil {
+		return Contact{}, ErrCharacterNotFound
+	}
+	contact, ok, err := m.repo.FindCharacter(ctx, name)
+	if err != nil {
+		return Contact{}, fmt.Errorf("finding character %q: %w", name, err)
+	}
+	if !ok {
+		return Contact{}, ErrCharacterNotFound
+	}
+	return contact, nil
+}
+
+// findByName ищет персонажа в списке по имени без учёта регистра.
+func findByName(list map[int64]string, name string) (Contact, bool) {
+	for id, n := range list {
+		if strings.EqualFold(n, name) {
+			return Contact{CharacterID: id, Name: n}, true
+		}
+	}
+	return Contact{}, false
+}
+
+func sortedContacts(list map[int64]string) []Contact {
+	out := make([]Contact, 0, len(list))
+	for id, name := range list {
+		out = append(out, Contact{CharacterID: id, Name: name})
+	}
+	slices.SortFunc(out, func(a, b Contact) int { return cmp.Compare(a.Name, b.Name) })
+	return out
+}
@@ -0,0 +1,240 @@
+package friend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func newTestPlayer(t *testing.T, id int64, name string) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, name, 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	return p
+}
+
+// memRepo хранит списки в памяти.
+type memRepo struct {
+	characters map[int64]string
+	friends    map[[2]int64]bool
+	blocks     map[[2]int64]bool
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		characters: make(map[int64]string),
+		friends:    make(map[[2]int64]bool),
+		blocks:     make(map[[2]int64]bool),
+	}
+}
+
+func (r *memRepo) LoadContacts(_ context.Context, id int64) (friends, blocks []Contact, err error) {
+	for k := range r.friends {
+		if k[0] == id {
+			friends = append(friends, Contact{CharacterID: k[1], Name: r.characters[k[1]]})
+		}
+	}
+	for k := range r.blocks {
+		if k[0] == id {
+			blocks = append(blocks, Contact{CharacterID: k[1], Name: r.characters[k[1]]})
+		}
+	}
+	return friends, blocks, nil
+}
+
+func (r *memRepo) FindCharacter(_ context.Context, name string) (Contact, bool, error) {
+	for id, n := range r.characters {
+		if strings.EqualFold(n, name) {
+			return Contact{CharacterID: id, Name: n}, true, nil
+		}
+	}
+	return Contact{}, false, nil
+}
+
+func (r *memRepo) SaveFriendship(_ context.Context, a, b int64) error {
+	r.friends[[2]int64{a, b}] = true
+	r.friends[[2]int64{b, a}] = true
+	return nil
+}
+
+func (r *memRepo) DeleteFriendship(_ context.Context, a, b int64) error {
+	delete(r.friends, [2]int64{a, b})
+	delete(r.friends, [2]int64{b, a})
+	return nil
+}
+
+func (r *memRepo) SaveBlock(_ context.Context, id, blockedID int64) error {
+	r.blocks[[2]int64{id, blockedID}] = true
+	return nil
+}
+
+func (r *memRepo) DeleteBlock(_ context.Context, id, blockedID int64) error {
+	delete(r.blocks, [2]int64{id, blockedID})
+	return nil
+}
+
+func attach(t *testing.T, m *Manager, players ...*model.Player) {
+	t.Helper()
+	for _, p := range players {
+		if _, err := m.Attach(context.Background(), p); err != nil {
+			t.Fatalf("Attach %s: %v", p.Name(), err)
+		}
+	}
+}
+
+func TestManager_InviteAndAnswer(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	m := NewManager(repo, DefaultConfig())
+	a := newTestPlayer(t, 1, "Alice")
+	b := newTestPlayer(t, 2, "Bob")
+	repo.characters[1], repo.characters[2] = "Alice", "Bob"
+	attach(t, m, a, b)
+
+	if err := m.Invite(a, a); !errors.Is(err, ErrSelfInvite) {
+		t.Errorf("self invite: err = %v", err)
+	}
+	if err := m.Invite(a, b); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if m.PendingRequester(b) != a {
+		t.Fatal("invitation was not registered")
+	}
+
+	requester, err := m.Answer(ctx, b, true)
+	if err != nil || requester != a {
+		t.Fatalf("Answer: requester=%v err=%v", requester, err)
+	}
+	if !m.IsFriend(a, 2) || !m.IsFriend(b, 1) {
+		t.Fatal("friendship must be mutual")
+	}
+	if err := m.Invite(a, b); !errors.Is(err, ErrAlreadyFriend) {
+		t.Errorf("repeated invite: err = %v", err)
+	}
+
+	// Списки восстанавливаются при следующем входе
+	if friends := m.Detach(a); len(friends) != 1 || friends[0].Name != "Bob" {
+		t.Errorf("Detach friends = %v", friends)
+	}
+	friends, err := m.Attach(ctx, a)
+	if err != nil || len(friends) != 1 || friends[0].CharacterID != 2 {
+		t.Fatalf("Attach friends = %v, err = %v", friends, err)
+	}
+
+	if _, err := m.Remove(ctx, a, "bob"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if m.IsFriend(a, 2) || m.IsFriend(b, 1) || len(repo.friends) != 0 {
+		t.Error("friendship should be removed on both sides")
+	}
+	if _, err := m.Remove(ctx, a, "Bob"); !errors.Is(err, ErrNotFriend) {
+		t.Errorf("second Remove: err = %v", err)
+	}
+}
+
+func TestManager_InviteLimits(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil, Config{MaxFriends: 1, MaxBlocks: 1})
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	a := newTestPlayer(t, 1, "Alice")
+	b := newTestPlayer(t, 2, "Bob")
+	c := newTestPlayer(t, 3, "Carol")
+	attach(t, m, a, b, c)
+
+	if err := m.Invite(a, b); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if err := m.Invite(c, b); !errors.Is(err, ErrTargetBusy) {
+		t.Errorf("busy target: err = %v", err)
+	}
+	if _, err := m.Answer(ctx, b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+	if err := m.Invite(a, c); !errors.Is(err, ErrFriendListFull) {
+		t.Errorf("full list: err = %v", err)
+	}
+	if err := m.Invite(c, b); !errors.Is(err, ErrTargetListFull) {
+		t.Errorf("full target list: err = %v", err)
+	}
+
+	if _, err := m.Remove(ctx, a, "Bob"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := m.Invite(a, c); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	now = now.Add(InviteTimeout + time.Second)
+	if _, err := m.Answer(ctx, c, true); !errors.Is(err, ErrInvitationExpired) {
+		t.Errorf("expired answer: err = %v", err)
+	}
+}
+
+func TestManager_BlockList(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	repo.characters[3] = "Offline"
+	m := NewManager(repo, DefaultConfig())
+	a := newTestPlayer(t, 1, "Alice")
+	b := newTestPlayer(t, 2, "Bob")
+	attach(t, m, a, b)
+
+	if _, err := m.Block(ctx, a, "Alice"); !errors.Is(err, ErrSelfBlock) {
+		t.Errorf("self block: err = %v", err)
+	}
+	if _, err := m.Block(ctx, a, "Nobody"); !errors.Is(err, ErrCharacterNotFound) {
+		t.Errorf("unknown character: err = %v", err)
+	}
+	if _, err := m.Block(ctx, a, "bob"); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if !m.IsBlocked(a, b) || m.IsBlocked(b, a) {
+		t.Error("block must be one-sided")
+	}
+	if err := m.Invite(b, a); !errors.Is(err, ErrBlocked) {
+		t.Errorf("invite from blocked player: err = %v", err)
+	}
+	if _, err := m.Block(ctx, a, "Bob"); !errors.Is(err, ErrAlreadyBlocked) {
+		t.Errorf("repeated block: err = %v", err)
+	}
+
+	// Офлайн-персонаж ищется в repository
+	if c, err := m.Block(ctx, a, "offline"); err != nil || c.CharacterID != 3 {
+		t.Fatalf("Block offline: contact=%v err=%v", c, err)
+	}
+	if blocks := m.Blocks(a); len(blocks) != 2 || blocks[0].Name != "Bob" {
+		t.Errorf("Blocks = %v", blocks)
+	}
+
+	if _, err := m.Unblock(ctx, a, "Bob"); err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	if m.IsBlocked(a, b) {
+		t.Error("player should be unblocked")
+	}
+
+	if err := m.SetBlockAll(b, true); err != nil {
+		t.Fatalf("SetBlockAll: %v", err)
+	}
+	if !m.IsBlocked(b, a) {
+		t.Error("block-all mode should refuse everyone")
+	}
+	_ = m.SetBlockAll(b, false)
+
+	if err := m.Invite(a, b); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, err := m.Answer(ctx, b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+	if _, err := m.Block(ctx, a, "Bob"); !errors.Is(err, ErrBlockFriend) {
+		t.Errorf("block friend: err = %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package friend
+
+import "context"
+
+// Contact — персонаж в списке друзей или в списке блокировки.
+type Contact struct {
+	CharacterID int64
+	Name        string
+}
+
+// Repository хранит списки друзей и блокировок между сессиями.
+// Дружба взаимна: SaveFriendship и DeleteFriendship изменяют записи обоих персонажей.
+type Repository interface {
+	LoadContacts(ctx context.Context, characterID int64) (friends, blocks []Contact, err error)
+	FindCharacter(ctx context.Context, name string) (Contact, bool, error)
+	SaveFriendship(ctx context.Context, a, b int64) error
+	DeleteFriendship(ctx context.Context, a, b int64) error
+	SaveBlock(ctx context.Context, characterID, blockedID int64) error
+	DeleteBlock(ctx context.Context, characterID, blockedID int64) error
+}
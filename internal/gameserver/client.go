@@ -7,8 +7,12 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/udisondev/la2go/internal/constants"
 	"github.com/udisondev/la2go/internal/crypto"
 	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/protocol"
+	"github.com/udisondev/la2go/internal/ratelimit"
 )
 
 // GameClient represents a single game client connection to the game server.
@@ -21,11 +25,26 @@ type GameClient struct {
 	// state использует atomic.Int32 для lock-free reads в hot path
 	state atomic.Int32
 
-	// mu защищает только accountName и sessionKey (редкие операции)
+	// mu защищает accountName, sessionKey и выбор персонажа (редкие операции)
 	mu          sync.Mutex
 	accountName string
 	sessionKey  *login.SessionKey
-	// activeChar будет добавлен позже в Phase 4.2 (model.Character)
+	characters  []*model.Player // персонажи аккаунта в порядке слотов экрана выбора
+	selected    *model.Player   // выбранный персонаж (nil до CharacterSelect)
+
+	// accessLevel — уровень доступа аккаунта (больше 0 — GM, см. admin.Access)
+	accessLevel atomic.Int32
+
+	// activePlayer — персонаж в игре (nil до EnterWorld)
+	activePlayer atomic.Pointer[model.Player]
+
+	// rateLimit — корзины лимитов пакетов; создаётся при первом пакете,
+	// используется только из read loop клиента
+	rateLimit *ratelimit.Client
+
+	// writeMu сериализует запись в conn: шифрование stateful,
+	// а пакеты могут отправляться из чужих goroutine (broadcast)
+	writeMu sync.Mutex
 }
 
 // NewGameClient creates a new game client state for the given connection.
@@ -111,6 +130,76 @@ func (c *GameClient) SetSessionKey(sk *login.SessionKey) {
 	c.sessionKey = sk
 }
 
+// AccessLevel returns the access level of the logged-in account.
+func (c *GameClient) AccessLevel() int32 {
+	return c.accessLevel.Load()
+}
+
+// SetAccessLevel sets the access level of the logged-in account.
+func (c *GameClient) SetAccessLevel(level int32) {
+	c.accessLevel.Store(level)
+}
+
+// Characters returns the characters of the account shown on the character selection screen.
+func (c *GameClient) Characters() []*model.Player {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.characters
+}
+
+// SetCharacters sets the characters of the account and clears the selection.
+func (c *GameClient) SetCharacters(chars []*model.Player) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.characters = chars
+	c.selected = nil
+}
+
+// SelectCharacter selects the character in slot for entering the world.
+// Returns false if the slot is empty.
+func (c *GameClient) SelectCharacter(slot int32) (*model.Player, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if slot < 0 || int(slot) >= len(c.characters) {
+		return nil, false
+	}
+	c.selected = c.characters[slot]
+	return c.selected, true
+}
+
+// SelectedCharacter returns the character selected for entering the world (nil if none).
+func (c *GameClient) SelectedCharacter() *model.Player {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.selected
+}
+
+// ActivePlayer returns the player currently controlled by this client (nil if not in game).
+func (c *GameClient) ActivePlayer() *model.Player {
+	return c.activePlayer.Load()
+}
+
+// SetActivePlayer binds the player to this client.
+func (c *GameClient) SetActivePlayer(p *model.Player) {
+	c.activePlayer.Store(p)
+}
+
+// SendPacket encrypts and sends a serialized packet (opcode + body).
+// Safe for concurrent use.
+func (c *GameClient) SendPacket(data []byte) error {
+	// Double padding: the first encrypted packet (XOR pass) needs up to 23 extra bytes
+	buf := make([]byte, constants.PacketHeaderSize+len(data)+2*constants.PacketBufferPadding)
+	copy(buf[constants.PacketHeaderSize:], data)
+	return c.writePacket(buf, len(data))
+}
+
+// writePacket encrypts and writes payload of length n located at buf[2:].
+func (c *GameClient) writePacket(buf []byte, n int) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return protocol.WritePacket(c.conn, c.encryption, buf, n)
+}
+
 // Close closes the connection.
 func (c *GameClient) Close() error {
 	// Проверяем state без lock (atomic read)
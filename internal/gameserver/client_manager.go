@@ -0,0 +1,104 @@
+package gameserver
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// ClientManager tracks in-game clients by player objectID and name.
+// Used to route packets to other players (broadcast, whispers, trade).
+type ClientManager struct {
+	byObjectID sync.Map // map[uint32]*GameClient
+	byName     sync.Map // map[string]*GameClient (lowercase name)
+}
+
+// NewClientManager creates an empty client registry.
+func NewClientManager() *ClientManager {
+	return &ClientManager{}
+}
+
+// Register binds the client's active player to the registry.
+func (m *ClientManager) Register(client *GameClient) {
+	p := client.ActivePlayer()
+	if p == nil {
+		return
+	}
+	m.byObjectID.Store(p.ObjectID(), client)
+	m.byName.Store(strings.ToLower(p.Name()), client)
+}
+
+// Unregister removes the player from the registry.
+// Only removes entries still pointing at the given client (protects re-login).
+func (m *ClientManager) Unregister(client *GameClient) {
+	p := client.ActivePlayer()
+	if p == nil {
+		return
+	}
+	m.byObjectID.CompareAndDelete(p.ObjectID(), client)
+	m.byName.CompareAndDelete(strings.ToLower(p.Name()), client)
+}
+
+// ByObjectID returns the client controlling the player with the given objectID.
+func (m *ClientManager) ByObjectID(objectID uint32) (*GameClient, bool) {
+	v, ok := m.byObjectID.Load(objectID)
+	if !ok {
+		return nil, false
+	}
+	return v.(*GameClient), true
+}
+
+// ByName returns the client of an online player (case-insensitive).
+func (m *ClientManager) ByName(name string) (*GameClient, bool) {
+	v, ok := m.byName.Load(strings.ToLower(name))
+	if !ok {
+		return nil, false
+	}
+	return v.(*GameClient), true
+}
+
+// ForEach iterates over all registered clients.
+// If fn returns false, iteration stops early.
+func (m *ClientManager) ForEach(fn func(*GameClient) bool) {
+	m.byObjectID.Range(func(_, v any) bool {
+		return fn(v.(*GameClient))
+	})
+}
+
+// Count returns the number of registered clients.
+func (m *ClientManager) Count() int {
+	n := 0
+	m.byObjectID.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// SendTo sends a packet to the player with the given objectID (if online).
+func (m *ClientManager) SendTo(objectID uint32, data []byte) bool {
+	client, ok := m.ByObjectID(objectID)
+	if !ok {
+		return false
+	}
+	if err := client.SendPacket(data); err != nil {
+		slog.Debug("failed to send packet", "objectID", objectID, "error", err)
+		return false
+	}
+	return true
+}
+
+// BroadcastToVisible sends a packet to all players that can see the given player
+// (3×3 region window), excluding the player itself.
+func (m *ClientManager) BroadcastToVisible(p *model.Player, data []byte) {
+	loc := p.Location()
+	world.ForEachVisibleObject(world.Instance(), loc.X, loc.Y, func(obj *model.WorldObject) bool {
+		if obj.ObjectID() != p.ObjectID() {
+			m.SendTo(obj.ObjectID(), data)
+		}
+		return true
+	})
+}
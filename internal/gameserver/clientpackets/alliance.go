@@ -0,0 +1,126 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestStartPledgeWar      = 0x4D
+	OpcodeRequestReplyStartPledgeWar = 0x4E
+	OpcodeRequestStopPledgeWar       = 0x4F
+	OpcodeRequestSurrenderPledgeWar  = 0x51
+	OpcodeRequestJoinAlly            = 0x82
+	OpcodeRequestAnswerJoinAlly      = 0x83
+	OpcodeAllyLeave                  = 0x84
+	OpcodeAllyDismiss                = 0x85
+	OpcodeRequestDismissAlly         = 0x86
+	OpcodeRequestSetAllyCrest        = 0x87
+	OpcodeRequestAllyCrest           = 0x88
+	OpcodeRequestAllyInfo            = 0x8E
+	ExOpcodeRequestPledgeWarList     = 0x1E
+)
+
+// Tabs of the clan war list window.
+const (
+	WarListDeclared    = 0 // wars declared by the clan
+	WarListUnderAttack = 1 // clans that declared war on the clan
+)
+
+// RequestJoinAlly is sent when the alliance leader invites a clan leader.
+// The answer (RequestAnswerJoinAlly, 0x83) has the RequestAnswerJoinPledge layout.
+//
+// Structure:
+// - int32: target objectID
+type RequestJoinAlly struct {
+	ObjectID uint32
+}
+
+// ParseRequestJoinAlly parses a RequestJoinAlly packet (without opcode).
+func ParseRequestJoinAlly(data []byte) (*RequestJoinAlly, error) {
+	r := packet.NewReader(data)
+
+	objectID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading target objectID: %w", err)
+	}
+
+	return &RequestJoinAlly{ObjectID: uint32(objectID)}, nil
+}
+
+// RequestPledgeName is a packet carrying a single clan name
+// (AllyDismiss, RequestStartPledgeWar, RequestStopPledgeWar, RequestSurrenderPledgeWar).
+//
+// Structure:
+// - string: clan name
+type RequestPledgeName struct {
+	Name string
+}
+
+// ParseRequestPledgeName parses a packet with a single clan name (without opcode).
+func ParseRequestPledgeName(data []byte) (*RequestPledgeName, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading clan name: %w", err)
+	}
+
+	return &RequestPledgeName{Name: name}, nil
+}
+
+// RequestReplyStartPledgeWar is a clan leader's answer to a war declaration.
+//
+// Structure:
+// - string: name of the declaring player
+// - int32: answer (1 = accept, 0 = decline)
+type RequestReplyStartPledgeWar struct {
+	Name   string
+	Accept bool
+}
+
+// ParseRequestReplyStartPledgeWar parses a RequestReplyStartPledgeWar packet (without opcode).
+func ParseRequestReplyStartPledgeWar(data []byte) (*RequestReplyStartPledgeWar, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading requester name: %w", err)
+	}
+	answer, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading answer: %w", err)
+	}
+
+	return &RequestReplyStartPledgeWar{Name: name, Accept: answer == 1}, nil
+}
+
+// RequestPledgeWarList requests a page of the clan war list (0xD0:0x1E).
+//
+// Structure:
+// - int32: page
+// - int32: tab (0 = declared, 1 = under attack)
+type RequestPledgeWarList struct {
+	Page int32
+	Tab  int32
+}
+
+// ParseRequestPledgeWarList parses the packet body (without opcodes).
+func ParseRequestPledgeWarList(data []byte) (*RequestPledgeWarList, error) {
+	r := packet.NewReader(data)
+
+	page, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading page: %w", err)
+	}
+	tab, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading tab: %w", err)
+	}
+	if tab != WarListDeclared && tab != WarListUnderAttack {
+		return nil, fmt.Errorf("invalid war list tab: %d", tab)
+	}
+
+	return &RequestPledgeWarList{Page: page, Tab: tab}, nil
+}
@@ -0,0 +1,58 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestReplyStartPledgeWar(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteString("Declarer")
+	w.WriteInt(1)
+
+	pkt, err := ParseRequestReplyStartPledgeWar(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestReplyStartPledgeWar: %v", err)
+	}
+	if pkt.Name != "Declarer" || !pkt.Accept {
+		t.Errorf("got %+v", pkt)
+	}
+}
+
+func TestParseRequestPledgeWarList(t *testing.T) {
+	w := packet.NewWriter(8)
+	w.WriteInt(0)
+	w.WriteInt(WarListUnderAttack)
+
+	pkt, err := ParseRequestPledgeWarList(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestPledgeWarList: %v", err)
+	}
+	if pkt.Page != 0 || pkt.Tab != WarListUnderAttack {
+		t.Errorf("got %+v", pkt)
+	}
+
+	w = packet.NewWriter(8)
+	w.WriteInt(0)
+	w.WriteInt(5)
+	if _, err := ParseRequestPledgeWarList(w.Bytes()); err == nil {
+		t.Error("expected error for invalid tab")
+	}
+}
+
+func TestParseRequestPledgeName(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteString("Enemies")
+
+	pkt, err := ParseRequestPledgeName(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestPledgeName: %v", err)
+	}
+	if pkt.Name != "Enemies" {
+		t.Errorf("Name = %q", pkt.Name)
+	}
+	if _, err := ParseRequestPledgeName(nil); err == nil {
+		t.Error("expected error for empty packet")
+	}
+}
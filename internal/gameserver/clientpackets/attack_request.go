@@ -0,0 +1,54 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeAttackRequest = 0x0A
+
+// AttackRequest is sent when the player attacks the selected object (Ctrl+click or the attack action).
+//
+// Structure:
+// - int32: target objectID
+// - int32: origin X
+// - int32: origin Y
+// - int32: origin Z
+// - byte: attack ID (0 = click, 1 = shift+click)
+type AttackRequest struct {
+	ObjectID int32
+	OriginX  int32
+	OriginY  int32
+	OriginZ  int32
+	ShiftHit bool
+}
+
+// ParseAttackRequest parses an AttackRequest packet (without opcode).
+func ParseAttackRequest(data []byte) (*AttackRequest, error) {
+	r := packet.NewReader(data)
+
+	var (
+		pkt AttackRequest
+		err error
+	)
+	if pkt.ObjectID, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading objectID: %w", err)
+	}
+	if pkt.OriginX, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading origin X: %w", err)
+	}
+	if pkt.OriginY, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading origin Y: %w", err)
+	}
+	if pkt.OriginZ, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading origin Z: %w", err)
+	}
+	shift, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("reading attack id: %w", err)
+	}
+	pkt.ShiftHit = shift == 1
+
+	return &pkt, nil
+}
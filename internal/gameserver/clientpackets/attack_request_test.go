@@ -0,0 +1,28 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseAttackRequest(t *testing.T) {
+	w := packet.NewWriter(17)
+	w.WriteInt(100002)
+	w.WriteInt(17000)
+	w.WriteInt(170000)
+	w.WriteInt(-3500)
+	_ = w.WriteByte(1)
+
+	pkt, err := ParseAttackRequest(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseAttackRequest: %v", err)
+	}
+	if pkt.ObjectID != 100002 || pkt.OriginX != 17000 || pkt.OriginY != 170000 || pkt.OriginZ != -3500 || !pkt.ShiftHit {
+		t.Errorf("AttackRequest = %+v", pkt)
+	}
+
+	if _, err := ParseAttackRequest(w.Bytes()[:16]); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
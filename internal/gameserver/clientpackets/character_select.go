@@ -0,0 +1,32 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeCharacterSelect = 0x0D
+	OpcodeEnterWorld      = 0x03
+)
+
+// CharacterSelect is sent when the player picks a character on the selection screen.
+//
+// Structure:
+// - int32: character slot
+// - int16, int32 x4: unknown (ignored)
+type CharacterSelect struct {
+	Slot int32
+}
+
+// ParseCharacterSelect parses a CharacterSelect packet (without opcode).
+func ParseCharacterSelect(data []byte) (*CharacterSelect, error) {
+	r := packet.NewReader(data)
+
+	slot, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading slot: %w", err)
+	}
+	return &CharacterSelect{Slot: slot}, nil
+}
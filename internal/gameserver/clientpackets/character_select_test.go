@@ -0,0 +1,28 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseCharacterSelect(t *testing.T) {
+	w := packet.NewWriter(22)
+	w.WriteInt(2)
+	w.WriteShort(0)
+	for range 4 {
+		w.WriteInt(0)
+	}
+
+	pkt, err := ParseCharacterSelect(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseCharacterSelect: %v", err)
+	}
+	if pkt.Slot != 2 {
+		t.Errorf("Slot = %d, want 2", pkt.Slot)
+	}
+
+	if _, err := ParseCharacterSelect(nil); err == nil {
+		t.Error("expected error for empty packet")
+	}
+}
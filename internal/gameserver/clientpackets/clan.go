@@ -0,0 +1,207 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestJoinPledge                  = 0x24
+	OpcodeRequestAnswerJoinPledge            = 0x25
+	OpcodeRequestWithdrawalPledge            = 0x26
+	OpcodeRequestOustPledgeMember            = 0x27
+	OpcodeRequestPledgeMemberList            = 0x3C
+	OpcodeRequestSetPledgeCrest              = 0x53
+	OpcodeRequestPledgeInfo                  = 0x66
+	OpcodeRequestPledgeCrest                 = 0x68
+	OpcodeRequestPledgePower                 = 0xC0
+	ExOpcodeRequestPledgeCrestLarge          = 0x10
+	ExOpcodeRequestSetPledgeCrestLarge       = 0x11
+	ExOpcodeRequestPledgeSetMemberPowerGrade = 0x15
+)
+
+// Actions of RequestPledgePower.
+const (
+	PledgePowerGet = 1
+	PledgePowerSet = 2
+)
+
+// MaxCrestDataSize limits crest uploads before they reach the clan manager.
+const MaxCrestDataSize = 2176
+
+// RequestJoinPledge is sent when a clan member invites the target to the clan.
+//
+// Structure:
+// - int32: target objectID
+// - int32: pledge type (0 = main clan, -1 = academy, 100/200 = royal guard, 1001.. = knights)
+type RequestJoinPledge struct {
+	ObjectID   uint32
+	PledgeType int32
+}
+
+// ParseRequestJoinPledge parses a RequestJoinPledge packet (without opcode).
+func ParseRequestJoinPledge(data []byte) (*RequestJoinPledge, error) {
+	r := packet.NewReader(data)
+
+	objectID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading target objectID: %w", err)
+	}
+	pledgeType, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading pledge type: %w", err)
+	}
+
+	return &RequestJoinPledge{ObjectID: uint32(objectID), PledgeType: pledgeType}, nil
+}
+
+// RequestAnswerJoinPledge is the invited player's answer.
+//
+// Structure:
+// - int32: response (1 = accept, 0 = decline)
+type RequestAnswerJoinPledge struct {
+	Accept bool
+}
+
+// ParseRequestAnswerJoinPledge parses a RequestAnswerJoinPledge packet (without opcode).
+func ParseRequestAnswerJoinPledge(data []byte) (*RequestAnswerJoinPledge, error) {
+	r := packet.NewReader(data)
+
+	response, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &RequestAnswerJoinPledge{Accept: response == 1}, nil
+}
+
+// RequestOustPledgeMember expels a member from the clan.
+//
+// Structure:
+// - string: member name
+type RequestOustPledgeMember struct {
+	Name string
+}
+
+// ParseRequestOustPledgeMember parses a RequestOustPledgeMember packet (without opcode).
+func ParseRequestOustPledgeMember(data []byte) (*RequestOustPledgeMember, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading member name: %w", err)
+	}
+
+	return &RequestOustPledgeMember{Name: name}, nil
+}
+
+// RequestPledgeID is a packet carrying a single clan or crest ID
+// (RequestPledgeInfo, RequestPledgeCrest, RequestExPledgeCrestLarge).
+//
+// Structure:
+// - int32: ID
+type RequestPledgeID struct {
+	ID int32
+}
+
+// ParseRequestPledgeID parses a packet with a single clan or crest ID (without opcode).
+func ParseRequestPledgeID(data []byte) (*RequestPledgeID, error) {
+	r := packet.NewReader(data)
+
+	id, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading ID: %w", err)
+	}
+
+	return &RequestPledgeID{ID: id}, nil
+}
+
+// RequestSetPledgeCrest uploads a clan crest (also used for the large crest, 0xD0:0x11).
+// Zero length removes the crest.
+//
+// Structure:
+// - int32: data length
+// - bytes: crest image (DDS)
+type RequestSetPledgeCrest struct {
+	Data []byte
+}
+
+// ParseRequestSetPledgeCrest parses a crest upload packet (without opcode).
+func ParseRequestSetPledgeCrest(data []byte) (*RequestSetPledgeCrest, error) {
+	r := packet.NewReader(data)
+
+	length, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading crest length: %w", err)
+	}
+	if length < 0 || length > MaxCrestDataSize {
+		return nil, fmt.Errorf("invalid crest length: %d", length)
+	}
+	crest, err := r.ReadBytesCopy(int(length))
+	if err != nil {
+		return nil, fmt.Errorf("reading crest data: %w", err)
+	}
+
+	return &RequestSetPledgeCrest{Data: crest}, nil
+}
+
+// RequestPledgePower reads or changes privileges of a clan rank.
+//
+// Structure:
+// - int32: rank (power grade)
+// - int32: action (1 = get, 2 = set)
+// - int32: privileges (only for action = 2)
+type RequestPledgePower struct {
+	Rank       int32
+	Action     int32
+	Privileges int32
+}
+
+// ParseRequestPledgePower parses a RequestPledgePower packet (without opcode).
+func ParseRequestPledgePower(data []byte) (*RequestPledgePower, error) {
+	r := packet.NewReader(data)
+
+	rank, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading rank: %w", err)
+	}
+	action, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading action: %w", err)
+	}
+
+	pkt := &RequestPledgePower{Rank: rank, Action: action}
+	if action == PledgePowerSet {
+		if pkt.Privileges, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading privileges: %w", err)
+		}
+	}
+	return pkt, nil
+}
+
+// RequestPledgeSetMemberPowerGrade assigns a rank to a clan member (0xD0:0x15).
+//
+// Structure:
+// - string: member name
+// - int32: power grade
+type RequestPledgeSetMemberPowerGrade struct {
+	Name       string
+	PowerGrade int32
+}
+
+// ParseRequestPledgeSetMemberPowerGrade parses the packet body (without opcodes).
+func ParseRequestPledgeSetMemberPowerGrade(data []byte) (*RequestPledgeSetMemberPowerGrade, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading member name: %w", err)
+	}
+	grade, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading power grade: %w", err)
+	}
+
+	return &RequestPledgeSetMemberPowerGrade{Name: name, PowerGrade: grade}, nil
+}
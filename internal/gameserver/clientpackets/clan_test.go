@@ -0,0 +1,86 @@
+package clientpackets
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestJoinPledge(t *testing.T) {
+	w := packet.NewWriter(8)
+	w.WriteInt(42)
+	w.WriteInt(-1)
+
+	pkt, err := ParseRequestJoinPledge(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestJoinPledge: %v", err)
+	}
+	if pkt.ObjectID != 42 || pkt.PledgeType != -1 {
+		t.Errorf("got %+v", pkt)
+	}
+}
+
+func TestParseRequestSetPledgeCrest(t *testing.T) {
+	crest := []byte{1, 2, 3, 4, 5}
+	w := packet.NewWriter(16)
+	w.WriteInt(int32(len(crest)))
+	w.WriteBytes(crest)
+
+	pkt, err := ParseRequestSetPledgeCrest(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestSetPledgeCrest: %v", err)
+	}
+	if !bytes.Equal(pkt.Data, crest) {
+		t.Errorf("Data = %v, want %v", pkt.Data, crest)
+	}
+
+	// Zero length removes the crest.
+	w = packet.NewWriter(4)
+	w.WriteInt(0)
+	if pkt, err = ParseRequestSetPledgeCrest(w.Bytes()); err != nil || len(pkt.Data) != 0 {
+		t.Errorf("empty crest: pkt=%+v err=%v", pkt, err)
+	}
+
+	w = packet.NewWriter(4)
+	w.WriteInt(MaxCrestDataSize + 1)
+	if _, err := ParseRequestSetPledgeCrest(w.Bytes()); err == nil {
+		t.Error("expected error for oversized crest")
+	}
+}
+
+func TestParseRequestPledgePower(t *testing.T) {
+	w := packet.NewWriter(12)
+	w.WriteInt(5)
+	w.WriteInt(PledgePowerSet)
+	w.WriteInt(0x42)
+
+	pkt, err := ParseRequestPledgePower(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestPledgePower: %v", err)
+	}
+	if pkt.Rank != 5 || pkt.Action != PledgePowerSet || pkt.Privileges != 0x42 {
+		t.Errorf("got %+v", pkt)
+	}
+
+	w = packet.NewWriter(8)
+	w.WriteInt(5)
+	w.WriteInt(PledgePowerGet)
+	if pkt, err = ParseRequestPledgePower(w.Bytes()); err != nil || pkt.Privileges != 0 {
+		t.Errorf("get: pkt=%+v err=%v", pkt, err)
+	}
+}
+
+func TestParseRequestPledgeSetMemberPowerGrade(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteString("Member")
+	w.WriteInt(7)
+
+	pkt, err := ParseRequestPledgeSetMemberPowerGrade(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestPledgeSetMemberPowerGrade: %v", err)
+	}
+	if pkt.Name != "Member" || pkt.PowerGrade != 7 {
+		t.Errorf("got %+v", pkt)
+	}
+}
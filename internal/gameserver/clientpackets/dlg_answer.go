@@ -0,0 +1,44 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeDlgAnswer = 0xC5
+
+// DlgAnswer is the player's answer to ConfirmDlg.
+//
+// Structure:
+// - int32: message ID of the dialog
+// - int32: answer (1 = yes)
+// - int32: objectID of the requester
+type DlgAnswer struct {
+	MessageID   int32
+	Accepted    bool
+	RequesterID int32
+}
+
+// ParseDlgAnswer parses a DlgAnswer packet (without opcode).
+func ParseDlgAnswer(data []byte) (*DlgAnswer, error) {
+	r := packet.NewReader(data)
+
+	var (
+		pkt DlgAnswer
+		err error
+	)
+	if pkt.MessageID, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading message ID: %w", err)
+	}
+	answer, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading answer: %w", err)
+	}
+	pkt.Accepted = answer == 1
+	if pkt.RequesterID, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading requester ID: %w", err)
+	}
+
+	return &pkt, nil
+}
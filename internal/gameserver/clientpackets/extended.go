@@ -0,0 +1,23 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+// OpcodeExtended prefixes extended client packets (0xD0 + int16 sub-opcode).
+const OpcodeExtended = 0xD0
+
+// ParseExtendedOpcode reads the sub-opcode of an extended packet.
+// Returns the sub-opcode and the remaining body.
+func ParseExtendedOpcode(data []byte) (int16, []byte, error) {
+	r := packet.NewReader(data)
+
+	sub, err := r.ReadShort()
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading extended opcode: %w", err)
+	}
+
+	return sub, data[r.Position():], nil
+}
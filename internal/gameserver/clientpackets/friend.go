@@ -0,0 +1,96 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestFriendInvite       = 0x5E
+	OpcodeRequestAnswerFriendInvite = 0x5F
+	OpcodeRequestFriendList         = 0x60
+	OpcodeRequestFriendDel          = 0x61
+	OpcodeRequestBlock              = 0xA0
+)
+
+// RequestBlock commands (/block, /unblock, /blocklist, /allblock, /allunblock).
+const (
+	BlockAdd      = 0
+	BlockRemove   = 1
+	BlockList     = 2
+	BlockAll      = 3
+	BlockAllClear = 4
+)
+
+// RequestFriendName is a packet carrying a single character name
+// (RequestFriendInvite, RequestFriendDel).
+//
+// Structure:
+// - string: character name
+type RequestFriendName struct {
+	Name string
+}
+
+// ParseRequestFriendName parses a packet with a single character name (without opcode).
+func ParseRequestFriendName(data []byte) (*RequestFriendName, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading character name: %w", err)
+	}
+
+	return &RequestFriendName{Name: name}, nil
+}
+
+// RequestAnswerFriendInvite is the invited player's answer.
+//
+// Structure:
+// - int32: response (1 = accept, 0 = decline)
+type RequestAnswerFriendInvite struct {
+	Accept bool
+}
+
+// ParseRequestAnswerFriendInvite parses a RequestAnswerFriendInvite packet (without opcode).
+func ParseRequestAnswerFriendInvite(data []byte) (*RequestAnswerFriendInvite, error) {
+	r := packet.NewReader(data)
+
+	response, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &RequestAnswerFriendInvite{Accept: response == 1}, nil
+}
+
+// RequestBlock manages the block list.
+//
+// Structure:
+// - int32: command (BlockAdd..BlockAllClear)
+// - string: character name (only for BlockAdd and BlockRemove)
+type RequestBlock struct {
+	Type int32
+	Name string
+}
+
+// ParseRequestBlock parses a RequestBlock packet (without opcode).
+func ParseRequestBlock(data []byte) (*RequestBlock, error) {
+	r := packet.NewReader(data)
+
+	typ, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading block command: %w", err)
+	}
+	if typ < BlockAdd || typ > BlockAllClear {
+		return nil, fmt.Errorf("invalid block command %d", typ)
+	}
+
+	pkt := &RequestBlock{Type: typ}
+	if typ == BlockAdd || typ == BlockRemove {
+		if pkt.Name, err = r.ReadString(); err != nil {
+			return nil, fmt.Errorf("reading character name: %w", err)
+		}
+	}
+	return pkt, nil
+}
@@ -0,0 +1,53 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestBlock(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteInt(BlockAdd)
+	w.WriteString("Spammer")
+
+	pkt, err := ParseRequestBlock(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestBlock: %v", err)
+	}
+	if pkt.Type != BlockAdd || pkt.Name != "Spammer" {
+		t.Errorf("got %+v", pkt)
+	}
+
+	// List commands carry no name
+	w = packet.NewWriter(4)
+	w.WriteInt(BlockAll)
+	if pkt, err = ParseRequestBlock(w.Bytes()); err != nil || pkt.Type != BlockAll || pkt.Name != "" {
+		t.Errorf("BlockAll: pkt=%+v err=%v", pkt, err)
+	}
+
+	w = packet.NewWriter(4)
+	w.WriteInt(9)
+	if _, err := ParseRequestBlock(w.Bytes()); err == nil {
+		t.Error("expected error for invalid command")
+	}
+
+	w = packet.NewWriter(4)
+	w.WriteInt(BlockRemove)
+	if _, err := ParseRequestBlock(w.Bytes()); err == nil {
+		t.Error("expected error for missing name")
+	}
+}
+
+func TestParseRequestAnswerFriendInvite(t *testing.T) {
+	w := packet.NewWriter(4)
+	w.WriteInt(1)
+
+	pkt, err := ParseRequestAnswerFriendInvite(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestAnswerFriendInvite: %v", err)
+	}
+	if !pkt.Accept {
+		t.Error("Accept = false, want true")
+	}
+}
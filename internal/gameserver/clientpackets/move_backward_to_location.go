@@ -0,0 +1,53 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeMoveBackwardToLocation = 0x01
+
+// Movement sources of MoveBackwardToLocation.
+const (
+	MoveByKeyboard = 0
+	MoveByMouse    = 1
+)
+
+// MoveBackwardToLocation is sent when the player clicks on the ground to walk there.
+//
+// Structure:
+// - int32: target x, y, z
+// - int32: origin x, y, z
+// - int32: movement source (0 = keyboard, 1 = mouse; missing in some clients)
+type MoveBackwardToLocation struct {
+	Target   model.Location
+	Origin   model.Location
+	Movement int32
+}
+
+// ParseMoveBackwardToLocation parses a MoveBackwardToLocation packet (without opcode).
+func ParseMoveBackwardToLocation(data []byte) (*MoveBackwardToLocation, error) {
+	r := packet.NewReader(data)
+
+	var coords [6]int32
+	for i := range coords {
+		v, err := r.ReadInt()
+		if err != nil {
+			return nil, fmt.Errorf("reading coordinates: %w", err)
+		}
+		coords[i] = v
+	}
+
+	pkt := &MoveBackwardToLocation{
+		Target:   model.NewLocation(coords[0], coords[1], coords[2], 0),
+		Origin:   model.NewLocation(coords[3], coords[4], coords[5], 0),
+		Movement: MoveByMouse,
+	}
+	// L2J: the movement flag is optional (older clients and bots omit it)
+	if movement, err := r.ReadInt(); err == nil {
+		pkt.Movement = movement
+	}
+	return pkt, nil
+}
@@ -0,0 +1,42 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestParseMoveBackwardToLocation(t *testing.T) {
+	w := packet.NewWriter(28)
+	for _, v := range []int32{17500, 170200, -3490, 17000, 170000, -3500, MoveByKeyboard} {
+		w.WriteInt(v)
+	}
+
+	pkt, err := ParseMoveBackwardToLocation(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseMoveBackwardToLocation: %v", err)
+	}
+	if pkt.Target != model.NewLocation(17500, 170200, -3490, 0) {
+		t.Errorf("Target = %v", pkt.Target)
+	}
+	if pkt.Origin != model.NewLocation(17000, 170000, -3500, 0) {
+		t.Errorf("Origin = %v", pkt.Origin)
+	}
+	if pkt.Movement != MoveByKeyboard {
+		t.Errorf("Movement = %d, want keyboard", pkt.Movement)
+	}
+
+	// Without the movement flag
+	pkt, err = ParseMoveBackwardToLocation(w.Bytes()[:24])
+	if err != nil {
+		t.Fatalf("ParseMoveBackwardToLocation without flag: %v", err)
+	}
+	if pkt.Movement != MoveByMouse {
+		t.Errorf("default Movement = %d, want mouse", pkt.Movement)
+	}
+
+	if _, err := ParseMoveBackwardToLocation(w.Bytes()[:20]); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
@@ -0,0 +1,82 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestJoinParty           = 0x29
+	OpcodeRequestAnswerJoinParty     = 0x2A
+	OpcodeRequestWithDrawalParty     = 0x2B
+	OpcodeRequestOustPartyMember     = 0x2C
+	ExOpcodeRequestChangePartyLeader = 0x04
+)
+
+// RequestJoinParty is sent when the player invites another player to a party.
+//
+// Structure:
+// - string: target name
+// - int32: item distribution (loot rule)
+type RequestJoinParty struct {
+	Name             string
+	ItemDistribution int32
+}
+
+// ParseRequestJoinParty parses a RequestJoinParty packet (without opcode).
+func ParseRequestJoinParty(data []byte) (*RequestJoinParty, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading target name: %w", err)
+	}
+	distribution, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading item distribution: %w", err)
+	}
+
+	return &RequestJoinParty{Name: name, ItemDistribution: distribution}, nil
+}
+
+// RequestAnswerJoinParty is the invited player's answer.
+//
+// Structure:
+// - int32: response (1 = accept, 0 = decline)
+type RequestAnswerJoinParty struct {
+	Accept bool
+}
+
+// ParseRequestAnswerJoinParty parses a RequestAnswerJoinParty packet (without opcode).
+func ParseRequestAnswerJoinParty(data []byte) (*RequestAnswerJoinParty, error) {
+	r := packet.NewReader(data)
+
+	response, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &RequestAnswerJoinParty{Accept: response == 1}, nil
+}
+
+// RequestPartyMemberName is a packet carrying a single party member name
+// (RequestOustPartyMember, RequestChangePartyLeader).
+//
+// Structure:
+// - string: member name
+type RequestPartyMemberName struct {
+	Name string
+}
+
+// ParseRequestPartyMemberName parses a packet with a single member name (without opcode).
+func ParseRequestPartyMemberName(data []byte) (*RequestPartyMemberName, error) {
+	r := packet.NewReader(data)
+
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading member name: %w", err)
+	}
+
+	return &RequestPartyMemberName{Name: name}, nil
+}
@@ -0,0 +1,90 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestJoinParty(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteString("Target")
+	w.WriteInt(4)
+
+	pkt, err := ParseRequestJoinParty(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestJoinParty: %v", err)
+	}
+	if pkt.Name != "Target" || pkt.ItemDistribution != 4 {
+		t.Errorf("got %+v", pkt)
+	}
+}
+
+func TestParseRequestAnswerJoinParty(t *testing.T) {
+	for _, tt := range []struct {
+		in   int32
+		want bool
+	}{{1, true}, {0, false}} {
+		w := packet.NewWriter(4)
+		w.WriteInt(tt.in)
+		pkt, err := ParseRequestAnswerJoinParty(w.Bytes())
+		if err != nil {
+			t.Fatalf("ParseRequestAnswerJoinParty: %v", err)
+		}
+		if pkt.Accept != tt.want {
+			t.Errorf("Accept(%d) = %v, want %v", tt.in, pkt.Accept, tt.want)
+		}
+	}
+
+	if _, err := ParseRequestAnswerJoinParty(nil); err == nil {
+		t.Error("expected error for empty packet")
+	}
+}
+
+func TestParseExtendedOpcode(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteShort(ExOpcodeRequestChangePartyLeader)
+	w.WriteString("Member")
+
+	sub, body, err := ParseExtendedOpcode(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseExtendedOpcode: %v", err)
+	}
+	if sub != ExOpcodeRequestChangePartyLeader {
+		t.Fatalf("sub = 0x%02X", sub)
+	}
+	pkt, err := ParseRequestPartyMemberName(body)
+	if err != nil {
+		t.Fatalf("ParseRequestPartyMemberName: %v", err)
+	}
+	if pkt.Name != "Member" {
+		t.Errorf("Name = %q, want Member", pkt.Name)
+	}
+}
+
+func TestParseSay2(t *testing.T) {
+	w := packet.NewWriter(64)
+	w.WriteString("hello")
+	w.WriteInt(ChatTell)
+	w.WriteString("Friend")
+
+	pkt, err := ParseSay2(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSay2: %v", err)
+	}
+	if pkt.Text != "hello" || pkt.Type != ChatTell || pkt.Target != "Friend" {
+		t.Errorf("got %+v", pkt)
+	}
+
+	// Target is only present for tells
+	w = packet.NewWriter(32)
+	w.WriteString("hi all")
+	w.WriteInt(ChatAll)
+	pkt, err = ParseSay2(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSay2: %v", err)
+	}
+	if pkt.Target != "" {
+		t.Errorf("Target = %q, want empty", pkt.Target)
+	}
+}
@@ -0,0 +1,147 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestPrivateStoreManageBuy = 0x90
+	OpcodeSetPrivateStoreListBuy       = 0x91
+	OpcodeRequestPrivateStoreQuitBuy   = 0x93
+	OpcodeSetPrivateStoreMsgBuy        = 0x94
+	OpcodeRequestPrivateStoreSell      = 0x96
+)
+
+// BuyStoreEntry — позиция buy-магазина.
+type BuyStoreEntry struct {
+	ItemID  int32
+	Enchant int16
+	Count   int32
+	Price   int32
+}
+
+// SetPrivateStoreListBuy is sent when the player confirms the buy store list.
+//
+// Structure:
+// - int32: item count
+// - for each item: int32 itemID, int16 enchant, int16 unknown, int32 count, int32 price
+type SetPrivateStoreListBuy struct {
+	Items []BuyStoreEntry
+}
+
+// ParseSetPrivateStoreListBuy parses a SetPrivateStoreListBuy packet (without opcode).
+func ParseSetPrivateStoreListBuy(data []byte) (*SetPrivateStoreListBuy, error) {
+	r := packet.NewReader(data)
+
+	count, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading item count: %w", err)
+	}
+	if count < 0 || count > MaxStoreItems {
+		return nil, fmt.Errorf("invalid item count: %d", count)
+	}
+
+	items := make([]BuyStoreEntry, 0, count)
+	seen := make(map[int32]struct{}, count)
+	for i := range count {
+		var e BuyStoreEntry
+		if e.ItemID, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d id: %w", i, err)
+		}
+		if e.Enchant, err = r.ReadShort(); err != nil {
+			return nil, fmt.Errorf("reading item %d enchant: %w", i, err)
+		}
+		if _, err = r.ReadShort(); err != nil {
+			return nil, fmt.Errorf("reading item %d unknown: %w", i, err)
+		}
+		if e.Count, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d count: %w", i, err)
+		}
+		if e.Price, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d price: %w", i, err)
+		}
+		if _, dup := seen[e.ItemID]; dup {
+			return nil, fmt.Errorf("item %d: duplicate itemID %d", i, e.ItemID)
+		}
+		seen[e.ItemID] = struct{}{}
+		items = append(items, e)
+	}
+
+	return &SetPrivateStoreListBuy{Items: items}, nil
+}
+
+// SellToStoreEntry — предмет, продаваемый в buy-магазин.
+type SellToStoreEntry struct {
+	ObjectID int32
+	ItemID   int32
+	Count    int32
+	Price    int32
+}
+
+// RequestPrivateStoreSell is sent when the player sells items to another player's buy store.
+//
+// Structure:
+// - int32: store player objectID
+// - int32: item count
+// - for each item: int32 objectID, int32 itemID, int16 enchant, int16 unknown, int32 count, int32 price
+type RequestPrivateStoreSell struct {
+	StorePlayerID int32
+	Items         []SellToStoreEntry
+}
+
+// ParseRequestPrivateStoreSell parses a RequestPrivateStoreSell packet (without opcode).
+func ParseRequestPrivateStoreSell(data []byte) (*RequestPrivateStoreSell, error) {
+	r := packet.NewReader(data)
+
+	storePlayerID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading store player id: %w", err)
+	}
+
+	count, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading item count: %w", err)
+	}
+	if count < 0 || count > MaxStoreItems {
+		return nil, fmt.Errorf("invalid item count: %d", count)
+	}
+
+	items := make([]SellToStoreEntry, 0, count)
+	seenObjects := make(map[int32]struct{}, count)
+	seenItems := make(map[int32]struct{}, count)
+	for i := range count {
+		var e SellToStoreEntry
+		if e.ObjectID, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d objectID: %w", i, err)
+		}
+		if e.ItemID, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d id: %w", i, err)
+		}
+		// enchant + unknown
+		if _, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d enchant: %w", i, err)
+		}
+		if e.Count, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d count: %w", i, err)
+		}
+		if e.Price, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d price: %w", i, err)
+		}
+		if _, dup := seenObjects[e.ObjectID]; dup {
+			return nil, fmt.Errorf("item %d: duplicate objectID %d", i, e.ObjectID)
+		}
+		if _, dup := seenItems[e.ItemID]; dup {
+			return nil, fmt.Errorf("item %d: duplicate itemID %d", i, e.ItemID)
+		}
+		seenObjects[e.ObjectID] = struct{}{}
+		seenItems[e.ItemID] = struct{}{}
+		items = append(items, e)
+	}
+
+	return &RequestPrivateStoreSell{
+		StorePlayerID: storePlayerID,
+		Items:         items,
+	}, nil
+}
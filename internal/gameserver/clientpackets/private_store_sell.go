@@ -0,0 +1,140 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestPrivateStoreManageSell = 0x73
+	OpcodeSetPrivateStoreListSell       = 0x74
+	OpcodeRequestPrivateStoreQuitSell   = 0x76
+	OpcodeSetPrivateStoreMsgSell        = 0x77
+	OpcodeRequestPrivateStoreBuy        = 0x79
+)
+
+// MaxStoreItems ограничивает количество позиций в пакетах магазина
+// (защита от аллокации по count из пакета).
+const MaxStoreItems = 100
+
+// StoreItemEntry — позиция в пакетах sell-магазина.
+type StoreItemEntry struct {
+	ObjectID int32
+	Count    int32
+	Price    int32
+}
+
+// SetPrivateStoreListSell is sent when the player confirms the sell store list.
+//
+// Structure:
+// - int32: package sale (1 = sell all items as one package)
+// - int32: item count
+// - for each item: int32 objectID, int32 count, int32 price
+type SetPrivateStoreListSell struct {
+	PackageSale bool
+	Items       []StoreItemEntry
+}
+
+// ParseSetPrivateStoreListSell parses a SetPrivateStoreListSell packet (without opcode).
+func ParseSetPrivateStoreListSell(data []byte) (*SetPrivateStoreListSell, error) {
+	r := packet.NewReader(data)
+
+	packageSale, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading package sale flag: %w", err)
+	}
+
+	items, err := readStoreItems(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetPrivateStoreListSell{
+		PackageSale: packageSale == 1,
+		Items:       items,
+	}, nil
+}
+
+// RequestPrivateStoreBuy is sent when the player buys from another player's sell store.
+//
+// Structure:
+// - int32: store player objectID
+// - int32: item count
+// - for each item: int32 objectID, int32 count, int32 price
+type RequestPrivateStoreBuy struct {
+	StorePlayerID int32
+	Items         []StoreItemEntry
+}
+
+// ParseRequestPrivateStoreBuy parses a RequestPrivateStoreBuy packet (without opcode).
+func ParseRequestPrivateStoreBuy(data []byte) (*RequestPrivateStoreBuy, error) {
+	r := packet.NewReader(data)
+
+	storePlayerID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading store player id: %w", err)
+	}
+
+	items, err := readStoreItems(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestPrivateStoreBuy{
+		StorePlayerID: storePlayerID,
+		Items:         items,
+	}, nil
+}
+
+// SetPrivateStoreMsg is the store message packet (SetPrivateStoreMsgSell,
+// SetPrivateStoreMsgBuy, RequestRecipeShopMessageSet share the layout).
+//
+// Structure:
+// - string: message (UTF-16LE null-terminated)
+type SetPrivateStoreMsg struct {
+	Message string
+}
+
+// ParseSetPrivateStoreMsg parses a store message packet (without opcode).
+func ParseSetPrivateStoreMsg(data []byte) (*SetPrivateStoreMsg, error) {
+	r := packet.NewReader(data)
+
+	msg, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading store message: %w", err)
+	}
+
+	return &SetPrivateStoreMsg{Message: msg}, nil
+}
+
+func readStoreItems(r *packet.Reader) ([]StoreItemEntry, error) {
+	count, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading item count: %w", err)
+	}
+	if count < 0 || count > MaxStoreItems {
+		return nil, fmt.Errorf("invalid item count: %d", count)
+	}
+
+	items := make([]StoreItemEntry, 0, count)
+	seen := make(map[int32]struct{}, count)
+	for i := range count {
+		var e StoreItemEntry
+		if e.ObjectID, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d objectID: %w", i, err)
+		}
+		if e.Count, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d count: %w", i, err)
+		}
+		if e.Price, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading item %d price: %w", i, err)
+		}
+		if _, dup := seen[e.ObjectID]; dup {
+			return nil, fmt.Errorf("item %d: duplicate objectID %d", i, e.ObjectID)
+		}
+		seen[e.ObjectID] = struct{}{}
+		items = append(items, e)
+	}
+	return items, nil
+}
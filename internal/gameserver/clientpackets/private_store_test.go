@@ -0,0 +1,168 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseSetPrivateStoreListSell(t *testing.T) {
+	w := packet.NewWriter(64)
+	w.WriteInt(1) // package sale
+	w.WriteInt(2)
+	w.WriteInt(100)
+	w.WriteInt(5)
+	w.WriteInt(1000)
+	w.WriteInt(200)
+	w.WriteInt(1)
+	w.WriteInt(50)
+
+	pkt, err := ParseSetPrivateStoreListSell(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSetPrivateStoreListSell: %v", err)
+	}
+	if !pkt.PackageSale {
+		t.Error("PackageSale = false, want true")
+	}
+	if len(pkt.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(pkt.Items))
+	}
+	want := StoreItemEntry{ObjectID: 200, Count: 1, Price: 50}
+	if pkt.Items[1] != want {
+		t.Errorf("Items[1] = %+v, want %+v", pkt.Items[1], want)
+	}
+}
+
+func TestParseSetPrivateStoreListSell_InvalidCount(t *testing.T) {
+	for _, count := range []int32{-1, MaxStoreItems + 1} {
+		w := packet.NewWriter(16)
+		w.WriteInt(0)
+		w.WriteInt(count)
+		if _, err := ParseSetPrivateStoreListSell(w.Bytes()); err == nil {
+			t.Errorf("count %d: expected error", count)
+		}
+	}
+}
+
+func TestParseSetPrivateStoreListSell_Truncated(t *testing.T) {
+	w := packet.NewWriter(16)
+	w.WriteInt(0)
+	w.WriteInt(1)
+	w.WriteInt(100) // objectID only
+
+	if _, err := ParseSetPrivateStoreListSell(w.Bytes()); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
+
+func TestParseStorePackets_Duplicates(t *testing.T) {
+	buy := packet.NewWriter(32)
+	buy.WriteInt(9001) // store player
+	buy.WriteInt(2)
+	for range 2 {
+		buy.WriteInt(100) // same objectID
+		buy.WriteInt(1)
+		buy.WriteInt(10)
+	}
+	if _, err := ParseRequestPrivateStoreBuy(buy.Bytes()); err == nil {
+		t.Error("RequestPrivateStoreBuy: expected error for duplicate objectID")
+	}
+
+	sellItem := func(w *packet.Writer, objectID, itemID int32) {
+		w.WriteInt(objectID)
+		w.WriteInt(itemID)
+		w.WriteInt(0) // enchant + unknown
+		w.WriteInt(1)
+		w.WriteInt(10)
+	}
+	for _, tc := range []struct {
+		name    string
+		objects [2]int32
+		items   [2]int32
+	}{
+		{"same objectID", [2]int32{300, 300}, [2]int32{17, 18}},
+		{"same itemID", [2]int32{300, 301}, [2]int32{17, 17}},
+	} {
+		w := packet.NewWriter(64)
+		w.WriteInt(9001)
+		w.WriteInt(2)
+		sellItem(w, tc.objects[0], tc.items[0])
+		sellItem(w, tc.objects[1], tc.items[1])
+		if _, err := ParseRequestPrivateStoreSell(w.Bytes()); err == nil {
+			t.Errorf("RequestPrivateStoreSell %s: expected error", tc.name)
+		}
+	}
+}
+
+func TestParseSetPrivateStoreListBuy(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteInt(1)
+	w.WriteInt(17)  // itemID
+	w.WriteShort(3) // enchant
+	w.WriteShort(0)
+	w.WriteInt(500)
+	w.WriteInt(2)
+
+	pkt, err := ParseSetPrivateStoreListBuy(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSetPrivateStoreListBuy: %v", err)
+	}
+	want := BuyStoreEntry{ItemID: 17, Enchant: 3, Count: 500, Price: 2}
+	if len(pkt.Items) != 1 || pkt.Items[0] != want {
+		t.Errorf("Items = %+v, want [%+v]", pkt.Items, want)
+	}
+}
+
+func TestParseRequestPrivateStoreSell(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteInt(9001) // store player
+	w.WriteInt(1)
+	w.WriteInt(300) // objectID
+	w.WriteInt(17)  // itemID
+	w.WriteShort(0)
+	w.WriteShort(0)
+	w.WriteInt(10)
+	w.WriteInt(4)
+
+	pkt, err := ParseRequestPrivateStoreSell(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestPrivateStoreSell: %v", err)
+	}
+	if pkt.StorePlayerID != 9001 {
+		t.Errorf("StorePlayerID = %d, want 9001", pkt.StorePlayerID)
+	}
+	want := SellToStoreEntry{ObjectID: 300, ItemID: 17, Count: 10, Price: 4}
+	if len(pkt.Items) != 1 || pkt.Items[0] != want {
+		t.Errorf("Items = %+v, want [%+v]", pkt.Items, want)
+	}
+}
+
+func TestParseRequestRecipeShopListSet(t *testing.T) {
+	w := packet.NewWriter(32)
+	w.WriteInt(1)
+	w.WriteInt(42)
+	w.WriteInt(1000)
+
+	pkt, err := ParseRequestRecipeShopListSet(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestRecipeShopListSet: %v", err)
+	}
+	if len(pkt.Items) != 1 || pkt.Items[0] != (RecipeShopEntry{RecipeID: 42, Cost: 1000}) {
+		t.Errorf("Items = %+v", pkt.Items)
+	}
+}
+
+func TestParseRequestActionUse(t *testing.T) {
+	w := packet.NewWriter(16)
+	w.WriteInt(ActionPrivateStoreSell)
+	w.WriteInt(1)
+	_ = w.WriteByte(0)
+
+	pkt, err := ParseRequestActionUse(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestActionUse: %v", err)
+	}
+	if pkt.ActionID != ActionPrivateStoreSell || !pkt.CtrlPressed || pkt.ShiftPressed {
+		t.Errorf("got %+v", pkt)
+	}
+}
@@ -0,0 +1,56 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestBypassToServer = 0x21
+	OpcodeRequestQuestList      = 0x63
+	OpcodeRequestQuestAbort     = 0x64
+)
+
+// RequestBypassToServer is sent when a player clicks a bypass link in an HTML window.
+//
+// Structure:
+// - string: bypass command (e.g. "npc_100001_Quest Q00001_LettersOfLove accept")
+type RequestBypassToServer struct {
+	Command string
+}
+
+// ParseRequestBypassToServer parses a RequestBypassToServer packet (without opcode).
+func ParseRequestBypassToServer(data []byte) (*RequestBypassToServer, error) {
+	r := packet.NewReader(data)
+
+	cmd, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading bypass command: %w", err)
+	}
+	if cmd == "" {
+		return nil, fmt.Errorf("empty bypass command")
+	}
+
+	return &RequestBypassToServer{Command: cmd}, nil
+}
+
+// RequestQuestAbort aborts a started quest.
+//
+// Structure:
+// - int32: quest ID
+type RequestQuestAbort struct {
+	QuestID int32
+}
+
+// ParseRequestQuestAbort parses a RequestQuestAbort packet (without opcode).
+func ParseRequestQuestAbort(data []byte) (*RequestQuestAbort, error) {
+	r := packet.NewReader(data)
+
+	id, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading quest ID: %w", err)
+	}
+
+	return &RequestQuestAbort{QuestID: id}, nil
+}
@@ -0,0 +1,39 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestBypassToServer(t *testing.T) {
+	w := packet.NewWriter(64)
+	w.WriteString("npc_100001_Quest Q00001_Test accept")
+
+	pkt, err := ParseRequestBypassToServer(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestBypassToServer: %v", err)
+	}
+	if pkt.Command != "npc_100001_Quest Q00001_Test accept" {
+		t.Errorf("Command = %q", pkt.Command)
+	}
+
+	w = packet.NewWriter(4)
+	w.WriteString("")
+	if _, err := ParseRequestBypassToServer(w.Bytes()); err == nil {
+		t.Error("expected error for empty command")
+	}
+}
+
+func TestParseRequestQuestAbort(t *testing.T) {
+	w := packet.NewWriter(4)
+	w.WriteInt(42)
+
+	pkt, err := ParseRequestQuestAbort(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestQuestAbort: %v", err)
+	}
+	if pkt.QuestID != 42 {
+		t.Errorf("QuestID = %d, want 42", pkt.QuestID)
+	}
+}
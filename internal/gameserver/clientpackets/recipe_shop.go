@@ -0,0 +1,55 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeRequestRecipeShopMessageSet = 0xB1
+	OpcodeRequestRecipeShopListSet    = 0xB2
+	OpcodeRequestRecipeShopManageQuit = 0xB3
+)
+
+// RecipeShopEntry — рецепт и цена изготовления.
+type RecipeShopEntry struct {
+	RecipeID int32
+	Cost     int32
+}
+
+// RequestRecipeShopListSet is sent when the dwarf opens a manufacture store.
+//
+// Structure:
+// - int32: recipe count
+// - for each recipe: int32 recipeID, int32 cost
+type RequestRecipeShopListSet struct {
+	Items []RecipeShopEntry
+}
+
+// ParseRequestRecipeShopListSet parses a RequestRecipeShopListSet packet (without opcode).
+func ParseRequestRecipeShopListSet(data []byte) (*RequestRecipeShopListSet, error) {
+	r := packet.NewReader(data)
+
+	count, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading recipe count: %w", err)
+	}
+	if count < 0 || count > MaxStoreItems {
+		return nil, fmt.Errorf("invalid recipe count: %d", count)
+	}
+
+	items := make([]RecipeShopEntry, 0, count)
+	for i := range count {
+		var e RecipeShopEntry
+		if e.RecipeID, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading recipe %d id: %w", i, err)
+		}
+		if e.Cost, err = r.ReadInt(); err != nil {
+			return nil, fmt.Errorf("reading recipe %d cost: %w", i, err)
+		}
+		items = append(items, e)
+	}
+
+	return &RequestRecipeShopListSet{Items: items}, nil
+}
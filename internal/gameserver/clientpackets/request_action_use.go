@@ -0,0 +1,54 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeRequestActionUse = 0x45
+
+// Action IDs from the client action bar (Interlude).
+const (
+	ActionSitStand           = 0
+	ActionPrivateStoreSell   = 10
+	ActionPrivateStoreBuy    = 28
+	ActionDwarvenManufacture = 37
+	ActionPackageSell        = 61
+)
+
+// RequestActionUse is sent when the player uses an action from the action bar.
+//
+// Structure:
+// - int32: action ID
+// - int32: ctrl pressed (1 = true)
+// - byte: shift pressed (1 = true)
+type RequestActionUse struct {
+	ActionID     int32
+	CtrlPressed  bool
+	ShiftPressed bool
+}
+
+// ParseRequestActionUse parses a RequestActionUse packet (without opcode).
+func ParseRequestActionUse(data []byte) (*RequestActionUse, error) {
+	r := packet.NewReader(data)
+
+	actionID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading action id: %w", err)
+	}
+	ctrl, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading ctrl flag: %w", err)
+	}
+	shift, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("reading shift flag: %w", err)
+	}
+
+	return &RequestActionUse{
+		ActionID:     actionID,
+		CtrlPressed:  ctrl == 1,
+		ShiftPressed: shift == 1,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeRequestRestartPoint = 0x6D
+
+// Restart points a dead player can choose.
+const (
+	RestartVillage  = 0
+	RestartClanHall = 1
+	RestartCastle   = 2
+	RestartSiegeHQ  = 3
+	RestartFixed    = 4 // in place
+)
+
+// RequestRestartPoint is sent when a dead player presses a restart button of Die.
+//
+// Structure:
+// - int32: restart point type
+type RequestRestartPoint struct {
+	PointType int32
+}
+
+// ParseRequestRestartPoint parses a RequestRestartPoint packet (without opcode).
+func ParseRequestRestartPoint(data []byte) (*RequestRestartPoint, error) {
+	r := packet.NewReader(data)
+
+	pointType, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading point type: %w", err)
+	}
+	return &RequestRestartPoint{PointType: pointType}, nil
+}
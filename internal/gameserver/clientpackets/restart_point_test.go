@@ -0,0 +1,43 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseRequestRestartPoint(t *testing.T) {
+	w := packet.NewWriter(4)
+	w.WriteInt(RestartCastle)
+
+	pkt, err := ParseRequestRestartPoint(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseRequestRestartPoint: %v", err)
+	}
+	if pkt.PointType != RestartCastle {
+		t.Errorf("PointType = %d, want %d", pkt.PointType, RestartCastle)
+	}
+
+	if _, err := ParseRequestRestartPoint(nil); err == nil {
+		t.Error("expected error for empty packet")
+	}
+}
+
+func TestParseDlgAnswer(t *testing.T) {
+	w := packet.NewWriter(12)
+	w.WriteInt(1510)
+	w.WriteInt(1)
+	w.WriteInt(100002)
+
+	pkt, err := ParseDlgAnswer(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseDlgAnswer: %v", err)
+	}
+	if pkt.MessageID != 1510 || !pkt.Accepted || pkt.RequesterID != 100002 {
+		t.Errorf("DlgAnswer = %+v", pkt)
+	}
+
+	if _, err := ParseDlgAnswer(w.Bytes()[:8]); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
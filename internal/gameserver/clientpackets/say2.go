@@ -0,0 +1,64 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeSay2 = 0x38
+
+// Chat types (Interlude).
+const (
+	ChatAll              = 0
+	ChatShout            = 1
+	ChatTell             = 2
+	ChatParty            = 3
+	ChatClan             = 4
+	ChatGM               = 5
+	ChatPetitionPlayer   = 6
+	ChatPetitionGM       = 7
+	ChatTrade            = 8
+	ChatAlliance         = 9
+	ChatAnnouncement     = 10
+	ChatPartyRoom        = 14
+	ChatCommanderChannel = 15
+	ChatHeroVoice        = 17
+)
+
+// MaxChatLength — максимальная длина сообщения (L2J Say2).
+const MaxChatLength = 105
+
+// Say2 is a chat message.
+//
+// Structure:
+// - string: text
+// - int32: chat type
+// - string: target name (only for ChatTell)
+type Say2 struct {
+	Text   string
+	Type   int32
+	Target string
+}
+
+// ParseSay2 parses a Say2 packet (without opcode).
+func ParseSay2(data []byte) (*Say2, error) {
+	r := packet.NewReader(data)
+
+	text, err := r.ReadString()
+	if err != nil {
+		return nil, fmt.Errorf("reading text: %w", err)
+	}
+	chatType, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading chat type: %w", err)
+	}
+
+	pkt := &Say2{Text: text, Type: chatType}
+	if chatType == ChatTell {
+		if pkt.Target, err = r.ReadString(); err != nil {
+			return nil, fmt.Errorf("reading target: %w", err)
+		}
+	}
+	return pkt, nil
+}
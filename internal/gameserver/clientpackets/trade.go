@@ -0,0 +1,104 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const (
+	OpcodeTradeRequest       = 0x15
+	OpcodeAddTradeItem       = 0x16
+	OpcodeTradeDone          = 0x17
+	OpcodeAnswerTradeRequest = 0x44
+)
+
+// TradeRequest is sent when the player offers a trade to another player.
+//
+// Structure:
+// - int32: target objectID
+type TradeRequest struct {
+	ObjectID uint32
+}
+
+// ParseTradeRequest parses a TradeRequest packet (without opcode).
+func ParseTradeRequest(data []byte) (*TradeRequest, error) {
+	r := packet.NewReader(data)
+
+	objectID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading target objectID: %w", err)
+	}
+
+	return &TradeRequest{ObjectID: uint32(objectID)}, nil
+}
+
+// AnswerTradeRequest is the requested player's answer.
+//
+// Structure:
+// - int32: response (1 = accept, 0 = decline)
+type AnswerTradeRequest struct {
+	Accept bool
+}
+
+// ParseAnswerTradeRequest parses an AnswerTradeRequest packet (without opcode).
+func ParseAnswerTradeRequest(data []byte) (*AnswerTradeRequest, error) {
+	r := packet.NewReader(data)
+
+	response, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &AnswerTradeRequest{Accept: response == 1}, nil
+}
+
+// AddTradeItem puts an item from the inventory into the trade window.
+//
+// Structure:
+// - int32: trade ID (unused)
+// - int32: item objectID
+// - int32: count
+type AddTradeItem struct {
+	ObjectID uint32
+	Count    int32
+}
+
+// ParseAddTradeItem parses an AddTradeItem packet (without opcode).
+func ParseAddTradeItem(data []byte) (*AddTradeItem, error) {
+	r := packet.NewReader(data)
+
+	if _, err := r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading trade ID: %w", err)
+	}
+	objectID, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading objectID: %w", err)
+	}
+	count, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading count: %w", err)
+	}
+
+	return &AddTradeItem{ObjectID: uint32(objectID), Count: count}, nil
+}
+
+// TradeDone confirms or cancels the trade.
+//
+// Structure:
+// - int32: response (1 = confirm, 0 = cancel)
+type TradeDone struct {
+	Confirm bool
+}
+
+// ParseTradeDone parses a TradeDone packet (without opcode).
+func ParseTradeDone(data []byte) (*TradeDone, error) {
+	r := packet.NewReader(data)
+
+	response, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &TradeDone{Confirm: response == 1}, nil
+}
@@ -0,0 +1,66 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseTradeRequest(t *testing.T) {
+	w := packet.NewWriter(4)
+	w.WriteInt(0x10000042)
+
+	pkt, err := ParseTradeRequest(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseTradeRequest: %v", err)
+	}
+	if pkt.ObjectID != 0x10000042 {
+		t.Errorf("ObjectID = 0x%X, want 0x10000042", pkt.ObjectID)
+	}
+	if _, err := ParseTradeRequest(nil); err == nil {
+		t.Error("expected error for empty packet")
+	}
+}
+
+func TestParseAddTradeItem(t *testing.T) {
+	w := packet.NewWriter(12)
+	w.WriteInt(1)
+	w.WriteInt(77)
+	w.WriteInt(30)
+
+	pkt, err := ParseAddTradeItem(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseAddTradeItem: %v", err)
+	}
+	if pkt.ObjectID != 77 || pkt.Count != 30 {
+		t.Errorf("got %+v, want objectID 77 count 30", pkt)
+	}
+	if _, err := ParseAddTradeItem(w.Bytes()[:8]); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
+
+func TestParseTradeAnswers(t *testing.T) {
+	for _, tt := range []struct {
+		in   int32
+		want bool
+	}{{1, true}, {0, false}} {
+		w := packet.NewWriter(4)
+		w.WriteInt(tt.in)
+
+		answer, err := ParseAnswerTradeRequest(w.Bytes())
+		if err != nil {
+			t.Fatalf("ParseAnswerTradeRequest: %v", err)
+		}
+		if answer.Accept != tt.want {
+			t.Errorf("Accept(%d) = %v, want %v", tt.in, answer.Accept, tt.want)
+		}
+		done, err := ParseTradeDone(w.Bytes())
+		if err != nil {
+			t.Fatalf("ParseTradeDone: %v", err)
+		}
+		if done.Confirm != tt.want {
+			t.Errorf("Confirm(%d) = %v, want %v", tt.in, done.Confirm, tt.want)
+		}
+	}
+}
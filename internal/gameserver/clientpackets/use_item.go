@@ -0,0 +1,39 @@
+package clientpackets
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeUseItem = 0x14
+
+// UseItem is sent when the player double-clicks an item in the inventory.
+//
+// Structure:
+// - int32: item objectID
+// - int32: ctrl pressed (1 = true)
+type UseItem struct {
+	ObjectID    int32
+	CtrlPressed bool
+}
+
+// ParseUseItem parses a UseItem packet (without opcode).
+func ParseUseItem(data []byte) (*UseItem, error) {
+	r := packet.NewReader(data)
+
+	var (
+		pkt UseItem
+		err error
+	)
+	if pkt.ObjectID, err = r.ReadInt(); err != nil {
+		return nil, fmt.Errorf("reading objectID: %w", err)
+	}
+	ctrl, err := r.ReadInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading ctrl pressed: %w", err)
+	}
+	pkt.CtrlPressed = ctrl == 1
+
+	return &pkt, nil
+}
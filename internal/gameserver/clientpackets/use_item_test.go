@@ -0,0 +1,25 @@
+package clientpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+func TestParseUseItem(t *testing.T) {
+	w := packet.NewWriter(8)
+	w.WriteInt(268435457)
+	w.WriteInt(1)
+
+	pkt, err := ParseUseItem(w.Bytes())
+	if err != nil {
+		t.Fatalf("ParseUseItem: %v", err)
+	}
+	if pkt.ObjectID != 268435457 || !pkt.CtrlPressed {
+		t.Errorf("UseItem = %+v", pkt)
+	}
+
+	if _, err := ParseUseItem(w.Bytes()[:6]); err == nil {
+		t.Error("expected error for truncated packet")
+	}
+}
@@ -4,21 +4,360 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 
+	"github.com/udisondev/la2go/internal/admin"
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/clan"
+	"github.com/udisondev/la2go/internal/friend"
 	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/karma"
 	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/party"
+	"github.com/udisondev/la2go/internal/privatestore"
+	"github.com/udisondev/la2go/internal/punishment"
+	"github.com/udisondev/la2go/internal/quest"
+	"github.com/udisondev/la2go/internal/ratelimit"
+	"github.com/udisondev/la2go/internal/script"
+	"github.com/udisondev/la2go/internal/teleport"
+	"github.com/udisondev/la2go/internal/trade"
+	"github.com/udisondev/la2go/internal/world"
+	"github.com/udisondev/la2go/internal/zone"
 )
 
 // Handler processes game client packets.
 type Handler struct {
 	sessionManager *login.SessionManager
+	clients        *ClientManager
+	byAccount      sync.Map // map[string]*GameClient — lowercased account → authenticated client
+
+	stores        *privatestore.Service
+	offlineStores *privatestore.OfflineStores // nil = offline trade disabled
+	inventories   InventoryStore              // nil = inventories are not persisted
+
+	parties *party.Manager
+	clans   *clan.Manager
+	friends *friend.Manager
+	quests  *quest.Manager
+	trades  *trade.Manager
+
+	npcs      NpcLocator      // nil = NPC interaction disabled
+	npcAI     NpcControllers  // nil = players cannot attack NPCs
+	npcDeaths NpcDeaths       // nil = killed NPCs stay in the world
+	scripts   *script.Runtime // nil = no scripted NPCs
+
+	paths  ai.Pathfinder // nil = players walk in straight lines
+	movers sync.Map      // map[uint32]*ai.Mover — objectID → route of a moving player
+
+	zones   *zone.Manager
+	compass sync.Map // map[uint32]int32 — objectID → last compass zone code sent
+
+	teleports         *teleport.Table // nil = gatekeepers teleport nowhere
+	teleportDiscounts teleport.Discounts
+	escapes           sync.Map // map[uint32]time.Time — objectID → end of escape scroll cast
+
+	deaths       sync.Map          // map[uint32]*deathState — objectID → dead player
+	delevel      bool              // death penalty may take a level away
+	clanRestarts ClanRestartPoints // nil = no clan halls and castles to restart at
+
+	karma    karma.Config
+	pvpFlags sync.Map // map[uint32]time.Time — objectID → end of PvP flag
+
+	accounts      AccountStore    // nil = access levels are not loaded, nobody is a GM
+	characters    CharacterStore  // nil = accounts have no characters to enter the world with
+	spawner       NpcSpawner      // nil = GMs cannot spawn and delete NPCs
+	access        *admin.Access   // nil = GM commands disabled
+	audit         *admin.AuditLog // nil = GM actions are not recorded
+	adminCommands map[string]adminCommand
+
+	punishments *punishment.Manager // nil = nobody is punished
+	loginBlocks LoginBlocks         // nil = the login server runs in another process
+
+	rateLimits *ratelimit.Limiter // nil = packets are not rate limited
+}
+
+// Option configures optional Handler dependencies.
+type Option func(*Handler)
+
+// WithPrivateStores sets the private store service.
+func WithPrivateStores(svc *privatestore.Service) Option {
+	return func(h *Handler) {
+		h.stores = svc
+	}
+}
+
+// WithOfflineStores enables offline trade: stores stay in the world after the client disconnects.
+func WithOfflineStores(o *privatestore.OfflineStores) Option {
+	return func(h *Handler) {
+		h.offlineStores = o
+	}
+}
+
+// WithInventoryStore enables saving inventories after item exchange between players.
+func WithInventoryStore(s InventoryStore) Option {
+	return func(h *Handler) {
+		h.inventories = s
+	}
+}
+
+// WithParties sets the party manager.
+func WithParties(m *party.Manager) Option {
+	return func(h *Handler) {
+		h.parties = m
+	}
+}
+
+// WithClans sets the clan manager.
+func WithClans(m *clan.Manager) Option {
+	return func(h *Handler) {
+		h.clans = m
+	}
+}
+
+// WithFriends sets the friend and block list manager.
+func WithFriends(m *friend.Manager) Option {
+	return func(h *Handler) {
+		h.friends = m
+	}
+}
+
+// WithQuests sets the quest manager.
+func WithQuests(m *quest.Manager) Option {
+	return func(h *Handler) {
+		h.quests = m
+	}
+}
+
+// WithNpcs enables NPC interaction (dialogs and quests) with spawned NPCs.
+func WithNpcs(l NpcLocator) Option {
+	return func(h *Handler) {
+		h.npcs = l
+	}
+}
+
+// WithNpcCombat lets players attack monsters: hits reach the monster AI through
+// controllers, and killed monsters are handed to deaths.
+func WithNpcCombat(controllers NpcControllers, deaths NpcDeaths) Option {
+	return func(h *Handler) {
+		h.npcAI = controllers
+		h.npcDeaths = deaths
+	}
+}
+
+// WithScripts enables scripted NPC dialogs and hooks; script output goes to game clients.
+func WithScripts(rt *script.Runtime) Option {
+	return func(h *Handler) {
+		h.scripts = rt
+	}
+}
+
+// WithPathfinder makes click-to-move routes go around walls.
+func WithPathfinder(pf ai.Pathfinder) Option {
+	return func(h *Handler) {
+		h.paths = pf
+	}
+}
+
+// WithZones sets the zone manager.
+func WithZones(m *zone.Manager) Option {
+	return func(h *Handler) {
+		h.zones = m
+	}
+}
+
+// WithTeleports sets the gatekeeper teleport lists and their discounts.
+func WithTeleports(t *teleport.Table, d teleport.Discounts) Option {
+	return func(h *Handler) {
+		h.teleports = t
+		h.teleportDiscounts = d
+	}
+}
+
+// WithDelevel sets whether players can lose a level to the death exp penalty.
+func WithDelevel(enabled bool) Option {
+	return func(h *Handler) {
+		h.delevel = enabled
+	}
+}
+
+// WithClanRestartPoints lets dead clan members restart at their clan hall and castle.
+func WithClanRestartPoints(c ClanRestartPoints) Option {
+	return func(h *Handler) {
+		h.clanRestarts = c
+	}
+}
+
+// WithKarma sets the PvP flag, karma and PK rules.
+func WithKarma(cfg karma.Config) Option {
+	return func(h *Handler) {
+		h.karma = cfg
+	}
+}
+
+// WithAccounts loads the access level of accounts logging in to the game server.
+func WithAccounts(s AccountStore) Option {
+	return func(h *Handler) {
+		h.accounts = s
+	}
+}
+
+// WithCharacters lets authenticated accounts select a character and enter the world with it.
+func WithCharacters(s CharacterStore) Option {
+	return func(h *Handler) {
+		h.characters = s
+	}
+}
+
+// WithNpcSpawner lets GMs spawn and delete NPCs.
+func WithNpcSpawner(s NpcSpawner) Option {
+	return func(h *Handler) {
+		h.spawner = s
+	}
+}
+
+// WithAdmin enables GM commands for the access levels in access; every GM action is written to audit.
+func WithAdmin(access *admin.Access, audit *admin.AuditLog) Option {
+	return func(h *Handler) {
+		h.access = access
+		h.audit = audit
+	}
+}
+
+// WithPunishments enforces chat bans, trade bans and jail, and lets GMs punish players.
+func WithPunishments(m *punishment.Manager) Option {
+	return func(h *Handler) {
+		h.punishments = m
+	}
+}
+
+// WithLoginBlocks lets GMs lift failed-login blocks of the login server running in this process.
+func WithLoginBlocks(b LoginBlocks) Option {
+	return func(h *Handler) {
+		h.loginBlocks = b
+	}
+}
+
+// WithRateLimits limits how fast clients may send packets.
+func WithRateLimits(l *ratelimit.Limiter) Option {
+	return func(h *Handler) {
+		h.rateLimits = l
+	}
 }
 
 // NewHandler creates a new packet handler for game clients.
-func NewHandler(sessionManager *login.SessionManager) *Handler {
-	return &Handler{
+func NewHandler(sessionManager *login.SessionManager, opts ...Option) *Handler {
+	h := &Handler{
 		sessionManager: sessionManager,
+		clients:        NewClientManager(),
+		stores:         privatestore.NewService(privatestore.DefaultMaxSlots),
+		parties:        party.NewManager(),
+		clans:          clan.NewManager(nil, clan.DefaultConfig()),
+		friends:        friend.NewManager(nil, friend.DefaultConfig()),
+		quests:         quest.NewManager(nil),
+		trades:         trade.NewManager(),
+		zones:          zone.NewManager(),
+		delevel:        true,
+		karma:          karma.DefaultConfig(),
+		adminCommands:  adminCommands(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.zones.AddListener(zoneNotifier{h})
+	if h.scripts != nil {
+		h.scripts.SetMessenger(scriptMessenger{h})
+	}
+	return h
+}
+
+// Clients returns the registry of in-game clients.
+func (h *Handler) Clients() *ClientManager {
+	return h.clients
+}
+
+// KickAccount disconnects the client of account when the account logs in again
+// on the login server (login.AccountKicker). Returns false if it is not connected.
+func (h *Handler) KickAccount(account string) bool {
+	v, ok := h.byAccount.Load(strings.ToLower(account))
+	if !ok {
+		return false
+	}
+	if err := v.(*GameClient).Conn().Close(); err != nil {
+		slog.Debug("failed to close kicked client", "account", account, "err", err)
+	}
+	return true
+}
+
+// bindAccount remembers the client an account authenticated with; an older
+// client of the same account is disconnected.
+func (h *Handler) bindAccount(client *GameClient) {
+	old, loaded := h.byAccount.Swap(strings.ToLower(client.AccountName()), client)
+	if !loaded || old.(*GameClient) == client {
+		return
+	}
+	slog.Info("account connected again, kicking the previous client", "account", client.AccountName())
+	if err := old.(*GameClient).Conn().Close(); err != nil {
+		slog.Debug("failed to close kicked client", "account", client.AccountName(), "err", err)
+	}
+}
+
+// Parties returns the party manager.
+func (h *Handler) Parties() *party.Manager {
+	return h.parties
+}
+
+// Clans returns the clan manager.
+func (h *Handler) Clans() *clan.Manager {
+	return h.clans
+}
+
+// Friends returns the friend and block list manager.
+func (h *Handler) Friends() *friend.Manager {
+	return h.friends
+}
+
+// Quests returns the quest manager.
+func (h *Handler) Quests() *quest.Manager {
+	return h.quests
+}
+
+// OnDisconnect releases the player of a disconnected client.
+// With offline trade enabled, a player with an open store stays in the world.
+func (h *Handler) OnDisconnect(client *GameClient) {
+	h.byAccount.CompareAndDelete(strings.ToLower(client.AccountName()), client)
+	player := client.ActivePlayer()
+	if player == nil {
+		return
+	}
+	h.clients.Unregister(client)
+	h.stopMoving(player)
+	h.forgetZones(player)
+	h.escapes.Delete(player.ObjectID())
+	h.deaths.Delete(player.ObjectID())
+	h.pvpFlags.Delete(player.ObjectID())
+	h.leaveParty(player)
+	h.cancelTrade(player)
+	h.detachClan(player)
+	h.detachFriends(player)
+	h.quests.Detach(player.CharacterID())
+
+	if h.offlineStores != nil && player.PrivateStoreType().IsActive() {
+		ctx, cancel := context.WithTimeout(context.Background(), offlineSaveTimeout)
+		defer cancel()
+		kept, err := h.offlineStores.Keep(ctx, player)
+		if err != nil {
+			slog.Error("failed to keep offline store", "player", player.Name(), "error", err)
+		}
+		if kept {
+			slog.Info("player switched to offline trade", "player", player.Name(), "store", player.PrivateStoreType())
+			return
+		}
+	}
+
+	h.stores.Forget(player)
+	world.Instance().RemoveObject(player.ObjectID())
 }
 
 // HandlePacket dispatches a decrypted packet to the appropriate handler.
@@ -35,6 +374,9 @@ func (h *Handler) HandlePacket(
 
 	opcode := data[0]
 	body := data[1:]
+	if handle, keepOpen := h.limitPacket(client, rateLimitKey(opcode, body)); !handle {
+		return 0, keepOpen, nil
+	}
 	state := client.State()
 
 	switch state {
@@ -53,7 +395,125 @@ func (h *Handler) HandlePacket(
 		switch opcode {
 		case clientpackets.OpcodeAuthLogin:
 			return h.handleAuthLogin(ctx, client, body, buf)
-		// TODO: Add more packet handlers (CharacterSelect, EnterWorld, Logout, etc.)
+		case clientpackets.OpcodeCharacterSelect:
+			return h.handleCharacterSelect(client, body)
+		case clientpackets.OpcodeEnterWorld:
+			return h.handleEnterWorld(ctx, client)
+		case clientpackets.OpcodeMoveBackwardToLocation:
+			return h.handleMoveBackwardToLocation(client, body, buf)
+		case clientpackets.OpcodeAction:
+			return h.handleAction(ctx, client, body, buf)
+		case clientpackets.OpcodeAttackRequest:
+			return h.handleAttackRequest(ctx, client, body, buf)
+		case clientpackets.OpcodeUseItem:
+			return h.handleUseItem(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestRestartPoint:
+			return h.handleRequestRestartPoint(client, body, buf)
+		case clientpackets.OpcodeDlgAnswer:
+			return h.handleDlgAnswer(client, body)
+		case clientpackets.OpcodeRequestActionUse:
+			return h.handleRequestActionUse(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestPrivateStoreManageSell:
+			return h.handleManageSell(client, buf, false)
+		case clientpackets.OpcodeSetPrivateStoreListSell:
+			return h.handleSetPrivateStoreListSell(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestPrivateStoreQuitSell,
+			clientpackets.OpcodeRequestPrivateStoreQuitBuy,
+			clientpackets.OpcodeRequestRecipeShopManageQuit:
+			return h.handlePrivateStoreQuit(client, buf)
+		case clientpackets.OpcodeSetPrivateStoreMsgSell:
+			return h.handleSetPrivateStoreMsg(client, body, buf, model.PrivateStoreSell)
+		case clientpackets.OpcodeRequestPrivateStoreBuy:
+			return h.handleRequestPrivateStoreBuy(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestPrivateStoreManageBuy:
+			return h.handleManageBuy(client, buf)
+		case clientpackets.OpcodeSetPrivateStoreListBuy:
+			return h.handleSetPrivateStoreListBuy(client, body, buf)
+		case clientpackets.OpcodeSetPrivateStoreMsgBuy:
+			return h.handleSetPrivateStoreMsg(client, body, buf, model.PrivateStoreBuy)
+		case clientpackets.OpcodeRequestPrivateStoreSell:
+			return h.handleRequestPrivateStoreSell(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestRecipeShopMessageSet:
+			return h.handleSetPrivateStoreMsg(client, body, buf, model.PrivateStoreManufacture)
+		case clientpackets.OpcodeRequestRecipeShopListSet:
+			return h.handleRecipeShopListSet(client, body, buf)
+		case clientpackets.OpcodeTradeRequest:
+			return h.handleTradeRequest(client, body, buf)
+		case clientpackets.OpcodeAnswerTradeRequest:
+			return h.handleAnswerTradeRequest(client, body, buf)
+		case clientpackets.OpcodeAddTradeItem:
+			return h.handleAddTradeItem(client, body, buf)
+		case clientpackets.OpcodeTradeDone:
+			return h.handleTradeDone(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestJoinParty:
+			return h.handleRequestJoinParty(client, body, buf)
+		case clientpackets.OpcodeRequestAnswerJoinParty:
+			return h.handleRequestAnswerJoinParty(client, body, buf)
+		case clientpackets.OpcodeRequestWithDrawalParty:
+			return h.handleRequestWithDrawalParty(client, buf)
+		case clientpackets.OpcodeRequestOustPartyMember:
+			return h.handleRequestOustPartyMember(client, body, buf)
+		case clientpackets.OpcodeRequestJoinPledge:
+			return h.handleRequestJoinPledge(client, body, buf)
+		case clientpackets.OpcodeRequestAnswerJoinPledge:
+			return h.handleRequestAnswerJoinPledge(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestWithdrawalPledge:
+			return h.handleRequestWithdrawalPledge(ctx, client, buf)
+		case clientpackets.OpcodeRequestOustPledgeMember:
+			return h.handleRequestOustPledgeMember(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestPledgeMemberList:
+			return h.handleRequestPledgeMemberList(client)
+		case clientpackets.OpcodeRequestSetPledgeCrest:
+			return h.handleRequestSetPledgeCrest(ctx, client, body, buf, false)
+		case clientpackets.OpcodeRequestPledgeInfo:
+			return h.handleRequestPledgeInfo(body, buf)
+		case clientpackets.OpcodeRequestPledgeCrest:
+			return h.handleRequestPledgeCrest(body, buf, false)
+		case clientpackets.OpcodeRequestPledgePower:
+			return h.handleRequestPledgePower(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestStartPledgeWar:
+			return h.handleRequestStartPledgeWar(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestReplyStartPledgeWar:
+			return h.handleRequestReplyStartPledgeWar(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestStopPledgeWar:
+			return h.handleRequestStopPledgeWar(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestSurrenderPledgeWar:
+			return h.handleRequestSurrenderPledgeWar(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestJoinAlly:
+			return h.handleRequestJoinAlly(client, body, buf)
+		case clientpackets.OpcodeRequestAnswerJoinAlly:
+			return h.handleRequestAnswerJoinAlly(ctx, client, body, buf)
+		case clientpackets.OpcodeAllyLeave:
+			return h.handleAllyLeave(ctx, client, buf)
+		case clientpackets.OpcodeAllyDismiss:
+			return h.handleAllyDismiss(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestDismissAlly:
+			return h.handleRequestDismissAlly(ctx, client, buf)
+		case clientpackets.OpcodeRequestSetAllyCrest:
+			return h.handleRequestSetAllyCrest(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestAllyCrest:
+			return h.handleRequestAllyCrest(body, buf)
+		case clientpackets.OpcodeRequestFriendInvite:
+			return h.handleRequestFriendInvite(client, body, buf)
+		case clientpackets.OpcodeRequestAnswerFriendInvite:
+			return h.handleRequestAnswerFriendInvite(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestFriendList:
+			return h.handleRequestFriendList(client, buf)
+		case clientpackets.OpcodeRequestFriendDel:
+			return h.handleRequestFriendDel(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestBlock:
+			return h.handleRequestBlock(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestBypassToServer:
+			return h.handleRequestBypassToServer(ctx, client, body, buf)
+		case clientpackets.OpcodeRequestQuestList:
+			return h.handleRequestQuestList(client, buf)
+		case clientpackets.OpcodeRequestQuestAbort:
+			return h.handleRequestQuestAbort(ctx, client, body, buf)
+		case clientpackets.OpcodeSay2:
+			return h.handleSay2(client, body, buf)
+		case clientpackets.OpcodeExtended:
+			return h.handleExtendedPacket(ctx, client, body, buf)
+		// TODO: Add more packet handlers (Logout, RequestRestart, etc.)
 		default:
 			slog.Warn("unknown packet opcode",
 				"opcode", fmt.Sprintf("0x%02X", opcode),
@@ -64,6 +524,32 @@ func (h *Handler) HandlePacket(
 
 	default:
 		return 0, false, fmt.Errorf("invalid state: %v", state)
+	}
+}
+
+// handleExtendedPacket dispatches extended packets (opcode 0xD0) by sub-opcode.
+func (h *Handler) handleExtendedPacket(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	sub, body, err := clientpackets.ParseExtendedOpcode(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing extended packet: %w", err)
+	}
+
+	switch sub {
+	case clientpackets.ExOpcodeRequestChangePartyLeader:
+		return h.handleRequestChangePartyLeader(client, body, buf)
+	case clientpackets.ExOpcodeRequestPledgeCrestLarge:
+		return h.handleRequestPledgeCrest(body, buf, true)
+	case clientpackets.ExOpcodeRequestSetPledgeCrestLarge:
+		return h.handleRequestSetPledgeCrest(ctx, client, body, buf, true)
+	case clientpackets.ExOpcodeRequestPledgeSetMemberPowerGrade:
+		return h.handleRequestPledgeSetMemberPowerGrade(ctx, client, body, buf)
+	case clientpackets.ExOpcodeRequestPledgeWarList:
+		return h.handleRequestPledgeWarList(client, body, buf)
+	default:
+		slog.Warn("unknown extended packet opcode",
+			"opcode", fmt.Sprintf("0xD0:0x%02X", sub),
+			"client", client.IP())
+		return 0, true, nil
 	}
 }
 
@@ -106,22 +592,34 @@ func (h *Handler) handleAuthLogin(ctx context.Context, client *GameClient, data,
 		return 0, false, fmt.Errorf("invalid session key for account %s", pkt.AccountName)
 	}
 
+	var accountID int64
+	if h.accounts != nil {
+		acc, err := h.accounts.GetAccount(ctx, pkt.AccountName)
+		if err != nil {
+			return 0, false, fmt.Errorf("loading account %s: %w", pkt.AccountName, err)
+		}
+		if acc != nil {
+			client.SetAccessLevel(int32(acc.AccessLevel))
+			accountID = acc.ID
+		}
+	}
+
 	// SessionKey is valid, set client state
 	client.SetAccountName(pkt.AccountName)
 	client.SetSessionKey(&pkt.SessionKey)
 	client.SetState(ClientStateAuthenticated)
+	h.bindAccount(client)
 
 	slog.Info("client authenticated",
 		"account", pkt.AccountName,
 		"client", client.IP())
 
-	// TODO: Send CharSelectionInfo packet (list of characters for this account)
-	// For now, just keep connection open
+	if err := h.sendCharSelectionInfo(ctx, client, accountID); err != nil {
+		return 0, false, err
+	}
 	return 0, true, nil
 }
 
 // TODO: Add more packet handlers:
-// - handleCharacterSelect (opcode 0x0D)
-// - handleEnterWorld (opcode 0x03)
 // - handleLogout (opcode 0x09)
 // - handleRequestRestart (opcode 0x46)
@@ -0,0 +1,391 @@
+package gameserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/udisondev/la2go/internal/admin"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// maxAdminSpawn is the most NPCs one //spawn creates.
+const maxAdminSpawn = 50
+
+// errAdminUsage is returned by GM commands called with wrong arguments.
+var errAdminUsage = errors.New("wrong arguments")
+
+// AccountStore loads accounts to learn the access level of logged-in clients.
+type AccountStore interface {
+	GetAccount(ctx context.Context, login string) (*model.Account, error)
+}
+
+// NpcSpawner spawns and despawns single NPCs outside of the spawn table (GM commands).
+type NpcSpawner interface {
+	SpawnNpc(ctx context.Context, templateID int32, loc model.Location) (*model.Npc, error)
+	DespawnNpc(npc *model.Npc)
+}
+
+// LoginBlocks lifts the blocks the login server puts on IPs after failed logins.
+type LoginBlocks interface {
+	Unblock(ip string) bool
+}
+
+// adminCommand is a GM command reached through the "admin_<name> <args>" bypass,
+// which the client sends for "//<name> <args>" typed in chat.
+type adminCommand struct {
+	usage string // arguments, shown in the panel and on misuse
+	help  string
+	// run executes the command for gm and returns who or what it affected (for the audit log).
+	run func(h *Handler, ctx context.Context, gm *model.Player, args []string) (string, error)
+}
+
+// adminCommands returns the GM command registry.
+func adminCommands() map[string]adminCommand {
+	return map[string]adminCommand{
+		"admin":       {"", "open this panel", (*Handler).adminPanel},
+		"announce":    {"<text>", "announce to all players", (*Handler).adminAnnounce},
+		"teleportto":  {"<player>", "teleport to a player", (*Handler).adminTeleportTo},
+		"move_to":     {"<x> <y> <z>", "teleport to coordinates", (*Handler).adminMoveTo},
+		"recall":      {"<player>", "bring a player to you", (*Handler).adminRecall},
+		"spawn":       {"<npc id> [count]", "spawn NPCs where you stand", (*Handler).adminSpawn},
+		"delete":      {"", "delete the selected NPC", (*Handler).adminDelete},
+		"give_item":   {"<item id> [count]", "give items to the selected player or yourself", (*Handler).adminGiveItem},
+		"setlevel":    {"<level>", "set the level of the selected player or yourself", (*Handler).adminSetLevel},
+		"heal":        {"", "restore HP, MP and CP of the selected player or yourself", (*Handler).adminHeal},
+		"kill":        {"", "kill the selected player", (*Handler).adminKill},
+		"kick":        {"<player>", "disconnect a player", (*Handler).adminKick},
+		"unblock_ip":  {"<ip>", "lift the block of an IP after failed logins", (*Handler).adminUnblockIP},
+		"invis":       {"", "hide from players and monsters", (*Handler).adminInvis},
+		"vis":         {"", "show yourself again", (*Handler).adminVis},
+		"punish":      {"<account|character|ip> <name> <type> <minutes> [reason]", "ban, chat_ban, jail or trade_ban; 0 minutes is permanent", (*Handler).adminPunish},
+		"punishments": {"", "list active punishments", (*Handler).adminPunishments},
+		"unpunish":    {"<id>", "lift a punishment", (*Handler).adminUnpunish},
+		"ratelimits":  {"[reset]", "list clients tripping packet rate limits", (*Handler).adminRateLimits},
+	}
+}
+
+// handleAdminCommand runs a GM command (bypass "admin_<command> <args>") if the access level
+// of the account allows it. Every attempt of a GM is written to the audit log.
+func (h *Handler) handleAdminCommand(ctx context.Context, client *GameClient, gm *model.Player, bypass string, buf []byte) (int, bool, error) {
+	fields := strings.Fields(bypass)
+	if len(fields) == 0 {
+		return actionFailed(buf)
+	}
+	name, args := fields[0], fields[1:]
+	level := client.AccessLevel()
+	action := admin.Action{GM: gm.Name(), Account: client.AccountName(), Level: level, Command: name, Args: args}
+
+	if _, isGM := h.access.Level(level); !isGM {
+		slog.Warn("admin command from a player without GM access", "player", gm.Name(), "account", client.AccountName(), "command", name)
+		return actionFailed(buf)
+	}
+	cmd, ok := h.adminCommands[name]
+	if !ok {
+		h.adminMessage(gm, "Unknown command //"+name)
+		return actionFailed(buf)
+	}
+	if !h.access.Allowed(level, name) {
+		action.Err = errors.New("access denied")
+		h.audit.Record(action)
+		h.adminMessage(gm, "You may not use //"+name)
+		return actionFailed(buf)
+	}
+
+	action.Target, action.Err = cmd.run(h, ctx, gm, args)
+	h.audit.Record(action)
+	switch {
+	case errors.Is(action.Err, errAdminUsage):
+		h.adminMessage(gm, "Usage: //"+name+" "+cmd.usage)
+		return actionFailed(buf)
+	case action.Err != nil:
+		h.adminMessage(gm, "//"+name+": "+action.Err.Error())
+		return actionFailed(buf)
+	}
+	return 0, true, nil
+}
+
+// adminMessage shows a GM command result to the GM.
+func (h *Handler) adminMessage(gm *model.Player, text string) {
+	h.sendToPlayer(gm, serverpackets.NewSystemMessageText(text))
+}
+
+// adminTarget returns the player selected by gm, or gm itself.
+func (h *Handler) adminTarget(gm *model.Player) *model.Player {
+	if p := h.findPlayer(gm.Target()); p != nil {
+		return p
+	}
+	return gm
+}
+
+// adminPlayer returns the online player named by the only argument.
+func (h *Handler) adminPlayer(args []string) (*model.Player, error) {
+	if len(args) != 1 {
+		return nil, errAdminUsage
+	}
+	p := h.onlinePlayer(args[0])
+	if p == nil {
+		return nil, fmt.Errorf("%s is not online", args[0])
+	}
+	return p, nil
+}
+
+// adminInts parses required int32 arguments followed by optional ones;
+// optional arguments missing from args take their values from defaults.
+func adminInts(args []string, required int, defaults ...int32) ([]int32, error) {
+	if len(args) < required || len(args) > required+len(defaults) {
+		return nil, errAdminUsage
+	}
+	values := make([]int32, required+len(defaults))
+	copy(values[required:], defaults)
+	for i, arg := range args {
+		v, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			return nil, errAdminUsage
+		}
+		values[i] = int32(v)
+	}
+	return values, nil
+}
+
+// adminPanel opens the GM panel listing the commands the GM may use.
+func (h *Handler) adminPanel(_ context.Context, gm *model.Player, _ []string) (string, error) {
+	client, ok := h.clients.ByObjectID(gm.ObjectID())
+	if !ok {
+		return "", errors.New("GM is offline")
+	}
+	level, _ := h.access.Level(client.AccessLevel())
+
+	names := make([]string, 0, len(h.adminCommands))
+	for name := range h.adminCommands {
+		if level.Allows(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<html><title>Admin Panel</title><body><center>%s (%s)</center><br>", gm.Name(), level.Name)
+	for _, name := range names {
+		cmd := h.adminCommands[name]
+		if cmd.usage == "" {
+			fmt.Fprintf(&b, `<a action="bypass -h admin_%s">%s</a> - %s<br>`, name, name, cmd.help)
+			continue
+		}
+		fmt.Fprintf(&b, `<a action="bypass -h admin_%s $%s">%s</a> %s - %s<br><edit var="%s" width=200><br>`,
+			name, name, name, cmd.usage, cmd.help, name)
+	}
+	b.WriteString("</body></html>")
+	h.sendToPlayer(gm, &serverpackets.NpcHtmlMessage{HTML: b.String()})
+	return "", nil
+}
+
+func (h *Handler) adminAnnounce(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errAdminUsage
+	}
+	h.broadcastToAll(&serverpackets.CreatureSay{
+		ObjectID: gm.ObjectID(),
+		ChatType: clientpackets.ChatAnnouncement,
+		Name:     gm.Name(),
+		Text:     strings.Join(args, " "),
+	})
+	return "", nil
+}
+
+func (h *Handler) adminTeleportTo(_ context.Context, gm *model.Player, args []string) (string, error) {
+	p, err := h.adminPlayer(args)
+	if err != nil {
+		return "", err
+	}
+	return p.Name(), h.Teleport(gm, p.Location())
+}
+
+func (h *Handler) adminMoveTo(_ context.Context, gm *model.Player, args []string) (string, error) {
+	xyz, err := adminInts(args, 3)
+	if err != nil {
+		return "", err
+	}
+	return "", h.Teleport(gm, model.NewLocation(xyz[0], xyz[1], xyz[2], 0))
+}
+
+func (h *Handler) adminRecall(_ context.Context, gm *model.Player, args []string) (string, error) {
+	p, err := h.adminPlayer(args)
+	if err != nil {
+		return "", err
+	}
+	if err := h.Teleport(p, gm.Location()); err != nil {
+		return p.Name(), err
+	}
+	h.adminMessage(p, "You have been recalled by a GM.")
+	return p.Name(), nil
+}
+
+func (h *Handler) adminSpawn(ctx context.Context, gm *model.Player, args []string) (string, error) {
+	v, err := adminInts(args, 1, 1)
+	if err != nil {
+		return "", err
+	}
+	templateID, count := v[0], v[1]
+	if count < 1 || count > maxAdminSpawn {
+		return "", fmt.Errorf("count must be 1-%d", maxAdminSpawn)
+	}
+	if h.spawner == nil {
+		return "", errors.New("NPC spawning is disabled")
+	}
+	target := "npc " + strconv.Itoa(int(templateID))
+	for range count {
+		if _, err := h.spawner.SpawnNpc(ctx, templateID, gm.Location()); err != nil {
+			return target, err
+		}
+	}
+	return target, nil
+}
+
+func (h *Handler) adminDelete(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	npc := h.findNpc(gm.Target())
+	if npc == nil {
+		return "", errors.New("select an NPC first")
+	}
+	if h.spawner == nil {
+		return "", errors.New("NPC spawning is disabled")
+	}
+	target := fmt.Sprintf("npc %d (object %d)", npc.TemplateID(), npc.ObjectID())
+	loc := npc.Location()
+	h.spawner.DespawnNpc(npc)
+	h.broadcastAround(loc, &serverpackets.DeleteObject{ObjectID: npc.ObjectID()})
+	gm.SetTarget(0)
+	return target, nil
+}
+
+func (h *Handler) adminGiveItem(ctx context.Context, gm *model.Player, args []string) (string, error) {
+	v, err := adminInts(args, 1, 1)
+	if err != nil {
+		return "", err
+	}
+	if v[0] <= 0 {
+		return "", errAdminUsage
+	}
+	p := h.adminTarget(gm)
+	if _, err := p.Inventory().AddByType(v[0], v[1]); err != nil {
+		return p.Name(), err
+	}
+	h.saveInventory(ctx, p)
+	h.adminMessage(gm, fmt.Sprintf("Gave %d of item %d to %s.", v[1], v[0], p.Name()))
+	return p.Name(), nil
+}
+
+func (h *Handler) adminSetLevel(_ context.Context, gm *model.Player, args []string) (string, error) {
+	v, err := adminInts(args, 1)
+	if err != nil {
+		return "", err
+	}
+	p := h.adminTarget(gm)
+	if err := p.SetLevel(v[0]); err != nil {
+		return p.Name(), err
+	}
+	p.SetExperience(model.ExpForLevel(v[0]))
+	h.sendExpUpdate(p)
+	return p.Name(), nil
+}
+
+func (h *Handler) adminHeal(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	p := h.adminTarget(gm)
+	if p.IsDead() {
+		return p.Name(), errors.New("the target is dead")
+	}
+	p.SetCurrentHP(p.MaxHP())
+	p.SetCurrentMP(p.MaxMP())
+	p.SetCurrentCP(p.MaxCP())
+	h.sendToPlayer(p, &serverpackets.StatusUpdate{
+		ObjectID: p.ObjectID(),
+		Attrs: []serverpackets.StatusAttr{
+			{ID: serverpackets.StatusCurHP, Value: p.CurrentHP()},
+			{ID: serverpackets.StatusCurMP, Value: p.CurrentMP()},
+			{ID: serverpackets.StatusCurCP, Value: p.CurrentCP()},
+		},
+	})
+	return p.Name(), nil
+}
+
+// adminKill kills the selected player; NPC deaths are not modelled, //delete removes NPCs.
+func (h *Handler) adminKill(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	p := h.findPlayer(gm.Target())
+	if p == nil || p == gm {
+		return "", errors.New("select a player first")
+	}
+	if p.IsDead() {
+		return p.Name(), errors.New("the target is already dead")
+	}
+	h.damagePlayer(p, p.CurrentHP(), nil)
+	return p.Name(), nil
+}
+
+func (h *Handler) adminKick(_ context.Context, _ *model.Player, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errAdminUsage
+	}
+	client, ok := h.clients.ByName(args[0])
+	if !ok {
+		return "", fmt.Errorf("%s is not online", args[0])
+	}
+	if err := client.Conn().Close(); err != nil {
+		return args[0], fmt.Errorf("closing connection: %w", err)
+	}
+	return args[0], nil
+}
+
+func (h *Handler) adminUnblockIP(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errAdminUsage
+	}
+	if h.loginBlocks == nil {
+		return "", errors.New("the login server runs in another process")
+	}
+	if !h.loginBlocks.Unblock(args[0]) {
+		return args[0], fmt.Errorf("%s is not blocked", args[0])
+	}
+	h.adminMessage(gm, args[0]+" may log in again.")
+	return args[0], nil
+}
+
+func (h *Handler) adminInvis(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	if gm.IsInvisible() {
+		return "", errors.New("you are already invisible")
+	}
+	h.broadcastToVisible(gm, &serverpackets.DeleteObject{ObjectID: gm.ObjectID()})
+	gm.SetInvisible(true)
+	h.adminMessage(gm, "You are now invisible.")
+	return "", nil
+}
+
+func (h *Handler) adminVis(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	if !gm.IsInvisible() {
+		return "", errors.New("you are already visible")
+	}
+	gm.SetInvisible(false)
+	h.broadcastCharInfo(gm)
+	h.adminMessage(gm, "You are now visible.")
+	return "", nil
+}
@@ -0,0 +1,207 @@
+package gameserver
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/admin"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+// npcSpawner spawns quest NPCs into an npcMap.
+type npcSpawner struct {
+	npcs npcMap
+	next uint32
+}
+
+func (s *npcSpawner) SpawnNpc(_ context.Context, templateID int32, loc model.Location) (*model.Npc, error) {
+	s.next++
+	npc := newQuestNpc(520000+s.next, templateID, loc.X)
+	s.npcs[npc.ObjectID()] = npc
+	return npc, nil
+}
+
+func (s *npcSpawner) DespawnNpc(npc *model.Npc) {
+	delete(s.npcs, npc.ObjectID())
+}
+
+type accountMap map[string]*model.Account
+
+func (m accountMap) GetAccount(_ context.Context, login string) (*model.Account, error) {
+	return m[login], nil
+}
+
+func newAdminHandler(t *testing.T, audit *bytes.Buffer, opts ...Option) (*Handler, npcMap) {
+	t.Helper()
+	access, err := admin.LoadAccess(strings.NewReader(`
+levels:
+  - {level: 100, name: Administrator, commands: ["*"]}
+  - {level: 30, name: Support, commands: [admin, recall]}
+`))
+	if err != nil {
+		t.Fatalf("LoadAccess: %v", err)
+	}
+	npcs := npcMap{}
+	opts = append(opts, WithNpcs(npcs), WithNpcSpawner(&npcSpawner{npcs: npcs}), WithAdmin(access, admin.NewAuditLog(audit)))
+	return NewHandler(login.NewSessionManager(), opts...), npcs
+}
+
+func TestHandler_AdminCommands(t *testing.T) {
+	ctx := context.Background()
+	var audit bytes.Buffer
+	h, npcs := newAdminHandler(t, &audit)
+	gmClient := newInGameClient(t, h, 9981, "Admin")
+	gmClient.SetAccessLevel(100)
+	gm := gmClient.ActivePlayer()
+	playerClient := newInGameClient(t, h, 9982, "Someone")
+	player := playerClient.ActivePlayer()
+	player.SetLocation(player.Location().WithCoordinates(17500, 170000, -3500))
+	buf := make([]byte, 1024)
+
+	run := func(client *GameClient, cmd string) bool {
+		t.Helper()
+		n, _, err := h.HandlePacket(ctx, client, bypassPacket("admin_"+cmd), buf)
+		if err != nil {
+			t.Fatalf("//%s: %v", cmd, err)
+		}
+		return n == 0 || buf[0] != serverpackets.OpcodeActionFailed
+	}
+
+	// Players without GM access cannot use commands, and it is not a GM action
+	if run(playerClient, "heal") {
+		t.Error("player ran a GM command")
+	}
+	if audit.Len() != 0 {
+		t.Errorf("player attempt audited: %s", audit.String())
+	}
+
+	if !run(gmClient, "recall Someone") {
+		t.Fatal("//recall failed")
+	}
+	if player.X() != gm.X() || player.Y() != gm.Y() {
+		t.Errorf("recalled to %v, want %v", player.Location(), gm.Location())
+	}
+	if run(gmClient, "recall") || run(gmClient, "recall Nobody") {
+		t.Error("//recall without an online player should fail")
+	}
+
+	gm.SetTarget(player.ObjectID())
+	if !run(gmClient, "setlevel 40") || player.Level() != 40 || player.Experience() != model.ExpForLevel(40) {
+		t.Errorf("after //setlevel 40: level %d, exp %d", player.Level(), player.Experience())
+	}
+	if !run(gmClient, "give_item 57 1000") || player.Inventory().Adena() != 1000 {
+		t.Errorf("after //give_item: adena %d", player.Inventory().Adena())
+	}
+	if !run(gmClient, "kill") || !player.IsDead() {
+		t.Error("//kill did not kill the selected player")
+	}
+
+	if !run(gmClient, "spawn 30001 2") || len(npcs) != 2 {
+		t.Fatalf("after //spawn: %d NPCs, want 2", len(npcs))
+	}
+	for id := range npcs {
+		gm.SetTarget(id)
+		break
+	}
+	if !run(gmClient, "delete") || len(npcs) != 1 {
+		t.Errorf("after //delete: %d NPCs, want 1", len(npcs))
+	}
+	if run(gmClient, "spawn 30001 1000") {
+		t.Error("//spawn above the limit should fail")
+	}
+
+	if !run(gmClient, "invis") || !gm.IsInvisible() {
+		t.Fatal("//invis did not hide the GM")
+	}
+	if run(gmClient, "invis") {
+		t.Error("second //invis should fail")
+	}
+	if !run(gmClient, "vis") || gm.IsInvisible() {
+		t.Error("//vis did not show the GM")
+	}
+	if !run(gmClient, "admin") {
+		t.Error("//admin failed")
+	}
+
+	log := audit.String()
+	for _, want := range []string{`"command":"recall","args":"Someone","target":"Someone"`, `"command":"kill"`, `"command":"spawn","args":"30001 1000"`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("audit log lacks %s", want)
+		}
+	}
+}
+
+func TestHandler_AdminAccessLevels(t *testing.T) {
+	ctx := context.Background()
+	var audit bytes.Buffer
+	h, _ := newAdminHandler(t, &audit)
+	support := newInGameClient(t, h, 9983, "Support")
+	support.SetAccessLevel(30)
+	buf := make([]byte, 1024)
+
+	n, _, _ := h.HandlePacket(ctx, support, bypassPacket("admin_spawn 30001"), buf)
+	if n == 0 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Error("support spawned an NPC")
+	}
+	if !strings.Contains(audit.String(), `"error":"access denied"`) {
+		t.Errorf("denied command not audited: %s", audit.String())
+	}
+}
+
+func TestHandler_AuthLoginAccessLevel(t *testing.T) {
+	sessions := login.NewSessionManager()
+	key := login.SessionKey{PlayOkID1: 1, PlayOkID2: 2, LoginOkID1: 3, LoginOkID2: 4}
+	sessions.Store("gm", key, &login.Client{})
+	h := NewHandler(sessions, WithAccounts(accountMap{"gm": {Login: "gm", AccessLevel: 100}}))
+
+	client, err := NewGameClient(testutil.NewMockConn(), make([]byte, 16))
+	if err != nil {
+		t.Fatalf("NewGameClient: %v", err)
+	}
+	client.SetState(ClientStateAuthenticated)
+	if _, _, err := h.HandlePacket(context.Background(), client, prepareAuthLoginPacket("gm", key), make([]byte, 1024)); err != nil {
+		t.Fatalf("AuthLogin: %v", err)
+	}
+	if client.AccessLevel() != 100 {
+		t.Errorf("AccessLevel = %d, want 100", client.AccessLevel())
+	}
+}
+
+// loginBlocks is a LoginBlocks with a set of blocked IPs.
+type loginBlocks map[string]bool
+
+func (b loginBlocks) Unblock(ip string) bool {
+	blocked := b[ip]
+	delete(b, ip)
+	return blocked
+}
+
+func TestHandler_AdminUnblockIP(t *testing.T) {
+	var audit bytes.Buffer
+	blocks := loginBlocks{"10.0.0.5": true}
+	h, _ := newAdminHandler(t, &audit, WithLoginBlocks(blocks))
+	gm := newInGameClient(t, h, 9984, "Admin")
+	gm.SetAccessLevel(100)
+	buf := make([]byte, 1024)
+
+	for _, tc := range []struct {
+		ip   string
+		want bool
+	}{{"10.0.0.5", true}, {"10.0.0.5", false}} {
+		n, _, err := h.HandlePacket(context.Background(), gm, bypassPacket("admin_unblock_ip "+tc.ip), buf)
+		if err != nil {
+			t.Fatalf("//unblock_ip: %v", err)
+		}
+		if ok := n == 0 || buf[0] != serverpackets.OpcodeActionFailed; ok != tc.want {
+			t.Errorf("//unblock_ip %s = %v, want %v", tc.ip, ok, tc.want)
+		}
+	}
+	if len(blocks) != 0 {
+		t.Error("IP still blocked")
+	}
+}
@@ -0,0 +1,163 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/clan"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+)
+
+// handleRequestJoinAlly processes RequestJoinAlly (opcode 0x82).
+func (h *Handler) handleRequestJoinAlly(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestJoinAlly(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestJoinAlly: %w", err)
+	}
+
+	targetClient, ok := h.clients.ByObjectID(pkt.ObjectID)
+	if !ok || targetClient.ActivePlayer() == nil {
+		return actionFailed(buf)
+	}
+	target := targetClient.ActivePlayer()
+
+	if err := h.clans.InviteAlly(player, target); err != nil {
+		slog.Debug("alliance invite rejected", "from", player.Name(), "to", target.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	h.sendToPlayer(target, &serverpackets.AskJoinAlly{
+		RequesterID: player.ObjectID(),
+		AllyName:    h.clans.ClanOf(player).AllyName(),
+	})
+	return 0, true, nil
+}
+
+// handleRequestAnswerJoinAlly processes RequestAnswerJoinAlly (opcode 0x83).
+func (h *Handler) handleRequestAnswerJoinAlly(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestAnswerJoinPledge(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestAnswerJoinAlly: %w", err)
+	}
+
+	c, _, err := h.clans.AnswerAlly(ctx, player, pkt.Accept)
+	if err != nil {
+		slog.Debug("alliance join failed", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	if c == nil {
+		return 0, true, nil // declined
+	}
+
+	slog.Debug("clan joined alliance", "clan", c.Name(), "alliance", c.AllyName())
+	h.refreshClanAppearance(c)
+	return 0, true, nil
+}
+
+// handleAllyLeave processes AllyLeave (opcode 0x84).
+func (h *Handler) handleAllyLeave(ctx context.Context, client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	c, err := h.clans.LeaveAlliance(ctx, player)
+	if err != nil {
+		slog.Debug("alliance leave rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	h.refreshClanAppearance(c)
+	return 0, true, nil
+}
+
+// handleAllyDismiss processes AllyDismiss (opcode 0x85).
+func (h *Handler) handleAllyDismiss(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing AllyDismiss: %w", err)
+	}
+
+	c, err := h.clans.DismissAllyClan(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("alliance dismiss rejected", "by", player.Name(), "clan", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+	h.refreshClanAppearance(c)
+	return 0, true, nil
+}
+
+// handleRequestDismissAlly processes RequestDismissAlly (opcode 0x86).
+func (h *Handler) handleRequestDismissAlly(ctx context.Context, client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	clans, err := h.clans.DissolveAlliance(ctx, player)
+	if err != nil {
+		slog.Debug("alliance dissolve rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	h.refreshAllianceAppearance(clans)
+	return 0, true, nil
+}
+
+// handleRequestSetAllyCrest processes RequestSetAllyCrest (opcode 0x87).
+func (h *Handler) handleRequestSetAllyCrest(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestSetPledgeCrest(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestSetAllyCrest: %w", err)
+	}
+
+	clans, err := h.clans.SetAllyCrest(ctx, player, pkt.Data)
+	if err != nil {
+		slog.Debug("alliance crest rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	h.refreshAllianceAppearance(clans)
+	return 0, true, nil
+}
+
+// handleRequestAllyCrest processes RequestAllyCrest (opcode 0x88).
+func (h *Handler) handleRequestAllyCrest(data, buf []byte) (int, bool, error) {
+	pkt, err := clientpackets.ParseRequestPledgeID(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestAllyCrest: %w", err)
+	}
+
+	crest := h.clans.Crest(pkt.ID)
+	if crest == nil {
+		return 0, true, nil
+	}
+	n, err := writeToBuf(buf, &serverpackets.AllyCrest{CrestID: pkt.ID, Data: crest})
+	return n, true, err
+}
+
+// refreshAllianceAppearance refreshes clan windows and CharInfo of all given clans.
+func (h *Handler) refreshAllianceAppearance(clans []*clan.Clan) {
+	for _, c := range clans {
+		h.refreshClanAppearance(c)
+	}
+}
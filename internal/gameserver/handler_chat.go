@@ -0,0 +1,79 @@
+package gameserver
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+)
+
+// handleSay2 processes Say2 (opcode 0x38).
+// Routes ALL to visible players, TELL to the named player (unless blocked), PARTY to party members.
+// Chat-banned players cannot talk.
+func (h *Handler) handleSay2(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseSay2(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing Say2: %w", err)
+	}
+	if pkt.Text == "" || utf8.RuneCountInString(pkt.Text) > clientpackets.MaxChatLength {
+		return actionFailed(buf)
+	}
+	if h.chatBanned(player) {
+		return actionFailed(buf)
+	}
+
+	say := &serverpackets.CreatureSay{
+		ObjectID: player.ObjectID(),
+		ChatType: pkt.Type,
+		Name:     player.Name(),
+		Text:     pkt.Text,
+	}
+
+	switch pkt.Type {
+	case clientpackets.ChatAll:
+		h.broadcastToVisible(player, say)
+
+	case clientpackets.ChatTell:
+		target := h.onlinePlayer(pkt.Target)
+		if target == nil || target == player {
+			return actionFailed(buf)
+		}
+		if h.friends.IsBlocked(target, player) {
+			slog.Debug("whisper blocked", "from", player.Name(), "to", target.Name())
+			return actionFailed(buf)
+		}
+		h.sendToPlayer(target, say)
+		// Sender sees the message addressed to the recipient
+		say = &serverpackets.CreatureSay{
+			ObjectID: player.ObjectID(),
+			ChatType: pkt.Type,
+			Name:     "->" + target.Name(),
+			Text:     pkt.Text,
+		}
+
+	case clientpackets.ChatParty:
+		p := h.parties.PartyOf(player)
+		if p == nil {
+			return 0, true, nil
+		}
+		for _, m := range p.Members() {
+			if m != player {
+				h.sendToPlayer(m, say)
+			}
+		}
+
+	default:
+		slog.Debug("unsupported chat type", "type", pkt.Type, "player", player.Name())
+		return 0, true, nil
+	}
+
+	n, err := writeToBuf(buf, say)
+	return n, true, err
+}
@@ -0,0 +1,395 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/udisondev/la2go/internal/clan"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// clanDissolveInterval is how often pending clan dissolutions are checked.
+const clanDissolveInterval = time.Minute
+
+// clanInfo builds the clan header for pledge packets.
+func clanInfo(c *clan.Clan) serverpackets.ClanInfo {
+	info := serverpackets.ClanInfo{
+		ClanID:      c.ID(),
+		Name:        c.Name(),
+		CrestID:     c.CrestID(),
+		Level:       c.Level(),
+		Reputation:  c.Reputation(),
+		AllyID:      c.AllyID(),
+		AllyName:    c.AllyName(),
+		AllyCrestID: c.AllyCrestID(),
+		AtWar:       len(c.WarList()) > 0,
+	}
+	if leader := c.Leader(); leader != nil {
+		info.LeaderName = leader.Name
+	}
+	return info
+}
+
+// pledgeMember converts a clan member into a member list entry.
+func pledgeMember(m *clan.Member) serverpackets.PledgeMember {
+	pm := serverpackets.PledgeMember{
+		Name:       m.Name,
+		Level:      m.Level,
+		ClassID:    m.ClassID,
+		PledgeType: m.PledgeType,
+	}
+	if p := m.Player(); p != nil {
+		pm.ObjectID = p.ObjectID()
+		pm.Race = p.RaceID()
+	}
+	return pm
+}
+
+// memberListOf builds PledgeShowMemberListAll for the main clan or a sub-pledge.
+func memberListOf(c *clan.Clan, pledgeType int32) *serverpackets.PledgeShowMemberListAll {
+	info := clanInfo(c)
+	pkt := &serverpackets.PledgeShowMemberListAll{
+		Clan:       info,
+		PledgeType: pledgeType,
+		PledgeName: info.Name,
+		LeaderName: info.LeaderName,
+	}
+	if sp := c.SubPledge(pledgeType); sp != nil {
+		pkt.PledgeName = sp.Name
+		pkt.LeaderName = ""
+		if leader := c.Member(sp.LeaderID); leader != nil {
+			pkt.LeaderName = leader.Name
+		}
+	}
+	for _, m := range c.MembersOf(pledgeType) {
+		pkt.Members = append(pkt.Members, pledgeMember(m))
+	}
+	return pkt
+}
+
+// sendMemberLists sends the member lists of the main clan and all sub-pledges.
+func (h *Handler) sendMemberLists(p *model.Player, c *clan.Clan) {
+	h.sendToPlayer(p, memberListOf(c, clan.PledgeMain))
+	for _, sp := range c.SubPledges() {
+		h.sendToPlayer(p, memberListOf(c, sp.Type))
+	}
+}
+
+// sendToClan sends pkt to all online clan members except the given player.
+func (h *Handler) sendToClan(c *clan.Clan, pkt serverPacket, except *model.Player) {
+	for _, m := range c.OnlineMembers() {
+		if m != except {
+			h.sendToPlayer(m, pkt)
+		}
+	}
+}
+
+// charInfoOf builds CharInfo for p with clan fields filled in.
+func (h *Handler) charInfoOf(p *model.Player) *serverpackets.CharInfo {
+	info := &serverpackets.CharInfo{Player: p}
+	if c := h.clans.ClanOf(p); c != nil {
+		info.ClanCrestID = c.CrestID()
+		info.LargeCrestID = c.LargeCrestID()
+		info.AllyID = c.AllyID()
+		info.AllyCrestID = c.AllyCrestID()
+	}
+	return info
+}
+
+// broadcastCharInfo shows p's updated appearance (clan, crest) to visible players.
+// Hidden GMs are shown to nobody.
+func (h *Handler) broadcastCharInfo(p *model.Player) {
+	if p.IsInvisible() {
+		return
+	}
+	h.broadcastToVisible(p, h.charInfoOf(p))
+}
+
+// refreshClanAppearance rebroadcasts CharInfo of all online members and updates their clan windows.
+func (h *Handler) refreshClanAppearance(c *clan.Clan) {
+	info := &serverpackets.PledgeShowInfoUpdate{Clan: clanInfo(c)}
+	for _, m := range c.OnlineMembers() {
+		h.sendToPlayer(m, info)
+		h.broadcastCharInfo(m)
+	}
+}
+
+// AttachClan links an entering player with their clan, sends the member lists
+// and notifies online clan members.
+func (h *Handler) AttachClan(p *model.Player) {
+	c := h.clans.AttachPlayer(p)
+	if c == nil {
+		return
+	}
+	h.sendMemberLists(p, c)
+	if m := c.Member(p.CharacterID()); m != nil {
+		h.sendToClan(c, &serverpackets.PledgeShowMemberListUpdate{Member: pledgeMember(m)}, p)
+	}
+}
+
+// detachClan marks the player offline in their clan and notifies online members.
+func (h *Handler) detachClan(p *model.Player) {
+	h.clans.DetachPlayer(p)
+	c := h.clans.ClanOf(p)
+	if c == nil {
+		return
+	}
+	if m := c.Member(p.CharacterID()); m != nil {
+		h.sendToClan(c, &serverpackets.PledgeShowMemberListUpdate{Member: pledgeMember(m)}, p)
+	}
+}
+
+// handleRequestJoinPledge processes RequestJoinPledge (opcode 0x24).
+func (h *Handler) handleRequestJoinPledge(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestJoinPledge(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestJoinPledge: %w", err)
+	}
+
+	targetClient, ok := h.clients.ByObjectID(pkt.ObjectID)
+	if !ok || targetClient.ActivePlayer() == nil {
+		return actionFailed(buf)
+	}
+	target := targetClient.ActivePlayer()
+
+	if err := h.clans.Invite(player, target, pkt.PledgeType); err != nil {
+		slog.Debug("clan invite rejected", "from", player.Name(), "to", target.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	c := h.clans.ClanOf(player)
+	h.sendToPlayer(target, &serverpackets.AskJoinPledge{
+		RequesterID: player.ObjectID(),
+		ClanName:    c.Name(),
+	})
+	return 0, true, nil
+}
+
+// handleRequestAnswerJoinPledge processes RequestAnswerJoinPledge (opcode 0x25).
+func (h *Handler) handleRequestAnswerJoinPledge(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestAnswerJoinPledge(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestAnswerJoinPledge: %w", err)
+	}
+
+	c, _, err := h.clans.Answer(ctx, player, pkt.Accept)
+	if err != nil {
+		slog.Debug("clan join failed", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	if c == nil {
+		return 0, true, nil // declined
+	}
+
+	slog.Debug("player joined clan", "player", player.Name(), "clan", c.Name(), "members", c.MemberCount())
+
+	if m := c.Member(player.CharacterID()); m != nil {
+		h.sendToClan(c, &serverpackets.PledgeShowMemberListAdd{Member: pledgeMember(m)}, player)
+	}
+	h.sendMemberLists(player, c)
+	h.broadcastCharInfo(player)
+
+	n, err := writeToBuf(buf, &serverpackets.JoinPledge{ClanID: c.ID()})
+	return n, true, err
+}
+
+// handleRequestWithdrawalPledge processes RequestWithdrawalPledge (opcode 0x26).
+func (h *Handler) handleRequestWithdrawalPledge(ctx context.Context, client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	c, err := h.clans.Leave(ctx, player)
+	if err != nil {
+		slog.Debug("clan leave rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	h.sendToClan(c, &serverpackets.PledgeShowMemberListDelete{Name: player.Name()}, nil)
+	h.broadcastCharInfo(player)
+
+	n, err := writeToBuf(buf, serverpackets.PledgeShowMemberListDeleteAll{})
+	return n, true, err
+}
+
+// handleRequestOustPledgeMember processes RequestOustPledgeMember (opcode 0x27).
+func (h *Handler) handleRequestOustPledgeMember(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestOustPledgeMember(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestOustPledgeMember: %w", err)
+	}
+
+	c, expelled, err := h.clans.Expel(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("clan expel rejected", "by", player.Name(), "member", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	h.sendToClan(c, &serverpackets.PledgeShowMemberListDelete{Name: expelled.Name}, nil)
+	if p := expelled.Player(); p != nil {
+		h.sendToPlayer(p, serverpackets.PledgeShowMemberListDeleteAll{})
+		h.broadcastCharInfo(p)
+	}
+	return 0, true, nil
+}
+
+// handleRequestPledgeMemberList processes RequestPledgeMemberList (opcode 0x3C).
+func (h *Handler) handleRequestPledgeMemberList(client *GameClient) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	c := h.clans.ClanOf(player)
+	if c == nil {
+		return 0, true, nil
+	}
+	h.sendMemberLists(player, c)
+	return 0, true, nil
+}
+
+// handleRequestPledgeInfo processes RequestPledgeInfo (opcode 0x66).
+func (h *Handler) handleRequestPledgeInfo(data, buf []byte) (int, bool, error) {
+	pkt, err := clientpackets.ParseRequestPledgeID(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPledgeInfo: %w", err)
+	}
+
+	c := h.clans.Clan(pkt.ID)
+	if c == nil {
+		return 0, true, nil
+	}
+	n, err := writeToBuf(buf, &serverpackets.PledgeInfo{ClanID: c.ID(), Name: c.Name(), AllyName: c.AllyName()})
+	return n, true, err
+}
+
+// handleRequestPledgeCrest processes RequestPledgeCrest (opcode 0x68) and
+// RequestPledgeCrestLarge (0xD0:0x10).
+func (h *Handler) handleRequestPledgeCrest(data, buf []byte, large bool) (int, bool, error) {
+	pkt, err := clientpackets.ParseRequestPledgeID(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPledgeCrest: %w", err)
+	}
+
+	crest := h.clans.Crest(pkt.ID)
+	if crest == nil {
+		return 0, true, nil
+	}
+
+	var resp serverPacket = &serverpackets.PledgeCrest{CrestID: pkt.ID, Data: crest}
+	if large {
+		resp = &serverpackets.ExPledgeCrestLarge{CrestID: pkt.ID, Data: crest}
+	}
+	n, err := writeToBuf(buf, resp)
+	return n, true, err
+}
+
+// handleRequestSetPledgeCrest processes RequestSetPledgeCrest (opcode 0x53) and
+// RequestSetPledgeCrestLarge (0xD0:0x11).
+func (h *Handler) handleRequestSetPledgeCrest(ctx context.Context, client *GameClient, data, buf []byte, large bool) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestSetPledgeCrest(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestSetPledgeCrest: %w", err)
+	}
+
+	c, err := h.clans.SetCrest(ctx, player, pkt.Data, large)
+	if err != nil {
+		slog.Debug("clan crest rejected", "player", player.Name(), "large", large, "error", err)
+		return actionFailed(buf)
+	}
+
+	slog.Debug("clan crest updated", "clan", c.Name(), "large", large, "size", len(pkt.Data))
+	h.refreshClanAppearance(c)
+	return 0, true, nil
+}
+
+// handleRequestPledgePower processes RequestPledgePower (opcode 0xC0).
+func (h *Handler) handleRequestPledgePower(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgePower(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPledgePower: %w", err)
+	}
+
+	c := h.clans.ClanOf(player)
+	if c == nil {
+		return actionFailed(buf)
+	}
+
+	if pkt.Action == clientpackets.PledgePowerSet {
+		if err := h.clans.SetRankPrivileges(ctx, player, pkt.Rank, clan.Privilege(pkt.Privileges)); err != nil {
+			slog.Debug("clan rank privileges rejected", "player", player.Name(), "rank", pkt.Rank, "error", err)
+			return actionFailed(buf)
+		}
+	}
+
+	n, err := writeToBuf(buf, &serverpackets.ManagePledgePower{Privileges: int32(c.RankPrivileges(pkt.Rank))})
+	return n, true, err
+}
+
+// handleRequestPledgeSetMemberPowerGrade processes RequestPledgeSetMemberPowerGrade (0xD0:0x15).
+func (h *Handler) handleRequestPledgeSetMemberPowerGrade(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeSetMemberPowerGrade(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPledgeSetMemberPowerGrade: %w", err)
+	}
+
+	m, err := h.clans.SetPowerGrade(ctx, player, pkt.Name, pkt.PowerGrade)
+	if err != nil {
+		slog.Debug("clan power grade rejected", "by", player.Name(), "member", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	if c := h.clans.ClanOf(player); c != nil {
+		h.sendToClan(c, &serverpackets.PledgeShowMemberListUpdate{Member: pledgeMember(m)}, nil)
+	}
+	return 0, true, nil
+}
+
+// notifyClanDissolved clears clan windows and appearance of former members.
+func (h *Handler) notifyClanDissolved(c *clan.Clan) {
+	slog.Info("clan dissolved", "clan", c.Name())
+	for _, m := range c.OnlineMembers() {
+		h.sendToPlayer(m, serverpackets.PledgeShowMemberListDeleteAll{})
+		h.broadcastCharInfo(m)
+	}
+}
+
+// RunClanUpdates completes pending clan dissolutions until ctx is cancelled.
+func (h *Handler) RunClanUpdates(ctx context.Context) error {
+	return h.clans.Start(ctx, clanDissolveInterval, h.notifyClanDissolved)
+}
@@ -0,0 +1,119 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+)
+
+func TestHandler_ClanInviteLeaveFlow(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	leader := newInGameClient(t, h, 9201, "Leader")
+	member := newInGameClient(t, h, 9202, "Member")
+	buf := make([]byte, 4096)
+
+	c, err := h.Clans().Create(ctx, leader.ActivePlayer(), "Knights")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	invite := packet.NewWriter(16)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestJoinPledge)
+	invite.WriteInt(int32(member.ActivePlayer().ObjectID()))
+	invite.WriteInt(0)
+	if n, ok, err := h.HandlePacket(ctx, leader, invite.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestJoinPledge: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if h.Clans().PendingRequester(member.ActivePlayer()) != leader.ActivePlayer() {
+		t.Fatal("invitation was not registered")
+	}
+
+	answer := packet.NewWriter(8)
+	_ = answer.WriteByte(clientpackets.OpcodeRequestAnswerJoinPledge)
+	answer.WriteInt(1)
+	n, ok, err := h.HandlePacket(ctx, member, answer.Bytes(), buf)
+	if err != nil || !ok {
+		t.Fatalf("RequestAnswerJoinPledge: ok=%v err=%v", ok, err)
+	}
+	if n == 0 || buf[0] != serverpackets.OpcodeJoinPledge {
+		t.Fatalf("expected JoinPledge, got n=%d opcode=0x%02X", n, buf[0])
+	}
+	if member.ActivePlayer().ClanID() != c.ID() || c.MemberCount() != 2 {
+		t.Fatalf("member not in clan: clanID=%d members=%d", member.ActivePlayer().ClanID(), c.MemberCount())
+	}
+
+	info := h.charInfoOf(member.ActivePlayer())
+	data, err := info.Write()
+	if err != nil || data[0] != serverpackets.OpcodeCharInfo {
+		t.Fatalf("CharInfo: err=%v", err)
+	}
+
+	// The leader cannot leave their own clan
+	withdraw := []byte{clientpackets.OpcodeRequestWithdrawalPledge}
+	n, _, _ = h.HandlePacket(ctx, leader, withdraw, buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("leader withdrawal must fail, got opcode 0x%02X", buf[0])
+	}
+
+	n, ok, err = h.HandlePacket(ctx, member, withdraw, buf)
+	if err != nil || !ok || n != 1 || buf[0] != serverpackets.OpcodePledgeShowMemberListDeleteAll {
+		t.Fatalf("RequestWithdrawalPledge: n=%d ok=%v err=%v opcode=0x%02X", n, ok, err, buf[0])
+	}
+	if member.ActivePlayer().ClanID() != 0 || c.MemberCount() != 1 {
+		t.Error("member should have left the clan")
+	}
+	if h.Clans().Penalty(member.ActivePlayer().CharacterID()).JoinExpiry.IsZero() {
+		t.Error("leaving should add a join penalty")
+	}
+}
+
+func TestHandler_ClanCrestAndInfo(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	leader := newInGameClient(t, h, 9211, "Leader")
+	buf := make([]byte, 4096)
+
+	c, err := h.Clans().Create(ctx, leader.ActivePlayer(), "Crested")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Clan level 0 cannot register a crest
+	crest := packet.NewWriter(16)
+	_ = crest.WriteByte(clientpackets.OpcodeRequestSetPledgeCrest)
+	crest.WriteInt(4)
+	crest.WriteBytes([]byte{1, 2, 3, 4})
+	n, ok, err := h.HandlePacket(ctx, leader, crest.Bytes(), buf)
+	if err != nil || !ok || n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Fatalf("crest upload must fail: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if c.CrestID() != 0 {
+		t.Error("crest must not be set")
+	}
+
+	req := packet.NewWriter(8)
+	_ = req.WriteByte(clientpackets.OpcodeRequestPledgeInfo)
+	req.WriteInt(c.ID())
+	n, ok, err = h.HandlePacket(ctx, leader, req.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodePledgeInfo {
+		t.Fatalf("RequestPledgeInfo: n=%d ok=%v err=%v", n, ok, err)
+	}
+
+	power := packet.NewWriter(16)
+	_ = power.WriteByte(clientpackets.OpcodeRequestPledgePower)
+	power.WriteInt(5)
+	power.WriteInt(clientpackets.PledgePowerSet)
+	power.WriteInt(0x42)
+	n, ok, err = h.HandlePacket(ctx, leader, power.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeManagePledgePower {
+		t.Fatalf("RequestPledgePower: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if c.RankPrivileges(5) != 0x42 {
+		t.Errorf("RankPrivileges(5) = %#x, want 0x42", c.RankPrivileges(5))
+	}
+}
@@ -0,0 +1,230 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/clan"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// relationTo returns the relation flags of p as seen by viewer.
+// Flagged and karma players are marked for everyone. A one-sided war icon is shown
+// when the viewer's clan declared war on p's clan, the mutual war icon when both clans
+// declared war on each other.
+func (h *Handler) relationTo(p, viewer *model.Player) int32 {
+	var rel int32
+	if p.IsPvPFlagged() {
+		rel |= serverpackets.RelationPvPFlag
+	}
+	if p.Karma() > 0 {
+		rel |= serverpackets.RelationHasKarma
+	}
+
+	if p.PledgeType() == clan.PledgeAcademy || viewer.PledgeType() == clan.PledgeAcademy {
+		return rel
+	}
+	pc, vc := h.clans.ClanOf(p), h.clans.ClanOf(viewer)
+	if pc == nil || vc == nil || pc == vc {
+		return rel
+	}
+
+	if vc.IsAtWarWith(pc.ID()) {
+		rel |= serverpackets.RelationOneSidedWar
+		if pc.IsAtWarWith(vc.ID()) {
+			rel |= serverpackets.RelationMutualWar
+		}
+	}
+	return rel
+}
+
+// sendWarRelations updates war icons and name colours between online members of two clans.
+func (h *Handler) sendWarRelations(a, b *clan.Clan) {
+	aMembers, bMembers := a.OnlineMembers(), b.OnlineMembers()
+	for _, pa := range aMembers {
+		for _, pb := range bMembers {
+			h.sendToPlayer(pa, h.relationChanged(pb, pa))
+			h.sendToPlayer(pb, h.relationChanged(pa, pb))
+		}
+	}
+}
+
+// relationChanged builds RelationChanged describing p for viewer.
+func (h *Handler) relationChanged(p, viewer *model.Player) *serverpackets.RelationChanged {
+	return &serverpackets.RelationChanged{
+		ObjectID:       p.ObjectID(),
+		Relation:       h.relationTo(p, viewer),
+		AutoAttackable: p.Karma() > 0 || p.IsPvPFlagged() || h.clans.AtMutualWar(p, viewer),
+		Karma:          p.Karma(),
+		PvPFlag:        p.IsPvPFlagged(),
+	}
+}
+
+// notifyWarChange refreshes clan windows, war relations and sends the notice to both clans.
+func (h *Handler) notifyWarChange(c, enemy *clan.Clan, notice *serverpackets.PledgeWarNotice) {
+	for _, side := range []*clan.Clan{c, enemy} {
+		info := &serverpackets.PledgeShowInfoUpdate{Clan: clanInfo(side)}
+		for _, m := range side.OnlineMembers() {
+			h.sendToPlayer(m, info)
+			if notice != nil {
+				h.sendToPlayer(m, notice)
+			}
+		}
+	}
+	h.sendWarRelations(c, enemy)
+}
+
+// handleRequestStartPledgeWar processes RequestStartPledgeWar (opcode 0x4D).
+func (h *Handler) handleRequestStartPledgeWar(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestStartPledgeWar: %w", err)
+	}
+
+	c, enemy, err := h.clans.DeclareWar(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("war declaration rejected", "by", player.Name(), "enemy", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	slog.Debug("clan war declared", "clan", c.Name(), "enemy", enemy.Name())
+	h.notifyWarChange(c, enemy, nil)
+
+	// Ask the enemy leader to declare war in return
+	if leader := enemy.Leader(); leader != nil && leader.IsOnline() {
+		h.sendToPlayer(leader.Player(), &serverpackets.PledgeWarNotice{
+			Opcode:     serverpackets.OpcodeStartPledgeWar,
+			PledgeName: c.Name(),
+			CharName:   player.Name(),
+		})
+	}
+	return 0, true, nil
+}
+
+// handleRequestReplyStartPledgeWar processes RequestReplyStartPledgeWar (opcode 0x4E).
+func (h *Handler) handleRequestReplyStartPledgeWar(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestReplyStartPledgeWar(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestReplyStartPledgeWar: %w", err)
+	}
+	if !pkt.Accept {
+		return 0, true, nil
+	}
+
+	requester := h.onlinePlayer(pkt.Name)
+	if requester == nil {
+		return actionFailed(buf)
+	}
+	enemyClan := h.clans.ClanOf(requester)
+	if enemyClan == nil {
+		return actionFailed(buf)
+	}
+
+	c, enemy, err := h.clans.AcceptWar(ctx, player, enemyClan.Name())
+	if err != nil {
+		slog.Debug("war acceptance rejected", "by", player.Name(), "enemy", enemyClan.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	slog.Debug("clan war is mutual", "clan", c.Name(), "enemy", enemy.Name())
+	h.notifyWarChange(c, enemy, nil)
+	return 0, true, nil
+}
+
+// handleRequestStopPledgeWar processes RequestStopPledgeWar (opcode 0x4F).
+func (h *Handler) handleRequestStopPledgeWar(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestStopPledgeWar: %w", err)
+	}
+
+	c, enemy, err := h.clans.StopWar(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("war stop rejected", "by", player.Name(), "enemy", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	h.notifyWarChange(c, enemy, &serverpackets.PledgeWarNotice{
+		Opcode:     serverpackets.OpcodeStopPledgeWar,
+		PledgeName: c.Name(),
+		CharName:   player.Name(),
+	})
+	return 0, true, nil
+}
+
+// handleRequestSurrenderPledgeWar processes RequestSurrenderPledgeWar (opcode 0x51).
+func (h *Handler) handleRequestSurrenderPledgeWar(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestSurrenderPledgeWar: %w", err)
+	}
+
+	c, enemy, err := h.clans.SurrenderWar(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("surrender rejected", "by", player.Name(), "enemy", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	slog.Debug("clan surrendered", "clan", c.Name(), "enemy", enemy.Name())
+	h.notifyWarChange(c, enemy, &serverpackets.PledgeWarNotice{
+		Opcode:     serverpackets.OpcodeSurrenderPledgeWar,
+		PledgeName: c.Name(),
+		CharName:   player.Name(),
+	})
+	return 0, true, nil
+}
+
+// handleRequestPledgeWarList processes RequestPledgeWarList (0xD0:0x1E).
+func (h *Handler) handleRequestPledgeWarList(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPledgeWarList(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPledgeWarList: %w", err)
+	}
+
+	c := h.clans.ClanOf(player)
+	if c == nil {
+		return actionFailed(buf)
+	}
+
+	ids := c.WarList()
+	if pkt.Tab == clientpackets.WarListUnderAttack {
+		ids = c.AttackerList()
+	}
+	resp := &serverpackets.PledgeReceiveWarList{Tab: pkt.Tab, Page: pkt.Page}
+	for _, id := range ids {
+		if enemy := h.clans.Clan(id); enemy != nil {
+			resp.Clans = append(resp.Clans, enemy.Name())
+		}
+	}
+
+	n, err := writeToBuf(buf, resp)
+	return n, true, err
+}
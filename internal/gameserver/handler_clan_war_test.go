@@ -0,0 +1,168 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/clan"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+)
+
+// snapshotClanRepo loads a fixed snapshot; writes are accepted and dropped.
+type snapshotClanRepo struct {
+	clan.Repository // unused methods panic
+	snap            *clan.Snapshot
+}
+
+func (r *snapshotClanRepo) LoadClans(context.Context) (*clan.Snapshot, error) { return r.snap, nil }
+func (r *snapshotClanRepo) UpdateClan(context.Context, clan.Record) error     { return nil }
+func (r *snapshotClanRepo) SaveWar(context.Context, clan.WarRecord) error     { return nil }
+func (r *snapshotClanRepo) DeleteWar(context.Context, clan.WarRecord) error   { return nil }
+
+// newWarHandler creates a handler with two level 5 clans led by characters 9301 and 9302.
+func newWarHandler(t *testing.T) *Handler {
+	t.Helper()
+	repo := &snapshotClanRepo{snap: &clan.Snapshot{
+		Clans: []clan.Record{
+			{ID: 31, Name: "Alpha", LeaderID: 9301, Level: 5},
+			{ID: 32, Name: "Beta", LeaderID: 9302, Level: 5},
+		},
+		Members: []clan.MemberRecord{
+			{ClanID: 31, CharacterID: 9301, Name: "AlphaLeader", Level: 20, PowerGrade: clan.RankLeader},
+			{ClanID: 32, CharacterID: 9302, Name: "BetaLeader", Level: 20, PowerGrade: clan.RankLeader},
+		},
+	}}
+	cfg := clan.DefaultConfig()
+	cfg.WarMinMembers = 1
+	clans := clan.NewManager(repo, cfg)
+	if err := clans.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return NewHandler(login.NewSessionManager(), WithClans(clans))
+}
+
+func TestHandler_ClanWarFlow(t *testing.T) {
+	ctx := context.Background()
+	h := newWarHandler(t)
+	alpha := newInGameClient(t, h, 9301, "AlphaLeader")
+	beta := newInGameClient(t, h, 9302, "BetaLeader")
+	h.AttachClan(alpha.ActivePlayer())
+	h.AttachClan(beta.ActivePlayer())
+	buf := make([]byte, 4096)
+
+	declare := packet.NewWriter(32)
+	_ = declare.WriteByte(clientpackets.OpcodeRequestStartPledgeWar)
+	declare.WriteString("Beta")
+	if n, ok, err := h.HandlePacket(ctx, alpha, declare.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestStartPledgeWar: n=%d ok=%v err=%v", n, ok, err)
+	}
+
+	a, b := h.Clans().Clan(31), h.Clans().Clan(32)
+	if !a.IsAtWarWith(b.ID()) || a.IsMutualWar(b.ID()) {
+		t.Fatal("war should be one-sided after declaration")
+	}
+	if rel := h.relationTo(beta.ActivePlayer(), alpha.ActivePlayer()); rel != serverpackets.RelationOneSidedWar {
+		t.Errorf("one-sided relation = %#x", rel)
+	}
+
+	reply := packet.NewWriter(32)
+	_ = reply.WriteByte(clientpackets.OpcodeRequestReplyStartPledgeWar)
+	reply.WriteString("AlphaLeader")
+	reply.WriteInt(1)
+	if _, ok, err := h.HandlePacket(ctx, beta, reply.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestReplyStartPledgeWar: ok=%v err=%v", ok, err)
+	}
+	if !h.Clans().AtMutualWar(alpha.ActivePlayer(), beta.ActivePlayer()) {
+		t.Fatal("war should be mutual after acceptance")
+	}
+	want := int32(serverpackets.RelationOneSidedWar | serverpackets.RelationMutualWar)
+	if rel := h.relationTo(beta.ActivePlayer(), alpha.ActivePlayer()); rel != want {
+		t.Errorf("mutual relation = %#x, want %#x", rel, want)
+	}
+
+	list := packet.NewWriter(16)
+	_ = list.WriteByte(clientpackets.OpcodeExtended)
+	list.WriteShort(clientpackets.ExOpcodeRequestPledgeWarList)
+	list.WriteInt(0)
+	list.WriteInt(clientpackets.WarListUnderAttack)
+	n, ok, err := h.HandlePacket(ctx, alpha, list.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeExtended {
+		t.Fatalf("RequestPledgeWarList: n=%d ok=%v err=%v", n, ok, err)
+	}
+	r := packet.NewReader(buf[3:n])
+	if tab, _ := r.ReadInt(); tab != clientpackets.WarListUnderAttack {
+		t.Errorf("tab = %d", tab)
+	}
+	_, _ = r.ReadInt() // page
+	if count, _ := r.ReadInt(); count != 1 {
+		t.Fatalf("war list count = %d, want 1", count)
+	}
+	if name, _ := r.ReadString(); name != "Beta" {
+		t.Errorf("war list clan = %q, want Beta", name)
+	}
+
+	surrender := packet.NewWriter(32)
+	_ = surrender.WriteByte(clientpackets.OpcodeRequestSurrenderPledgeWar)
+	surrender.WriteString("Alpha")
+	if _, ok, err := h.HandlePacket(ctx, beta, surrender.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestSurrenderPledgeWar: ok=%v err=%v", ok, err)
+	}
+	if a.HasWars() || b.HasWars() {
+		t.Error("surrender should end the war")
+	}
+}
+
+func TestHandler_AllianceInvite(t *testing.T) {
+	ctx := context.Background()
+	h := newWarHandler(t)
+	alpha := newInGameClient(t, h, 9301, "AlphaLeader")
+	beta := newInGameClient(t, h, 9302, "BetaLeader")
+	h.AttachClan(alpha.ActivePlayer())
+	h.AttachClan(beta.ActivePlayer())
+	buf := make([]byte, 4096)
+
+	if _, err := h.Clans().CreateAlliance(ctx, alpha.ActivePlayer(), "Union"); err != nil {
+		t.Fatalf("CreateAlliance: %v", err)
+	}
+
+	invite := packet.NewWriter(8)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestJoinAlly)
+	invite.WriteInt(int32(beta.ActivePlayer().ObjectID()))
+	if n, ok, err := h.HandlePacket(ctx, alpha, invite.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestJoinAlly: n=%d ok=%v err=%v", n, ok, err)
+	}
+
+	answer := packet.NewWriter(8)
+	_ = answer.WriteByte(clientpackets.OpcodeRequestAnswerJoinAlly)
+	answer.WriteInt(1)
+	if _, ok, err := h.HandlePacket(ctx, beta, answer.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestAnswerJoinAlly: ok=%v err=%v", ok, err)
+	}
+	b := h.Clans().Clan(32)
+	if b.AllyID() != 31 || b.AllyName() != "Union" {
+		t.Fatalf("clan did not join alliance: allyID=%d", b.AllyID())
+	}
+	if info := h.charInfoOf(beta.ActivePlayer()); info.AllyID != 31 {
+		t.Errorf("CharInfo AllyID = %d, want 31", info.AllyID)
+	}
+
+	// Alliance members cannot go to war with each other
+	declare := packet.NewWriter(32)
+	_ = declare.WriteByte(clientpackets.OpcodeRequestStartPledgeWar)
+	declare.WriteString("Alpha")
+	n, _, _ := h.HandlePacket(ctx, beta, declare.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("war inside alliance must fail, got opcode 0x%02X", buf[0])
+	}
+
+	leave := []byte{clientpackets.OpcodeAllyLeave}
+	if _, ok, err := h.HandlePacket(ctx, beta, leave, buf); err != nil || !ok {
+		t.Fatalf("AllyLeave: ok=%v err=%v", ok, err)
+	}
+	if b.AllyID() != 0 {
+		t.Error("clan should have left the alliance")
+	}
+}
@@ -0,0 +1,178 @@
+package gameserver
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// playerPDef is the P.Def of players against monster hits
+// (base P.Def of an unequipped character; player stats are not modelled yet).
+const playerPDef = 80
+
+// npcTickInterval is how often idle NPC AI runs (TickManager default interval).
+const npcTickInterval = time.Second
+
+// npcCombatTickInterval is how often monster AI runs while chasing and fighting.
+const npcCombatTickInterval = 250 * time.Millisecond
+
+// NpcControllers finds the AI of spawned NPCs (implemented by ai.TickManager).
+type NpcControllers interface {
+	GetController(objectID uint32) (ai.Controller, error)
+	Wake(objectID uint32)
+}
+
+// NpcDeaths takes killed NPCs out of the world (implemented by spawn.RespawnTaskManager).
+type NpcDeaths interface {
+	OnNpcDeath(npc *model.Npc)
+}
+
+// attackableAI is the AI of NPCs that fight back when hit (ai.AttackableAI).
+type attackableAI interface {
+	OnAttacked(attacker *model.Player, damage int32)
+}
+
+// FindPlayer returns an online player (or offline trader) by objectID; used by monster AI
+// to tell players apart from other world objects.
+func (h *Handler) FindPlayer(objectID uint32) (*model.Player, bool) {
+	p := h.findPlayer(objectID)
+	return p, p != nil
+}
+
+// npcCombat deals monster hits to players.
+type npcCombat struct {
+	h *Handler
+}
+
+// Attack hits the target once: L2J base physical damage 70 * P.Atk / P.Def.
+func (c npcCombat) Attack(npc *model.Npc, target *model.Player) {
+	damage := max(70*npc.PAtk()/playerPDef, 1)
+	c.h.broadcastAround(npc.Location(), &serverpackets.Attack{
+		AttackerID: npc.ObjectID(),
+		Loc:        npc.Location(),
+		Hits:       []serverpackets.Hit{{TargetID: target.ObjectID(), Damage: damage}},
+	})
+	c.h.damagePlayer(target, damage, nil)
+}
+
+// npcAttackable returns the AI of an NPC players may attack (monsters and guards), or nil.
+func (h *Handler) npcAttackable(npc *model.Npc) attackableAI {
+	if h.npcAI == nil {
+		return nil
+	}
+	ctrl, err := h.npcAI.GetController(npc.ObjectID())
+	if err != nil {
+		return nil
+	}
+	a, _ := ctrl.(attackableAI)
+	return a
+}
+
+// attackNpc hits a monster once; its AI puts the attacker on the hate list and fights back.
+func (h *Handler) attackNpc(ctx context.Context, player *model.Player, npc *model.Npc, buf []byte) (int, bool, error) {
+	target := h.npcAttackable(npc)
+	if target == nil || npc.IsDead() ||
+		player.Location().DistanceSquared(npc.Location()) > attackRange*attackRange {
+		return actionFailed(buf)
+	}
+	if h.zones.InsideZone(player.WorldObject, zone.Peace) {
+		return actionFailed(buf)
+	}
+
+	damage := int32(max(70*playerPAtk/max(npc.PDef(), 1), 1))
+	h.broadcastAround(player.Location(), &serverpackets.Attack{
+		AttackerID: player.ObjectID(),
+		Loc:        player.Location(),
+		Hits:       []serverpackets.Hit{{TargetID: npc.ObjectID(), Damage: damage}},
+	})
+	hp, killed := npc.ReduceHP(damage)
+	h.sendToPlayer(player, &serverpackets.StatusUpdate{
+		ObjectID: npc.ObjectID(),
+		Attrs: []serverpackets.StatusAttr{
+			{ID: serverpackets.StatusCurHP, Value: hp},
+			{ID: serverpackets.StatusMaxHP, Value: npc.MaxHP()},
+		},
+	})
+	if killed {
+		h.killNpc(ctx, player, npc)
+		return 0, true, nil
+	}
+	target.OnAttacked(player, damage)
+	h.npcAI.Wake(npc.ObjectID())
+	return 0, true, nil
+}
+
+// killNpc shows the death of an NPC killed by a player, gives out its experience and SP
+// (shared within the killer's party) and its drops, runs the kill triggers of scripts
+// and quests and removes the corpse until respawn.
+func (h *Handler) killNpc(ctx context.Context, killer *model.Player, npc *model.Npc) {
+	h.broadcastAround(npc.Location(), &serverpackets.Die{ObjectID: npc.ObjectID()})
+	if tmpl := npc.Template(); tmpl.Exp() > 0 || tmpl.SP() > 0 {
+		for _, r := range h.parties.RewardKill(killer, npc.Location(), tmpl.Exp(), tmpl.SP()) {
+			h.sendToPlayer(r.Player, &serverpackets.StatusUpdate{
+				ObjectID: r.Player.ObjectID(),
+				Attrs: []serverpackets.StatusAttr{
+					{ID: serverpackets.StatusExp, Value: int32(r.Player.Experience())},
+					{ID: serverpackets.StatusSP, Value: int32(r.Player.SP())},
+				},
+			})
+		}
+	}
+	h.dropLoot(ctx, killer, npc)
+	h.notifyNpcKilled(ctx, killer, npc)
+	if h.npcDeaths != nil {
+		h.npcDeaths.OnNpcDeath(npc)
+	}
+	slog.Debug("npc killed", "npc", npc.Name(), "objectID", npc.ObjectID(), "killer", killer.Name())
+}
+
+// dropLoot rolls the drop list of an NPC killed by killer. Items do not lie on the ground
+// yet, so each drop goes straight into an inventory (auto-loot): the loot rule of the
+// killer's party picks whose. Getting a drop runs the quest pickup triggers.
+func (h *Handler) dropLoot(ctx context.Context, killer *model.Player, npc *model.Npc) {
+	drops := npc.Template().Drops()
+	if len(drops) == 0 {
+		return
+	}
+	rnd := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	var looted []*model.Player
+	for _, d := range drops {
+		count := d.Roll(rnd)
+		if count == 0 {
+			continue
+		}
+		receiver := h.parties.LootReceiver(killer, false)
+		if _, err := receiver.Inventory().AddByType(d.ItemType, count); err != nil {
+			slog.Warn("failed to give dropped item", "player", receiver.Name(), "item", d.ItemType, "count", count, "error", err)
+			continue
+		}
+		slog.Debug("player looted item", "player", receiver.Name(), "npc", npc.TemplateID(), "item", d.ItemType, "count", count)
+		if !slices.Contains(looted, receiver) {
+			looted = append(looted, receiver)
+		}
+		h.notifyItemPickup(ctx, receiver, d.ItemType)
+	}
+	for _, p := range looted {
+		h.saveInventory(ctx, p)
+	}
+}
+
+// AttackableConfig returns the dependencies of monster AI backed by this handler.
+func (h *Handler) AttackableConfig() ai.AttackableConfig {
+	return ai.AttackableConfig{
+		Players:            h,
+		Combat:             npcCombat{h},
+		Zones:              h.zones,
+		Paths:              h.paths,
+		Listener:           moveBroadcaster{h},
+		TickInterval:       npcTickInterval,
+		CombatTickInterval: npcCombatTickInterval,
+	}
+}
@@ -0,0 +1,215 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/party"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// npcDeathLog records NPCs handed over after death.
+type npcDeathLog []*model.Npc
+
+func (l *npcDeathLog) OnNpcDeath(npc *model.Npc) {
+	*l = append(*l, npc)
+}
+
+// newMonsterHandler creates a handler whose players can attack the returned monster,
+// standing next to the spot where newInGameClient puts players.
+func newMonsterHandler(t *testing.T, opts ...Option) (*Handler, *model.Monster, *ai.AttackableAI, *npcDeathLog) {
+	t.Helper()
+	tmpl := model.NewNpcTemplate(2002, "Wolf", "", 10, 1000, 50,
+		30, 40, 10, 10, 0, 100, 253, 30, 60)
+	monster := model.NewMonster(350002, 2002, tmpl)
+	monster.SetSpawn(model.NewSpawn(-350002, 2002, 17050, 170000, -3500, 0, 1, false))
+	monster.SetLocation(model.NewLocation(17050, 170000, -3500, 0))
+	if err := world.Instance().AddObject(monster.WorldObject); err != nil {
+		t.Fatalf("AddObject: %v", err)
+	}
+	t.Cleanup(func() { world.Instance().RemoveObject(monster.ObjectID()) })
+
+	aiMgr := ai.NewTickManager()
+	deaths := &npcDeathLog{}
+	opts = append(opts, WithNpcs(npcMap{monster.ObjectID(): monster.Npc}), WithNpcCombat(aiMgr, deaths))
+	h := NewHandler(login.NewSessionManager(), opts...)
+
+	ctrl := ai.NewAttackableAI(monster, h.AttackableConfig())
+	aiMgr.Register(monster.ObjectID(), ctrl)
+	return h, monster, ctrl, deaths
+}
+
+func TestHandler_MonsterAttacksPlayer(t *testing.T) {
+	h := NewHandler(login.NewSessionManager())
+	client := newInGameClient(t, h, 9801, "Bait")
+	player := client.ActivePlayer()
+
+	tmpl := model.NewNpcTemplate(2001, "Orc", "", 10, 1000, 200,
+		50, 40, 20, 20, 300, 100, 253, 30, 60)
+	monster := model.NewMonster(350001, 2001, tmpl)
+	monster.SetSpawn(model.NewSpawn(-350001, 2001, 17150, 170000, -3500, 0, 1, false))
+	monster.SetLocation(model.NewLocation(17150, 170000, -3500, 0))
+	if err := world.Instance().AddObject(monster.WorldObject); err != nil {
+		t.Fatalf("AddObject: %v", err)
+	}
+	t.Cleanup(func() { world.Instance().RemoveObject(monster.ObjectID()) })
+
+	if _, ok := h.FindPlayer(monster.ObjectID()); ok {
+		t.Fatal("FindPlayer should not resolve NPCs")
+	}
+
+	ctrl := ai.NewAttackableAI(monster, h.AttackableConfig())
+	ctrl.Start()
+	hp := player.CurrentHP()
+
+	// Combat ticks: aggro, a second of chasing, then the hit
+	for range 4 {
+		ctrl.Tick()
+	}
+	want := hp - 70*tmpl.PAtk()/playerPDef
+	if player.CurrentHP() != want {
+		t.Errorf("player HP = %d, want %d", player.CurrentHP(), want)
+	}
+	if d := monster.Location(); d.X-player.X() > ai.AttackRange {
+		t.Errorf("monster at %v, should have closed in on the player", d)
+	}
+
+	// HP never drops below zero
+	player.SetCurrentHP(1)
+	npcCombat{h}.Attack(monster.Npc, player)
+	if player.CurrentHP() != 0 {
+		t.Errorf("player HP = %d, want 0", player.CurrentHP())
+	}
+}
+
+func TestHandler_PlayerAttacksMonster(t *testing.T) {
+	ctx := context.Background()
+	h, monster, ctrl, deaths := newMonsterHandler(t)
+	client := newInGameClient(t, h, 9802, "Hunter")
+	player := client.ActivePlayer()
+	buf := make([]byte, 1024)
+
+	n, ok, err := h.HandlePacket(ctx, client, attackPacket(monster.ObjectID()), buf)
+	if err != nil || !ok || n != 0 {
+		t.Fatalf("AttackRequest: n=%d ok=%v err=%v", n, ok, err)
+	}
+	damage := int32(70 * playerPAtk / monster.PDef())
+	if monster.CurrentHP() != monster.MaxHP()-damage {
+		t.Errorf("monster HP = %d, want %d", monster.CurrentHP(), monster.MaxHP()-damage)
+	}
+	if top, ok := ctrl.HateList().Top(); !ok || top != player.ObjectID() {
+		t.Errorf("hate list top = %d, want attacker %d", top, player.ObjectID())
+	}
+	if ctrl.HateList().Hate(player.ObjectID()) != int64(damage) {
+		t.Errorf("hate = %d, want %d", ctrl.HateList().Hate(player.ObjectID()), damage)
+	}
+
+	// Hits that kill hand the corpse over once
+	for !monster.IsDead() {
+		if _, _, err := h.HandlePacket(ctx, client, attackPacket(monster.ObjectID()), buf); err != nil {
+			t.Fatalf("AttackRequest: %v", err)
+		}
+	}
+	if len(*deaths) != 1 || (*deaths)[0] != monster.Npc {
+		t.Fatalf("deaths = %v, want the monster once", *deaths)
+	}
+
+	// Dead monsters cannot be hit
+	n, _, _ = h.HandlePacket(ctx, client, attackPacket(monster.ObjectID()), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("attacking a corpse: n=%d opcode=0x%02X, want ActionFailed", n, buf[0])
+	}
+	if len(*deaths) != 1 {
+		t.Errorf("corpse died again: %d deaths", len(*deaths))
+	}
+}
+
+func TestHandler_AttackNpcWithoutAI(t *testing.T) {
+	ctx := context.Background()
+	npc := newQuestNpc(500010, questNpcTemplate, 17050)
+	h := NewHandler(login.NewSessionManager(), WithNpcs(npcMap{npc.ObjectID(): npc}),
+		WithNpcCombat(ai.NewTickManager(), &npcDeathLog{}))
+	client := newInGameClient(t, h, 9803, "Rude")
+	buf := make([]byte, 1024)
+
+	// Town NPCs without monster AI cannot be attacked
+	n, _, _ := h.HandlePacket(ctx, client, attackPacket(npc.ObjectID()), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("attacking a town NPC: n=%d opcode=0x%02X, want ActionFailed", n, buf[0])
+	}
+	if npc.CurrentHP() != npc.MaxHP() {
+		t.Errorf("town NPC HP = %d, want %d", npc.CurrentHP(), npc.MaxHP())
+	}
+}
+
+func TestHandler_MonsterKillRewardsParty(t *testing.T) {
+	ctx := context.Background()
+	h, monster, _, _ := newMonsterHandler(t)
+	monster.Template().SetRewards(1000, 100)
+	killer := newInGameClient(t, h, 9804, "Killer").ActivePlayer()
+	mate := newInGameClient(t, h, 9805, "Mate").ActivePlayer()
+	killerClient, _ := h.Clients().ByObjectID(killer.ObjectID())
+	buf := make([]byte, 1024)
+
+	if err := h.Parties().Invite(killer, mate, 0); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, _, err := h.Parties().Answer(mate, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+
+	for !monster.IsDead() {
+		if _, _, err := h.HandlePacket(ctx, killerClient, attackPacket(monster.ObjectID()), buf); err != nil {
+			t.Fatalf("AttackRequest: %v", err)
+		}
+	}
+
+	// Same level members share evenly, with the two-member party bonus (x1.3)
+	for _, p := range []*model.Player{killer, mate} {
+		if p.Experience() != 650 || p.SP() != 65 {
+			t.Errorf("%s: exp=%d sp=%d, want 650 and 65", p.Name(), p.Experience(), p.SP())
+		}
+	}
+}
+
+func TestHandler_MonsterKillDropsLoot(t *testing.T) {
+	ctx := context.Background()
+	h, monster, _, _ := newMonsterHandler(t)
+	monster.Template().SetDrops([]model.NpcDrop{
+		{ItemType: model.AdenaItemID, Min: 50, Max: 50, Chance: model.DropChanceMax},
+		{ItemType: 1864, Min: 2, Max: 2, Chance: model.DropChanceMax},
+		{ItemType: 1865, Min: 1, Max: 1, Chance: 0},
+	})
+	killer := newInGameClient(t, h, 9806, "Looter").ActivePlayer()
+	mate := newInGameClient(t, h, 9807, "Turn").ActivePlayer()
+	killerClient, _ := h.Clients().ByObjectID(killer.ObjectID())
+	buf := make([]byte, 1024)
+
+	if err := h.Parties().Invite(killer, mate, party.LootByTurn); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, _, err := h.Parties().Answer(mate, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+
+	for !monster.IsDead() {
+		if _, _, err := h.HandlePacket(ctx, killerClient, attackPacket(monster.ObjectID()), buf); err != nil {
+			t.Fatalf("AttackRequest: %v", err)
+		}
+	}
+
+	// By turn: the leader gets the first drop, the next member the second
+	if got := killer.Inventory().Adena(); got != 50 {
+		t.Errorf("killer adena = %d, want 50", got)
+	}
+	if got := mate.Inventory().CountOf(1864); got != 2 {
+		t.Errorf("mate item count = %d, want 2", got)
+	}
+	if killer.Inventory().CountOf(1865) != 0 || mate.Inventory().CountOf(1865) != 0 {
+		t.Error("drop with zero chance fell")
+	}
+}
@@ -0,0 +1,289 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// reviveHPRatio is the part of max HP a player comes back with (L2J RespawnRestoreHP).
+const reviveHPRatio = 0.65
+
+// resurrectRange is how close a player must be to resurrect someone.
+const resurrectRange = 400
+
+// resurrectionScrolls are the scrolls that resurrect the selected dead player,
+// with the percent of lost experience they restore (L2J skills 2014 and 2049).
+var resurrectionScrolls = map[int32]float64{
+	737:  0,  // Scroll of Resurrection
+	3936: 70, // Blessed Scroll of Resurrection
+}
+
+// ClanRestartPoints finds where clan members restart after death.
+type ClanRestartPoints interface {
+	ClanHall(clanID int32) (model.Location, bool)
+	Castle(clanID int32) (model.Location, bool)
+}
+
+// deathState is what is kept about a dead player until it restarts or is resurrected.
+type deathState struct {
+	level          int32 // before the exp penalty
+	expBeforeDeath int64
+	options        *serverpackets.Die // restart options offered to the player
+
+	mu          sync.Mutex
+	reviverID   uint32 // player offering resurrection (0 = none)
+	revivePower float64
+}
+
+// deathExpPenalty returns the experience a player of level loses at death: a level-scaled
+// percent of the experience between this level and the next one, a quarter of it in sieges.
+func deathExpPenalty(level int32, siege bool) int64 {
+	percent := 7.0
+	switch {
+	case level >= 76:
+		percent = 2.0
+	case level >= 40:
+		percent = 4.0
+	}
+	if siege {
+		percent /= 4
+	}
+	return int64(math.Round(float64(model.ExpToNextLevel(level)) * percent / 100))
+}
+
+// damagePlayer takes HP from a player and kills it when no HP is left;
+// killer is the attacking player (nil for monsters and zones).
+func (h *Handler) damagePlayer(p *model.Player, damage int32, killer *model.Player) {
+	hp, killed := p.ReduceHP(damage)
+	h.sendToPlayer(p, &serverpackets.StatusUpdate{
+		ObjectID: p.ObjectID(),
+		Attrs:    []serverpackets.StatusAttr{{ID: serverpackets.StatusCurHP, Value: hp}},
+	})
+	if killed {
+		h.die(p, killer)
+	}
+}
+
+// die turns a player with no HP left into a corpse: it stops, loses experience unless it
+// died in a PvP zone, and gets the restart options. Dead players can do nothing but restart
+// or accept resurrection. Karma players lose more experience and may drop items.
+func (h *Handler) die(p *model.Player, killer *model.Player) {
+	st := &deathState{level: p.Level(), expBeforeDeath: p.Experience(), options: h.restartOptions(p)}
+	if _, dead := h.deaths.LoadOrStore(p.ObjectID(), st); dead {
+		return
+	}
+	h.stopMoving(p)
+	h.escapes.Delete(p.ObjectID())
+
+	if !h.zones.InsideZone(p.WorldObject, zone.PvP) {
+		lost := deathExpPenalty(st.level, h.zones.InsideZone(p.WorldObject, zone.Siege))
+		if p.Karma() > 0 {
+			lost = int64(math.Round(float64(lost) * h.karma.ExpLostRate))
+		}
+		p.LoseExperience(lost, h.delevel)
+		h.sendExpUpdate(p)
+		// Karma players keep their items: the drop waits for items that can lie on the ground.
+	}
+	if killer != nil {
+		h.onPlayerKill(killer, p)
+	}
+
+	h.broadcastAround(p.Location(), st.options)
+	slog.Debug("player died", "player", p.Name(), "location", p.Location())
+}
+
+// restartOptions returns the Die packet of a player with the restarts it may choose.
+func (h *Handler) restartOptions(p *model.Player) *serverpackets.Die {
+	die := &serverpackets.Die{ObjectID: p.ObjectID(), Village: true}
+	if h.clanRestarts != nil && p.ClanID() != 0 {
+		_, die.ClanHall = h.clanRestarts.ClanHall(p.ClanID())
+		_, die.Castle = h.clanRestarts.Castle(p.ClanID())
+	}
+	return die
+}
+
+// sendExpUpdate sends the level and experience of a player to its client.
+func (h *Handler) sendExpUpdate(p *model.Player) {
+	h.sendToPlayer(p, &serverpackets.StatusUpdate{
+		ObjectID: p.ObjectID(),
+		Attrs: []serverpackets.StatusAttr{
+			{ID: serverpackets.StatusLevel, Value: p.Level()},
+			{ID: serverpackets.StatusExp, Value: int32(p.Experience())},
+		},
+	})
+}
+
+// revive brings a dead player back to life with part of its HP and power percent
+// of the experience it lost at death.
+func (h *Handler) revive(p *model.Player, power float64) {
+	v, ok := h.deaths.LoadAndDelete(p.ObjectID())
+	if !ok {
+		return
+	}
+	st := v.(*deathState)
+
+	if lost := st.expBeforeDeath - p.Experience(); lost > 0 && power > 0 {
+		p.AddExperience(int64(math.Round(float64(lost) * power / 100)))
+		if p.Level() < st.level && p.Experience() >= model.ExpForLevel(st.level) {
+			_ = p.SetLevel(st.level) // st.level is a valid level of this player
+		}
+		h.sendExpUpdate(p)
+	}
+
+	hp := int32(float64(p.MaxHP()) * reviveHPRatio)
+	p.SetCurrentHP(hp)
+	h.broadcastAround(p.Location(), &serverpackets.Revive{ObjectID: p.ObjectID()})
+	h.sendToPlayer(p, &serverpackets.StatusUpdate{
+		ObjectID: p.ObjectID(),
+		Attrs:    []serverpackets.StatusAttr{{ID: serverpackets.StatusCurHP, Value: hp}},
+	})
+	slog.Debug("player revived", "player", p.Name(), "power", power)
+}
+
+// handleRequestRestartPoint processes RequestRestartPoint (opcode 0x6D): a dead player
+// restarts at one of the points Die offered it.
+func (h *Handler) handleRequestRestartPoint(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestRestartPoint(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestRestartPoint: %w", err)
+	}
+
+	v, dead := h.deaths.Load(player.ObjectID())
+	if !dead {
+		return actionFailed(buf)
+	}
+	options := v.(*deathState).options
+
+	var (
+		loc model.Location
+		ok  bool
+	)
+	switch pkt.PointType {
+	case clientpackets.RestartVillage:
+		if loc, ok = h.jailCell(player); ok {
+			break // jailed players restart in the jail
+		}
+		if loc, ok = h.zones.RestartPoint(player.Location()); !ok {
+			// No towns loaded: stand up where the player fell
+			loc, ok = player.Location(), true
+		}
+	case clientpackets.RestartClanHall:
+		if options.ClanHall {
+			loc, ok = h.clanRestarts.ClanHall(player.ClanID())
+		}
+	case clientpackets.RestartCastle:
+		if options.Castle {
+			loc, ok = h.clanRestarts.Castle(player.ClanID())
+		}
+	case clientpackets.RestartFixed:
+		loc, ok = player.Location(), options.Fixed
+	}
+	// Siege headquarters wait for castle sieges
+	if !ok {
+		return actionFailed(buf)
+	}
+
+	if loc != player.Location() {
+		if err := h.Teleport(player, loc); err != nil {
+			slog.Error("failed to move player to restart point", "player", player.Name(), "error", err)
+			return actionFailed(buf)
+		}
+	}
+	h.revive(player, 0)
+	return 0, true, nil
+}
+
+// offerResurrection asks a dead player whether it accepts resurrection by reviver,
+// restoring power percent of the experience lost at death. Only scrolls offer it:
+// resurrection skills wait for the skill system.
+// Returns false if target is not dead or already has an offer to answer.
+func (h *Handler) offerResurrection(reviver, target *model.Player, power float64) bool {
+	v, dead := h.deaths.Load(target.ObjectID())
+	if !dead {
+		return false
+	}
+	st := v.(*deathState)
+
+	st.mu.Lock()
+	if st.reviverID != 0 {
+		st.mu.Unlock()
+		return false
+	}
+	st.reviverID, st.revivePower = reviver.ObjectID(), power
+	st.mu.Unlock()
+
+	restored := math.Round(float64(max(st.expBeforeDeath-target.Experience(), 0)) * power / 100)
+	h.sendToPlayer(target, &serverpackets.ConfirmDlg{
+		MessageID:   serverpackets.SystemMessageResurrectRequest,
+		Params:      []string{reviver.Name(), strconv.FormatInt(int64(restored), 10)},
+		RequesterID: reviver.ObjectID(),
+	})
+	return true
+}
+
+// useResurrectionScroll offers resurrection to the dead player selected by player
+// and consumes the scroll.
+func (h *Handler) useResurrectionScroll(ctx context.Context, player *model.Player, item *model.Item, power float64, buf []byte) (int, bool, error) {
+	target := h.findPlayer(player.Target())
+	if target == nil || !target.IsDead() ||
+		player.Location().DistanceSquared(target.Location()) > resurrectRange*resurrectRange {
+		return actionFailed(buf)
+	}
+	if !h.offerResurrection(player, target, power) {
+		return actionFailed(buf)
+	}
+	if err := player.Inventory().DestroyByType(item.ItemType(), 1); err != nil {
+		return actionFailed(buf)
+	}
+	h.saveInventory(ctx, player)
+	return 0, true, nil
+}
+
+// handleDlgAnswer processes DlgAnswer (opcode 0xC5). Only resurrection offers are asked so far.
+func (h *Handler) handleDlgAnswer(client *GameClient, data []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseDlgAnswer(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing DlgAnswer: %w", err)
+	}
+	if pkt.MessageID != serverpackets.SystemMessageResurrectRequest {
+		return 0, true, nil
+	}
+
+	v, dead := h.deaths.Load(player.ObjectID())
+	if !dead {
+		return 0, true, nil
+	}
+	st := v.(*deathState)
+
+	st.mu.Lock()
+	offered := st.reviverID != 0 && st.reviverID == uint32(pkt.RequesterID)
+	power := st.revivePower
+	if offered {
+		st.reviverID, st.revivePower = 0, 0
+	}
+	st.mu.Unlock()
+
+	if offered && pkt.Accepted {
+		h.revive(player, power)
+	}
+	return 0, true, nil
+}
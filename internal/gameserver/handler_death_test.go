@@ -0,0 +1,153 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestDeathExpPenalty(t *testing.T) {
+	tests := []struct {
+		level int32
+		siege bool
+		want  int64
+	}{
+		{20, false, 13154}, // 7% of 187921
+		{40, false, 68566}, // 4% of 1714151
+		{76, false, 923130},
+		{80, false, 1058502}, // the last level width
+		{40, true, 17142},
+	}
+	for _, tt := range tests {
+		if got := deathExpPenalty(tt.level, tt.siege); got != tt.want {
+			t.Errorf("deathExpPenalty(%d, %v) = %d, want %d", tt.level, tt.siege, got, tt.want)
+		}
+	}
+}
+
+func restartPacket(pointType int32) []byte {
+	w := packet.NewWriter(8)
+	_ = w.WriteByte(clientpackets.OpcodeRequestRestartPoint)
+	w.WriteInt(pointType)
+	return w.Bytes()
+}
+
+func TestHandler_DeathAndRestart(t *testing.T) {
+	ctx := context.Background()
+	h := newTeleportHandler(t, npcMap{})
+	client := newInGameClient(t, h, 9961, "Victim")
+	player := client.ActivePlayer()
+	player.SetExperience(model.ExpForLevel(20) + 1000)
+	buf := make([]byte, 1024)
+
+	// Restarting alive does nothing
+	if n, _, _ := h.HandlePacket(ctx, client, restartPacket(clientpackets.RestartVillage), buf); n == 0 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Fatal("restart of a living player should fail")
+	}
+
+	h.damagePlayer(player, player.MaxHP()+100, nil)
+	if !player.IsDead() {
+		t.Fatal("player should be dead")
+	}
+	if player.Level() != 19 || player.Experience() != model.ExpForLevel(20)+1000-13154 {
+		t.Errorf("after death: level %d, exp %d", player.Level(), player.Experience())
+	}
+	// Hits on a corpse take no more experience
+	h.damagePlayer(player, 100, nil)
+	if player.Experience() != model.ExpForLevel(20)+1000-13154 {
+		t.Error("dying twice took experience twice")
+	}
+
+	// Dead players cannot walk
+	move := moveRequest(17500, 170000, -3500, clientpackets.MoveByMouse, player.Location())
+	if n, _, _ := h.HandlePacket(ctx, client, move, buf); n == 0 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Error("dead player should not move")
+	}
+	// Clan hall and castle were not offered
+	if n, _, _ := h.HandlePacket(ctx, client, restartPacket(clientpackets.RestartCastle), buf); n == 0 || !player.IsDead() {
+		t.Error("restart at a castle that was not offered")
+	}
+
+	if _, _, err := h.HandlePacket(ctx, client, restartPacket(clientpackets.RestartVillage), buf); err != nil {
+		t.Fatalf("RequestRestartPoint: %v", err)
+	}
+	if player.IsDead() {
+		t.Fatal("player should be alive after restart")
+	}
+	if loc := player.Location(); loc.X != -80826 || loc.Y != 149775 {
+		t.Errorf("restarted at %v, want the Gludin restart point", loc)
+	}
+	if want := int32(float64(player.MaxHP()) * reviveHPRatio); player.CurrentHP() != want {
+		t.Errorf("HP after restart = %d, want %d", player.CurrentHP(), want)
+	}
+}
+
+func TestHandler_Resurrection(t *testing.T) {
+	ctx := context.Background()
+	h := newTeleportHandler(t, npcMap{})
+	dead := newInGameClient(t, h, 9962, "Fallen")
+	healer := newInGameClient(t, h, 9963, "Healer")
+	victim := dead.ActivePlayer()
+	victim.SetExperience(model.ExpForLevel(20) + 5000)
+	scroll := giveItem(t, healer.ActivePlayer(), 3, 3936, 1)
+	buf := make([]byte, 1024)
+
+	h.damagePlayer(victim, victim.MaxHP(), nil)
+	if victim.Level() != 19 {
+		t.Fatalf("level after death = %d, want 19", victim.Level())
+	}
+
+	action := packet.NewWriter(32)
+	_ = action.WriteByte(clientpackets.OpcodeAction)
+	action.WriteInt(int32(victim.ObjectID()))
+	action.WriteBytes(make([]byte, 13))
+	_, _, _ = h.HandlePacket(ctx, healer, action.Bytes(), buf)
+	if healer.ActivePlayer().Target() != victim.ObjectID() {
+		t.Fatal("Action should select the dead player")
+	}
+
+	use := packet.NewWriter(16)
+	_ = use.WriteByte(clientpackets.OpcodeUseItem)
+	use.WriteInt(int32(scroll.ObjectID()))
+	use.WriteInt(0)
+	if n, _, err := h.HandlePacket(ctx, healer, use.Bytes(), buf); err != nil || n != 0 {
+		t.Fatalf("UseItem: n=%d err=%v", n, err)
+	}
+	if healer.ActivePlayer().Inventory().CountOf(3936) != 0 {
+		t.Error("scroll not consumed")
+	}
+	if h.offerResurrection(healer.ActivePlayer(), victim, 100) {
+		t.Error("second offer should wait for the answer to the first")
+	}
+
+	answer := func(requesterID uint32) []byte {
+		w := packet.NewWriter(16)
+		_ = w.WriteByte(clientpackets.OpcodeDlgAnswer)
+		w.WriteInt(serverpackets.SystemMessageResurrectRequest)
+		w.WriteInt(1)
+		w.WriteInt(int32(requesterID))
+		return w.Bytes()
+	}
+	// Answers to someone else's offer are ignored
+	_, _, _ = h.HandlePacket(ctx, dead, answer(victim.ObjectID()), buf)
+	if !victim.IsDead() {
+		t.Fatal("revived by an answer to nobody")
+	}
+	if _, _, err := h.HandlePacket(ctx, dead, answer(healer.ActivePlayer().ObjectID()), buf); err != nil {
+		t.Fatalf("DlgAnswer: %v", err)
+	}
+	if victim.IsDead() {
+		t.Fatal("player should be resurrected")
+	}
+	// 70% of 13154 lost: 9208 back, enough to regain level 20
+	if victim.Experience() != model.ExpForLevel(20)+5000-13154+9208 || victim.Level() != 20 {
+		t.Errorf("after resurrection: level %d, exp %d", victim.Level(), victim.Experience())
+	}
+	if loc := victim.Location(); loc.X != 17000 {
+		t.Errorf("resurrected at %v, want where the player fell", loc)
+	}
+}
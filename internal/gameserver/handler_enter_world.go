@@ -0,0 +1,148 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// CharacterStore loads the characters of an account, with their inventories.
+type CharacterStore interface {
+	LoadCharacters(ctx context.Context, accountID int64) ([]*model.Player, error)
+}
+
+// CharacterLoaderFunc adapts a function to CharacterStore.
+type CharacterLoaderFunc func(ctx context.Context, accountID int64) ([]*model.Player, error)
+
+// LoadCharacters calls f.
+func (f CharacterLoaderFunc) LoadCharacters(ctx context.Context, accountID int64) ([]*model.Player, error) {
+	return f(ctx, accountID)
+}
+
+// sendCharSelectionInfo loads the characters of an authenticated account
+// and shows them on the selection screen.
+func (h *Handler) sendCharSelectionInfo(ctx context.Context, client *GameClient, accountID int64) error {
+	var chars []*model.Player
+	if h.characters != nil && accountID != 0 {
+		var err error
+		chars, err = h.characters.LoadCharacters(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("loading characters of %s: %w", client.AccountName(), err)
+		}
+	}
+	client.SetCharacters(chars)
+
+	var sessionID int32
+	if sk := client.SessionKey(); sk != nil {
+		sessionID = sk.PlayOkID1
+	}
+	sendPacket(client, &serverpackets.CharSelectionInfo{
+		Account:    client.AccountName(),
+		SessionID:  sessionID,
+		Characters: chars,
+	})
+	return nil
+}
+
+// handleCharacterSelect processes CharacterSelect (opcode 0x0D).
+func (h *Handler) handleCharacterSelect(client *GameClient, data []byte) (int, bool, error) {
+	if client.State() != ClientStateAuthenticated {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseCharacterSelect(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing CharacterSelect: %w", err)
+	}
+
+	player, ok := client.SelectCharacter(pkt.Slot)
+	if !ok {
+		slog.Warn("character select from an empty slot",
+			"account", client.AccountName(),
+			"slot", pkt.Slot,
+			"client", client.IP())
+		return 0, true, nil
+	}
+
+	var sessionID int32
+	if sk := client.SessionKey(); sk != nil {
+		sessionID = sk.PlayOkID1
+	}
+	client.SetState(ClientStateEntering)
+	sendPacket(client, &serverpackets.CharSelected{Player: player, SessionID: sessionID})
+	return 0, true, nil
+}
+
+// handleEnterWorld processes EnterWorld (opcode 0x03).
+func (h *Handler) handleEnterWorld(ctx context.Context, client *GameClient) (int, bool, error) {
+	if client.State() != ClientStateEntering {
+		return 0, true, nil
+	}
+	player := client.SelectedCharacter()
+	if player == nil {
+		return 0, false, fmt.Errorf("EnterWorld without a selected character from %s", client.AccountName())
+	}
+
+	if err := h.enterWorld(ctx, client, player); err != nil {
+		return 0, false, err
+	}
+	return 0, true, nil
+}
+
+// enterWorld puts the selected character of a client into the world: the client
+// is registered, the player appears to others and the subsystems pick it up.
+func (h *Handler) enterWorld(ctx context.Context, client *GameClient, p *model.Player) error {
+	if trader := h.takeOfflineTrader(ctx, p); trader != nil {
+		p = trader
+	}
+	if err := world.Instance().AddObject(p.WorldObject); err != nil {
+		return fmt.Errorf("entering world as %s: %w", p.Name(), err)
+	}
+	client.SetActivePlayer(p)
+	h.clients.Register(client)
+	client.SetState(ClientStateInGame)
+	p.UpdateLastLogin()
+
+	_, gm := h.access.Level(client.AccessLevel())
+	sendPacket(client, &serverpackets.UserInfo{CharInfo: *h.charInfoOf(p), GM: gm})
+	h.AttachClan(p)
+	if err := h.AttachQuests(ctx, p); err != nil {
+		slog.Error("failed to load quests", "player", p.Name(), "error", err)
+	}
+	if err := h.AttachFriends(ctx, p); err != nil {
+		slog.Error("failed to load friends", "player", p.Name(), "error", err)
+	}
+	h.applyPunishments(p)
+	h.revalidateZones(p)
+	h.broadcastCharInfo(p)
+
+	slog.Info("player entered world",
+		"account", client.AccountName(),
+		"player", p.Name(),
+		"client", client.IP())
+	return nil
+}
+
+// takeOfflineTrader closes the offline store of a character entering the world again
+// and takes the trader out of the world. The trader is returned: its inventory is
+// newer than the one loaded at login. Returns nil if the character is not trading.
+func (h *Handler) takeOfflineTrader(ctx context.Context, p *model.Player) *model.Player {
+	if h.offlineStores == nil {
+		return nil
+	}
+	trader, ok := h.offlineStores.Get(p.ObjectID())
+	if !ok {
+		return nil
+	}
+	if err := h.offlineStores.Release(ctx, trader); err != nil {
+		slog.Error("failed to release offline store", "player", p.Name(), "error", err)
+	}
+	h.stores.Forget(trader)
+	world.Instance().RemoveObject(trader.ObjectID())
+	return trader
+}
@@ -0,0 +1,201 @@
+package gameserver
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// characterMap is a CharacterStore keyed by account ID.
+type characterMap map[int64][]*model.Player
+
+func (m characterMap) LoadCharacters(_ context.Context, accountID int64) ([]*model.Player, error) {
+	return m[accountID], nil
+}
+
+// newCharacter creates a character of account standing at x.
+func newCharacter(t *testing.T, characterID, accountID int64, name string, x int32) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(characterID, accountID, name, 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLocation(model.NewLocation(x, 170000, -3500, 0))
+	t.Cleanup(func() { world.Instance().RemoveObject(p.ObjectID()) })
+	return p
+}
+
+// closeConn is a MockConn that remembers being closed.
+type closeConn struct {
+	*testutil.MockConn
+	closed atomic.Bool
+}
+
+func newCloseConn() *closeConn {
+	return &closeConn{MockConn: testutil.NewMockConn()}
+}
+
+func (c *closeConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+// loginClient connects a client of account to h through AuthLogin.
+func loginClient(t *testing.T, h *Handler, sessions *login.SessionManager, account string, conn net.Conn) *GameClient {
+	t.Helper()
+	key := login.SessionKey{PlayOkID1: 11, PlayOkID2: 12, LoginOkID1: 13, LoginOkID2: 14}
+	sessions.Store(account, key, &login.Client{})
+
+	client, err := NewGameClient(conn, make([]byte, 16))
+	if err != nil {
+		t.Fatalf("NewGameClient: %v", err)
+	}
+	client.SetState(ClientStateAuthenticated)
+	if _, _, err := h.HandlePacket(context.Background(), client, prepareAuthLoginPacket(account, key), make([]byte, 1024)); err != nil {
+		t.Fatalf("AuthLogin: %v", err)
+	}
+	return client
+}
+
+// enterWorldAs selects the character in slot and enters the world with it.
+func enterWorldAs(t *testing.T, h *Handler, client *GameClient, slot int32) {
+	t.Helper()
+	ctx := context.Background()
+	buf := make([]byte, 1024)
+
+	w := packet.NewWriter(23)
+	_ = w.WriteByte(clientpackets.OpcodeCharacterSelect)
+	w.WriteInt(slot)
+	w.WriteShort(0)
+	for range 4 {
+		w.WriteInt(0)
+	}
+	if _, _, err := h.HandlePacket(ctx, client, w.Bytes(), buf); err != nil {
+		t.Fatalf("CharacterSelect: %v", err)
+	}
+	if _, _, err := h.HandlePacket(ctx, client, []byte{clientpackets.OpcodeEnterWorld}, buf); err != nil {
+		t.Fatalf("EnterWorld: %v", err)
+	}
+}
+
+// newWorldHandler creates a handler whose accounts own the given characters.
+func newWorldHandler(t *testing.T, accounts accountMap, chars characterMap, opts ...Option) (*Handler, *login.SessionManager) {
+	t.Helper()
+	sessions := login.NewSessionManager()
+	opts = append(opts, WithAccounts(accounts), WithCharacters(chars))
+	return NewHandler(sessions, opts...), sessions
+}
+
+func TestHandler_EnterWorld(t *testing.T) {
+	first := newCharacter(t, 9701, 7, "Первый", 17000)
+	second := newCharacter(t, 9702, 7, "Second", 17100)
+	h, sessions := newWorldHandler(t,
+		accountMap{"hero": {ID: 7, Login: "hero"}},
+		characterMap{7: {first, second}},
+	)
+
+	client := loginClient(t, h, sessions, "hero", testutil.NewMockConn())
+	if got := client.Characters(); len(got) != 2 {
+		t.Fatalf("characters after AuthLogin = %d, want 2", len(got))
+	}
+
+	enterWorldAs(t, h, client, 1)
+
+	if client.State() != ClientStateInGame {
+		t.Errorf("state = %v, want IN_GAME", client.State())
+	}
+	if client.ActivePlayer() != second {
+		t.Fatalf("active player = %v, want %s", client.ActivePlayer(), second.Name())
+	}
+	if c, ok := h.Clients().ByObjectID(second.ObjectID()); !ok || c != client {
+		t.Error("client is not registered under the character")
+	}
+	if c, ok := h.Clients().ByName("second"); !ok || c != client {
+		t.Error("client is not registered under the character name")
+	}
+	if _, ok := world.Instance().GetObject(second.ObjectID()); !ok {
+		t.Error("character is not in the world")
+	}
+	if err := h.Friends().SetBlockAll(second, false); err != nil {
+		t.Errorf("friend lists not loaded on enter world: %v", err)
+	}
+
+	h.OnDisconnect(client)
+	if _, ok := h.Clients().ByObjectID(second.ObjectID()); ok {
+		t.Error("client still registered after disconnect")
+	}
+	if _, ok := world.Instance().GetObject(second.ObjectID()); ok {
+		t.Error("character still in the world after disconnect")
+	}
+	if err := h.Friends().SetBlockAll(second, false); err == nil {
+		t.Error("friend lists still loaded after disconnect")
+	}
+}
+
+func TestHandler_CharacterSelect_Invalid(t *testing.T) {
+	h, sessions := newWorldHandler(t,
+		accountMap{"hero": {ID: 7, Login: "hero"}},
+		characterMap{7: {newCharacter(t, 9703, 7, "Only", 17000)}},
+	)
+	client := loginClient(t, h, sessions, "hero", testutil.NewMockConn())
+
+	// An empty slot selects nothing; EnterWorld without a selection is ignored.
+	enterWorldAs(t, h, client, 3)
+	if client.State() != ClientStateAuthenticated {
+		t.Errorf("state = %v, want AUTHENTICATED", client.State())
+	}
+	if client.ActivePlayer() != nil {
+		t.Error("active player set without a valid selection")
+	}
+	if h.Clients().Count() != 0 {
+		t.Errorf("registered clients = %d, want 0", h.Clients().Count())
+	}
+}
+
+func TestHandler_KickAccount(t *testing.T) {
+	hero := newCharacter(t, 9704, 7, "Kicked", 17000)
+	h, sessions := newWorldHandler(t,
+		accountMap{"hero": {ID: 7, Login: "hero"}},
+		characterMap{7: {hero}},
+	)
+	if h.KickAccount("hero") {
+		t.Error("KickAccount of an account that is not connected = true")
+	}
+
+	conn := newCloseConn()
+	first := loginClient(t, h, sessions, "Hero", conn)
+	enterWorldAs(t, h, first, 0)
+
+	// The account logs in on the login server again.
+	if !h.KickAccount("hero") {
+		t.Fatal("KickAccount of an account in game = false")
+	}
+	if !conn.closed.Load() {
+		t.Error("client of the kicked account is still connected")
+	}
+
+	// The game server sees the account authenticate again before the kicked client is gone.
+	conn.closed.Store(false)
+	second := loginClient(t, h, sessions, "hero", newCloseConn())
+	if !conn.closed.Load() {
+		t.Error("second AuthLogin did not kick the first client")
+	}
+
+	// The first client disconnecting does not forget the second one.
+	h.OnDisconnect(first)
+	if !h.KickAccount("hero") {
+		t.Error("second client forgotten after the first one disconnected")
+	}
+	h.OnDisconnect(second)
+	if h.KickAccount("hero") {
+		t.Error("account still connected after its last client disconnected")
+	}
+}
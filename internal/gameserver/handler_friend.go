@@ -0,0 +1,173 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/friend"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// friendListOf builds the friend list of p with the current online status.
+func (h *Handler) friendListOf(p *model.Player) *serverpackets.FriendList {
+	contacts := h.friends.Friends(p)
+	pkt := &serverpackets.FriendList{Friends: make([]serverpackets.FriendInfo, 0, len(contacts))}
+	for _, c := range contacts {
+		pkt.Friends = append(pkt.Friends, serverpackets.FriendInfo{
+			CharacterID: c.CharacterID,
+			Name:        c.Name,
+			Online:      h.onlinePlayer(c.Name) != nil,
+		})
+	}
+	return pkt
+}
+
+// notifyFriends resends the friend list to every online friend (login/logout notification).
+func (h *Handler) notifyFriends(contacts []friend.Contact) {
+	for _, c := range contacts {
+		if f := h.onlinePlayer(c.Name); f != nil {
+			h.sendToPlayer(f, h.friendListOf(f))
+		}
+	}
+}
+
+// AttachFriends loads the friend and block lists of a player entering the world,
+// sends the friend list and notifies online friends.
+func (h *Handler) AttachFriends(ctx context.Context, p *model.Player) error {
+	contacts, err := h.friends.Attach(ctx, p)
+	if err != nil {
+		return fmt.Errorf("attaching friends of %s: %w", p.Name(), err)
+	}
+	h.sendToPlayer(p, h.friendListOf(p))
+	h.notifyFriends(contacts)
+	return nil
+}
+
+// detachFriends unloads the lists of a leaving player and notifies online friends.
+// Must be called after the client is unregistered so friends see the player offline.
+func (h *Handler) detachFriends(p *model.Player) {
+	h.notifyFriends(h.friends.Detach(p))
+}
+
+// handleRequestFriendInvite processes RequestFriendInvite (opcode 0x5E).
+func (h *Handler) handleRequestFriendInvite(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestFriendName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestFriendInvite: %w", err)
+	}
+
+	target := h.onlinePlayer(pkt.Name)
+	if target == nil {
+		return actionFailed(buf)
+	}
+
+	if err := h.friends.Invite(player, target); err != nil {
+		slog.Debug("friend invite rejected", "from", player.Name(), "to", target.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	h.sendToPlayer(target, &serverpackets.AskJoinFriend{RequesterName: player.Name()})
+	return 0, true, nil
+}
+
+// handleRequestAnswerFriendInvite processes RequestAnswerFriendInvite (opcode 0x5F).
+func (h *Handler) handleRequestAnswerFriendInvite(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestAnswerFriendInvite(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestAnswerFriendInvite: %w", err)
+	}
+
+	requester, err := h.friends.Answer(ctx, player, pkt.Accept)
+	if err != nil {
+		slog.Debug("friend invite failed", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	if !pkt.Accept {
+		return 0, true, nil
+	}
+
+	slog.Debug("players became friends", "player", player.Name(), "friend", requester.Name())
+	h.sendToPlayer(requester, h.friendListOf(requester))
+	n, err := writeToBuf(buf, h.friendListOf(player))
+	return n, true, err
+}
+
+// handleRequestFriendList processes RequestFriendList (opcode 0x60).
+func (h *Handler) handleRequestFriendList(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	n, err := writeToBuf(buf, h.friendListOf(player))
+	return n, true, err
+}
+
+// handleRequestFriendDel processes RequestFriendDel (opcode 0x61).
+func (h *Handler) handleRequestFriendDel(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestFriendName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestFriendDel: %w", err)
+	}
+
+	removed, err := h.friends.Remove(ctx, player, pkt.Name)
+	if err != nil {
+		slog.Debug("friend removal rejected", "player", player.Name(), "friend", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+
+	if f := h.onlinePlayer(removed.Name); f != nil {
+		h.sendToPlayer(f, h.friendListOf(f))
+	}
+	n, err := writeToBuf(buf, h.friendListOf(player))
+	return n, true, err
+}
+
+// handleRequestBlock processes RequestBlock (opcode 0xA0).
+func (h *Handler) handleRequestBlock(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestBlock(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestBlock: %w", err)
+	}
+
+	switch pkt.Type {
+	case clientpackets.BlockAdd:
+		_, err = h.friends.Block(ctx, player, pkt.Name)
+	case clientpackets.BlockRemove:
+		_, err = h.friends.Unblock(ctx, player, pkt.Name)
+	case clientpackets.BlockAll:
+		err = h.friends.SetBlockAll(player, true)
+	case clientpackets.BlockAllClear:
+		err = h.friends.SetBlockAll(player, false)
+	case clientpackets.BlockList:
+		// The client shows the list through system messages, which are not sent yet
+		slog.Debug("block list requested", "player", player.Name(), "count", len(h.friends.Blocks(player)))
+	}
+	if err != nil {
+		slog.Debug("block command rejected", "player", player.Name(), "type", pkt.Type, "name", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+	return 0, true, nil
+}
@@ -0,0 +1,127 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+)
+
+func TestHandler_FriendInviteAndDelete(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	alice := newInGameClient(t, h, 9401, "Alice")
+	bob := newInGameClient(t, h, 9402, "Bob")
+	for _, c := range []*GameClient{alice, bob} {
+		if err := h.AttachFriends(ctx, c.ActivePlayer()); err != nil {
+			t.Fatalf("AttachFriends: %v", err)
+		}
+	}
+	buf := make([]byte, 4096)
+
+	invite := packet.NewWriter(16)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestFriendInvite)
+	invite.WriteString("bob")
+	if n, ok, err := h.HandlePacket(ctx, alice, invite.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestFriendInvite: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if h.Friends().PendingRequester(bob.ActivePlayer()) != alice.ActivePlayer() {
+		t.Fatal("invitation was not registered")
+	}
+
+	answer := packet.NewWriter(8)
+	_ = answer.WriteByte(clientpackets.OpcodeRequestAnswerFriendInvite)
+	answer.WriteInt(1)
+	n, ok, err := h.HandlePacket(ctx, bob, answer.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeFriendList {
+		t.Fatalf("RequestAnswerFriendInvite: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if !h.Friends().IsFriend(alice.ActivePlayer(), 9402) {
+		t.Fatal("players should be friends")
+	}
+	list := h.friendListOf(alice.ActivePlayer())
+	if len(list.Friends) != 1 || list.Friends[0].Name != "Bob" || !list.Friends[0].Online {
+		t.Errorf("friend list = %+v", list.Friends)
+	}
+
+	del := packet.NewWriter(16)
+	_ = del.WriteByte(clientpackets.OpcodeRequestFriendDel)
+	del.WriteString("Bob")
+	n, ok, err = h.HandlePacket(ctx, alice, del.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeFriendList {
+		t.Fatalf("RequestFriendDel: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if h.Friends().IsFriend(bob.ActivePlayer(), 9401) {
+		t.Error("friendship should be removed on both sides")
+	}
+}
+
+func TestHandler_BlockFiltersWhisperAndInvites(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	alice := newInGameClient(t, h, 9411, "Alice")
+	bob := newInGameClient(t, h, 9412, "Bob")
+	for _, c := range []*GameClient{alice, bob} {
+		if err := h.AttachFriends(ctx, c.ActivePlayer()); err != nil {
+			t.Fatalf("AttachFriends: %v", err)
+		}
+	}
+	buf := make([]byte, 4096)
+
+	block := packet.NewWriter(16)
+	_ = block.WriteByte(clientpackets.OpcodeRequestBlock)
+	block.WriteInt(clientpackets.BlockAdd)
+	block.WriteString("Bob")
+	if n, ok, err := h.HandlePacket(ctx, alice, block.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestBlock: n=%d ok=%v err=%v", n, ok, err)
+	}
+
+	tell := packet.NewWriter(32)
+	_ = tell.WriteByte(clientpackets.OpcodeSay2)
+	tell.WriteString("hello")
+	tell.WriteInt(clientpackets.ChatTell)
+	tell.WriteString("Alice")
+	n, _, _ := h.HandlePacket(ctx, bob, tell.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("whisper to a blocking player must fail, got opcode 0x%02X", buf[0])
+	}
+
+	party := packet.NewWriter(32)
+	_ = party.WriteByte(clientpackets.OpcodeRequestJoinParty)
+	party.WriteString("Alice")
+	party.WriteInt(0)
+	n, _, _ = h.HandlePacket(ctx, bob, party.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("party invite to a blocking player must fail, got opcode 0x%02X", buf[0])
+	}
+
+	trade := packet.NewWriter(8)
+	_ = trade.WriteByte(clientpackets.OpcodeTradeRequest)
+	trade.WriteInt(int32(alice.ActivePlayer().ObjectID()))
+	n, _, _ = h.HandlePacket(ctx, bob, trade.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("trade request to a blocking player must fail, got opcode 0x%02X", buf[0])
+	}
+
+	invite := packet.NewWriter(16)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestFriendInvite)
+	invite.WriteString("Alice")
+	n, _, _ = h.HandlePacket(ctx, bob, invite.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("friend invite to a blocking player must fail, got opcode 0x%02X", buf[0])
+	}
+
+	// Alice can still whisper to Bob
+	tell = packet.NewWriter(32)
+	_ = tell.WriteByte(clientpackets.OpcodeSay2)
+	tell.WriteString("hi")
+	tell.WriteInt(clientpackets.ChatTell)
+	tell.WriteString("Bob")
+	n, _, err := h.HandlePacket(ctx, alice, tell.Bytes(), buf)
+	if err != nil || n == 0 || buf[0] != serverpackets.OpcodeCreatureSay {
+		t.Errorf("whisper from the blocking player: n=%d err=%v opcode=0x%02X", n, err, buf[0])
+	}
+}
@@ -0,0 +1,128 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// playerRunSpeed is the run speed of players in game units per second
+// (base run speed of most classes; player stats are not modelled yet).
+const playerRunSpeed = 120
+
+// movementInterval is how often moving players advance along their routes.
+const movementInterval = 100 * time.Millisecond
+
+// moveBroadcaster sends every new leg of a route as MoveToLocation to the players around.
+type moveBroadcaster struct {
+	h *Handler
+}
+
+func (b moveBroadcaster) MoveStarted(obj *model.WorldObject, from, to model.Location) {
+	pkt := &serverpackets.MoveToLocation{ObjectID: obj.ObjectID(), Dest: to, Origin: from}
+	if p := b.h.findPlayer(obj.ObjectID()); p != nil {
+		b.h.broadcastFrom(p, from, pkt)
+		return
+	}
+	b.h.broadcastAround(from, pkt)
+}
+
+// WalkConfig returns the movement settings of idle NPCs backed by this handler;
+// walk radius and rate come from the server config.
+func (h *Handler) WalkConfig() ai.WalkConfig {
+	return ai.WalkConfig{
+		Paths:        h.paths,
+		Listener:     moveBroadcaster{h},
+		TickInterval: npcTickInterval,
+	}
+}
+
+// moverOf returns the mover of a player, creating it on the first move.
+func (h *Handler) moverOf(p *model.Player) *ai.Mover {
+	if m, ok := h.movers.Load(p.ObjectID()); ok {
+		return m.(*ai.Mover)
+	}
+	m, _ := h.movers.LoadOrStore(p.ObjectID(), ai.NewMover(p.WorldObject, h.paths, moveBroadcaster{h}))
+	return m.(*ai.Mover)
+}
+
+// stopMoving drops the route of a player (e.g. on disconnect).
+func (h *Handler) stopMoving(p *model.Player) {
+	if m, ok := h.movers.LoadAndDelete(p.ObjectID()); ok {
+		m.(*ai.Mover).Stop()
+	}
+}
+
+// handleMoveBackwardToLocation processes MoveBackwardToLocation (opcode 0x01): click-to-move.
+// The route goes around walls; clients get it one waypoint at a time.
+func (h *Handler) handleMoveBackwardToLocation(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseMoveBackwardToLocation(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing MoveBackwardToLocation: %w", err)
+	}
+
+	// L2J: keyboard movement cannot be validated against geodata
+	if pkt.Movement == clientpackets.MoveByKeyboard {
+		return actionFailed(buf)
+	}
+	if player.IsDead() || player.IsSitting() || player.PrivateStoreType().IsActive() {
+		return actionFailed(buf)
+	}
+
+	if !h.moverOf(player).MoveTo(pkt.Target) {
+		return actionFailed(buf)
+	}
+	return 0, true, nil
+}
+
+// stepMovers advances all moving players by the distance covered in elapsed
+// and updates the zones of those that moved.
+func (h *Handler) stepMovers(elapsed time.Duration) {
+	dist := playerRunSpeed * elapsed.Seconds()
+	h.movers.Range(func(key, value any) bool {
+		obj, ok := world.Instance().GetObject(key.(uint32))
+		if !ok {
+			h.movers.Delete(key)
+			return true
+		}
+		before := obj.Location()
+		value.(*ai.Mover).Step(dist * h.moveSpeedFactor(obj))
+		if obj.Location() != before {
+			if p := h.findPlayer(obj.ObjectID()); p != nil {
+				h.revalidateZones(p)
+			}
+		}
+		return true
+	})
+}
+
+// RunMovement moves players along their routes, ends escape scroll casts
+// and expires PvP flags until ctx is cancelled.
+func (h *Handler) RunMovement(ctx context.Context) error {
+	ticker := time.NewTicker(movementInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			h.stepMovers(now.Sub(last))
+			h.finishEscapes(now)
+			h.expirePvPFlags(now)
+			last = now
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package gameserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// detourPathfinder routes around a wall at x = 17200 through (17200, 170300);
+// destinations with y > 171000 are unreachable.
+type detourPathfinder struct{}
+
+func (detourPathfinder) FindPath(from, to model.Location) ([]model.Location, error) {
+	if to.Y > 171000 {
+		return nil, errors.New("no path")
+	}
+	if (from.X < 17200) == (to.X < 17200) {
+		return []model.Location{to}, nil
+	}
+	return []model.Location{model.NewLocation(17200, 170300, to.Z, 0), to}, nil
+}
+
+func moveRequest(x, y, z, movement int32, origin model.Location) []byte {
+	w := packet.NewWriter(32)
+	_ = w.WriteByte(clientpackets.OpcodeMoveBackwardToLocation)
+	for _, v := range []int32{x, y, z, origin.X, origin.Y, origin.Z, movement} {
+		w.WriteInt(v)
+	}
+	return w.Bytes()
+}
+
+func TestHandler_MoveAroundWall(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager(), WithPathfinder(detourPathfinder{}))
+	client := newInGameClient(t, h, 9701, "Walker")
+	player := client.ActivePlayer()
+	buf := make([]byte, 1024)
+
+	n, ok, err := h.HandlePacket(ctx, client, moveRequest(17400, 170000, -3500, clientpackets.MoveByMouse, player.Location()), buf)
+	if err != nil || !ok || n != 0 {
+		t.Fatalf("MoveBackwardToLocation: n=%d ok=%v err=%v", n, ok, err)
+	}
+	wps := h.moverOf(player).Waypoints()
+	if len(wps) != 2 || wps[0].X != 17200 || wps[0].Y != 170300 {
+		t.Fatalf("waypoints = %v, want a detour through (17200, 170300)", wps)
+	}
+
+	// One second of running
+	h.stepMovers(time.Second)
+	if loc := player.Location(); loc.X == 17000 || loc.X > 17200 {
+		t.Errorf("after 1s at %v, want on the first leg", loc)
+	}
+	for range 10 {
+		h.stepMovers(time.Second)
+	}
+	if loc := player.Location(); loc.X != 17400 || loc.Y != 170000 {
+		t.Errorf("after the route at %v, want (17400, 170000)", loc)
+	}
+	if h.moverOf(player).IsMoving() {
+		t.Error("player should stop at the destination")
+	}
+}
+
+func TestHandler_MoveRejected(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager(), WithPathfinder(detourPathfinder{}))
+	client := newInGameClient(t, h, 9702, "Stuck")
+	player := client.ActivePlayer()
+	buf := make([]byte, 1024)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		sitting bool
+	}{
+		{"unreachable", moveRequest(17000, 172000, -3500, clientpackets.MoveByMouse, player.Location()), false},
+		{"keyboard", moveRequest(17100, 170000, -3500, clientpackets.MoveByKeyboard, player.Location()), false},
+		{"sitting", moveRequest(17100, 170000, -3500, clientpackets.MoveByMouse, player.Location()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player.SetSitting(tt.sitting)
+			n, ok, err := h.HandlePacket(ctx, client, tt.data, buf)
+			if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeActionFailed {
+				t.Errorf("n=%d ok=%v err=%v opcode=0x%02X, want ActionFailed", n, ok, err, buf[0])
+			}
+			h.stepMovers(time.Second)
+			if player.X() != 17000 || player.Y() != 170000 {
+				t.Errorf("player moved to %v", player.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/party"
+)
+
+// partyPositionInterval is how often party members receive minimap positions.
+const partyPositionInterval = time.Second
+
+// sendToPlayer sends pkt to the player's client if it is online.
+func (h *Handler) sendToPlayer(p *model.Player, pkt serverPacket) {
+	if c, ok := h.clients.ByObjectID(p.ObjectID()); ok {
+		sendPacket(c, pkt)
+	}
+}
+
+// onlinePlayer returns the active player of an online client by name.
+func (h *Handler) onlinePlayer(name string) *model.Player {
+	c, ok := h.clients.ByName(name)
+	if !ok {
+		return nil
+	}
+	return c.ActivePlayer()
+}
+
+// handleRequestJoinParty processes RequestJoinParty (opcode 0x29).
+func (h *Handler) handleRequestJoinParty(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestJoinParty(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestJoinParty: %w", err)
+	}
+
+	target := h.onlinePlayer(pkt.Name)
+	if target == nil {
+		return actionFailed(buf)
+	}
+	if h.friends.IsBlocked(target, player) {
+		slog.Debug("party invite blocked", "from", player.Name(), "to", target.Name())
+		return actionFailed(buf)
+	}
+
+	// An existing party keeps its loot rule
+	rule := party.LootRule(pkt.ItemDistribution)
+	if p := h.parties.PartyOf(player); p != nil {
+		rule = p.LootRule()
+	}
+
+	if err := h.parties.Invite(player, target, rule); err != nil {
+		slog.Debug("party invite rejected", "from", player.Name(), "to", target.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	h.sendToPlayer(target, &serverpackets.AskJoinParty{
+		RequesterName:    player.Name(),
+		ItemDistribution: int32(rule),
+	})
+	return 0, true, nil
+}
+
+// handleRequestAnswerJoinParty processes RequestAnswerJoinParty (opcode 0x2A).
+func (h *Handler) handleRequestAnswerJoinParty(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestAnswerJoinParty(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestAnswerJoinParty: %w", err)
+	}
+
+	p, requester, err := h.parties.Answer(player, pkt.Accept)
+	if requester != nil {
+		h.sendToPlayer(requester, &serverpackets.JoinParty{Accepted: err == nil && pkt.Accept})
+	}
+	if err != nil {
+		slog.Debug("party join failed", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	if p == nil {
+		return 0, true, nil // declined
+	}
+
+	slog.Debug("player joined party", "player", player.Name(), "party", p.ID(), "size", p.Size())
+
+	leader := p.Leader()
+	for _, m := range p.Members() {
+		if m == player {
+			continue
+		}
+		h.sendToPlayer(m, &serverpackets.PartySmallWindowAdd{
+			LeaderID:         leader.ObjectID(),
+			ItemDistribution: int32(p.LootRule()),
+			Member:           player,
+		})
+	}
+
+	n, err := writeToBuf(buf, partyWindowFor(p, player))
+	return n, true, err
+}
+
+// partyWindowFor builds PartySmallWindowAll for the given member.
+func partyWindowFor(p *party.Party, member *model.Player) *serverpackets.PartySmallWindowAll {
+	members := p.Members()
+	others := make([]*model.Player, 0, len(members))
+	for _, m := range members {
+		if m != member {
+			others = append(others, m)
+		}
+	}
+	return &serverpackets.PartySmallWindowAll{
+		LeaderID:         members[0].ObjectID(),
+		ItemDistribution: int32(p.LootRule()),
+		Members:          others,
+	}
+}
+
+// handleRequestWithDrawalParty processes RequestWithDrawalParty (opcode 0x2B).
+func (h *Handler) handleRequestWithDrawalParty(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	res, err := h.parties.Leave(player)
+	if err != nil {
+		return actionFailed(buf)
+	}
+	h.notifyPartyLeave(res)
+	return 0, true, nil
+}
+
+// handleRequestOustPartyMember processes RequestOustPartyMember (opcode 0x2C).
+func (h *Handler) handleRequestOustPartyMember(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPartyMemberName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestOustPartyMember: %w", err)
+	}
+
+	res, err := h.parties.Kick(player, pkt.Name)
+	if err != nil {
+		slog.Debug("party kick rejected", "leader", player.Name(), "target", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+	h.notifyPartyLeave(res)
+	return 0, true, nil
+}
+
+// handleRequestChangePartyLeader processes RequestChangePartyLeader (0xD0:0x04).
+func (h *Handler) handleRequestChangePartyLeader(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestPartyMemberName(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestChangePartyLeader: %w", err)
+	}
+
+	p, _, err := h.parties.ChangeLeader(player, pkt.Name)
+	if err != nil {
+		slog.Debug("party leader change rejected", "leader", player.Name(), "target", pkt.Name, "error", err)
+		return actionFailed(buf)
+	}
+	h.refreshPartyWindows(p)
+	return 0, true, nil
+}
+
+// refreshPartyWindows re-sends the whole party window to every member (L2J does the same on leader change).
+func (h *Handler) refreshPartyWindows(p *party.Party) {
+	for _, m := range p.Members() {
+		h.sendToPlayer(m, serverpackets.PartySmallWindowDeleteAll{})
+		h.sendToPlayer(m, partyWindowFor(p, m))
+	}
+}
+
+// notifyPartyLeave updates party windows after a member left or was kicked.
+func (h *Handler) notifyPartyLeave(res party.LeaveResult) {
+	h.sendToPlayer(res.Left, serverpackets.PartySmallWindowDeleteAll{})
+
+	if res.Disbanded {
+		for _, m := range res.Remaining {
+			h.sendToPlayer(m, serverpackets.PartySmallWindowDeleteAll{})
+		}
+		return
+	}
+
+	if res.NewLeader != nil {
+		h.refreshPartyWindows(res.Party)
+		return
+	}
+
+	del := &serverpackets.PartySmallWindowDelete{ObjectID: res.Left.ObjectID(), Name: res.Left.Name()}
+	for _, m := range res.Remaining {
+		h.sendToPlayer(m, del)
+	}
+}
+
+// leaveParty removes a disconnecting player from the party and pending invitations.
+func (h *Handler) leaveParty(player *model.Player) {
+	h.parties.CancelInvitations(player)
+	if res, err := h.parties.Leave(player); err == nil {
+		h.notifyPartyLeave(res)
+	}
+}
+
+// BroadcastPartyPositions sends member positions to each member (minimap markers).
+func (h *Handler) BroadcastPartyPositions(p *party.Party) {
+	members := p.Members()
+	for _, m := range members {
+		others := make([]*model.Player, 0, len(members)-1)
+		for _, o := range members {
+			if o != m {
+				others = append(others, o)
+			}
+		}
+		h.sendToPlayer(m, &serverpackets.PartyMemberPosition{Members: others})
+	}
+}
+
+// RunPartyUpdates periodically refreshes party member positions until ctx is cancelled.
+func (h *Handler) RunPartyUpdates(ctx context.Context) error {
+	return h.parties.Start(ctx, partyPositionInterval, h.BroadcastPartyPositions)
+}
@@ -0,0 +1,139 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+)
+
+func TestHandler_PartyInviteLeaveFlow(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	leader := newInGameClient(t, h, 9101, "Leader")
+	member := newInGameClient(t, h, 9102, "Member")
+	buf := make([]byte, 4096)
+
+	invite := packet.NewWriter(32)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestJoinParty)
+	invite.WriteString("member") // name lookup is case-insensitive
+	invite.WriteInt(3)
+	if n, ok, err := h.HandlePacket(ctx, leader, invite.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestJoinParty: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if h.Parties().PendingRequester(member.ActivePlayer()) != leader.ActivePlayer() {
+		t.Fatal("invitation was not registered")
+	}
+
+	answer := packet.NewWriter(8)
+	_ = answer.WriteByte(clientpackets.OpcodeRequestAnswerJoinParty)
+	answer.WriteInt(1)
+	n, ok, err := h.HandlePacket(ctx, member, answer.Bytes(), buf)
+	if err != nil || !ok {
+		t.Fatalf("RequestAnswerJoinParty: ok=%v err=%v", ok, err)
+	}
+	if n == 0 || buf[0] != serverpackets.OpcodePartySmallWindowAll {
+		t.Fatalf("expected PartySmallWindowAll, got n=%d opcode=0x%02X", n, buf[0])
+	}
+
+	p := h.Parties().PartyOf(member.ActivePlayer())
+	if p == nil || !p.IsLeader(leader.ActivePlayer()) || p.LootRule() != 3 {
+		t.Fatalf("party not formed correctly: %+v", p)
+	}
+
+	// Member cannot kick the leader
+	oust := packet.NewWriter(32)
+	_ = oust.WriteByte(clientpackets.OpcodeRequestOustPartyMember)
+	oust.WriteString("Leader")
+	n, _, _ = h.HandlePacket(ctx, member, oust.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("kick by member must fail, got opcode 0x%02X", buf[0])
+	}
+
+	// Leader hands over leadership via the extended packet
+	change := packet.NewWriter(32)
+	_ = change.WriteByte(clientpackets.OpcodeExtended)
+	change.WriteShort(clientpackets.ExOpcodeRequestChangePartyLeader)
+	change.WriteString("Member")
+	if _, ok, err := h.HandlePacket(ctx, leader, change.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestChangePartyLeader: ok=%v err=%v", ok, err)
+	}
+	if !p.IsLeader(member.ActivePlayer()) {
+		t.Fatal("leadership was not transferred")
+	}
+
+	// Disconnecting leaves the party; a party of one is disbanded
+	h.OnDisconnect(leader)
+	if h.Parties().PartyOf(member.ActivePlayer()) != nil {
+		t.Error("party should be disbanded after member disconnect")
+	}
+}
+
+func TestHandler_PartyDeclined(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	leader := newInGameClient(t, h, 9111, "Leader")
+	member := newInGameClient(t, h, 9112, "Member")
+	buf := make([]byte, 4096)
+
+	invite := packet.NewWriter(32)
+	_ = invite.WriteByte(clientpackets.OpcodeRequestJoinParty)
+	invite.WriteString("Member")
+	invite.WriteInt(0)
+	if _, _, err := h.HandlePacket(ctx, leader, invite.Bytes(), buf); err != nil {
+		t.Fatalf("RequestJoinParty: %v", err)
+	}
+
+	answer := packet.NewWriter(8)
+	_ = answer.WriteByte(clientpackets.OpcodeRequestAnswerJoinParty)
+	answer.WriteInt(0)
+	if n, ok, err := h.HandlePacket(ctx, member, answer.Bytes(), buf); err != nil || !ok || n != 0 {
+		t.Fatalf("RequestAnswerJoinParty: n=%d ok=%v err=%v", n, ok, err)
+	}
+	if h.Parties().PartyOf(leader.ActivePlayer()) != nil {
+		t.Error("declined invitation must not create a party")
+	}
+}
+
+func TestHandler_Say2(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	alice := newInGameClient(t, h, 9121, "Alice")
+	newInGameClient(t, h, 9122, "Bob")
+	buf := make([]byte, 4096)
+
+	say := func(text string, chatType int32, target string) (int, error) {
+		w := packet.NewWriter(128)
+		_ = w.WriteByte(clientpackets.OpcodeSay2)
+		w.WriteString(text)
+		w.WriteInt(chatType)
+		if chatType == clientpackets.ChatTell {
+			w.WriteString(target)
+		}
+		n, _, err := h.HandlePacket(ctx, alice, w.Bytes(), buf)
+		return n, err
+	}
+
+	n, err := say("hello", clientpackets.ChatAll, "")
+	if err != nil || n == 0 || buf[0] != serverpackets.OpcodeCreatureSay {
+		t.Fatalf("ALL: n=%d err=%v opcode=0x%02X", n, err, buf[0])
+	}
+
+	n, err = say("psst", clientpackets.ChatTell, "Bob")
+	if err != nil || n == 0 || buf[0] != serverpackets.OpcodeCreatureSay {
+		t.Fatalf("TELL: n=%d err=%v opcode=0x%02X", n, err, buf[0])
+	}
+
+	n, _ = say("psst", clientpackets.ChatTell, "Nobody")
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("TELL to offline player must fail, got opcode 0x%02X", buf[0])
+	}
+
+	// Party chat without a party is silently dropped
+	if n, _ = say("team", clientpackets.ChatParty, ""); n != 0 {
+		t.Errorf("PARTY without party: n=%d, want 0", n)
+	}
+}
@@ -0,0 +1,562 @@
+package gameserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/privatestore"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// offlineSaveTimeout limits DB calls made outside of a request context (disconnect, death).
+const offlineSaveTimeout = 5 * time.Second
+
+// InventoryStore persists inventories after item exchange between players.
+type InventoryStore interface {
+	SyncInventory(ctx context.Context, ownerID int64, items []*model.Item) error
+}
+
+// findPlayer returns an online or offline-trading player by objectID.
+func (h *Handler) findPlayer(objectID uint32) *model.Player {
+	if c, ok := h.clients.ByObjectID(objectID); ok {
+		if p := c.ActivePlayer(); p != nil {
+			return p
+		}
+	}
+	if h.offlineStores != nil {
+		if p, ok := h.offlineStores.Get(objectID); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// handleAction processes Action (opcode 0x04).
+// Clicks on NPCs open quest dialogs; clicks on players open their private store.
+func (h *Handler) handleAction(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseAction(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing Action: %w", err)
+	}
+	if player.IsDead() {
+		return actionFailed(buf)
+	}
+	player.SetTarget(uint32(pkt.ObjectID))
+
+	if npc := h.findNpc(uint32(pkt.ObjectID)); npc != nil {
+		return h.talkToNpc(ctx, player, npc, "", "", buf)
+	}
+
+	target := h.findPlayer(uint32(pkt.ObjectID))
+	if target == nil || target == player {
+		return actionFailed(buf)
+	}
+
+	switch target.PrivateStoreType() {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		list := target.SellList()
+		n, err := writeToBuf(buf, &serverpackets.PrivateStoreListSell{
+			StoreObjectID: target.ObjectID(),
+			PackageSale:   list.IsPackaged(),
+			BuyerAdena:    player.Inventory().Adena(),
+			Items:         list.Items(),
+		})
+		return n, true, err
+
+	case model.PrivateStoreBuy:
+		n, err := writeToBuf(buf, &serverpackets.PrivateStoreListBuy{
+			StoreObjectID: target.ObjectID(),
+			SellerAdena:   player.Inventory().Adena(),
+			Items:         buyStoreEntries(target.BuyList().Items(), player.Inventory()),
+		})
+		return n, true, err
+
+	default:
+		return actionFailed(buf)
+	}
+}
+
+// buyStoreEntries matches buy store entries with items of the seller.
+func buyStoreEntries(items []model.TradeItem, inv *model.Inventory) []serverpackets.BuyStoreEntry {
+	entries := make([]serverpackets.BuyStoreEntry, 0, len(items))
+	for _, it := range items {
+		item := inv.ItemByType(it.ItemType)
+		if item == nil || item.IsEquipped() {
+			continue
+		}
+		entries = append(entries, serverpackets.BuyStoreEntry{
+			Item:        it,
+			SellerObjID: item.ObjectID(),
+			SellerCount: item.Count(),
+		})
+	}
+	return entries
+}
+
+// handleRequestActionUse processes RequestActionUse (opcode 0x45).
+func (h *Handler) handleRequestActionUse(_ context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestActionUse(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestActionUse: %w", err)
+	}
+	if player.IsDead() {
+		return actionFailed(buf)
+	}
+
+	switch pkt.ActionID {
+	case clientpackets.ActionSitStand:
+		// Торговец сидит пока магазин открыт
+		if player.PrivateStoreType() != model.PrivateStoreNone {
+			return actionFailed(buf)
+		}
+		player.SetSitting(!player.IsSitting())
+		wait := serverpackets.NewChangeWaitType(player)
+		h.broadcastToVisible(player, wait)
+		n, err := writeToBuf(buf, wait)
+		return n, true, err
+
+	case clientpackets.ActionPrivateStoreSell:
+		return h.handleManageSell(client, buf, false)
+	case clientpackets.ActionPackageSell:
+		return h.handleManageSell(client, buf, true)
+	case clientpackets.ActionPrivateStoreBuy:
+		return h.handleManageBuy(client, buf)
+	case clientpackets.ActionDwarvenManufacture:
+		return h.handleManageManufacture(client, buf)
+
+	default:
+		slog.Debug("unsupported action", "actionID", pkt.ActionID, "player", player.Name())
+		return actionFailed(buf)
+	}
+}
+
+// reopenForManage closes an open store before editing it (L2J: player stands up).
+func (h *Handler) reopenForManage(player *model.Player) {
+	if player.PrivateStoreType().IsActive() {
+		h.stores.CloseStore(player)
+		h.broadcastToVisible(player, serverpackets.NewChangeWaitType(player))
+	}
+}
+
+// handleManageSell opens the sell store setup window (opcode 0x73, actions 10/61).
+func (h *Handler) handleManageSell(client *GameClient, buf []byte, packaged bool) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) || h.trades.IsTrading(player) {
+		return actionFailed(buf)
+	}
+
+	h.reopenForManage(player)
+	player.SetPrivateStoreType(model.PrivateStoreSellManage)
+
+	n, err := writeToBuf(buf, &serverpackets.PrivateStoreManageListSell{
+		ObjectID:    player.ObjectID(),
+		PackageSale: packaged,
+		Adena:       player.Inventory().Adena(),
+		Inventory:   sellableItems(player.Inventory()),
+		SellList:    player.SellList().Items(),
+	})
+	return n, true, err
+}
+
+// sellableItems returns inventory items that can be put into a store.
+func sellableItems(inv *model.Inventory) []*model.Item {
+	items := inv.Items()
+	sellable := items[:0]
+	for _, item := range items {
+		if item.IsEquipped() || item.ItemType() == model.AdenaItemID {
+			continue
+		}
+		sellable = append(sellable, item)
+	}
+	return sellable
+}
+
+// handleManageBuy opens the buy store setup window (opcode 0x90, action 28).
+func (h *Handler) handleManageBuy(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) || h.trades.IsTrading(player) {
+		return actionFailed(buf)
+	}
+
+	h.reopenForManage(player)
+	player.SetPrivateStoreType(model.PrivateStoreBuyManage)
+
+	n, err := writeToBuf(buf, &serverpackets.PrivateStoreManageListBuy{
+		ObjectID:  player.ObjectID(),
+		Adena:     player.Inventory().Adena(),
+		Inventory: sellableItems(player.Inventory()),
+		BuyList:   player.BuyList().Items(),
+	})
+	return n, true, err
+}
+
+// handleManageManufacture opens the dwarven manufacture setup window (action 37).
+func (h *Handler) handleManageManufacture(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) || h.trades.IsTrading(player) {
+		return actionFailed(buf)
+	}
+
+	h.reopenForManage(player)
+
+	items := player.ManufactureList().Items()
+	recipes := make([]int32, 0, len(items))
+	for _, it := range items {
+		recipes = append(recipes, it.RecipeID)
+	}
+
+	n, err := writeToBuf(buf, &serverpackets.RecipeShopManageList{
+		ObjectID: player.ObjectID(),
+		Adena:    player.Inventory().Adena(),
+		Dwarven:  true,
+		Recipes:  recipes,
+		Items:    items,
+	})
+	return n, true, err
+}
+
+// handleSetPrivateStoreListSell opens the sell store (opcode 0x74).
+func (h *Handler) handleSetPrivateStoreListSell(_ context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseSetPrivateStoreListSell(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing SetPrivateStoreListSell: %w", err)
+	}
+
+	items := make([]model.TradeItem, 0, len(pkt.Items))
+	for _, e := range pkt.Items {
+		items = append(items, model.TradeItem{
+			ObjectID: uint32(e.ObjectID),
+			Count:    e.Count,
+			Price:    e.Price,
+		})
+	}
+
+	if err := h.stores.OpenSellStore(player, items, pkt.PackageSale); err != nil {
+		slog.Debug("sell store rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	return h.announceStore(player, buf)
+}
+
+// handleSetPrivateStoreListBuy opens the buy store (opcode 0x91).
+func (h *Handler) handleSetPrivateStoreListBuy(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseSetPrivateStoreListBuy(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing SetPrivateStoreListBuy: %w", err)
+	}
+
+	items := make([]model.TradeItem, 0, len(pkt.Items))
+	for _, e := range pkt.Items {
+		items = append(items, model.TradeItem{
+			ItemType: e.ItemID,
+			Enchant:  int32(e.Enchant),
+			Count:    e.Count,
+			Price:    e.Price,
+		})
+	}
+
+	if err := h.stores.OpenBuyStore(player, items); err != nil {
+		slog.Debug("buy store rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	return h.announceStore(player, buf)
+}
+
+// handleRecipeShopListSet opens the dwarven manufacture store (opcode 0xB2).
+// Recipe book is not loaded yet, so recipe ownership is not validated.
+func (h *Handler) handleRecipeShopListSet(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseRequestRecipeShopListSet(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestRecipeShopListSet: %w", err)
+	}
+
+	items := make([]model.ManufactureItem, 0, len(pkt.Items))
+	for _, e := range pkt.Items {
+		items = append(items, model.ManufactureItem{RecipeID: e.RecipeID, Cost: e.Cost})
+	}
+
+	if err := h.stores.OpenManufactureStore(player, items); err != nil {
+		slog.Debug("manufacture store rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	return h.announceStore(player, buf)
+}
+
+// announceStore broadcasts sitting and the store title after the store is opened.
+func (h *Handler) announceStore(player *model.Player, buf []byte) (int, bool, error) {
+	wait := serverpackets.NewChangeWaitType(player)
+	h.broadcastToVisible(player, wait)
+
+	msg := storeMsgPacket(player)
+	if msg != nil {
+		h.broadcastToVisible(player, msg)
+		if c, ok := h.clients.ByObjectID(player.ObjectID()); ok {
+			sendPacket(c, wait)
+		}
+		n, err := writeToBuf(buf, msg)
+		return n, true, err
+	}
+
+	n, err := writeToBuf(buf, wait)
+	return n, true, err
+}
+
+// storeMsgPacket returns the title packet matching the player's open store (nil if closed).
+func storeMsgPacket(player *model.Player) *serverpackets.PrivateStoreMsg {
+	title := privatestore.Title(player)
+	switch player.PrivateStoreType() {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		return serverpackets.NewPrivateStoreMsgSell(player.ObjectID(), title)
+	case model.PrivateStoreBuy:
+		return serverpackets.NewPrivateStoreMsgBuy(player.ObjectID(), title)
+	case model.PrivateStoreManufacture:
+		return serverpackets.NewRecipeShopMsg(player.ObjectID(), title)
+	default:
+		return nil
+	}
+}
+
+// handlePrivateStoreQuit closes any store (opcodes 0x76, 0x93, 0xB3).
+func (h *Handler) handlePrivateStoreQuit(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	wasSitting := player.IsSitting()
+	h.stores.CloseStore(player)
+	if !wasSitting {
+		return 0, true, nil
+	}
+
+	wait := serverpackets.NewChangeWaitType(player)
+	h.broadcastToVisible(player, wait)
+	n, err := writeToBuf(buf, wait)
+	return n, true, err
+}
+
+// handleSetPrivateStoreMsg sets the store title (opcodes 0x77, 0x94, 0xB1).
+func (h *Handler) handleSetPrivateStoreMsg(client *GameClient, data, buf []byte, storeType model.PrivateStoreType) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseSetPrivateStoreMsg(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing store message: %w", err)
+	}
+
+	if err := h.stores.SetTitle(player, storeType, pkt.Message); err != nil {
+		slog.Debug("store message rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+
+	// Магазин уже открыт — обновляем надпись у окружающих
+	if msg := storeMsgPacket(player); msg != nil {
+		h.broadcastToVisible(player, msg)
+		n, err := writeToBuf(buf, msg)
+		return n, true, err
+	}
+	return 0, true, nil
+}
+
+// handleRequestPrivateStoreBuy buys items from a sell store (opcode 0x79).
+func (h *Handler) handleRequestPrivateStoreBuy(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseRequestPrivateStoreBuy(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPrivateStoreBuy: %w", err)
+	}
+
+	store := h.findPlayer(uint32(pkt.StorePlayerID))
+	if store == nil {
+		return actionFailed(buf)
+	}
+
+	reqs := make([]privatestore.ItemRequest, 0, len(pkt.Items))
+	for _, e := range pkt.Items {
+		reqs = append(reqs, privatestore.ItemRequest{
+			ObjectID: uint32(e.ObjectID),
+			Count:    e.Count,
+			Price:    e.Price,
+		})
+	}
+
+	total, closed, err := h.stores.Buy(player, store, reqs)
+	if err != nil {
+		logTradeError("private store buy failed", player, store, err)
+		return actionFailed(buf)
+	}
+
+	slog.Info("private store purchase",
+		"buyer", player.Name(),
+		"seller", store.Name(),
+		"items", len(reqs),
+		"adena", total)
+
+	h.afterTrade(ctx, player, store, closed)
+	return 0, true, nil
+}
+
+// handleRequestPrivateStoreSell sells items to a buy store (opcode 0x96).
+func (h *Handler) handleRequestPrivateStoreSell(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseRequestPrivateStoreSell(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestPrivateStoreSell: %w", err)
+	}
+
+	store := h.findPlayer(uint32(pkt.StorePlayerID))
+	if store == nil {
+		return actionFailed(buf)
+	}
+
+	reqs := make([]privatestore.ItemRequest, 0, len(pkt.Items))
+	for _, e := range pkt.Items {
+		reqs = append(reqs, privatestore.ItemRequest{
+			ObjectID: uint32(e.ObjectID),
+			ItemType: e.ItemID,
+			Count:    e.Count,
+			Price:    e.Price,
+		})
+	}
+
+	total, closed, err := h.stores.Sell(player, store, reqs)
+	if err != nil {
+		logTradeError("private store sell failed", player, store, err)
+		return actionFailed(buf)
+	}
+
+	slog.Info("private store sale",
+		"seller", player.Name(),
+		"buyer", store.Name(),
+		"items", len(reqs),
+		"adena", total)
+
+	h.afterTrade(ctx, player, store, closed)
+	return 0, true, nil
+}
+
+// afterTrade persists inventories and updates the store owner after a deal.
+func (h *Handler) afterTrade(ctx context.Context, customer, store *model.Player, closed bool) {
+	if h.inventories != nil {
+		for _, p := range []*model.Player{customer, store} {
+			if err := h.inventories.SyncInventory(ctx, p.CharacterID(), p.Inventory().Items()); err != nil {
+				slog.Error("failed to save inventory after trade", "player", p.Name(), "error", err)
+			}
+		}
+	}
+
+	if h.offlineStores != nil && store.IsOffline() {
+		released, err := h.offlineStores.Sync(ctx, store)
+		if err != nil {
+			slog.Error("failed to update offline store", "player", store.Name(), "error", err)
+		}
+		if released {
+			// Всё продано — offline-торговец покидает мир
+			h.stores.Forget(store)
+			world.Instance().RemoveObject(store.ObjectID())
+			return
+		}
+	}
+
+	if closed {
+		h.broadcastToVisible(store, serverpackets.NewChangeWaitType(store))
+		if c, ok := h.clients.ByObjectID(store.ObjectID()); ok {
+			sendPacket(c, serverpackets.NewChangeWaitType(store))
+		}
+	}
+}
+
+// logTradeError logs rejected trades; validation failures are expected (stale client lists).
+func logTradeError(msg string, customer, store *model.Player, err error) {
+	level := slog.LevelDebug
+	if !isValidationError(err) {
+		level = slog.LevelError
+	}
+	slog.Log(context.Background(), level, msg,
+		"player", customer.Name(),
+		"store", store.Name(),
+		"error", err)
+}
+
+func isValidationError(err error) bool {
+	for _, target := range []error{
+		privatestore.ErrStoreClosed, privatestore.ErrSelfTrade, privatestore.ErrTooFar,
+		privatestore.ErrEmptyList, privatestore.ErrItemNotInStore, privatestore.ErrPriceMismatch,
+		privatestore.ErrCountExceeded, privatestore.ErrPackageSale, privatestore.ErrNotEnoughAdena,
+		privatestore.ErrNotEnoughItems, privatestore.ErrAdenaOverflow, privatestore.ErrItemOverflow,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
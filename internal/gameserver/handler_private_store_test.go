@@ -0,0 +1,231 @@
+package gameserver
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/privatestore"
+	"github.com/udisondev/la2go/internal/testutil"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// newInGameClient creates a client with an active player registered in the handler and the world.
+func newInGameClient(t *testing.T, h *Handler, characterID int64, name string) *GameClient {
+	t.Helper()
+
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i + 1)
+	}
+	client, err := NewGameClient(testutil.NewMockConn(), key)
+	if err != nil {
+		t.Fatalf("NewGameClient: %v", err)
+	}
+	client.SetState(ClientStateInGame)
+
+	player, err := model.NewPlayer(characterID, 1, name, 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	player.SetLocation(model.NewLocation(17000, 170000, -3500, 0))
+	client.SetActivePlayer(player)
+	h.Clients().Register(client)
+
+	if err := world.Instance().AddObject(player.WorldObject); err != nil {
+		t.Fatalf("AddObject: %v", err)
+	}
+	t.Cleanup(func() { world.Instance().RemoveObject(player.ObjectID()) })
+	return client
+}
+
+func giveItem(t *testing.T, p *model.Player, itemID int64, itemType, count int32) *model.Item {
+	t.Helper()
+	item, err := model.NewItem(p.CharacterID(), itemType, count)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	item.SetItemID(itemID)
+	p.Inventory().AddItem(item)
+	return item
+}
+
+func TestHandler_PrivateStoreSellAndBuy(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	seller := newInGameClient(t, h, 9001, "Seller")
+	buyer := newInGameClient(t, h, 9002, "Buyer")
+
+	arrows := giveItem(t, seller.ActivePlayer(), 90010, 17, 100)
+	giveItem(t, buyer.ActivePlayer(), 90020, model.AdenaItemID, 1000)
+
+	buf := make([]byte, 4096)
+
+	// Seller: title + list
+	msg := packet.NewWriter(64)
+	_ = msg.WriteByte(clientpackets.OpcodeSetPrivateStoreMsgSell)
+	msg.WriteString("arrows")
+	if _, _, err := h.HandlePacket(ctx, seller, msg.Bytes(), buf); err != nil {
+		t.Fatalf("SetPrivateStoreMsgSell: %v", err)
+	}
+
+	list := packet.NewWriter(64)
+	_ = list.WriteByte(clientpackets.OpcodeSetPrivateStoreListSell)
+	list.WriteInt(0) // not package sale
+	list.WriteInt(1)
+	list.WriteInt(int32(arrows.ObjectID()))
+	list.WriteInt(50)
+	list.WriteInt(3)
+	n, ok, err := h.HandlePacket(ctx, seller, list.Bytes(), buf)
+	if err != nil || !ok {
+		t.Fatalf("SetPrivateStoreListSell: ok=%v err=%v", ok, err)
+	}
+	if n == 0 || buf[0] != serverpackets.OpcodePrivateStoreMsgSell {
+		t.Fatalf("expected PrivateStoreMsgSell response, got n=%d opcode=0x%02X", n, buf[0])
+	}
+	if seller.ActivePlayer().PrivateStoreType() != model.PrivateStoreSell {
+		t.Fatalf("store type = %s, want SELL", seller.ActivePlayer().PrivateStoreType())
+	}
+
+	// Seller cannot stand up while the store is open
+	sit := packet.NewWriter(16)
+	_ = sit.WriteByte(clientpackets.OpcodeRequestActionUse)
+	sit.WriteInt(clientpackets.ActionSitStand)
+	sit.WriteInt(0)
+	_ = sit.WriteByte(0)
+	n, _, _ = h.HandlePacket(ctx, seller, sit.Bytes(), buf)
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("standing up in store must fail, got opcode 0x%02X", buf[0])
+	}
+
+	// Buyer clicks the seller → store list
+	action := packet.NewWriter(32)
+	_ = action.WriteByte(clientpackets.OpcodeAction)
+	action.WriteInt(int32(seller.ActivePlayer().ObjectID()))
+	action.WriteInt(0)
+	action.WriteInt(0)
+	action.WriteInt(0)
+	_ = action.WriteByte(0)
+	n, _, err = h.HandlePacket(ctx, buyer, action.Bytes(), buf)
+	if err != nil || n == 0 || buf[0] != serverpackets.OpcodePrivateStoreListSell {
+		t.Fatalf("Action on store: n=%d err=%v opcode=0x%02X", n, err, buf[0])
+	}
+
+	// Buyer buys everything at the listed price
+	buy := packet.NewWriter(64)
+	_ = buy.WriteByte(clientpackets.OpcodeRequestPrivateStoreBuy)
+	buy.WriteInt(int32(seller.ActivePlayer().ObjectID()))
+	buy.WriteInt(1)
+	buy.WriteInt(int32(arrows.ObjectID()))
+	buy.WriteInt(50)
+	buy.WriteInt(3)
+	if _, ok, err := h.HandlePacket(ctx, buyer, buy.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestPrivateStoreBuy: ok=%v err=%v", ok, err)
+	}
+
+	if got := buyer.ActivePlayer().Inventory().CountOf(17); got != 50 {
+		t.Errorf("buyer arrows = %d, want 50", got)
+	}
+	if got := seller.ActivePlayer().Inventory().Adena(); got != 150 {
+		t.Errorf("seller adena = %d, want 150", got)
+	}
+	if seller.ActivePlayer().PrivateStoreType() != model.PrivateStoreNone || seller.ActivePlayer().IsSitting() {
+		t.Error("sold out store must close and stand the seller up")
+	}
+}
+
+func TestHandler_PrivateStoreBuy_StalePrice(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	seller := newInGameClient(t, h, 9011, "Seller2")
+	buyer := newInGameClient(t, h, 9012, "Buyer2")
+
+	sword := giveItem(t, seller.ActivePlayer(), 90110, 100, 1)
+	giveItem(t, buyer.ActivePlayer(), 90120, model.AdenaItemID, 1000)
+
+	svc := h.stores
+	if err := svc.OpenSellStore(seller.ActivePlayer(), []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 500}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	buy := packet.NewWriter(64)
+	_ = buy.WriteByte(clientpackets.OpcodeRequestPrivateStoreBuy)
+	buy.WriteInt(int32(seller.ActivePlayer().ObjectID()))
+	buy.WriteInt(1)
+	buy.WriteInt(int32(sword.ObjectID()))
+	buy.WriteInt(1)
+	buy.WriteInt(1) // stale price
+
+	n, ok, err := h.HandlePacket(ctx, buyer, buy.Bytes(), buf)
+	if err != nil || !ok {
+		t.Fatalf("RequestPrivateStoreBuy: ok=%v err=%v", ok, err)
+	}
+	if n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("expected ActionFailed, got n=%d opcode=0x%02X", n, buf[0])
+	}
+	if seller.ActivePlayer().Inventory().ItemByObjectID(sword.ObjectID()) == nil {
+		t.Error("item must stay with the seller")
+	}
+}
+
+type memOfflineRepo struct {
+	mu     sync.Mutex
+	stores map[int64]privatestore.OfflineStore
+}
+
+func (r *memOfflineRepo) SaveOfflineStore(_ context.Context, s privatestore.OfflineStore) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stores[s.CharacterID] = s
+	return nil
+}
+
+func (r *memOfflineRepo) DeleteOfflineStore(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.stores, id)
+	return nil
+}
+
+func (r *memOfflineRepo) LoadOfflineStores(context.Context) ([]privatestore.OfflineStore, error) {
+	return nil, nil
+}
+
+func TestHandler_OnDisconnect_OfflineStore(t *testing.T) {
+	repo := &memOfflineRepo{stores: make(map[int64]privatestore.OfflineStore)}
+	offline := privatestore.NewOfflineStores(repo, nil)
+	h := NewHandler(login.NewSessionManager(), WithOfflineStores(offline))
+
+	trader := newInGameClient(t, h, 9021, "Trader")
+	leaver := newInGameClient(t, h, 9022, "Leaver")
+
+	sword := giveItem(t, trader.ActivePlayer(), 90210, 100, 1)
+	if err := h.stores.OpenSellStore(trader.ActivePlayer(), []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 5}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	h.OnDisconnect(trader)
+	h.OnDisconnect(leaver)
+
+	if _, ok := world.Instance().GetObject(trader.ActivePlayer().ObjectID()); !ok {
+		t.Error("offline trader must stay in the world")
+	}
+	if _, ok := world.Instance().GetObject(leaver.ActivePlayer().ObjectID()); ok {
+		t.Error("player without store must leave the world")
+	}
+	if _, ok := repo.stores[9021]; !ok {
+		t.Error("offline store must be persisted")
+	}
+	if p := h.findPlayer(trader.ActivePlayer().ObjectID()); p != trader.ActivePlayer() {
+		t.Error("offline trader must remain reachable for buyers")
+	}
+	if _, ok := h.Clients().ByName("trader"); ok {
+		t.Error("disconnected client must be unregistered")
+	}
+}
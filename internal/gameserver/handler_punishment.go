@@ -0,0 +1,254 @@
+package gameserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/punishment"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// punishmentCheckInterval is how often timed punishments are checked for expiry.
+const punishmentCheckInterval = 10 * time.Second
+
+// Punishments returns the punishment manager, nil if punishments are disabled.
+func (h *Handler) Punishments() *punishment.Manager {
+	return h.punishments
+}
+
+// activePunishment returns the punishment of type t on a player, its account or the IP it plays from.
+func (h *Handler) activePunishment(p *model.Player, t punishment.Type) (punishment.Punishment, bool) {
+	if h.punishments == nil {
+		return punishment.Punishment{}, false
+	}
+	var account, ip string
+	if client, ok := h.clients.ByObjectID(p.ObjectID()); ok {
+		account, ip = client.AccountName(), client.IP()
+	}
+	return h.punishments.Check(t, account, p.Name(), ip)
+}
+
+// punishedPlayers returns the online players a punishment applies to.
+func (h *Handler) punishedPlayers(pun punishment.Punishment) []*model.Player {
+	var players []*model.Player
+	h.clients.ForEach(func(client *GameClient) bool {
+		p := client.ActivePlayer()
+		if p == nil {
+			return true
+		}
+		var key string
+		switch pun.Affect {
+		case punishment.Account:
+			key = client.AccountName()
+		case punishment.Character:
+			key = p.Name()
+		case punishment.IP:
+			key = client.IP()
+		}
+		if punishment.NormalizeKey(pun.Affect, key) == pun.Key {
+			players = append(players, p)
+		}
+		return true
+	})
+	return players
+}
+
+// untilText describes when a punishment ends, for messages to players.
+func untilText(pun punishment.Punishment) string {
+	if pun.Permanent() {
+		return "permanently"
+	}
+	return "until " + pun.Expires.Format("2006-01-02 15:04")
+}
+
+// chatBanned reports whether a player may not talk, telling it so.
+func (h *Handler) chatBanned(p *model.Player) bool {
+	if _, ok := h.activePunishment(p, punishment.ChatBan); !ok {
+		return false
+	}
+	h.sendToPlayer(p, serverpackets.NewSystemMessage(serverpackets.SystemMessageChattingProhibited))
+	return true
+}
+
+// tradeBanned reports whether a player may not use private stores, telling it so.
+func (h *Handler) tradeBanned(p *model.Player) bool {
+	pun, ok := h.activePunishment(p, punishment.TradeBan)
+	if !ok {
+		return false
+	}
+	h.sendToPlayer(p, serverpackets.NewSystemMessageText("You are banned from trading "+untilText(pun)+"."))
+	return true
+}
+
+// jailCell returns where a jailed player must stay. ok is false if the player is not
+// jailed or no jail zone is loaded.
+func (h *Handler) jailCell(p *model.Player) (model.Location, bool) {
+	if _, jailed := h.activePunishment(p, punishment.Jail); !jailed {
+		return model.Location{}, false
+	}
+	jail, ok := h.zones.Jail()
+	if !ok {
+		slog.Warn("jailed player but no jail zone is loaded", "player", p.Name())
+		return model.Location{}, false
+	}
+	return jail.RestartPoint, true
+}
+
+// applyPunishments puts a jailed player entering the world into the jail.
+// The player must already be in the world with its client registered.
+func (h *Handler) applyPunishments(p *model.Player) {
+	cell, jailed := h.jailCell(p)
+	if !jailed || h.zones.InsideZone(p.WorldObject, zone.Jail) {
+		return
+	}
+	if err := h.Teleport(p, cell); err != nil {
+		slog.Error("failed to jail player", "player", p.Name(), "error", err)
+		return
+	}
+	pun, _ := h.activePunishment(p, punishment.Jail)
+	h.sendToPlayer(p, serverpackets.NewSystemMessageText("You are in jail "+untilText(pun)+"."))
+}
+
+// onPunished applies a new punishment to the online players it concerns:
+// banned players are disconnected, jailed ones are put in the jail.
+func (h *Handler) onPunished(pun punishment.Punishment) {
+	for _, p := range h.punishedPlayers(pun) {
+		switch pun.Type {
+		case punishment.Ban:
+			if client, ok := h.clients.ByObjectID(p.ObjectID()); ok {
+				_ = client.Conn().Close()
+			}
+		case punishment.ChatBan:
+			h.sendToPlayer(p, serverpackets.NewSystemMessageText("You are banned from chatting "+untilText(pun)+"."))
+		case punishment.TradeBan:
+			h.sendToPlayer(p, serverpackets.NewSystemMessageText("You are banned from trading "+untilText(pun)+"."))
+		case punishment.Jail:
+			h.applyPunishments(p)
+		}
+	}
+}
+
+// onPunishmentEnded releases the online players of a punishment that expired or was lifted.
+func (h *Handler) onPunishmentEnded(pun punishment.Punishment) {
+	for _, p := range h.punishedPlayers(pun) {
+		if _, still := h.activePunishment(p, pun.Type); still {
+			continue
+		}
+		switch pun.Type {
+		case punishment.ChatBan:
+			h.sendToPlayer(p, serverpackets.NewSystemMessageText("You may chat again."))
+		case punishment.TradeBan:
+			h.sendToPlayer(p, serverpackets.NewSystemMessageText("You may trade again."))
+		case punishment.Jail:
+			if h.zones.InsideZone(p.WorldObject, zone.Jail) && !p.IsDead() {
+				h.returnToVillage(p)
+			}
+			h.sendToPlayer(p, serverpackets.NewSystemMessageText("You have been released from jail."))
+		}
+	}
+}
+
+// RunPunishments expires timed punishments until ctx is cancelled, releasing the
+// players they concerned. Does nothing if punishments are disabled.
+func (h *Handler) RunPunishments(ctx context.Context) error {
+	if h.punishments == nil {
+		return nil
+	}
+	return h.punishments.Run(ctx, punishmentCheckInterval, h.onPunishmentEnded)
+}
+
+// adminPunish gives a punishment: //punish <affect> <key> <type> <minutes, 0 = permanent> [reason].
+func (h *Handler) adminPunish(ctx context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) < 4 {
+		return "", errAdminUsage
+	}
+	affect, ok := punishment.ParseAffect(args[0])
+	if !ok {
+		return "", errAdminUsage
+	}
+	typ, ok := punishment.ParseType(args[2])
+	if !ok {
+		return "", errAdminUsage
+	}
+	minutes, err := strconv.Atoi(args[3])
+	if err != nil || minutes < 0 {
+		return "", errAdminUsage
+	}
+	if h.punishments == nil {
+		return "", errors.New("punishments are disabled")
+	}
+
+	pun := punishment.Punishment{
+		Affect:     affect,
+		Key:        args[1],
+		Type:       typ,
+		Reason:     strings.Join(args[4:], " "),
+		PunishedBy: gm.Name(),
+	}
+	if minutes > 0 {
+		pun.Expires = time.Now().Add(time.Duration(minutes) * time.Minute)
+	}
+	target := affect.String() + " " + args[1]
+	pun, err = h.punishments.Punish(ctx, pun)
+	if err != nil {
+		return target, err
+	}
+	h.onPunished(pun)
+	h.adminMessage(gm, fmt.Sprintf("Punishment %d: %s of %s %s.", pun.ID, typ, target, untilText(pun)))
+	return target, nil
+}
+
+// adminPunishments lists the active punishments with links lifting them.
+func (h *Handler) adminPunishments(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errAdminUsage
+	}
+	if h.punishments == nil {
+		return "", errors.New("punishments are disabled")
+	}
+
+	var b strings.Builder
+	b.WriteString("<html><title>Punishments</title><body>")
+	list := h.punishments.Active()
+	if len(list) == 0 {
+		b.WriteString("No active punishments.")
+	}
+	for _, pun := range list {
+		fmt.Fprintf(&b, `%d. %s %s: %s %s<br1>by %s: %s <a action="bypass -h admin_unpunish %d">lift</a><br>`,
+			pun.ID, pun.Affect, html.EscapeString(pun.Key), pun.Type, untilText(pun),
+			html.EscapeString(pun.PunishedBy), html.EscapeString(pun.Reason), pun.ID)
+	}
+	b.WriteString("</body></html>")
+	h.sendToPlayer(gm, &serverpackets.NpcHtmlMessage{HTML: b.String()})
+	return "", nil
+}
+
+// adminUnpunish lifts a punishment by ID: //unpunish <id>.
+func (h *Handler) adminUnpunish(ctx context.Context, gm *model.Player, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errAdminUsage
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return "", errAdminUsage
+	}
+	if h.punishments == nil {
+		return "", errors.New("punishments are disabled")
+	}
+	pun, err := h.punishments.Lift(ctx, id)
+	if err != nil {
+		return "punishment " + args[0], err
+	}
+	h.onPunishmentEnded(pun)
+	target := pun.Affect.String() + " " + pun.Key
+	h.adminMessage(gm, fmt.Sprintf("Lifted %s of %s.", pun.Type, target))
+	return target, nil
+}
@@ -0,0 +1,154 @@
+package gameserver
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/punishment"
+	"github.com/udisondev/la2go/internal/testutil"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+var jailCell = model.NewLocation(-114356, -249645, -2984, 0)
+
+func newJailZones(t *testing.T) *zone.Manager {
+	t.Helper()
+	zones := zone.NewManager()
+	for _, z := range []*zone.Zone{
+		{ID: 1, Name: "gludin", Type: zone.Town, TownID: 1,
+			Shape:        zone.Cylinder{X: -80000, Y: 150000, Radius: 2000, MinZ: -4000, MaxZ: -2000},
+			RestartPoint: model.NewLocation(-80826, 149775, -3043, 0)},
+		{ID: 2, Name: "jail", Type: zone.Jail,
+			Shape:        zone.Cuboid{MinX: -115600, MinY: -250700, MinZ: -3500, MaxX: -112500, MaxY: -247600, MaxZ: -2500},
+			RestartPoint: jailCell},
+	} {
+		if err := zones.Add(z); err != nil {
+			t.Fatalf("Add(%s): %v", z.Name, err)
+		}
+	}
+	return zones
+}
+
+func TestHandler_Punishments(t *testing.T) {
+	ctx := context.Background()
+	var audit bytes.Buffer
+	h, _ := newAdminHandler(t, &audit, WithZones(newJailZones(t)), WithPunishments(punishment.NewManager(nil)))
+	gmClient := newInGameClient(t, h, 9991, "Warden")
+	gmClient.SetAccessLevel(100)
+	client := newInGameClient(t, h, 9992, "Rowdy")
+	player := client.ActivePlayer()
+	buf := make([]byte, 4096)
+
+	send := func(client *GameClient, data []byte) bool {
+		t.Helper()
+		n, _, err := h.HandlePacket(ctx, client, data, buf)
+		if err != nil {
+			t.Fatalf("HandlePacket(0x%02X): %v", data[0], err)
+		}
+		return n == 0 || buf[0] != serverpackets.OpcodeActionFailed
+	}
+	gm := func(cmd string) bool {
+		t.Helper()
+		return send(gmClient, bypassPacket("admin_"+cmd))
+	}
+	say := func() bool {
+		w := packet.NewWriter(64)
+		_ = w.WriteByte(clientpackets.OpcodeSay2)
+		w.WriteString("hello")
+		w.WriteInt(clientpackets.ChatAll)
+		return send(client, w.Bytes())
+	}
+
+	if !say() {
+		t.Fatal("player cannot chat before punishment")
+	}
+	if !gm("punish character rowdy chat_ban 10 spam") {
+		t.Fatal("//punish chat_ban failed")
+	}
+	if say() {
+		t.Error("chat-banned player talked")
+	}
+
+	if !gm("punish character Rowdy trade_ban 0") {
+		t.Fatal("//punish trade_ban failed")
+	}
+	if send(client, []byte{clientpackets.OpcodeRequestPrivateStoreManageSell}) || player.PrivateStoreType() != model.PrivateStoreNone {
+		t.Error("trade-banned player opened a store")
+	}
+
+	if !gm("punish character Rowdy jail 60 fighting in town") {
+		t.Fatal("//punish jail failed")
+	}
+	if player.Location() != jailCell {
+		t.Fatalf("jailed player at %v, want the jail cell", player.Location())
+	}
+	if h.canTeleport(player) {
+		t.Error("jailed player may teleport")
+	}
+	if gm("punish character Rowdy curse 10") || gm("punish character Rowdy jail soon") {
+		t.Error("//punish accepted wrong arguments")
+	}
+
+	if !gm("punishments") {
+		t.Error("//punishments failed")
+	}
+	active := h.Punishments().Active()
+	if len(active) != 3 || active[2].Type != punishment.Jail || active[2].Reason != "fighting in town" || active[2].PunishedBy != "Warden" {
+		t.Fatalf("active punishments = %+v", active)
+	}
+	if !gm("unpunish " + strconv.FormatInt(active[2].ID, 10)) {
+		t.Fatal("//unpunish failed")
+	}
+	if h.Zones().InsideZone(player.WorldObject, zone.Jail) || player.X() != -80826 {
+		t.Errorf("released player at %v, want the Gludin restart point", player.Location())
+	}
+	if gm("unpunish " + strconv.FormatInt(active[2].ID, 10)) {
+		t.Error("second //unpunish should fail")
+	}
+
+	log := audit.String()
+	for _, want := range []string{`"command":"punish","args":"character Rowdy jail 60 fighting in town","target":"character Rowdy"`, `"command":"unpunish"`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("audit log lacks %s", want)
+		}
+	}
+}
+
+func TestHandler_EnterWorldJails(t *testing.T) {
+	ctx := context.Background()
+	punishments := punishment.NewManager(nil)
+	player := newCharacter(t, 9993, 7, "Latecomer", 17000)
+	h, sessions := newWorldHandler(t,
+		accountMap{"late": {ID: 7, Login: "late"}},
+		characterMap{7: {player}},
+		WithZones(newJailZones(t)), WithPunishments(punishments),
+	)
+	if _, err := punishments.Punish(ctx, punishment.Punishment{Affect: punishment.Character, Key: "Latecomer", Type: punishment.Jail}); err != nil {
+		t.Fatalf("Punish: %v", err)
+	}
+
+	client := loginClient(t, h, sessions, "late", testutil.NewMockConn())
+	enterWorldAs(t, h, client, 0)
+	if client.ActivePlayer() != player {
+		t.Fatal("jailed character did not enter the world")
+	}
+	if player.Location() != jailCell {
+		t.Fatalf("jailed player entered at %v, want the jail cell", player.Location())
+	}
+
+	// Dying does not get a jailed player out
+	h.damagePlayer(player, player.CurrentHP(), nil)
+	if _, _, err := h.HandlePacket(ctx, client, restartPacket(clientpackets.RestartVillage), make([]byte, 1024)); err != nil {
+		t.Fatalf("RequestRestartPoint: %v", err)
+	}
+	if player.IsDead() || player.Location() != jailCell {
+		t.Errorf("after restart: dead %v at %v, want alive in the jail", player.IsDead(), player.Location())
+	}
+}
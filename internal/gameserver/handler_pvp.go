@@ -0,0 +1,144 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// playerPAtk is the P.Atk of player hits
+// (base P.Atk of an unequipped character; player stats are not modelled yet).
+const playerPAtk = 80
+
+// attackRange is how close a player must stand to hit another player.
+const attackRange = 100
+
+// handleAttackRequest processes AttackRequest (opcode 0x0A): a player hits another player
+// or a monster.
+func (h *Handler) handleAttackRequest(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseAttackRequest(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing AttackRequest: %w", err)
+	}
+	if player.IsDead() {
+		return actionFailed(buf)
+	}
+	player.SetTarget(uint32(pkt.ObjectID))
+
+	if npc := h.findNpc(uint32(pkt.ObjectID)); npc != nil {
+		return h.attackNpc(ctx, player, npc, buf)
+	}
+	target := h.findPlayer(uint32(pkt.ObjectID))
+	if target == nil || target == player || target.IsDead() ||
+		player.Location().DistanceSquared(target.Location()) > attackRange*attackRange {
+		return actionFailed(buf)
+	}
+	if h.zones.InsideZone(player.WorldObject, zone.Peace) || h.zones.InsideZone(target.WorldObject, zone.Peace) {
+		return actionFailed(buf)
+	}
+
+	damage := int32(max(70*playerPAtk/playerPDef, 1))
+	h.broadcastAround(player.Location(), &serverpackets.Attack{
+		AttackerID: player.ObjectID(),
+		Loc:        player.Location(),
+		Hits:       []serverpackets.Hit{{TargetID: target.ObjectID(), Damage: damage}},
+	})
+	h.updatePvPStatus(player, target)
+	h.damagePlayer(target, damage, player)
+	return 0, true, nil
+}
+
+// inCombatZone reports whether p fights where killing gives no karma and no PvP flag (arenas, sieges).
+func (h *Handler) inCombatZone(p *model.Player) bool {
+	return h.zones.InsideZone(p.WorldObject, zone.PvP) || h.zones.InsideZone(p.WorldObject, zone.Siege)
+}
+
+// updatePvPStatus flags a player that attacked another player, so it can be attacked back
+// without karma. Attacking a karma player or fighting in arenas and sieges flags nobody;
+// the flag of those attacking flagged players or enemies at war lasts shorter.
+func (h *Handler) updatePvPStatus(attacker, target *model.Player) {
+	if target.Karma() > 0 || h.inCombatZone(attacker) || h.inCombatZone(target) {
+		return
+	}
+	pvp := target.IsPvPFlagged() || h.clans.AtMutualWar(attacker, target)
+	h.pvpFlags.Store(attacker.ObjectID(), time.Now().Add(h.karma.FlagTime(pvp)))
+	if !attacker.IsPvPFlagged() {
+		attacker.SetPvPFlag(true)
+		h.broadcastCharInfo(attacker)
+	}
+}
+
+// expirePvPFlags removes PvP flags whose time ended by now.
+func (h *Handler) expirePvPFlags(now time.Time) {
+	h.pvpFlags.Range(func(key, value any) bool {
+		if value.(time.Time).After(now) {
+			return true
+		}
+		h.pvpFlags.Delete(key)
+		if p := h.findPlayer(key.(uint32)); p != nil {
+			p.SetPvPFlag(false)
+			h.broadcastCharInfo(p)
+		}
+		return true
+	})
+}
+
+// onPlayerKill counts a kill of victim by killer: killing flagged players, enemies at war
+// and (with AwardPKKill) karma players is PvP, killing anyone else is PK and gives karma.
+// Kills in arenas and sieges count as neither.
+func (h *Handler) onPlayerKill(killer, victim *model.Player) {
+	if killer == victim || h.inCombatZone(killer) || h.inCombatZone(victim) {
+		return
+	}
+	switch {
+	case victim.IsPvPFlagged() || h.clans.AtMutualWar(killer, victim):
+		killer.AddPvPKill()
+	case victim.Karma() > 0:
+		if !h.karma.AwardPKKill {
+			return
+		}
+		killer.AddPvPKill()
+	default:
+		killer.AddPKKill(h.karma.Gain(killer.PKKills(), killer.Level(), victim.Level()))
+		h.sendKarma(killer)
+		h.broadcastCharInfo(killer)
+		slog.Info("player killed a peaceful player", "killer", killer.Name(), "victim", victim.Name(),
+			"karma", killer.Karma(), "pk_kills", killer.PKKills())
+	}
+}
+
+// sendKarma sends the karma of a player to its client.
+func (h *Handler) sendKarma(p *model.Player) {
+	h.sendToPlayer(p, &serverpackets.StatusUpdate{
+		ObjectID: p.ObjectID(),
+		Attrs:    []serverpackets.StatusAttr{{ID: serverpackets.StatusKarma, Value: p.Karma()}},
+	})
+}
+
+// workOffKarma takes away the karma worked off by exp a player gained.
+// Experience gained in arenas works nothing off.
+func (h *Handler) workOffKarma(p *model.Player, exp int64) {
+	if p.Karma() == 0 || h.zones.InsideZone(p.WorldObject, zone.PvP) {
+		return
+	}
+	lost := h.karma.Lost(p.Karma(), exp)
+	if lost == 0 {
+		return
+	}
+	p.SetKarma(p.Karma() - lost)
+	h.sendKarma(p)
+	if p.Karma() == 0 {
+		h.broadcastCharInfo(p)
+	}
+}
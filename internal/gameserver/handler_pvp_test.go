@@ -0,0 +1,147 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/karma"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func attackPacket(objectID uint32) []byte {
+	w := packet.NewWriter(32)
+	_ = w.WriteByte(clientpackets.OpcodeAttackRequest)
+	w.WriteInt(int32(objectID))
+	w.WriteBytes(make([]byte, 13))
+	return w.Bytes()
+}
+
+func TestHandler_PvPFlagAndKarma(t *testing.T) {
+	ctx := context.Background()
+	h := newZoneHandler(t)
+	killerClient := newInGameClient(t, h, 9971, "Killer")
+	victimClient := newInGameClient(t, h, 9972, "Peaceful")
+	killer, victim := killerClient.ActivePlayer(), victimClient.ActivePlayer()
+	h.revalidateZones(killer)
+	h.revalidateZones(victim)
+	buf := make([]byte, 1024)
+
+	hp := victim.CurrentHP()
+	if _, _, err := h.HandlePacket(ctx, killerClient, attackPacket(victim.ObjectID()), buf); err != nil {
+		t.Fatalf("AttackRequest: %v", err)
+	}
+	if victim.CurrentHP() != hp-70 {
+		t.Errorf("victim HP = %d, want %d", victim.CurrentHP(), hp-70)
+	}
+	if !killer.IsPvPFlagged() || victim.IsPvPFlagged() {
+		t.Fatalf("flags: attacker %v, target %v; only the attacker should be flagged", killer.IsPvPFlagged(), victim.IsPvPFlagged())
+	}
+	h.expirePvPFlags(time.Now().Add(time.Minute))
+	if !killer.IsPvPFlagged() {
+		t.Fatal("flag expired too early")
+	}
+	h.expirePvPFlags(time.Now().Add(3 * time.Minute))
+	if killer.IsPvPFlagged() {
+		t.Fatal("flag should expire after the normal flag time")
+	}
+
+	// Killing a player that did not fight back is PK
+	victim.SetCurrentHP(10)
+	_, _, _ = h.HandlePacket(ctx, killerClient, attackPacket(victim.ObjectID()), buf)
+	if !victim.IsDead() {
+		t.Fatal("victim should be dead")
+	}
+	if killer.PKKills() != 1 || killer.PvPKills() != 0 || killer.Karma() != 240 {
+		t.Errorf("killer: pk %d, pvp %d, karma %d", killer.PKKills(), killer.PvPKills(), killer.Karma())
+	}
+	// Corpses cannot be attacked
+	if n, _, _ := h.HandlePacket(ctx, killerClient, attackPacket(victim.ObjectID()), buf); n == 0 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Error("attack on a dead player should fail")
+	}
+
+	// Attacking a karma player flags nobody, killing it is PvP
+	h.revive(victim, 0)
+	killer.SetExperience(model.ExpForLevel(20) + 20000)
+	killer.SetCurrentHP(10)
+	_, _, _ = h.HandlePacket(ctx, victimClient, attackPacket(killer.ObjectID()), buf)
+	if victim.IsPvPFlagged() {
+		t.Error("attacking a karma player should not flag")
+	}
+	if !killer.IsDead() || victim.PvPKills() != 1 || victim.Karma() != 0 {
+		t.Errorf("avenger: pvp %d, karma %d; killer dead %v", victim.PvPKills(), victim.Karma(), killer.IsDead())
+	}
+
+	// Experience works karma off
+	h.revive(killer, 0)
+	h.workOffKarma(killer, 260*100)
+	if killer.Karma() != 140 {
+		t.Errorf("karma after 26000 exp = %d, want 140", killer.Karma())
+	}
+	h.workOffKarma(killer, 260*1000)
+	if killer.Karma() != 0 {
+		t.Errorf("karma = %d, want 0", killer.Karma())
+	}
+
+	h.OnDisconnect(killerClient)
+	if _, ok := h.pvpFlags.Load(killer.ObjectID()); ok {
+		t.Error("PvP flag kept after disconnect")
+	}
+}
+
+func TestHandler_ArenaKillGivesNoKarma(t *testing.T) {
+	ctx := context.Background()
+	h := newZoneHandler(t)
+	aClient := newInGameClient(t, h, 9973, "Gladiator")
+	bClient := newInGameClient(t, h, 9974, "Retiarius")
+	a, b := aClient.ActivePlayer(), bClient.ActivePlayer()
+	for _, p := range []*model.Player{a, b} {
+		p.SetLocation(p.Location().WithCoordinates(17400, 170000, -3500))
+		h.revalidateZones(p)
+	}
+	exp := b.Experience()
+	b.SetCurrentHP(10)
+
+	if _, _, err := h.HandlePacket(ctx, aClient, attackPacket(b.ObjectID()), make([]byte, 1024)); err != nil {
+		t.Fatalf("AttackRequest: %v", err)
+	}
+	if !b.IsDead() {
+		t.Fatal("b should be dead")
+	}
+	if a.IsPvPFlagged() || a.Karma() != 0 || a.PKKills() != 0 || a.PvPKills() != 0 {
+		t.Errorf("arena fighter: flag %v, karma %d, pk %d, pvp %d", a.IsPvPFlagged(), a.Karma(), a.PKKills(), a.PvPKills())
+	}
+	if b.Experience() != exp {
+		t.Errorf("arena death took experience: %d → %d", exp, b.Experience())
+	}
+}
+
+func TestHandler_KarmaDeathKeepsItems(t *testing.T) {
+	cfg := karma.DefaultConfig()
+	cfg.DropChance, cfg.ItemDropChance = 100, 100
+	h := NewHandler(login.NewSessionManager(), WithKarma(cfg))
+	pkClient := newInGameClient(t, h, 9975, "Outlaw")
+	avengerClient := newInGameClient(t, h, 9976, "Avenger")
+	pk := pkClient.ActivePlayer()
+	pk.SetKarma(1000)
+	pk.SetPKKills(cfg.PKLimit)
+	if _, err := pk.Inventory().AddByType(1864, 5); err != nil {
+		t.Fatalf("AddByType: %v", err)
+	}
+	pk.SetCurrentHP(10)
+
+	if _, _, err := h.HandlePacket(context.Background(), avengerClient, attackPacket(pk.ObjectID()), make([]byte, 1024)); err != nil {
+		t.Fatalf("AttackRequest: %v", err)
+	}
+	if !pk.IsDead() {
+		t.Fatal("karma player should be dead")
+	}
+	// Items cannot lie on the ground yet, so nothing is dropped
+	if got := pk.Inventory().CountOf(1864); got != 5 {
+		t.Errorf("items after death = %d, want 5", got)
+	}
+}
@@ -0,0 +1,256 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/quest"
+)
+
+// npcInteractionRange is the maximum distance for talking to an NPC (L2J INTERACTION_DISTANCE).
+const npcInteractionRange = 150
+
+// NpcLocator finds spawned NPCs by objectID (implemented by spawn.Manager).
+type NpcLocator interface {
+	Npc(objectID uint32) (*model.Npc, bool)
+}
+
+// questListOf builds the quest window contents of p.
+func (h *Handler) questListOf(p *model.Player) *serverpackets.QuestList {
+	active := h.quests.Active(p.CharacterID())
+	pkt := &serverpackets.QuestList{Quests: make([]serverpackets.QuestEntry, 0, len(active))}
+	for _, e := range active {
+		pkt.Quests = append(pkt.Quests, serverpackets.QuestEntry{QuestID: e.QuestID, Cond: e.Cond})
+	}
+	return pkt
+}
+
+// npcHTML builds an NPC dialog; %objectId% in quest HTML is replaced with the NPC objectID
+// so that bypass links ("npc_%objectId%_Quest ...") point back to the NPC.
+func npcHTML(npc *model.Npc, html string) *serverpackets.NpcHtmlMessage {
+	id := strconv.FormatUint(uint64(npc.ObjectID()), 10)
+	return &serverpackets.NpcHtmlMessage{
+		NpcObjectID: npc.ObjectID(),
+		HTML:        strings.ReplaceAll(html, "%objectId%", id),
+	}
+}
+
+// AttachQuests loads the quest progress of a player entering the world and sends the quest list.
+func (h *Handler) AttachQuests(ctx context.Context, p *model.Player) error {
+	if err := h.quests.Attach(ctx, p.CharacterID()); err != nil {
+		return fmt.Errorf("attaching quests of %s: %w", p.Name(), err)
+	}
+	h.sendToPlayer(p, h.questListOf(p))
+	return nil
+}
+
+// afterQuestChange refreshes the quest window and persists items given or taken by quests.
+func (h *Handler) afterQuestChange(ctx context.Context, p *model.Player) {
+	h.sendToPlayer(p, h.questListOf(p))
+	h.saveInventory(ctx, p)
+}
+
+// questPlayer is the quest view of a player: experience given by quests works off karma.
+type questPlayer struct {
+	quest.Player
+	h *Handler
+	p *model.Player
+}
+
+func (q questPlayer) AddExpSp(exp, sp int64) {
+	q.Player.AddExpSp(exp, sp)
+	q.h.workOffKarma(q.p, exp)
+}
+
+// questPlayer adapts p for the quest manager.
+func (h *Handler) questPlayer(p *model.Player) quest.Player {
+	return questPlayer{Player: quest.PlayerOf(p), h: h, p: p}
+}
+
+// saveInventory persists the inventory of p after server-side changes (quests, scripts).
+func (h *Handler) saveInventory(ctx context.Context, p *model.Player) {
+	if h.inventories == nil {
+		return
+	}
+	if err := h.inventories.SyncInventory(ctx, p.CharacterID(), p.Inventory().Items()); err != nil {
+		slog.Error("failed to save inventory", "player", p.Name(), "error", err)
+	}
+}
+
+// findNpc returns a spawned NPC by objectID.
+func (h *Handler) findNpc(objectID uint32) *model.Npc {
+	if h.npcs == nil {
+		return nil
+	}
+	npc, ok := h.npcs.Npc(objectID)
+	if !ok {
+		return nil
+	}
+	return npc
+}
+
+// canTalk reports whether player may talk to npc (both alive and within interaction range).
+func canTalk(player *model.Player, npc *model.Npc) bool {
+	return !player.IsDead() && !npc.IsDead() && player.Location().DistanceSquared(npc.Location()) <= npcInteractionRange*npcInteractionRange
+}
+
+// talkToNpc runs NPC dialogs: a quest event when event is set, otherwise the talk
+// of questName. Plain talk (no quest name) shows the NPC script dialog if there is one,
+// else the talk of all NPC quests.
+func (h *Handler) talkToNpc(ctx context.Context, player *model.Player, npc *model.Npc, questName, event string, buf []byte) (int, bool, error) {
+	if !canTalk(player, npc) {
+		return actionFailed(buf)
+	}
+
+	if questName == "" && event == "" && h.scripts != nil {
+		if html, ok := h.scripts.Talk(ctx, player, npc); ok {
+			n, err := writeToBuf(buf, npcHTML(npc, html))
+			return n, true, err
+		}
+	}
+	if questName == "" && event == "" {
+		if dests := h.teleports.Destinations(npc.TemplateID()); dests != nil {
+			n, err := writeToBuf(buf, npcHTML(npc, h.teleportListHTML(player, npc, dests)))
+			return n, true, err
+		}
+	}
+
+	var (
+		res quest.Result
+		err error
+	)
+	qp := h.questPlayer(player)
+	if event != "" {
+		res, err = h.quests.Event(ctx, qp, npc, questName, event)
+	} else {
+		res, err = h.quests.Talk(ctx, qp, npc, questName)
+	}
+	if res.Changed {
+		h.afterQuestChange(ctx, player)
+	}
+	if err != nil {
+		slog.Debug("quest dialog failed", "player", player.Name(), "npc", npc.TemplateID(), "quest", questName, "error", err)
+		return actionFailed(buf)
+	}
+	if res.HTML == "" {
+		return actionFailed(buf)
+	}
+
+	n, err := writeToBuf(buf, npcHTML(npc, res.HTML))
+	return n, true, err
+}
+
+// handleRequestBypassToServer processes RequestBypassToServer (opcode 0x21).
+// Supported: "npc_<objectID>_Quest [quest name [event]]", "npc_<objectID>_Script <event>"
+// and "npc_<objectID>_teleport <destination ID>".
+func (h *Handler) handleRequestBypassToServer(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestBypassToServer(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestBypassToServer: %w", err)
+	}
+
+	if cmd, ok := strings.CutPrefix(pkt.Command, "admin_"); ok {
+		return h.handleAdminCommand(ctx, client, player, cmd, buf)
+	}
+	rest, ok := strings.CutPrefix(pkt.Command, "npc_")
+	if !ok {
+		slog.Debug("unsupported bypass", "player", player.Name(), "command", pkt.Command)
+		return actionFailed(buf)
+	}
+	idStr, action, _ := strings.Cut(rest, "_")
+	objectID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return actionFailed(buf)
+	}
+	npc := h.findNpc(uint32(objectID))
+	if npc == nil {
+		return actionFailed(buf)
+	}
+
+	fields := strings.Fields(action)
+	if len(fields) > 1 && fields[0] == "Script" {
+		return h.scriptEvent(ctx, player, npc, strings.Join(fields[1:], " "), buf)
+	}
+	if len(fields) == 2 && fields[0] == "teleport" {
+		return h.gatekeeperTeleport(ctx, player, npc, fields[1], buf)
+	}
+	if len(fields) == 0 || fields[0] != "Quest" {
+		slog.Debug("unsupported NPC bypass", "player", player.Name(), "command", pkt.Command)
+		return actionFailed(buf)
+	}
+	var questName, event string
+	if len(fields) > 1 {
+		questName = fields[1]
+	}
+	if len(fields) > 2 {
+		event = strings.Join(fields[2:], " ")
+	}
+	return h.talkToNpc(ctx, player, npc, questName, event, buf)
+}
+
+// handleRequestQuestList processes RequestQuestList (opcode 0x63).
+func (h *Handler) handleRequestQuestList(client *GameClient, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	n, err := writeToBuf(buf, h.questListOf(player))
+	return n, true, err
+}
+
+// handleRequestQuestAbort processes RequestQuestAbort (opcode 0x64).
+func (h *Handler) handleRequestQuestAbort(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseRequestQuestAbort(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing RequestQuestAbort: %w", err)
+	}
+
+	if err := h.quests.Abort(ctx, h.questPlayer(player), pkt.QuestID); err != nil {
+		slog.Debug("quest abort rejected", "player", player.Name(), "quest", pkt.QuestID, "error", err)
+		return actionFailed(buf)
+	}
+
+	h.afterQuestChange(ctx, player)
+	return 0, true, nil
+}
+
+// notifyNpcKilled runs script and quest kill triggers for the killer of an NPC.
+func (h *Handler) notifyNpcKilled(ctx context.Context, killer *model.Player, npc *model.Npc) {
+	if h.scripts != nil {
+		h.scripts.Kill(ctx, killer, npc)
+	}
+	res, err := h.quests.Kill(ctx, h.questPlayer(killer), npc)
+	if err != nil {
+		slog.Error("quest kill trigger failed", "player", killer.Name(), "npc", npc.TemplateID(), "error", err)
+	}
+	if len(res) > 0 {
+		h.afterQuestChange(ctx, killer)
+	}
+}
+
+// notifyItemPickup runs quest pickup triggers for a player that got an item from loot.
+func (h *Handler) notifyItemPickup(ctx context.Context, p *model.Player, itemID int32) {
+	res, err := h.quests.Pickup(ctx, h.questPlayer(p), itemID)
+	if err != nil {
+		slog.Error("quest pickup trigger failed", "player", p.Name(), "item", itemID, "error", err)
+	}
+	if len(res) > 0 {
+		h.afterQuestChange(ctx, p)
+	}
+}
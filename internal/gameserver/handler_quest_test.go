@@ -0,0 +1,186 @@
+package gameserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/quest"
+)
+
+// npcMap is an NpcLocator over a fixed set of NPCs.
+type npcMap map[uint32]*model.Npc
+
+func (m npcMap) Npc(objectID uint32) (*model.Npc, bool) {
+	npc, ok := m[objectID]
+	return npc, ok
+}
+
+const (
+	questNpcTemplate = 30001
+	questWolf        = 20120
+	questPelt        = 1001
+)
+
+func newQuestNpc(objectID uint32, templateID int32, x int32) *model.Npc {
+	tmpl := model.NewNpcTemplate(templateID, "QuestNpc", "", 10, 1000, 500,
+		100, 50, 80, 40, 0, 120, 253, 30, 60)
+	npc := model.NewNpc(objectID, templateID, tmpl)
+	npc.SetLocation(model.NewLocation(x, 170000, -3500, 0))
+	return npc
+}
+
+func newQuestHandler(t *testing.T, npcs npcMap, opts ...Option) *Handler {
+	t.Helper()
+	quests := quest.NewManager(nil)
+	err := quests.Register(&quest.Definition{
+		ID:         1,
+		Name:       "Q00001_WolfPelts",
+		MinLevel:   1,
+		QuestItems: []int32{questPelt},
+		Steps: []quest.Step{
+			{On: quest.Talk(questNpcTemplate), HTML: `<a action="bypass -h npc_%objectId%_Quest Q00001_WolfPelts accept">Accept</a>`},
+			{On: quest.Event(questNpcTemplate, "accept"), Next: 1, HTML: "accepted"},
+			{Cond: 1, On: quest.Kill(questWolf), Give: []quest.ItemCount{{ID: questPelt, Count: 1}}, Until: []quest.ItemCount{{ID: questPelt, Count: 2}}, Next: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	opts = append(opts, WithQuests(quests), WithNpcs(npcs))
+	return NewHandler(login.NewSessionManager(), opts...)
+}
+
+func TestHandler_QuestDialogAndKill(t *testing.T) {
+	ctx := context.Background()
+	npc := newQuestNpc(500001, questNpcTemplate, 17050)
+	far := newQuestNpc(500002, questNpcTemplate, 19000)
+	wolf := model.NewMonster(500003, questWolf, model.NewNpcTemplate(questWolf, "Wolf", "", 5, 100, 50,
+		30, 40, 10, 10, 0, 100, 253, 30, 60))
+	wolf.SetLocation(model.NewLocation(17050, 170000, -3500, 0))
+	aiMgr := ai.NewTickManager()
+	h := newQuestHandler(t, npcMap{npc.ObjectID(): npc, far.ObjectID(): far, wolf.ObjectID(): wolf.Npc},
+		WithNpcCombat(aiMgr, &npcDeathLog{}))
+	aiMgr.Register(wolf.ObjectID(), ai.NewAttackableAI(wolf, h.AttackableConfig()))
+	client := newInGameClient(t, h, 9501, "Hunter")
+	player := client.ActivePlayer()
+	if err := h.AttachQuests(ctx, player); err != nil {
+		t.Fatalf("AttachQuests: %v", err)
+	}
+	buf := make([]byte, 4096)
+
+	action := packet.NewWriter(32)
+	_ = action.WriteByte(clientpackets.OpcodeAction)
+	action.WriteInt(int32(npc.ObjectID()))
+	action.WriteInt(0)
+	action.WriteInt(0)
+	action.WriteInt(0)
+	_ = action.WriteByte(0)
+	n, ok, err := h.HandlePacket(ctx, client, action.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeNpcHtmlMessage {
+		t.Fatalf("Action on NPC: n=%d ok=%v err=%v opcode=0x%02X", n, ok, err, buf[0])
+	}
+	r := packet.NewReader(buf[5:n])
+	html, _ := r.ReadString()
+	if !strings.Contains(html, "npc_500001_Quest") {
+		t.Errorf("objectId placeholder not replaced: %q", html)
+	}
+
+	farAction := packet.NewWriter(32)
+	_ = farAction.WriteByte(clientpackets.OpcodeAction)
+	farAction.WriteInt(int32(far.ObjectID()))
+	farAction.WriteInt(0)
+	farAction.WriteInt(0)
+	farAction.WriteInt(0)
+	_ = farAction.WriteByte(0)
+	if n, _, _ := h.HandlePacket(ctx, client, farAction.Bytes(), buf); n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("talking to a distant NPC must fail, got opcode 0x%02X", buf[0])
+	}
+
+	bypass := packet.NewWriter(128)
+	_ = bypass.WriteByte(clientpackets.OpcodeRequestBypassToServer)
+	bypass.WriteString("npc_500001_Quest Q00001_WolfPelts accept")
+	n, ok, err = h.HandlePacket(ctx, client, bypass.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeNpcHtmlMessage {
+		t.Fatalf("quest bypass: n=%d ok=%v err=%v opcode=0x%02X", n, ok, err, buf[0])
+	}
+	if h.Quests().Cond(player.CharacterID(), 1) != 1 {
+		t.Fatal("quest should be started")
+	}
+
+	// Each hit kills the wolf, which comes back with full HP
+	for range 2 {
+		wolf.SetCurrentHP(wolf.MaxHP())
+		if _, _, err := h.HandlePacket(ctx, client, attackPacket(wolf.ObjectID()), buf); err != nil {
+			t.Fatalf("AttackRequest: %v", err)
+		}
+		if !wolf.IsDead() {
+			t.Fatal("wolf should die in one hit")
+		}
+	}
+	if player.Inventory().CountOf(questPelt) != 2 || h.Quests().Cond(player.CharacterID(), 1) != 2 {
+		t.Fatalf("pelts=%d cond=%d", player.Inventory().CountOf(questPelt), h.Quests().Cond(player.CharacterID(), 1))
+	}
+
+	list := []byte{clientpackets.OpcodeRequestQuestList}
+	n, _, err = h.HandlePacket(ctx, client, list, buf)
+	if err != nil || n != 3+8 || buf[0] != serverpackets.OpcodeQuestList {
+		t.Fatalf("RequestQuestList: n=%d err=%v", n, err)
+	}
+
+	abort := packet.NewWriter(8)
+	_ = abort.WriteByte(clientpackets.OpcodeRequestQuestAbort)
+	abort.WriteInt(1)
+	if _, ok, err := h.HandlePacket(ctx, client, abort.Bytes(), buf); err != nil || !ok {
+		t.Fatalf("RequestQuestAbort: ok=%v err=%v", ok, err)
+	}
+	if len(h.Quests().Active(player.CharacterID())) != 0 || player.Inventory().CountOf(questPelt) != 0 {
+		t.Error("abort should remove progress and quest items")
+	}
+}
+
+func TestHandler_QuestPickup(t *testing.T) {
+	ctx := context.Background()
+	const amulet = 1002
+	quests := quest.NewManager(nil)
+	err := quests.Register(&quest.Definition{
+		ID:         2,
+		Name:       "Q00002_WolfFangs",
+		MinLevel:   1,
+		QuestItems: []int32{amulet},
+		Steps: []quest.Step{
+			{On: quest.Event(questNpcTemplate, "accept"), Next: 1},
+			{Cond: 1, On: quest.Pickup(amulet), Until: []quest.ItemCount{{ID: amulet, Count: 3}}, Next: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	h, monster, _, _ := newMonsterHandler(t, WithQuests(quests))
+	monster.Template().SetDrops([]model.NpcDrop{{ItemType: amulet, Min: 3, Max: 3, Chance: model.DropChanceMax}})
+	client := newInGameClient(t, h, 9502, "Collector")
+	player := client.ActivePlayer()
+	if err := h.AttachQuests(ctx, player); err != nil {
+		t.Fatalf("AttachQuests: %v", err)
+	}
+	npc := newQuestNpc(500004, questNpcTemplate, 17050)
+	if _, err := quests.Event(ctx, quest.PlayerOf(player), npc, "Q00002_WolfFangs", "accept"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	for !monster.IsDead() {
+		if _, _, err := h.HandlePacket(ctx, client, attackPacket(monster.ObjectID()), buf); err != nil {
+			t.Fatalf("AttackRequest: %v", err)
+		}
+	}
+	if player.Inventory().CountOf(amulet) != 3 || quests.Cond(player.CharacterID(), 2) != 2 {
+		t.Errorf("amulets=%d cond=%d, want 3 and 2", player.Inventory().CountOf(amulet), quests.Cond(player.CharacterID(), 2))
+	}
+}
@@ -0,0 +1,108 @@
+package gameserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/ratelimit"
+)
+
+// rateLimitOffendersShown is how many offenders //ratelimits lists.
+const rateLimitOffendersShown = 20
+
+// DefaultRateLimits returns the packet limits used when there is no rate limits file.
+func DefaultRateLimits() *ratelimit.Rules {
+	return &ratelimit.Rules{
+		Global: ratelimit.NewRule(ratelimit.GlobalRule, 100, 200, ratelimit.Disconnect),
+		ByOpcode: map[ratelimit.Key]*ratelimit.Rule{
+			ratelimit.Opcode(clientpackets.OpcodeSay2):                   ratelimit.NewRule("Say2", 3, 5, ratelimit.Drop),
+			ratelimit.Opcode(clientpackets.OpcodeMoveBackwardToLocation): ratelimit.NewRule("MoveBackwardToLocation", 10, 0, ratelimit.Drop),
+			ratelimit.Opcode(clientpackets.OpcodeUseItem):                ratelimit.NewRule("UseItem", 8, 0, ratelimit.Drop),
+		},
+	}
+}
+
+// rateLimitKey returns the rate limit key of a packet: extended packets are limited
+// by their sub-opcode. A body too short for one is limited as sub-opcode 0.
+func rateLimitKey(opcode byte, body []byte) ratelimit.Key {
+	if opcode != clientpackets.OpcodeExtended {
+		return ratelimit.Opcode(opcode)
+	}
+	sub, _, _ := clientpackets.ParseExtendedOpcode(body)
+	return ratelimit.Extended(sub)
+}
+
+// limitPacket takes a packet from the client's rate limit buckets. It reports whether
+// the packet is handled and, if it is not, whether the connection stays open.
+func (h *Handler) limitPacket(client *GameClient, key ratelimit.Key) (handle, keepOpen bool) {
+	if h.rateLimits == nil {
+		return true, true
+	}
+	if client.rateLimit == nil {
+		client.rateLimit = h.rateLimits.NewClient()
+	}
+	who := client.AccountName()
+	if who == "" {
+		who = client.IP()
+	}
+
+	rule := client.rateLimit.Allow(key, who)
+	if rule == nil {
+		return true, true
+	}
+	switch rule.Action {
+	case ratelimit.Warn:
+		slog.Warn("packet rate limit exceeded",
+			"rule", rule.Name, "opcode", key.String(), "client", who, "ip", client.IP())
+		return true, true
+	case ratelimit.Disconnect:
+		slog.Warn("packet rate limit exceeded, disconnecting",
+			"rule", rule.Name, "opcode", key.String(), "client", who, "ip", client.IP())
+		return false, false
+	default:
+		slog.Debug("packet over rate limit dropped",
+			"rule", rule.Name, "opcode", key.String(), "client", who)
+		return false, true
+	}
+}
+
+// adminRateLimits lists the clients tripping packet limits: //ratelimits [reset].
+func (h *Handler) adminRateLimits(_ context.Context, gm *model.Player, args []string) (string, error) {
+	if h.rateLimits == nil {
+		return "", errors.New("packet rate limits are disabled")
+	}
+	switch {
+	case len(args) == 1 && args[0] == "reset":
+		h.rateLimits.ResetOffenders()
+		h.adminMessage(gm, "Rate limit counters reset.")
+		return "", nil
+	case len(args) != 0:
+		return "", errAdminUsage
+	}
+
+	var b strings.Builder
+	b.WriteString("<html><title>Rate limits</title><body>")
+	offenders := h.rateLimits.Offenders()
+	if len(offenders) == 0 {
+		b.WriteString("Nobody has tripped a limit.")
+	}
+	for _, o := range offenders[:min(len(offenders), rateLimitOffendersShown)] {
+		fmt.Fprintf(&b, "%s: %d, last at %s<br1>", html.EscapeString(o.Who), o.Total, o.Last.Format("15:04:05"))
+		for _, rule := range slices.Sorted(maps.Keys(o.Trips)) {
+			fmt.Fprintf(&b, "&nbsp;&nbsp;%s: %d<br1>", html.EscapeString(rule), o.Trips[rule])
+		}
+		b.WriteString("<br>")
+	}
+	b.WriteString(`<a action="bypass -h admin_ratelimits reset">reset</a></body></html>`)
+	h.sendToPlayer(gm, &serverpackets.NpcHtmlMessage{HTML: b.String()})
+	return "", nil
+}
@@ -0,0 +1,109 @@
+package gameserver
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/ratelimit"
+)
+
+func TestHandler_RateLimits(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewLimiter(&ratelimit.Rules{
+		ByOpcode: map[ratelimit.Key]*ratelimit.Rule{
+			ratelimit.Opcode(clientpackets.OpcodeSay2):    ratelimit.NewRule("Say2", 0.001, 1, ratelimit.Drop),
+			ratelimit.Opcode(clientpackets.OpcodeUseItem): ratelimit.NewRule("UseItem", 0.001, 1, ratelimit.Disconnect),
+		},
+	})
+	var audit bytes.Buffer
+	h, _ := newAdminHandler(t, &audit, WithRateLimits(limiter))
+	alice := newInGameClient(t, h, 9301, "Alice")
+	alice.SetAccountName("alice")
+	buf := make([]byte, 4096)
+
+	say := func() (int, bool) {
+		t.Helper()
+		w := packet.NewWriter(64)
+		_ = w.WriteByte(clientpackets.OpcodeSay2)
+		w.WriteString("spam")
+		w.WriteInt(clientpackets.ChatAll)
+		n, keepOpen, err := h.HandlePacket(ctx, alice, w.Bytes(), buf)
+		if err != nil {
+			t.Fatalf("Say2: %v", err)
+		}
+		return n, keepOpen
+	}
+
+	if n, _ := say(); n == 0 || buf[0] != serverpackets.OpcodeCreatureSay {
+		t.Fatalf("first Say2 not handled: n=%d", n)
+	}
+	if n, keepOpen := say(); n != 0 || !keepOpen {
+		t.Errorf("Say2 over the limit: n=%d keepOpen=%v, want dropped", n, keepOpen)
+	}
+
+	// Over a disconnect rule the connection is closed before the packet is read
+	useItem := []byte{clientpackets.OpcodeUseItem, 0, 0, 0, 0, 0, 0, 0, 0}
+	h.HandlePacket(ctx, alice, useItem, buf)
+	if _, keepOpen, _ := h.HandlePacket(ctx, alice, useItem, buf); keepOpen {
+		t.Error("UseItem over the limit kept the connection open")
+	}
+
+	offenders := limiter.Offenders()
+	if len(offenders) != 1 || offenders[0].Who != "alice" || offenders[0].Trips["Say2"] != 1 || offenders[0].Trips["UseItem"] != 1 {
+		t.Fatalf("offenders = %+v", offenders)
+	}
+
+	// GMs see who trips limits
+	gmClient := newInGameClient(t, h, 9302, "Admin")
+	gmClient.SetAccessLevel(100)
+	if n, _, _ := h.HandlePacket(ctx, gmClient, bypassPacket("admin_ratelimits"), buf); n != 0 {
+		t.Fatalf("//ratelimits replied with opcode 0x%02X", buf[0])
+	}
+	if !strings.Contains(audit.String(), "ratelimits") {
+		t.Errorf("//ratelimits not audited: %q", audit.String())
+	}
+	h.HandlePacket(ctx, gmClient, bypassPacket("admin_ratelimits reset"), buf)
+	if len(limiter.Offenders()) != 0 {
+		t.Error("//ratelimits reset kept counters")
+	}
+}
+
+func TestHandler_RateLimitsExtended(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewLimiter(&ratelimit.Rules{
+		ByOpcode: map[ratelimit.Key]*ratelimit.Rule{
+			ratelimit.Extended(0x7E): ratelimit.NewRule("Flooded", 0.001, 1, ratelimit.Disconnect),
+		},
+	})
+	h := NewHandler(login.NewSessionManager(), WithRateLimits(limiter))
+	client := newInGameClient(t, h, 9303, "Extended")
+	buf := make([]byte, 1024)
+
+	extended := func(sub int16) bool {
+		t.Helper()
+		w := packet.NewWriter(8)
+		_ = w.WriteByte(clientpackets.OpcodeExtended)
+		w.WriteShort(sub)
+		_, keepOpen, _ := h.HandlePacket(ctx, client, w.Bytes(), buf)
+		return keepOpen
+	}
+
+	if !extended(0x7E) {
+		t.Fatal("first limited extended packet closed the connection")
+	}
+	// Other sub-opcodes of 0xD0 do not share the limit
+	for range 5 {
+		if !extended(0x7F) {
+			t.Fatal("extended packet with another sub-opcode hit the limit")
+		}
+	}
+	if extended(0x7E) {
+		t.Error("extended packet over its limit kept the connection open")
+	}
+}
@@ -0,0 +1,61 @@
+package gameserver
+
+import (
+	"context"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// scriptMessenger delivers script output to game clients (implements script.Messenger).
+type scriptMessenger struct {
+	h *Handler
+}
+
+// NpcSay broadcasts an NPC chat line to players around the NPC.
+func (m scriptMessenger) NpcSay(npc *model.Npc, text string) {
+	m.h.broadcastAround(npc.Location(), &serverpackets.CreatureSay{
+		ObjectID: npc.ObjectID(),
+		ChatType: clientpackets.ChatAll,
+		Name:     npc.Name(),
+		Text:     text,
+	})
+}
+
+// SendMessage shows a system message to the player.
+func (m scriptMessenger) SendMessage(player *model.Player, text string) {
+	m.h.sendToPlayer(player, serverpackets.NewSystemMessageText(text))
+}
+
+// ShowHTML opens a dialog window; bypass links point to npc when it is set.
+func (m scriptMessenger) ShowHTML(player *model.Player, npc *model.Npc, html string) {
+	if npc == nil {
+		m.h.sendToPlayer(player, &serverpackets.NpcHtmlMessage{HTML: html})
+		return
+	}
+	m.h.sendToPlayer(player, npcHTML(npc, html))
+}
+
+// InventoryChanged persists items given or taken by a script.
+func (m scriptMessenger) InventoryChanged(ctx context.Context, player *model.Player) {
+	m.h.saveInventory(ctx, player)
+}
+
+// ExpGained works off karma with experience given by a script.
+func (m scriptMessenger) ExpGained(player *model.Player, exp int64) {
+	m.h.workOffKarma(player, exp)
+}
+
+// scriptEvent runs the on_event hook of the NPC script (bypass "npc_<objectID>_Script <event>").
+func (h *Handler) scriptEvent(ctx context.Context, player *model.Player, npc *model.Npc, event string, buf []byte) (int, bool, error) {
+	if h.scripts == nil || !canTalk(player, npc) {
+		return actionFailed(buf)
+	}
+	html, ok := h.scripts.Event(ctx, player, npc, event)
+	if !ok {
+		return actionFailed(buf)
+	}
+	n, err := writeToBuf(buf, npcHTML(npc, html))
+	return n, true, err
+}
@@ -0,0 +1,87 @@
+package gameserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/script"
+)
+
+const shopkeeperScript = `
+register_npc(30101)
+
+function on_talk(player, npc)
+	return "<a action=\"bypass -h npc_%objectId%_Script gift\">Gift</a>"
+end
+
+function on_event(player, npc, event)
+	if event == "gift" then
+		player:give_item(57, 500)
+		return "<html>Enjoy, " .. player:name() .. "</html>"
+	end
+end
+`
+
+func TestHandler_ScriptedNpc(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shopkeeper.lua"), []byte(shopkeeperScript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	npc := newQuestNpc(500101, 30101, 17050)
+	npcs := npcMap{npc.ObjectID(): npc}
+	scripts := script.NewRuntime(dir, nil, script.DefaultLimits())
+	if _, err := scripts.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	h := NewHandler(login.NewSessionManager(), WithNpcs(npcs), WithScripts(scripts))
+	client := newInGameClient(t, h, 9601, "Shopper")
+	player := client.ActivePlayer()
+	buf := make([]byte, 4096)
+
+	action := packet.NewWriter(32)
+	_ = action.WriteByte(clientpackets.OpcodeAction)
+	action.WriteInt(int32(npc.ObjectID()))
+	action.WriteInt(0)
+	action.WriteInt(0)
+	action.WriteInt(0)
+	_ = action.WriteByte(0)
+	n, ok, err := h.HandlePacket(ctx, client, action.Bytes(), buf)
+	if err != nil || !ok || n == 0 || buf[0] != serverpackets.OpcodeNpcHtmlMessage {
+		t.Fatalf("Action on scripted NPC: n=%d ok=%v err=%v opcode=0x%02X", n, ok, err, buf[0])
+	}
+	r := packet.NewReader(buf[5:n])
+	if html, _ := r.ReadString(); html != `<a action="bypass -h npc_500101_Script gift">Gift</a>` {
+		t.Errorf("dialog = %q", html)
+	}
+
+	bypass := packet.NewWriter(64)
+	_ = bypass.WriteByte(clientpackets.OpcodeRequestBypassToServer)
+	bypass.WriteString("npc_500101_Script gift")
+	n, _, err = h.HandlePacket(ctx, client, bypass.Bytes(), buf)
+	if err != nil || buf[0] != serverpackets.OpcodeNpcHtmlMessage {
+		t.Fatalf("Script bypass: err=%v opcode=0x%02X", err, buf[0])
+	}
+	r = packet.NewReader(buf[5:n])
+	if html, _ := r.ReadString(); html != "<html>Enjoy, Shopper</html>" {
+		t.Errorf("event dialog = %q", html)
+	}
+	if player.Inventory().CountOf(57) != 500 {
+		t.Errorf("adena = %d, want 500", player.Inventory().CountOf(57))
+	}
+
+	// Unknown event: no dialog
+	bypass = packet.NewWriter(64)
+	_ = bypass.WriteByte(clientpackets.OpcodeRequestBypassToServer)
+	bypass.WriteString("npc_500101_Script nothing")
+	if _, _, err := h.HandlePacket(ctx, client, bypass.Bytes(), buf); err != nil || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("unknown script event: err=%v opcode=0x%02X", err, buf[0])
+	}
+}
@@ -0,0 +1,167 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/teleport"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// escapeScrolls are the scrolls that return players to the closest town, with their cast times
+// (L2J skills 2013 and 2036).
+var escapeScrolls = map[int32]time.Duration{
+	736:  20 * time.Second,       // Scroll of Escape
+	1538: 200 * time.Millisecond, // Blessed Scroll of Escape
+}
+
+// canTeleport reports whether a player may be sent elsewhere by a gatekeeper or a scroll.
+// Jailed players stay in the jail.
+func (h *Handler) canTeleport(p *model.Player) bool {
+	if p.IsDead() || p.IsSitting() || p.PrivateStoreType().IsActive() {
+		return false
+	}
+	_, jailed := h.jailCell(p)
+	return !jailed
+}
+
+// Teleport moves a player to loc at once: the player leaves the region it was in for the
+// region of loc, players around the old place drop it, players around the new one see it,
+// and its zones are checked again.
+func (h *Handler) Teleport(p *model.Player, loc model.Location) error {
+	w := world.Instance()
+	if w.GetRegion(loc.X, loc.Y) == nil {
+		return fmt.Errorf("teleporting %s: (%d, %d) is outside the world", p.Name(), loc.X, loc.Y)
+	}
+	h.stopMoving(p)
+
+	from := p.Location()
+	h.broadcastFrom(p, from, &serverpackets.TeleportToLocation{ObjectID: p.ObjectID(), Loc: loc})
+	w.RemoveObject(p.ObjectID())
+	p.SetLocation(loc.WithHeading(from.Heading))
+	if err := w.AddObject(p.WorldObject); err != nil {
+		return fmt.Errorf("teleporting %s: %w", p.Name(), err)
+	}
+	p.InvalidateVisibilityCache()
+	h.broadcastCharInfo(p)
+	h.revalidateZones(p)
+
+	slog.Debug("player teleported", "player", p.Name(), "from", from, "to", loc)
+	return nil
+}
+
+// returnToVillage teleports a player to the town of its map region
+// (escape scrolls, restart after death).
+func (h *Handler) returnToVillage(p *model.Player) bool {
+	loc, ok := h.zones.RestartPoint(p.Location())
+	if !ok {
+		slog.Warn("no town to return to", "player", p.Name(), "location", p.Location())
+		return false
+	}
+	if err := h.Teleport(p, loc); err != nil {
+		slog.Error("failed to return to village", "player", p.Name(), "error", err)
+		return false
+	}
+	return true
+}
+
+// teleportListHTML builds the gatekeeper dialog with prices after discounts.
+func (h *Handler) teleportListHTML(p *model.Player, npc *model.Npc, dests []teleport.Destination) string {
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "<html><body>%s:<br>", npc.Name())
+	for _, d := range dests {
+		fmt.Fprintf(&b, `<a action="bypass -h npc_%%objectId%%_teleport %d">%s - %d Adena</a><br>`,
+			d.ID, d.Name, h.teleportDiscounts.Price(d, p.Level(), now))
+	}
+	b.WriteString(`<a action="bypass -h npc_%objectId%_Quest">Quest</a></body></html>`)
+	return b.String()
+}
+
+// gatekeeperTeleport takes the teleport fee and sends the player to a destination of the gatekeeper
+// (bypass "npc_<objectID>_teleport <destination ID>").
+func (h *Handler) gatekeeperTeleport(ctx context.Context, player *model.Player, npc *model.Npc, destID string, buf []byte) (int, bool, error) {
+	if !canTalk(player, npc) || !h.canTeleport(player) {
+		return actionFailed(buf)
+	}
+	id, err := strconv.ParseInt(destID, 10, 32)
+	if err != nil {
+		return actionFailed(buf)
+	}
+	dest, ok := h.teleports.Destination(npc.TemplateID(), int32(id))
+	if !ok {
+		return actionFailed(buf)
+	}
+
+	if price := h.teleportDiscounts.Price(dest, player.Level(), time.Now()); price > 0 {
+		if err := player.Inventory().DestroyByType(model.AdenaItemID, price); err != nil {
+			n, err := writeToBuf(buf, serverpackets.NewSystemMessage(serverpackets.SystemMessageNotEnoughAdena))
+			return n, true, err
+		}
+		h.saveInventory(ctx, player)
+	}
+	if err := h.Teleport(player, dest.Loc); err != nil {
+		slog.Error("gatekeeper teleport failed", "player", player.Name(), "destination", dest.Name, "error", err)
+		return actionFailed(buf)
+	}
+	return 0, true, nil
+}
+
+// handleUseItem processes UseItem (opcode 0x14). Only escape and resurrection scrolls can be
+// used so far: an escape scroll is consumed and the player returns to village when the cast
+// ends; a resurrection scroll offers resurrection to the selected dead player.
+func (h *Handler) handleUseItem(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseUseItem(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing UseItem: %w", err)
+	}
+
+	item := player.Inventory().ItemByObjectID(uint32(pkt.ObjectID))
+	if item == nil || player.IsDead() {
+		return actionFailed(buf)
+	}
+	if power, ok := resurrectionScrolls[item.ItemType()]; ok {
+		return h.useResurrectionScroll(ctx, player, item, power, buf)
+	}
+	castTime, ok := escapeScrolls[item.ItemType()]
+	if !ok || !h.canTeleport(player) {
+		return actionFailed(buf)
+	}
+	if _, casting := h.escapes.Load(player.ObjectID()); casting {
+		return actionFailed(buf)
+	}
+	if err := player.Inventory().DestroyByType(item.ItemType(), 1); err != nil {
+		return actionFailed(buf)
+	}
+	h.saveInventory(ctx, player)
+
+	h.stopMoving(player)
+	h.escapes.Store(player.ObjectID(), time.Now().Add(castTime))
+	return 0, true, nil
+}
+
+// finishEscapes returns to village the players whose escape casts ended by now.
+func (h *Handler) finishEscapes(now time.Time) {
+	h.escapes.Range(func(key, value any) bool {
+		if value.(time.Time).After(now) {
+			return true
+		}
+		h.escapes.Delete(key)
+		if p := h.findPlayer(key.(uint32)); p != nil && !p.IsDead() {
+			h.returnToVillage(p)
+		}
+		return true
+	})
+}
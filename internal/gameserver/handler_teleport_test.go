@@ -0,0 +1,126 @@
+package gameserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/teleport"
+	"github.com/udisondev/la2go/internal/world"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+const gatekeeperTemplate = 30006
+
+func newTeleportHandler(t *testing.T, npcs npcMap) *Handler {
+	t.Helper()
+	table, err := teleport.LoadTable(strings.NewReader(`
+gatekeepers:
+  - npc_id: 30006
+    destinations:
+      - {id: 1, name: Elven Village, x: 46934, y: 51467, z: -2977, price: 7100}
+`))
+	if err != nil {
+		t.Fatalf("LoadTable: %v", err)
+	}
+	zones := zone.NewManager()
+	if err := zones.Add(&zone.Zone{ID: 1, Name: "gludin", Type: zone.Town, TownID: 1,
+		Shape:        zone.Cylinder{X: -80000, Y: 150000, Radius: 2000, MinZ: -4000, MaxZ: -2000},
+		RestartPoint: model.NewLocation(-80826, 149775, -3043, 0)}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return NewHandler(login.NewSessionManager(), WithNpcs(npcs), WithZones(zones),
+		WithTeleports(table, teleport.Discounts{FreeLevel: 10}))
+}
+
+func bypassPacket(command string) []byte {
+	w := packet.NewWriter(128)
+	_ = w.WriteByte(clientpackets.OpcodeRequestBypassToServer)
+	w.WriteString(command)
+	return w.Bytes()
+}
+
+func TestHandler_GatekeeperTeleport(t *testing.T) {
+	ctx := context.Background()
+	npc := newQuestNpc(510001, gatekeeperTemplate, 17050)
+	h := newTeleportHandler(t, npcMap{npc.ObjectID(): npc})
+	client := newInGameClient(t, h, 9951, "Traveler")
+	player := client.ActivePlayer()
+	buf := make([]byte, 4096)
+
+	action := packet.NewWriter(32)
+	_ = action.WriteByte(clientpackets.OpcodeAction)
+	action.WriteInt(int32(npc.ObjectID()))
+	action.WriteBytes(make([]byte, 13))
+	n, _, err := h.HandlePacket(ctx, client, action.Bytes(), buf)
+	if err != nil || n == 0 || buf[0] != serverpackets.OpcodeNpcHtmlMessage {
+		t.Fatalf("Action on gatekeeper: n=%d err=%v opcode=0x%02X", n, err, buf[0])
+	}
+	r := packet.NewReader(buf[5:n])
+	html, _ := r.ReadString()
+	if !strings.Contains(html, "npc_510001_teleport 1") || !strings.Contains(html, "7100 Adena") {
+		t.Errorf("teleport list = %q", html)
+	}
+
+	// Without adena the gatekeeper refuses
+	n, _, _ = h.HandlePacket(ctx, client, bypassPacket("npc_510001_teleport 1"), buf)
+	if n == 0 || buf[0] != serverpackets.OpcodeSystemMessage || player.X() != 17000 {
+		t.Fatalf("teleport without adena: opcode=0x%02X at %v", buf[0], player.Location())
+	}
+
+	giveItem(t, player, 1, model.AdenaItemID, 10000)
+	if _, _, err := h.HandlePacket(ctx, client, bypassPacket("npc_510001_teleport 1"), buf); err != nil {
+		t.Fatalf("teleport bypass: %v", err)
+	}
+	if loc := player.Location(); loc.X != 46934 || loc.Y != 51467 || loc.Z != -2977 {
+		t.Fatalf("after teleport at %v, want the Elven Village", loc)
+	}
+	if player.Inventory().Adena() != 2900 {
+		t.Errorf("adena = %d, want 2900", player.Inventory().Adena())
+	}
+	if obj, ok := world.Instance().GetObject(player.ObjectID()); !ok || obj != player.WorldObject {
+		t.Error("teleported player left the world")
+	}
+	if h.Teleport(player, model.NewLocation(1_000_000, 0, 0, 0)) == nil {
+		t.Error("teleport outside the world should fail")
+	}
+}
+
+func TestHandler_EscapeScroll(t *testing.T) {
+	ctx := context.Background()
+	h := newTeleportHandler(t, npcMap{})
+	client := newInGameClient(t, h, 9952, "Escaper")
+	player := client.ActivePlayer()
+	scroll := giveItem(t, player, 2, 736, 1)
+	buf := make([]byte, 1024)
+
+	use := packet.NewWriter(16)
+	_ = use.WriteByte(clientpackets.OpcodeUseItem)
+	use.WriteInt(int32(scroll.ObjectID()))
+	use.WriteInt(0)
+	if n, _, err := h.HandlePacket(ctx, client, use.Bytes(), buf); err != nil || n != 0 {
+		t.Fatalf("UseItem: n=%d err=%v", n, err)
+	}
+	if player.Inventory().CountOf(736) != 0 {
+		t.Error("scroll not consumed")
+	}
+
+	// The cast takes 20 seconds
+	h.finishEscapes(time.Now())
+	if player.X() != 17000 {
+		t.Fatalf("escaped before the cast ended: %v", player.Location())
+	}
+	h.finishEscapes(time.Now().Add(21 * time.Second))
+	if loc := player.Location(); loc.X != -80826 || loc.Y != 149775 {
+		t.Errorf("after escape at %v, want the Gludin restart point", loc)
+	}
+	if !h.Zones().InsideZone(player.WorldObject, zone.Town) {
+		t.Error("escaped player should be in the town")
+	}
+}
@@ -0,0 +1,166 @@
+package gameserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/trade"
+)
+
+// handleTradeRequest processes TradeRequest (opcode 0x15).
+func (h *Handler) handleTradeRequest(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+	if h.tradeBanned(player) {
+		return actionFailed(buf)
+	}
+
+	pkt, err := clientpackets.ParseTradeRequest(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing TradeRequest: %w", err)
+	}
+
+	c, ok := h.clients.ByObjectID(pkt.ObjectID)
+	if !ok || c.ActivePlayer() == nil {
+		return actionFailed(buf)
+	}
+	target := c.ActivePlayer()
+	if player.IsDead() || target.IsDead() ||
+		player.PrivateStoreType() != model.PrivateStoreNone || target.PrivateStoreType() != model.PrivateStoreNone ||
+		player.Location().DistanceSquared(target.Location()) > trade.Range*trade.Range {
+		return actionFailed(buf)
+	}
+	if h.friends.IsBlocked(target, player) {
+		slog.Debug("trade request blocked", "from", player.Name(), "to", target.Name())
+		return actionFailed(buf)
+	}
+
+	if err := h.trades.Request(player, target); err != nil {
+		slog.Debug("trade request rejected", "from", player.Name(), "to", target.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	h.sendToPlayer(target, &serverpackets.SendTradeRequest{RequesterID: player.ObjectID()})
+	return 0, true, nil
+}
+
+// handleAnswerTradeRequest processes AnswerTradeRequest (opcode 0x44).
+func (h *Handler) handleAnswerTradeRequest(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseAnswerTradeRequest(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing AnswerTradeRequest: %w", err)
+	}
+
+	accept := pkt.Accept && !h.tradeBanned(player)
+	requester, err := h.trades.Answer(player, accept)
+	if err != nil {
+		slog.Debug("trade request answer rejected", "player", player.Name(), "error", err)
+		return actionFailed(buf)
+	}
+	if !accept {
+		return 0, true, nil
+	}
+
+	h.sendToPlayer(requester, &serverpackets.TradeStart{PartnerID: player.ObjectID(), Items: tradableItems(requester)})
+	sendPacket(client, &serverpackets.TradeStart{PartnerID: requester.ObjectID(), Items: tradableItems(player)})
+	return 0, true, nil
+}
+
+// handleAddTradeItem processes AddTradeItem (opcode 0x16).
+func (h *Handler) handleAddTradeItem(client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseAddTradeItem(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing AddTradeItem: %w", err)
+	}
+
+	added, partner, err := h.trades.AddItem(player, pkt.ObjectID, pkt.Count)
+	if err != nil {
+		slog.Debug("trade item rejected", "player", player.Name(), "item", pkt.ObjectID, "error", err)
+		return actionFailed(buf)
+	}
+	sendPacket(client, &serverpackets.TradeAdd{Item: added})
+	h.sendToPlayer(partner, &serverpackets.TradeAdd{Partner: true, Item: added})
+	return 0, true, nil
+}
+
+// handleTradeDone processes TradeDone (opcode 0x17): the player confirms or cancels the trade.
+func (h *Handler) handleTradeDone(ctx context.Context, client *GameClient, data, buf []byte) (int, bool, error) {
+	player := client.ActivePlayer()
+	if player == nil {
+		return 0, true, nil
+	}
+
+	pkt, err := clientpackets.ParseTradeDone(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing TradeDone: %w", err)
+	}
+
+	if !pkt.Confirm {
+		h.cancelTrade(player)
+		return 0, true, nil
+	}
+
+	res, err := h.trades.Confirm(player)
+	if err != nil {
+		return actionFailed(buf)
+	}
+	if !res.Done {
+		sendPacket(client, &serverpackets.TradePressOk{})
+		h.sendToPlayer(res.Partner, &serverpackets.TradePressOk{Partner: true})
+		return 0, true, nil
+	}
+
+	if res.Err != nil {
+		slog.Warn("trade failed", "player", player.Name(), "partner", res.Partner.Name(), "error", res.Err)
+	} else {
+		h.saveInventory(ctx, player)
+		h.saveInventory(ctx, res.Partner)
+	}
+	done := &serverpackets.TradeDone{Success: res.Err == nil}
+	sendPacket(client, done)
+	h.sendToPlayer(res.Partner, done)
+	return 0, true, nil
+}
+
+// cancelTrade closes the trade of a player and its requests; the partner's window closes too.
+func (h *Handler) cancelTrade(player *model.Player) {
+	h.trades.CancelRequests(player)
+	partner := h.trades.Cancel(player)
+	if partner == nil {
+		return
+	}
+	h.sendToPlayer(player, &serverpackets.TradeDone{})
+	h.sendToPlayer(partner, &serverpackets.TradeDone{})
+}
+
+// tradableItems returns the inventory items a player can put into a trade window.
+func tradableItems(p *model.Player) []model.TradeItem {
+	var items []model.TradeItem
+	for _, it := range p.Inventory().Items() {
+		if it.IsEquipped() {
+			continue
+		}
+		items = append(items, model.TradeItem{
+			ObjectID: it.ObjectID(),
+			ItemType: it.ItemType(),
+			Enchant:  it.Enchant(),
+			Count:    it.Count(),
+		})
+	}
+	return items
+}
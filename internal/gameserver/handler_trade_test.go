@@ -0,0 +1,96 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestHandler_Trade(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler(login.NewSessionManager())
+	alice := newInGameClient(t, h, 9601, "Alice")
+	bob := newInGameClient(t, h, 9602, "Bob")
+	buf := make([]byte, 1024)
+
+	give := func(p *model.Player, itemID int64, itemType, count int32) *model.Item {
+		t.Helper()
+		item, err := model.NewItem(p.CharacterID(), itemType, count)
+		if err != nil {
+			t.Fatalf("NewItem: %v", err)
+		}
+		item.SetItemID(itemID)
+		p.Inventory().AddItem(item)
+		return item
+	}
+	sword := give(alice.ActivePlayer(), 96011, 100, 1)
+	adena := give(bob.ActivePlayer(), 96021, model.AdenaItemID, 1000)
+
+	send := func(client *GameClient, opcode byte, ints ...int32) int {
+		t.Helper()
+		w := packet.NewWriter(16)
+		_ = w.WriteByte(opcode)
+		for _, v := range ints {
+			w.WriteInt(v)
+		}
+		n, _, err := h.HandlePacket(ctx, client, w.Bytes(), buf)
+		if err != nil {
+			t.Fatalf("opcode 0x%02X: %v", opcode, err)
+		}
+		return n
+	}
+
+	if n := send(alice, clientpackets.OpcodeTradeRequest, int32(bob.ActivePlayer().ObjectID())); n != 0 {
+		t.Fatalf("TradeRequest replied with opcode 0x%02X", buf[0])
+	}
+	send(bob, clientpackets.OpcodeAnswerTradeRequest, 1)
+	if !h.trades.IsTrading(alice.ActivePlayer()) || !h.trades.IsTrading(bob.ActivePlayer()) {
+		t.Fatal("accepted request did not open the trade")
+	}
+
+	// A trading player cannot open a private store
+	if n, _, _ := h.HandlePacket(ctx, alice, []byte{clientpackets.OpcodeRequestPrivateStoreManageSell}, buf); n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Errorf("store setup while trading must fail, got opcode 0x%02X", buf[0])
+	}
+
+	if n := send(alice, clientpackets.OpcodeAddTradeItem, 0, int32(sword.ObjectID()), 2); n != 1 || buf[0] != serverpackets.OpcodeActionFailed {
+		t.Error("adding more items than the stack holds must fail")
+	}
+	send(alice, clientpackets.OpcodeAddTradeItem, 0, int32(sword.ObjectID()), 1)
+	send(bob, clientpackets.OpcodeAddTradeItem, 0, int32(adena.ObjectID()), 400)
+
+	send(alice, clientpackets.OpcodeTradeDone, 1)
+	if alice.ActivePlayer().Inventory().CountOf(100) != 1 {
+		t.Fatal("items moved before both sides confirmed")
+	}
+	send(bob, clientpackets.OpcodeTradeDone, 1)
+
+	if h.trades.IsTrading(alice.ActivePlayer()) {
+		t.Error("trade still open after both confirmed")
+	}
+	if got := bob.ActivePlayer().Inventory().CountOf(100); got != 1 {
+		t.Errorf("bob swords = %d, want 1", got)
+	}
+	if a, b := alice.ActivePlayer().Inventory().Adena(), bob.ActivePlayer().Inventory().Adena(); a != 400 || b != 600 {
+		t.Errorf("adena alice=%d bob=%d, want 400/600", a, b)
+	}
+
+	// Cancelling and disconnecting close the window of the partner too
+	send(bob, clientpackets.OpcodeTradeRequest, int32(alice.ActivePlayer().ObjectID()))
+	send(alice, clientpackets.OpcodeAnswerTradeRequest, 1)
+	send(alice, clientpackets.OpcodeTradeDone, 0)
+	if h.trades.IsTrading(bob.ActivePlayer()) {
+		t.Error("cancelled trade still open")
+	}
+	send(bob, clientpackets.OpcodeTradeRequest, int32(alice.ActivePlayer().ObjectID()))
+	send(alice, clientpackets.OpcodeAnswerTradeRequest, 1)
+	h.OnDisconnect(alice)
+	if h.trades.IsTrading(bob.ActivePlayer()) {
+		t.Error("trade with a disconnected player still open")
+	}
+}
@@ -0,0 +1,129 @@
+package gameserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// zoneEffectInterval is how often damage zones hurt the players inside.
+const zoneEffectInterval = 3 * time.Second
+
+// Zones returns the zone manager.
+func (h *Handler) Zones() *zone.Manager {
+	return h.zones
+}
+
+// zoneNotifier tells players about combat zones they enter and leave.
+type zoneNotifier struct {
+	h *Handler
+}
+
+// isCombatZone reports whether players fight without karma in zones of type t.
+func isCombatZone(t zone.Type) bool {
+	return t == zone.PvP || t == zone.Siege
+}
+
+func (n zoneNotifier) ZoneEntered(obj *model.WorldObject, z *zone.Zone) {
+	if !isCombatZone(z.Type) {
+		return
+	}
+	// Only the first combat zone counts: arenas may overlap siege fields
+	if p := n.h.findPlayer(obj.ObjectID()); p != nil && n.combatZones(obj) == 1 {
+		n.h.sendToPlayer(p, serverpackets.NewSystemMessage(serverpackets.SystemMessageEnteredCombatZone))
+	}
+}
+
+func (n zoneNotifier) ZoneExited(obj *model.WorldObject, z *zone.Zone) {
+	if !isCombatZone(z.Type) {
+		return
+	}
+	if p := n.h.findPlayer(obj.ObjectID()); p != nil && n.combatZones(obj) == 0 {
+		n.h.sendToPlayer(p, serverpackets.NewSystemMessage(serverpackets.SystemMessageLeftCombatZone))
+	}
+}
+
+func (n zoneNotifier) combatZones(obj *model.WorldObject) int {
+	count := 0
+	for _, z := range n.h.zones.ZonesOf(obj) {
+		if isCombatZone(z.Type) {
+			count++
+		}
+	}
+	return count
+}
+
+// compassCode returns the compass zone code of a player (L2J Player.revalidateZone order).
+func (h *Handler) compassCode(p *model.Player) int32 {
+	switch {
+	case h.zones.InsideZone(p.WorldObject, zone.Siege):
+		return serverpackets.CompassSiegeWarZone2
+	case h.zones.InsideZone(p.WorldObject, zone.PvP):
+		return serverpackets.CompassPvPZone
+	case h.zones.InsideZone(p.WorldObject, zone.Peace):
+		return serverpackets.CompassPeaceZone
+	default:
+		return serverpackets.CompassGeneralZone
+	}
+}
+
+// revalidateZones updates the zones of a player after a move and refreshes its compass
+// when the kind of zone changed.
+func (h *Handler) revalidateZones(p *model.Player) {
+	if !h.zones.Revalidate(p.WorldObject) {
+		return
+	}
+	code := h.compassCode(p)
+	if last, ok := h.compass.Load(p.ObjectID()); ok && last.(int32) == code {
+		return
+	}
+	h.compass.Store(p.ObjectID(), code)
+	h.sendToPlayer(p, &serverpackets.ExSetCompassZoneCode{Code: code})
+}
+
+// forgetZones stops tracking the zones of a player leaving the world.
+func (h *Handler) forgetZones(p *model.Player) {
+	h.zones.Forget(p.ObjectID())
+	h.compass.Delete(p.ObjectID())
+}
+
+// moveSpeedFactor returns the part of normal speed an object moves with: swamps slow it down.
+func (h *Handler) moveSpeedFactor(obj *model.WorldObject) float64 {
+	factor := 1.0
+	for _, z := range h.zones.ZonesOf(obj) {
+		if z.Type == zone.Swamp {
+			factor = min(factor, float64(z.MoveSpeed)/100)
+		}
+	}
+	return factor
+}
+
+// applyZoneEffects hurts the players inside damage zones once.
+// Effect zones wait for the skill system.
+func (h *Handler) applyZoneEffects() {
+	h.zones.ForEachInside(zone.Damage, func(objectID uint32, z *zone.Zone) {
+		p := h.findPlayer(objectID)
+		if p == nil || p.IsDead() {
+			return
+		}
+		h.damagePlayer(p, z.DamageHP, nil)
+	})
+}
+
+// RunZoneEffects applies damage zones every zoneEffectInterval until ctx is cancelled.
+func (h *Handler) RunZoneEffects(ctx context.Context) error {
+	ticker := time.NewTicker(zoneEffectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			h.applyZoneEffects()
+		}
+	}
+}
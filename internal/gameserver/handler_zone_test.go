@@ -0,0 +1,85 @@
+package gameserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/gameserver/clientpackets"
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/zone"
+)
+
+// newZoneHandler creates a handler with an arena east of the test spawn point (17000, 170000),
+// a swamp around it and a damage zone to the north.
+func newZoneHandler(t *testing.T) *Handler {
+	t.Helper()
+	zones := zone.NewManager()
+	for _, z := range []*zone.Zone{
+		{ID: 1, Name: "arena", Type: zone.PvP,
+			Shape: zone.Cuboid{MinX: 17200, MinY: 169800, MinZ: -4000, MaxX: 17600, MaxY: 170200, MaxZ: -3000}},
+		{ID: 2, Name: "swamp", Type: zone.Swamp, MoveSpeed: 50,
+			Shape: zone.Cylinder{X: 17000, Y: 170000, Radius: 100, MinZ: -4000, MaxZ: -3000}},
+		{ID: 3, Name: "fumes", Type: zone.Damage, DamageHP: 30,
+			Shape: zone.Cylinder{X: 17000, Y: 171000, Radius: 200, MinZ: -4000, MaxZ: -3000}},
+	} {
+		if err := zones.Add(z); err != nil {
+			t.Fatalf("Add(%s): %v", z.Name, err)
+		}
+	}
+	return NewHandler(login.NewSessionManager(), WithZones(zones))
+}
+
+func TestHandler_EnterArena(t *testing.T) {
+	h := newZoneHandler(t)
+	client := newInGameClient(t, h, 9901, "Gladiator")
+	player := client.ActivePlayer()
+	h.revalidateZones(player)
+
+	if _, _, err := h.HandlePacket(context.Background(), client,
+		moveRequest(17400, 170000, -3500, clientpackets.MoveByMouse, player.Location()), make([]byte, 1024)); err != nil {
+		t.Fatalf("MoveBackwardToLocation: %v", err)
+	}
+
+	// The swamp halves the speed: 60 units in the first second, then 120 per second
+	h.stepMovers(time.Second)
+	if player.X() != 17060 {
+		t.Fatalf("after 1s in the swamp at %v, want x = 17060", player.Location())
+	}
+	for range 3 {
+		h.stepMovers(time.Second)
+	}
+	if !h.Zones().InsideZone(player.WorldObject, zone.PvP) {
+		t.Fatalf("at %v, want inside the arena", player.Location())
+	}
+	if code, _ := h.compass.Load(player.ObjectID()); code != int32(serverpackets.CompassPvPZone) {
+		t.Errorf("compass code = %v, want PvP zone", code)
+	}
+
+	h.OnDisconnect(client)
+	if h.Zones().InsideZone(player.WorldObject, zone.PvP) {
+		t.Error("disconnected player still tracked in the arena")
+	}
+	if _, ok := h.compass.Load(player.ObjectID()); ok {
+		t.Error("compass code kept after disconnect")
+	}
+}
+
+func TestHandler_DamageZone(t *testing.T) {
+	h := newZoneHandler(t)
+	outside := newInGameClient(t, h, 9902, "Safe").ActivePlayer()
+	inside := newInGameClient(t, h, 9903, "Choking").ActivePlayer()
+	inside.SetLocation(inside.Location().WithCoordinates(17050, 171000, -3500))
+	h.revalidateZones(outside)
+	h.revalidateZones(inside)
+	hp := inside.CurrentHP()
+
+	h.applyZoneEffects()
+	if inside.CurrentHP() != hp-30 {
+		t.Errorf("HP in damage zone = %d, want %d", inside.CurrentHP(), hp-30)
+	}
+	if outside.CurrentHP() != hp {
+		t.Errorf("HP outside = %d, want %d", outside.CurrentHP(), hp)
+	}
+}
@@ -0,0 +1,94 @@
+package gameserver
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// serverPacket is implemented by all packets in serverpackets.
+type serverPacket interface {
+	Write() ([]byte, error)
+}
+
+// writeToBuf serializes pkt into the response buffer.
+// Returns the number of bytes written.
+func writeToBuf(buf []byte, pkt serverPacket) (int, error) {
+	data, err := pkt.Write()
+	if err != nil {
+		return 0, fmt.Errorf("writing %T: %w", pkt, err)
+	}
+	if len(data) > len(buf) {
+		return 0, fmt.Errorf("%T too large: %d bytes, buffer %d", pkt, len(data), len(buf))
+	}
+	return copy(buf, data), nil
+}
+
+// actionFailed writes ActionFailed into buf and keeps the connection open.
+func actionFailed(buf []byte) (int, bool, error) {
+	n, err := writeToBuf(buf, serverpackets.ActionFailed{})
+	return n, true, err
+}
+
+// sendPacket serializes and sends pkt to the client outside the request/response path.
+func sendPacket(client *GameClient, pkt serverPacket) {
+	data, err := pkt.Write()
+	if err != nil {
+		slog.Error("failed to serialize packet", "packet", fmt.Sprintf("%T", pkt), "error", err)
+		return
+	}
+	if err := client.SendPacket(data); err != nil {
+		slog.Debug("failed to send packet", "packet", fmt.Sprintf("%T", pkt), "client", client.IP(), "error", err)
+	}
+}
+
+// broadcastToVisible sends pkt to all players that can see p (excluding p).
+func (h *Handler) broadcastToVisible(p *model.Player, pkt serverPacket) {
+	data, err := pkt.Write()
+	if err != nil {
+		slog.Error("failed to serialize packet", "packet", fmt.Sprintf("%T", pkt), "error", err)
+		return
+	}
+	h.clients.BroadcastToVisible(p, data)
+}
+
+// broadcastAround sends pkt to all players that can see the given location.
+func (h *Handler) broadcastAround(loc model.Location, pkt serverPacket) {
+	data, err := pkt.Write()
+	if err != nil {
+		slog.Error("failed to serialize packet", "packet", fmt.Sprintf("%T", pkt), "error", err)
+		return
+	}
+	world.ForEachVisibleObject(world.Instance(), loc.X, loc.Y, func(obj *model.WorldObject) bool {
+		h.clients.SendTo(obj.ObjectID(), data)
+		return true
+	})
+}
+
+// broadcastFrom sends pkt about p to all players that can see loc;
+// a hidden GM tells only itself.
+func (h *Handler) broadcastFrom(p *model.Player, loc model.Location, pkt serverPacket) {
+	if p.IsInvisible() {
+		h.sendToPlayer(p, pkt)
+		return
+	}
+	h.broadcastAround(loc, pkt)
+}
+
+// broadcastToAll sends pkt to every player in the game.
+func (h *Handler) broadcastToAll(pkt serverPacket) {
+	data, err := pkt.Write()
+	if err != nil {
+		slog.Error("failed to serialize packet", "packet", fmt.Sprintf("%T", pkt), "error", err)
+		return
+	}
+	h.clients.ForEach(func(c *GameClient) bool {
+		if err := c.SendPacket(data); err != nil {
+			slog.Debug("failed to send packet", "packet", fmt.Sprintf("%T", pkt), "client", c.IP(), "error", err)
+		}
+		return true
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/udisondev/la2go/internal/config"
 	"github.com/udisondev/la2go/internal/constants"
+	"github.com/udisondev/la2go/internal/floodprotect"
 	"github.com/udisondev/la2go/internal/gameserver/serverpackets"
 	"github.com/udisondev/la2go/internal/login"
 	"github.com/udisondev/la2go/internal/protocol"
@@ -24,24 +25,34 @@ type Server struct {
 	sendPool *BytePool
 	readPool *BytePool
 	handler  *Handler
+	flood    *floodprotect.Filter // nil = flood protection disabled
 
 	listener net.Listener
 	mu       sync.Mutex
 }
 
 // NewServer creates a new GameServer.
-func NewServer(cfg config.GameServer, sessionManager *login.SessionManager) (*Server, error) {
+// opts configure the packet handler (game services, repositories).
+func NewServer(cfg config.GameServer, sessionManager *login.SessionManager, opts ...Option) (*Server, error) {
 	s := &Server{
 		cfg:            cfg,
 		sessionManager: sessionManager,
 		sendPool:       NewBytePool(constants.DefaultSendBufSize),
 		readPool:       NewBytePool(constants.DefaultReadBufSize),
-		handler:        NewHandler(sessionManager),
+		handler:        NewHandler(sessionManager, opts...),
+	}
+	if cfg.FloodProtection {
+		s.flood = floodprotect.NewFilter(floodprotect.ConfigFrom(cfg.FloodProtect))
 	}
 
 	return s, nil
 }
 
+// Handler returns the packet handler.
+func (s *Server) Handler() *Handler {
+	return s.handler
+}
+
 // generateBlowfishKey creates a fresh 16-byte random Blowfish key.
 func generateBlowfishKey() ([]byte, error) {
 	key := make([]byte, constants.BlowfishKeySize)
@@ -97,6 +108,7 @@ func (s *Server) Run(ctx context.Context) error {
 // Serve accepts connections from the given listener and starts the accept loop.
 // Used for testing with custom listeners.
 func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ln = floodprotect.Listen(ln, s.flood)
 	go func() {
 		<-ctx.Done()
 		ln.Close()
@@ -182,6 +194,8 @@ func handleConnection(ctx context.Context, srv *Server, conn net.Conn) {
 
 	slog.Debug("sent KeyPacket", "client", client.IP())
 
+	defer srv.handler.OnDisconnect(client)
+
 	// Enter packet handling loop (read → decrypt → handle → encrypt → write)
 	for {
 		select {
@@ -221,7 +235,7 @@ func handlePacket(ctx context.Context, srv *Server, client *GameClient) error {
 
 	// Send response if any
 	if n > 0 {
-		if err := protocol.WritePacket(client.Conn(), client.Encryption(), sendBuf, n); err != nil {
+		if err := client.writePacket(sendBuf, n); err != nil {
 			return fmt.Errorf("writing response packet: %w", err)
 		}
 	}
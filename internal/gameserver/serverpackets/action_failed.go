@@ -0,0 +1,21 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeActionFailed = 0x25
+
+// ActionFailed tells the client that the requested action was rejected
+// (unlocks the client UI).
+//
+// Structure:
+// - byte: opcode (0x25)
+type ActionFailed struct{}
+
+// Write serializes the ActionFailed packet.
+func (p ActionFailed) Write() ([]byte, error) {
+	w := packet.NewWriter(1)
+	if err := w.WriteByte(OpcodeActionFailed); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
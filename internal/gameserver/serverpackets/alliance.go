@@ -0,0 +1,162 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodeStartPledgeWar         = 0x65
+	OpcodeStopPledgeWar          = 0x67
+	OpcodeSurrenderPledgeWar     = 0x69
+	OpcodeAskJoinAlly            = 0xA8
+	OpcodeAllyCrest              = 0xAE
+	OpcodeRelationChanged        = 0xCE
+	ExOpcodePledgeReceiveWarList = 0x3E
+)
+
+// Relation flags of RelationChanged (name colour and war icons).
+const (
+	RelationPvPFlag     = 0x00002
+	RelationHasKarma    = 0x00004
+	RelationMutualWar   = 0x08000 // double fist icon
+	RelationOneSidedWar = 0x10000 // single fist icon
+)
+
+// AskJoinAlly asks a clan leader to join an alliance.
+//
+// Structure:
+// - byte: opcode (0xA8)
+// - int32: requester objectID
+// - string: alliance name
+type AskJoinAlly struct {
+	RequesterID uint32
+	AllyName    string
+}
+
+// Write serializes the AskJoinAlly packet.
+func (p *AskJoinAlly) Write() ([]byte, error) {
+	w := packet.NewWriter(5 + (len(p.AllyName)+1)*2)
+	if err := w.WriteByte(OpcodeAskJoinAlly); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.RequesterID))
+	w.WriteString(p.AllyName)
+	return w.Bytes(), nil
+}
+
+// AllyCrest sends alliance crest image data.
+//
+// Structure:
+// - byte: opcode (0xAE)
+// - int32: crest ID
+// - int32: data length
+// - bytes: data
+type AllyCrest struct {
+	CrestID int32
+	Data    []byte
+}
+
+// Write serializes the AllyCrest packet.
+func (p *AllyCrest) Write() ([]byte, error) {
+	w := packet.NewWriter(9 + len(p.Data))
+	if err := w.WriteByte(OpcodeAllyCrest); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.CrestID)
+	w.WriteInt(int32(len(p.Data)))
+	w.WriteBytes(p.Data)
+	return w.Bytes(), nil
+}
+
+// PledgeWarNotice notifies about a clan war state change. The opcode selects the notice:
+// StartPledgeWar (0x65) asks the enemy leader to accept a declaration,
+// StopPledgeWar (0x67) and SurrenderPledgeWar (0x69) announce the end of a war.
+//
+// Structure:
+// - byte: opcode
+// - string: clan name
+// - string: player name
+type PledgeWarNotice struct {
+	Opcode     byte
+	PledgeName string
+	CharName   string
+}
+
+// Write serializes the war notice packet.
+func (p *PledgeWarNotice) Write() ([]byte, error) {
+	w := packet.NewWriter(1 + (len(p.PledgeName)+len(p.CharName)+2)*2)
+	if err := w.WriteByte(p.Opcode); err != nil {
+		return nil, err
+	}
+	// StartPledgeWar has the player name first
+	if p.Opcode == OpcodeStartPledgeWar {
+		w.WriteString(p.CharName)
+		w.WriteString(p.PledgeName)
+	} else {
+		w.WriteString(p.PledgeName)
+		w.WriteString(p.CharName)
+	}
+	return w.Bytes(), nil
+}
+
+// PledgeReceiveWarList sends one tab of the clan war list.
+//
+// Structure:
+// - byte: opcode (0xFE), int16: sub-opcode (0x3E)
+// - int32: tab (0 = declared, 1 = under attack)
+// - int32: page
+// - int32: count
+// - for each clan: string name, int32 tab, int32 tab
+type PledgeReceiveWarList struct {
+	Tab   int32
+	Page  int32
+	Clans []string
+}
+
+// Write serializes the PledgeReceiveWarList packet.
+func (p *PledgeReceiveWarList) Write() ([]byte, error) {
+	w := packet.NewWriter(15 + len(p.Clans)*48)
+	if err := w.WriteByte(OpcodeExtended); err != nil {
+		return nil, err
+	}
+	w.WriteShort(ExOpcodePledgeReceiveWarList)
+	w.WriteInt(p.Tab)
+	w.WriteInt(p.Page)
+	w.WriteInt(int32(len(p.Clans)))
+	for _, name := range p.Clans {
+		w.WriteString(name)
+		w.WriteInt(p.Tab)
+		w.WriteInt(p.Tab)
+	}
+	return w.Bytes(), nil
+}
+
+// RelationChanged tells a viewer how a player relates to them
+// (war icons, name colour, whether attacking is free of karma).
+//
+// Structure:
+// - byte: opcode (0xCE)
+// - int32: objectID
+// - int32: relation flags
+// - int32: auto-attackable
+// - int32: karma
+// - int32: pvp flag
+type RelationChanged struct {
+	ObjectID       uint32
+	Relation       int32
+	AutoAttackable bool
+	Karma          int32
+	PvPFlag        bool
+}
+
+// Write serializes the RelationChanged packet.
+func (p *RelationChanged) Write() ([]byte, error) {
+	w := packet.NewWriter(21)
+	if err := w.WriteByte(OpcodeRelationChanged); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(p.Relation)
+	w.WriteInt(boolToInt(p.AutoAttackable))
+	w.WriteInt(p.Karma)
+	w.WriteInt(boolToInt(p.PvPFlag))
+	return w.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestPledgeReceiveWarList_Write(t *testing.T) {
+	data, err := (&PledgeReceiveWarList{Tab: 1, Clans: []string{"Enemies"}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeExtended, data)
+	if data[1] != ExOpcodePledgeReceiveWarList {
+		t.Errorf("sub-opcode = 0x%02X", data[1])
+	}
+	testutil.AssertInt32LE(t, 1, data, 3)
+	testutil.AssertInt32LE(t, 0, data, 7)
+	testutil.AssertInt32LE(t, 1, data, 11)
+	testutil.AssertUTF16String(t, "Enemies", data, 15)
+	testutil.AssertInt32LE(t, 1, data, 15+(len("Enemies")+1)*2)
+}
+
+func TestRelationChanged_Write(t *testing.T) {
+	pkt := &RelationChanged{
+		ObjectID:       42,
+		Relation:       RelationOneSidedWar | RelationMutualWar,
+		AutoAttackable: true,
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeRelationChanged, data)
+	testutil.AssertPacketLength(t, 21, data)
+	testutil.AssertInt32LE(t, 42, data, 1)
+	testutil.AssertInt32LE(t, RelationOneSidedWar|RelationMutualWar, data, 5)
+	testutil.AssertInt32LE(t, 1, data, 9)
+}
+
+func TestPledgeWarNotice_Write(t *testing.T) {
+	data, err := (&PledgeWarNotice{Opcode: OpcodeStartPledgeWar, PledgeName: "Alpha", CharName: "Leader"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeStartPledgeWar, data)
+	testutil.AssertUTF16String(t, "Leader", data, 1)
+	testutil.AssertUTF16String(t, "Alpha", data, 1+(len("Leader")+1)*2)
+
+	data, err = (&PledgeWarNotice{Opcode: OpcodeSurrenderPledgeWar, PledgeName: "Alpha", CharName: "Leader"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertUTF16String(t, "Alpha", data, 1)
+}
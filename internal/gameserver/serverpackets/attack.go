@@ -0,0 +1,69 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeAttack = 0x05
+
+// Hit flags of Attack.
+const (
+	HitFlagSoulshot = 0x10
+	HitFlagCritical = 0x20
+	HitFlagShield   = 0x40
+	HitFlagMiss     = 0x80
+)
+
+// Hit is one target of an attack.
+type Hit struct {
+	TargetID uint32
+	Damage   int32
+	Flags    byte
+}
+
+// Attack shows a physical attack: the attacker swings at one or more targets (pole weapons).
+//
+// Structure:
+// - byte: opcode (0x05)
+// - int32: attacker objectID
+// - int32, int32, byte: first hit (target objectID, damage, flags)
+// - int32: attacker x, y, z
+// - int16: number of further hits
+// - further hits: int32 target objectID, int32 damage, byte flags
+type Attack struct {
+	AttackerID uint32
+	Loc        model.Location
+	Hits       []Hit
+}
+
+// Write serializes the Attack packet.
+func (p *Attack) Write() ([]byte, error) {
+	w := packet.NewWriter(27 + len(p.Hits)*9)
+	if err := w.WriteByte(OpcodeAttack); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.AttackerID))
+	writeHit := func(h Hit) error {
+		w.WriteInt(int32(h.TargetID))
+		w.WriteInt(h.Damage)
+		return w.WriteByte(h.Flags)
+	}
+	var first Hit
+	if len(p.Hits) > 0 {
+		first = p.Hits[0]
+	}
+	if err := writeHit(first); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.Loc.X)
+	w.WriteInt(p.Loc.Y)
+	w.WriteInt(p.Loc.Z)
+	w.WriteShort(int16(max(len(p.Hits)-1, 0)))
+	for i := 1; i < len(p.Hits); i++ {
+		if err := writeHit(p.Hits[i]); err != nil {
+			return nil, err
+		}
+	}
+	return w.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package serverpackets
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestAttack_Write(t *testing.T) {
+	pkt := &Attack{
+		AttackerID: 300001,
+		Loc:        model.NewLocation(17000, 170000, -3500, 0),
+		Hits:       []Hit{{TargetID: 100001, Damage: 87}},
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeAttack, data)
+	testutil.AssertPacketLength(t, 28, data)
+	testutil.AssertInt32LE(t, 300001, data, 1)
+	testutil.AssertInt32LE(t, 100001, data, 5)
+	testutil.AssertInt32LE(t, 87, data, 9)
+	testutil.AssertByteAtOffset(t, 0, data, 13)
+	testutil.AssertInt32LE(t, 17000, data, 14)
+	testutil.AssertInt32LE(t, 170000, data, 18)
+	testutil.AssertInt32LE(t, -3500, data, 22)
+	if n := binary.LittleEndian.Uint16(data[26:]); n != 0 {
+		t.Errorf("extra hits = %d, want 0", n)
+	}
+}
+
+func TestAttack_WriteSeveralHits(t *testing.T) {
+	pkt := &Attack{
+		AttackerID: 300001,
+		Hits: []Hit{
+			{TargetID: 100001, Damage: 87},
+			{TargetID: 100002, Flags: HitFlagMiss},
+		},
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketLength(t, 37, data)
+	if n := binary.LittleEndian.Uint16(data[26:]); n != 1 {
+		t.Errorf("extra hits = %d, want 1", n)
+	}
+	testutil.AssertInt32LE(t, 100002, data, 28)
+	testutil.AssertInt32LE(t, 0, data, 32)
+	testutil.AssertByteAtOffset(t, HitFlagMiss, data, 36)
+}
+
+func TestStatusUpdate_Write(t *testing.T) {
+	pkt := &StatusUpdate{
+		ObjectID: 100001,
+		Attrs: []StatusAttr{
+			{ID: StatusCurHP, Value: 413},
+			{ID: StatusMaxHP, Value: 500},
+		},
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeStatusUpdate, data)
+	testutil.AssertPacketLength(t, 25, data)
+	for i, want := range []int32{100001, 2, StatusCurHP, 413, StatusMaxHP, 500} {
+		testutil.AssertInt32LE(t, want, data, 1+i*4)
+	}
+}
@@ -0,0 +1,54 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeChangeWaitType = 0x2F
+
+// Wait types for ChangeWaitType.
+const (
+	WaitTypeSitting  = 0
+	WaitTypeStanding = 1
+)
+
+// ChangeWaitType broadcasts sit/stand of a player.
+//
+// Structure:
+// - byte: opcode (0x2F)
+// - int32: objectID
+// - int32: wait type (0 = sitting, 1 = standing)
+// - int32: x, y, z
+type ChangeWaitType struct {
+	ObjectID uint32
+	WaitType int32
+	Loc      model.Location
+}
+
+// NewChangeWaitType creates the packet for the player's current sitting state.
+func NewChangeWaitType(p *model.Player) *ChangeWaitType {
+	waitType := int32(WaitTypeStanding)
+	if p.IsSitting() {
+		waitType = WaitTypeSitting
+	}
+	return &ChangeWaitType{
+		ObjectID: p.ObjectID(),
+		WaitType: waitType,
+		Loc:      p.Location(),
+	}
+}
+
+// Write serializes the ChangeWaitType packet.
+func (p *ChangeWaitType) Write() ([]byte, error) {
+	w := packet.NewWriter(21)
+	if err := w.WriteByte(OpcodeChangeWaitType); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(p.WaitType)
+	w.WriteInt(p.Loc.X)
+	w.WriteInt(p.Loc.Y)
+	w.WriteInt(p.Loc.Z)
+	return w.Bytes(), nil
+}
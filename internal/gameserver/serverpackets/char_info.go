@@ -0,0 +1,229 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeCharInfo = 0x03
+
+// Paperdoll slots (Interlude inventory layout).
+const (
+	PaperdollUnder   = 0
+	PaperdollLEar    = 1
+	PaperdollREar    = 2
+	PaperdollNeck    = 3
+	PaperdollLFinger = 4
+	PaperdollRFinger = 5
+	PaperdollHead    = 6
+	PaperdollRHand   = 7
+	PaperdollLHand   = 8
+	PaperdollGloves  = 9
+	PaperdollChest   = 10
+	PaperdollLegs    = 11
+	PaperdollFeet    = 12
+	PaperdollBack    = 13
+	PaperdollLRHand  = 14
+	PaperdollFace    = 15
+	PaperdollHair    = 16
+	PaperdollDHair   = 17
+)
+
+// charInfoPaperdoll is the order of paperdoll slots in CharInfo.
+var charInfoPaperdoll = [...]int32{
+	PaperdollDHair, PaperdollHead, PaperdollRHand, PaperdollLHand,
+	PaperdollGloves, PaperdollChest, PaperdollLegs, PaperdollFeet,
+	PaperdollBack, PaperdollLRHand, PaperdollHair, PaperdollFace,
+}
+
+// userPaperdoll is the order of paperdoll slots in CharSelectionInfo and UserInfo.
+var userPaperdoll = [...]int32{
+	PaperdollUnder, PaperdollREar, PaperdollLEar, PaperdollNeck, PaperdollRFinger,
+	PaperdollLFinger, PaperdollHead, PaperdollRHand, PaperdollLHand, PaperdollGloves,
+	PaperdollChest, PaperdollLegs, PaperdollFeet, PaperdollBack, PaperdollLRHand,
+	PaperdollHair, PaperdollFace,
+}
+
+// Movement defaults until character stats are implemented.
+const (
+	defaultRunSpeed        = 126
+	defaultWalkSpeed       = 88
+	defaultCollisionRadius = 9.0
+	defaultCollisionHeight = 23.0
+	defaultNameColor       = 0xFFFFFF
+	defaultTitleColor      = 0xFFFF77
+)
+
+// CharInfo describes another player to clients that see them.
+// Clan and alliance fields come from the clan subsystem.
+//
+// Structure (Interlude):
+//   - byte: opcode (0x03)
+//   - int32: x, y, z, heading, objectID
+//   - string: name
+//   - int32: race, sex, class ID
+//   - int32 x12: paperdoll item IDs
+//   - augmentation block (shorts and ints)
+//   - int32: pvp flag, karma, casting/attack speed, pvp flag, karma
+//   - int32 x8: movement speeds
+//   - double: move multiplier, attack speed multiplier, collision radius, collision height
+//   - int32: hair style, hair color, face
+//   - string: title
+//   - int32: clan ID, clan crest ID, ally ID, ally crest ID, relation
+//   - byte: standing, running, in combat, dead, invisible, mount type, private store type
+//   - int16: cubic count, byte: in party match room
+//   - int32: abnormal effect, byte: recommendations left, int16: recommendations
+//   - int32: class ID, max CP, current CP
+//   - byte: enchant, team
+//   - int32: large crest ID
+//   - byte: noble, hero, fishing; int32 x3: fishing location
+//   - int32: name color, heading, pledge class, pledge type, title color, cursed weapon level
+type CharInfo struct {
+	Player       *model.Player
+	Title        string
+	ClanCrestID  int32
+	LargeCrestID int32
+	AllyID       int32
+	AllyCrestID  int32
+	PledgeClass  int32
+}
+
+// Write serializes the CharInfo packet.
+func (p *CharInfo) Write() ([]byte, error) {
+	pl := p.Player
+	loc := pl.Location()
+	w := packet.NewWriter(320 + (len(pl.Name())+len(p.Title)+2)*2)
+	if err := w.WriteByte(OpcodeCharInfo); err != nil {
+		return nil, err
+	}
+
+	w.WriteInt(loc.X)
+	w.WriteInt(loc.Y)
+	w.WriteInt(loc.Z)
+	w.WriteInt(int32(loc.Heading))
+	w.WriteInt(int32(pl.ObjectID()))
+	w.WriteString(pl.Name())
+	w.WriteInt(pl.RaceID())
+	w.WriteInt(0) // sex
+	w.WriteInt(pl.ClassID())
+
+	paperdoll := equippedItems(pl)
+	for _, slot := range charInfoPaperdoll {
+		w.WriteInt(paperdoll[slot])
+	}
+
+	// Augmentation block: only the weapon slots carry augmentation IDs.
+	for range 4 {
+		w.WriteShort(0)
+	}
+	w.WriteInt(0) // right hand augmentation
+	for range 12 {
+		w.WriteShort(0)
+	}
+	w.WriteInt(0) // two-hand augmentation
+	for range 4 {
+		w.WriteShort(0)
+	}
+
+	pvpFlag := boolToInt(pl.IsPvPFlagged())
+	w.WriteInt(pvpFlag)
+	w.WriteInt(pl.Karma())
+	w.WriteInt(0) // casting speed
+	w.WriteInt(0) // attack speed
+	w.WriteInt(pvpFlag)
+	w.WriteInt(pl.Karma())
+
+	for range 4 {
+		w.WriteInt(defaultRunSpeed)
+		w.WriteInt(defaultWalkSpeed)
+	}
+	w.WriteDouble(1.0) // move multiplier
+	w.WriteDouble(1.0) // attack speed multiplier
+	w.WriteDouble(defaultCollisionRadius)
+	w.WriteDouble(defaultCollisionHeight)
+
+	w.WriteInt(0) // hair style
+	w.WriteInt(0) // hair color
+	w.WriteInt(0) // face
+	w.WriteString(p.Title)
+
+	w.WriteInt(pl.ClanID())
+	w.WriteInt(p.ClanCrestID)
+	w.WriteInt(p.AllyID)
+	w.WriteInt(p.AllyCrestID)
+	w.WriteInt(0) // relation
+
+	w.WriteBytes([]byte{
+		byte(boolToInt(!pl.IsSitting())),
+		1, // running
+		0, // in combat
+		byte(boolToInt(pl.IsDead())),
+		byte(boolToInt(pl.IsInvisible())),
+		0, // mount type
+		byte(pl.PrivateStoreType()),
+	})
+	w.WriteShort(0)         // cubics
+	w.WriteBytes([]byte{0}) // in party match room
+
+	w.WriteInt(0)           // abnormal effect
+	w.WriteBytes([]byte{0}) // recommendations left
+	w.WriteShort(0)         // recommendations have
+	w.WriteInt(pl.ClassID())
+	w.WriteInt(pl.MaxCP())
+	w.WriteInt(pl.CurrentCP())
+
+	w.WriteBytes([]byte{0, 0}) // enchant, team
+	w.WriteInt(p.LargeCrestID)
+	w.WriteBytes([]byte{0, 0, 0}) // noble, hero, fishing
+	w.WriteInt(0)                 // fish x
+	w.WriteInt(0)                 // fish y
+	w.WriteInt(0)                 // fish z
+
+	w.WriteInt(defaultNameColor)
+	w.WriteInt(int32(loc.Heading))
+	w.WriteInt(p.PledgeClass)
+	w.WriteInt(pl.PledgeType())
+	w.WriteInt(defaultTitleColor)
+	w.WriteInt(0) // cursed weapon level
+
+	return w.Bytes(), nil
+}
+
+// writeUserPaperdoll writes object IDs, then item type IDs of the items equipped
+// in userPaperdoll slots.
+func writeUserPaperdoll(w *packet.Writer, pl *model.Player) {
+	equipped := make(map[int32]*model.Item)
+	for _, item := range pl.Inventory().Items() {
+		if item.IsEquipped() {
+			_, slot := item.Location()
+			equipped[slot] = item
+		}
+	}
+	for _, slot := range userPaperdoll {
+		var objectID uint32
+		if item := equipped[slot]; item != nil {
+			objectID = item.ObjectID()
+		}
+		w.WriteInt(int32(objectID))
+	}
+	for _, slot := range userPaperdoll {
+		var itemType int32
+		if item := equipped[slot]; item != nil {
+			itemType = item.ItemType()
+		}
+		w.WriteInt(itemType)
+	}
+}
+
+// equippedItems returns item type IDs of equipped items by paperdoll slot.
+func equippedItems(pl *model.Player) map[int32]int32 {
+	out := make(map[int32]int32)
+	for _, item := range pl.Inventory().Items() {
+		if !item.IsEquipped() {
+			continue
+		}
+		_, slot := item.Location()
+		out[slot] = item.ItemType()
+	}
+	return out
+}
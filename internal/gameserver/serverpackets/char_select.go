@@ -0,0 +1,146 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	OpcodeCharSelectionInfo = 0x13
+	OpcodeCharSelected      = 0x15
+)
+
+// CharSelectionInfo lists the characters of an account on the selection screen.
+// The character played last is preselected.
+//
+// Structure (Interlude):
+//   - byte: opcode (0x13)
+//   - int32: character count
+//   - for each character:
+//   - string: name; int32: objectID; string: account; int32: session ID, clan ID, builder level
+//   - int32: sex, race, base class, active
+//   - int32: x, y, z; double: current HP, current MP
+//   - int32: SP; int64: exp; int32: level, karma, PK kills, PvP kills; int32 x7: unknown
+//   - int32 x17: paperdoll object IDs; int32 x17: paperdoll item IDs
+//   - int32: hair style, hair color, face; double: max HP, max MP
+//   - int32: seconds until deletion, class ID, last used; byte: enchant; int32: augmentation
+type CharSelectionInfo struct {
+	Account    string
+	SessionID  int32 // play OK ID1 of the session key
+	Characters []*model.Player
+}
+
+// Write serializes the CharSelectionInfo packet.
+func (p *CharSelectionInfo) Write() ([]byte, error) {
+	w := packet.NewWriter(5 + len(p.Characters)*(300+(len(p.Account)+16)*2))
+	if err := w.WriteByte(OpcodeCharSelectionInfo); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(len(p.Characters)))
+
+	lastUsed := -1
+	for i, pl := range p.Characters {
+		if lastUsed < 0 || pl.LastLogin().After(p.Characters[lastUsed].LastLogin()) {
+			lastUsed = i
+		}
+	}
+
+	for i, pl := range p.Characters {
+		loc := pl.Location()
+		w.WriteString(pl.Name())
+		w.WriteInt(int32(pl.ObjectID()))
+		w.WriteString(p.Account)
+		w.WriteInt(p.SessionID)
+		w.WriteInt(pl.ClanID())
+		w.WriteInt(0) // builder level
+		w.WriteInt(0) // sex
+		w.WriteInt(pl.RaceID())
+		w.WriteInt(pl.ClassID()) // base class
+		w.WriteInt(1)            // active
+		w.WriteInt(loc.X)
+		w.WriteInt(loc.Y)
+		w.WriteInt(loc.Z)
+		w.WriteDouble(float64(pl.CurrentHP()))
+		w.WriteDouble(float64(pl.CurrentMP()))
+		w.WriteInt(int32(pl.SP()))
+		w.WriteLong(pl.Experience())
+		w.WriteInt(pl.Level())
+		w.WriteInt(pl.Karma())
+		w.WriteInt(pl.PKKills())
+		w.WriteInt(pl.PvPKills())
+		for range 7 {
+			w.WriteInt(0)
+		}
+		writeUserPaperdoll(w, pl)
+		w.WriteInt(0) // hair style
+		w.WriteInt(0) // hair color
+		w.WriteInt(0) // face
+		w.WriteDouble(float64(pl.MaxHP()))
+		w.WriteDouble(float64(pl.MaxMP()))
+		w.WriteInt(0) // seconds until deletion
+		w.WriteInt(pl.ClassID())
+		w.WriteInt(boolToInt(i == lastUsed))
+		w.WriteBytes([]byte{0}) // enchant
+		w.WriteInt(0)           // augmentation
+	}
+	return w.Bytes(), nil
+}
+
+// CharSelected confirms the selected character; the client answers with EnterWorld.
+//
+// Structure (Interlude):
+//   - byte: opcode (0x15)
+//   - string: name; int32: objectID; string: title; int32: session ID, clan ID, builder level
+//   - int32: sex, race, class ID, active, x, y, z
+//   - double: current HP, current MP; int32: SP; int64: exp; int32: level, karma, PK kills
+//   - int32: INT, STR, CON, MEN, DEX, WIT
+//   - int32 x32: unknown; int32: game time, unknown, class ID; int32 x4: unknown
+type CharSelected struct {
+	Player    *model.Player
+	Title     string
+	SessionID int32 // play OK ID1 of the session key
+}
+
+// Write serializes the CharSelected packet.
+func (p *CharSelected) Write() ([]byte, error) {
+	pl := p.Player
+	loc := pl.Location()
+	w := packet.NewWriter(320 + (len(pl.Name())+len(p.Title)+2)*2)
+	if err := w.WriteByte(OpcodeCharSelected); err != nil {
+		return nil, err
+	}
+
+	w.WriteString(pl.Name())
+	w.WriteInt(int32(pl.ObjectID()))
+	w.WriteString(p.Title)
+	w.WriteInt(p.SessionID)
+	w.WriteInt(pl.ClanID())
+	w.WriteInt(0) // builder level
+	w.WriteInt(0) // sex
+	w.WriteInt(pl.RaceID())
+	w.WriteInt(pl.ClassID())
+	w.WriteInt(1) // active
+	w.WriteInt(loc.X)
+	w.WriteInt(loc.Y)
+	w.WriteInt(loc.Z)
+	w.WriteDouble(float64(pl.CurrentHP()))
+	w.WriteDouble(float64(pl.CurrentMP()))
+	w.WriteInt(int32(pl.SP()))
+	w.WriteLong(pl.Experience())
+	w.WriteInt(pl.Level())
+	w.WriteInt(pl.Karma())
+	w.WriteInt(pl.PKKills())
+	for range 6 {
+		w.WriteInt(0) // base stats are not modelled yet
+	}
+	for range 32 {
+		w.WriteInt(0)
+	}
+	w.WriteInt(0) // game time: there is no day cycle yet
+	w.WriteInt(0)
+	w.WriteInt(pl.ClassID())
+	for range 4 {
+		w.WriteInt(0)
+	}
+	return w.Bytes(), nil
+}
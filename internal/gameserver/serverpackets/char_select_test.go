@@ -0,0 +1,73 @@
+package serverpackets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func newSelectPlayer(t *testing.T, id int64, name string, lastLogin time.Time) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, name, 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLastLogin(lastLogin)
+	return p
+}
+
+func TestCharSelectionInfo_Write(t *testing.T) {
+	now := time.Now()
+	older := newSelectPlayer(t, 100001, "Ann", now.Add(-time.Hour))
+	last := newSelectPlayer(t, 100002, "Bob", now)
+
+	data, err := (&CharSelectionInfo{Account: "acc", SessionID: 77, Characters: []*model.Player{older, last}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeCharSelectionInfo, data)
+	testutil.AssertInt32LE(t, 2, data, 1)
+	testutil.AssertUTF16String(t, "Ann", data, 5)
+	testutil.AssertInt32LE(t, 100001, data, 13)
+	testutil.AssertUTF16String(t, "acc", data, 17)
+	testutil.AssertInt32LE(t, 77, data, 25)
+	// The character played last is preselected: "last used" precedes enchant and augmentation.
+	testutil.AssertInt32LE(t, 1, data, len(data)-9)
+}
+
+func TestCharSelectionInfo_Empty(t *testing.T) {
+	data, err := (&CharSelectionInfo{Account: "acc"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketLength(t, 5, data)
+	testutil.AssertInt32LE(t, 0, data, 1)
+}
+
+func TestCharSelected_Write(t *testing.T) {
+	p := newSelectPlayer(t, 100001, "Ann", time.Now())
+	data, err := (&CharSelected{Player: p, SessionID: 77}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeCharSelected, data)
+	testutil.AssertUTF16String(t, "Ann", data, 1)
+	testutil.AssertInt32LE(t, 100001, data, 9)
+	testutil.AssertInt32LE(t, 77, data, 15)
+}
+
+func TestUserInfo_Write(t *testing.T) {
+	p := newSelectPlayer(t, 100001, "Ann", time.Now())
+	p.SetLocation(model.NewLocation(17000, 170000, -3500, 0))
+	data, err := (&UserInfo{CharInfo: CharInfo{Player: p}, GM: true}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeUserInfo, data)
+	testutil.AssertInt32LE(t, 17000, data, 1)
+	testutil.AssertInt32LE(t, 100001, data, 17)
+	testutil.AssertUTF16String(t, "Ann", data, 21)
+	testutil.AssertInt32LE(t, 20, data, 41)
+}
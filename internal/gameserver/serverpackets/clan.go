@@ -0,0 +1,350 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodeManagePledgePower             = 0x30
+	OpcodeAskJoinPledge                 = 0x32
+	OpcodeJoinPledge                    = 0x33
+	OpcodePledgeShowMemberListAll       = 0x53
+	OpcodePledgeShowMemberListUpdate    = 0x54
+	OpcodePledgeShowMemberListAdd       = 0x55
+	OpcodePledgeShowMemberListDelete    = 0x56
+	OpcodePledgeCrest                   = 0x6C
+	OpcodePledgeShowMemberListDeleteAll = 0x82
+	OpcodePledgeInfo                    = 0x83
+	OpcodePledgeShowInfoUpdate          = 0x88
+	OpcodeExtended                      = 0xFE
+	ExOpcodePledgeCrestLarge            = 0x1B
+)
+
+// ClanInfo is the clan header shared by pledge packets.
+type ClanInfo struct {
+	ClanID      int32
+	Name        string
+	LeaderName  string
+	CrestID     int32
+	Level       int32
+	Reputation  int32
+	AllyID      int32
+	AllyName    string
+	AllyCrestID int32
+	AtWar       bool
+}
+
+// PledgeMember is a clan member entry in the member list.
+type PledgeMember struct {
+	Name       string
+	Level      int32
+	ClassID    int32
+	Sex        int32
+	Race       int32
+	ObjectID   uint32 // 0 = offline
+	PledgeType int32
+	Sponsor    bool
+}
+
+// AskJoinPledge asks the target to join a clan.
+//
+// Structure:
+// - byte: opcode (0x32)
+// - int32: requester objectID
+// - string: clan name
+type AskJoinPledge struct {
+	RequesterID uint32
+	ClanName    string
+}
+
+// Write serializes the AskJoinPledge packet.
+func (p *AskJoinPledge) Write() ([]byte, error) {
+	w := packet.NewWriter(5 + (len(p.ClanName)+1)*2)
+	if err := w.WriteByte(OpcodeAskJoinPledge); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.RequesterID))
+	w.WriteString(p.ClanName)
+	return w.Bytes(), nil
+}
+
+// JoinPledge confirms the new member has joined the clan.
+//
+// Structure:
+// - byte: opcode (0x33)
+// - int32: clan ID
+type JoinPledge struct {
+	ClanID int32
+}
+
+// Write serializes the JoinPledge packet.
+func (p *JoinPledge) Write() ([]byte, error) {
+	w := packet.NewWriter(5)
+	if err := w.WriteByte(OpcodeJoinPledge); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.ClanID)
+	return w.Bytes(), nil
+}
+
+// writeClanStatus writes the clan status block shared by
+// PledgeShowMemberListAll and PledgeShowInfoUpdate.
+func writeClanStatus(w *packet.Writer, c *ClanInfo) {
+	w.WriteInt(c.Level)
+	w.WriteInt(0) // castle ID
+	w.WriteInt(0) // clan hall ID
+	w.WriteInt(0) // rank
+	w.WriteInt(c.Reputation)
+	w.WriteInt(0)
+	w.WriteInt(0)
+	w.WriteInt(c.AllyID)
+	w.WriteString(c.AllyName)
+	w.WriteInt(c.AllyCrestID)
+	w.WriteInt(boolToInt(c.AtWar))
+}
+
+// PledgeShowMemberListAll sends the member list of the main clan or a sub-pledge.
+//
+// Structure:
+//   - byte: opcode (0x53)
+//   - int32: 0 for main clan, 1 for sub-pledge
+//   - int32: clan ID
+//   - int32: pledge type
+//   - string: pledge name, string: pledge leader name
+//   - int32: crest ID, clan status block (level, castle, hall, rank, reputation, ally, war)
+//   - int32: member count
+//   - for each member: string name, int32 level, class ID, sex, race,
+//     objectID (0 = offline), sponsor flag
+type PledgeShowMemberListAll struct {
+	Clan       ClanInfo
+	PledgeType int32
+	PledgeName string
+	LeaderName string
+	Members    []PledgeMember
+}
+
+// Write serializes the PledgeShowMemberListAll packet.
+func (p *PledgeShowMemberListAll) Write() ([]byte, error) {
+	w := packet.NewWriter(128 + len(p.Members)*64)
+	if err := w.WriteByte(OpcodePledgeShowMemberListAll); err != nil {
+		return nil, err
+	}
+	w.WriteInt(boolToInt(p.PledgeType != 0))
+	w.WriteInt(p.Clan.ClanID)
+	w.WriteInt(p.PledgeType)
+	w.WriteString(p.PledgeName)
+	w.WriteString(p.LeaderName)
+	w.WriteInt(p.Clan.CrestID)
+	writeClanStatus(w, &p.Clan)
+	w.WriteInt(int32(len(p.Members)))
+	for _, m := range p.Members {
+		w.WriteString(m.Name)
+		w.WriteInt(m.Level)
+		w.WriteInt(m.ClassID)
+		w.WriteInt(m.Sex)
+		w.WriteInt(m.Race)
+		w.WriteInt(int32(m.ObjectID))
+		w.WriteInt(boolToInt(m.Sponsor))
+	}
+	return w.Bytes(), nil
+}
+
+// PledgeShowMemberListAdd adds a member to the clan window.
+//
+// Structure:
+// - byte: opcode (0x55)
+// - string: name, int32: level, class ID, 0, 1, objectID (0 = offline), pledge type
+type PledgeShowMemberListAdd struct {
+	Member PledgeMember
+}
+
+// Write serializes the PledgeShowMemberListAdd packet.
+func (p *PledgeShowMemberListAdd) Write() ([]byte, error) {
+	w := packet.NewWriter(32 + (len(p.Member.Name)+1)*2)
+	if err := w.WriteByte(OpcodePledgeShowMemberListAdd); err != nil {
+		return nil, err
+	}
+	w.WriteString(p.Member.Name)
+	w.WriteInt(p.Member.Level)
+	w.WriteInt(p.Member.ClassID)
+	w.WriteInt(0)
+	w.WriteInt(1)
+	w.WriteInt(int32(p.Member.ObjectID))
+	w.WriteInt(p.Member.PledgeType)
+	return w.Bytes(), nil
+}
+
+// PledgeShowMemberListUpdate updates a member entry (online status, rank, level).
+//
+// Structure:
+//   - byte: opcode (0x54)
+//   - string: name, int32: level, class ID, sex, race, objectID (0 = offline),
+//     pledge type, sponsor flag
+type PledgeShowMemberListUpdate struct {
+	Member PledgeMember
+}
+
+// Write serializes the PledgeShowMemberListUpdate packet.
+func (p *PledgeShowMemberListUpdate) Write() ([]byte, error) {
+	w := packet.NewWriter(40 + (len(p.Member.Name)+1)*2)
+	if err := w.WriteByte(OpcodePledgeShowMemberListUpdate); err != nil {
+		return nil, err
+	}
+	w.WriteString(p.Member.Name)
+	w.WriteInt(p.Member.Level)
+	w.WriteInt(p.Member.ClassID)
+	w.WriteInt(p.Member.Sex)
+	w.WriteInt(p.Member.Race)
+	w.WriteInt(int32(p.Member.ObjectID))
+	w.WriteInt(p.Member.PledgeType)
+	w.WriteInt(boolToInt(p.Member.Sponsor))
+	return w.Bytes(), nil
+}
+
+// PledgeShowMemberListDelete removes a member from the clan window.
+//
+// Structure:
+// - byte: opcode (0x56)
+// - string: member name
+type PledgeShowMemberListDelete struct {
+	Name string
+}
+
+// Write serializes the PledgeShowMemberListDelete packet.
+func (p *PledgeShowMemberListDelete) Write() ([]byte, error) {
+	w := packet.NewWriter(1 + (len(p.Name)+1)*2)
+	if err := w.WriteByte(OpcodePledgeShowMemberListDelete); err != nil {
+		return nil, err
+	}
+	w.WriteString(p.Name)
+	return w.Bytes(), nil
+}
+
+// PledgeShowMemberListDeleteAll clears the clan window (left, expelled or clan dissolved).
+//
+// Structure:
+// - byte: opcode (0x82)
+type PledgeShowMemberListDeleteAll struct{}
+
+// Write serializes the PledgeShowMemberListDeleteAll packet.
+func (p PledgeShowMemberListDeleteAll) Write() ([]byte, error) {
+	return []byte{OpcodePledgeShowMemberListDeleteAll}, nil
+}
+
+// PledgeShowInfoUpdate updates clan level, crest and reputation in the clan window.
+//
+// Structure:
+// - byte: opcode (0x88)
+// - int32: clan ID, int32: crest ID, clan status block
+type PledgeShowInfoUpdate struct {
+	Clan ClanInfo
+}
+
+// Write serializes the PledgeShowInfoUpdate packet.
+func (p *PledgeShowInfoUpdate) Write() ([]byte, error) {
+	w := packet.NewWriter(64 + (len(p.Clan.AllyName)+1)*2)
+	if err := w.WriteByte(OpcodePledgeShowInfoUpdate); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.Clan.ClanID)
+	w.WriteInt(p.Clan.CrestID)
+	writeClanStatus(w, &p.Clan)
+	return w.Bytes(), nil
+}
+
+// PledgeInfo answers RequestPledgeInfo with the clan name.
+//
+// Structure:
+// - byte: opcode (0x83)
+// - int32: clan ID
+// - string: clan name
+// - string: alliance name
+type PledgeInfo struct {
+	ClanID   int32
+	Name     string
+	AllyName string
+}
+
+// Write serializes the PledgeInfo packet.
+func (p *PledgeInfo) Write() ([]byte, error) {
+	w := packet.NewWriter(5 + (len(p.Name)+len(p.AllyName)+2)*2)
+	if err := w.WriteByte(OpcodePledgeInfo); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.ClanID)
+	w.WriteString(p.Name)
+	w.WriteString(p.AllyName)
+	return w.Bytes(), nil
+}
+
+// PledgeCrest sends crest image data.
+//
+// Structure:
+// - byte: opcode (0x6C)
+// - int32: crest ID
+// - int32: data length
+// - bytes: data
+type PledgeCrest struct {
+	CrestID int32
+	Data    []byte
+}
+
+// Write serializes the PledgeCrest packet.
+func (p *PledgeCrest) Write() ([]byte, error) {
+	w := packet.NewWriter(9 + len(p.Data))
+	if err := w.WriteByte(OpcodePledgeCrest); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.CrestID)
+	w.WriteInt(int32(len(p.Data)))
+	w.WriteBytes(p.Data)
+	return w.Bytes(), nil
+}
+
+// ExPledgeCrestLarge sends large crest image data.
+//
+// Structure:
+// - byte: opcode (0xFE), int16: sub-opcode (0x1B)
+// - int32: 0
+// - int32: crest ID
+// - int32: data length
+// - bytes: data
+type ExPledgeCrestLarge struct {
+	CrestID int32
+	Data    []byte
+}
+
+// Write serializes the ExPledgeCrestLarge packet.
+func (p *ExPledgeCrestLarge) Write() ([]byte, error) {
+	w := packet.NewWriter(15 + len(p.Data))
+	if err := w.WriteByte(OpcodeExtended); err != nil {
+		return nil, err
+	}
+	w.WriteShort(ExOpcodePledgeCrestLarge)
+	w.WriteInt(0)
+	w.WriteInt(p.CrestID)
+	w.WriteInt(int32(len(p.Data)))
+	w.WriteBytes(p.Data)
+	return w.Bytes(), nil
+}
+
+// ManagePledgePower sends privileges of a clan rank.
+//
+// Structure:
+// - byte: opcode (0x30)
+// - int32: 0
+// - int32: 0
+// - int32: privileges bitmask
+type ManagePledgePower struct {
+	Privileges int32
+}
+
+// Write serializes the ManagePledgePower packet.
+func (p *ManagePledgePower) Write() ([]byte, error) {
+	w := packet.NewWriter(13)
+	if err := w.WriteByte(OpcodeManagePledgePower); err != nil {
+		return nil, err
+	}
+	w.WriteInt(0)
+	w.WriteInt(0)
+	w.WriteInt(p.Privileges)
+	return w.Bytes(), nil
+}
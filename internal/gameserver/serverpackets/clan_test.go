@@ -0,0 +1,131 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestAskJoinPledge_Write(t *testing.T) {
+	data, err := (&AskJoinPledge{RequesterID: 42, ClanName: "Knights"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeAskJoinPledge, data)
+	testutil.AssertInt32LE(t, 42, data, 1)
+	testutil.AssertUTF16String(t, "Knights", data, 5)
+}
+
+func TestPledgeShowMemberListAll_Write(t *testing.T) {
+	pkt := &PledgeShowMemberListAll{
+		Clan:       ClanInfo{ClanID: 7, Name: "Knights", CrestID: 3, Level: 2, Reputation: 500},
+		PledgeName: "Knights",
+		LeaderName: "Leader",
+		Members: []PledgeMember{
+			{Name: "Leader", Level: 40, ClassID: 1, ObjectID: 11},
+			{Name: "Member", Level: 20, ClassID: 2},
+		},
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePledgeShowMemberListAll, data)
+	testutil.AssertInt32LE(t, 0, data, 1) // main clan
+	testutil.AssertInt32LE(t, 7, data, 5)
+	testutil.AssertInt32LE(t, 0, data, 9)
+
+	off := 13
+	testutil.AssertUTF16String(t, "Knights", data, off)
+	off += (len("Knights") + 1) * 2
+	testutil.AssertUTF16String(t, "Leader", data, off)
+	off += (len("Leader") + 1) * 2
+	testutil.AssertInt32LE(t, 3, data, off) // crest
+	testutil.AssertInt32LE(t, 2, data, off+4)
+	testutil.AssertInt32LE(t, 500, data, off+20)
+	off += 4 + 32 // crest, status block up to ally ID
+	off += 2      // empty ally name
+	off += 8      // ally crest, at war
+	testutil.AssertInt32LE(t, 2, data, off)
+	off += 4
+	testutil.AssertUTF16String(t, "Leader", data, off)
+	off += (len("Leader") + 1) * 2
+	testutil.AssertInt32LE(t, 40, data, off)
+	testutil.AssertInt32LE(t, 11, data, off+16)
+}
+
+func TestPledgeShowMemberListAll_SubPledge(t *testing.T) {
+	data, err := (&PledgeShowMemberListAll{Clan: ClanInfo{ClanID: 7}, PledgeType: -1}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertInt32LE(t, 1, data, 1)
+	testutil.AssertInt32LE(t, -1, data, 9)
+}
+
+func TestPledgeShowMemberListDeleteAll_Write(t *testing.T) {
+	data, err := PledgeShowMemberListDeleteAll{}.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePledgeShowMemberListDeleteAll, data)
+	testutil.AssertPacketLength(t, 1, data)
+}
+
+func TestPledgeCrest_Write(t *testing.T) {
+	crest := []byte{1, 2, 3, 4}
+	data, err := (&PledgeCrest{CrestID: 9, Data: crest}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePledgeCrest, data)
+	testutil.AssertInt32LE(t, 9, data, 1)
+	testutil.AssertInt32LE(t, 4, data, 5)
+	testutil.AssertPacketLength(t, 13, data)
+}
+
+func TestExPledgeCrestLarge_Write(t *testing.T) {
+	data, err := (&ExPledgeCrestLarge{CrestID: 9, Data: []byte{1, 2}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeExtended, data)
+	if data[1] != ExOpcodePledgeCrestLarge || data[2] != 0 {
+		t.Errorf("sub-opcode = %#x%02x, want %#x", data[2], data[1], ExOpcodePledgeCrestLarge)
+	}
+	testutil.AssertInt32LE(t, 9, data, 7)
+	testutil.AssertInt32LE(t, 2, data, 11)
+	testutil.AssertPacketLength(t, 17, data)
+}
+
+func TestCharInfo_Write(t *testing.T) {
+	p, err := model.NewPlayer(11, 1, "Alpha", 40, 0, 10)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetClan(7, 0, 6)
+	p.SetLocation(model.NewLocation(100, 200, 300, 0))
+	p.SetKarma(480)
+	p.SetPvPFlag(true)
+
+	data, err := (&CharInfo{Player: p, ClanCrestID: 3, LargeCrestID: 4}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeCharInfo, data)
+	testutil.AssertInt32LE(t, 100, data, 1)
+	testutil.AssertInt32LE(t, int32(p.ObjectID()), data, 17)
+	testutil.AssertUTF16String(t, "Alpha", data, 21)
+
+	off := 21 + (len("Alpha")+1)*2
+	off += 12 + 12*4                // race, sex, class, paperdoll
+	off += 4*2 + 4 + 12*2 + 4 + 4*2 // augmentation block
+	testutil.AssertInt32LE(t, 1, data, off)
+	testutil.AssertInt32LE(t, 480, data, off+4)
+	testutil.AssertInt32LE(t, 480, data, off+20)
+	off += 6*4 + 8*4 + 4*8 + 3*4
+	off += 2 // empty title
+	testutil.AssertInt32LE(t, 7, data, off)
+	testutil.AssertInt32LE(t, 3, data, off+4)
+}
@@ -0,0 +1,35 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const ExOpcodeSetCompassZoneCode = 0x32
+
+// Compass zone codes: the zone kind shown on the client compass.
+const (
+	CompassSiegeWarZone1 = 0x0A
+	CompassSiegeWarZone2 = 0x0B
+	CompassPeaceZone     = 0x0C
+	CompassSevenSigns    = 0x0D
+	CompassPvPZone       = 0x0E
+	CompassGeneralZone   = 0x0F
+)
+
+// ExSetCompassZoneCode tells the client which kind of zone the player is in.
+//
+// Structure:
+// - byte: opcode (0xFE), int16: sub-opcode (0x32)
+// - int32: zone code
+type ExSetCompassZoneCode struct {
+	Code int32
+}
+
+// Write serializes the ExSetCompassZoneCode packet.
+func (p *ExSetCompassZoneCode) Write() ([]byte, error) {
+	w := packet.NewWriter(7)
+	if err := w.WriteByte(OpcodeExtended); err != nil {
+		return nil, err
+	}
+	w.WriteShort(ExOpcodeSetCompassZoneCode)
+	w.WriteInt(p.Code)
+	return w.Bytes(), nil
+}
@@ -0,0 +1,21 @@
+package serverpackets
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestExSetCompassZoneCode_Write(t *testing.T) {
+	data, err := (&ExSetCompassZoneCode{Code: CompassPeaceZone}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeExtended, data)
+	if sub := binary.LittleEndian.Uint16(data[1:]); sub != ExOpcodeSetCompassZoneCode {
+		t.Errorf("sub-opcode = 0x%02X, want 0x%02X", sub, ExOpcodeSetCompassZoneCode)
+	}
+	testutil.AssertInt32LE(t, CompassPeaceZone, data, 3)
+	testutil.AssertPacketLength(t, 7, data)
+}
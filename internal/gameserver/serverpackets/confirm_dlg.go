@@ -0,0 +1,43 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeConfirmDlg = 0xED
+
+// ConfirmDlg asks the player a yes/no question built from a system message;
+// the client answers with DlgAnswer.
+//
+// Structure:
+// - byte: opcode (0xED)
+// - int32: message ID
+// - int32: parameter count
+// - per parameter: int32 type (0 = text), string value
+// - int32: time to answer in ms (0 = no limit)
+// - int32: objectID of the requester
+type ConfirmDlg struct {
+	MessageID   int32
+	Params      []string
+	Time        int32
+	RequesterID uint32
+}
+
+// Write serializes the ConfirmDlg packet.
+func (p *ConfirmDlg) Write() ([]byte, error) {
+	size := 17
+	for _, s := range p.Params {
+		size += 4 + (len(s)+1)*2
+	}
+	w := packet.NewWriter(size)
+	if err := w.WriteByte(OpcodeConfirmDlg); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.MessageID)
+	w.WriteInt(int32(len(p.Params)))
+	for _, s := range p.Params {
+		w.WriteInt(paramTypeText)
+		w.WriteString(s)
+	}
+	w.WriteInt(p.Time)
+	w.WriteInt(int32(p.RequesterID))
+	return w.Bytes(), nil
+}
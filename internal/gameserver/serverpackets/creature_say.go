@@ -0,0 +1,33 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeCreatureSay = 0x4A
+
+// CreatureSay delivers a chat message.
+//
+// Structure:
+// - byte: opcode (0x4A)
+// - int32: sender objectID
+// - int32: chat type
+// - string: sender name
+// - string: text
+type CreatureSay struct {
+	ObjectID uint32
+	ChatType int32
+	Name     string
+	Text     string
+}
+
+// Write serializes the CreatureSay packet.
+func (p *CreatureSay) Write() ([]byte, error) {
+	w := packet.NewWriter(13 + (len(p.Name)+len(p.Text)+2)*2)
+	if err := w.WriteByte(OpcodeCreatureSay); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(p.ChatType)
+	w.WriteString(p.Name)
+	w.WriteString(p.Text)
+	return w.Bytes(), nil
+}
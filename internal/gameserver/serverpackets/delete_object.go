@@ -0,0 +1,26 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeDeleteObject = 0x12
+
+// DeleteObject removes an object from the client's view (despawned NPCs, hidden GMs).
+//
+// Structure:
+// - byte: opcode (0x12)
+// - int32: objectID
+// - int32: unknown (0)
+type DeleteObject struct {
+	ObjectID uint32
+}
+
+// Write serializes the DeleteObject packet.
+func (p *DeleteObject) Write() ([]byte, error) {
+	w := packet.NewWriter(9)
+	if err := w.WriteByte(OpcodeDeleteObject); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(0)
+	return w.Bytes(), nil
+}
@@ -0,0 +1,18 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestDeleteObject_Write(t *testing.T) {
+	data, err := (&DeleteObject{ObjectID: 100001}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeDeleteObject, data)
+	testutil.AssertPacketLength(t, 9, data)
+	testutil.AssertInt32LE(t, 100001, data, 1)
+	testutil.AssertInt32LE(t, 0, data, 5)
+}
@@ -0,0 +1,61 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodeDie    = 0x06
+	OpcodeRevive = 0x07
+)
+
+// Die shows a creature dying; the dead player gets buttons for its restart options.
+//
+// Structure:
+// - byte: opcode (0x06)
+// - int32: objectID
+// - int32: to village (1 = offered)
+// - int32: to clan hall
+// - int32: to castle
+// - int32: to siege headquarters
+// - int32: sweepable
+// - int32: restart in place (fixed)
+type Die struct {
+	ObjectID  uint32
+	Village   bool
+	ClanHall  bool
+	Castle    bool
+	SiegeHQ   bool
+	Sweepable bool
+	Fixed     bool
+}
+
+// Write serializes the Die packet.
+func (p *Die) Write() ([]byte, error) {
+	w := packet.NewWriter(29)
+	if err := w.WriteByte(OpcodeDie); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	for _, v := range []bool{p.Village, p.ClanHall, p.Castle, p.SiegeHQ, p.Sweepable, p.Fixed} {
+		w.WriteInt(boolToInt(v))
+	}
+	return w.Bytes(), nil
+}
+
+// Revive brings a dead creature back to life on clients.
+//
+// Structure:
+// - byte: opcode (0x07)
+// - int32: objectID
+type Revive struct {
+	ObjectID uint32
+}
+
+// Write serializes the Revive packet.
+func (p *Revive) Write() ([]byte, error) {
+	w := packet.NewWriter(5)
+	if err := w.WriteByte(OpcodeRevive); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	return w.Bytes(), nil
+}
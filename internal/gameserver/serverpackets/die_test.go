@@ -0,0 +1,50 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestDie_Write(t *testing.T) {
+	data, err := (&Die{ObjectID: 100001, Village: true, Castle: true, Fixed: true}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeDie, data)
+	testutil.AssertPacketLength(t, 29, data)
+	for i, want := range []int32{100001, 1, 0, 1, 0, 0, 1} {
+		testutil.AssertInt32LE(t, want, data, 1+i*4)
+	}
+}
+
+func TestRevive_Write(t *testing.T) {
+	data, err := (&Revive{ObjectID: 100001}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeRevive, data)
+	testutil.AssertPacketLength(t, 5, data)
+	testutil.AssertInt32LE(t, 100001, data, 1)
+}
+
+func TestConfirmDlg_Write(t *testing.T) {
+	data, err := (&ConfirmDlg{
+		MessageID:   SystemMessageResurrectRequest,
+		Params:      []string{"Ann", "70"},
+		RequesterID: 100002,
+	}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeConfirmDlg, data)
+	testutil.AssertInt32LE(t, SystemMessageResurrectRequest, data, 1)
+	testutil.AssertInt32LE(t, 2, data, 5)
+	testutil.AssertInt32LE(t, 0, data, 9)
+	testutil.AssertUTF16String(t, "Ann", data, 13)
+	testutil.AssertInt32LE(t, 0, data, 21)
+	testutil.AssertUTF16String(t, "70", data, 25)
+	testutil.AssertInt32LE(t, 0, data, 31)
+	testutil.AssertInt32LE(t, 100002, data, 35)
+	testutil.AssertPacketLength(t, 39, data)
+}
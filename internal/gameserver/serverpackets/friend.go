@@ -0,0 +1,65 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodeAskJoinFriend = 0x7D
+	OpcodeFriendList    = 0xFA
+)
+
+// AskJoinFriend asks a player to accept a friend invitation.
+//
+// Structure:
+// - byte: opcode (0x7D)
+// - string: requester name
+type AskJoinFriend struct {
+	RequesterName string
+}
+
+// Write serializes the AskJoinFriend packet.
+func (p *AskJoinFriend) Write() ([]byte, error) {
+	w := packet.NewWriter(1 + (len(p.RequesterName)+1)*2)
+	if err := w.WriteByte(OpcodeAskJoinFriend); err != nil {
+		return nil, err
+	}
+	w.WriteString(p.RequesterName)
+	return w.Bytes(), nil
+}
+
+// FriendInfo is a friend list entry.
+type FriendInfo struct {
+	CharacterID int64
+	Name        string
+	Online      bool
+}
+
+// FriendList sends the full friend list with online status.
+//
+// Structure:
+// - byte: opcode (0xFA)
+// - int16: count
+// - per friend: int16 (0), int32 character ID, string name, int32 online, int16 (0)
+type FriendList struct {
+	Friends []FriendInfo
+}
+
+// Write serializes the FriendList packet.
+func (p *FriendList) Write() ([]byte, error) {
+	size := 3
+	for _, f := range p.Friends {
+		size += 12 + (len(f.Name)+1)*2
+	}
+	w := packet.NewWriter(size)
+	if err := w.WriteByte(OpcodeFriendList); err != nil {
+		return nil, err
+	}
+	w.WriteShort(int16(len(p.Friends)))
+	for _, f := range p.Friends {
+		w.WriteShort(0)
+		w.WriteInt(int32(f.CharacterID))
+		w.WriteString(f.Name)
+		w.WriteInt(boolToInt(f.Online))
+		w.WriteShort(0)
+	}
+	return w.Bytes(), nil
+}
@@ -0,0 +1,42 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestFriendList_Write(t *testing.T) {
+	pkt := &FriendList{Friends: []FriendInfo{
+		{CharacterID: 7, Name: "Alice", Online: true},
+		{CharacterID: 8, Name: "Bob"},
+	}}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeFriendList, data)
+	if count := int16(data[1]) | int16(data[2])<<8; count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+
+	off := 3 + 2
+	testutil.AssertInt32LE(t, 7, data, off)
+	testutil.AssertUTF16String(t, "Alice", data, off+4)
+	off += 4 + (len("Alice")+1)*2
+	testutil.AssertInt32LE(t, 1, data, off)
+	off += 4 + 2 + 2
+	testutil.AssertInt32LE(t, 8, data, off)
+	off += 4 + (len("Bob")+1)*2
+	testutil.AssertInt32LE(t, 0, data, off)
+	testutil.AssertPacketLength(t, off+4+2, data)
+}
+
+func TestAskJoinFriend_Write(t *testing.T) {
+	data, err := (&AskJoinFriend{RequesterName: "Alice"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeAskJoinFriend, data)
+	testutil.AssertUTF16String(t, "Alice", data, 1)
+}
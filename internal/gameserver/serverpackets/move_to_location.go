@@ -0,0 +1,38 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeMoveToLocation = 0x01
+
+// MoveToLocation tells clients that a creature starts walking to a point.
+// Long routes are sent one waypoint at a time.
+//
+// Structure:
+// - byte: opcode (0x01)
+// - int32: objectID
+// - int32: destination x, y, z
+// - int32: current x, y, z
+type MoveToLocation struct {
+	ObjectID uint32
+	Dest     model.Location
+	Origin   model.Location
+}
+
+// Write serializes the MoveToLocation packet.
+func (p *MoveToLocation) Write() ([]byte, error) {
+	w := packet.NewWriter(29)
+	if err := w.WriteByte(OpcodeMoveToLocation); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(p.Dest.X)
+	w.WriteInt(p.Dest.Y)
+	w.WriteInt(p.Dest.Z)
+	w.WriteInt(p.Origin.X)
+	w.WriteInt(p.Origin.Y)
+	w.WriteInt(p.Origin.Z)
+	return w.Bytes(), nil
+}
@@ -0,0 +1,25 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestMoveToLocation_Write(t *testing.T) {
+	pkt := &MoveToLocation{
+		ObjectID: 100001,
+		Dest:     model.NewLocation(17500, 170200, -3490, 0),
+		Origin:   model.NewLocation(17000, 170000, -3500, 0),
+	}
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeMoveToLocation, data)
+	testutil.AssertPacketLength(t, 29, data)
+	for i, want := range []int32{100001, 17500, 170200, -3490, 17000, 170000, -3500} {
+		testutil.AssertInt32LE(t, want, data, 1+i*4)
+	}
+}
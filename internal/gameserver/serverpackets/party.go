@@ -0,0 +1,214 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	OpcodeAskJoinParty              = 0x39
+	OpcodeJoinParty                 = 0x3A
+	OpcodePartySmallWindowAll       = 0x4E
+	OpcodePartySmallWindowAdd       = 0x4F
+	OpcodePartySmallWindowDeleteAll = 0x50
+	OpcodePartySmallWindowDelete    = 0x51
+	OpcodePartySmallWindowUpdate    = 0x52
+	OpcodePartyMemberPosition       = 0xA7
+)
+
+// AskJoinParty asks the target to join the requester's party.
+//
+// Structure:
+// - byte: opcode (0x39)
+// - string: requester name
+// - int32: item distribution
+type AskJoinParty struct {
+	RequesterName    string
+	ItemDistribution int32
+}
+
+// Write serializes the AskJoinParty packet.
+func (p *AskJoinParty) Write() ([]byte, error) {
+	w := packet.NewWriter(8 + (len(p.RequesterName)+1)*2)
+	if err := w.WriteByte(OpcodeAskJoinParty); err != nil {
+		return nil, err
+	}
+	w.WriteString(p.RequesterName)
+	w.WriteInt(p.ItemDistribution)
+	return w.Bytes(), nil
+}
+
+// JoinParty tells the requester whether the invitation was accepted.
+//
+// Structure:
+// - byte: opcode (0x3A)
+// - int32: response (1 = accepted, 0 = declined)
+type JoinParty struct {
+	Accepted bool
+}
+
+// Write serializes the JoinParty packet.
+func (p *JoinParty) Write() ([]byte, error) {
+	w := packet.NewWriter(5)
+	if err := w.WriteByte(OpcodeJoinParty); err != nil {
+		return nil, err
+	}
+	w.WriteInt(boolToInt(p.Accepted))
+	return w.Bytes(), nil
+}
+
+// writePartyMember writes the member block shared by PartySmallWindow packets.
+func writePartyMember(w *packet.Writer, m *model.Player) {
+	w.WriteInt(int32(m.ObjectID()))
+	w.WriteString(m.Name())
+	w.WriteInt(m.CurrentCP())
+	w.WriteInt(m.MaxCP())
+	w.WriteInt(m.CurrentHP())
+	w.WriteInt(m.MaxHP())
+	w.WriteInt(m.CurrentMP())
+	w.WriteInt(m.MaxMP())
+	w.WriteInt(m.Level())
+	w.WriteInt(m.ClassID())
+}
+
+// PartySmallWindowAll sends the full party list to a member (excluding the member itself).
+//
+// Structure:
+//   - byte: opcode (0x4E)
+//   - int32: leader objectID
+//   - int32: item distribution
+//   - int32: member count
+//   - for each member: int32 objectID, string name, int32 cp, max cp, hp, max hp,
+//     mp, max mp, level, class ID, int32 0, int32 race
+type PartySmallWindowAll struct {
+	LeaderID         uint32
+	ItemDistribution int32
+	Members          []*model.Player
+}
+
+// Write serializes the PartySmallWindowAll packet.
+func (p *PartySmallWindowAll) Write() ([]byte, error) {
+	w := packet.NewWriter(13 + len(p.Members)*80)
+	if err := w.WriteByte(OpcodePartySmallWindowAll); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.LeaderID))
+	w.WriteInt(p.ItemDistribution)
+	w.WriteInt(int32(len(p.Members)))
+	for _, m := range p.Members {
+		writePartyMember(w, m)
+		w.WriteInt(0)
+		w.WriteInt(m.RaceID())
+	}
+	return w.Bytes(), nil
+}
+
+// PartySmallWindowAdd adds a new member to the party window.
+//
+// Structure:
+// - byte: opcode (0x4F)
+// - int32: leader objectID
+// - int32: item distribution
+// - member block (see PartySmallWindowAll) + int32 0, int32 0
+type PartySmallWindowAdd struct {
+	LeaderID         uint32
+	ItemDistribution int32
+	Member           *model.Player
+}
+
+// Write serializes the PartySmallWindowAdd packet.
+func (p *PartySmallWindowAdd) Write() ([]byte, error) {
+	w := packet.NewWriter(96)
+	if err := w.WriteByte(OpcodePartySmallWindowAdd); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.LeaderID))
+	w.WriteInt(p.ItemDistribution)
+	writePartyMember(w, p.Member)
+	w.WriteInt(0)
+	w.WriteInt(0)
+	return w.Bytes(), nil
+}
+
+// PartySmallWindowDeleteAll closes the party window (party disbanded or player left).
+//
+// Structure:
+// - byte: opcode (0x50)
+type PartySmallWindowDeleteAll struct{}
+
+// Write serializes the PartySmallWindowDeleteAll packet.
+func (p PartySmallWindowDeleteAll) Write() ([]byte, error) {
+	w := packet.NewWriter(1)
+	if err := w.WriteByte(OpcodePartySmallWindowDeleteAll); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// PartySmallWindowDelete removes a member from the party window.
+//
+// Structure:
+// - byte: opcode (0x51)
+// - int32: member objectID
+// - string: member name
+type PartySmallWindowDelete struct {
+	ObjectID uint32
+	Name     string
+}
+
+// Write serializes the PartySmallWindowDelete packet.
+func (p *PartySmallWindowDelete) Write() ([]byte, error) {
+	w := packet.NewWriter(8 + (len(p.Name)+1)*2)
+	if err := w.WriteByte(OpcodePartySmallWindowDelete); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteString(p.Name)
+	return w.Bytes(), nil
+}
+
+// PartySmallWindowUpdate refreshes a member's HP/MP/CP/level in the party window.
+//
+// Structure:
+// - byte: opcode (0x52)
+// - member block (see PartySmallWindowAll)
+type PartySmallWindowUpdate struct {
+	Member *model.Player
+}
+
+// Write serializes the PartySmallWindowUpdate packet.
+func (p *PartySmallWindowUpdate) Write() ([]byte, error) {
+	w := packet.NewWriter(80)
+	if err := w.WriteByte(OpcodePartySmallWindowUpdate); err != nil {
+		return nil, err
+	}
+	writePartyMember(w, p.Member)
+	return w.Bytes(), nil
+}
+
+// PartyMemberPosition updates party member markers on the minimap.
+//
+// Structure:
+// - byte: opcode (0xA7)
+// - int32: member count
+// - for each member: int32 objectID, int32 x, int32 y, int32 z
+type PartyMemberPosition struct {
+	Members []*model.Player
+}
+
+// Write serializes the PartyMemberPosition packet.
+func (p *PartyMemberPosition) Write() ([]byte, error) {
+	w := packet.NewWriter(5 + len(p.Members)*16)
+	if err := w.WriteByte(OpcodePartyMemberPosition); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(len(p.Members)))
+	for _, m := range p.Members {
+		loc := m.Location()
+		w.WriteInt(int32(m.ObjectID()))
+		w.WriteInt(loc.X)
+		w.WriteInt(loc.Y)
+		w.WriteInt(loc.Z)
+	}
+	return w.Bytes(), nil
+}
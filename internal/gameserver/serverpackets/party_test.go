@@ -0,0 +1,92 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func newPartyMember(t *testing.T, id int64, name string) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, name, 40, 0, 10)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	return p
+}
+
+func TestAskJoinParty_Write(t *testing.T) {
+	data, err := (&AskJoinParty{RequesterName: "Leader", ItemDistribution: 3}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeAskJoinParty, data)
+	testutil.AssertUTF16String(t, "Leader", data, 1)
+	testutil.AssertInt32LE(t, 3, data, 1+(len("Leader")+1)*2)
+}
+
+func TestJoinParty_Write(t *testing.T) {
+	data, err := (&JoinParty{Accepted: true}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeJoinParty, data)
+	testutil.AssertPacketLength(t, 5, data)
+	testutil.AssertInt32LE(t, 1, data, 1)
+}
+
+func TestPartySmallWindowAll_Write(t *testing.T) {
+	a := newPartyMember(t, 11, "Alpha")
+	b := newPartyMember(t, 12, "Beta")
+
+	data, err := (&PartySmallWindowAll{LeaderID: 11, ItemDistribution: 1, Members: []*model.Player{a, b}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePartySmallWindowAll, data)
+	testutil.AssertInt32LE(t, 11, data, 1)
+	testutil.AssertInt32LE(t, 1, data, 5)
+	testutil.AssertInt32LE(t, 2, data, 9)
+	testutil.AssertInt32LE(t, 11, data, 13)
+	testutil.AssertUTF16String(t, "Alpha", data, 17)
+}
+
+func TestPartySmallWindowDelete_Write(t *testing.T) {
+	data, err := (&PartySmallWindowDelete{ObjectID: 12, Name: "Beta"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePartySmallWindowDelete, data)
+	testutil.AssertInt32LE(t, 12, data, 1)
+	testutil.AssertUTF16String(t, "Beta", data, 5)
+}
+
+func TestPartyMemberPosition_Write(t *testing.T) {
+	a := newPartyMember(t, 11, "Alpha")
+	a.SetLocation(model.NewLocation(100, -200, 300, 0))
+
+	data, err := (&PartyMemberPosition{Members: []*model.Player{a}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodePartyMemberPosition, data)
+	testutil.AssertPacketLength(t, 1+4+16, data)
+	testutil.AssertInt32LE(t, 1, data, 1)
+	testutil.AssertInt32LE(t, 11, data, 5)
+	testutil.AssertInt32LE(t, 100, data, 9)
+	testutil.AssertInt32LE(t, -200, data, 13)
+	testutil.AssertInt32LE(t, 300, data, 17)
+}
+
+func TestCreatureSay_Write(t *testing.T) {
+	data, err := (&CreatureSay{ObjectID: 5, ChatType: 3, Name: "Alpha", Text: "hi"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeCreatureSay, data)
+	testutil.AssertInt32LE(t, 5, data, 1)
+	testutil.AssertInt32LE(t, 3, data, 5)
+	testutil.AssertUTF16String(t, "Alpha", data, 9)
+	testutil.AssertUTF16String(t, "hi", data, 9+(len("Alpha")+1)*2)
+}
@@ -0,0 +1,116 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	OpcodePrivateStoreListSell = 0x9B
+	OpcodePrivateStoreListBuy  = 0xB8
+)
+
+// PrivateStoreListSell shows a sell store's items to a buyer.
+// Item template data (type2, body part, reference price) is not loaded yet
+// and is written as zero.
+//
+// Structure:
+//   - byte: opcode (0x9B)
+//   - int32: store owner objectID
+//   - int32: package sale (1 = must buy all)
+//   - int32: buyer adena
+//   - int32: item count
+//   - for each item: int32 type2, int32 objectID, int32 itemID, int32 count,
+//     int16 0, int16 enchant, int16 0, int32 body part, int32 price, int32 reference price
+type PrivateStoreListSell struct {
+	StoreObjectID uint32
+	PackageSale   bool
+	BuyerAdena    int64
+	Items         []model.TradeItem
+}
+
+// Write serializes the PrivateStoreListSell packet.
+func (p *PrivateStoreListSell) Write() ([]byte, error) {
+	w := packet.NewWriter(17 + len(p.Items)*34)
+	if err := w.WriteByte(OpcodePrivateStoreListSell); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.StoreObjectID))
+	w.WriteInt(boolToInt(p.PackageSale))
+	w.WriteInt(clampAdena(p.BuyerAdena))
+	w.WriteInt(int32(len(p.Items)))
+	for _, it := range p.Items {
+		w.WriteInt(0) // type2
+		w.WriteInt(int32(it.ObjectID))
+		w.WriteInt(it.ItemType)
+		w.WriteInt(it.Count)
+		w.WriteShort(0)
+		w.WriteShort(int16(it.Enchant))
+		w.WriteShort(0)
+		w.WriteInt(0) // body part
+		w.WriteInt(it.Price)
+		w.WriteInt(0) // reference price
+	}
+	return w.Bytes(), nil
+}
+
+// BuyStoreEntry — позиция buy-магазина вместе с предметом продавца, который подходит под неё.
+type BuyStoreEntry struct {
+	Item        model.TradeItem
+	SellerObjID uint32 // objectID предмета в инвентаре продавца (0 если нет)
+	SellerCount int32  // сколько таких предметов у продавца
+}
+
+// PrivateStoreListBuy shows a buy store's wanted items to a seller.
+//
+// Structure:
+//   - byte: opcode (0xB8)
+//   - int32: store owner objectID
+//   - int32: seller adena
+//   - int32: item count
+//   - for each item: int32 objectID, int32 itemID, int16 enchant, int32 seller count,
+//     int32 reference price, int16 0, int32 body part, int16 type2, int32 price, int32 store count
+type PrivateStoreListBuy struct {
+	StoreObjectID uint32
+	SellerAdena   int64
+	Items         []BuyStoreEntry
+}
+
+// Write serializes the PrivateStoreListBuy packet.
+func (p *PrivateStoreListBuy) Write() ([]byte, error) {
+	w := packet.NewWriter(13 + len(p.Items)*32)
+	if err := w.WriteByte(OpcodePrivateStoreListBuy); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.StoreObjectID))
+	w.WriteInt(clampAdena(p.SellerAdena))
+	w.WriteInt(int32(len(p.Items)))
+	for _, e := range p.Items {
+		w.WriteInt(int32(e.SellerObjID))
+		w.WriteInt(e.Item.ItemType)
+		w.WriteShort(int16(e.Item.Enchant))
+		w.WriteInt(e.SellerCount)
+		w.WriteInt(0) // reference price
+		w.WriteShort(0)
+		w.WriteInt(0)   // body part
+		w.WriteShort(0) // type2
+		w.WriteInt(e.Item.Price)
+		w.WriteInt(e.Item.Count)
+	}
+	return w.Bytes(), nil
+}
+
+func boolToInt(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// clampAdena приводит количество адены к int32 клиента Interlude.
+func clampAdena(adena int64) int32 {
+	if adena > 1<<31-1 {
+		return 1<<31 - 1
+	}
+	return int32(adena)
+}
@@ -0,0 +1,167 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	OpcodePrivateStoreManageListSell = 0x9A
+	OpcodePrivateStoreManageListBuy  = 0xB7
+	OpcodeRecipeShopManageList       = 0xD8
+)
+
+// PrivateStoreManageListSell opens the sell store setup window.
+//
+// Structure:
+//   - byte: opcode (0x9A)
+//   - int32: player objectID
+//   - int32: package sale
+//   - int32: player adena
+//   - int32: sellable inventory item count
+//   - for each item: int32 type2, int32 objectID, int32 itemID, int32 count,
+//     int16 0, int16 enchant, int16 0, int32 body part, int32 reference price
+//   - int32: current sell list count
+//   - for each entry: same as above + int32 price before reference price
+type PrivateStoreManageListSell struct {
+	ObjectID    uint32
+	PackageSale bool
+	Adena       int64
+	Inventory   []*model.Item
+	SellList    []model.TradeItem
+}
+
+// Write serializes the PrivateStoreManageListSell packet.
+func (p *PrivateStoreManageListSell) Write() ([]byte, error) {
+	w := packet.NewWriter(21 + len(p.Inventory)*30 + len(p.SellList)*34)
+	if err := w.WriteByte(OpcodePrivateStoreManageListSell); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(boolToInt(p.PackageSale))
+	w.WriteInt(clampAdena(p.Adena))
+
+	w.WriteInt(int32(len(p.Inventory)))
+	for _, item := range p.Inventory {
+		w.WriteInt(0) // type2
+		w.WriteInt(int32(item.ObjectID()))
+		w.WriteInt(item.ItemType())
+		w.WriteInt(item.Count())
+		w.WriteShort(0)
+		w.WriteShort(int16(item.Enchant()))
+		w.WriteShort(0)
+		w.WriteInt(0) // body part
+		w.WriteInt(0) // reference price
+	}
+
+	w.WriteInt(int32(len(p.SellList)))
+	for _, it := range p.SellList {
+		w.WriteInt(0) // type2
+		w.WriteInt(int32(it.ObjectID))
+		w.WriteInt(it.ItemType)
+		w.WriteInt(it.Count)
+		w.WriteShort(0)
+		w.WriteShort(int16(it.Enchant))
+		w.WriteShort(0)
+		w.WriteInt(0) // body part
+		w.WriteInt(it.Price)
+		w.WriteInt(0) // reference price
+	}
+	return w.Bytes(), nil
+}
+
+// PrivateStoreManageListBuy opens the buy store setup window.
+//
+// Structure:
+//   - byte: opcode (0xB7)
+//   - int32: player objectID
+//   - int32: player adena
+//   - int32: inventory item count
+//   - for each item: int32 itemID, int16 enchant, int32 count, int32 reference price,
+//     int16 0, int32 body part, int16 type2
+//   - int32: current buy list count
+//   - for each entry: same as above + int32 price, int32 reference price
+type PrivateStoreManageListBuy struct {
+	ObjectID  uint32
+	Adena     int64
+	Inventory []*model.Item
+	BuyList   []model.TradeItem
+}
+
+// Write serializes the PrivateStoreManageListBuy packet.
+func (p *PrivateStoreManageListBuy) Write() ([]byte, error) {
+	w := packet.NewWriter(17 + len(p.Inventory)*22 + len(p.BuyList)*30)
+	if err := w.WriteByte(OpcodePrivateStoreManageListBuy); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(clampAdena(p.Adena))
+
+	w.WriteInt(int32(len(p.Inventory)))
+	for _, item := range p.Inventory {
+		w.WriteInt(item.ItemType())
+		w.WriteShort(int16(item.Enchant()))
+		w.WriteInt(item.Count())
+		w.WriteInt(0) // reference price
+		w.WriteShort(0)
+		w.WriteInt(0)   // body part
+		w.WriteShort(0) // type2
+	}
+
+	w.WriteInt(int32(len(p.BuyList)))
+	for _, it := range p.BuyList {
+		w.WriteInt(it.ItemType)
+		w.WriteShort(int16(it.Enchant))
+		w.WriteInt(it.Count)
+		w.WriteInt(0) // reference price
+		w.WriteShort(0)
+		w.WriteInt(0)   // body part
+		w.WriteShort(0) // type2
+		w.WriteInt(it.Price)
+		w.WriteInt(0) // reference price
+	}
+	return w.Bytes(), nil
+}
+
+// RecipeShopManageList opens the dwarven manufacture setup window.
+// Recipe book is not loaded yet, so the known recipe list mirrors the shop list.
+//
+// Structure:
+// - byte: opcode (0xD8)
+// - int32: player objectID
+// - int32: player adena
+// - int32: is dwarven (1) / common (0) recipe book
+// - int32: recipe count, for each: int32 recipeID, int32 index (1-based)
+// - int32: shop count, for each: int32 recipeID, int32 0, int32 cost
+type RecipeShopManageList struct {
+	ObjectID uint32
+	Adena    int64
+	Dwarven  bool
+	Recipes  []int32
+	Items    []model.ManufactureItem
+}
+
+// Write serializes the RecipeShopManageList packet.
+func (p *RecipeShopManageList) Write() ([]byte, error) {
+	w := packet.NewWriter(21 + len(p.Recipes)*8 + len(p.Items)*12)
+	if err := w.WriteByte(OpcodeRecipeShopManageList); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(clampAdena(p.Adena))
+	w.WriteInt(boolToInt(p.Dwarven))
+
+	w.WriteInt(int32(len(p.Recipes)))
+	for i, id := range p.Recipes {
+		w.WriteInt(id)
+		w.WriteInt(int32(i + 1))
+	}
+
+	w.WriteInt(int32(len(p.Items)))
+	for _, it := range p.Items {
+		w.WriteInt(it.RecipeID)
+		w.WriteInt(0)
+		w.WriteInt(it.Cost)
+	}
+	return w.Bytes(), nil
+}
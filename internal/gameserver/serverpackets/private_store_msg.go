@@ -0,0 +1,48 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodePrivateStoreMsgSell = 0x9C
+	OpcodePrivateStoreMsgBuy  = 0xB9
+	OpcodeRecipeShopMsg       = 0xDB
+)
+
+// PrivateStoreMsg is the store title shown above a player's head.
+// The same layout is used for sell (0x9C), buy (0xB9) and manufacture (0xDB) stores.
+//
+// Structure:
+// - byte: opcode
+// - int32: store owner objectID
+// - string: message (UTF-16LE null-terminated)
+type PrivateStoreMsg struct {
+	Opcode   byte
+	ObjectID uint32
+	Message  string
+}
+
+// NewPrivateStoreMsgSell creates PrivateStoreMsgSell (also used for package sale).
+func NewPrivateStoreMsgSell(objectID uint32, msg string) *PrivateStoreMsg {
+	return &PrivateStoreMsg{Opcode: OpcodePrivateStoreMsgSell, ObjectID: objectID, Message: msg}
+}
+
+// NewPrivateStoreMsgBuy creates PrivateStoreMsgBuy.
+func NewPrivateStoreMsgBuy(objectID uint32, msg string) *PrivateStoreMsg {
+	return &PrivateStoreMsg{Opcode: OpcodePrivateStoreMsgBuy, ObjectID: objectID, Message: msg}
+}
+
+// NewRecipeShopMsg creates RecipeShopMsg (dwarven manufacture).
+func NewRecipeShopMsg(objectID uint32, msg string) *PrivateStoreMsg {
+	return &PrivateStoreMsg{Opcode: OpcodeRecipeShopMsg, ObjectID: objectID, Message: msg}
+}
+
+// Write serializes the store message packet.
+func (p *PrivateStoreMsg) Write() ([]byte, error) {
+	w := packet.NewWriter(8 + (len(p.Message)+1)*2)
+	if err := w.WriteByte(p.Opcode); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteString(p.Message)
+	return w.Bytes(), nil
+}
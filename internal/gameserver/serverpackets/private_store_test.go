@@ -0,0 +1,79 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestPrivateStoreMsg_Write(t *testing.T) {
+	tests := []struct {
+		name   string
+		pkt    *PrivateStoreMsg
+		opcode byte
+	}{
+		{"sell", NewPrivateStoreMsgSell(42, "wts"), OpcodePrivateStoreMsgSell},
+		{"buy", NewPrivateStoreMsgBuy(42, "wts"), OpcodePrivateStoreMsgBuy},
+		{"manufacture", NewRecipeShopMsg(42, "wts"), OpcodeRecipeShopMsg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.pkt.Write()
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			testutil.AssertPacketOpcode(t, tt.opcode, data)
+			testutil.AssertInt32LE(t, 42, data, 1)
+			testutil.AssertUTF16String(t, "wts", data, 5)
+		})
+	}
+}
+
+func TestPrivateStoreListSell_Write(t *testing.T) {
+	pkt := &PrivateStoreListSell{
+		StoreObjectID: 7,
+		PackageSale:   true,
+		BuyerAdena:    1 << 40, // clamped to int32
+		Items: []model.TradeItem{
+			{ObjectID: 100, ItemType: 17, Enchant: 2, Count: 50, Price: 3},
+		},
+	}
+
+	data, err := pkt.Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	testutil.AssertPacketOpcode(t, OpcodePrivateStoreListSell, data)
+	testutil.AssertPacketLength(t, 17+34, data)
+	testutil.AssertInt32LE(t, 7, data, 1)
+	testutil.AssertInt32LE(t, 1, data, 5)
+	testutil.AssertInt32LE(t, 1<<31-1, data, 9)
+	testutil.AssertInt32LE(t, 1, data, 13)
+	testutil.AssertInt32LE(t, 100, data, 21) // objectID
+	testutil.AssertInt32LE(t, 17, data, 25)  // itemID
+	testutil.AssertInt32LE(t, 50, data, 29)  // count
+	testutil.AssertInt32LE(t, 3, data, 43)   // price
+}
+
+func TestChangeWaitType_Write(t *testing.T) {
+	p, err := model.NewPlayer(5, 1, "Sitter", 10, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLocation(model.NewLocation(10, 20, 30, 0))
+	p.SetSitting(true)
+
+	data, err := NewChangeWaitType(p).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	testutil.AssertPacketOpcode(t, OpcodeChangeWaitType, data)
+	testutil.AssertPacketLength(t, 21, data)
+	testutil.AssertInt32LE(t, 5, data, 1)
+	testutil.AssertInt32LE(t, WaitTypeSitting, data, 5)
+	testutil.AssertInt32LE(t, 30, data, 17)
+}
@@ -0,0 +1,62 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const (
+	OpcodeNpcHtmlMessage = 0x0F
+	OpcodeQuestList      = 0x80
+)
+
+// NpcHtmlMessage opens an NPC dialog window.
+//
+// Structure:
+// - byte: opcode (0x0F)
+// - int32: NPC objectID
+// - string: HTML
+// - int32: item ID (0 = not an item dialog)
+type NpcHtmlMessage struct {
+	NpcObjectID uint32
+	HTML        string
+}
+
+// Write serializes the NpcHtmlMessage packet.
+func (p *NpcHtmlMessage) Write() ([]byte, error) {
+	w := packet.NewWriter(9 + (len(p.HTML)+1)*2)
+	if err := w.WriteByte(OpcodeNpcHtmlMessage); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.NpcObjectID))
+	w.WriteString(p.HTML)
+	w.WriteInt(0)
+	return w.Bytes(), nil
+}
+
+// QuestEntry is a started quest in the quest window.
+type QuestEntry struct {
+	QuestID int32
+	Cond    int32
+}
+
+// QuestList sends the started quests of the player.
+//
+// Structure:
+// - byte: opcode (0x80)
+// - int16: count
+// - per quest: int32 quest ID, int32 cond
+type QuestList struct {
+	Quests []QuestEntry
+}
+
+// Write serializes the QuestList packet.
+func (p *QuestList) Write() ([]byte, error) {
+	w := packet.NewWriter(3 + len(p.Quests)*8)
+	if err := w.WriteByte(OpcodeQuestList); err != nil {
+		return nil, err
+	}
+	w.WriteShort(int16(len(p.Quests)))
+	for _, q := range p.Quests {
+		w.WriteInt(q.QuestID)
+		w.WriteInt(q.Cond)
+	}
+	return w.Bytes(), nil
+}
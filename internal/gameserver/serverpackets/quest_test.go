@@ -0,0 +1,34 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestQuestList_Write(t *testing.T) {
+	data, err := (&QuestList{Quests: []QuestEntry{{QuestID: 1, Cond: 2}, {QuestID: 7, Cond: 1}}}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeQuestList, data)
+	testutil.AssertPacketLength(t, 3+2*8, data)
+	if count := int16(data[1]) | int16(data[2])<<8; count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+	testutil.AssertInt32LE(t, 1, data, 3)
+	testutil.AssertInt32LE(t, 2, data, 7)
+	testutil.AssertInt32LE(t, 7, data, 11)
+	testutil.AssertInt32LE(t, 1, data, 15)
+}
+
+func TestNpcHtmlMessage_Write(t *testing.T) {
+	data, err := (&NpcHtmlMessage{NpcObjectID: 100001, HTML: "<html>hi</html>"}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeNpcHtmlMessage, data)
+	testutil.AssertInt32LE(t, 100001, data, 1)
+	testutil.AssertUTF16String(t, "<html>hi</html>", data, 5)
+	testutil.AssertInt32LE(t, 0, data, 5+(len("<html>hi</html>")+1)*2)
+}
@@ -0,0 +1,52 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeStatusUpdate = 0x0E
+
+// StatusUpdate attribute IDs.
+const (
+	StatusLevel = 0x01
+	StatusExp   = 0x02
+	StatusCurHP = 0x09
+	StatusMaxHP = 0x0A
+	StatusCurMP = 0x0B
+	StatusMaxMP = 0x0C
+	StatusSP    = 0x0D
+	StatusKarma = 0x1B
+	StatusCurCP = 0x21
+	StatusMaxCP = 0x22
+)
+
+// StatusAttr is one changed attribute of StatusUpdate.
+type StatusAttr struct {
+	ID    int32
+	Value int32
+}
+
+// StatusUpdate sends changed attributes (HP, MP, ...) of a creature.
+//
+// Structure:
+// - byte: opcode (0x0E)
+// - int32: objectID
+// - int32: attribute count
+// - for each attribute: int32 ID, int32 value
+type StatusUpdate struct {
+	ObjectID uint32
+	Attrs    []StatusAttr
+}
+
+// Write serializes the StatusUpdate packet.
+func (p *StatusUpdate) Write() ([]byte, error) {
+	w := packet.NewWriter(9 + len(p.Attrs)*8)
+	if err := w.WriteByte(OpcodeStatusUpdate); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(int32(len(p.Attrs)))
+	for _, a := range p.Attrs {
+		w.WriteInt(a.ID)
+		w.WriteInt(a.Value)
+	}
+	return w.Bytes(), nil
+}
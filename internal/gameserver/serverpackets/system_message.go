@@ -0,0 +1,59 @@
+package serverpackets
+
+import "github.com/udisondev/la2go/internal/gameserver/packet"
+
+const OpcodeSystemMessage = 0x64
+
+// System message IDs (L2J SystemMessageId).
+const (
+	SystemMessageChattingProhibited = 243  // "Chatting is currently prohibited."
+	SystemMessageNotEnoughAdena     = 279  // "You do not have enough adena."
+	SystemMessageText               = 614  // "$s1": arbitrary text
+	SystemMessageEnteredCombatZone  = 1023 // "You have entered a combat zone."
+	SystemMessageLeftCombatZone     = 1024 // "You have left a combat zone."
+	SystemMessageResurrectRequest   = 1510 // "$s1 is making an attempt to resurrect you ... $s2 experience ..."
+)
+
+// System message parameter types.
+const paramTypeText = 0
+
+// SystemMessage shows a client-side system message with text parameters.
+//
+// Structure:
+// - byte: opcode (0x64)
+// - int32: message ID
+// - int32: parameter count
+// - per parameter: int32 type (0 = text), string value
+type SystemMessage struct {
+	MessageID int32
+	Params    []string
+}
+
+// NewSystemMessageText creates a system message showing text as is.
+func NewSystemMessageText(text string) *SystemMessage {
+	return &SystemMessage{MessageID: SystemMessageText, Params: []string{text}}
+}
+
+// NewSystemMessage creates a system message without parameters.
+func NewSystemMessage(id int32) *SystemMessage {
+	return &SystemMessage{MessageID: id}
+}
+
+// Write serializes the SystemMessage packet.
+func (p *SystemMessage) Write() ([]byte, error) {
+	size := 9
+	for _, s := range p.Params {
+		size += 4 + (len(s)+1)*2
+	}
+	w := packet.NewWriter(size)
+	if err := w.WriteByte(OpcodeSystemMessage); err != nil {
+		return nil, err
+	}
+	w.WriteInt(p.MessageID)
+	w.WriteInt(int32(len(p.Params)))
+	for _, s := range p.Params {
+		w.WriteInt(paramTypeText)
+		w.WriteString(s)
+	}
+	return w.Bytes(), nil
+}
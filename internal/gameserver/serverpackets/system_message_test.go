@@ -0,0 +1,20 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestSystemMessage_Write(t *testing.T) {
+	data, err := NewSystemMessageText("Hello").Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeSystemMessage, data)
+	testutil.AssertInt32LE(t, SystemMessageText, data, 1)
+	testutil.AssertInt32LE(t, 1, data, 5)
+	testutil.AssertInt32LE(t, 0, data, 9)
+	testutil.AssertUTF16String(t, "Hello", data, 13)
+	testutil.AssertPacketLength(t, 13+6*2, data)
+}
@@ -0,0 +1,33 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const OpcodeTeleportToLocation = 0x28
+
+// TeleportToLocation moves a creature to another place at once; clients around the old
+// place drop it, the teleported player reloads the scene.
+//
+// Structure:
+// - byte: opcode (0x28)
+// - int32: objectID
+// - int32: x, y, z
+type TeleportToLocation struct {
+	ObjectID uint32
+	Loc      model.Location
+}
+
+// Write serializes the TeleportToLocation packet.
+func (p *TeleportToLocation) Write() ([]byte, error) {
+	w := packet.NewWriter(17)
+	if err := w.WriteByte(OpcodeTeleportToLocation); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.ObjectID))
+	w.WriteInt(p.Loc.X)
+	w.WriteInt(p.Loc.Y)
+	w.WriteInt(p.Loc.Z)
+	return w.Bytes(), nil
+}
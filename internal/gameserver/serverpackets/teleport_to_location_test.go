@@ -0,0 +1,20 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestTeleportToLocation_Write(t *testing.T) {
+	data, err := (&TeleportToLocation{ObjectID: 100001, Loc: model.NewLocation(-84318, 244579, -3730, 0)}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeTeleportToLocation, data)
+	testutil.AssertPacketLength(t, 17, data)
+	for i, want := range []int32{100001, -84318, 244579, -3730} {
+		testutil.AssertInt32LE(t, want, data, 1+i*4)
+	}
+}
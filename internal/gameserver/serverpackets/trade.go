@@ -0,0 +1,143 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	OpcodeTradeStart        = 0x1E
+	OpcodeTradeOwnAdd       = 0x20
+	OpcodeTradeOtherAdd     = 0x21
+	OpcodeTradeDone         = 0x22
+	OpcodeSendTradeRequest  = 0x5E
+	OpcodeTradePressOwnOk   = 0x75
+	OpcodeTradePressOtherOk = 0x7C
+)
+
+// tradeItemSize is the size of an item block in trade packets.
+const tradeItemSize = 28
+
+// SendTradeRequest asks the target to trade with the requester.
+//
+// Structure:
+// - byte: opcode (0x5E)
+// - int32: requester objectID
+type SendTradeRequest struct {
+	RequesterID uint32
+}
+
+// Write serializes the SendTradeRequest packet.
+func (p *SendTradeRequest) Write() ([]byte, error) {
+	w := packet.NewWriter(5)
+	if err := w.WriteByte(OpcodeSendTradeRequest); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.RequesterID))
+	return w.Bytes(), nil
+}
+
+// TradeStart opens the trade window with the items the player can offer.
+// Item template data (type1, type2, body part) is not loaded yet and is written as zero.
+//
+// Structure:
+// - byte: opcode (0x1E)
+// - int32: partner objectID
+// - int16: item count
+// - for each item: int16 type1, int32 objectID, int32 itemID, int32 count, int16 type2,
+// int16 0, int32 body part, int16 enchant, int16 0, int16 0
+type TradeStart struct {
+	PartnerID uint32
+	Items     []model.TradeItem
+}
+
+// Write serializes the TradeStart packet.
+func (p *TradeStart) Write() ([]byte, error) {
+	w := packet.NewWriter(7 + len(p.Items)*tradeItemSize)
+	if err := w.WriteByte(OpcodeTradeStart); err != nil {
+		return nil, err
+	}
+	w.WriteInt(int32(p.PartnerID))
+	w.WriteShort(int16(len(p.Items)))
+	for _, it := range p.Items {
+		writeTradeItem(w, it)
+	}
+	return w.Bytes(), nil
+}
+
+// TradeAdd shows an item put into the trade window: by the player itself (TradeOwnAdd)
+// or by the partner (TradeOtherAdd).
+//
+// Structure:
+// - byte: opcode (0x20 own, 0x21 partner)
+// - int16: 1
+// - item block as in TradeStart, with the count put into the window
+type TradeAdd struct {
+	Partner bool
+	Item    model.TradeItem
+}
+
+// Write serializes the TradeOwnAdd or TradeOtherAdd packet.
+func (p *TradeAdd) Write() ([]byte, error) {
+	opcode := byte(OpcodeTradeOwnAdd)
+	if p.Partner {
+		opcode = OpcodeTradeOtherAdd
+	}
+	w := packet.NewWriter(3 + tradeItemSize)
+	if err := w.WriteByte(opcode); err != nil {
+		return nil, err
+	}
+	w.WriteShort(1)
+	writeTradeItem(w, p.Item)
+	return w.Bytes(), nil
+}
+
+// TradeDone closes the trade window.
+//
+// Structure:
+// - byte: opcode (0x22)
+// - int32: result (1 = items exchanged, 0 = cancelled)
+type TradeDone struct {
+	Success bool
+}
+
+// Write serializes the TradeDone packet.
+func (p *TradeDone) Write() ([]byte, error) {
+	w := packet.NewWriter(5)
+	if err := w.WriteByte(OpcodeTradeDone); err != nil {
+		return nil, err
+	}
+	w.WriteInt(boolToInt(p.Success))
+	return w.Bytes(), nil
+}
+
+// TradePressOk marks a confirmed side of the trade: the player's own (TradePressOwnOk)
+// or the partner's (TradePressOtherOk).
+//
+// Structure:
+// - byte: opcode (0x75 own, 0x7C partner)
+type TradePressOk struct {
+	Partner bool
+}
+
+// Write serializes the TradePressOwnOk or TradePressOtherOk packet.
+func (p *TradePressOk) Write() ([]byte, error) {
+	if p.Partner {
+		return []byte{OpcodeTradePressOtherOk}, nil
+	}
+	return []byte{OpcodeTradePressOwnOk}, nil
+}
+
+// writeTradeItem writes the item block of trade packets.
+func writeTradeItem(w *packet.Writer, it model.TradeItem) {
+	w.WriteShort(0) // type1
+	w.WriteInt(int32(it.ObjectID))
+	w.WriteInt(it.ItemType)
+	w.WriteInt(it.Count)
+	w.WriteShort(0) // type2
+	w.WriteShort(0)
+	w.WriteInt(0) // body part
+	w.WriteShort(int16(it.Enchant))
+	w.WriteShort(0)
+	w.WriteShort(0)
+}
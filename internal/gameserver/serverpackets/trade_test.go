@@ -0,0 +1,73 @@
+package serverpackets
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/testutil"
+)
+
+func TestSendTradeRequest_Write(t *testing.T) {
+	data, err := (&SendTradeRequest{RequesterID: 0x10000001}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeSendTradeRequest, data)
+	testutil.AssertPacketLength(t, 5, data)
+	testutil.AssertInt32LE(t, 0x10000001, data, 1)
+}
+
+func TestTradeStart_Write(t *testing.T) {
+	data, err := (&TradeStart{
+		PartnerID: 42,
+		Items: []model.TradeItem{
+			{ObjectID: 7, ItemType: 100, Count: 1, Enchant: 3},
+			{ObjectID: 8, ItemType: 17, Count: 500},
+		},
+	}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeTradeStart, data)
+	testutil.AssertPacketLength(t, 7+2*tradeItemSize, data)
+	testutil.AssertInt32LE(t, 42, data, 1)
+	testutil.AssertByteAtOffset(t, 2, data, 5)
+
+	first := 7
+	testutil.AssertInt32LE(t, 7, data, first+2)
+	testutil.AssertInt32LE(t, 100, data, first+6)
+	testutil.AssertInt32LE(t, 1, data, first+10)
+	testutil.AssertByteAtOffset(t, 3, data, first+22)
+	testutil.AssertInt32LE(t, 500, data, first+tradeItemSize+10)
+}
+
+func TestTradeAdd_Write(t *testing.T) {
+	item := model.TradeItem{ObjectID: 7, ItemType: 57, Count: 1000}
+	for _, tt := range []struct {
+		partner bool
+		opcode  byte
+	}{{false, OpcodeTradeOwnAdd}, {true, OpcodeTradeOtherAdd}} {
+		data, err := (&TradeAdd{Partner: tt.partner, Item: item}).Write()
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		testutil.AssertPacketOpcode(t, tt.opcode, data)
+		testutil.AssertPacketLength(t, 3+tradeItemSize, data)
+		testutil.AssertInt32LE(t, 7, data, 5)
+		testutil.AssertInt32LE(t, 1000, data, 13)
+	}
+}
+
+func TestTradeDone_Write(t *testing.T) {
+	data, err := (&TradeDone{Success: true}).Write()
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	testutil.AssertPacketOpcode(t, OpcodeTradeDone, data)
+	testutil.AssertInt32LE(t, 1, data, 1)
+
+	own, _ := (&TradePressOk{}).Write()
+	other, _ := (&TradePressOk{Partner: true}).Write()
+	testutil.AssertBytesEqual(t, []byte{OpcodeTradePressOwnOk}, own, "TradePressOwnOk")
+	testutil.AssertBytesEqual(t, []byte{OpcodeTradePressOtherOk}, other, "TradePressOtherOk")
+}
@@ -0,0 +1,150 @@
+package serverpackets
+
+import (
+	"github.com/udisondev/la2go/internal/gameserver/packet"
+)
+
+const OpcodeUserInfo = 0x04
+
+// defaultInventoryLimit is the Interlude inventory size of non-dwarf characters.
+const defaultInventoryLimit = 80
+
+// UserInfo describes the player to its own client. It carries the CharInfo
+// fields plus what only the owner sees: exp, SP, load and combat stats.
+// Stats the server does not model yet are sent as zeros.
+//
+// Structure (Interlude):
+//   - byte: opcode (0x04)
+//   - int32: x, y, z, heading, objectID; string: name; int32: race, sex, base class
+//   - int32: level; int64: exp; int32 x6: base stats
+//   - int32: max HP, HP, max MP, MP, SP, load, max load, weapon flag
+//   - int32 x17: paperdoll object IDs; int32 x17: paperdoll item IDs; augmentation block
+//   - int32 x10: combat stats; int32: pvp flag, karma; int32 x8: movement speeds
+//   - double: move multiplier, attack speed multiplier, collision radius, collision height
+//   - int32: hair style, hair color, face, GM; string: title
+//   - int32: clan ID, clan crest ID, ally ID, ally crest ID, relation
+//   - byte: mount type, private store type, dwarven craft; int32: PK kills, PvP kills
+//   - int16: cubic count; byte: in party match room; int32: abnormal effect; byte: unknown
+//   - int32: clan privileges; int16: recommendations left, have; int32: mount NPC
+//   - int16: inventory limit; int32: class ID, special effects, max CP, CP
+//   - byte: enchant, team; int32: large crest ID; byte: noble, hero, fishing
+//   - int32 x3: fishing location; int32: name color; byte: running
+//   - int32: pledge class, pledge type, title color, cursed weapon level
+type UserInfo struct {
+	CharInfo
+	GM bool
+}
+
+// Write serializes the UserInfo packet.
+func (p *UserInfo) Write() ([]byte, error) {
+	pl := p.Player
+	loc := pl.Location()
+	w := packet.NewWriter(480 + (len(pl.Name())+len(p.Title)+2)*2)
+	if err := w.WriteByte(OpcodeUserInfo); err != nil {
+		return nil, err
+	}
+
+	w.WriteInt(loc.X)
+	w.WriteInt(loc.Y)
+	w.WriteInt(loc.Z)
+	w.WriteInt(int32(loc.Heading))
+	w.WriteInt(int32(pl.ObjectID()))
+	w.WriteString(pl.Name())
+	w.WriteInt(pl.RaceID())
+	w.WriteInt(0) // sex
+	w.WriteInt(pl.ClassID())
+	w.WriteInt(pl.Level())
+	w.WriteLong(pl.Experience())
+	for range 6 {
+		w.WriteInt(0) // STR, DEX, CON, INT, WIT, MEN
+	}
+	w.WriteInt(pl.MaxHP())
+	w.WriteInt(pl.CurrentHP())
+	w.WriteInt(pl.MaxMP())
+	w.WriteInt(pl.CurrentMP())
+	w.WriteInt(int32(pl.SP()))
+	w.WriteInt(0) // current load
+	w.WriteInt(0) // max load
+
+	weapon := int32(20)
+	if equippedItems(pl)[PaperdollRHand] != 0 {
+		weapon = 40
+	}
+	w.WriteInt(weapon)
+	writeUserPaperdoll(w, pl)
+
+	// Augmentation block: only the weapon slots carry augmentation IDs.
+	for range 14 {
+		w.WriteShort(0)
+	}
+	w.WriteInt(0) // right hand augmentation
+	for range 12 {
+		w.WriteShort(0)
+	}
+	w.WriteInt(0) // two-hand augmentation
+	for range 4 {
+		w.WriteShort(0)
+	}
+
+	for range 10 {
+		w.WriteInt(0) // P.Atk, P.Atk speed, P.Def, evasion, accuracy, critical, M.Atk, casting speed, attack speed, M.Def
+	}
+	w.WriteInt(boolToInt(pl.IsPvPFlagged()))
+	w.WriteInt(pl.Karma())
+	for range 4 {
+		w.WriteInt(defaultRunSpeed)
+		w.WriteInt(defaultWalkSpeed)
+	}
+	w.WriteDouble(1.0) // move multiplier
+	w.WriteDouble(1.0) // attack speed multiplier
+	w.WriteDouble(defaultCollisionRadius)
+	w.WriteDouble(defaultCollisionHeight)
+
+	w.WriteInt(0) // hair style
+	w.WriteInt(0) // hair color
+	w.WriteInt(0) // face
+	w.WriteInt(boolToInt(p.GM))
+	w.WriteString(p.Title)
+
+	w.WriteInt(pl.ClanID())
+	w.WriteInt(p.ClanCrestID)
+	w.WriteInt(p.AllyID)
+	w.WriteInt(p.AllyCrestID)
+	w.WriteInt(0) // relation
+
+	w.WriteBytes([]byte{
+		0, // mount type
+		byte(pl.PrivateStoreType()),
+		0, // dwarven craft
+	})
+	w.WriteInt(pl.PKKills())
+	w.WriteInt(pl.PvPKills())
+	w.WriteShort(0)         // cubics
+	w.WriteBytes([]byte{0}) // in party match room
+	w.WriteInt(0)           // abnormal effect
+	w.WriteBytes([]byte{0})
+	w.WriteInt(0)   // clan privileges
+	w.WriteShort(0) // recommendations left
+	w.WriteShort(0) // recommendations have
+	w.WriteInt(0)   // mount NPC ID
+	w.WriteShort(defaultInventoryLimit)
+	w.WriteInt(pl.ClassID())
+	w.WriteInt(0) // special effects
+	w.WriteInt(pl.MaxCP())
+	w.WriteInt(pl.CurrentCP())
+
+	w.WriteBytes([]byte{0, 0}) // enchant, team
+	w.WriteInt(p.LargeCrestID)
+	w.WriteBytes([]byte{0, 0, 0}) // noble, hero, fishing
+	w.WriteInt(0)                 // fish x
+	w.WriteInt(0)                 // fish y
+	w.WriteInt(0)                 // fish z
+	w.WriteInt(defaultNameColor)
+	w.WriteBytes([]byte{1}) // running
+	w.WriteInt(p.PledgeClass)
+	w.WriteInt(pl.PledgeType())
+	w.WriteInt(defaultTitleColor)
+	w.WriteInt(0) // cursed weapon level
+
+	return w.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+package geo
+
+// NSWE movement flags of a geodata cell: the directions a character may leave the cell in.
+const (
+	East  byte = 1 << 0
+	West  byte = 1 << 1
+	South byte = 1 << 2
+	North byte = 1 << 3
+
+	NSWEAll  = East | West | South | North
+	NSWENone = 0
+)
+
+// block is an 8×8 cell geodata block.
+// Cell coordinates are local to the block (0..BlockCells-1).
+type block interface {
+	// height returns the layer height of the cell nearest to z.
+	height(cx, cy int, z int32) int32
+	// nswe returns movement flags of the cell layer nearest to z.
+	nswe(cx, cy int, z int32) byte
+	// below returns the highest layer of the cell at or below z (ok=false if all layers are above z).
+	below(cx, cy int, z int32) (h int32, ok bool)
+}
+
+func cellIndex(cx, cy int) int {
+	return cx*BlockCells + cy
+}
+
+// decodeCell splits L2J cell data: height in the upper 12 bits (in 8-unit steps), NSWE in the lower 4.
+func decodeCell(data int16) (int32, byte) {
+	return int32(int16(uint16(data)&0xFFF0) >> 1), byte(data & 0x0F)
+}
+
+// flatBlock has a single height for all cells and no movement restrictions.
+type flatBlock struct {
+	h int32
+}
+
+func (b flatBlock) height(_, _ int, _ int32) int32 { return b.h }
+func (b flatBlock) nswe(_, _ int, _ int32) byte    { return NSWEAll }
+
+func (b flatBlock) below(_, _ int, z int32) (int32, bool) {
+	return b.h, b.h <= z
+}
+
+// complexBlock has one layer per cell.
+type complexBlock struct {
+	cells [BlockCells * BlockCells]int16
+}
+
+func (b *complexBlock) height(cx, cy int, _ int32) int32 {
+	h, _ := decodeCell(b.cells[cellIndex(cx, cy)])
+	return h
+}
+
+func (b *complexBlock) nswe(cx, cy int, _ int32) byte {
+	_, nswe := decodeCell(b.cells[cellIndex(cx, cy)])
+	return nswe
+}
+
+func (b *complexBlock) below(cx, cy int, z int32) (int32, bool) {
+	h := b.height(cx, cy, z)
+	return h, h <= z
+}
+
+// multilayerBlock has one or more layers per cell (bridges, buildings, caves).
+type multilayerBlock struct {
+	layers []int16                           // cell data of all cells, cell by cell
+	offset [BlockCells*BlockCells + 1]uint16 // layers of cell i are layers[offset[i]:offset[i+1]]
+}
+
+func (b *multilayerBlock) cell(cx, cy int) []int16 {
+	i := cellIndex(cx, cy)
+	return b.layers[b.offset[i]:b.offset[i+1]]
+}
+
+// nearest returns the layer nearest to z.
+func (b *multilayerBlock) nearest(cx, cy int, z int32) (int32, byte) {
+	var (
+		bestH    int32
+		bestNSWE byte
+		bestDiff int32 = -1
+	)
+	for _, data := range b.cell(cx, cy) {
+		h, nswe := decodeCell(data)
+		diff := h - z
+		if diff < 0 {
+			diff = -diff
+		}
+		if bestDiff < 0 || diff < bestDiff {
+			bestH, bestNSWE, bestDiff = h, nswe, diff
+		}
+	}
+	return bestH, bestNSWE
+}
+
+func (b *multilayerBlock) height(cx, cy int, z int32) int32 {
+	h, _ := b.nearest(cx, cy, z)
+	return h
+}
+
+func (b *multilayerBlock) nswe(cx, cy int, z int32) byte {
+	_, nswe := b.nearest(cx, cy, z)
+	return nswe
+}
+
+func (b *multilayerBlock) below(cx, cy int, z int32) (int32, bool) {
+	var (
+		best  int32
+		found bool
+	)
+	for _, data := range b.cell(cx, cy) {
+		h, _ := decodeCell(data)
+		if h <= z && (!found || h > best) {
+			best, found = h, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,270 @@
+// Package geo answers terrain queries (height, line of sight, walkability)
+// from L2J-format geodata.
+//
+// The world is split into geodata regions of 2048×2048 cells (16 game units each),
+// stored one region per file. A region covers RegionSize = 32768 game units, exactly
+// 16×16 world regions of world.RegionSize. Regions without geodata are passthrough:
+// heights are taken as given and nothing blocks movement or sight.
+package geo
+
+import (
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Geodata grid constants (L2J GeoEngine / World tile layout).
+const (
+	CellSize     = 16                        // game units per cell side
+	BlockCells   = 8                         // cells per block side
+	RegionBlocks = 256                       // blocks per region side
+	RegionCells  = RegionBlocks * BlockCells // 2048 cells per region side
+	RegionSize   = RegionCells * CellSize    // 32768 game units per region side
+
+	// Region file indices ("<x>_<y>.l2j") covering the world.
+	TileXMin = 11
+	TileXMax = 26
+	TileYMin = 10
+	TileYMax = 26
+
+	// Region indices of the tile containing world coordinate 0.
+	tileZeroX = 20
+	tileZeroY = 18
+
+	// World coordinates of the first geodata cell.
+	WorldXMin = (TileXMin - tileZeroX) * RegionSize
+	WorldYMin = (TileYMin - tileZeroY) * RegionSize
+)
+
+const (
+	// MaxClimbHeight is the highest step between neighbour cells a character can walk up.
+	MaxClimbHeight = 48
+
+	// EyeHeight raises line-of-sight rays above the ground so that small bumps do not block sight.
+	EyeHeight = 32
+)
+
+// region is the geodata of one region file.
+type region struct {
+	blocks [RegionBlocks * RegionBlocks]block
+}
+
+// Engine holds loaded geodata. Queries are safe for concurrent use once loading is done.
+type Engine struct {
+	regions [TileXMax - TileXMin + 1][TileYMax - TileYMin + 1]*region
+}
+
+// NewEngine creates an engine without geodata (passthrough everywhere).
+func NewEngine() *Engine {
+	return &Engine{}
+}
+
+// RegionCount returns the number of loaded regions.
+func (e *Engine) RegionCount() int {
+	n := 0
+	for x := range e.regions {
+		for y := range e.regions[x] {
+			if e.regions[x][y] != nil {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func validRegion(rx, ry int) bool {
+	return rx >= TileXMin && rx <= TileXMax && ry >= TileYMin && ry <= TileYMax
+}
+
+// GeoX converts a world X coordinate to a global cell X.
+func GeoX(x int32) int32 {
+	return (x - WorldXMin) / CellSize
+}
+
+// GeoY converts a world Y coordinate to a global cell Y.
+func GeoY(y int32) int32 {
+	return (y - WorldYMin) / CellSize
+}
+
+// WorldX returns the world X of the center of cell gx.
+func WorldX(gx int32) int32 {
+	return gx*CellSize + WorldXMin + CellSize/2
+}
+
+// WorldY returns the world Y of the center of cell gy.
+func WorldY(gy int32) int32 {
+	return gy*CellSize + WorldYMin + CellSize/2
+}
+
+// blockAt returns the block containing global cell (gx, gy) and the local cell coordinates
+// (nil for cells without geodata).
+func (e *Engine) blockAt(gx, gy int32) (block, int, int) {
+	if gx < 0 || gy < 0 {
+		return nil, 0, 0
+	}
+	rx, ry := int(gx/RegionCells), int(gy/RegionCells)
+	if rx >= len(e.regions) || ry >= len(e.regions[0]) {
+		return nil, 0, 0
+	}
+	reg := e.regions[rx][ry]
+	if reg == nil {
+		return nil, 0, 0
+	}
+	bx := int(gx%RegionCells) / BlockCells
+	by := int(gy%RegionCells) / BlockCells
+	return reg.blocks[bx*RegionBlocks+by], int(gx % BlockCells), int(gy % BlockCells)
+}
+
+// HasGeo reports whether geodata is loaded for the world point (x, y).
+func (e *Engine) HasGeo(x, y int32) bool {
+	b, _, _ := e.blockAt(GeoX(x), GeoY(y))
+	return b != nil
+}
+
+// GetHeight returns the ground height at (x, y) nearest to z (z itself without geodata).
+func (e *Engine) GetHeight(x, y, z int32) int32 {
+	return e.cellHeight(GeoX(x), GeoY(y), z)
+}
+
+func (e *Engine) cellHeight(gx, gy, z int32) int32 {
+	b, cx, cy := e.blockAt(gx, gy)
+	if b == nil {
+		return z
+	}
+	return b.height(cx, cy, z)
+}
+
+// NSWE returns movement flags of the cell at (x, y) on the layer nearest to z.
+func (e *Engine) NSWE(x, y, z int32) byte {
+	return e.cellNSWE(GeoX(x), GeoY(y), z)
+}
+
+func (e *Engine) cellNSWE(gx, gy, z int32) byte {
+	b, cx, cy := e.blockAt(gx, gy)
+	if b == nil {
+		return NSWEAll
+	}
+	return b.nswe(cx, cy, z)
+}
+
+// direction returns the NSWE flag of a step (dx, dy) along one axis.
+func direction(dx, dy int32) byte {
+	switch {
+	case dx > 0:
+		return East
+	case dx < 0:
+		return West
+	case dy > 0:
+		return South
+	case dy < 0:
+		return North
+	}
+	return NSWENone
+}
+
+// canStep reports whether a character at cell (gx, gy, z) can step to the neighbour cell
+// (gx+dx, gy+dy) and returns the height there. Diagonal steps must be possible along both
+// axis-aligned detours so that characters cannot cut wall corners.
+func (e *Engine) canStep(gx, gy, z, dx, dy int32) (int32, bool) {
+	if dx != 0 && dy != 0 {
+		zx, okX := e.canStep(gx, gy, z, dx, 0)
+		if okX {
+			_, okX = e.canStep(gx+dx, gy, zx, 0, dy)
+		}
+		zy, okY := e.canStep(gx, gy, z, 0, dy)
+		if okY {
+			_, okY = e.canStep(gx, gy+dy, zy, dx, 0)
+		}
+		if !okX || !okY {
+			return z, false
+		}
+		nz := e.cellHeight(gx+dx, gy+dy, z)
+		return nz, nz-z <= MaxClimbHeight
+	}
+
+	if e.cellNSWE(gx, gy, z)&direction(dx, dy) == 0 {
+		return z, false
+	}
+	nz := e.cellHeight(gx+dx, gy+dy, z)
+	if nz-z > MaxClimbHeight {
+		return z, false
+	}
+	return nz, true
+}
+
+// line walks the cells of a straight line from (x0, y0) to (x1, y1) (Bresenham),
+// calling fn for every step with the previous cell, the step and the step number.
+// Stops and returns false as soon as fn does.
+func line(x0, y0, x1, y1 int32, fn func(gx, gy, dx, dy int32, step, steps int) bool) bool {
+	dx, dy := abs(x1-x0), abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	steps := int(max(dx, dy))
+	err := dx - dy
+
+	x, y := x0, y0
+	for step := 1; x != x1 || y != y1; step++ {
+		var mx, my int32
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			mx = sx
+		}
+		if e2 < dx {
+			err += dx
+			my = sy
+		}
+		if !fn(x, y, mx, my, step, steps) {
+			return false
+		}
+		x += mx
+		y += my
+	}
+	return true
+}
+
+// CanMoveTo reports whether a character can walk in a straight line from one point to another:
+// every step must be allowed by cell NSWE flags and must not climb more than MaxClimbHeight.
+func (e *Engine) CanMoveTo(from, to model.Location) bool {
+	z := e.cellHeight(GeoX(from.X), GeoY(from.Y), from.Z)
+	return line(GeoX(from.X), GeoY(from.Y), GeoX(to.X), GeoY(to.Y), func(gx, gy, dx, dy int32, _, _ int) bool {
+		nz, ok := e.canStep(gx, gy, z, dx, dy)
+		z = nz
+		return ok
+	})
+}
+
+// CanSeeTarget reports whether the straight line between the eyes of characters standing
+// at a and b stays above the ground of every cell in between.
+func (e *Engine) CanSeeTarget(a, b model.Location) bool {
+	gx0, gy0, gx1, gy1 := GeoX(a.X), GeoY(a.Y), GeoX(b.X), GeoY(b.Y)
+	z0 := e.cellHeight(gx0, gy0, a.Z) + EyeHeight
+	z1 := e.cellHeight(gx1, gy1, b.Z) + EyeHeight
+
+	return line(gx0, gy0, gx1, gy1, func(gx, gy, dx, dy int32, step, steps int) bool {
+		if step == steps {
+			return true // target cell
+		}
+		rayZ := z0 + int32(int64(z1-z0)*int64(step)/int64(steps))
+		blk, cx, cy := e.blockAt(gx+dx, gy+dy)
+		if blk == nil {
+			return true
+		}
+		_, ok := blk.below(cx, cy, rayZ)
+		return ok
+	})
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int32) int32 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
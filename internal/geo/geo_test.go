@@ -0,0 +1,303 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// Test geodata lives in region 20_18, whose first cell starts at world (0, 0).
+const (
+	testRegionX = 20
+	testRegionY = 18
+	ground      = -104
+	cliff       = 200
+	bridge      = 400
+)
+
+// encodeCell packs a height (multiple of 8) and NSWE flags into L2J cell data.
+func encodeCell(h int32, nswe byte) uint16 {
+	return uint16(h<<1)&0xFFF0 | uint16(nswe)
+}
+
+// regionBuilder builds a region file of flat ground with some blocks replaced.
+type regionBuilder struct {
+	blocks map[int][]byte
+}
+
+func newRegionBuilder() *regionBuilder {
+	return &regionBuilder{blocks: make(map[int][]byte)}
+}
+
+// complex sets block (bx, by) to a complex block with cell data from fn.
+func (b *regionBuilder) complex(bx, by int, fn func(cx, cy int) uint16) {
+	buf := []byte{blockTypeComplex}
+	for cx := range BlockCells {
+		for cy := range BlockCells {
+			buf = binary.LittleEndian.AppendUint16(buf, fn(cx, cy))
+		}
+	}
+	b.blocks[bx*RegionBlocks+by] = buf
+}
+
+// multilayer sets block (bx, by) to a multilayer block with the same layers in every cell.
+func (b *regionBuilder) multilayer(bx, by int, layers ...uint16) {
+	buf := []byte{blockTypeMultilayer}
+	for range BlockCells * BlockCells {
+		buf = append(buf, byte(len(layers)))
+		for _, l := range layers {
+			buf = binary.LittleEndian.AppendUint16(buf, l)
+		}
+	}
+	b.blocks[bx*RegionBlocks+by] = buf
+}
+
+func (b *regionBuilder) bytes() []byte {
+	var buf bytes.Buffer
+	flat := int16(ground)
+	for i := range RegionBlocks * RegionBlocks {
+		if data, ok := b.blocks[i]; ok {
+			buf.Write(data)
+			continue
+		}
+		buf.WriteByte(blockTypeFlat)
+		buf.Write(binary.LittleEndian.AppendUint16(nil, uint16(flat)))
+	}
+	return buf.Bytes()
+}
+
+// testEngine loads a region with:
+//   - block (0,0): a wall between cell columns 3 and 4;
+//   - block (0,1): a cliff of height 200 from cell row 4;
+//   - block (2,0): a bridge of height 400 over the ground.
+func testEngine(t *testing.T) *Engine {
+	t.Helper()
+	b := newRegionBuilder()
+	b.complex(0, 0, func(cx, cy int) uint16 {
+		nswe := NSWEAll
+		switch cx {
+		case 3:
+			nswe &^= East
+		case 4:
+			nswe &^= West
+		}
+		return encodeCell(ground, nswe)
+	})
+	b.complex(0, 1, func(cx, cy int) uint16 {
+		if cy >= 4 {
+			return encodeCell(cliff, NSWEAll)
+		}
+		return encodeCell(ground, NSWEAll)
+	})
+	b.multilayer(2, 0, encodeCell(ground, NSWEAll), encodeCell(bridge, NSWEAll))
+
+	e := NewEngine()
+	if err := e.LoadRegion(testRegionX, testRegionY, bytes.NewReader(b.bytes())); err != nil {
+		t.Fatalf("LoadRegion: %v", err)
+	}
+	return e
+}
+
+func loc(x, y, z int32) model.Location {
+	return model.NewLocation(x, y, z, 0)
+}
+
+func TestGrid_AlignedWithWorldRegions(t *testing.T) {
+	if RegionSize%world.RegionSize != 0 {
+		t.Errorf("geodata region size %d is not a multiple of world region size %d", RegionSize, world.RegionSize)
+	}
+	if WorldXMin%world.RegionSize != 0 || WorldYMin%world.RegionSize != 0 {
+		t.Errorf("geodata origin (%d, %d) is not aligned with world regions", WorldXMin, WorldYMin)
+	}
+	if WorldXMin > world.WorldXMin || WorldYMin > world.WorldYMin {
+		t.Errorf("geodata origin (%d, %d) does not cover the world", WorldXMin, WorldYMin)
+	}
+	if GeoX(0) != (testRegionX-TileXMin)*RegionCells || GeoY(0) != (testRegionY-TileYMin)*RegionCells {
+		t.Errorf("world origin maps to cell (%d, %d)", GeoX(0), GeoY(0))
+	}
+	if x := WorldX(GeoX(100)); x != 104 {
+		t.Errorf("WorldX(GeoX(100)) = %d, want cell center 104", x)
+	}
+}
+
+func TestEngine_Passthrough(t *testing.T) {
+	e := NewEngine()
+	a, b := loc(1000, 1000, -3000), loc(5000, 3000, 2000)
+
+	if e.HasGeo(a.X, a.Y) {
+		t.Error("empty engine should have no geodata")
+	}
+	if h := e.GetHeight(a.X, a.Y, a.Z); h != a.Z {
+		t.Errorf("GetHeight = %d, want %d", h, a.Z)
+	}
+	if !e.CanMoveTo(a, b) || !e.CanSeeTarget(a, b) {
+		t.Error("nothing should block without geodata")
+	}
+
+	// Outside of loaded regions of a loaded engine, and outside the world
+	e = testEngine(t)
+	if e.HasGeo(-1000, -1000) || e.HasGeo(WorldXMin-100, 0) {
+		t.Error("unexpected geodata outside the loaded region")
+	}
+	if h := e.GetHeight(-1000, -1000, 77); h != 77 {
+		t.Errorf("GetHeight outside region = %d, want 77", h)
+	}
+}
+
+func TestEngine_GetHeight(t *testing.T) {
+	e := testEngine(t)
+
+	tests := []struct {
+		name    string
+		x, y, z int32
+		want    int32
+	}{
+		{"flat", 1000, 1000, 500, ground},
+		{"complex ground", 8, 136, 0, ground},
+		{"complex cliff", 8, 200, 0, cliff},
+		{"under bridge", 300, 50, -90, ground},
+		{"on bridge", 300, 50, 380, bridge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !e.HasGeo(tt.x, tt.y) {
+				t.Fatal("HasGeo = false")
+			}
+			if h := e.GetHeight(tt.x, tt.y, tt.z); h != tt.want {
+				t.Errorf("GetHeight = %d, want %d", h, tt.want)
+			}
+		})
+	}
+
+	if nswe := e.NSWE(56, 8, ground); nswe != NSWEAll&^East {
+		t.Errorf("NSWE of wall cell = %04b", nswe)
+	}
+}
+
+func TestEngine_CanMoveTo(t *testing.T) {
+	e := testEngine(t)
+
+	tests := []struct {
+		name     string
+		from, to model.Location
+		want     bool
+	}{
+		{"open ground", loc(8, 8, ground), loc(40, 100, ground), true},
+		{"through wall", loc(8, 8, ground), loc(120, 8, ground), false},
+		{"through wall backwards", loc(120, 40, ground), loc(8, 40, ground), false},
+		{"diagonal corner cut", loc(56, 56, ground), loc(72, 72, ground), false},
+		{"along wall", loc(56, 8, ground), loc(56, 120, ground), true},
+		{"up the cliff", loc(8, 136, ground), loc(8, 250, ground), false},
+		{"down the cliff", loc(8, 250, cliff), loc(8, 136, cliff), true},
+		{"under the bridge", loc(200, 50, ground), loc(450, 50, ground), true},
+		{"same cell", loc(8, 8, ground), loc(10, 10, ground), true},
+		{"into region without geodata", loc(8, 8, ground), loc(-500, 8, ground), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.CanMoveTo(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanMoveTo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_CanSeeTarget(t *testing.T) {
+	e := testEngine(t)
+
+	tests := []struct {
+		name string
+		a, b model.Location
+		want bool
+	}{
+		{"open ground", loc(1000, 1000, ground), loc(3000, 2500, ground), true},
+		{"wall does not block sight", loc(8, 8, ground), loc(120, 8, ground), true},
+		{"behind the cliff", loc(40, 136, ground), loc(40, 400, ground), false},
+		{"from the cliff top", loc(40, 250, cliff), loc(40, 1000, ground), true},
+		{"under the bridge", loc(200, 50, ground), loc(450, 50, ground), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.CanSeeTarget(tt.a, tt.b); got != tt.want {
+				t.Errorf("CanSeeTarget = %v, want %v", got, tt.want)
+			}
+			if got := e.CanSeeTarget(tt.b, tt.a); got != tt.want {
+				t.Errorf("reverse CanSeeTarget = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_LoadRegionErrors(t *testing.T) {
+	valid := newRegionBuilder().bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing data", append(append([]byte{}, valid...), 0)},
+		{"unknown block type", append([]byte{7}, valid[3:]...)},
+		{"multilayer without layers", append([]byte{blockTypeMultilayer, 0}, valid[3:]...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine()
+			err := e.LoadRegion(testRegionX, testRegionY, bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrInvalidGeodata) {
+				t.Errorf("err = %v, want ErrInvalidGeodata", err)
+			}
+			if e.RegionCount() != 0 {
+				t.Error("invalid region should not be loaded")
+			}
+		})
+	}
+
+	if err := NewEngine().LoadRegion(TileXMax+1, TileYMin, bytes.NewReader(valid)); err == nil {
+		t.Error("region outside the world should be rejected")
+	}
+}
+
+func TestEngine_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "20_18"+FileExtension), newRegionBuilder().bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("not geodata"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken"+FileExtension), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngine()
+	n, err := e.LoadDir(dir)
+	if err != nil || n != 1 {
+		t.Fatalf("LoadDir = %d, %v", n, err)
+	}
+	if !e.HasGeo(100, 100) || e.GetHeight(100, 100, 0) != ground {
+		t.Error("region 20_18 not loaded")
+	}
+
+	// A missing directory disables geodata
+	n, err = NewEngine().LoadDir(filepath.Join(dir, "missing"))
+	if err != nil || n != 0 {
+		t.Errorf("LoadDir(missing) = %d, %v", n, err)
+	}
+
+	// A corrupt region file fails the load
+	if err := os.WriteFile(filepath.Join(dir, "21_18"+FileExtension), []byte{blockTypeFlat}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEngine().LoadDir(dir); err == nil || !strings.Contains(err.Error(), "21_18") {
+		t.Errorf("LoadDir with corrupt file: err = %v", err)
+	}
+}
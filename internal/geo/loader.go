@@ -0,0 +1,149 @@
+package geo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// L2J geodata block types.
+const (
+	blockTypeFlat       = 0
+	blockTypeComplex    = 1
+	blockTypeMultilayer = 2
+)
+
+// FileExtension is the extension of L2J geodata region files ("<regionX>_<regionY>.l2j").
+const FileExtension = ".l2j"
+
+var ErrInvalidGeodata = errors.New("invalid geodata")
+
+// readRegion parses an L2J geodata region: RegionBlocks×RegionBlocks blocks,
+// ordered by block X then block Y, each starting with a type byte.
+func readRegion(r io.Reader) (*region, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	reg := &region{}
+
+	readShort := func() (int16, error) {
+		var b [2]byte
+		if _, err := io.ReadFull(br, b[:]); err != nil {
+			return 0, err
+		}
+		return int16(binary.LittleEndian.Uint16(b[:])), nil
+	}
+
+	for i := range reg.blocks {
+		typ, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: block %d: %v", ErrInvalidGeodata, i, err)
+		}
+
+		switch typ {
+		case blockTypeFlat:
+			h, err := readShort()
+			if err != nil {
+				return nil, fmt.Errorf("%w: flat block %d: %v", ErrInvalidGeodata, i, err)
+			}
+			reg.blocks[i] = flatBlock{h: int32(h)}
+
+		case blockTypeComplex:
+			b := &complexBlock{}
+			for c := range b.cells {
+				if b.cells[c], err = readShort(); err != nil {
+					return nil, fmt.Errorf("%w: complex block %d: %v", ErrInvalidGeodata, i, err)
+				}
+			}
+			reg.blocks[i] = b
+
+		case blockTypeMultilayer:
+			b := &multilayerBlock{layers: make([]int16, 0, BlockCells*BlockCells*2)}
+			for c := range BlockCells * BlockCells {
+				n, err := br.ReadByte()
+				if err != nil {
+					return nil, fmt.Errorf("%w: multilayer block %d: %v", ErrInvalidGeodata, i, err)
+				}
+				if n == 0 {
+					return nil, fmt.Errorf("%w: multilayer block %d cell %d has no layers", ErrInvalidGeodata, i, c)
+				}
+				for range n {
+					data, err := readShort()
+					if err != nil {
+						return nil, fmt.Errorf("%w: multilayer block %d: %v", ErrInvalidGeodata, i, err)
+					}
+					b.layers = append(b.layers, data)
+				}
+				b.offset[c+1] = uint16(len(b.layers))
+			}
+			reg.blocks[i] = b
+
+		default:
+			return nil, fmt.Errorf("%w: block %d has unknown type %d", ErrInvalidGeodata, i, typ)
+		}
+	}
+
+	if _, err := br.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data after %d blocks", ErrInvalidGeodata, len(reg.blocks))
+	}
+	return reg, nil
+}
+
+// LoadRegion loads one region from r, replacing previously loaded data of that region.
+// Not safe to call concurrently with queries: load geodata at startup.
+func (e *Engine) LoadRegion(regionX, regionY int, r io.Reader) error {
+	if !validRegion(regionX, regionY) {
+		return fmt.Errorf("geodata region %d_%d is outside the world", regionX, regionY)
+	}
+	reg, err := readRegion(r)
+	if err != nil {
+		return fmt.Errorf("loading geodata region %d_%d: %w", regionX, regionY, err)
+	}
+	e.regions[regionX-TileXMin][regionY-TileYMin] = reg
+	return nil
+}
+
+// LoadDir loads all region files ("<regionX>_<regionY>.l2j") from dir.
+// A missing directory is not an error: the whole world stays passthrough.
+// Returns the number of loaded regions.
+func (e *Engine) LoadDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		slog.Warn("geodata directory not found, geodata disabled", "dir", dir)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading geodata directory %s: %w", dir, err)
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, FileExtension) {
+			continue
+		}
+		var rx, ry int
+		if _, err := fmt.Sscanf(strings.TrimSuffix(name, FileExtension), "%d_%d", &rx, &ry); err != nil {
+			slog.Warn("skipping geodata file with unexpected name", "file", name)
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return loaded, fmt.Errorf("opening geodata file: %w", err)
+		}
+		err = e.LoadRegion(rx, ry, f)
+		f.Close()
+		if err != nil {
+			return loaded, err
+		}
+		loaded++
+	}
+
+	slog.Info("geodata loaded", "dir", dir, "regions", loaded)
+	return loaded, nil
+}
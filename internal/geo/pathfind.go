@@ -0,0 +1,268 @@
+package geo
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+var (
+	ErrNoPath      = errors.New("no path")
+	ErrNodeLimit   = errors.New("pathfinding node limit reached")
+	ErrPathTimeout = errors.New("pathfinding timed out")
+)
+
+// Step costs of the search: axis-aligned and diagonal moves between neighbour cells.
+const (
+	costStraight = 10
+	costDiagonal = 14
+)
+
+// timeCheckInterval is how often (in expanded nodes) the search checks its deadline.
+const timeCheckInterval = 128
+
+// PathConfig limits a single path search.
+type PathConfig struct {
+	MaxNodes int           // expanded cells per search
+	Timeout  time.Duration // wall time per search
+}
+
+// DefaultPathConfig returns limits that keep a search within a few milliseconds.
+func DefaultPathConfig() PathConfig {
+	return PathConfig{
+		MaxNodes: 20_000,
+		Timeout:  20 * time.Millisecond,
+	}
+}
+
+// Pathfinder finds walkable routes around obstacles with A* over geodata cells.
+// Safe for concurrent use: every search takes its own buffers from a pool.
+type Pathfinder struct {
+	geo      *Engine
+	cfg      PathConfig
+	searches sync.Pool // *search
+}
+
+// NewPathfinder creates a pathfinder over loaded geodata.
+func NewPathfinder(e *Engine, cfg PathConfig) *Pathfinder {
+	return &Pathfinder{
+		geo: e,
+		cfg: cfg,
+		searches: sync.Pool{New: func() any {
+			return &search{index: make(map[uint64]int32)}
+		}},
+	}
+}
+
+// pathNode is a cell visited by the search.
+type pathNode struct {
+	gx, gy, z int32
+	g         int32 // cost from start
+	parent    int32 // index in search.nodes, -1 for start
+	closed    bool
+}
+
+// openItem is an entry of the open list; stale entries are skipped when popped.
+type openItem struct {
+	f   int32 // g + heuristic
+	g   int32
+	idx int32
+}
+
+// less orders the open list by f; on ties the node closer to the target goes first,
+// which avoids expanding every equally good cell of open ground.
+func (a openItem) less(b openItem) bool {
+	return a.f < b.f || a.f == b.f && a.g > b.g
+}
+
+// search holds the reusable buffers of one path search.
+type search struct {
+	nodes []pathNode
+	index map[uint64]int32 // cell key → index in nodes
+	open  []openItem       // binary min-heap by openItem.less
+	cells []pathNode       // reconstructed path, reused between searches
+}
+
+func (s *search) reset() {
+	s.nodes = s.nodes[:0]
+	clear(s.index)
+	s.open = s.open[:0]
+	s.cells = s.cells[:0]
+}
+
+func cellKey(gx, gy, z int32) uint64 {
+	return uint64(uint16(gx))<<32 | uint64(uint16(gy))<<16 | uint64(uint16(int16(z)))
+}
+
+func (s *search) push(it openItem) {
+	s.open = append(s.open, it)
+	i := len(s.open) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !s.open[i].less(s.open[parent]) {
+			break
+		}
+		s.open[parent], s.open[i] = s.open[i], s.open[parent]
+		i = parent
+	}
+}
+
+func (s *search) pop() openItem {
+	top := s.open[0]
+	last := len(s.open) - 1
+	s.open[0] = s.open[last]
+	s.open = s.open[:last]
+	i := 0
+	for {
+		l, r, least := 2*i+1, 2*i+2, i
+		if l < last && s.open[l].less(s.open[least]) {
+			least = l
+		}
+		if r < last && s.open[r].less(s.open[least]) {
+			least = r
+		}
+		if least == i {
+			break
+		}
+		s.open[i], s.open[least] = s.open[least], s.open[i]
+		i = least
+	}
+	return top
+}
+
+// heuristic is the octile distance between cells.
+func heuristic(gx, gy, tx, ty int32) int32 {
+	dx, dy := abs(tx-gx), abs(ty-gy)
+	return costStraight*(dx+dy) + (costDiagonal-2*costStraight)*min(dx, dy)
+}
+
+// neighbours are the eight step directions.
+var neighbours = [8][2]int32{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// FindPath returns waypoints from one point to another, excluding the start and ending at
+// the destination. A straight walkable line gives a single waypoint.
+// Fails with ErrNoPath, ErrNodeLimit or ErrPathTimeout when no route is found within limits.
+func (pf *Pathfinder) FindPath(from, to model.Location) ([]model.Location, error) {
+	e := pf.geo
+	to.Z = e.GetHeight(to.X, to.Y, to.Z)
+	if e.CanMoveTo(from, to) {
+		return []model.Location{to}, nil
+	}
+
+	s := pf.searches.Get().(*search)
+	defer pf.searches.Put(s)
+	s.reset()
+
+	if err := pf.search(s, from, to); err != nil {
+		return nil, err
+	}
+	return pf.smooth(s.cells, from, to), nil
+}
+
+// search runs A* from the cell of from to the cell of to, leaving the cells of the path
+// (start excluded) in s.cells.
+func (pf *Pathfinder) search(s *search, from, to model.Location) error {
+	e := pf.geo
+	start := time.Now()
+	sx, sy := GeoX(from.X), GeoY(from.Y)
+	tx, ty := GeoX(to.X), GeoY(to.Y)
+	sz := e.cellHeight(sx, sy, from.Z)
+
+	s.nodes = append(s.nodes, pathNode{gx: sx, gy: sy, z: sz, parent: -1})
+	s.index[cellKey(sx, sy, sz)] = 0
+	s.push(openItem{f: heuristic(sx, sy, tx, ty), idx: 0})
+
+	expanded := 0
+	for len(s.open) > 0 {
+		it := s.pop()
+		cur := s.nodes[it.idx]
+		if cur.closed {
+			continue
+		}
+		s.nodes[it.idx].closed = true
+
+		if cur.gx == tx && cur.gy == ty && abs(cur.z-to.Z) <= MaxClimbHeight {
+			for i := it.idx; s.nodes[i].parent >= 0; i = s.nodes[i].parent {
+				s.cells = append(s.cells, s.nodes[i])
+			}
+			// Parent links give the path backwards
+			for i, j := 0, len(s.cells)-1; i < j; i, j = i+1, j-1 {
+				s.cells[i], s.cells[j] = s.cells[j], s.cells[i]
+			}
+			return nil
+		}
+
+		expanded++
+		if expanded > pf.cfg.MaxNodes {
+			return ErrNodeLimit
+		}
+		if expanded%timeCheckInterval == 0 && time.Since(start) > pf.cfg.Timeout {
+			return ErrPathTimeout
+		}
+
+		for _, d := range neighbours {
+			nz, ok := e.canStep(cur.gx, cur.gy, cur.z, d[0], d[1])
+			if !ok {
+				continue
+			}
+			nx, ny := cur.gx+d[0], cur.gy+d[1]
+			g := cur.g + costStraight
+			if d[0] != 0 && d[1] != 0 {
+				g = cur.g + costDiagonal
+			}
+
+			key := cellKey(nx, ny, nz)
+			idx, seen := s.index[key]
+			if seen {
+				if s.nodes[idx].closed || s.nodes[idx].g <= g {
+					continue
+				}
+				s.nodes[idx].g = g
+				s.nodes[idx].parent = it.idx
+			} else {
+				idx = int32(len(s.nodes))
+				s.nodes = append(s.nodes, pathNode{gx: nx, gy: ny, z: nz, g: g, parent: it.idx})
+				s.index[key] = idx
+			}
+			s.push(openItem{f: g + heuristic(nx, ny, tx, ty), g: g, idx: idx})
+		}
+	}
+	return ErrNoPath
+}
+
+// smooth turns path cells into waypoints: cells on a straight run are dropped, then every
+// waypoint that can be skipped with a walkable straight line is dropped too.
+func (pf *Pathfinder) smooth(cells []pathNode, from, to model.Location) []model.Location {
+	corners := make([]model.Location, 0, 16)
+	for i := range cells {
+		if i == len(cells)-1 {
+			break // replaced by the exact destination
+		}
+		if i > 0 {
+			prev, next := cells[i-1], cells[i+1]
+			cur := cells[i]
+			if cur.gx-prev.gx == next.gx-cur.gx && cur.gy-prev.gy == next.gy-cur.gy {
+				continue
+			}
+		}
+		c := cells[i]
+		corners = append(corners, model.NewLocation(WorldX(c.gx), WorldY(c.gy), c.z, 0))
+	}
+	corners = append(corners, to)
+
+	path := corners[:0]
+	anchor := from
+	for i := range corners {
+		if i < len(corners)-1 && pf.geo.CanMoveTo(anchor, corners[i+1]) {
+			continue
+		}
+		path = append(path, corners[i])
+		anchor = corners[i]
+	}
+	return path
+}
@@ -0,0 +1,35 @@
+package geo
+
+import "testing"
+
+// BenchmarkPathfinder_FindPath measures a detour around a wall; node buffers are reused
+// between searches, so allocations stay constant per path.
+func BenchmarkPathfinder_FindPath(b *testing.B) {
+	e := walledEngine(b, func(gx, gy int) bool { return gx == 20 && gy < 48 })
+	pf := NewPathfinder(e, DefaultPathConfig())
+	from, to := cellLoc(8, 20), cellLoc(33, 20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := pf.FindPath(from, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPathfinder_FindPathParallel(b *testing.B) {
+	e := walledEngine(b, func(gx, gy int) bool { return gx == 20 && gy < 48 })
+	pf := NewPathfinder(e, DefaultPathConfig())
+	from, to := cellLoc(8, 20), cellLoc(33, 20)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := pf.FindPath(from, to); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package geo
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// testPathConfig has generous limits so that slow test machines do not time out.
+var testPathConfig = PathConfig{MaxNodes: 100_000, Timeout: 5 * time.Second}
+
+// wallHeight is far above MaxClimbHeight: wall cells cannot be entered from the ground.
+const wallHeight = 2000
+
+// walledEngine loads region 20_18 with flat ground and wall cells where wall(gx, gy) is true
+// (cell coordinates local to the region, only the first 16×16 blocks are checked).
+func walledEngine(t testing.TB, wall func(gx, gy int) bool) *Engine {
+	t.Helper()
+	b := newRegionBuilder()
+	for bx := range 16 {
+		for by := range 16 {
+			hasWall := false
+			for c := range BlockCells * BlockCells {
+				if wall(bx*BlockCells+c/BlockCells, by*BlockCells+c%BlockCells) {
+					hasWall = true
+					break
+				}
+			}
+			if !hasWall {
+				continue
+			}
+			b.complex(bx, by, func(cx, cy int) uint16 {
+				if wall(bx*BlockCells+cx, by*BlockCells+cy) {
+					return encodeCell(wallHeight, NSWEAll)
+				}
+				return encodeCell(ground, NSWEAll)
+			})
+		}
+	}
+	e := NewEngine()
+	if err := e.LoadRegion(testRegionX, testRegionY, bytes.NewReader(b.bytes())); err != nil {
+		t.Fatalf("LoadRegion: %v", err)
+	}
+	return e
+}
+
+// cellLoc returns the world location of the center of region-local cell (gx, gy) on the ground.
+func cellLoc(gx, gy int) model.Location {
+	return loc(int32(gx*CellSize+CellSize/2), int32(gy*CellSize+CellSize/2), ground)
+}
+
+// checkPath verifies that every leg of a path is walkable and that it ends at to.
+func checkPath(t *testing.T, e *Engine, from, to model.Location, path []model.Location) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("empty path")
+	}
+	prev := from
+	for i, wp := range path {
+		if !e.CanMoveTo(prev, wp) {
+			t.Errorf("leg %d %v → %v is not walkable", i, prev, wp)
+		}
+		prev = wp
+	}
+	if last := path[len(path)-1]; last.X != to.X || last.Y != to.Y {
+		t.Errorf("path ends at %v, want %v", last, to)
+	}
+}
+
+func TestPathfinder_AroundWall(t *testing.T) {
+	// A wall along cell column 20 from row 0 to row 47
+	e := walledEngine(t, func(gx, gy int) bool { return gx == 20 && gy < 48 })
+	pf := NewPathfinder(e, testPathConfig)
+	from, to := cellLoc(8, 30), cellLoc(33, 30)
+
+	if e.CanMoveTo(from, to) {
+		t.Fatal("wall does not block the straight line")
+	}
+	path, err := pf.FindPath(from, to)
+	if err != nil {
+		t.Fatalf("FindPath: %v", err)
+	}
+	checkPath(t, e, from, to, path)
+	if len(path) > 4 {
+		t.Errorf("path is not smoothed: %d waypoints %v", len(path), path)
+	}
+	for _, wp := range path[:len(path)-1] {
+		if GeoY(wp.Y)-GeoY(0) < 47 {
+			t.Errorf("waypoint %v does not go around the wall end", wp)
+		}
+	}
+}
+
+func TestPathfinder_StraightLine(t *testing.T) {
+	e := walledEngine(t, func(gx, gy int) bool { return false })
+	pf := NewPathfinder(e, testPathConfig)
+	from, to := cellLoc(5, 5), loc(900, 700, 0)
+
+	path, err := pf.FindPath(from, to)
+	if err != nil {
+		t.Fatalf("FindPath: %v", err)
+	}
+	if len(path) != 1 || path[0] != loc(900, 700, ground) {
+		t.Errorf("path = %v, want the destination on the ground", path)
+	}
+
+	// Without geodata everything is a straight line
+	path, err = NewPathfinder(NewEngine(), testPathConfig).FindPath(from, to)
+	if err != nil || len(path) != 1 || path[0] != to {
+		t.Errorf("passthrough path = %v, %v", path, err)
+	}
+}
+
+func TestPathfinder_Limits(t *testing.T) {
+	// Start closed in a box, target outside
+	box := func(gx, gy int) bool {
+		return (gx == 10 || gx == 20) && gy >= 10 && gy <= 20 ||
+			(gy == 10 || gy == 20) && gx >= 10 && gx <= 20
+	}
+	e := walledEngine(t, box)
+	inside, outside := cellLoc(15, 15), cellLoc(40, 40)
+
+	tests := []struct {
+		name     string
+		cfg      PathConfig
+		from, to model.Location
+		want     error
+	}{
+		{"closed box", testPathConfig, inside, outside, ErrNoPath},
+		{"node limit", PathConfig{MaxNodes: 500, Timeout: time.Second}, outside, inside, ErrNodeLimit},
+		{"timeout", PathConfig{MaxNodes: 1 << 30, Timeout: time.Nanosecond}, outside, inside, ErrPathTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPathfinder(e, tt.cfg).FindPath(tt.from, tt.to)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinder_Concurrent(t *testing.T) {
+	e := walledEngine(t, func(gx, gy int) bool { return gx == 20 && gy < 48 })
+	pf := NewPathfinder(e, testPathConfig)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			from, to := cellLoc(8, 10+i), cellLoc(30, 20+i)
+			for range 20 {
+				path, err := pf.FindPath(from, to)
+				if err != nil {
+					t.Errorf("FindPath: %v", err)
+					return
+				}
+				if last := path[len(path)-1]; last.X != to.X || last.Y != to.Y {
+					t.Errorf("path of goroutine %d ends at %v", i, last)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
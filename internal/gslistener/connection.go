@@ -15,6 +15,8 @@ type GSConnection struct {
 	ip         string
 	rsaKeyPair *crypto.RSAKeyPair
 
+	writeMu sync.Mutex // пакеты пишет и цикл чтения, и KickPlayer
+
 	mu             sync.Mutex
 	state          gameserver.GSConnectionState
 	blowfishCipher *crypto.BlowfishCipher
@@ -46,6 +48,14 @@ func NewGSConnection(conn net.Conn, rsaKeyPair *crypto.RSAKeyPair) (*GSConnectio
 	}, nil
 }
 
+// WritePacket шифрует payload из buf и отправляет его GameServer.
+// Безопасно вызывать из разных горутин.
+func (c *GSConnection) WritePacket(buf []byte, payloadLen int) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return WritePacket(c.conn, c.BlowfishCipher(), buf, payloadLen)
+}
+
 // IP returns the remote IP address
 func (c *GSConnection) IP() string {
 	return c.ip
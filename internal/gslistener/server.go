@@ -12,6 +12,7 @@ import (
 	"github.com/udisondev/la2go/internal/constants"
 	"github.com/udisondev/la2go/internal/crypto"
 	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/floodprotect"
 	"github.com/udisondev/la2go/internal/gameserver"
 	"github.com/udisondev/la2go/internal/gslistener/serverpackets"
 	"github.com/udisondev/la2go/internal/login"
@@ -31,6 +32,8 @@ type Server struct {
 	sendPool    *BytePool
 	readPool    *BytePool
 	handler     *Handler
+	flood       *floodprotect.Filter // nil = защита от флуда отключена
+	conns       sync.Map             // *GSConnection → struct{}, подключённые GameServer
 
 	listener net.Listener
 	mu       sync.Mutex
@@ -52,6 +55,10 @@ func NewServer(
 		handler:  NewHandler(database, gsTable, sessionManager),
 	}
 
+	if cfg.FloodProtection {
+		s.flood = floodprotect.NewFilter(floodprotect.ConfigFrom(cfg.FloodProtect))
+	}
+
 	// Pre-generate RSA-512 key pairs
 	slog.Info("generating RSA-512 key pairs for GS listener", "count", rsaKeyPairCount)
 	for i := range rsaKeyPairCount {
@@ -105,6 +112,8 @@ func (s *Server) Run(ctx context.Context) error {
 // Serve принимает готовый listener и запускает accept loop.
 // Используется для тестирования с произвольным listener.
 func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ln = floodprotect.Listen(ln, s.flood)
+
 	// Graceful shutdown
 	go func() {
 		<-ctx.Done()
@@ -173,7 +182,7 @@ func handleConnection(ctx context.Context, srv *Server, conn net.Conn) {
 	// Send InitLS packet — write payload into sendBuf[constants.PacketHeaderSize:], then WritePacket encrypts in-place
 	sendBuf := srv.sendPool.Get(constants.GSListenerSendBufSize)
 	n := serverpackets.InitLS(sendBuf[constants.PacketHeaderSize:], constants.ProtocolRevisionInterlude, rsaKeyPair.ScrambledModulus)
-	if err := WritePacket(conn, gsConn.BlowfishCipher(), sendBuf, n); err != nil {
+	if err := gsConn.WritePacket(sendBuf, n); err != nil {
 		srv.sendPool.Put(sendBuf)
 		slog.Error("failed to send InitLS packet", "err", err, "remote", host)
 		return
@@ -181,6 +190,9 @@ func handleConnection(ctx context.Context, srv *Server, conn net.Conn) {
 	srv.sendPool.Put(sendBuf)
 	slog.Debug("InitLS packet sent", "remote", host)
 
+	srv.conns.Store(gsConn, struct{}{})
+	defer srv.conns.Delete(gsConn)
+
 	// Packet loop
 	for {
 		select {
@@ -230,10 +242,37 @@ func handlePacket(
 	}
 
 	if n > 0 {
-		if err := WritePacket(conn.conn, conn.BlowfishCipher(), sendBuf, n); err != nil {
+		if err := conn.WritePacket(sendBuf, n); err != nil {
 			return false, fmt.Errorf("write packet: %w", err)
 		}
 	}
 
 	return ok, nil
 }
+
+// KickAccount отправляет KickPlayer каждому GameServer, на котором аккаунт в игре.
+// Возвращает false, если аккаунта нет ни на одном сервере (login.AccountKicker).
+func (s *Server) KickAccount(account string) bool {
+	kicked := false
+	s.conns.Range(func(key, _ any) bool {
+		conn := key.(*GSConnection)
+		if conn.State() != gameserver.GSStateAuthed || !conn.HasAccount(account) {
+			return true
+		}
+		kicked = true
+
+		sendBuf := s.sendPool.Get(constants.GSListenerSendBufSize)
+		defer s.sendPool.Put(sendBuf)
+		n := serverpackets.KickPlayer(sendBuf[constants.PacketHeaderSize:], account)
+		if err := conn.WritePacket(sendBuf, n); err != nil {
+			slog.Error("failed to send KickPlayer", "account", account, "remote", conn.IP(), "err", err)
+			return true
+		}
+		// Аккаунт остаётся в списке до PlayerLogout от GameServer
+		if info := conn.GameServerInfo(); info != nil {
+			slog.Info("player kicked from game server", "account", account, "server_id", info.ID())
+		}
+		return true
+	})
+	return kicked
+}
@@ -2,12 +2,14 @@ package gslistener
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/udisondev/la2go/internal/config"
+	"github.com/udisondev/la2go/internal/constants"
 	"github.com/udisondev/la2go/internal/db"
 	"github.com/udisondev/la2go/internal/gameserver"
 	"github.com/udisondev/la2go/internal/login"
@@ -65,3 +67,31 @@ func TestServerRun(t *testing.T) {
 		assert.Equal(t, context.DeadlineExceeded, err)
 	}
 }
+
+func TestServerKickAccount(t *testing.T) {
+	var database *db.DB
+	srv, err := NewServer(config.LoginServer{}, database, gameserver.NewGameServerTable(database), login.NewSessionManager())
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	gsConn, err := NewGSConnection(server, srv.rsaKeyPairs[0])
+	require.NoError(t, err)
+	gsConn.SetState(gameserver.GSStateAuthed)
+	gsConn.AddAccount("hero")
+	srv.conns.Store(gsConn, struct{}{})
+
+	assert.False(t, srv.KickAccount("nobody"), "account not in game")
+
+	kicked := make(chan bool, 1)
+	go func() { kicked <- srv.KickAccount("hero") }()
+
+	buf := make([]byte, constants.GSListenerReadBufSize)
+	data, err := ReadPacket(client, gsConn.BlowfishCipher(), buf)
+	require.NoError(t, err)
+	assert.Equal(t, byte(OpcodeLSKickPlayer), data[0])
+	assert.Equal(t, encodeUTF16LE("hero"), data[1:11])
+	assert.True(t, <-kicked)
+}
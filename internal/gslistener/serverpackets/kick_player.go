@@ -0,0 +1,38 @@
+package serverpackets
+
+import "unicode/utf16"
+
+const (
+	opcodeKickPlayer = 0x04
+)
+
+// KickPlayer [0x04] — LS → GS выгнать игрока аккаунта (повторный вход)
+//
+// Format:
+//
+//	[opcodeKickPlayer]                   // opcode
+//	[account UTF-16LE null-terminated]
+//
+// Returns: number of bytes written to buf
+func KickPlayer(buf []byte, account string) int {
+	pos := 0
+
+	// Opcode
+	buf[pos] = opcodeKickPlayer
+	pos++
+
+	// Account (UTF-16LE null-terminated)
+	accountRunes := utf16.Encode([]rune(account))
+	for _, r := range accountRunes {
+		buf[pos] = byte(r)
+		buf[pos+1] = byte(r >> 8)
+		pos += 2
+	}
+
+	// Null terminator
+	buf[pos] = 0
+	buf[pos+1] = 0
+	pos += 2
+
+	return pos
+}
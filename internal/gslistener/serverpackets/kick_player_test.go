@@ -0,0 +1,16 @@
+package serverpackets
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKickPlayer(t *testing.T) {
+	buf := make([]byte, 64)
+	n := KickPlayer(buf, "hero")
+
+	want := []byte{opcodeKickPlayer, 'h', 0, 'e', 0, 'r', 0, 'o', 0, 0, 0}
+	if !bytes.Equal(buf[:n], want) {
+		t.Errorf("KickPlayer = % x, want % x", buf[:n], want)
+	}
+}
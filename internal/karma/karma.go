@@ -0,0 +1,114 @@
+// Package karma holds the PvP rules: how long attackers stay flagged, how much karma
+// player killers get, how experience works it off and what karma players drop at death.
+package karma
+
+import (
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// weaponSlot is the paperdoll slot of the right hand (L2J PAPERDOLL_RHAND).
+const weaponSlot = 7
+
+// Config holds the PvP rules of a server.
+type Config struct {
+	NormalFlagTime time.Duration // flag of players who attacked a player that was not flagged
+	PvPFlagTime    time.Duration // flag of players who attacked a flagged player
+
+	MinKarma    int32   // karma for the first PK
+	MaxKarma    int32   // most karma a single PK gives
+	XPDivider   int64   // experience that works off one point of karma
+	LostBase    int32   // karma worked off by any experience gain at least
+	AwardPKKill bool    // killing a player with karma counts as PvP
+	ExpLostRate float64 // death exp penalty multiplier of karma players
+
+	PKLimit             int32   // PK count from which karma players drop items at death
+	DropChance          int32   // percent: a karma player drops anything at death
+	ItemDropChance      int32   // percent per item in the inventory
+	EquipmentDropChance int32   // percent per equipped item
+	WeaponDropChance    int32   // percent for the equipped weapon
+	DropLimit           int     // most items dropped at one death
+	NonDroppable        []int32 // item IDs never dropped
+}
+
+// DefaultConfig returns the L2J defaults.
+func DefaultConfig() Config {
+	return Config{
+		NormalFlagTime:      120 * time.Second,
+		PvPFlagTime:         60 * time.Second,
+		MinKarma:            240,
+		MaxKarma:            10000,
+		XPDivider:           260,
+		AwardPKKill:         true,
+		ExpLostRate:         1,
+		PKLimit:             5,
+		DropChance:          40,
+		ItemDropChance:      50,
+		EquipmentDropChance: 40,
+		WeaponDropChance:    10,
+		DropLimit:           10,
+		NonDroppable:        []int32{model.AdenaItemID},
+	}
+}
+
+// FlagTime returns how long attacking a player keeps the attacker flagged.
+func (c Config) FlagTime(targetFlagged bool) time.Duration {
+	if targetFlagged {
+		return c.PvPFlagTime
+	}
+	return c.NormalFlagTime
+}
+
+// Gain returns the karma for a PK: MinKarma multiplied by half the PK count of the killer
+// and by how many times the killer outlevels the victim, capped at MaxKarma.
+func (c Config) Gain(pkKills, killerLevel, victimLevel int32) int32 {
+	karma := int64(c.MinKarma) * int64(max(pkKills/2, 1))
+	if victimLevel > 0 && killerLevel > victimLevel {
+		karma *= int64(killerLevel / victimLevel)
+	}
+	return int32(min(max(karma, int64(c.MinKarma)), int64(c.MaxKarma)))
+}
+
+// Lost returns the karma worked off by exp gained with karma left.
+func (c Config) Lost(karma int32, exp int64) int32 {
+	if karma <= 0 || exp <= 0 || c.XPDivider <= 0 {
+		return 0
+	}
+	lost := max(exp/c.XPDivider, int64(c.LostBase))
+	return int32(min(lost, int64(karma)))
+}
+
+// DeathDrops picks the items a karma player loses at death;
+// nil unless the player has karma and at least PKLimit PKs.
+// The game server does not apply it until items can lie on the ground.
+func (c Config) DeathDrops(p *model.Player, rnd *rand.Rand) []*model.Item {
+	if p.Karma() <= 0 || p.PKKills() < c.PKLimit || rnd.Int32N(100) >= c.DropChance {
+		return nil
+	}
+	var drops []*model.Item
+	for _, item := range p.Inventory().Items() {
+		if len(drops) >= c.DropLimit {
+			break
+		}
+		if slices.Contains(c.NonDroppable, item.ItemType()) {
+			continue
+		}
+		if rnd.Int32N(100) < c.dropChanceOf(item) {
+			drops = append(drops, item)
+		}
+	}
+	return drops
+}
+
+func (c Config) dropChanceOf(item *model.Item) int32 {
+	if !item.IsEquipped() {
+		return c.ItemDropChance
+	}
+	if _, slot := item.Location(); slot == weaponSlot {
+		return c.WeaponDropChance
+	}
+	return c.EquipmentDropChance
+}
@@ -0,0 +1,96 @@
+package karma
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestConfig_Gain(t *testing.T) {
+	c := DefaultConfig()
+	tests := []struct {
+		name                     string
+		pk, killerLvl, victimLvl int32
+		want                     int32
+	}{
+		{"first PK", 0, 40, 40, 240},
+		{"half the PK count", 6, 40, 40, 720},
+		{"outlevels the victim", 0, 60, 20, 720},
+		{"both", 4, 40, 20, 960},
+		{"capped", 200, 80, 10, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Gain(tt.pk, tt.killerLvl, tt.victimLvl); got != tt.want {
+				t.Errorf("Gain(%d, %d, %d) = %d, want %d", tt.pk, tt.killerLvl, tt.victimLvl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Lost(t *testing.T) {
+	c := DefaultConfig()
+	if got := c.Lost(1000, 26000); got != 100 {
+		t.Errorf("Lost(1000, 26000) = %d, want 100", got)
+	}
+	if got := c.Lost(50, 26000); got != 50 {
+		t.Errorf("Lost(50, 26000) = %d, want all 50", got)
+	}
+	if c.Lost(0, 26000) != 0 || c.Lost(1000, -26000) != 0 {
+		t.Error("no karma or no exp gained must work off nothing")
+	}
+}
+
+func TestConfig_FlagTime(t *testing.T) {
+	c := DefaultConfig()
+	if c.FlagTime(false) != 120*time.Second || c.FlagTime(true) != 60*time.Second {
+		t.Errorf("FlagTime = %v / %v", c.FlagTime(false), c.FlagTime(true))
+	}
+}
+
+func TestConfig_DeathDrops(t *testing.T) {
+	p, err := model.NewPlayer(1, 1, "Killer", 40, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	for _, itemType := range []int32{model.AdenaItemID, 17, 1835} {
+		if _, err := p.Inventory().AddByType(itemType, 10); err != nil {
+			t.Fatalf("AddByType: %v", err)
+		}
+	}
+
+	c := DefaultConfig()
+	c.DropChance, c.ItemDropChance = 100, 100
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	p.SetKarma(1000)
+	for range c.PKLimit - 1 {
+		p.AddPKKill(0)
+	}
+	if drops := c.DeathDrops(p, rnd); drops != nil {
+		t.Fatalf("dropped %d items below the PK limit", len(drops))
+	}
+
+	p.AddPKKill(0)
+	drops := c.DeathDrops(p, rnd)
+	if len(drops) != 2 {
+		t.Fatalf("dropped %d items, want 2", len(drops))
+	}
+	for _, item := range drops {
+		if item.ItemType() == model.AdenaItemID {
+			t.Error("adena must not drop")
+		}
+	}
+
+	c.DropLimit = 1
+	if drops := c.DeathDrops(p, rnd); len(drops) != 1 {
+		t.Errorf("dropped %d items over the limit of 1", len(drops))
+	}
+
+	p.SetKarma(0)
+	if drops := c.DeathDrops(p, rnd); drops != nil {
+		t.Error("players without karma drop nothing")
+	}
+}
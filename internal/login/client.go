@@ -19,6 +19,7 @@ type Client struct {
 	state      ConnectionState
 	sessionKey SessionKey
 	account    string
+	closed     bool // соединение закрыто (клиент ушёл или выгнан)
 
 	mu sync.Mutex
 }
@@ -91,6 +92,24 @@ func (c *Client) SetSessionKey(sk SessionKey) {
 	c.sessionKey = sk
 }
 
+// Connected reports whether the client's connection is still open.
+func (c *Client) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil && !c.closed
+}
+
+// Close closes the client's connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
 // RSAKeyPair returns the RSA key pair assigned to this client.
 func (c *Client) RSAKeyPair() *crypto.RSAKeyPair {
 	return c.rsaKeyPair
@@ -0,0 +1,159 @@
+package login
+
+import (
+	"sync"
+	"time"
+)
+
+// failedLoginSweep — как часто из FailedLogins удаляются устаревшие записи.
+const failedLoginSweep = time.Minute
+
+// failures — неудачные попытки входа с одного IP или в один аккаунт.
+type failures struct {
+	count int
+	last  time.Time
+}
+
+// FailedLogins считает неудачные попытки входа и временно блокирует IP
+// (L2J LOGIN_TRY_BEFORE_BAN / LOGIN_BLOCK_AFTER_BAN), а при lockAccounts — и аккаунт.
+// Счётчик сбрасывается при успешном входе или через blockFor после последней ошибки.
+// Thread-safe.
+type FailedLogins struct {
+	maxTries     int // 0 = блокировка отключена
+	blockFor     time.Duration
+	lockAccounts bool
+
+	mu        sync.Mutex
+	byIP      map[string]*failures
+	byAccount map[string]*failures
+	blocked   map[string]time.Time // IP или "account:<login>" → конец блокировки
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+// NewFailedLogins создаёт счётчик: после maxTries ошибок подряд IP блокируется на blockFor.
+func NewFailedLogins(maxTries int, blockFor time.Duration, lockAccounts bool) *FailedLogins {
+	return &FailedLogins{
+		maxTries:     maxTries,
+		blockFor:     blockFor,
+		lockAccounts: lockAccounts,
+		byIP:         make(map[string]*failures),
+		byAccount:    make(map[string]*failures),
+		blocked:      make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+func accountBlockKey(account string) string {
+	return "account:" + account
+}
+
+// Fail засчитывает неудачную попытку входа в account с ip.
+// Возвращает true, если после неё IP (или аккаунт) заблокирован.
+func (f *FailedLogins) Fail(ip, account string) bool {
+	if f.maxTries <= 0 {
+		return false
+	}
+	now := f.now()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sweep(now)
+
+	blocked := false
+	if f.count(f.byIP, ip, now) >= f.maxTries {
+		delete(f.byIP, ip)
+		f.blocked[ip] = now.Add(f.blockFor)
+		blocked = true
+	}
+	if f.lockAccounts && account != "" && f.count(f.byAccount, account, now) >= f.maxTries {
+		delete(f.byAccount, account)
+		f.blocked[accountBlockKey(account)] = now.Add(f.blockFor)
+		blocked = true
+	}
+	return blocked
+}
+
+// count увеличивает счётчик key; ошибки старше blockFor забываются.
+func (f *FailedLogins) count(m map[string]*failures, key string, now time.Time) int {
+	e, ok := m[key]
+	if !ok || now.Sub(e.last) > f.blockFor {
+		e = &failures{}
+		m[key] = e
+	}
+	e.count++
+	e.last = now
+	return e.count
+}
+
+// Success сбрасывает счётчики после успешного входа.
+func (f *FailedLogins) Success(ip, account string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.byIP, ip)
+	delete(f.byAccount, account)
+}
+
+// Blocked сообщает, заблокирован ли IP.
+func (f *FailedLogins) Blocked(ip string) bool {
+	return f.blockedKey(ip)
+}
+
+// AccountBlocked сообщает, заблокирован ли аккаунт (только при lockAccounts).
+func (f *FailedLogins) AccountBlocked(account string) bool {
+	return f.blockedKey(accountBlockKey(account))
+}
+
+func (f *FailedLogins) blockedKey(key string) bool {
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	until, ok := f.blocked[key]
+	if ok && !now.Before(until) {
+		delete(f.blocked, key)
+		return false
+	}
+	return ok
+}
+
+// Unblock снимает блокировку IP до срока и сбрасывает его счётчик (для администраторов).
+// Возвращает false, если IP не был заблокирован.
+func (f *FailedLogins) Unblock(ip string) bool {
+	return f.unblock(ip, f.byIP, ip)
+}
+
+// UnblockAccount снимает блокировку аккаунта до срока.
+func (f *FailedLogins) UnblockAccount(account string) bool {
+	return f.unblock(accountBlockKey(account), f.byAccount, account)
+}
+
+func (f *FailedLogins) unblock(key string, m map[string]*failures, counter string) bool {
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(m, counter)
+	until, ok := f.blocked[key]
+	delete(f.blocked, key)
+	return ok && now.Before(until)
+}
+
+// sweep удаляет истёкшие блокировки и забытые счётчики. Вызывается под f.mu.
+func (f *FailedLogins) sweep(now time.Time) {
+	if now.Sub(f.lastSweep) < failedLoginSweep {
+		return
+	}
+	f.lastSweep = now
+	for key, until := range f.blocked {
+		if !now.Before(until) {
+			delete(f.blocked, key)
+		}
+	}
+	for _, m := range []map[string]*failures{f.byIP, f.byAccount} {
+		for key, e := range m {
+			if now.Sub(e.last) > f.blockFor {
+				delete(m, key)
+			}
+		}
+	}
+}
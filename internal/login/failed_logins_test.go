@@ -0,0 +1,80 @@
+package login
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailedLogins_BlocksIPAfterTries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := NewFailedLogins(3, 15*time.Minute, false)
+	f.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if f.Fail("10.0.0.1", "victim") {
+			t.Fatalf("blocked after %d failures, want 3", i+1)
+		}
+	}
+	if !f.Fail("10.0.0.1", "other") {
+		t.Fatal("third failure did not block the IP")
+	}
+	if !f.Blocked("10.0.0.1") || f.Blocked("10.0.0.2") {
+		t.Error("only 10.0.0.1 should be blocked")
+	}
+	if f.AccountBlocked("victim") {
+		t.Error("account blocked without lockAccounts")
+	}
+
+	// The block ends on its own
+	now = now.Add(15 * time.Minute)
+	if f.Blocked("10.0.0.1") {
+		t.Error("block did not expire")
+	}
+}
+
+func TestFailedLogins_ResetAndUnblock(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := NewFailedLogins(2, time.Minute, true)
+	f.now = func() time.Time { return now }
+
+	f.Fail("10.0.0.1", "alice")
+	f.Success("10.0.0.1", "alice")
+	if f.Fail("10.0.0.1", "alice") {
+		t.Error("success did not reset the counter")
+	}
+
+	// Old failures are forgotten
+	now = now.Add(2 * time.Minute)
+	if f.Fail("10.0.0.1", "alice") {
+		t.Error("failure older than the block time still counted")
+	}
+
+	// Spraying one account from many IPs locks the account
+	f.Fail("10.0.0.2", "bob")
+	if !f.Fail("10.0.0.3", "bob") || !f.AccountBlocked("bob") {
+		t.Fatal("account not locked")
+	}
+	if !f.UnblockAccount("bob") || f.AccountBlocked("bob") {
+		t.Error("UnblockAccount did not lift the lock")
+	}
+
+	f.Fail("10.0.0.1", "carol")
+	if !f.Blocked("10.0.0.1") {
+		t.Fatal("IP not blocked")
+	}
+	if !f.Unblock("10.0.0.1") || f.Blocked("10.0.0.1") {
+		t.Error("Unblock did not lift the block")
+	}
+	if f.Unblock("10.0.0.1") {
+		t.Error("second Unblock reported a block")
+	}
+}
+
+func TestFailedLogins_Disabled(t *testing.T) {
+	f := NewFailedLogins(0, time.Minute, true)
+	for range 10 {
+		if f.Fail("10.0.0.1", "alice") {
+			t.Fatal("blocked with login_try_before_ban = 0")
+		}
+	}
+}
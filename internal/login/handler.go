@@ -7,12 +7,14 @@ import (
 	"log/slog"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/udisondev/la2go/internal/config"
 	"github.com/udisondev/la2go/internal/constants"
 	"github.com/udisondev/la2go/internal/crypto"
-	"github.com/udisondev/la2go/internal/db"
 	"github.com/udisondev/la2go/internal/login/serverpackets"
+	"github.com/udisondev/la2go/internal/passhash"
+	"github.com/udisondev/la2go/internal/punishment"
 )
 
 // Client packet opcodes
@@ -28,6 +30,10 @@ type Handler struct {
 	accounts       AccountRepository
 	cfg            config.LoginServer
 	sessionManager *SessionManager
+	bans           BanChecker // nil = баны не проверяются
+	failedLogins   *FailedLogins
+	passwords      *passhash.Hasher
+	kickers        []AccountKicker
 }
 
 // NewHandler creates a packet handler.
@@ -36,9 +42,24 @@ func NewHandler(accounts AccountRepository, cfg config.LoginServer, sessionManag
 		accounts:       accounts,
 		cfg:            cfg,
 		sessionManager: sessionManager,
+		failedLogins: NewFailedLogins(
+			cfg.LoginTryBeforeBan,
+			time.Duration(cfg.LoginBlockAfterBan)*time.Second,
+			cfg.LoginLockAccounts,
+		),
+		passwords: passhash.NewHasher(passhash.Params{
+			Memory:      uint32(cfg.PasswordHashMemory),
+			Iterations:  uint32(cfg.PasswordHashIterations),
+			Parallelism: uint8(cfg.PasswordHashParallelism),
+		}, cfg.PasswordHashWorkers),
 	}
 }
 
+// FailedLogins возвращает счётчик неудачных попыток входа (для снятия блокировок администратором).
+func (h *Handler) FailedLogins() *FailedLogins {
+	return h.failedLogins
+}
+
 // HandlePacket dispatches a decrypted packet to the appropriate handler.
 // Writes response into buf. Returns: n — bytes written to buf (0 = nothing to send),
 // ok — true if connection stays open (false = close after sending).
@@ -79,6 +100,85 @@ func closeFail(buf []byte, reason byte) (int, bool) {
 	return serverpackets.LoginFail(buf, reason), false
 }
 
+// loginFailed засчитывает неверный логин или пароль и пишет в buf LoginFail.
+func (h *Handler) loginFailed(buf []byte, client *Client, login string) (int, bool) {
+	if h.failedLogins.Fail(client.IP(), login) {
+		slog.Warn("login blocked after failed attempts",
+			"login", login,
+			"client", client.IP(),
+			"tries", h.cfg.LoginTryBeforeBan,
+			"block_seconds", h.cfg.LoginBlockAfterBan)
+	}
+	return closeFail(buf, serverpackets.ReasonUserOrPassWrong)
+}
+
+// rehashPassword переводит пароль аккаунта на текущий формат хеша после успешного входа
+// (устаревший SHA-1 или прежние параметры argon2id). Ошибки не мешают входу.
+func (h *Handler) rehashPassword(ctx context.Context, login, password string) {
+	newHash, err := h.passwords.Hash(ctx, password)
+	if err != nil {
+		slog.Error("failed to rehash password", "login", login, "err", err)
+		return
+	}
+	if err := h.accounts.UpdatePassword(ctx, login, newHash); err != nil {
+		slog.Error("failed to store rehashed password", "login", login, "err", err)
+		return
+	}
+	slog.Info("password rehashed", "login", login)
+}
+
+// kickDuplicate выгоняет прежний вход аккаунта: другого клиента, ещё подключённого
+// к LoginServer, и игрока на игровых серверах. true — аккаунт был занят, новый
+// клиент получает отказ и входит повторно, когда прежняя сессия закроется.
+func (h *Handler) kickDuplicate(client *Client, login string) bool {
+	kicked := false
+	if info, ok := h.sessionManager.Load(login); ok && info.Client != nil && info.Client != client && info.Client.Connected() {
+		slog.Warn("account already on login server, closing the other client",
+			"login", login,
+			"client", client.IP(),
+			"other", info.Client.IP())
+		if err := info.Client.Close(); err != nil {
+			slog.Debug("failed to close login client", "login", login, "err", err)
+		}
+		h.sessionManager.Remove(login)
+		kicked = true
+	}
+	for _, k := range h.kickers {
+		if k.KickAccount(login) {
+			slog.Warn("account already in game, kicking", "login", login, "client", client.IP())
+			kicked = true
+		}
+	}
+	return kicked
+}
+
+// checkBan ищет действующий бан аккаунта или IP и пишет в buf ответ клиенту:
+// LoginFail для IP, AccountKicked для аккаунта.
+func (h *Handler) checkBan(buf []byte, login, ip string) (int, bool) {
+	if h.bans == nil {
+		return 0, false
+	}
+	ban, ok := h.bans.Check(punishment.Ban, login, "", ip)
+	if !ok {
+		return 0, false
+	}
+
+	slog.Warn("login banned",
+		"login", login,
+		"ip", ip,
+		"affect", ban.Affect,
+		"expires", ban.Expires,
+		"reason", ban.Reason)
+	switch {
+	case ban.Affect == punishment.IP:
+		return serverpackets.LoginFail(buf, serverpackets.ReasonRestrictedIP), true
+	case ban.Permanent():
+		return serverpackets.AccountKicked(buf, serverpackets.ReasonPermanentlyBanned), true
+	default:
+		return serverpackets.AccountKicked(buf, serverpackets.Reason7DaysSuspended), true
+	}
+}
+
 // handleAuthGameGuard processes opcode 0x07 in state CONNECTED.
 func handleAuthGameGuard(client *Client, data, buf []byte) (int, bool, error) {
 	if client.State() != StateConnected {
@@ -151,7 +251,16 @@ func (h *Handler) handleRequestAuthLogin(
 
 	slog.Info("auth attempt", "login", login, "client", client.IP())
 
-	passHash := db.HashPassword(password)
+	if h.failedLogins.Blocked(client.IP()) || h.failedLogins.AccountBlocked(login) {
+		slog.Warn("login blocked after failed attempts", "login", login, "client", client.IP())
+		n, ok := closeFail(buf, serverpackets.ReasonAccessFailedTryLater)
+		return n, ok, nil
+	}
+
+	// IP бан проверяется до пароля, чтобы забаненный адрес не мог подбирать пароли
+	if n, banned := h.checkBan(buf, "", client.IP()); banned {
+		return n, false, nil
+	}
 
 	acc, err := h.accounts.GetAccount(ctx, login)
 	if err != nil {
@@ -160,26 +269,45 @@ func (h *Handler) handleRequestAuthLogin(
 		return n, ok, nil
 	}
 
+	var newHash string
 	if acc == nil {
 		if h.cfg.AutoCreateAccounts {
+			newHash, err = h.passwords.Hash(ctx, password)
+			if err != nil {
+				slog.Error("failed to hash password", "err", err, "client", client.IP())
+				n, ok := closeFail(buf, serverpackets.ReasonSystemError)
+				return n, ok, nil
+			}
 			// Атомарная операция: получить существующий или создать новый
 			// Thread-safe: использует INSERT ... ON CONFLICT для защиты от race conditions
-			acc, err = h.accounts.GetOrCreateAccount(ctx, login, passHash, client.IP())
+			acc, err = h.accounts.GetOrCreateAccount(ctx, login, newHash, client.IP())
 			if err != nil {
 				slog.Error("failed to get or create account", "err", err, "client", client.IP())
 				n, ok := closeFail(buf, serverpackets.ReasonSystemError)
 				return n, ok, nil
 			}
 		} else {
-			n, ok := closeFail(buf, serverpackets.ReasonUserOrPassWrong)
+			n, ok := h.loginFailed(buf, client, login)
 			return n, ok, nil
 		}
 	}
 
-	if acc.PasswordHash != passHash {
-		slog.Warn("wrong password", "login", login, "client", client.IP())
-		n, ok := closeFail(buf, serverpackets.ReasonUserOrPassWrong)
-		return n, ok, nil
+	// Только что созданный аккаунт проверять не нужно; если его успел создать
+	// параллельный вход, хеши различаются (разная соль) и пароль проверяется как обычно
+	rehash := false
+	if newHash == "" || acc.PasswordHash != newHash {
+		var match bool
+		match, rehash, err = h.passwords.Verify(ctx, password, acc.PasswordHash)
+		if err != nil {
+			slog.Error("failed to verify password", "login", login, "err", err, "client", client.IP())
+			n, ok := closeFail(buf, serverpackets.ReasonSystemError)
+			return n, ok, nil
+		}
+		if !match {
+			slog.Warn("wrong password", "login", login, "client", client.IP())
+			n, ok := h.loginFailed(buf, client, login)
+			return n, ok, nil
+		}
 	}
 
 	if acc.AccessLevel < 0 {
@@ -188,6 +316,20 @@ func (h *Handler) handleRequestAuthLogin(
 		return n, false, nil
 	}
 
+	if n, banned := h.checkBan(buf, login, client.IP()); banned {
+		return n, false, nil
+	}
+
+	if h.kickDuplicate(client, login) {
+		n, ok := closeFail(buf, serverpackets.ReasonAccountInUse)
+		return n, ok, nil
+	}
+
+	if rehash {
+		h.rehashPassword(ctx, login, password)
+	}
+
+	h.failedLogins.Success(client.IP(), login)
 	client.SetAccount(login)
 	client.SetState(StateAuthedLogin)
 	sk := NewSessionKey()
@@ -0,0 +1,130 @@
+package login
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/config"
+	"github.com/udisondev/la2go/internal/constants"
+	"github.com/udisondev/la2go/internal/crypto"
+	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/login/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/punishment"
+)
+
+// buildAuthLoginPacket шифрует логин и пароль открытым ключом клиента, как это делает клиент L2.
+func buildAuthLoginPacket(t *testing.T, kp *crypto.RSAKeyPair, login, password string) []byte {
+	t.Helper()
+	plain := make([]byte, constants.RSA1024ModulusSize)
+	copy(plain[constants.AuthLoginUsernameOffset:], login)
+	copy(plain[constants.AuthLoginPasswordOffset:], password)
+
+	m := new(big.Int).SetBytes(plain)
+	c := new(big.Int).Exp(m, big.NewInt(int64(kp.PrivateKey.E)), kp.PrivateKey.N)
+	cipher := make([]byte, constants.RSA1024ModulusSize)
+	c.FillBytes(cipher)
+	return append([]byte{OpcodeRequestAuthLogin}, cipher...)
+}
+
+func TestHandler_RequestAuthLogin_Punishments(t *testing.T) {
+	kp, err := crypto.GenerateRSAKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair: %v", err)
+	}
+	mockRepo := &MockAccountRepository{
+		GetAccountFunc: func(_ context.Context, login string) (*model.Account, error) {
+			return &model.Account{Login: login, PasswordHash: db.HashPassword("secret")}, nil
+		},
+		UpdateLastLoginFunc: func(context.Context, string, string) error { return nil },
+	}
+
+	ctx := context.Background()
+	bans := punishment.NewManager(nil)
+	bans.Punish(ctx, punishment.Punishment{Affect: punishment.Account, Key: "forever", Type: punishment.Ban})
+	bans.Punish(ctx, punishment.Punishment{Affect: punishment.Account, Key: "suspended", Type: punishment.Ban, Expires: time.Now().Add(time.Hour)})
+	bans.Punish(ctx, punishment.Punishment{Affect: punishment.IP, Key: "10.0.0.66", Type: punishment.Ban})
+	bans.Punish(ctx, punishment.Punishment{Affect: punishment.Account, Key: "muted", Type: punishment.ChatBan})
+
+	cfg := config.DefaultLoginServer()
+	cfg.ShowLicence = true
+	handler := NewHandler(mockRepo, cfg, NewSessionManager())
+	handler.bans = bans
+
+	tests := []struct {
+		name     string
+		login    string
+		ip       string
+		wantOp   byte
+		wantCode byte
+		wantOpen bool
+	}{
+		{"permanent ban", "forever", "10.0.0.1", serverpackets.AccountKickedOpcode, byte(serverpackets.ReasonPermanentlyBanned), false},
+		{"timed ban", "suspended", "10.0.0.1", serverpackets.AccountKickedOpcode, byte(serverpackets.Reason7DaysSuspended), false},
+		{"banned IP", "player", "10.0.0.66", serverpackets.LoginFailOpcode, serverpackets.ReasonRestrictedIP, false},
+		{"chat ban does not block login", "muted", "10.0.0.1", serverpackets.LoginOkOpcode, 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &Client{sessionID: 1, rsaKeyPair: kp, state: StateAuthedGG, ip: tc.ip}
+			buf := make([]byte, 256)
+			n, ok, err := handler.HandlePacket(ctx, client, buildAuthLoginPacket(t, kp, tc.login, "secret"), buf)
+			if err != nil {
+				t.Fatalf("HandlePacket: %v", err)
+			}
+			if n == 0 || buf[0] != tc.wantOp || ok != tc.wantOpen {
+				t.Fatalf("reply opcode 0x%02X, open %v; want 0x%02X, %v", buf[0], ok, tc.wantOp, tc.wantOpen)
+			}
+			if tc.wantCode != 0 && buf[1] != tc.wantCode {
+				t.Errorf("reason 0x%02X, want 0x%02X", buf[1], tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler_RequestAuthLogin_Lockout(t *testing.T) {
+	kp, err := crypto.GenerateRSAKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair: %v", err)
+	}
+	mockRepo := &MockAccountRepository{
+		GetAccountFunc: func(_ context.Context, login string) (*model.Account, error) {
+			return &model.Account{Login: login, PasswordHash: db.HashPassword("secret")}, nil
+		},
+		UpdateLastLoginFunc: func(context.Context, string, string) error { return nil },
+	}
+	cfg := config.DefaultLoginServer()
+	cfg.ShowLicence = true
+	cfg.LoginTryBeforeBan = 3
+	handler := NewHandler(mockRepo, cfg, NewSessionManager())
+
+	ctx := context.Background()
+	login := func(password string) (byte, byte) {
+		t.Helper()
+		client := &Client{sessionID: 1, rsaKeyPair: kp, state: StateAuthedGG, ip: "10.0.0.7"}
+		buf := make([]byte, 256)
+		if _, _, err := handler.HandlePacket(ctx, client, buildAuthLoginPacket(t, kp, "victim", password), buf); err != nil {
+			t.Fatalf("HandlePacket: %v", err)
+		}
+		return buf[0], buf[1]
+	}
+
+	for range cfg.LoginTryBeforeBan {
+		if op, reason := login("guess"); op != serverpackets.LoginFailOpcode || reason != serverpackets.ReasonUserOrPassWrong {
+			t.Fatalf("wrong password: opcode 0x%02X reason 0x%02X", op, reason)
+		}
+	}
+	// The right password does not help while the IP is blocked
+	if op, reason := login("secret"); op != serverpackets.LoginFailOpcode || reason != serverpackets.ReasonAccessFailedTryLater {
+		t.Fatalf("blocked IP: opcode 0x%02X reason 0x%02X, want AccessFailedTryLater", op, reason)
+	}
+
+	if !handler.FailedLogins().Unblock("10.0.0.7") {
+		t.Fatal("Unblock found no block")
+	}
+	if op, _ := login("secret"); op != serverpackets.LoginOkOpcode {
+		t.Errorf("after Unblock: opcode 0x%02X, want LoginOk", op)
+	}
+}
@@ -0,0 +1,121 @@
+package login
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/config"
+	"github.com/udisondev/la2go/internal/crypto"
+	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/login/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// fakeKicker — игровой сервер, на котором в игре аккаунты online.
+type fakeKicker struct {
+	mu     sync.Mutex
+	online map[string]bool
+	kicked []string
+}
+
+func (k *fakeKicker) KickAccount(account string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if !k.online[account] {
+		return false
+	}
+	k.kicked = append(k.kicked, account)
+	delete(k.online, account) // GameServer ответил PlayerLogout
+	return true
+}
+
+func TestHandler_RequestAuthLogin_DuplicateLogin(t *testing.T) {
+	kp, err := crypto.GenerateRSAKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair: %v", err)
+	}
+	mockRepo := &MockAccountRepository{
+		GetAccountFunc: func(_ context.Context, login string) (*model.Account, error) {
+			return &model.Account{Login: login, PasswordHash: db.HashPassword("secret")}, nil
+		},
+	}
+
+	cfg := config.DefaultLoginServer()
+	cfg.ShowLicence = true
+	cfg.PasswordHashMemory = 64
+	cfg.PasswordHashIterations = 1
+	cfg.PasswordHashParallelism = 1
+	sessions := NewSessionManager()
+	handler := NewHandler(mockRepo, cfg, sessions)
+	kicker := &fakeKicker{online: map[string]bool{"ingame": true}}
+	handler.kickers = append(handler.kickers, kicker)
+
+	ctx := context.Background()
+	login := func(client *Client, account string) (byte, byte, bool) {
+		t.Helper()
+		buf := make([]byte, 256)
+		_, ok, err := handler.HandlePacket(ctx, client, buildAuthLoginPacket(t, kp, account, "secret"), buf)
+		if err != nil {
+			t.Fatalf("HandlePacket: %v", err)
+		}
+		return buf[0], buf[1], ok
+	}
+	newClient := func(conn net.Conn) *Client {
+		return &Client{conn: conn, sessionID: 1, rsaKeyPair: kp, state: StateAuthedGG, ip: "10.0.0.1"}
+	}
+
+	t.Run("other login client is closed", func(t *testing.T) {
+		server, remote := net.Pipe()
+		defer remote.Close()
+		first := newClient(server)
+		if op, _, _ := login(first, "twice"); op != serverpackets.LoginOkOpcode {
+			t.Fatalf("first login: opcode 0x%02X, want LoginOk", op)
+		}
+
+		op, reason, ok := login(newClient(nil), "twice")
+		if op != serverpackets.LoginFailOpcode || reason != serverpackets.ReasonAccountInUse || ok {
+			t.Errorf("second login: opcode 0x%02X reason 0x%02X open %v, want LoginFail AccountInUse and close", op, reason, ok)
+		}
+		if first.Connected() {
+			t.Error("first client is still connected")
+		}
+		if _, err := remote.Read(make([]byte, 1)); err == nil {
+			t.Error("connection of the first client is still open")
+		}
+		if _, ok := sessions.Load("twice"); ok {
+			t.Error("session of the first client was not removed")
+		}
+
+		if op, _, _ := login(newClient(nil), "twice"); op != serverpackets.LoginOkOpcode {
+			t.Errorf("retry: opcode 0x%02X, want LoginOk", op)
+		}
+	})
+
+	t.Run("client that left for the game server is not kicked", func(t *testing.T) {
+		server, remote := net.Pipe()
+		defer remote.Close()
+		first := newClient(server)
+		if op, _, _ := login(first, "moved"); op != serverpackets.LoginOkOpcode {
+			t.Fatalf("first login: opcode 0x%02X, want LoginOk", op)
+		}
+		_ = first.Close()
+		if op, _, _ := login(newClient(nil), "moved"); op != serverpackets.LoginOkOpcode {
+			t.Errorf("second login: opcode 0x%02X, want LoginOk", op)
+		}
+	})
+
+	t.Run("player in game is kicked", func(t *testing.T) {
+		op, reason, _ := login(newClient(nil), "ingame")
+		if op != serverpackets.LoginFailOpcode || reason != serverpackets.ReasonAccountInUse {
+			t.Errorf("login: opcode 0x%02X reason 0x%02X, want LoginFail AccountInUse", op, reason)
+		}
+		if len(kicker.kicked) != 1 || kicker.kicked[0] != "ingame" {
+			t.Errorf("kicked = %v, want [ingame]", kicker.kicked)
+		}
+		if op, _, _ := login(newClient(nil), "ingame"); op != serverpackets.LoginOkOpcode {
+			t.Errorf("retry: opcode 0x%02X, want LoginOk", op)
+		}
+	})
+}
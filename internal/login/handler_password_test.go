@@ -0,0 +1,99 @@
+package login
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/config"
+	"github.com/udisondev/la2go/internal/crypto"
+	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/login/serverpackets"
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/passhash"
+)
+
+func TestHandler_RequestAuthLogin_PasswordHashes(t *testing.T) {
+	kp, err := crypto.GenerateRSAKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair: %v", err)
+	}
+
+	var mu sync.Mutex
+	hashes := map[string]string{"veteran": db.HashPassword("secret")}
+	mockRepo := &MockAccountRepository{
+		GetAccountFunc: func(_ context.Context, login string) (*model.Account, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if hash, ok := hashes[login]; ok {
+				return &model.Account{Login: login, PasswordHash: hash}, nil
+			}
+			return nil, nil
+		},
+		GetOrCreateAccountFunc: func(_ context.Context, login, passwordHash, _ string) (*model.Account, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			hashes[login] = passwordHash
+			return &model.Account{Login: login, PasswordHash: passwordHash}, nil
+		},
+		UpdatePasswordFunc: func(_ context.Context, login, passwordHash string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			hashes[login] = passwordHash
+			return nil
+		},
+	}
+	stored := func(login string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		return hashes[login]
+	}
+
+	cfg := config.DefaultLoginServer()
+	cfg.ShowLicence = true
+	cfg.PasswordHashMemory = 64
+	cfg.PasswordHashIterations = 1
+	cfg.PasswordHashParallelism = 1
+	handler := NewHandler(mockRepo, cfg, NewSessionManager())
+
+	ctx := context.Background()
+	login := func(account, password string) byte {
+		t.Helper()
+		client := &Client{sessionID: 1, rsaKeyPair: kp, state: StateAuthedGG, ip: "10.0.0.1"}
+		buf := make([]byte, 256)
+		if _, _, err := handler.HandlePacket(ctx, client, buildAuthLoginPacket(t, kp, account, password), buf); err != nil {
+			t.Fatalf("HandlePacket: %v", err)
+		}
+		return buf[0]
+	}
+
+	// A legacy SHA-1 account logs in and is moved to argon2id
+	if op := login("veteran", "secret"); op != serverpackets.LoginOkOpcode {
+		t.Fatalf("legacy login: opcode 0x%02X, want LoginOk", op)
+	}
+	migrated := stored("veteran")
+	if passhash.IsLegacy(migrated) || !strings.HasPrefix(migrated, "$argon2id$") {
+		t.Fatalf("legacy hash not migrated: %q", migrated)
+	}
+	if op := login("veteran", "secret"); op != serverpackets.LoginOkOpcode {
+		t.Errorf("login after migration: opcode 0x%02X, want LoginOk", op)
+	}
+	if stored("veteran") != migrated {
+		t.Error("current hash was rehashed again")
+	}
+	if op := login("veteran", "wrong"); op != serverpackets.LoginFailOpcode {
+		t.Errorf("wrong password after migration: opcode 0x%02X, want LoginFail", op)
+	}
+
+	// Auto-created accounts get the new format right away
+	if op := login("newbie", "hunter2"); op != serverpackets.LoginOkOpcode {
+		t.Fatalf("auto-create: opcode 0x%02X, want LoginOk", op)
+	}
+	if hash := stored("newbie"); passhash.IsLegacy(hash) {
+		t.Errorf("auto-created account got a legacy hash %q", hash)
+	}
+	if op := login("newbie", "hunter3"); op != serverpackets.LoginFailOpcode {
+		t.Errorf("wrong password of an auto-created account: opcode 0x%02X, want LoginFail", op)
+	}
+}
@@ -18,6 +18,7 @@ type MockAccountRepository struct {
 	CreateAccountFunc      func(ctx context.Context, login, passwordHash, ip string) error
 	GetOrCreateAccountFunc func(ctx context.Context, login, passwordHash, ip string) (*model.Account, error)
 	UpdateLastLoginFunc    func(ctx context.Context, login, ip string) error
+	UpdatePasswordFunc     func(ctx context.Context, login, passwordHash string) error
 }
 
 func (m *MockAccountRepository) GetAccount(ctx context.Context, login string) (*model.Account, error) {
@@ -53,6 +54,13 @@ func (m *MockAccountRepository) UpdateLastLogin(ctx context.Context, login, ip s
 	return nil
 }
 
+func (m *MockAccountRepository) UpdatePassword(ctx context.Context, login, passwordHash string) error {
+	if m.UpdatePasswordFunc != nil {
+		return m.UpdatePasswordFunc(ctx, login, passwordHash)
+	}
+	return nil
+}
+
 // buildAuthGameGuardPacket создаёт тестовый пакет AuthGameGuard.
 func buildAuthGameGuardPacket(sessionID int32) []byte {
 	packet := make([]byte, 5)
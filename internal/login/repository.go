@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/punishment"
 )
 
 // AccountRepository определяет интерфейс для работы с аккаунтами.
@@ -23,4 +24,20 @@ type AccountRepository interface {
 
 	// UpdateLastLogin обновляет last_active и last_ip при успешном логине.
 	UpdateLastLogin(ctx context.Context, login, ip string) error
+
+	// UpdatePassword заменяет хеш пароля (перехеширование устаревших хешей при входе).
+	UpdatePassword(ctx context.Context, login, passwordHash string) error
+}
+
+// BanChecker проверяет действующие наказания (punishment.Manager).
+type BanChecker interface {
+	// Check возвращает действующее наказание типа t аккаунта, персонажа или IP.
+	Check(t punishment.Type, account, character, ip string) (punishment.Punishment, bool)
+}
+
+// AccountKicker выгоняет аккаунт из игры при повторном входе
+// (gslistener для удалённых GameServer, встроенный GameServer).
+type AccountKicker interface {
+	// KickAccount выгоняет игрока аккаунта; false — аккаунт не в игре.
+	KickAccount(account string) bool
 }
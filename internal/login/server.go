@@ -13,6 +13,7 @@ import (
 	"github.com/udisondev/la2go/internal/constants"
 	"github.com/udisondev/la2go/internal/crypto"
 	"github.com/udisondev/la2go/internal/db"
+	"github.com/udisondev/la2go/internal/floodprotect"
 	"github.com/udisondev/la2go/internal/login/serverpackets"
 	"github.com/udisondev/la2go/internal/protocol"
 )
@@ -21,13 +22,26 @@ const (
 	rsaKeyPairCount = 10
 )
 
+// serverOptions collects the optional dependencies of a Server.
+type serverOptions struct {
+	sessionManager *SessionManager
+	bans           BanChecker
+}
+
 // ServerOption is a functional option for Server configuration.
-type ServerOption func(**SessionManager)
+type ServerOption func(*serverOptions)
 
 // WithSessionManager sets a custom SessionManager (useful for testing with shared SessionManager).
 func WithSessionManager(sm *SessionManager) ServerOption {
-	return func(current **SessionManager) {
-		*current = sm
+	return func(o *serverOptions) {
+		o.sessionManager = sm
+	}
+}
+
+// WithBans rejects logins of banned accounts and IP addresses.
+func WithBans(b BanChecker) ServerOption {
+	return func(o *serverOptions) {
+		o.bans = b
 	}
 }
 
@@ -41,6 +55,7 @@ type Server struct {
 	sendPool    *BytePool
 	readPool    *BytePool
 	handler     *Handler
+	flood       *floodprotect.Filter // nil = flood protection disabled
 
 	listener net.Listener
 	mu       sync.Mutex
@@ -49,14 +64,15 @@ type Server struct {
 // NewServer creates a new LoginServer with pre-generated RSA key pairs.
 // Blowfish keys are generated fresh per connection.
 func NewServer(cfg config.LoginServer, database *db.DB, opts ...ServerOption) (*Server, error) {
-	sessionManager := NewSessionManager()
+	o := serverOptions{sessionManager: NewSessionManager()}
 
 	// Применяем опции
 	for _, opt := range opts {
 		if opt != nil {
-			opt(&sessionManager)
+			opt(&o)
 		}
 	}
+	sessionManager := o.sessionManager
 
 	// Создаём AccountRepository для Handler
 	accountRepo := db.NewPostgresAccountRepository(database.Pool())
@@ -69,6 +85,10 @@ func NewServer(cfg config.LoginServer, database *db.DB, opts ...ServerOption) (*
 		readPool:       NewBytePool(constants.DefaultReadBufSize),
 		handler:        NewHandler(accountRepo, cfg, sessionManager),
 	}
+	s.handler.bans = o.bans
+	if cfg.FloodProtection {
+		s.flood = floodprotect.NewFilter(floodprotect.ConfigFrom(cfg.FloodProtect))
+	}
 
 	// Pre-generate RSA key pairs (expensive operation — ~10-50ms each)
 	slog.Info("generating RSA key pairs", "count", rsaKeyPairCount)
@@ -88,6 +108,12 @@ func (s *Server) SessionManager() *SessionManager {
 	return s.sessionManager
 }
 
+// AddKicker добавляет источник повторных входов: при входе аккаунта, который уже
+// в игре, ему отправляется KickPlayer. Вызывается до Run.
+func (s *Server) AddKicker(k AccountKicker) {
+	s.handler.kickers = append(s.handler.kickers, k)
+}
+
 // generateBlowfishKey creates a fresh 16-byte random Blowfish key.
 func generateBlowfishKey() ([]byte, error) {
 	key := make([]byte, constants.BlowfishKeySize)
@@ -143,6 +169,7 @@ func (s *Server) Run(ctx context.Context) error {
 // Serve принимает готовый listener и запускает accept loop.
 // Используется для тестирования с произвольным listener.
 func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ln = floodprotect.Listen(ln, s.flood)
 	go func() {
 		<-ctx.Done()
 		ln.Close()
@@ -175,6 +202,14 @@ func acceptLoop(
 				slog.Error("Failed to accept new connection", "error", err)
 				continue
 			}
+			// IP, заблокированный после неудачных попыток входа, сразу получает отказ
+			if host, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil && srv.handler.failedLogins.Blocked(host) {
+				slog.Warn("connection from blocked IP", "remote", host)
+				wg.Go(func() {
+					rejectConnection(srv, conn, serverpackets.ReasonAccessFailedTryLater)
+				})
+				continue
+			}
 			wg.Go(func() {
 				handleConnection(ctx, srv, conn)
 			})
@@ -198,48 +233,77 @@ func handleConnection(ctx context.Context, srv *Server, conn net.Conn) {
 
 	slog.Info("new connection", "remote", host)
 
+	client, enc, err := sendInit(srv, conn)
+	if err != nil {
+		slog.Error("failed to start session", "err", err, "remote", host)
+		return
+	}
+	slog.Debug("Init packet sent", "remote", host, "sessionId", client.SessionID())
+	defer client.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if ok, err := handlePacket(ctx, client, enc, srv); !ok {
+				return
+			} else if err != nil {
+				slog.Error("Failed to handle packet", "remote", conn.RemoteAddr(), "error", err)
+			}
+		}
+	}
+}
+
+// sendInit создаёт клиента с новым Blowfish ключом и отправляет ему Init.
+func sendInit(srv *Server, conn net.Conn) (*Client, *crypto.LoginEncryption, error) {
 	rsaKeyPair := srv.rsaKeyPairs[mathrand.IntN(rsaKeyPairCount)]
 	bfKey, err := generateBlowfishKey()
 	if err != nil {
-		slog.Error("failed to generate blowfish key", "err", err, "remote", host)
-		return
+		return nil, nil, err
 	}
 
 	enc, err := crypto.NewLoginEncryption(bfKey)
 	if err != nil {
-		slog.Error("failed to create login encryption", "err", err, "remote", host)
-		return
+		return nil, nil, fmt.Errorf("creating login encryption: %w", err)
 	}
 
 	client, err := NewClient(conn, rsaKeyPair)
 	if err != nil {
-		slog.Error("failed to create client", "err", err, "remote", host)
-		return
+		return nil, nil, fmt.Errorf("creating client: %w", err)
 	}
 
 	sendBuf := srv.sendPool.Get(constants.DefaultSendBufSize)
+	defer srv.sendPool.Put(sendBuf)
 	// Send Init packet — write payload into sendBuf[2:], then WritePacket encrypts in-place
 	n := serverpackets.Init(sendBuf[2:], client.SessionID(), rsaKeyPair.ScrambledModulus, bfKey)
 	if err := protocol.WritePacket(conn, enc, sendBuf, n); err != nil {
-		srv.sendPool.Put(sendBuf)
-		slog.Error("failed to send Init packet", "err", err, "remote", host)
-		return
+		return nil, nil, fmt.Errorf("sending Init packet: %w", err)
 	}
-	srv.sendPool.Put(sendBuf)
-	slog.Debug("Init packet sent", "remote", host, "sessionId", client.SessionID())
+	return client, enc, nil
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			if ok, err := handlePacket(ctx, client, enc, srv); !ok {
-				return
-			} else if err != nil {
-				slog.Error("Failed to handle packet", "remote", conn.RemoteAddr(), "error", err)
-			}
-		}
+// rejectConnection отправляет Init и LoginFail с reason и закрывает соединение:
+// без Init клиент не расшифрует отказ.
+func rejectConnection(srv *Server, conn net.Conn, reason byte) {
+	defer conn.Close()
+
+	_, enc, err := sendInit(srv, conn)
+	if err != nil {
+		slog.Debug("failed to reject connection", "err", err, "remote", conn.RemoteAddr())
+		return
 	}
+	sendBuf := srv.sendPool.Get(constants.DefaultSendBufSize)
+	defer srv.sendPool.Put(sendBuf)
+	n := serverpackets.LoginFail(sendBuf[2:], reason)
+	if err := protocol.WritePacket(conn, enc, sendBuf, n); err != nil {
+		slog.Debug("failed to send LoginFail", "err", err, "remote", conn.RemoteAddr())
+	}
+}
+
+// FailedLogins возвращает счётчик неудачных попыток входа (снятие блокировок IP).
+func (s *Server) FailedLogins() *FailedLogins {
+	return s.handler.FailedLogins()
 }
 
 func handlePacket(
@@ -58,6 +58,15 @@ func (sm *SessionManager) Validate(account string, key SessionKey, showLicence b
 		info.SessionKey.PlayOkID2 == key.PlayOkID2
 }
 
+// Load возвращает сессию аккаунта.
+func (sm *SessionManager) Load(account string) (*SessionInfo, bool) {
+	val, ok := sm.sessions.Load(account)
+	if !ok {
+		return nil, false
+	}
+	return val.(*SessionInfo), true
+}
+
 // Remove удаляет сессию для аккаунта.
 func (sm *SessionManager) Remove(account string) {
 	sm.sessions.Delete(account)
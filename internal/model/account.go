@@ -4,6 +4,7 @@ import "time"
 
 // Account represents a player account stored in the database.
 type Account struct {
+	ID           int64
 	Login        string
 	PasswordHash string
 	AccessLevel  int
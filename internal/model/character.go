@@ -57,6 +57,20 @@ func (c *Character) SetCurrentHP(hp int32) {
 	c.currentHP = hp
 }
 
+// ReduceHP атомарно отнимает damage от текущего HP (не ниже 0) и возвращает новое HP.
+// killed == true только у того вызова, который довёл HP до нуля: при одновременных
+// ударах смерть обрабатывается ровно один раз.
+func (c *Character) ReduceHP(damage int32) (hp int32, killed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.currentHP <= 0 {
+		return 0, false
+	}
+	c.currentHP = max(c.currentHP-max(damage, 0), 0)
+	return c.currentHP, c.currentHP == 0
+}
+
 // SetMaxHP устанавливает максимальное HP и корректирует текущее если нужно.
 func (c *Character) SetMaxHP(maxHP int32) {
 	c.mu.Lock()
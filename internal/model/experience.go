@@ -0,0 +1,38 @@
+package model
+
+// MaxLevel is the highest level a player can reach
+const MaxLevel = 80
+
+// expTable holds the experience a player needs to reach each level (index = level)
+var expTable = [MaxLevel + 1]int64{
+	0,
+	0, 68, 363, 1168, 2884, 6038, 11287, 19423, 31378, 48229,
+	71201, 101676, 141192, 191452, 254327, 331864, 426284, 539995, 675590, 835854,
+	1023775, 1242536, 1495531, 1786365, 2118860, 2497059, 2925229, 3407873, 3949727, 4555766,
+	5231213, 5981539, 6812472, 7729999, 8740372, 9850111, 11066012, 12395149, 13844879, 15422851,
+	17137002, 18995573, 21007103, 23180442, 25524751, 28049509, 30764517, 33679902, 36806124, 40153982,
+	45524945, 51262259, 57383717, 63907474, 70852037, 80700399, 91162274, 102265478, 114038852, 126512280,
+	146274034, 167269164, 189546883, 213157727, 238153554, 264587558, 292514277, 321989604, 353070800, 385816500,
+	420286722, 456542879, 494647786, 534665672, 576662191, 620704427, 666860911, 715201638, 765798069, 818723149,
+}
+
+// ExpForLevel returns the experience a player needs to reach level
+func ExpForLevel(level int32) int64 {
+	return expTable[min(max(level, 1), MaxLevel)]
+}
+
+// LevelForExp returns the level a player with exp experience has
+func LevelForExp(exp int64) int32 {
+	level := int32(1)
+	for level < MaxLevel && exp >= expTable[level+1] {
+		level++
+	}
+	return level
+}
+
+// ExpToNextLevel returns the experience between the start of level and the next one;
+// at MaxLevel it is the width of the last level
+func ExpToNextLevel(level int32) int64 {
+	level = min(max(level, 1), MaxLevel-1)
+	return expTable[level+1] - expTable[level]
+}
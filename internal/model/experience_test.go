@@ -0,0 +1,40 @@
+package model
+
+import "testing"
+
+func TestLevelForExp(t *testing.T) {
+	tests := []struct {
+		exp  int64
+		want int32
+	}{
+		{0, 1},
+		{67, 1},
+		{68, 2},
+		{48229, 10},
+		{48228, 9},
+		{818723149, MaxLevel},
+		{1 << 40, MaxLevel},
+	}
+	for _, tt := range tests {
+		if got := LevelForExp(tt.exp); got != tt.want {
+			t.Errorf("LevelForExp(%d) = %d, want %d", tt.exp, got, tt.want)
+		}
+	}
+}
+
+func TestExpForLevel(t *testing.T) {
+	for level := int32(1); level <= MaxLevel; level++ {
+		if got := LevelForExp(ExpForLevel(level)); got != level {
+			t.Errorf("LevelForExp(ExpForLevel(%d)) = %d", level, got)
+		}
+	}
+	if ExpForLevel(0) != 0 || ExpForLevel(MaxLevel+1) != ExpForLevel(MaxLevel) {
+		t.Error("ExpForLevel must clamp levels out of range")
+	}
+	if ExpToNextLevel(10) != 71201-48229 {
+		t.Errorf("ExpToNextLevel(10) = %d", ExpToNextLevel(10))
+	}
+	if ExpToNextLevel(MaxLevel) != ExpToNextLevel(MaxLevel-1) {
+		t.Error("ExpToNextLevel(MaxLevel) must be the width of the last level")
+	}
+}
@@ -0,0 +1,437 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// AdenaItemID — item type адены (игровая валюта).
+const AdenaItemID int32 = 57
+
+// ItemIDSource выдаёт ID предметам, созданным в памяти (новый стак, деление стака).
+// ID сразу постоянный: SyncInventory сохраняет предмет под ним же.
+type ItemIDSource interface {
+	NextItemID() (int64, error)
+}
+
+// memoryItemIDs — источник ID без БД (тесты): стартует высоко, чтобы не
+// пересекаться с загруженными предметами.
+type memoryItemIDs struct {
+	next atomic.Int64
+}
+
+func (m *memoryItemIDs) NextItemID() (int64, error) {
+	return m.next.Add(1), nil
+}
+
+var itemIDSource atomic.Pointer[ItemIDSource]
+
+func init() {
+	mem := &memoryItemIDs{}
+	mem.next.Store(1 << 30)
+	SetItemIDSource(mem)
+}
+
+// SetItemIDSource задаёт источник ID новых предметов; в игре — последовательность
+// items.item_id (db.ItemIDAllocator). Вызывается при старте до входа игроков.
+func SetItemIDSource(src ItemIDSource) {
+	itemIDSource.Store(&src)
+}
+
+// newItemID выдаёт ID новому предмету.
+func newItemID() (int64, error) {
+	id, err := (*itemIDSource.Load()).NextItemID()
+	if err != nil {
+		return 0, fmt.Errorf("allocating item id: %w", err)
+	}
+	return id, nil
+}
+
+// Inventory — предметы игрока в памяти.
+// Все операции thread-safe; TransferTo атомарна относительно обоих инвентарей.
+type Inventory struct {
+	ownerID int64
+
+	mu    sync.RWMutex
+	items []*Item
+}
+
+// NewInventory создаёт пустой инвентарь для владельца.
+func NewInventory(ownerID int64) *Inventory {
+	return &Inventory{
+		ownerID: ownerID,
+		items:   make([]*Item, 0, 16),
+	}
+}
+
+// OwnerID возвращает ID владельца инвентаря.
+func (inv *Inventory) OwnerID() int64 {
+	return inv.ownerID
+}
+
+// Items возвращает копию списка предметов.
+func (inv *Inventory) Items() []*Item {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	items := make([]*Item, len(inv.items))
+	copy(items, inv.items)
+	return items
+}
+
+// Size возвращает количество предметов (стаков) в инвентаре.
+func (inv *Inventory) Size() int {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return len(inv.items)
+}
+
+// AddItem добавляет предмет в инвентарь и делает инвентарь его владельцем.
+func (inv *Inventory) AddItem(item *Item) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.addLocked(item)
+}
+
+// AddByType добавляет count штук предмета itemType: сливает с существующим стаком
+// того же типа или создаёт новый предмет с ID из ItemIDSource.
+func (inv *Inventory) AddByType(itemType int32, count int32) (*Item, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if item := inv.findTypeLocked(itemType); item != nil && !item.IsEquipped() {
+		if err := item.AddCount(count); err != nil {
+			return nil, fmt.Errorf("merging stack: %w", err)
+		}
+		return item, nil
+	}
+
+	item, err := NewItem(inv.ownerID, itemType, count)
+	if err != nil {
+		return nil, fmt.Errorf("creating item: %w", err)
+	}
+	id, err := newItemID()
+	if err != nil {
+		return nil, err
+	}
+	item.SetItemID(id)
+	inv.addLocked(item)
+	return item, nil
+}
+
+// RemoveItem удаляет предмет из инвентаря по objectID.
+// Возвращает удалённый предмет или nil.
+func (inv *Inventory) RemoveItem(objectID uint32) *Item {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.removeLocked(objectID)
+}
+
+// ItemByObjectID возвращает предмет по objectID (nil если не найден).
+func (inv *Inventory) ItemByObjectID(objectID uint32) *Item {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return inv.findLocked(objectID)
+}
+
+// ItemByType возвращает первый стак предмета данного типа (nil если нет).
+func (inv *Inventory) ItemByType(itemType int32) *Item {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return inv.findTypeLocked(itemType)
+}
+
+// CountOf возвращает суммарное количество предметов данного типа.
+func (inv *Inventory) CountOf(itemType int32) int64 {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	var total int64
+	for _, item := range inv.items {
+		if item.ItemType() == itemType {
+			total += int64(item.Count())
+		}
+	}
+	return total
+}
+
+// Adena возвращает количество адены.
+func (inv *Inventory) Adena() int64 {
+	return inv.CountOf(AdenaItemID)
+}
+
+// DestroyByType уничтожает count штук предметов данного типа (из нескольких стаков при необходимости).
+// Если предметов недостаточно, инвентарь не изменяется.
+func (inv *Inventory) DestroyByType(itemType int32, count int64) error {
+	if count <= 0 {
+		return fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	var have int64
+	for _, item := range inv.items {
+		if item.ItemType() == itemType && !item.IsEquipped() {
+			have += int64(item.Count())
+		}
+	}
+	if have < count {
+		return fmt.Errorf("not enough items of type %d: have %d, want %d", itemType, have, count)
+	}
+
+	for count > 0 {
+		var item *Item
+		for _, it := range inv.items {
+			if it.ItemType() == itemType && !it.IsEquipped() {
+				item = it
+				break
+			}
+		}
+		n := int64(item.Count())
+		if n > count {
+			if err := item.AddCount(int32(-count)); err != nil {
+				return fmt.Errorf("reducing stack: %w", err)
+			}
+			return nil
+		}
+		inv.removeLocked(item.ObjectID())
+		count -= n
+	}
+	return nil
+}
+
+// TransferTo переносит count штук предмета objectID в dst.
+// Стак делится если count меньше размера стака; стакуемые предметы
+// сливаются с существующим стаком того же типа у получателя.
+// Оба инвентаря блокируются в порядке ownerID (защита от deadlock).
+// Возвращает предмет в инвентаре получателя.
+func (inv *Inventory) TransferTo(dst *Inventory, objectID uint32, count int32) (*Item, error) {
+	if inv == dst {
+		return nil, fmt.Errorf("transfer to the same inventory")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	unlock := lockPair(inv, dst)
+	defer unlock()
+
+	item := inv.findLocked(objectID)
+	if item == nil {
+		return nil, fmt.Errorf("item %d not found in inventory of %d", objectID, inv.ownerID)
+	}
+	return inv.transferLocked(dst, item, count, newItemID)
+}
+
+// TransferByType переносит count штук предметов данного типа (например адену) в dst.
+func (inv *Inventory) TransferByType(dst *Inventory, itemType int32, count int32) (*Item, error) {
+	if inv == dst {
+		return nil, fmt.Errorf("transfer to the same inventory")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	unlock := lockPair(inv, dst)
+	defer unlock()
+
+	item := inv.findTypeLocked(itemType)
+	if item == nil {
+		return nil, fmt.Errorf("item type %d not found in inventory of %d", itemType, inv.ownerID)
+	}
+	return inv.transferLocked(dst, item, count, newItemID)
+}
+
+// ItemTransfer — перенос предмета в Exchange. ObjectID != 0 указывает предмет,
+// иначе переносится первый стак типа ItemType (адена).
+type ItemTransfer struct {
+	From, To *Inventory
+	ObjectID uint32
+	ItemType int32
+	Count    int32
+}
+
+// Exchange атомарно выполняет переносы между инвентарями a и b (сделки игроков):
+// под блокировкой обоих сначала проверяются все переносы — наличие предметов,
+// экипировка, переполнение стаков получателя, — и только затем предметы переносятся.
+// При ошибке проверки ни один инвентарь не меняется.
+// ID для деления стаков выделяются заранее, по одному на перенос; лишние
+// просто пропадают (пропуски в последовательности допустимы).
+func Exchange(a, b *Inventory, transfers []ItemTransfer) error {
+	if a == b {
+		return fmt.Errorf("exchange within the same inventory")
+	}
+	ids := make([]int64, 0, len(transfers))
+	for range transfers {
+		id, err := newItemID()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	nextID := func() (int64, error) {
+		id := ids[0]
+		ids = ids[1:]
+		return id, nil
+	}
+
+	unlock := lockPair(a, b)
+	defer unlock()
+
+	items := make([]*Item, len(transfers))
+	taken := make(map[*Item]int64, len(transfers))
+	incoming := make(map[*Inventory]map[int32]int64, 2)
+	for i, t := range transfers {
+		if t.From == t.To || (t.From != a && t.From != b) || (t.To != a && t.To != b) {
+			return fmt.Errorf("transfer %d: inventories are not the exchanging pair", i)
+		}
+		if t.Count <= 0 {
+			return fmt.Errorf("transfer %d: count must be positive, got %d", i, t.Count)
+		}
+
+		var item *Item
+		if t.ObjectID != 0 {
+			item = t.From.findLocked(t.ObjectID)
+		} else {
+			item = t.From.findTypeLocked(t.ItemType)
+		}
+		if item == nil {
+			return fmt.Errorf("transfer %d: item %d (type %d) not found in inventory of %d", i, t.ObjectID, t.ItemType, t.From.ownerID)
+		}
+		if item.IsEquipped() {
+			return fmt.Errorf("transfer %d: item %d is equipped", i, item.ObjectID())
+		}
+		taken[item] += int64(t.Count)
+		if taken[item] > int64(item.Count()) {
+			return fmt.Errorf("transfer %d: not enough items: have %d, want %d", i, item.Count(), taken[item])
+		}
+
+		in := incoming[t.To]
+		if in == nil {
+			in = make(map[int32]int64)
+			incoming[t.To] = in
+		}
+		in[item.ItemType()] += int64(t.Count)
+		var have int64
+		if target := t.To.findTypeLocked(item.ItemType()); target != nil {
+			have = int64(target.Count())
+		}
+		if have+in[item.ItemType()] > math.MaxInt32 {
+			return fmt.Errorf("transfer %d: %w: item type %d in inventory of %d", i, ErrCountOverflow, item.ItemType(), t.To.ownerID)
+		}
+		items[i] = item
+	}
+
+	for i, t := range transfers {
+		if _, err := t.From.transferLocked(t.To, items[i], t.Count, nextID); err != nil {
+			return fmt.Errorf("transfer %d after validation: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// transferLocked выполняет перенос; оба инвентаря уже заблокированы.
+// allocID выдаёт ID новому предмету при делении стака.
+func (inv *Inventory) transferLocked(dst *Inventory, item *Item, count int32, allocID func() (int64, error)) (*Item, error) {
+	have := item.Count()
+	if count > have {
+		return nil, fmt.Errorf("not enough items: have %d, want %d", have, count)
+	}
+	if item.IsEquipped() {
+		return nil, fmt.Errorf("item %d is equipped", item.ObjectID())
+	}
+
+	stackable := have > 1 || item.ItemType() == AdenaItemID
+	target := dst.findTypeLocked(item.ItemType())
+
+	// Слияние со стаком получателя: сначала получатель (может переполниться),
+	// затем источник — при ошибке предметы не теряются
+	if stackable && target != nil && !target.IsEquipped() {
+		if err := target.AddCount(count); err != nil {
+			return nil, fmt.Errorf("merging stack: %w", err)
+		}
+		if count == have {
+			inv.removeLocked(item.ObjectID())
+		} else if err := item.AddCount(-count); err != nil {
+			return nil, fmt.Errorf("splitting stack: %w", err)
+		}
+		return target, nil
+	}
+
+	// Перенос целого предмета
+	if count == have {
+		inv.removeLocked(item.ObjectID())
+		dst.addLocked(item)
+		return item, nil
+	}
+
+	// Деление стака: новый предмет для получателя
+	split, err := NewItem(dst.ownerID, item.ItemType(), count)
+	if err != nil {
+		return nil, fmt.Errorf("creating split item: %w", err)
+	}
+	id, err := allocID()
+	if err != nil {
+		return nil, err
+	}
+	split.SetItemID(id)
+	_ = split.SetEnchant(item.Enchant())
+	if err := item.AddCount(-count); err != nil {
+		return nil, fmt.Errorf("splitting stack: %w", err)
+	}
+	dst.addLocked(split)
+	return split, nil
+}
+
+func (inv *Inventory) addLocked(item *Item) {
+	item.SetOwnerID(inv.ownerID)
+	inv.items = append(inv.items, item)
+}
+
+func (inv *Inventory) removeLocked(objectID uint32) *Item {
+	for i, item := range inv.items {
+		if item.ObjectID() == objectID {
+			inv.items = append(inv.items[:i], inv.items[i+1:]...)
+			return item
+		}
+	}
+	return nil
+}
+
+func (inv *Inventory) findLocked(objectID uint32) *Item {
+	for _, item := range inv.items {
+		if item.ObjectID() == objectID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (inv *Inventory) findTypeLocked(itemType int32) *Item {
+	for _, item := range inv.items {
+		if item.ItemType() == itemType {
+			return item
+		}
+	}
+	return nil
+}
+
+// lockPair блокирует два инвентаря в детерминированном порядке (по ownerID).
+func lockPair(a, b *Inventory) func() {
+	first, second := a, b
+	if b.ownerID < a.ownerID {
+		first, second = b, a
+	}
+	first.mu.Lock()
+	second.mu.Lock()
+	return func() {
+		second.mu.Unlock()
+		first.mu.Unlock()
+	}
+}
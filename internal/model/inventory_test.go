@@ -0,0 +1,314 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+func mustItem(t *testing.T, ownerID int64, itemID int64, itemType, count int32) *Item {
+	t.Helper()
+	item, err := NewItem(ownerID, itemType, count)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	item.SetItemID(itemID)
+	return item
+}
+
+func TestInventory_AddRemove(t *testing.T) {
+	inv := NewInventory(1)
+	item := mustItem(t, 99, 10, 100, 1)
+
+	inv.AddItem(item)
+	if item.OwnerID() != 1 {
+		t.Errorf("OwnerID() = %d, want 1", item.OwnerID())
+	}
+	if got := inv.ItemByObjectID(10); got != item {
+		t.Errorf("ItemByObjectID(10) = %v, want %v", got, item)
+	}
+	if got := inv.ItemByType(100); got != item {
+		t.Errorf("ItemByType(100) = %v, want %v", got, item)
+	}
+
+	if removed := inv.RemoveItem(10); removed != item {
+		t.Errorf("RemoveItem(10) = %v, want %v", removed, item)
+	}
+	if inv.Size() != 0 {
+		t.Errorf("Size() = %d, want 0", inv.Size())
+	}
+	if removed := inv.RemoveItem(10); removed != nil {
+		t.Errorf("RemoveItem on missing item = %v, want nil", removed)
+	}
+}
+
+func TestInventory_AddByType(t *testing.T) {
+	inv := NewInventory(1)
+
+	first, err := inv.AddByType(1060, 3)
+	if err != nil {
+		t.Fatalf("AddByType: %v", err)
+	}
+	if first.ObjectID() == 0 || first.OwnerID() != 1 {
+		t.Errorf("new item: objectID=%d owner=%d", first.ObjectID(), first.OwnerID())
+	}
+
+	second, err := inv.AddByType(1060, 2)
+	if err != nil {
+		t.Fatalf("AddByType: %v", err)
+	}
+	if second != first || inv.CountOf(1060) != 5 || inv.Size() != 1 {
+		t.Errorf("stack not merged: size=%d count=%d", inv.Size(), inv.CountOf(1060))
+	}
+
+	if _, err := inv.AddByType(1060, 0); err == nil {
+		t.Error("expected error for zero count")
+	}
+}
+
+func TestInventory_Adena(t *testing.T) {
+	inv := NewInventory(1)
+	inv.AddItem(mustItem(t, 1, 1, AdenaItemID, 500))
+	inv.AddItem(mustItem(t, 1, 2, AdenaItemID, 250))
+
+	if got := inv.Adena(); got != 750 {
+		t.Errorf("Adena() = %d, want 750", got)
+	}
+}
+
+func TestInventory_DestroyByType(t *testing.T) {
+	inv := NewInventory(1)
+	inv.AddItem(mustItem(t, 1, 10, 1419, 2))
+	inv.AddItem(mustItem(t, 1, 11, 1419, 3))
+
+	if err := inv.DestroyByType(1419, 6); err == nil {
+		t.Fatal("DestroyByType beyond available count should fail")
+	}
+	if got := inv.CountOf(1419); got != 5 {
+		t.Fatalf("CountOf after failed destroy = %d, want 5", got)
+	}
+
+	if err := inv.DestroyByType(1419, 3); err != nil {
+		t.Fatalf("DestroyByType: %v", err)
+	}
+	if got := inv.CountOf(1419); got != 2 {
+		t.Errorf("CountOf = %d, want 2", got)
+	}
+	if inv.ItemByObjectID(10) != nil {
+		t.Error("first stack should be destroyed completely")
+	}
+}
+
+func TestInventory_TransferTo(t *testing.T) {
+	t.Run("whole item", func(t *testing.T) {
+		src, dst := NewInventory(1), NewInventory(2)
+		sword := mustItem(t, 1, 10, 100, 1)
+		src.AddItem(sword)
+
+		got, err := src.TransferTo(dst, 10, 1)
+		if err != nil {
+			t.Fatalf("TransferTo: %v", err)
+		}
+		if got != sword || sword.OwnerID() != 2 {
+			t.Errorf("item not moved to receiver: got %v owner %d", got, sword.OwnerID())
+		}
+		if src.Size() != 0 || dst.Size() != 1 {
+			t.Errorf("sizes = %d/%d, want 0/1", src.Size(), dst.Size())
+		}
+	})
+
+	t.Run("split stack", func(t *testing.T) {
+		src, dst := NewInventory(1), NewInventory(2)
+		src.AddItem(mustItem(t, 1, 10, 1835, 100))
+
+		got, err := src.TransferTo(dst, 10, 30)
+		if err != nil {
+			t.Fatalf("TransferTo: %v", err)
+		}
+		if got.Count() != 30 || got.OwnerID() != 2 {
+			t.Errorf("split item count=%d owner=%d, want 30/2", got.Count(), got.OwnerID())
+		}
+		if got.ObjectID() == 10 {
+			t.Error("split item must get a new objectID")
+		}
+		if src.CountOf(1835) != 70 {
+			t.Errorf("source count = %d, want 70", src.CountOf(1835))
+		}
+	})
+
+	t.Run("merge stack", func(t *testing.T) {
+		src, dst := NewInventory(1), NewInventory(2)
+		src.AddItem(mustItem(t, 1, 10, AdenaItemID, 100))
+		dst.AddItem(mustItem(t, 2, 20, AdenaItemID, 5))
+
+		got, err := src.TransferTo(dst, 10, 100)
+		if err != nil {
+			t.Fatalf("TransferTo: %v", err)
+		}
+		if got.ObjectID() != 20 || got.Count() != 105 {
+			t.Errorf("merged into %d with count %d, want 20/105", got.ObjectID(), got.Count())
+		}
+		if src.Size() != 0 || dst.Size() != 1 {
+			t.Errorf("sizes = %d/%d, want 0/1", src.Size(), dst.Size())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		src, dst := NewInventory(1), NewInventory(2)
+		src.AddItem(mustItem(t, 1, 10, 100, 5))
+
+		if _, err := src.TransferTo(dst, 10, 6); err == nil {
+			t.Error("expected error for count > stack")
+		}
+		if _, err := src.TransferTo(dst, 11, 1); err == nil {
+			t.Error("expected error for missing item")
+		}
+		if _, err := src.TransferTo(dst, 10, 0); err == nil {
+			t.Error("expected error for zero count")
+		}
+		if _, err := src.TransferTo(src, 10, 1); err == nil {
+			t.Error("expected error for same inventory")
+		}
+		if src.CountOf(100) != 5 {
+			t.Errorf("failed transfers must not change source, count = %d", src.CountOf(100))
+		}
+	})
+}
+
+func TestExchange(t *testing.T) {
+	t.Run("deal", func(t *testing.T) {
+		seller, buyer := NewInventory(1), NewInventory(2)
+		seller.AddItem(mustItem(t, 1, 10, 100, 1))
+		seller.AddItem(mustItem(t, 1, 11, 1835, 100))
+		buyer.AddItem(mustItem(t, 2, 20, AdenaItemID, 1000))
+
+		err := Exchange(buyer, seller, []ItemTransfer{
+			{From: seller, To: buyer, ObjectID: 10, Count: 1},
+			{From: seller, To: buyer, ObjectID: 11, Count: 40},
+			{From: buyer, To: seller, ItemType: AdenaItemID, Count: 600},
+		})
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if buyer.CountOf(100) != 1 || buyer.CountOf(1835) != 40 || buyer.Adena() != 400 {
+			t.Errorf("buyer sword=%d shots=%d adena=%d, want 1/40/400", buyer.CountOf(100), buyer.CountOf(1835), buyer.Adena())
+		}
+		if seller.CountOf(100) != 0 || seller.CountOf(1835) != 60 || seller.Adena() != 600 {
+			t.Errorf("seller sword=%d shots=%d adena=%d, want 0/60/600", seller.CountOf(100), seller.CountOf(1835), seller.Adena())
+		}
+	})
+
+	t.Run("failure changes nothing", func(t *testing.T) {
+		seller, buyer := NewInventory(1), NewInventory(2)
+		seller.AddItem(mustItem(t, 1, 10, 100, 1))
+		buyer.AddItem(mustItem(t, 2, 20, AdenaItemID, 100))
+
+		// Предмет проходит проверку, оплата — нет
+		err := Exchange(buyer, seller, []ItemTransfer{
+			{From: seller, To: buyer, ObjectID: 10, Count: 1},
+			{From: buyer, To: seller, ItemType: AdenaItemID, Count: 101},
+		})
+		if err == nil {
+			t.Fatal("expected error for not enough adena")
+		}
+		if seller.CountOf(100) != 1 || buyer.CountOf(100) != 0 || buyer.Adena() != 100 {
+			t.Error("failed exchange must not change inventories")
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		seller, buyer := NewInventory(1), NewInventory(2)
+		seller.AddItem(mustItem(t, 1, 10, 1835, 100))
+		seller.AddItem(mustItem(t, 1, 11, AdenaItemID, math.MaxInt32-10))
+		buyer.AddItem(mustItem(t, 2, 20, 1835, math.MaxInt32-50))
+		buyer.AddItem(mustItem(t, 2, 21, AdenaItemID, 100))
+
+		tests := []struct {
+			name      string
+			transfers []ItemTransfer
+		}{
+			{"receiver stack", []ItemTransfer{
+				{From: seller, To: buyer, ObjectID: 10, Count: 30},
+				{From: seller, To: buyer, ObjectID: 10, Count: 30},
+			}},
+			{"receiver adena", []ItemTransfer{
+				{From: seller, To: buyer, ObjectID: 10, Count: 1},
+				{From: buyer, To: seller, ItemType: AdenaItemID, Count: 11},
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := Exchange(buyer, seller, tt.transfers); !errors.Is(err, ErrCountOverflow) {
+					t.Errorf("err = %v, want ErrCountOverflow", err)
+				}
+				if seller.CountOf(1835) != 100 || buyer.CountOf(1835) != math.MaxInt32-50 || buyer.Adena() != 100 {
+					t.Error("overflowing exchange must not change inventories")
+				}
+			})
+		}
+	})
+}
+
+func TestInventory_TransferByType_Concurrent(t *testing.T) {
+	a, b := NewInventory(1), NewInventory(2)
+	a.AddItem(mustItem(t, 1, 10, AdenaItemID, 1000))
+	b.AddItem(mustItem(t, 2, 20, AdenaItemID, 1000))
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { _, _ = a.TransferByType(b, AdenaItemID, 1) })
+		wg.Go(func() { _, _ = b.TransferByType(a, AdenaItemID, 1) })
+	}
+	wg.Wait()
+
+	if total := a.Adena() + b.Adena(); total != 2000 {
+		t.Errorf("total adena = %d, want 2000", total)
+	}
+}
+
+// sequenceIDs — ItemIDSource с заданными ID, как последовательность items.item_id.
+type sequenceIDs struct {
+	ids []int64
+}
+
+func (s *sequenceIDs) NextItemID() (int64, error) {
+	if len(s.ids) == 0 {
+		return 0, errors.New("sequence is exhausted")
+	}
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+	return id, nil
+}
+
+func TestInventory_NewItemIDsFromSource(t *testing.T) {
+	prev := *itemIDSource.Load()
+	t.Cleanup(func() { SetItemIDSource(prev) })
+	SetItemIDSource(&sequenceIDs{ids: []int64{501, 502}})
+
+	src, dst := NewInventory(1), NewInventory(2)
+	src.AddItem(mustItem(t, 1, 10, 17, 100))
+
+	split, err := src.TransferTo(dst, 10, 40)
+	if err != nil {
+		t.Fatalf("TransferTo: %v", err)
+	}
+	if split.ItemID() != 501 {
+		t.Errorf("split item ID = %d, want 501 from the source", split.ItemID())
+	}
+	created, err := NewInventory(3).AddByType(57, 10)
+	if err != nil {
+		t.Fatalf("AddByType: %v", err)
+	}
+	if created.ItemID() != 502 {
+		t.Errorf("new item ID = %d, want 502 from the source", created.ItemID())
+	}
+
+	// Источник исчерпан: стак не делится и не теряет штук
+	if _, err := src.TransferTo(NewInventory(4), 10, 10); err == nil {
+		t.Error("TransferTo succeeded without an item ID")
+	}
+	if got := src.ItemByObjectID(10).Count(); got != 60 {
+		t.Errorf("source stack = %d after failed split, want 60", got)
+	}
+}
@@ -1,11 +1,16 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 )
 
+// ErrCountOverflow — количество в стаке превысило бы максимум int32.
+var ErrCountOverflow = errors.New("item count overflow")
+
 // ItemLocation представляет местоположение предмета.
 type ItemLocation int32
 
@@ -68,11 +73,25 @@ func (i *Item) ItemID() int64 {
 	return i.itemID
 }
 
-// OwnerID возвращает ID владельца (immutable).
+// ObjectID возвращает игровой objectID предмета (используется в пакетах).
+func (i *Item) ObjectID() uint32 {
+	return uint32(i.itemID)
+}
+
+// OwnerID возвращает ID владельца.
 func (i *Item) OwnerID() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	return i.ownerID
 }
 
+// SetOwnerID меняет владельца предмета (trade, private store).
+func (i *Item) SetOwnerID(ownerID int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.ownerID = ownerID
+}
+
 // ItemType возвращает тип предмета.
 func (i *Item) ItemType() int32 {
 	i.mu.RLock()
@@ -105,12 +124,15 @@ func (i *Item) AddCount(delta int32) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	newCount := i.count + delta
+	newCount := int64(i.count) + int64(delta)
 	if newCount <= 0 {
 		return fmt.Errorf("count would become %d (non-positive)", newCount)
 	}
+	if newCount > math.MaxInt32 {
+		return fmt.Errorf("%w: %d", ErrCountOverflow, newCount)
+	}
 
-	i.count = newCount
+	i.count = int32(newCount)
 	return nil
 }
 
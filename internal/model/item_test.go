@@ -1,6 +1,8 @@
 package model
 
 import (
+	"errors"
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -200,6 +202,11 @@ func TestItem_AddCount(t *testing.T) {
 		t.Error("AddCount(-2000) error = nil, want error (would result in negative)")
 	}
 
+	// AddCount сверх MaxInt32 (invalid)
+	if err := item.AddCount(math.MaxInt32); !errors.Is(err, ErrCountOverflow) {
+		t.Errorf("AddCount(MaxInt32) error = %v, want ErrCountOverflow", err)
+	}
+
 	// Count не должно измениться после invalid AddCount
 	if item.Count() != 1300 {
 		t.Errorf("After invalid AddCount, Count() = %d, want 1300", item.Count())
@@ -14,6 +14,7 @@ type Npc struct {
 
 	mu         sync.RWMutex
 	spawn      *Spawn
+	spawnLoc   *Location // where this NPC appeared (territory spawns differ per NPC)
 	isDecayed  atomic.Bool
 	intention  atomic.Int32 // Intention type
 }
@@ -74,6 +75,28 @@ func (n *Npc) Spawn() *Spawn {
 	return n.spawn
 }
 
+// SetSpawnLocation remembers where this NPC appeared
+func (n *Npc) SetSpawnLocation(loc Location) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.spawnLoc = &loc
+}
+
+// SpawnLocation returns where this NPC appeared: its home for leashing and random walk.
+// Defaults to the spawn point location, or the current location without a spawn.
+func (n *Npc) SpawnLocation() Location {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	switch {
+	case n.spawnLoc != nil:
+		return *n.spawnLoc
+	case n.spawn != nil:
+		return n.spawn.Location()
+	default:
+		return n.Location()
+	}
+}
+
 // IsDecayed returns whether NPC is decayed (corpse disappeared)
 func (n *Npc) IsDecayed() bool {
 	return n.isDecayed.Load()
@@ -1,5 +1,25 @@
 package model
 
+import "math/rand/v2"
+
+// DropChanceMax is the chance of a drop that always falls: chances are per million, as in L2J drop lists
+const DropChanceMax = 1_000_000
+
+// NpcDrop is an item an NPC may drop when killed
+type NpcDrop struct {
+	ItemType int32
+	Min, Max int32 // count range
+	Chance   int32 // per million
+}
+
+// Roll returns how many items of the drop fall at one kill (0 if none)
+func (d NpcDrop) Roll(rnd *rand.Rand) int32 {
+	if d.Max < d.Min || d.Min <= 0 || rnd.Int32N(DropChanceMax) >= d.Chance {
+		return 0
+	}
+	return d.Min + rnd.Int32N(d.Max-d.Min+1)
+}
+
 // NpcTemplate represents NPC stats and AI parameters from npc_templates table
 type NpcTemplate struct {
 	templateID  int32
@@ -17,6 +37,10 @@ type NpcTemplate struct {
 	atkSpeed    int32
 	respawnMin  int32 // seconds
 	respawnMax  int32 // seconds
+	guard       bool  // town guard: hunts players with karma
+	exp         int64 // experience for killing the NPC
+	sp          int64 // SP for killing the NPC
+	drops       []NpcDrop
 }
 
 // NewNpcTemplate creates a new NPC template
@@ -121,3 +145,39 @@ func (t *NpcTemplate) RespawnMin() int32 {
 func (t *NpcTemplate) RespawnMax() int32 {
 	return t.respawnMax
 }
+
+// IsGuard returns whether NPCs of the template are guards that attack players with karma
+func (t *NpcTemplate) IsGuard() bool {
+	return t.guard
+}
+
+// SetGuard marks NPCs of the template as guards
+func (t *NpcTemplate) SetGuard(guard bool) {
+	t.guard = guard
+}
+
+// Exp returns experience for killing the NPC
+func (t *NpcTemplate) Exp() int64 {
+	return t.exp
+}
+
+// SP returns SP for killing the NPC
+func (t *NpcTemplate) SP() int64 {
+	return t.sp
+}
+
+// SetRewards sets experience and SP for killing the NPC
+func (t *NpcTemplate) SetRewards(exp, sp int64) {
+	t.exp = exp
+	t.sp = sp
+}
+
+// Drops returns the items the NPC may drop when killed
+func (t *NpcTemplate) Drops() []NpcDrop {
+	return t.drops
+}
+
+// SetDrops sets the items the NPC may drop when killed
+func (t *NpcTemplate) SetDrops(drops []NpcDrop) {
+	t.drops = drops
+}
@@ -1,6 +1,9 @@
 package model
 
-import "testing"
+import (
+	"math/rand/v2"
+	"testing"
+)
 
 func TestNewNpcTemplate(t *testing.T) {
 	template := NewNpcTemplate(
@@ -64,3 +67,25 @@ func TestNewNpcTemplate(t *testing.T) {
 		t.Errorf("RespawnMax() = %d, want 60", template.RespawnMax())
 	}
 }
+
+func TestNpcDrop_Roll(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	always := NpcDrop{ItemType: 57, Min: 10, Max: 20, Chance: DropChanceMax}
+	for range 100 {
+		if n := always.Roll(rnd); n < 10 || n > 20 {
+			t.Fatalf("Roll() = %d, want 10..20", n)
+		}
+	}
+
+	never := NpcDrop{ItemType: 57, Min: 1, Max: 1, Chance: 0}
+	broken := NpcDrop{ItemType: 57, Min: 5, Max: 1, Chance: DropChanceMax}
+	for range 100 {
+		if n := never.Roll(rnd); n != 0 {
+			t.Fatalf("zero chance: Roll() = %d, want 0", n)
+		}
+		if n := broken.Roll(rnd); n != 0 {
+			t.Fatalf("min > max: Roll() = %d, want 0", n)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,7 @@ type Player struct {
 	raceID      int32
 	classID     int32
 	experience  int64
+	sp          int64
 	createdAt   time.Time
 	lastLogin   time.Time
 
@@ -27,6 +29,30 @@ type Player struct {
 	// Stores *VisibilityCache — updated by VisibilityManager every 100ms
 	// atomic.Value allows lock-free concurrent reads
 	visibilityCache atomic.Value // *VisibilityCache (defined in internal/world)
+
+	inventory *Inventory
+
+	// Private store (личный магазин)
+	privateStoreType atomic.Int32 // PrivateStoreType
+	sellList         *TradeList
+	buyList          *TradeList
+	manufactureList  *ManufactureList
+	sitting          atomic.Bool
+	offline          atomic.Bool // клиент отключён, магазин остаётся в мире
+	invisible        atomic.Bool // GM скрыт от других игроков и монстров
+
+	target atomic.Uint32 // objectID выбранной цели (0 = нет цели)
+
+	// PvP
+	karma    int32
+	pvpKills int32
+	pkKills  int32
+	pvpFlag  atomic.Bool // напал на игрока — можно атаковать без кармы
+
+	// Клан (0 = не в клане)
+	clanID     int32
+	pledgeType int32 // подразделение клана (0 = основной состав)
+	powerGrade int32 // ранг в клане (1-9)
 }
 
 // NewPlayer создаёт нового игрока с валидацией.
@@ -48,7 +74,8 @@ func NewPlayer(characterID, accountID int64, name string, level, raceID, classID
 	maxCP := int32(800 + level*40)
 
 	p := &Player{
-		Character:   NewCharacter(0, name, loc, level, maxHP, maxMP, maxCP),
+		// objectID игрока = characterID (NPC objectID начинаются с 100000)
+		Character:   NewCharacter(uint32(characterID), name, loc, level, maxHP, maxMP, maxCP),
 		characterID: characterID,
 		accountID:   accountID,
 		level:       level, // FIXME: level duplicated in Character and Player
@@ -56,6 +83,11 @@ func NewPlayer(characterID, accountID int64, name string, level, raceID, classID
 		classID:     classID,
 		experience:  0,
 		createdAt:   time.Now(),
+
+		inventory:       NewInventory(characterID),
+		sellList:        NewTradeList(),
+		buyList:         NewTradeList(),
+		manufactureList: NewManufactureList(),
 	}
 
 	// Initialize visibility cache (Phase 4.5 PR3)
@@ -125,6 +157,26 @@ func (p *Player) AddExperience(exp int64) {
 	}
 }
 
+// LoseExperience отнимает опыт (штраф за смерть).
+// С delevel персонаж теряет уровень, если опыта стало меньше начала уровня,
+// иначе опыт не опускается ниже начала текущего уровня.
+// Возвращает уровень после потери.
+func (p *Player) LoseExperience(exp int64, delevel bool) int32 {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+
+	floor := ExpForLevel(p.level)
+	if !delevel {
+		p.experience = max(p.experience-exp, min(p.experience, floor))
+		return p.level
+	}
+	p.experience = max(p.experience-exp, 0)
+	if p.experience < floor && p.level > 1 {
+		p.level--
+	}
+	return p.level
+}
+
 // SetExperience устанавливает точное значение опыта.
 func (p *Player) SetExperience(exp int64) {
 	p.playerMu.Lock()
@@ -136,6 +188,48 @@ func (p *Player) SetExperience(exp int64) {
 	p.experience = exp
 }
 
+// SP возвращает skill points.
+func (p *Player) SP() int64 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.sp
+}
+
+// AddSP добавляет skill points (не опускается ниже нуля).
+func (p *Player) AddSP(sp int64) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+
+	p.sp += sp
+	if p.sp < 0 {
+		p.sp = 0
+	}
+}
+
+// SpendSP списывает skill points.
+// Возвращает false (без изменений) если SP недостаточно.
+func (p *Player) SpendSP(sp int64) bool {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+
+	if sp < 0 || p.sp < sp {
+		return false
+	}
+	p.sp -= sp
+	return true
+}
+
+// SetSP устанавливает точное значение skill points.
+func (p *Player) SetSP(sp int64) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+
+	if sp < 0 {
+		sp = 0
+	}
+	p.sp = sp
+}
+
 // CreatedAt возвращает время создания персонажа.
 func (p *Player) CreatedAt() time.Time {
 	p.playerMu.RLock()
@@ -215,3 +309,177 @@ func (p *Player) SetVisibilityCache(cache *VisibilityCache) {
 func (p *Player) InvalidateVisibilityCache() {
 	p.visibilityCache.Store((*VisibilityCache)(nil))
 }
+
+// Inventory возвращает инвентарь игрока.
+func (p *Player) Inventory() *Inventory {
+	return p.inventory
+}
+
+// PrivateStoreType возвращает текущий режим личного магазина.
+func (p *Player) PrivateStoreType() PrivateStoreType {
+	return PrivateStoreType(p.privateStoreType.Load())
+}
+
+// SetPrivateStoreType устанавливает режим личного магазина.
+func (p *Player) SetPrivateStoreType(t PrivateStoreType) {
+	p.privateStoreType.Store(int32(t))
+}
+
+// SellList возвращает sell-список личного магазина.
+func (p *Player) SellList() *TradeList {
+	return p.sellList
+}
+
+// BuyList возвращает buy-список личного магазина.
+func (p *Player) BuyList() *TradeList {
+	return p.buyList
+}
+
+// ManufactureList возвращает список рецептов dwarven manufacture.
+func (p *Player) ManufactureList() *ManufactureList {
+	return p.manufactureList
+}
+
+// IsSitting возвращает true если игрок сидит.
+func (p *Player) IsSitting() bool {
+	return p.sitting.Load()
+}
+
+// SetSitting устанавливает режим сидения.
+func (p *Player) SetSitting(sitting bool) {
+	p.sitting.Store(sitting)
+}
+
+// IsOffline возвращает true если клиент отключён, а персонаж остаётся в мире (offline store).
+func (p *Player) IsOffline() bool {
+	return p.offline.Load()
+}
+
+// SetOffline устанавливает offline-режим.
+func (p *Player) SetOffline(offline bool) {
+	p.offline.Store(offline)
+}
+
+// Target возвращает objectID выбранной цели (0 = нет цели).
+func (p *Player) Target() uint32 {
+	return p.target.Load()
+}
+
+// SetTarget выбирает цель (0 — сбросить цель).
+func (p *Player) SetTarget(objectID uint32) {
+	p.target.Store(objectID)
+}
+
+// Karma возвращает карму (больше 0 — PK, красный ник).
+func (p *Player) Karma() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.karma
+}
+
+// SetKarma устанавливает карму (отрицательная обрезается до 0).
+func (p *Player) SetKarma(karma int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.karma = max(karma, 0)
+}
+
+// PvPKills возвращает число убийств в PvP.
+func (p *Player) PvPKills() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.pvpKills
+}
+
+// SetPvPKills устанавливает число убийств в PvP (загрузка из БД).
+func (p *Player) SetPvPKills(n int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.pvpKills = n
+}
+
+// PKKills возвращает число убийств мирных игроков.
+func (p *Player) PKKills() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.pkKills
+}
+
+// SetPKKills устанавливает число убийств мирных игроков (загрузка из БД).
+func (p *Player) SetPKKills(n int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.pkKills = n
+}
+
+// AddPvPKill засчитывает убийство в PvP.
+func (p *Player) AddPvPKill() {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.pvpKills++
+}
+
+// AddPKKill засчитывает убийство мирного игрока и добавляет карму.
+func (p *Player) AddPKKill(karma int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.pkKills++
+	p.karma = int32(min(int64(p.karma)+int64(karma), math.MaxInt32))
+}
+
+// IsPvPFlagged проверяет PvP-флаг (фиолетовый ник).
+func (p *Player) IsPvPFlagged() bool {
+	return p.pvpFlag.Load()
+}
+
+// SetPvPFlag ставит или снимает PvP-флаг.
+func (p *Player) SetPvPFlag(flagged bool) {
+	p.pvpFlag.Store(flagged)
+}
+
+// IsInvisible проверяет, скрыт ли игрок (GM-невидимость).
+func (p *Player) IsInvisible() bool {
+	return p.invisible.Load()
+}
+
+// SetInvisible скрывает игрока от других игроков и монстров или показывает его.
+func (p *Player) SetInvisible(invisible bool) {
+	p.invisible.Store(invisible)
+}
+
+// ClanID возвращает ID клана игрока (0 если не в клане).
+func (p *Player) ClanID() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.clanID
+}
+
+// PledgeType возвращает подразделение клана игрока.
+func (p *Player) PledgeType() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.pledgeType
+}
+
+// PowerGrade возвращает ранг игрока в клане.
+func (p *Player) PowerGrade() int32 {
+	p.playerMu.RLock()
+	defer p.playerMu.RUnlock()
+	return p.powerGrade
+}
+
+// SetClan устанавливает членство в клане (clanID=0 — выход из клана).
+func (p *Player) SetClan(clanID, pledgeType, powerGrade int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.clanID = clanID
+	p.pledgeType = pledgeType
+	p.powerGrade = powerGrade
+}
+
+// SetPowerGrade устанавливает ранг игрока в клане.
+func (p *Player) SetPowerGrade(grade int32) {
+	p.playerMu.Lock()
+	defer p.playerMu.Unlock()
+	p.powerGrade = grade
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -243,6 +244,61 @@ func TestPlayer_Experience(t *testing.T) {
 	}
 }
 
+func TestPlayer_LoseExperience(t *testing.T) {
+	player, _ := NewPlayer(1, 100, "TestHero", 10, 0, 0)
+
+	// Без delevel опыт не опускается ниже начала уровня
+	player.SetExperience(ExpForLevel(10) + 100)
+	if level := player.LoseExperience(1000, false); level != 10 {
+		t.Errorf("LoseExperience without delevel: level = %d, want 10", level)
+	}
+	if player.Experience() != ExpForLevel(10) {
+		t.Errorf("Experience() = %d, want %d", player.Experience(), ExpForLevel(10))
+	}
+
+	// С delevel персонаж теряет уровень
+	if level := player.LoseExperience(1000, true); level != 9 {
+		t.Errorf("LoseExperience with delevel: level = %d, want 9", level)
+	}
+	if player.Level() != 9 || player.Experience() != ExpForLevel(10)-1000 {
+		t.Errorf("after delevel: level %d, exp %d", player.Level(), player.Experience())
+	}
+
+	// Опыт не уходит в минус, за одну смерть теряется не больше уровня
+	player.LoseExperience(1<<40, true)
+	if player.Level() != 8 || player.Experience() != 0 {
+		t.Errorf("after losing everything: level %d, exp %d", player.Level(), player.Experience())
+	}
+}
+
+func TestPlayer_Karma(t *testing.T) {
+	player, _ := NewPlayer(1, 100, "TestHero", 40, 0, 0)
+
+	player.AddPKKill(240)
+	player.AddPKKill(480)
+	if player.PKKills() != 2 || player.Karma() != 720 {
+		t.Errorf("after 2 PK: pk %d, karma %d", player.PKKills(), player.Karma())
+	}
+
+	// Карма не уходит в минус
+	player.SetKarma(-5)
+	if player.Karma() != 0 {
+		t.Errorf("Karma() = %d, want 0", player.Karma())
+	}
+
+	// Карма не переполняется
+	player.SetKarma(math.MaxInt32 - 10)
+	player.AddPKKill(100)
+	if player.Karma() != math.MaxInt32 {
+		t.Errorf("Karma() = %d, want MaxInt32", player.Karma())
+	}
+
+	player.AddPvPKill()
+	if player.PvPKills() != 1 {
+		t.Errorf("PvPKills() = %d, want 1", player.PvPKills())
+	}
+}
+
 func TestPlayer_LastLogin(t *testing.T) {
 	player, _ := NewPlayer(1, 100, "TestHero", 1, 0, 0)
 
@@ -418,6 +474,47 @@ func TestPlayer_ConcurrentExperienceUpdates(t *testing.T) {
 	}
 }
 
+func TestPlayer_ConcurrentReduceHP(t *testing.T) {
+	player, _ := NewPlayer(1, 100, "TestHero", 1, 0, 0)
+	player.SetMaxHP(1000)
+	player.SetCurrentHP(1000)
+
+	const numAttackers = 50
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		kills int
+	)
+	wg.Add(numAttackers)
+
+	// Удары суммарно втрое больше HP: умереть игрок должен ровно один раз
+	for range numAttackers {
+		go func() {
+			defer wg.Done()
+
+			for range 20 {
+				if _, killed := player.ReduceHP(3); killed {
+					mu.Lock()
+					kills++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if kills != 1 {
+		t.Errorf("kills = %d, want 1", kills)
+	}
+	if hp := player.CurrentHP(); hp != 0 {
+		t.Errorf("CurrentHP() = %d, want 0", hp)
+	}
+	if hp, killed := player.ReduceHP(10); hp != 0 || killed {
+		t.Errorf("ReduceHP() on dead player = (%d, %v), want (0, false)", hp, killed)
+	}
+}
+
 func TestPlayer_MixedConcurrentAccess(t *testing.T) {
 	player, _ := NewPlayer(1, 100, "TestHero", 10, 0, 0)
 
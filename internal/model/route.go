@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// RouteMode defines what a patrolling NPC does after the last point of its route
+type RouteMode int
+
+const (
+	// RouteLoop walks from the last point back to the first one
+	RouteLoop RouteMode = iota
+	// RouteReverse walks the route backwards, then forwards again
+	RouteReverse
+)
+
+// String returns route mode name as written in route data
+func (m RouteMode) String() string {
+	switch m {
+	case RouteLoop:
+		return "loop"
+	case RouteReverse:
+		return "reverse"
+	default:
+		return "unknown"
+	}
+}
+
+// RoutePoint is a waypoint of a patrol route
+type RoutePoint struct {
+	Location Location
+	Pause    time.Duration // how long the NPC stands at the point
+}
+
+// Route is a named patrol route assigned to spawns (immutable after loading)
+type Route struct {
+	Name   string
+	Mode   RouteMode
+	Points []RoutePoint
+}
+
+// Next returns the index of the point after i and the new walking direction
+// (1 forwards, -1 backwards; backwards only happens in RouteReverse).
+func (r *Route) Next(i, dir int) (int, int) {
+	n := len(r.Points)
+	if n < 2 {
+		return 0, 1
+	}
+	if r.Mode == RouteLoop {
+		return (i + 1) % n, 1
+	}
+	if next := i + dir; next >= 0 && next < n {
+		return next, dir
+	}
+	return i - dir, -dir
+}
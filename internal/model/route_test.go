@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestRoute_Next(t *testing.T) {
+	points := make([]RoutePoint, 3)
+	tests := []struct {
+		mode RouteMode
+		want []int
+	}{
+		{RouteLoop, []int{1, 2, 0, 1, 2, 0}},
+		{RouteReverse, []int{1, 2, 1, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			r := &Route{Name: "test", Mode: tt.mode, Points: points}
+			i, dir := 0, 1
+			for step, want := range tt.want {
+				i, dir = r.Next(i, dir)
+				if i != want {
+					t.Fatalf("step %d: Next() = %d, want %d", step, i, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoute_NextSinglePoint(t *testing.T) {
+	r := &Route{Mode: RouteReverse, Points: make([]RoutePoint, 1)}
+	if i, dir := r.Next(0, 1); i != 0 || dir != 1 {
+		t.Errorf("Next() = %d, %d, want 0, 1", i, dir)
+	}
+}
@@ -12,6 +12,8 @@ type Spawn struct {
 	location     Location
 	maximumCount int32
 	doRespawn    bool
+	route        string // name of the patrol route ("" = random walk)
+	territory    string // name of the territory NPCs are scattered in ("" = fixed location)
 
 	mu           sync.RWMutex
 	currentCount atomic.Int32
@@ -69,6 +71,27 @@ func (s *Spawn) DoRespawn() bool {
 	return s.doRespawn
 }
 
+// Route returns the name of the patrol route of spawned NPCs ("" = none)
+func (s *Spawn) Route() string {
+	return s.route
+}
+
+// SetRoute assigns a patrol route (set while loading, before NPCs are spawned)
+func (s *Spawn) SetRoute(name string) {
+	s.route = name
+}
+
+// Territory returns the name of the spawn territory ("" = NPCs spawn at Location)
+func (s *Spawn) Territory() string {
+	return s.territory
+}
+
+// SetTerritory makes NPCs spawn at random points of a territory
+// (set while loading, before NPCs are spawned)
+func (s *Spawn) SetTerritory(name string) {
+	s.territory = name
+}
+
 // CurrentCount returns current spawned count (atomic read)
 func (s *Spawn) CurrentCount() int32 {
 	return s.currentCount.Load()
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// ErrInvalidTerritory is returned for territories that cannot hold spawns
+var ErrInvalidTerritory = errors.New("invalid spawn territory")
+
+// maxRandomPointAttempts limits rejection sampling in RandomPoint
+const maxRandomPointAttempts = 100
+
+// TerritoryPoint is a corner of a territory polygon
+type TerritoryPoint struct {
+	X, Y int32
+}
+
+// Territory is a polygon with a height range where NPCs of territory spawns are scattered.
+// Banned territories are holes in it (lakes, camps of other NPCs).
+// Immutable after loading.
+type Territory struct {
+	name       string
+	points     []TerritoryPoint
+	minZ, maxZ int32
+	banned     []*Territory
+
+	// bounding box of the polygon
+	minX, maxX, minY, maxY int32
+}
+
+// NewTerritory creates a territory from at least 3 polygon corners
+func NewTerritory(name string, points []TerritoryPoint, minZ, maxZ int32) (*Territory, error) {
+	if name == "" {
+		return nil, fmt.Errorf("territory without name: %w", ErrInvalidTerritory)
+	}
+	if len(points) < 3 {
+		return nil, fmt.Errorf("territory %q needs at least 3 points, got %d: %w", name, len(points), ErrInvalidTerritory)
+	}
+	if minZ > maxZ {
+		return nil, fmt.Errorf("territory %q has min Z %d above max Z %d: %w", name, minZ, maxZ, ErrInvalidTerritory)
+	}
+
+	t := &Territory{
+		name:   name,
+		points: append([]TerritoryPoint(nil), points...),
+		minZ:   minZ,
+		maxZ:   maxZ,
+		minX:   points[0].X,
+		maxX:   points[0].X,
+		minY:   points[0].Y,
+		maxY:   points[0].Y,
+	}
+	for _, p := range points[1:] {
+		t.minX, t.maxX = min(t.minX, p.X), max(t.maxX, p.X)
+		t.minY, t.maxY = min(t.minY, p.Y), max(t.maxY, p.Y)
+	}
+	return t, nil
+}
+
+// Name returns territory name
+func (t *Territory) Name() string {
+	return t.name
+}
+
+// Points returns a copy of the polygon corners
+func (t *Territory) Points() []TerritoryPoint {
+	return append([]TerritoryPoint(nil), t.points...)
+}
+
+// MinZ returns the lowest spawn height
+func (t *Territory) MinZ() int32 {
+	return t.minZ
+}
+
+// MaxZ returns the highest spawn height
+func (t *Territory) MaxZ() int32 {
+	return t.maxZ
+}
+
+// Banned returns the sub-areas where NPCs must not spawn
+func (t *Territory) Banned() []*Territory {
+	return t.banned
+}
+
+// AddBanned excludes another territory from this one (while loading only)
+func (t *Territory) AddBanned(b *Territory) error {
+	if b == t {
+		return fmt.Errorf("territory %q cannot ban itself: %w", t.name, ErrInvalidTerritory)
+	}
+	t.banned = append(t.banned, b)
+	return nil
+}
+
+// Contains reports whether (x, y) lies inside the polygon (even-odd rule)
+func (t *Territory) Contains(x, y int32) bool {
+	if x < t.minX || x > t.maxX || y < t.minY || y > t.maxY {
+		return false
+	}
+	inside := false
+	px, py := float64(x), float64(y)
+	for i, j := 0, len(t.points)-1; i < len(t.points); j, i = i, i+1 {
+		a, b := t.points[i], t.points[j]
+		ax, ay, bx, by := float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)
+		if (ay > py) != (by > py) && px < (bx-ax)*(py-ay)/(by-ay)+ax {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ContainsZ reports whether a height lies within the territory height range
+func (t *Territory) ContainsZ(z int32) bool {
+	return z >= t.minZ && z <= t.maxZ
+}
+
+// IsAllowed reports whether NPCs may spawn at (x, y): inside the polygon, outside banned areas
+func (t *Territory) IsAllowed(x, y int32) bool {
+	if !t.Contains(x, y) {
+		return false
+	}
+	for _, b := range t.banned {
+		if b.Contains(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// RandomPoint returns a random allowed point of the territory.
+// ok is false if none was found (e.g. the banned areas cover the territory).
+func (t *Territory) RandomPoint() (x, y int32, ok bool) {
+	for range maxRandomPointAttempts {
+		x = t.minX + rand.Int32N(t.maxX-t.minX+1)
+		y = t.minY + rand.Int32N(t.maxY-t.minY+1)
+		if t.IsAllowed(x, y) {
+			return x, y, true
+		}
+	}
+	return 0, 0, false
+}
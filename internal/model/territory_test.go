@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+// lShape is an L-shaped territory: a 200x200 square without its upper right quarter
+func lShape(t *testing.T) *Territory {
+	t.Helper()
+	terr, err := NewTerritory("l_shape", []TerritoryPoint{
+		{0, 0}, {200, 0}, {200, 100}, {100, 100}, {100, 200}, {0, 200},
+	}, -100, 100)
+	if err != nil {
+		t.Fatalf("NewTerritory() error = %v", err)
+	}
+	return terr
+}
+
+func TestNewTerritory_Invalid(t *testing.T) {
+	square := []TerritoryPoint{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	tests := []struct {
+		name       string
+		terrName   string
+		points     []TerritoryPoint
+		minZ, maxZ int32
+	}{
+		{"no name", "", square, 0, 0},
+		{"two points", "a", square[:2], 0, 0},
+		{"inverted Z", "a", square, 10, -10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTerritory(tt.terrName, tt.points, tt.minZ, tt.maxZ); !errors.Is(err, ErrInvalidTerritory) {
+				t.Errorf("NewTerritory() error = %v, want ErrInvalidTerritory", err)
+			}
+		})
+	}
+}
+
+func TestTerritory_Contains(t *testing.T) {
+	terr := lShape(t)
+	tests := []struct {
+		x, y int32
+		want bool
+	}{
+		{50, 50, true},
+		{150, 50, true},
+		{50, 150, true},
+		{150, 150, false}, // cut-out quarter
+		{-1, 50, false},
+		{250, 50, false},
+	}
+	for _, tt := range tests {
+		if got := terr.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("Contains(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+	if !terr.ContainsZ(-100) || !terr.ContainsZ(100) || terr.ContainsZ(101) {
+		t.Error("ContainsZ() should accept exactly [minZ, maxZ]")
+	}
+}
+
+func TestTerritory_RandomPoint(t *testing.T) {
+	terr := lShape(t)
+	lake, err := NewTerritory("lake", []TerritoryPoint{{0, 0}, {100, 0}, {100, 100}, {0, 100}}, -100, 100)
+	if err != nil {
+		t.Fatalf("NewTerritory() error = %v", err)
+	}
+	if err := terr.AddBanned(lake); err != nil {
+		t.Fatalf("AddBanned() error = %v", err)
+	}
+	if err := terr.AddBanned(terr); !errors.Is(err, ErrInvalidTerritory) {
+		t.Errorf("AddBanned(self) error = %v, want ErrInvalidTerritory", err)
+	}
+
+	for range 200 {
+		x, y, ok := terr.RandomPoint()
+		if !ok {
+			t.Fatal("RandomPoint() found no point")
+		}
+		if !terr.Contains(x, y) || lake.Contains(x, y) {
+			t.Fatalf("RandomPoint() = (%d, %d), outside the allowed area", x, y)
+		}
+	}
+
+	// A territory covered by its banned area has no spawn points
+	flooded, _ := NewTerritory("flooded", lake.Points(), 0, 0)
+	if err := flooded.AddBanned(lShape(t)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := flooded.RandomPoint(); ok {
+		t.Error("RandomPoint() in a fully banned territory should fail")
+	}
+}
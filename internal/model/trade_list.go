@@ -0,0 +1,224 @@
+package model
+
+import "sync"
+
+// PrivateStoreType — режим личного магазина игрока (значения совпадают с клиентом Interlude).
+type PrivateStoreType int32
+
+const (
+	PrivateStoreNone        PrivateStoreType = 0
+	PrivateStoreSell        PrivateStoreType = 1
+	PrivateStoreSellManage  PrivateStoreType = 2
+	PrivateStoreBuy         PrivateStoreType = 3
+	PrivateStoreBuyManage   PrivateStoreType = 4
+	PrivateStoreManufacture PrivateStoreType = 5
+	PrivateStorePackageSell PrivateStoreType = 8
+)
+
+// String returns human-readable store type name
+func (t PrivateStoreType) String() string {
+	switch t {
+	case PrivateStoreNone:
+		return "NONE"
+	case PrivateStoreSell:
+		return "SELL"
+	case PrivateStoreSellManage:
+		return "SELL_MANAGE"
+	case PrivateStoreBuy:
+		return "BUY"
+	case PrivateStoreBuyManage:
+		return "BUY_MANAGE"
+	case PrivateStoreManufacture:
+		return "MANUFACTURE"
+	case PrivateStorePackageSell:
+		return "PACKAGE_SELL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// IsActive возвращает true если магазин открыт для покупателей (не режим настройки).
+func (t PrivateStoreType) IsActive() bool {
+	switch t {
+	case PrivateStoreSell, PrivateStorePackageSell, PrivateStoreBuy, PrivateStoreManufacture:
+		return true
+	default:
+		return false
+	}
+}
+
+// TradeItem — позиция в списке личного магазина.
+// Для sell-магазина ObjectID указывает на предмет в инвентаре продавца;
+// для buy-магазина ObjectID = 0, покупка идёт по ItemType.
+type TradeItem struct {
+	ObjectID uint32
+	ItemType int32
+	Enchant  int32
+	Count    int32
+	Price    int32
+}
+
+// TradeList — список предметов личного магазина (sell или buy).
+// Thread-safe.
+type TradeList struct {
+	mu       sync.RWMutex
+	items    []TradeItem
+	title    string
+	packaged bool
+}
+
+// NewTradeList создаёт пустой список.
+func NewTradeList() *TradeList {
+	return &TradeList{}
+}
+
+// Items возвращает копию позиций.
+func (l *TradeList) Items() []TradeItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	items := make([]TradeItem, len(l.items))
+	copy(items, l.items)
+	return items
+}
+
+// SetItems заменяет позиции списка (копирует входной slice).
+func (l *TradeList) SetItems(items []TradeItem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = make([]TradeItem, len(items))
+	copy(l.items, items)
+}
+
+// Len возвращает количество позиций.
+func (l *TradeList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.items)
+}
+
+// Title возвращает сообщение магазина.
+func (l *TradeList) Title() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.title
+}
+
+// SetTitle устанавливает сообщение магазина.
+func (l *TradeList) SetTitle(title string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.title = title
+}
+
+// IsPackaged возвращает true если список продаётся только целиком (package sale).
+func (l *TradeList) IsPackaged() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.packaged
+}
+
+// SetPackaged устанавливает режим package sale.
+func (l *TradeList) SetPackaged(packaged bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.packaged = packaged
+}
+
+// Clear удаляет все позиции и сбрасывает package sale (title сохраняется).
+func (l *TradeList) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = nil
+	l.packaged = false
+}
+
+// ItemByObjectID возвращает позицию sell-списка по objectID предмета.
+func (l *TradeList) ItemByObjectID(objectID uint32) (TradeItem, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, it := range l.items {
+		if it.ObjectID == objectID {
+			return it, true
+		}
+	}
+	return TradeItem{}, false
+}
+
+// ItemByType возвращает позицию buy-списка по типу предмета.
+func (l *TradeList) ItemByType(itemType int32) (TradeItem, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, it := range l.items {
+		if it.ItemType == itemType {
+			return it, true
+		}
+	}
+	return TradeItem{}, false
+}
+
+// Consume уменьшает количество позиции (по objectID, либо по itemType для buy-списка).
+// Позиция удаляется когда количество достигает нуля.
+func (l *TradeList) Consume(objectID uint32, itemType int32, count int32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.items {
+		it := &l.items[i]
+		if (objectID != 0 && it.ObjectID == objectID) || (objectID == 0 && it.ItemType == itemType) {
+			it.Count -= count
+			if it.Count <= 0 {
+				l.items = append(l.items[:i], l.items[i+1:]...)
+			}
+			return
+		}
+	}
+}
+
+// ManufactureItem — рецепт в dwarven manufacture магазине.
+type ManufactureItem struct {
+	RecipeID int32
+	Cost     int32
+}
+
+// ManufactureList — список рецептов manufacture магазина.
+// Thread-safe.
+type ManufactureList struct {
+	mu    sync.RWMutex
+	items []ManufactureItem
+	title string
+}
+
+// NewManufactureList создаёт пустой список рецептов.
+func NewManufactureList() *ManufactureList {
+	return &ManufactureList{}
+}
+
+// Items возвращает копию рецептов.
+func (l *ManufactureList) Items() []ManufactureItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	items := make([]ManufactureItem, len(l.items))
+	copy(items, l.items)
+	return items
+}
+
+// SetItems заменяет рецепты (копирует входной slice).
+func (l *ManufactureList) SetItems(items []ManufactureItem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = make([]ManufactureItem, len(items))
+	copy(l.items, items)
+}
+
+// Title возвращает сообщение магазина.
+func (l *ManufactureList) Title() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.title
+}
+
+// SetTitle устанавливает сообщение магазина.
+func (l *ManufactureList) SetTitle(title string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.title = title
+}
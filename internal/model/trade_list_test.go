@@ -0,0 +1,76 @@
+package model
+
+import "testing"
+
+func TestPrivateStoreType_IsActive(t *testing.T) {
+	tests := []struct {
+		storeType PrivateStoreType
+		want      bool
+	}{
+		{PrivateStoreNone, false},
+		{PrivateStoreSell, true},
+		{PrivateStoreSellManage, false},
+		{PrivateStoreBuy, true},
+		{PrivateStoreBuyManage, false},
+		{PrivateStoreManufacture, true},
+		{PrivateStorePackageSell, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.storeType.String(), func(t *testing.T) {
+			if got := tt.storeType.IsActive(); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTradeList_Consume(t *testing.T) {
+	list := NewTradeList()
+	list.SetItems([]TradeItem{
+		{ObjectID: 1, ItemType: 100, Count: 10, Price: 5},
+		{ObjectID: 2, ItemType: 200, Count: 1, Price: 50},
+	})
+
+	list.Consume(1, 0, 4)
+	it, ok := list.ItemByObjectID(1)
+	if !ok || it.Count != 6 {
+		t.Errorf("after partial consume: ok=%v count=%d, want true/6", ok, it.Count)
+	}
+
+	list.Consume(0, 200, 1)
+	if _, ok := list.ItemByType(200); ok {
+		t.Error("item 200 must be removed after consuming whole count")
+	}
+	if list.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", list.Len())
+	}
+}
+
+func TestTradeList_Clear(t *testing.T) {
+	list := NewTradeList()
+	list.SetItems([]TradeItem{{ObjectID: 1, Count: 1}})
+	list.SetPackaged(true)
+	list.SetTitle("cheap")
+
+	list.Clear()
+
+	if list.Len() != 0 || list.IsPackaged() {
+		t.Errorf("Clear() left len=%d packaged=%v", list.Len(), list.IsPackaged())
+	}
+	if list.Title() != "cheap" {
+		t.Errorf("Clear() must keep title, got %q", list.Title())
+	}
+}
+
+func TestTradeList_ItemsIsCopy(t *testing.T) {
+	list := NewTradeList()
+	list.SetItems([]TradeItem{{ObjectID: 1, Count: 1}})
+
+	items := list.Items()
+	items[0].Count = 100
+
+	if it, _ := list.ItemByObjectID(1); it.Count != 1 {
+		t.Errorf("Items() must return a copy, list count changed to %d", it.Count)
+	}
+}
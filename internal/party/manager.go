@@ -0,0 +1,279 @@
+package party
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	// InviteTimeout — время ожидания ответа на приглашение (L2J REQUEST_TIMEOUT).
+	InviteTimeout = 15 * time.Second
+
+	// DefaultRange — радиус распределения опыта и добычи (L2J ALT_PARTY_RANGE).
+	DefaultRange = 1600
+)
+
+var (
+	ErrSelfInvite        = errors.New("cannot invite yourself")
+	ErrNotLeader         = errors.New("only the party leader can do this")
+	ErrAlreadyInParty    = errors.New("player is already in a party")
+	ErrTargetBusy        = errors.New("player is answering another invitation")
+	ErrPartyFull         = errors.New("party is full")
+	ErrInvalidLootRule   = errors.New("invalid loot rule")
+	ErrNoInvitation      = errors.New("no pending invitation")
+	ErrNotInParty        = errors.New("player is not in a party")
+	ErrMemberNotFound    = errors.New("party member not found")
+	ErrInvitationExpired = errors.New("invitation expired")
+)
+
+// invitation — ожидающее ответа приглашение.
+type invitation struct {
+	requester *model.Player
+	rule      LootRule
+	expires   time.Time
+}
+
+// Manager управляет группами и приглашениями.
+// Thread-safe: все изменения состава выполняются под одним mutex,
+// поэтому игрок не может оказаться в двух группах одновременно.
+type Manager struct {
+	mu       sync.Mutex
+	byMember map[uint32]*Party     // objectID → party
+	invites  map[uint32]invitation // target objectID → invitation
+
+	now func() time.Time
+}
+
+// NewManager создаёт менеджер групп.
+func NewManager() *Manager {
+	return &Manager{
+		byMember: make(map[uint32]*Party),
+		invites:  make(map[uint32]invitation),
+		now:      time.Now,
+	}
+}
+
+// PartyOf возвращает группу игрока (nil если не в группе).
+func (m *Manager) PartyOf(player *model.Player) *Party {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.byMember[player.ObjectID()]
+}
+
+// Invite регистрирует приглашение target в группу requester.
+// rule используется только если requester ещё не в группе.
+func (m *Manager) Invite(requester, target *model.Player, rule LootRule) error {
+	if requester == target {
+		return ErrSelfInvite
+	}
+	if !rule.IsValid() {
+		return ErrInvalidLootRule
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p := m.byMember[requester.ObjectID()]; p != nil {
+		if !p.IsLeader(requester) {
+			return ErrNotLeader
+		}
+		if p.Size() >= MaxMembers {
+			return ErrPartyFull
+		}
+	}
+	if m.byMember[target.ObjectID()] != nil {
+		return ErrAlreadyInParty
+	}
+	if inv, ok := m.invites[target.ObjectID()]; ok && m.now().Before(inv.expires) {
+		return ErrTargetBusy
+	}
+
+	m.invites[target.ObjectID()] = invitation{
+		requester: requester,
+		rule:      rule,
+		expires:   m.now().Add(InviteTimeout),
+	}
+	return nil
+}
+
+// PendingRequester возвращает игрока, пригласившего target (nil если приглашения нет).
+func (m *Manager) PendingRequester(target *model.Player) *model.Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.invites[target.ObjectID()]
+	if !ok {
+		return nil
+	}
+	return inv.requester
+}
+
+// Answer обрабатывает ответ на приглашение.
+// При accept=true возвращает группу, в которую вступил target (созданную при необходимости).
+// requester возвращается всегда, если приглашение существовало.
+func (m *Manager) Answer(target *model.Player, accept bool) (p *Party, requester *model.Player, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inv, ok := m.invites[target.ObjectID()]
+	if !ok {
+		return nil, nil, ErrNoInvitation
+	}
+	delete(m.invites, target.ObjectID())
+
+	if !accept {
+		return nil, inv.requester, nil
+	}
+	if m.now().After(inv.expires) {
+		return nil, inv.requester, ErrInvitationExpired
+	}
+	if m.byMember[target.ObjectID()] != nil {
+		return nil, inv.requester, ErrAlreadyInParty
+	}
+
+	p = m.byMember[inv.requester.ObjectID()]
+	if p == nil {
+		p = newParty(inv.requester, inv.rule)
+		m.byMember[inv.requester.ObjectID()] = p
+	} else if !p.IsLeader(inv.requester) {
+		return nil, inv.requester, ErrNotLeader
+	}
+
+	if !p.add(target) {
+		if p.Size() == 1 {
+			delete(m.byMember, inv.requester.ObjectID())
+		}
+		return nil, inv.requester, ErrPartyFull
+	}
+	m.byMember[target.ObjectID()] = p
+	return p, inv.requester, nil
+}
+
+// LeaveResult описывает изменения группы после выхода участника.
+type LeaveResult struct {
+	Party     *Party
+	Left      *model.Player
+	Remaining []*model.Player // участники, оставшиеся в группе (пусто если группа распущена)
+	Disbanded bool
+	NewLeader *model.Player // не nil если лидер сменился
+}
+
+// Leave удаляет игрока из группы.
+// Группа распускается если в ней остаётся меньше двух участников.
+func (m *Manager) Leave(player *model.Player) (LeaveResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removeLocked(player)
+}
+
+// Kick исключает участника по имени (только лидер).
+func (m *Manager) Kick(leader *model.Player, name string) (LeaveResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.byMember[leader.ObjectID()]
+	if p == nil {
+		return LeaveResult{}, ErrNotInParty
+	}
+	if !p.IsLeader(leader) {
+		return LeaveResult{}, ErrNotLeader
+	}
+	member := p.MemberByName(name)
+	if member == nil || member == leader {
+		return LeaveResult{}, ErrMemberNotFound
+	}
+	return m.removeLocked(member)
+}
+
+// ChangeLeader передаёт лидерство участнику по имени.
+func (m *Manager) ChangeLeader(leader *model.Player, name string) (*Party, *model.Player, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.byMember[leader.ObjectID()]
+	if p == nil {
+		return nil, nil, ErrNotInParty
+	}
+	if !p.IsLeader(leader) {
+		return nil, nil, ErrNotLeader
+	}
+	member := p.MemberByName(name)
+	if member == nil || !p.setLeader(member) {
+		return nil, nil, ErrMemberNotFound
+	}
+	return p, member, nil
+}
+
+func (m *Manager) removeLocked(player *model.Player) (LeaveResult, error) {
+	p := m.byMember[player.ObjectID()]
+	if p == nil {
+		return LeaveResult{}, ErrNotInParty
+	}
+
+	wasLeader := p.IsLeader(player)
+	p.remove(player)
+	delete(m.byMember, player.ObjectID())
+
+	res := LeaveResult{Party: p, Left: player}
+	remaining := p.Members()
+	if len(remaining) < 2 {
+		for _, other := range remaining {
+			delete(m.byMember, other.ObjectID())
+			p.remove(other)
+		}
+		res.Disbanded = true
+		res.Remaining = remaining
+		return res, nil
+	}
+
+	res.Remaining = remaining
+	if wasLeader {
+		res.NewLeader = remaining[0]
+	}
+	return res, nil
+}
+
+// CancelInvitations удаляет приглашения от и для игрока (выход из игры).
+func (m *Manager) CancelInvitations(player *model.Player) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.invites, player.ObjectID())
+	for target, inv := range m.invites {
+		if inv.requester == player {
+			delete(m.invites, target)
+		}
+	}
+}
+
+// ForEach вызывает fn для каждой группы.
+func (m *Manager) ForEach(fn func(*Party)) {
+	m.mu.Lock()
+	parties := make(map[*Party]struct{}, len(m.byMember)/2+1)
+	for _, p := range m.byMember {
+		parties[p] = struct{}{}
+	}
+	m.mu.Unlock()
+
+	for p := range parties {
+		fn(p)
+	}
+}
+
+// Start периодически вызывает fn для каждой группы (обновление позиций на миникарте).
+// Блокируется до отмены ctx.
+func (m *Manager) Start(ctx context.Context, interval time.Duration, fn func(*Party)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.ForEach(fn)
+		}
+	}
+}
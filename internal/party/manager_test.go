@@ -0,0 +1,210 @@
+package party
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestManager_InviteAndAnswer(t *testing.T) {
+	m := NewManager()
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	member := newTestPlayer(t, 2, "Member", 40)
+
+	if err := m.Invite(leader, member, LootRandom); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if got := m.PendingRequester(member); got != leader {
+		t.Fatalf("PendingRequester = %v, want leader", got)
+	}
+
+	p, requester, err := m.Answer(member, true)
+	if err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+	if requester != leader {
+		t.Errorf("requester = %v, want leader", requester)
+	}
+	if p.Size() != 2 || !p.IsLeader(leader) || p.LootRule() != LootRandom {
+		t.Errorf("party: size=%d leader=%v rule=%v", p.Size(), p.Leader().Name(), p.LootRule())
+	}
+	if m.PartyOf(leader) != p || m.PartyOf(member) != p {
+		t.Error("PartyOf should return the new party for both players")
+	}
+	if _, _, err := m.Answer(member, true); !errors.Is(err, ErrNoInvitation) {
+		t.Errorf("second Answer: err = %v, want ErrNoInvitation", err)
+	}
+}
+
+func TestManager_InviteErrors(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	c := newTestPlayer(t, 3, "PlayerC", 40)
+
+	if err := m.Invite(a, a, LootRandom); !errors.Is(err, ErrSelfInvite) {
+		t.Errorf("self invite: err = %v", err)
+	}
+	if err := m.Invite(a, b, LootRule(7)); !errors.Is(err, ErrInvalidLootRule) {
+		t.Errorf("invalid rule: err = %v", err)
+	}
+	if err := m.Invite(a, b, LootRandom); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if err := m.Invite(c, b, LootRandom); !errors.Is(err, ErrTargetBusy) {
+		t.Errorf("busy target: err = %v", err)
+	}
+	if _, _, err := m.Answer(b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+	if err := m.Invite(b, c, LootRandom); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("non-leader invite: err = %v", err)
+	}
+	if err := m.Invite(c, b, LootRandom); !errors.Is(err, ErrAlreadyInParty) {
+		t.Errorf("target in party: err = %v", err)
+	}
+}
+
+func TestManager_InvitationExpired(t *testing.T) {
+	m := NewManager()
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	c := newTestPlayer(t, 3, "PlayerC", 40)
+
+	if err := m.Invite(a, b, LootRandom); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	now = now.Add(InviteTimeout + time.Second)
+
+	// Просроченное приглашение не блокирует новое
+	if err := m.Invite(c, b, LootRandom); err != nil {
+		t.Fatalf("Invite after expiry: %v", err)
+	}
+	now = now.Add(InviteTimeout + time.Second)
+	if _, requester, err := m.Answer(b, true); !errors.Is(err, ErrInvitationExpired) || requester != c {
+		t.Errorf("Answer: requester=%v err=%v, want C and ErrInvitationExpired", requester, err)
+	}
+	if m.PartyOf(c) != nil {
+		t.Error("expired invitation should not create a party")
+	}
+}
+
+func TestManager_PartyFull(t *testing.T) {
+	m := NewManager()
+	leader := newTestPlayer(t, 1, "Leader", 40)
+	for i := range MaxMembers - 1 {
+		p := newTestPlayer(t, int64(10+i), "Member"+string(rune('a'+i)), 40)
+		if err := m.Invite(leader, p, LootRandom); err != nil {
+			t.Fatalf("Invite %d: %v", i, err)
+		}
+		if _, _, err := m.Answer(p, true); err != nil {
+			t.Fatalf("Answer %d: %v", i, err)
+		}
+	}
+	extra := newTestPlayer(t, 99, "Extra", 40)
+	if err := m.Invite(leader, extra, LootRandom); !errors.Is(err, ErrPartyFull) {
+		t.Errorf("err = %v, want ErrPartyFull", err)
+	}
+}
+
+func formParty(t *testing.T, m *Manager, leader *model.Player, members ...*model.Player) *Party {
+	t.Helper()
+	var p *Party
+	for _, pl := range members {
+		if err := m.Invite(leader, pl, LootRandom); err != nil {
+			t.Fatalf("Invite %s: %v", pl.Name(), err)
+		}
+		var err error
+		if p, _, err = m.Answer(pl, true); err != nil {
+			t.Fatalf("Answer %s: %v", pl.Name(), err)
+		}
+	}
+	return p
+}
+
+func TestManager_LeaveAndDisband(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	c := newTestPlayer(t, 3, "PlayerC", 40)
+	formParty(t, m, a, b, c)
+
+	// Лидер выходит — лидерство переходит к следующему участнику
+	res, err := m.Leave(a)
+	if err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	if res.Disbanded || res.NewLeader != b || len(res.Remaining) != 2 {
+		t.Errorf("leave: disbanded=%v newLeader=%v remaining=%d", res.Disbanded, res.NewLeader, len(res.Remaining))
+	}
+	if m.PartyOf(a) != nil {
+		t.Error("left player should not be in a party")
+	}
+
+	// Остаётся один участник — группа распускается
+	res, err = m.Leave(c)
+	if err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	if !res.Disbanded {
+		t.Error("party of one should be disbanded")
+	}
+	if m.PartyOf(b) != nil {
+		t.Error("remaining player should be released on disband")
+	}
+	if _, err := m.Leave(b); !errors.Is(err, ErrNotInParty) {
+		t.Errorf("Leave without party: err = %v", err)
+	}
+}
+
+func TestManager_KickAndChangeLeader(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	c := newTestPlayer(t, 3, "PlayerC", 40)
+	p := formParty(t, m, a, b, c)
+
+	if _, err := m.Kick(b, "PlayerC"); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("kick by member: err = %v", err)
+	}
+	if _, err := m.Kick(a, "Nobody"); !errors.Is(err, ErrMemberNotFound) {
+		t.Errorf("kick unknown: err = %v", err)
+	}
+
+	res, err := m.Kick(a, "PlayerC")
+	if err != nil {
+		t.Fatalf("Kick: %v", err)
+	}
+	if res.Left != c || res.Disbanded || p.Contains(c) {
+		t.Errorf("kick: left=%v disbanded=%v", res.Left.Name(), res.Disbanded)
+	}
+
+	if _, _, err := m.ChangeLeader(b, "PlayerA"); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("change leader by member: err = %v", err)
+	}
+	if _, newLeader, err := m.ChangeLeader(a, "PlayerB"); err != nil || newLeader != b {
+		t.Fatalf("ChangeLeader: leader=%v err=%v", newLeader, err)
+	}
+	if !p.IsLeader(b) {
+		t.Error("B should be the leader")
+	}
+}
+
+func TestManager_CancelInvitations(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+
+	if err := m.Invite(a, b, LootRandom); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	m.CancelInvitations(a)
+	if m.PendingRequester(b) != nil {
+		t.Error("invitation from disconnected player should be cancelled")
+	}
+}
@@ -0,0 +1,220 @@
+// Package party implements player parties: membership, loot distribution
+// and exp/SP sharing.
+package party
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// MaxMembers — максимальный размер группы (Interlude).
+const MaxMembers = 9
+
+// LootRule — режим распределения добычи (значения совпадают с клиентом).
+type LootRule int32
+
+const (
+	LootFindersKeepers       LootRule = 0
+	LootRandom               LootRule = 1
+	LootRandomIncludingSpoil LootRule = 2
+	LootByTurn               LootRule = 3
+	LootByTurnIncludingSpoil LootRule = 4
+)
+
+// String returns human-readable loot rule name
+func (r LootRule) String() string {
+	switch r {
+	case LootFindersKeepers:
+		return "FINDERS_KEEPERS"
+	case LootRandom:
+		return "RANDOM"
+	case LootRandomIncludingSpoil:
+		return "RANDOM_INCLUDING_SPOIL"
+	case LootByTurn:
+		return "BY_TURN"
+	case LootByTurnIncludingSpoil:
+		return "BY_TURN_INCLUDING_SPOIL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// IsValid returns true for the five Interlude loot rules.
+func (r LootRule) IsValid() bool {
+	return r >= LootFindersKeepers && r <= LootByTurnIncludingSpoil
+}
+
+// includesSpoil returns true if spoiled items are also distributed.
+func (r LootRule) includesSpoil() bool {
+	return r == LootRandomIncludingSpoil || r == LootByTurnIncludingSpoil
+}
+
+var partyIDCounter atomic.Int32
+
+// Party — группа игроков. Первый участник — лидер.
+// Thread-safe.
+type Party struct {
+	id       int32
+	lootRule LootRule
+
+	mu       sync.RWMutex
+	members  []*model.Player
+	lootTurn int // индекс следующего получателя для by-turn режимов
+}
+
+func newParty(leader *model.Player, rule LootRule) *Party {
+	return &Party{
+		id:       partyIDCounter.Add(1),
+		lootRule: rule,
+		members:  []*model.Player{leader},
+	}
+}
+
+// ID возвращает уникальный ID группы (в пределах процесса).
+func (p *Party) ID() int32 {
+	return p.id
+}
+
+// LootRule возвращает режим распределения добычи.
+func (p *Party) LootRule() LootRule {
+	return p.lootRule
+}
+
+// Leader возвращает лидера группы.
+func (p *Party) Leader() *model.Player {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.members) == 0 {
+		return nil
+	}
+	return p.members[0]
+}
+
+// IsLeader возвращает true если игрок — лидер группы.
+func (p *Party) IsLeader(player *model.Player) bool {
+	return p.Leader() == player
+}
+
+// Members возвращает копию списка участников (лидер первый).
+func (p *Party) Members() []*model.Player {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.members)
+}
+
+// Size возвращает количество участников.
+func (p *Party) Size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.members)
+}
+
+// Contains возвращает true если игрок состоит в группе.
+func (p *Party) Contains(player *model.Player) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Contains(p.members, player)
+}
+
+// MemberByName возвращает участника по имени.
+func (p *Party) MemberByName(name string) *model.Player {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, m := range p.members {
+		if m.Name() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (p *Party) add(player *model.Player) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.members) >= MaxMembers || slices.Contains(p.members, player) {
+		return false
+	}
+	p.members = append(p.members, player)
+	return true
+}
+
+func (p *Party) remove(player *model.Player) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := slices.Index(p.members, player)
+	if i < 0 {
+		return false
+	}
+	p.members = slices.Delete(p.members, i, i+1)
+	if p.lootTurn > i {
+		p.lootTurn--
+	}
+	return true
+}
+
+func (p *Party) setLeader(player *model.Player) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := slices.Index(p.members, player)
+	if i <= 0 {
+		return false
+	}
+	p.members[0], p.members[i] = p.members[i], p.members[0]
+	return true
+}
+
+// MembersInRange возвращает участников в радиусе rng от точки loc.
+func (p *Party) MembersInRange(loc model.Location, rng int64) []*model.Player {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]*model.Player, 0, len(p.members))
+	for _, m := range p.members {
+		if m.Location().DistanceSquared(loc) <= rng*rng {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// LootReceiver выбирает получателя предмета по правилу группы.
+// looter — игрок, подобравший предмет (или убивший моба при auto-loot);
+// spoil=true для предметов, полученных через Sweep.
+// Учитываются только участники в радиусе distributionRange от looter.
+func (p *Party) LootReceiver(looter *model.Player, spoil bool, distributionRange int64) *model.Player {
+	if p.lootRule == LootFindersKeepers || (spoil && !p.lootRule.includesSpoil()) {
+		return looter
+	}
+
+	candidates := p.MembersInRange(looter.Location(), distributionRange)
+	if len(candidates) == 0 {
+		return looter
+	}
+
+	switch p.lootRule {
+	case LootRandom, LootRandomIncludingSpoil:
+		return candidates[rand.IntN(len(candidates))]
+
+	case LootByTurn, LootByTurnIncludingSpoil:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		// Перебираем участников по кругу, пропуская тех, кто вне радиуса
+		for range len(p.members) {
+			if p.lootTurn >= len(p.members) {
+				p.lootTurn = 0
+			}
+			m := p.members[p.lootTurn]
+			p.lootTurn++
+			if slices.Contains(candidates, m) {
+				return m
+			}
+		}
+		return looter
+
+	default:
+		return looter
+	}
+}
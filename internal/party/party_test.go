@@ -0,0 +1,104 @@
+package party
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func newTestPlayer(t *testing.T, id int64, name string, level int32) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, name, level, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLocation(model.NewLocation(1000, 1000, 0, 0))
+	return p
+}
+
+func newTestParty(t *testing.T, rule LootRule, n int) (*Party, []*model.Player) {
+	t.Helper()
+	players := make([]*model.Player, n)
+	for i := range n {
+		players[i] = newTestPlayer(t, int64(100+i), "Player"+string(rune('A'+i)), 40)
+	}
+	p := newParty(players[0], rule)
+	for _, pl := range players[1:] {
+		if !p.add(pl) {
+			t.Fatalf("add %s failed", pl.Name())
+		}
+	}
+	return p, players
+}
+
+func TestLootRule_IsValid(t *testing.T) {
+	for r := LootFindersKeepers; r <= LootByTurnIncludingSpoil; r++ {
+		if !r.IsValid() {
+			t.Errorf("%v should be valid", r)
+		}
+	}
+	if LootRule(5).IsValid() || LootRule(-1).IsValid() {
+		t.Error("out of range rules should be invalid")
+	}
+}
+
+func TestParty_LootReceiver_FindersKeepers(t *testing.T) {
+	p, players := newTestParty(t, LootFindersKeepers, 3)
+	for range 10 {
+		if got := p.LootReceiver(players[1], false, DefaultRange); got != players[1] {
+			t.Fatalf("receiver = %s, want looter", got.Name())
+		}
+	}
+}
+
+func TestParty_LootReceiver_ByTurn(t *testing.T) {
+	p, players := newTestParty(t, LootByTurn, 3)
+
+	want := []*model.Player{players[0], players[1], players[2], players[0]}
+	for i, w := range want {
+		if got := p.LootReceiver(players[2], false, DefaultRange); got != w {
+			t.Errorf("turn %d: receiver = %s, want %s", i, got.Name(), w.Name())
+		}
+	}
+}
+
+func TestParty_LootReceiver_ByTurnSkipsOutOfRange(t *testing.T) {
+	p, players := newTestParty(t, LootByTurn, 3)
+	players[1].SetLocation(model.NewLocation(100000, 100000, 0, 0))
+
+	for i := range 4 {
+		if got := p.LootReceiver(players[0], false, DefaultRange); got == players[1] {
+			t.Fatalf("turn %d: out of range member received loot", i)
+		}
+	}
+}
+
+func TestParty_LootReceiver_Spoil(t *testing.T) {
+	p, players := newTestParty(t, LootByTurn, 3)
+	for range 5 {
+		if got := p.LootReceiver(players[2], true, DefaultRange); got != players[2] {
+			t.Fatalf("spoil without spoil rule: receiver = %s, want looter", got.Name())
+		}
+	}
+
+	p, players = newTestParty(t, LootByTurnIncludingSpoil, 3)
+	if got := p.LootReceiver(players[2], true, DefaultRange); got != players[0] {
+		t.Errorf("spoil with spoil rule: receiver = %s, want first member", got.Name())
+	}
+}
+
+func TestParty_LootReceiver_RandomInRange(t *testing.T) {
+	p, players := newTestParty(t, LootRandom, 4)
+	players[3].SetLocation(model.NewLocation(100000, 100000, 0, 0))
+
+	seen := make(map[*model.Player]bool)
+	for range 200 {
+		seen[p.LootReceiver(players[0], false, DefaultRange)] = true
+	}
+	if seen[players[3]] {
+		t.Error("out of range member received loot")
+	}
+	if len(seen) != 3 {
+		t.Errorf("distinct receivers = %d, want 3", len(seen))
+	}
+}
@@ -0,0 +1,89 @@
+package party
+
+import "github.com/udisondev/la2go/internal/model"
+
+// LevelCutoff — участники ниже (top level - LevelCutoff) не получают опыт
+// (L2J PARTY_XP_CUTOFF_METHOD=level, PARTY_XP_CUTOFF_LEVEL).
+const LevelCutoff = 20
+
+// bonusExpSp — множитель опыта/SP по количеству участников в радиусе (L2J BONUS_EXP_SP).
+var bonusExpSp = [...]float64{1.00, 1.00, 1.30, 1.39, 1.50, 1.54, 1.58, 1.63, 1.67, 1.71}
+
+// Reward — доля опыта и SP участника.
+type Reward struct {
+	Player *model.Player
+	Exp    int64
+	SP     int64
+}
+
+// DistributeExpSp делит опыт и SP за убийство между участниками в радиусе rng от loc.
+// Доля пропорциональна квадрату уровня (как в L2J), общая награда умножается
+// на бонус за размер группы. Участники ниже порога LevelCutoff исключаются.
+func DistributeExpSp(members []*model.Player, loc model.Location, exp, sp int64, rng int64) []Reward {
+	valid := make([]*model.Player, 0, len(members))
+	var topLevel int32
+	for _, m := range members {
+		if m.Location().DistanceSquared(loc) > rng*rng {
+			continue
+		}
+		valid = append(valid, m)
+		topLevel = max(topLevel, m.Level())
+	}
+
+	// Отсечка низкоуровневых участников
+	filtered := valid[:0]
+	var sqLevelSum int64
+	for _, m := range valid {
+		if m.Level() <= topLevel-LevelCutoff {
+			continue
+		}
+		filtered = append(filtered, m)
+		lvl := int64(m.Level())
+		sqLevelSum += lvl * lvl
+	}
+	if len(filtered) == 0 || sqLevelSum == 0 {
+		return nil
+	}
+
+	mul := bonusExpSp[min(len(filtered), len(bonusExpSp)-1)]
+	totalExp := float64(exp) * mul
+	totalSp := float64(sp) * mul
+
+	rewards := make([]Reward, 0, len(filtered))
+	for _, m := range filtered {
+		lvl := int64(m.Level())
+		share := float64(lvl*lvl) / float64(sqLevelSum)
+		rewards = append(rewards, Reward{
+			Player: m,
+			Exp:    int64(totalExp * share),
+			SP:     int64(totalSp * share),
+		})
+	}
+	return rewards
+}
+
+// RewardKill начисляет опыт и SP за убийство: в группе — по DistributeExpSp,
+// без группы — целиком убийце. Возвращает начисленные награды.
+func (m *Manager) RewardKill(killer *model.Player, victimLoc model.Location, exp, sp int64) []Reward {
+	var rewards []Reward
+	if p := m.PartyOf(killer); p != nil {
+		rewards = DistributeExpSp(p.Members(), victimLoc, exp, sp, DefaultRange)
+	} else {
+		rewards = []Reward{{Player: killer, Exp: exp, SP: sp}}
+	}
+
+	for _, r := range rewards {
+		r.Player.AddExperience(r.Exp)
+		r.Player.AddSP(r.SP)
+	}
+	return rewards
+}
+
+// LootReceiver выбирает получателя предмета, добытого looter: в группе — по правилу
+// лута группы (Party.LootReceiver), без группы — сам looter.
+func (m *Manager) LootReceiver(looter *model.Player, spoil bool) *model.Player {
+	if p := m.PartyOf(looter); p != nil {
+		return p.LootReceiver(looter, spoil, DefaultRange)
+	}
+	return looter
+}
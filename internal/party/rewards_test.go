@@ -0,0 +1,104 @@
+package party
+
+import (
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestDistributeExpSp_LevelWeighting(t *testing.T) {
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 30)
+	loc := model.NewLocation(1000, 1000, 0, 0)
+
+	rewards := DistributeExpSp([]*model.Player{a, b}, loc, 1000, 100, DefaultRange)
+	if len(rewards) != 2 {
+		t.Fatalf("rewards = %d, want 2", len(rewards))
+	}
+
+	// 1000 * 1.30 = 1300; A: 1600/2500, B: 900/2500
+	if rewards[0].Exp != 832 || rewards[1].Exp != 468 {
+		t.Errorf("exp = %d/%d, want 832/468", rewards[0].Exp, rewards[1].Exp)
+	}
+	if rewards[0].SP != 83 || rewards[1].SP != 46 {
+		t.Errorf("sp = %d/%d, want 83/46", rewards[0].SP, rewards[1].SP)
+	}
+}
+
+func TestDistributeExpSp_OutOfRange(t *testing.T) {
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	b.SetLocation(model.NewLocation(100000, 100000, 0, 0))
+
+	rewards := DistributeExpSp([]*model.Player{a, b}, a.Location(), 1000, 0, DefaultRange)
+	if len(rewards) != 1 || rewards[0].Player != a {
+		t.Fatalf("rewards = %+v, want only A", rewards)
+	}
+	// Single member in range — no party bonus
+	if rewards[0].Exp != 1000 {
+		t.Errorf("exp = %d, want 1000", rewards[0].Exp)
+	}
+}
+
+func TestDistributeExpSp_LevelCutoff(t *testing.T) {
+	a := newTestPlayer(t, 1, "PlayerA", 60)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+
+	rewards := DistributeExpSp([]*model.Player{a, b}, a.Location(), 1000, 0, DefaultRange)
+	if len(rewards) != 1 || rewards[0].Player != a {
+		t.Fatalf("rewards = %+v, want only A", rewards)
+	}
+}
+
+func TestManager_RewardKill(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	solo := newTestPlayer(t, 3, "Solo", 40)
+
+	if err := m.Invite(a, b, LootFindersKeepers); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, _, err := m.Answer(b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+
+	expA, expB := a.Experience(), b.Experience()
+	m.RewardKill(a, a.Location(), 1000, 100)
+	if a.Experience()-expA != 650 || b.Experience()-expB != 650 {
+		t.Errorf("party exp gain = %d/%d, want 650/650", a.Experience()-expA, b.Experience()-expB)
+	}
+	if a.SP() != 65 || b.SP() != 65 {
+		t.Errorf("party sp = %d/%d, want 65/65", a.SP(), b.SP())
+	}
+
+	expSolo := solo.Experience()
+	m.RewardKill(solo, solo.Location(), 1000, 100)
+	if solo.Experience()-expSolo != 1000 || solo.SP() != 100 {
+		t.Errorf("solo gain = %d exp / %d sp, want 1000/100", solo.Experience()-expSolo, solo.SP())
+	}
+}
+
+func TestManager_LootReceiver(t *testing.T) {
+	m := NewManager()
+	a := newTestPlayer(t, 1, "PlayerA", 40)
+	b := newTestPlayer(t, 2, "PlayerB", 40)
+	solo := newTestPlayer(t, 3, "Solo", 40)
+
+	if err := m.Invite(a, b, LootByTurn); err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, _, err := m.Answer(b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+
+	if got := m.LootReceiver(b, false); got != a {
+		t.Errorf("first turn: receiver = %s, want %s", got.Name(), a.Name())
+	}
+	if got := m.LootReceiver(b, false); got != b {
+		t.Errorf("second turn: receiver = %s, want %s", got.Name(), b.Name())
+	}
+	if got := m.LootReceiver(solo, false); got != solo {
+		t.Errorf("solo: receiver = %s, want looter", got.Name())
+	}
+}
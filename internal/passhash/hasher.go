@@ -0,0 +1,70 @@
+package passhash
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// Hasher hashes and verifies passwords on a bounded number of workers, so a burst of
+// logins queues up instead of taking all CPUs and Memory × logins of RAM. Thread-safe.
+type Hasher struct {
+	params  Params
+	workers chan struct{} // one token per running hash
+}
+
+// NewHasher creates a hasher with the given cost (zero fields take DefaultParams
+// values) running at most workers hashes at once (0 = one per CPU).
+func NewHasher(params Params, workers int) *Hasher {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Hasher{
+		params:  params.withDefaults(),
+		workers: make(chan struct{}, workers),
+	}
+}
+
+// Params returns the cost of new hashes.
+func (h *Hasher) Params() Params {
+	return h.params
+}
+
+// run waits for a free worker and calls fn on it.
+// Returns ctx.Err() if ctx ends before a worker is free.
+func (h *Hasher) run(ctx context.Context, fn func()) error {
+	select {
+	case h.workers <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for a password hash worker: %w", ctx.Err())
+	}
+	defer func() { <-h.workers }()
+	fn()
+	return nil
+}
+
+// Hash returns a new salted hash of password.
+func (h *Hasher) Hash(ctx context.Context, password string) (string, error) {
+	var (
+		hashed string
+		err    error
+	)
+	if runErr := h.run(ctx, func() { hashed, err = hash(password, h.params) }); runErr != nil {
+		return "", runErr
+	}
+	return hashed, err
+}
+
+// Verify checks password against a stored hash of either format. rehash reports that
+// the password matched but its hash should be replaced by a new one from Hash: it is
+// a legacy SHA-1 hash or was made with a different cost.
+func (h *Hasher) Verify(ctx context.Context, password, stored string) (ok, rehash bool, err error) {
+	if IsLegacy(stored) {
+		// SHA-1 is cheap, no need to wait for a worker
+		return verify(password, stored, h.params)
+	}
+	if runErr := h.run(ctx, func() { ok, rehash, err = verify(password, stored, h.params) }); runErr != nil {
+		return false, false, runErr
+	}
+	return ok, rehash, err
+}
@@ -0,0 +1,130 @@
+// Package passhash stores account passwords as salted argon2id hashes and still
+// verifies the unsalted SHA-1 hashes of L2J, so old accounts can be migrated on login.
+//
+// New hashes use the PHC string format, which records the algorithm, its version and
+// the cost parameters next to the salt:
+//
+//	$argon2id$v=19$m=65536,t=2,p=2$<salt>$<key>
+//
+// Legacy hashes are base64(SHA-1(password)) and never start with "$".
+package passhash
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrInvalidHash is returned for stored hashes in no known format
+var ErrInvalidHash = errors.New("invalid password hash")
+
+const (
+	algorithm = "argon2id"
+	saltLen   = 16
+	keyLen    = 32
+)
+
+// Params are the argon2id cost parameters.
+type Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultParams returns the cost used when none is configured: 64 MiB, 2 passes, 2 lanes.
+func DefaultParams() Params {
+	return Params{Memory: 64 * 1024, Iterations: 2, Parallelism: 2}
+}
+
+// withDefaults fills zero parameters from DefaultParams.
+func (p Params) withDefaults() Params {
+	d := DefaultParams()
+	if p.Memory == 0 {
+		p.Memory = d.Memory
+	}
+	if p.Iterations == 0 {
+		p.Iterations = d.Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = d.Parallelism
+	}
+	return p
+}
+
+// Legacy returns the L2J hash of a password: base64(SHA-1(password)).
+func Legacy(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// IsLegacy reports whether a stored hash is in the L2J SHA-1 format.
+func IsLegacy(hash string) bool {
+	return !strings.HasPrefix(hash, "$")
+}
+
+// hash derives a new argon2id hash with a random salt. CPU and memory heavy.
+func hash(password string, p Params) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, keyLen)
+	return format(p, salt, key), nil
+}
+
+func format(p Params, salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		algorithm, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+// parse splits an argon2id hash into its parameters, salt and key.
+func parse(hash string) (Params, []byte, []byte, error) {
+	var p Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != algorithm {
+		return p, nil, nil, ErrInvalidHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("argon2 version %q: %w", parts[2], ErrInvalidHash)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("argon2 parameters %q: %w", parts[3], ErrInvalidHash)
+	}
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, fmt.Errorf("argon2 parameters %q: %w", parts[3], ErrInvalidHash)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("salt: %w", ErrInvalidHash)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, fmt.Errorf("key: %w", ErrInvalidHash)
+	}
+	return p, salt, key, nil
+}
+
+// verify checks a password against a stored hash of either format. rehash is true for
+// matching passwords whose hash is legacy or made with other parameters than p.
+func verify(password, stored string, p Params) (ok, rehash bool, err error) {
+	if IsLegacy(stored) {
+		ok = subtle.ConstantTimeCompare([]byte(Legacy(password)), []byte(stored)) == 1
+		return ok, ok, nil
+	}
+
+	hp, salt, key, err := parse(stored)
+	if err != nil {
+		return false, false, err
+	}
+	got := argon2.IDKey([]byte(password), salt, hp.Iterations, hp.Memory, hp.Parallelism, uint32(len(key)))
+	ok = subtle.ConstantTimeCompare(got, key) == 1
+	return ok, ok && hp != p, nil
+}
@@ -0,0 +1,98 @@
+package passhash
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testParams keeps tests fast; the format does not depend on the cost.
+var testParams = Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestHasher_HashVerify(t *testing.T) {
+	ctx := context.Background()
+	h := NewHasher(testParams, 2)
+
+	hashed, err := h.Hash(ctx, "secret")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !strings.HasPrefix(hashed, "$argon2id$v=19$m=64,t=1,p=1$") || IsLegacy(hashed) {
+		t.Fatalf("Hash() = %q, want an argon2id PHC string", hashed)
+	}
+	if again, _ := h.Hash(ctx, "secret"); again == hashed {
+		t.Error("two hashes of a password are equal, salt not random")
+	}
+
+	if ok, rehash, err := h.Verify(ctx, "secret", hashed); !ok || rehash || err != nil {
+		t.Errorf("Verify(right) = %v, %v, %v; want true, false, nil", ok, rehash, err)
+	}
+	if ok, _, err := h.Verify(ctx, "Secret", hashed); ok || err != nil {
+		t.Errorf("Verify(wrong) = %v, %v; want false, nil", ok, err)
+	}
+
+	// A hash made with another cost still verifies but asks to be redone
+	stronger := NewHasher(Params{Memory: 128, Iterations: 1, Parallelism: 1}, 1)
+	if ok, rehash, _ := stronger.Verify(ctx, "secret", hashed); !ok || !rehash {
+		t.Errorf("Verify(other cost) = %v, %v; want true, true", ok, rehash)
+	}
+}
+
+func TestHasher_Legacy(t *testing.T) {
+	ctx := context.Background()
+	h := NewHasher(testParams, 1)
+
+	// L2J: base64(SHA-1("admin"))
+	legacy := Legacy("admin")
+	if legacy != "0DPiKuNIrrVmD8IUCuw1hQxNqZc=" {
+		t.Fatalf("Legacy() = %q", legacy)
+	}
+	if !IsLegacy(legacy) {
+		t.Error("IsLegacy() = false for a SHA-1 hash")
+	}
+	if ok, rehash, err := h.Verify(ctx, "admin", legacy); !ok || !rehash || err != nil {
+		t.Errorf("Verify(legacy) = %v, %v, %v; want true, true, nil", ok, rehash, err)
+	}
+	if ok, rehash, _ := h.Verify(ctx, "wrong", legacy); ok || rehash {
+		t.Errorf("Verify(wrong legacy) = %v, %v; want false, false", ok, rehash)
+	}
+}
+
+func TestHasher_InvalidHash(t *testing.T) {
+	h := NewHasher(testParams, 1)
+	for _, stored := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$bcrypt$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		if _, _, err := h.Verify(context.Background(), "secret", stored); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("Verify(%q) error = %v, want ErrInvalidHash", stored, err)
+		}
+	}
+}
+
+func TestHasher_BoundedWorkers(t *testing.T) {
+	h := NewHasher(testParams, 1)
+
+	// Hold the only worker; a hash waits and gives up with its context
+	h.workers <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := h.Hash(ctx, "secret"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Hash() with busy workers error = %v, want DeadlineExceeded", err)
+	}
+
+	// Legacy hashes are checked without a worker
+	if ok, _, err := h.Verify(ctx, "admin", Legacy("admin")); !ok || err != nil {
+		t.Errorf("Verify(legacy) with busy workers = %v, %v", ok, err)
+	}
+
+	<-h.workers
+	if _, err := h.Hash(context.Background(), "secret"); err != nil {
+		t.Errorf("Hash() after the worker was freed: %v", err)
+	}
+}
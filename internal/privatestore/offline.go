@@ -0,0 +1,220 @@
+package privatestore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// OfflineStore — сохранённое состояние магазина offline-торговца.
+type OfflineStore struct {
+	CharacterID int64
+	StoreType   model.PrivateStoreType
+	Title       string
+	Packaged    bool
+	Items       []model.TradeItem       // sell/buy магазин
+	Recipes     []model.ManufactureItem // manufacture магазин
+	StartedAt   time.Time
+}
+
+// OfflineRepository хранит offline-магазины между рестартами сервера.
+type OfflineRepository interface {
+	SaveOfflineStore(ctx context.Context, store OfflineStore) error
+	DeleteOfflineStore(ctx context.Context, characterID int64) error
+	LoadOfflineStores(ctx context.Context) ([]OfflineStore, error)
+}
+
+// PlayerLoader загружает персонажа с инвентарём для восстановления магазина.
+type PlayerLoader interface {
+	LoadPlayer(ctx context.Context, characterID int64) (*model.Player, error)
+}
+
+// PlayerLoaderFunc адаптирует функцию к PlayerLoader.
+type PlayerLoaderFunc func(ctx context.Context, characterID int64) (*model.Player, error)
+
+// LoadPlayer вызывает f(ctx, characterID).
+func (f PlayerLoaderFunc) LoadPlayer(ctx context.Context, characterID int64) (*model.Player, error) {
+	return f(ctx, characterID)
+}
+
+// WorldAdder добавляет восстановленного торговца в мир.
+type WorldAdder interface {
+	AddObject(obj *model.WorldObject) error
+}
+
+// OfflineStores управляет магазинами, которые остаются в мире после выхода клиента.
+type OfflineStores struct {
+	repo   OfflineRepository
+	loader PlayerLoader
+
+	mu      sync.RWMutex
+	players map[uint32]*model.Player // objectID → offline player
+}
+
+// NewOfflineStores создаёт менеджер offline-магазинов.
+func NewOfflineStores(repo OfflineRepository, loader PlayerLoader) *OfflineStores {
+	return &OfflineStores{
+		repo:    repo,
+		loader:  loader,
+		players: make(map[uint32]*model.Player),
+	}
+}
+
+// Snapshot возвращает описание активного магазина игрока.
+// ok=false если магазин не открыт.
+func Snapshot(p *model.Player) (OfflineStore, bool) {
+	st := p.PrivateStoreType()
+	if !st.IsActive() {
+		return OfflineStore{}, false
+	}
+
+	store := OfflineStore{
+		CharacterID: p.CharacterID(),
+		StoreType:   st,
+		Title:       Title(p),
+		StartedAt:   time.Now(),
+	}
+	switch st {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		store.Items = p.SellList().Items()
+		store.Packaged = p.SellList().IsPackaged()
+	case model.PrivateStoreBuy:
+		store.Items = p.BuyList().Items()
+	case model.PrivateStoreManufacture:
+		store.Recipes = p.ManufactureList().Items()
+	}
+	return store, true
+}
+
+// Keep переводит магазин игрока в offline-режим при отключении клиента.
+// Возвращает false если у игрока нет активного магазина (игрок должен покинуть мир).
+func (o *OfflineStores) Keep(ctx context.Context, p *model.Player) (bool, error) {
+	store, ok := Snapshot(p)
+	if !ok {
+		return false, nil
+	}
+
+	if err := o.repo.SaveOfflineStore(ctx, store); err != nil {
+		return false, fmt.Errorf("saving offline store of character %d: %w", store.CharacterID, err)
+	}
+
+	p.SetOffline(true)
+	o.mu.Lock()
+	o.players[p.ObjectID()] = p
+	o.mu.Unlock()
+	return true, nil
+}
+
+// Sync сохраняет текущее состояние offline-магазина после сделки.
+// Если магазин закрылся (всё продано/куплено), запись удаляется и торговец
+// возвращается вызывающему для удаления из мира.
+func (o *OfflineStores) Sync(ctx context.Context, p *model.Player) (released bool, err error) {
+	if !p.IsOffline() {
+		return false, nil
+	}
+
+	store, ok := Snapshot(p)
+	if ok {
+		if err := o.repo.SaveOfflineStore(ctx, store); err != nil {
+			return false, fmt.Errorf("updating offline store of character %d: %w", store.CharacterID, err)
+		}
+		return false, nil
+	}
+
+	return true, o.Release(ctx, p)
+}
+
+// Release удаляет offline-магазин (магазин опустел или персонаж вошёл в игру).
+func (o *OfflineStores) Release(ctx context.Context, p *model.Player) error {
+	o.mu.Lock()
+	delete(o.players, p.ObjectID())
+	o.mu.Unlock()
+
+	p.SetOffline(false)
+	if err := o.repo.DeleteOfflineStore(ctx, p.CharacterID()); err != nil {
+		return fmt.Errorf("deleting offline store of character %d: %w", p.CharacterID(), err)
+	}
+	return nil
+}
+
+// Get возвращает offline-торговца по objectID.
+func (o *OfflineStores) Get(objectID uint32) (*model.Player, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	p, ok := o.players[objectID]
+	return p, ok
+}
+
+// Count возвращает количество offline-магазинов.
+func (o *OfflineStores) Count() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.players)
+}
+
+// Restore загружает offline-магазины после рестарта и добавляет торговцев в мир.
+// Магазины, которые больше не валидны (предметы пропали), удаляются.
+// Возвращает количество восстановленных магазинов.
+func (o *OfflineStores) Restore(ctx context.Context, svc *Service, w WorldAdder) (int, error) {
+	stores, err := o.repo.LoadOfflineStores(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("loading offline stores: %w", err)
+	}
+
+	restored := 0
+	for _, store := range stores {
+		p, err := o.restoreOne(ctx, svc, store)
+		if err != nil {
+			slog.Warn("dropping offline store",
+				"characterID", store.CharacterID,
+				"type", store.StoreType,
+				"error", err)
+			if err := o.repo.DeleteOfflineStore(ctx, store.CharacterID); err != nil {
+				slog.Error("failed to delete offline store", "characterID", store.CharacterID, "error", err)
+			}
+			continue
+		}
+
+		if err := w.AddObject(p.WorldObject); err != nil {
+			slog.Warn("failed to add offline trader to world", "characterID", store.CharacterID, "error", err)
+			continue
+		}
+
+		p.SetOffline(true)
+		o.mu.Lock()
+		o.players[p.ObjectID()] = p
+		o.mu.Unlock()
+		restored++
+	}
+	return restored, nil
+}
+
+func (o *OfflineStores) restoreOne(ctx context.Context, svc *Service, store OfflineStore) (*model.Player, error) {
+	p, err := o.loader.LoadPlayer(ctx, store.CharacterID)
+	if err != nil {
+		return nil, fmt.Errorf("loading character: %w", err)
+	}
+
+	switch store.StoreType {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		err = svc.OpenSellStore(p, store.Items, store.Packaged)
+	case model.PrivateStoreBuy:
+		err = svc.OpenBuyStore(p, store.Items)
+	case model.PrivateStoreManufacture:
+		err = svc.OpenManufactureStore(p, store.Recipes)
+	default:
+		err = fmt.Errorf("unsupported store type %s", store.StoreType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.SetTitle(p, store.StoreType, store.Title); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
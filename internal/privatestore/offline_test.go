@@ -0,0 +1,168 @@
+package privatestore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+type fakeOfflineRepo struct {
+	mu     sync.Mutex
+	stores map[int64]OfflineStore
+}
+
+func newFakeOfflineRepo() *fakeOfflineRepo {
+	return &fakeOfflineRepo{stores: make(map[int64]OfflineStore)}
+}
+
+func (r *fakeOfflineRepo) SaveOfflineStore(_ context.Context, store OfflineStore) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stores[store.CharacterID] = store
+	return nil
+}
+
+func (r *fakeOfflineRepo) DeleteOfflineStore(_ context.Context, characterID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.stores, characterID)
+	return nil
+}
+
+func (r *fakeOfflineRepo) LoadOfflineStores(_ context.Context) ([]OfflineStore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]OfflineStore, 0, len(r.stores))
+	for _, s := range r.stores {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+type fakeWorld struct {
+	objects map[uint32]*model.WorldObject
+}
+
+func (w *fakeWorld) AddObject(obj *model.WorldObject) error {
+	w.objects[obj.ObjectID()] = obj
+	return nil
+}
+
+func TestOfflineStores_KeepAndSync(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(0)
+	repo := newFakeOfflineRepo()
+	offline := NewOfflineStores(repo, nil)
+
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	arrows := give(t, seller, itemArrows, 10)
+	give(t, buyer, model.AdenaItemID, 100)
+
+	// Без магазина игрок не остаётся в мире
+	if kept, err := offline.Keep(ctx, seller); err != nil || kept {
+		t.Fatalf("Keep without store = %v, %v; want false, nil", kept, err)
+	}
+
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: arrows.ObjectID(), Count: 10, Price: 2}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+	if err := svc.SetTitle(seller, model.PrivateStoreSell, "arrows"); err != nil {
+		t.Fatalf("SetTitle: %v", err)
+	}
+
+	kept, err := offline.Keep(ctx, seller)
+	if err != nil || !kept {
+		t.Fatalf("Keep = %v, %v; want true, nil", kept, err)
+	}
+	if !seller.IsOffline() {
+		t.Error("seller must be marked offline")
+	}
+	if p, ok := offline.Get(seller.ObjectID()); !ok || p != seller {
+		t.Error("offline trader must be registered")
+	}
+	if saved := repo.stores[seller.CharacterID()]; saved.Title != "arrows" || len(saved.Items) != 1 {
+		t.Errorf("saved store = %+v", saved)
+	}
+
+	// Частичная покупка обновляет сохранённый магазин
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 4, Price: 2}}); err != nil {
+		t.Fatalf("Buy: %v", err)
+	}
+	if released, err := offline.Sync(ctx, seller); err != nil || released {
+		t.Fatalf("Sync = %v, %v; want false, nil", released, err)
+	}
+	if got := repo.stores[seller.CharacterID()].Items[0].Count; got != 6 {
+		t.Errorf("saved count = %d, want 6", got)
+	}
+
+	// Распродажа освобождает торговца
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 6, Price: 2}}); err != nil {
+		t.Fatalf("Buy rest: %v", err)
+	}
+	released, err := offline.Sync(ctx, seller)
+	if err != nil || !released {
+		t.Fatalf("Sync = %v, %v; want true, nil", released, err)
+	}
+	if _, ok := repo.stores[seller.CharacterID()]; ok {
+		t.Error("store record must be deleted")
+	}
+	if offline.Count() != 0 || seller.IsOffline() {
+		t.Error("trader must be released")
+	}
+}
+
+func TestOfflineStores_Restore(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeOfflineRepo()
+
+	const arrowsID = 5000
+	loader := PlayerLoaderFunc(func(_ context.Context, characterID int64) (*model.Player, error) {
+		if characterID == 3 {
+			return nil, errors.New("character deleted")
+		}
+		p := newPlayer(t, characterID)
+		arrows := give(t, p, itemArrows, 10)
+		arrows.SetItemID(arrowsID)
+		return p, nil
+	})
+
+	repo.stores[1] = OfflineStore{
+		CharacterID: 1,
+		StoreType:   model.PrivateStoreSell,
+		Title:       "restored",
+		Items:       []model.TradeItem{{ObjectID: arrowsID, Count: 5, Price: 3}},
+	}
+	// Персонаж удалён — магазин должен быть удалён при восстановлении
+	repo.stores[3] = OfflineStore{CharacterID: 3, StoreType: model.PrivateStoreSell}
+
+	w := &fakeWorld{objects: make(map[uint32]*model.WorldObject)}
+	offline := NewOfflineStores(repo, loader)
+
+	restored, err := offline.Restore(ctx, NewService(0), w)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("restored = %d, want 1", restored)
+	}
+
+	trader, ok := offline.Get(1)
+	if !ok {
+		t.Fatal("restored trader not registered")
+	}
+	if trader.PrivateStoreType() != model.PrivateStoreSell || Title(trader) != "restored" || !trader.IsOffline() {
+		t.Errorf("restored store: type=%s title=%q offline=%v", trader.PrivateStoreType(), Title(trader), trader.IsOffline())
+	}
+	if it, ok := trader.SellList().ItemByObjectID(arrowsID); !ok || it.Count != 5 {
+		t.Errorf("restored list item = %+v, %v", it, ok)
+	}
+	if _, ok := w.objects[1]; !ok {
+		t.Error("trader must be added to world")
+	}
+	if _, ok := repo.stores[3]; ok {
+		t.Error("invalid store must be deleted")
+	}
+}
@@ -0,0 +1,490 @@
+package privatestore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Defaults from L2J (Config.MAX_PVTSTORESELL_SLOTS_* / MAX_PVTSTOREBUY_SLOTS_*).
+const (
+	DefaultMaxSlots = 4
+
+	// InteractionDistance — максимальная дистанция покупателя до магазина (game units).
+	InteractionDistance = 150
+
+	// MaxTitleLength — максимальная длина сообщения магазина (как в клиенте).
+	MaxTitleLength = 29
+)
+
+var (
+	ErrStoreClosed    = errors.New("private store is closed")
+	ErrSelfTrade      = errors.New("cannot trade with own store")
+	ErrTooFar         = errors.New("store is too far")
+	ErrEmptyList      = errors.New("store list is empty")
+	ErrTooManyItems   = errors.New("too many items in store list")
+	ErrItemNotInStore = errors.New("item is not in store list")
+	ErrPriceMismatch  = errors.New("price does not match store list")
+	ErrCountExceeded  = errors.New("count exceeds store list")
+	ErrPackageSale    = errors.New("package sale must be bought entirely")
+	ErrNotEnoughAdena = errors.New("not enough adena")
+	ErrNotEnoughItems = errors.New("not enough items")
+	ErrInvalidItem    = errors.New("invalid store item")
+	ErrDuplicateItem  = errors.New("item is listed twice")
+	ErrTitleTooLong   = errors.New("store message too long")
+	ErrAdenaOverflow  = errors.New("total price overflow")
+	ErrItemOverflow   = errors.New("too many items for the receiving inventory")
+)
+
+// ItemRequest — позиция покупки/продажи, присланная клиентом.
+type ItemRequest struct {
+	ObjectID uint32 // предмет (sell-магазин: в инвентаре продавца; buy-магазин: в инвентаре клиента)
+	ItemType int32  // тип предмета (используется buy-магазином)
+	Count    int32
+	Price    int32 // цена за штуку, которую видел клиент
+}
+
+// Service реализует логику личных магазинов.
+// Сделки с одним магазином сериализуются per-player mutex'ами, поэтому
+// проверка содержимого/цен и перенос предметов выполняются атомарно.
+type Service struct {
+	maxSlots int
+
+	locks sync.Map // map[uint32]*sync.Mutex — objectID → trade lock
+}
+
+// NewService создаёт сервис личных магазинов.
+// maxSlots <= 0 означает DefaultMaxSlots.
+func NewService(maxSlots int) *Service {
+	if maxSlots <= 0 {
+		maxSlots = DefaultMaxSlots
+	}
+	return &Service{maxSlots: maxSlots}
+}
+
+// MaxSlots возвращает максимальное количество позиций в магазине.
+func (s *Service) MaxSlots() int {
+	return s.maxSlots
+}
+
+// OpenSellStore открывает магазин продажи.
+// packaged=true — покупатель обязан купить весь список (package sale).
+func (s *Service) OpenSellStore(p *model.Player, items []model.TradeItem, packaged bool) error {
+	if len(items) == 0 {
+		return ErrEmptyList
+	}
+	if len(items) > s.maxSlots {
+		return ErrTooManyItems
+	}
+
+	unlock := s.lockPlayers(p, nil)
+	defer unlock()
+
+	// Новый список заменяет открытый магазин или режим настройки
+	p.SetPrivateStoreType(model.PrivateStoreNone)
+
+	inv := p.Inventory()
+	seen := make(map[uint32]struct{}, len(items))
+	validated := make([]model.TradeItem, 0, len(items))
+	for _, it := range items {
+		if _, dup := seen[it.ObjectID]; dup {
+			return fmt.Errorf("%w: objectID %d", ErrDuplicateItem, it.ObjectID)
+		}
+		seen[it.ObjectID] = struct{}{}
+		item := inv.ItemByObjectID(it.ObjectID)
+		if item == nil || item.IsEquipped() {
+			return fmt.Errorf("%w: objectID %d", ErrInvalidItem, it.ObjectID)
+		}
+		if it.Count <= 0 || it.Count > item.Count() || it.Price < 0 {
+			return fmt.Errorf("%w: objectID %d count %d price %d", ErrInvalidItem, it.ObjectID, it.Count, it.Price)
+		}
+		validated = append(validated, model.TradeItem{
+			ObjectID: it.ObjectID,
+			ItemType: item.ItemType(),
+			Enchant:  item.Enchant(),
+			Count:    it.Count,
+			Price:    it.Price,
+		})
+	}
+
+	list := p.SellList()
+	list.SetItems(validated)
+	list.SetPackaged(packaged)
+
+	if packaged {
+		p.SetPrivateStoreType(model.PrivateStorePackageSell)
+	} else {
+		p.SetPrivateStoreType(model.PrivateStoreSell)
+	}
+	p.SetSitting(true)
+	return nil
+}
+
+// OpenBuyStore открывает магазин покупки.
+// Владелец должен иметь адену на полную стоимость списка.
+func (s *Service) OpenBuyStore(p *model.Player, items []model.TradeItem) error {
+	if len(items) == 0 {
+		return ErrEmptyList
+	}
+	if len(items) > s.maxSlots {
+		return ErrTooManyItems
+	}
+
+	unlock := s.lockPlayers(p, nil)
+	defer unlock()
+
+	p.SetPrivateStoreType(model.PrivateStoreNone)
+
+	var total int64
+	seen := make(map[int32]struct{}, len(items))
+	validated := make([]model.TradeItem, 0, len(items))
+	for _, it := range items {
+		if it.ItemType <= 0 || it.Count <= 0 || it.Price < 0 {
+			return fmt.Errorf("%w: itemType %d count %d price %d", ErrInvalidItem, it.ItemType, it.Count, it.Price)
+		}
+		if _, dup := seen[it.ItemType]; dup {
+			return fmt.Errorf("%w: itemType %d", ErrDuplicateItem, it.ItemType)
+		}
+		seen[it.ItemType] = struct{}{}
+		total += int64(it.Count) * int64(it.Price)
+		if total > math.MaxInt32 {
+			return ErrAdenaOverflow
+		}
+		validated = append(validated, model.TradeItem{
+			ItemType: it.ItemType,
+			Enchant:  it.Enchant,
+			Count:    it.Count,
+			Price:    it.Price,
+		})
+	}
+
+	if total > p.Inventory().Adena() {
+		return ErrNotEnoughAdena
+	}
+
+	p.BuyList().SetItems(validated)
+	p.SetPrivateStoreType(model.PrivateStoreBuy)
+	p.SetSitting(true)
+	return nil
+}
+
+// OpenManufactureStore открывает dwarven manufacture магазин.
+func (s *Service) OpenManufactureStore(p *model.Player, items []model.ManufactureItem) error {
+	if len(items) == 0 {
+		return ErrEmptyList
+	}
+
+	unlock := s.lockPlayers(p, nil)
+	defer unlock()
+
+	p.SetPrivateStoreType(model.PrivateStoreNone)
+
+	for _, it := range items {
+		if it.RecipeID <= 0 || it.Cost < 0 {
+			return fmt.Errorf("%w: recipe %d cost %d", ErrInvalidItem, it.RecipeID, it.Cost)
+		}
+	}
+
+	p.ManufactureList().SetItems(items)
+	p.SetPrivateStoreType(model.PrivateStoreManufacture)
+	p.SetSitting(true)
+	return nil
+}
+
+// SetTitle устанавливает сообщение магазина для указанного режима.
+func (s *Service) SetTitle(p *model.Player, storeType model.PrivateStoreType, title string) error {
+	if len([]rune(title)) > MaxTitleLength {
+		return ErrTitleTooLong
+	}
+
+	switch storeType {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		p.SellList().SetTitle(title)
+	case model.PrivateStoreBuy:
+		p.BuyList().SetTitle(title)
+	case model.PrivateStoreManufacture:
+		p.ManufactureList().SetTitle(title)
+	default:
+		return fmt.Errorf("unsupported store type %s", storeType)
+	}
+	return nil
+}
+
+// Title возвращает сообщение активного магазина игрока.
+func Title(p *model.Player) string {
+	switch p.PrivateStoreType() {
+	case model.PrivateStoreSell, model.PrivateStorePackageSell:
+		return p.SellList().Title()
+	case model.PrivateStoreBuy:
+		return p.BuyList().Title()
+	case model.PrivateStoreManufacture:
+		return p.ManufactureList().Title()
+	default:
+		return ""
+	}
+}
+
+// CloseStore закрывает магазин и поднимает игрока.
+func (s *Service) CloseStore(p *model.Player) {
+	unlock := s.lockPlayers(p, nil)
+	defer unlock()
+
+	closeLocked(p)
+}
+
+// closeLocked закрывает магазин; trade lock игрока уже захвачен.
+func closeLocked(p *model.Player) {
+	p.SetPrivateStoreType(model.PrivateStoreNone)
+	p.SellList().Clear()
+	p.BuyList().Clear()
+	p.ManufactureList().SetItems(nil)
+	p.SetSitting(false)
+}
+
+// Buy выполняет покупку в sell-магазине seller.
+// Возвращает суммарную стоимость и флаг закрытия магазина (всё продано).
+func (s *Service) Buy(buyer, seller *model.Player, reqs []ItemRequest) (int64, bool, error) {
+	if buyer == seller {
+		return 0, false, ErrSelfTrade
+	}
+	if len(reqs) == 0 {
+		return 0, false, ErrEmptyList
+	}
+
+	unlock := s.lockPlayers(buyer, seller)
+	defer unlock()
+
+	storeType := seller.PrivateStoreType()
+	if storeType != model.PrivateStoreSell && storeType != model.PrivateStorePackageSell {
+		return 0, false, ErrStoreClosed
+	}
+	if !inRange(buyer, seller) {
+		return 0, false, ErrTooFar
+	}
+
+	list := seller.SellList()
+	if list.IsPackaged() && !coversWholeList(list.Items(), reqs) {
+		return 0, false, ErrPackageSale
+	}
+
+	// Фаза 1: проверка по текущему содержимому магазина и инвентаря.
+	// Повтор позиции прошёл бы проверку дважды, а перенос — только один раз.
+	var total int64
+	sellerInv := seller.Inventory()
+	seen := make(map[uint32]struct{}, len(reqs))
+	for _, req := range reqs {
+		if _, dup := seen[req.ObjectID]; dup {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrDuplicateItem, req.ObjectID)
+		}
+		seen[req.ObjectID] = struct{}{}
+		entry, ok := list.ItemByObjectID(req.ObjectID)
+		if !ok {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrItemNotInStore, req.ObjectID)
+		}
+		if entry.Price != req.Price {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrPriceMismatch, req.ObjectID)
+		}
+		if req.Count <= 0 || req.Count > entry.Count {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrCountExceeded, req.ObjectID)
+		}
+		item := sellerInv.ItemByObjectID(req.ObjectID)
+		if item == nil || item.Count() < req.Count {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrNotEnoughItems, req.ObjectID)
+		}
+		total += int64(req.Count) * int64(entry.Price)
+		if total > math.MaxInt32 {
+			return 0, false, ErrAdenaOverflow
+		}
+	}
+	if total > buyer.Inventory().Adena() {
+		return 0, false, ErrNotEnoughAdena
+	}
+	if sellerInv.Adena()+total > math.MaxInt32 {
+		return 0, false, ErrAdenaOverflow
+	}
+
+	// Фаза 2: перенос предметов и адены одной атомарной операцией —
+	// либо сделка целиком, либо инвентари не меняются
+	transfers := make([]model.ItemTransfer, 0, len(reqs)+1)
+	for _, req := range reqs {
+		transfers = append(transfers, model.ItemTransfer{From: sellerInv, To: buyer.Inventory(), ObjectID: req.ObjectID, Count: req.Count})
+	}
+	if total > 0 {
+		transfers = append(transfers, model.ItemTransfer{From: buyer.Inventory(), To: sellerInv, ItemType: model.AdenaItemID, Count: int32(total)})
+	}
+	if err := exchange(buyer.Inventory(), sellerInv, transfers); err != nil {
+		return 0, false, err
+	}
+	for _, req := range reqs {
+		list.Consume(req.ObjectID, 0, req.Count)
+	}
+
+	closed := list.Len() == 0
+	if closed {
+		closeLocked(seller)
+	}
+	return total, closed, nil
+}
+
+// Sell продаёт предметы клиента в buy-магазин storeOwner.
+// Возвращает суммарную стоимость и флаг закрытия магазина (всё куплено).
+func (s *Service) Sell(seller, storeOwner *model.Player, reqs []ItemRequest) (int64, bool, error) {
+	if seller == storeOwner {
+		return 0, false, ErrSelfTrade
+	}
+	if len(reqs) == 0 {
+		return 0, false, ErrEmptyList
+	}
+
+	unlock := s.lockPlayers(seller, storeOwner)
+	defer unlock()
+
+	if storeOwner.PrivateStoreType() != model.PrivateStoreBuy {
+		return 0, false, ErrStoreClosed
+	}
+	if !inRange(seller, storeOwner) {
+		return 0, false, ErrTooFar
+	}
+
+	list := storeOwner.BuyList()
+	sellerInv := seller.Inventory()
+
+	var total int64
+	seenObjects := make(map[uint32]struct{}, len(reqs))
+	seenTypes := make(map[int32]struct{}, len(reqs))
+	for _, req := range reqs {
+		if _, dup := seenObjects[req.ObjectID]; dup {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrDuplicateItem, req.ObjectID)
+		}
+		if _, dup := seenTypes[req.ItemType]; dup {
+			return 0, false, fmt.Errorf("%w: itemType %d", ErrDuplicateItem, req.ItemType)
+		}
+		seenObjects[req.ObjectID] = struct{}{}
+		seenTypes[req.ItemType] = struct{}{}
+		entry, ok := list.ItemByType(req.ItemType)
+		if !ok {
+			return 0, false, fmt.Errorf("%w: itemType %d", ErrItemNotInStore, req.ItemType)
+		}
+		if entry.Price != req.Price {
+			return 0, false, fmt.Errorf("%w: itemType %d", ErrPriceMismatch, req.ItemType)
+		}
+		if req.Count <= 0 || req.Count > entry.Count {
+			return 0, false, fmt.Errorf("%w: itemType %d", ErrCountExceeded, req.ItemType)
+		}
+		item := sellerInv.ItemByObjectID(req.ObjectID)
+		if item == nil || item.ItemType() != req.ItemType || item.IsEquipped() || item.Count() < req.Count {
+			return 0, false, fmt.Errorf("%w: objectID %d", ErrNotEnoughItems, req.ObjectID)
+		}
+		total += int64(req.Count) * int64(entry.Price)
+		if total > math.MaxInt32 {
+			return 0, false, ErrAdenaOverflow
+		}
+	}
+	if total > storeOwner.Inventory().Adena() {
+		return 0, false, ErrNotEnoughAdena
+	}
+	if sellerInv.Adena()+total > math.MaxInt32 {
+		return 0, false, ErrAdenaOverflow
+	}
+
+	transfers := make([]model.ItemTransfer, 0, len(reqs)+1)
+	for _, req := range reqs {
+		transfers = append(transfers, model.ItemTransfer{From: sellerInv, To: storeOwner.Inventory(), ObjectID: req.ObjectID, Count: req.Count})
+	}
+	if total > 0 {
+		transfers = append(transfers, model.ItemTransfer{From: storeOwner.Inventory(), To: sellerInv, ItemType: model.AdenaItemID, Count: int32(total)})
+	}
+	if err := exchange(sellerInv, storeOwner.Inventory(), transfers); err != nil {
+		return 0, false, err
+	}
+	for _, req := range reqs {
+		list.Consume(0, req.ItemType, req.Count)
+	}
+
+	closed := list.Len() == 0
+	if closed {
+		closeLocked(storeOwner)
+	}
+	return total, closed, nil
+}
+
+// exchange переносит предметы сделки; переполнение стака получателя
+// возвращается как ErrItemOverflow.
+func exchange(a, b *model.Inventory, transfers []model.ItemTransfer) error {
+	err := model.Exchange(a, b, transfers)
+	if errors.Is(err, model.ErrCountOverflow) {
+		return fmt.Errorf("%w: %w", ErrItemOverflow, err)
+	}
+	if err != nil {
+		return fmt.Errorf("exchanging items: %w", err)
+	}
+	return nil
+}
+
+// coversWholeList проверяет что запрос покупает все позиции package sale полностью.
+func coversWholeList(items []model.TradeItem, reqs []ItemRequest) bool {
+	if len(items) != len(reqs) {
+		return false
+	}
+	for _, it := range items {
+		found := false
+		for _, req := range reqs {
+			if req.ObjectID == it.ObjectID && req.Count == it.Count {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// inRange проверяет дистанцию взаимодействия с магазином.
+func inRange(a, b *model.Player) bool {
+	return a.Location().DistanceSquared(b.Location()) <= InteractionDistance*InteractionDistance
+}
+
+// lockPlayers захватывает trade lock'и игроков в порядке objectID.
+// b может быть nil (операция над одним магазином).
+func (s *Service) lockPlayers(a, b *model.Player) func() {
+	first := s.lockFor(a.ObjectID())
+	if b == nil {
+		first.Lock()
+		return first.Unlock
+	}
+
+	second := s.lockFor(b.ObjectID())
+	if b.ObjectID() < a.ObjectID() {
+		first, second = second, first
+	}
+	first.Lock()
+	second.Lock()
+	return func() {
+		second.Unlock()
+		first.Unlock()
+	}
+}
+
+func (s *Service) lockFor(objectID uint32) *sync.Mutex {
+	if mu, ok := s.locks.Load(objectID); ok {
+		return mu.(*sync.Mutex)
+	}
+	mu, _ := s.locks.LoadOrStore(objectID, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// Forget закрывает магазин и освобождает trade lock игрока (вызывается при выходе из мира).
+// Lock удаляется под ним самим: сделка, ждавшая его, увидит закрытый магазин.
+func (s *Service) Forget(p *model.Player) {
+	mu := s.lockFor(p.ObjectID())
+	mu.Lock()
+	defer mu.Unlock()
+
+	closeLocked(p)
+	s.locks.Delete(p.ObjectID())
+}
@@ -0,0 +1,384 @@
+package privatestore
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	itemSword  int32 = 100
+	itemArrows int32 = 17
+)
+
+var nextItemID int64 = 1000
+
+func newPlayer(t *testing.T, id int64) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, "Player"+string(rune('A'+id)), 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p.SetLocation(model.NewLocation(1000, 1000, 0, 0))
+	return p
+}
+
+func give(t *testing.T, p *model.Player, itemType, count int32) *model.Item {
+	t.Helper()
+	item, err := model.NewItem(p.CharacterID(), itemType, count)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	nextItemID++
+	item.SetItemID(nextItemID)
+	p.Inventory().AddItem(item)
+	return item
+}
+
+func TestService_OpenSellStore(t *testing.T) {
+	svc := NewService(2)
+	seller := newPlayer(t, 1)
+	sword := give(t, seller, itemSword, 1)
+
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 2, Price: 10}}, false); !errors.Is(err, ErrInvalidItem) {
+		t.Errorf("count > owned: err = %v, want ErrInvalidItem", err)
+	}
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: 999, Count: 1}}, false); !errors.Is(err, ErrInvalidItem) {
+		t.Errorf("missing item: err = %v, want ErrInvalidItem", err)
+	}
+	if err := svc.OpenSellStore(seller, make([]model.TradeItem, 3), false); !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("too many items: err = %v, want ErrTooManyItems", err)
+	}
+
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 10}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+	if seller.PrivateStoreType() != model.PrivateStoreSell || !seller.IsSitting() {
+		t.Errorf("store=%s sitting=%v, want SELL/true", seller.PrivateStoreType(), seller.IsSitting())
+	}
+	it, _ := seller.SellList().ItemByObjectID(sword.ObjectID())
+	if it.ItemType != itemSword {
+		t.Errorf("item type must be taken from inventory, got %d", it.ItemType)
+	}
+
+	// Новый список заменяет открытый магазин
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 20}}, false); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if it, _ := seller.SellList().ItemByObjectID(sword.ObjectID()); it.Price != 20 || seller.SellList().Len() != 1 {
+		t.Errorf("reopened list = %+v, want one item at price 20", seller.SellList().Items())
+	}
+	dup := []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 1}, {ObjectID: sword.ObjectID(), Count: 1, Price: 1}}
+	if err := svc.OpenSellStore(seller, dup, false); !errors.Is(err, ErrDuplicateItem) {
+		t.Errorf("duplicate item: err = %v, want ErrDuplicateItem", err)
+	}
+
+	svc.CloseStore(seller)
+	if seller.PrivateStoreType() != model.PrivateStoreNone || seller.IsSitting() || seller.SellList().Len() != 0 {
+		t.Error("CloseStore must reset store state")
+	}
+}
+
+func TestService_OpenBuyStore_RequiresAdena(t *testing.T) {
+	svc := NewService(0)
+	buyer := newPlayer(t, 1)
+	give(t, buyer, model.AdenaItemID, 100)
+
+	err := svc.OpenBuyStore(buyer, []model.TradeItem{{ItemType: itemArrows, Count: 11, Price: 10}})
+	if !errors.Is(err, ErrNotEnoughAdena) {
+		t.Errorf("err = %v, want ErrNotEnoughAdena", err)
+	}
+
+	if err := svc.OpenBuyStore(buyer, []model.TradeItem{{ItemType: itemArrows, Count: 10, Price: 10}}); err != nil {
+		t.Fatalf("OpenBuyStore: %v", err)
+	}
+	if buyer.PrivateStoreType() != model.PrivateStoreBuy {
+		t.Errorf("store = %s, want BUY", buyer.PrivateStoreType())
+	}
+}
+
+func TestService_Buy(t *testing.T) {
+	svc := NewService(0)
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	arrows := give(t, seller, itemArrows, 100)
+	give(t, buyer, model.AdenaItemID, 1000)
+
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: arrows.ObjectID(), Count: 50, Price: 10}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  ItemRequest
+		want error
+	}{
+		{"wrong price", ItemRequest{ObjectID: arrows.ObjectID(), Count: 1, Price: 9}, ErrPriceMismatch},
+		{"over store count", ItemRequest{ObjectID: arrows.ObjectID(), Count: 51, Price: 10}, ErrCountExceeded},
+		{"unknown item", ItemRequest{ObjectID: 1, Count: 1, Price: 10}, ErrItemNotInStore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Buy(buyer, seller, []ItemRequest{tt.req}); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	total, closed, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 20, Price: 10}})
+	if err != nil {
+		t.Fatalf("Buy: %v", err)
+	}
+	if total != 200 || closed {
+		t.Errorf("total=%d closed=%v, want 200/false", total, closed)
+	}
+	if buyer.Inventory().Adena() != 800 || seller.Inventory().Adena() != 200 {
+		t.Errorf("adena buyer=%d seller=%d, want 800/200", buyer.Inventory().Adena(), seller.Inventory().Adena())
+	}
+	if buyer.Inventory().CountOf(itemArrows) != 20 || seller.Inventory().CountOf(itemArrows) != 80 {
+		t.Error("arrows were not transferred")
+	}
+
+	_, closed, err = svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 30, Price: 10}})
+	if err != nil {
+		t.Fatalf("Buy rest: %v", err)
+	}
+	if !closed || seller.PrivateStoreType() != model.PrivateStoreNone {
+		t.Error("store must close when sold out")
+	}
+
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 1, Price: 10}}); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("buy from closed store: err = %v, want ErrStoreClosed", err)
+	}
+}
+
+func TestService_Buy_Validation(t *testing.T) {
+	svc := NewService(0)
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	sword := give(t, seller, itemSword, 1)
+	arrows := give(t, seller, itemArrows, 10)
+	give(t, buyer, model.AdenaItemID, 100)
+
+	items := []model.TradeItem{
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 50},
+		{ObjectID: arrows.ObjectID(), Count: 10, Price: 5},
+	}
+	if err := svc.OpenSellStore(seller, items, true); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: sword.ObjectID(), Count: 1, Price: 50}}); !errors.Is(err, ErrPackageSale) {
+		t.Errorf("partial package: err = %v, want ErrPackageSale", err)
+	}
+	if _, _, err := svc.Buy(seller, seller, nil); !errors.Is(err, ErrSelfTrade) {
+		t.Errorf("self trade: err = %v, want ErrSelfTrade", err)
+	}
+
+	buyer.SetLocation(model.NewLocation(5000, 5000, 0, 0))
+	all := []ItemRequest{
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 50},
+		{ObjectID: arrows.ObjectID(), Count: 10, Price: 5},
+	}
+	if _, _, err := svc.Buy(buyer, seller, all); !errors.Is(err, ErrTooFar) {
+		t.Errorf("far buyer: err = %v, want ErrTooFar", err)
+	}
+	buyer.SetLocation(seller.Location())
+
+	// Пакет стоит 100 — адены хватает ровно; стак стрел у продавца пропал
+	seller.Inventory().RemoveItem(arrows.ObjectID())
+	if _, _, err := svc.Buy(buyer, seller, all); !errors.Is(err, ErrNotEnoughItems) {
+		t.Errorf("missing seller item: err = %v, want ErrNotEnoughItems", err)
+	}
+	if buyer.Inventory().Adena() != 100 || buyer.Inventory().Size() != 1 {
+		t.Error("failed purchase must not change buyer inventory")
+	}
+}
+
+func TestService_Buy_Overflow(t *testing.T) {
+	svc := NewService(0)
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	arrows := give(t, seller, itemArrows, 100)
+	sword := give(t, seller, itemSword, 1)
+	give(t, buyer, model.AdenaItemID, 1000)
+	owned := give(t, buyer, itemArrows, math.MaxInt32-10)
+
+	items := []model.TradeItem{
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 100},
+		{ObjectID: arrows.ObjectID(), Count: 100, Price: 1},
+	}
+	if err := svc.OpenSellStore(seller, items, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	// Меч проходит, стак стрел покупателя переполнится — сделка не идёт целиком
+	reqs := []ItemRequest{
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 100},
+		{ObjectID: arrows.ObjectID(), Count: 20, Price: 1},
+	}
+	if _, _, err := svc.Buy(buyer, seller, reqs); !errors.Is(err, ErrItemOverflow) {
+		t.Fatalf("buyer stack overflow: err = %v, want ErrItemOverflow", err)
+	}
+	if buyer.Inventory().Adena() != 1000 || seller.Inventory().Adena() != 0 {
+		t.Errorf("adena buyer=%d seller=%d, want 1000/0", buyer.Inventory().Adena(), seller.Inventory().Adena())
+	}
+	if seller.Inventory().CountOf(itemSword) != 1 || seller.Inventory().CountOf(itemArrows) != 100 || owned.Count() != math.MaxInt32-10 {
+		t.Error("failed purchase must not move items")
+	}
+
+	// Адена продавца у предела
+	give(t, seller, model.AdenaItemID, math.MaxInt32-50)
+	if _, _, err := svc.Buy(buyer, seller, reqs[:1]); !errors.Is(err, ErrAdenaOverflow) {
+		t.Errorf("seller adena overflow: err = %v, want ErrAdenaOverflow", err)
+	}
+	if buyer.Inventory().Adena() != 1000 || buyer.Inventory().CountOf(itemSword) != 0 {
+		t.Error("failed purchase must not change buyer inventory")
+	}
+
+	// Покупка, которая помещается, проходит и расходует витрину
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 10, Price: 1}}); err != nil {
+		t.Fatalf("Buy: %v", err)
+	}
+	if owned.Count() != math.MaxInt32 || seller.Inventory().Adena() != math.MaxInt32-40 {
+		t.Errorf("buyer arrows=%d seller adena=%d after buy", owned.Count(), seller.Inventory().Adena())
+	}
+}
+
+func TestService_Sell(t *testing.T) {
+	svc := NewService(0)
+	owner, seller := newPlayer(t, 1), newPlayer(t, 2)
+	give(t, owner, model.AdenaItemID, 500)
+	arrows := give(t, seller, itemArrows, 30)
+
+	if err := svc.OpenBuyStore(owner, []model.TradeItem{{ItemType: itemArrows, Count: 20, Price: 5}}); err != nil {
+		t.Fatalf("OpenBuyStore: %v", err)
+	}
+
+	if _, _, err := svc.Sell(seller, owner, []ItemRequest{{ObjectID: arrows.ObjectID(), ItemType: itemArrows, Count: 21, Price: 5}}); !errors.Is(err, ErrCountExceeded) {
+		t.Errorf("over count: err = %v, want ErrCountExceeded", err)
+	}
+
+	total, closed, err := svc.Sell(seller, owner, []ItemRequest{{ObjectID: arrows.ObjectID(), ItemType: itemArrows, Count: 20, Price: 5}})
+	if err != nil {
+		t.Fatalf("Sell: %v", err)
+	}
+	if total != 100 || !closed {
+		t.Errorf("total=%d closed=%v, want 100/true", total, closed)
+	}
+	if owner.Inventory().CountOf(itemArrows) != 20 || seller.Inventory().Adena() != 100 || owner.Inventory().Adena() != 400 {
+		t.Error("items or adena were not exchanged")
+	}
+}
+
+// TestService_DuplicateRequests проверяет что повтор позиции в запросе отклоняется до переноса.
+func TestService_DuplicateRequests(t *testing.T) {
+	svc := NewService(0)
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	sword := give(t, seller, itemSword, 1)
+	give(t, buyer, model.AdenaItemID, 1000)
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 100}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	twice := []ItemRequest{
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 100},
+		{ObjectID: sword.ObjectID(), Count: 1, Price: 100},
+	}
+	if _, _, err := svc.Buy(buyer, seller, twice); !errors.Is(err, ErrDuplicateItem) {
+		t.Errorf("Buy: err = %v, want ErrDuplicateItem", err)
+	}
+	if buyer.Inventory().Adena() != 1000 || seller.Inventory().CountOf(itemSword) != 1 {
+		t.Error("rejected purchase changed inventories")
+	}
+
+	owner, vendor := newPlayer(t, 3), newPlayer(t, 4)
+	give(t, owner, model.AdenaItemID, 1000)
+	first := give(t, vendor, itemArrows, 10)
+	second := give(t, vendor, itemArrows, 10)
+	if err := svc.OpenBuyStore(owner, []model.TradeItem{{ItemType: itemArrows, Count: 10, Price: 5}}); err != nil {
+		t.Fatalf("OpenBuyStore: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		reqs []ItemRequest
+	}{
+		{"same object", []ItemRequest{
+			{ObjectID: first.ObjectID(), ItemType: itemArrows, Count: 10, Price: 5},
+			{ObjectID: first.ObjectID(), ItemType: itemArrows, Count: 10, Price: 5},
+		}},
+		{"same item type", []ItemRequest{
+			{ObjectID: first.ObjectID(), ItemType: itemArrows, Count: 10, Price: 5},
+			{ObjectID: second.ObjectID(), ItemType: itemArrows, Count: 10, Price: 5},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.Sell(vendor, owner, tt.reqs); !errors.Is(err, ErrDuplicateItem) {
+				t.Errorf("Sell: err = %v, want ErrDuplicateItem", err)
+			}
+		})
+	}
+	if owner.Inventory().CountOf(itemArrows) != 0 || vendor.Inventory().Adena() != 0 {
+		t.Error("rejected sale changed inventories")
+	}
+}
+
+// TestService_Forget проверяет что выход из мира закрывает магазин.
+func TestService_Forget(t *testing.T) {
+	svc := NewService(0)
+	seller, buyer := newPlayer(t, 1), newPlayer(t, 2)
+	sword := give(t, seller, itemSword, 1)
+	give(t, buyer, model.AdenaItemID, 100)
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: sword.ObjectID(), Count: 1, Price: 10}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	svc.Forget(seller)
+	if seller.PrivateStoreType() != model.PrivateStoreNone {
+		t.Errorf("store = %s after Forget, want NONE", seller.PrivateStoreType())
+	}
+	if _, _, err := svc.Buy(buyer, seller, []ItemRequest{{ObjectID: sword.ObjectID(), Count: 1, Price: 10}}); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("buy after Forget: err = %v, want ErrStoreClosed", err)
+	}
+}
+
+// TestService_ConcurrentBuy проверяет что конкурентные покупатели не продают больше, чем есть в магазине.
+func TestService_ConcurrentBuy(t *testing.T) {
+	svc := NewService(0)
+	seller := newPlayer(t, 1)
+	arrows := give(t, seller, itemArrows, 10)
+	if err := svc.OpenSellStore(seller, []model.TradeItem{{ObjectID: arrows.ObjectID(), Count: 10, Price: 1}}, false); err != nil {
+		t.Fatalf("OpenSellStore: %v", err)
+	}
+
+	buyers := make([]*model.Player, 20)
+	for i := range buyers {
+		buyers[i] = newPlayer(t, int64(10+i))
+		give(t, buyers[i], model.AdenaItemID, 10)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sold int
+	)
+	for _, b := range buyers {
+		wg.Go(func() {
+			if _, _, err := svc.Buy(b, seller, []ItemRequest{{ObjectID: arrows.ObjectID(), Count: 1, Price: 1}}); err == nil {
+				mu.Lock()
+				sold++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	if sold != 10 {
+		t.Errorf("sold = %d, want 10", sold)
+	}
+	if seller.Inventory().Adena() != 10 {
+		t.Errorf("seller adena = %d, want 10", seller.Inventory().Adena())
+	}
+}
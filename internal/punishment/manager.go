@@ -0,0 +1,232 @@
+package punishment
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidPunishment = errors.New("invalid punishment")
+	ErrNotFound          = errors.New("punishment not found")
+)
+
+// Manager holds the active punishments and answers whether an account, character
+// or IP is punished. Thread-safe: changes are stored in the repository before the
+// in-memory state is changed.
+type Manager struct {
+	repo Repository // nil = punishments are kept in memory only
+
+	mu     sync.RWMutex
+	active map[int64]Punishment // ID → punishment
+	lastID int64                // last ID given out without a repository
+
+	now func() time.Time
+}
+
+// NewManager creates a punishment manager. repo may be nil (punishments are lost on restart).
+func NewManager(repo Repository) *Manager {
+	return &Manager{
+		repo:   repo,
+		active: make(map[int64]Punishment),
+		now:    time.Now,
+	}
+}
+
+// Load replaces the active punishments with those stored in the repository and
+// returns how many are active. Punishments that ran out but were not yet reported
+// by Expire are kept for it.
+func (m *Manager) Load(ctx context.Context) (int, error) {
+	if m.repo == nil {
+		return m.Len(), nil
+	}
+	now := m.now()
+	list, err := m.repo.LoadActive(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("loading punishments: %w", err)
+	}
+
+	active := make(map[int64]Punishment, len(list))
+	for _, p := range list {
+		p.Key = NormalizeKey(p.Affect, p.Key)
+		active[p.ID] = p
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, p := range m.active {
+		if _, ok := active[id]; !ok && !p.ActiveAt(now) {
+			active[id] = p
+		}
+	}
+	m.active = active
+	return len(list), nil
+}
+
+// Len returns the number of active punishments.
+func (m *Manager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.active)
+}
+
+// Punish stores a new punishment and returns it with its ID.
+func (m *Manager) Punish(ctx context.Context, p Punishment) (Punishment, error) {
+	p.Key = NormalizeKey(p.Affect, p.Key)
+	switch {
+	case p.Affect >= affectCount:
+		return Punishment{}, fmt.Errorf("unknown affect %d: %w", p.Affect, ErrInvalidPunishment)
+	case p.Type >= typeCount:
+		return Punishment{}, fmt.Errorf("unknown type %d: %w", p.Type, ErrInvalidPunishment)
+	case p.Key == "":
+		return Punishment{}, fmt.Errorf("empty %s: %w", p.Affect, ErrInvalidPunishment)
+	case !p.ActiveAt(m.now()):
+		return Punishment{}, fmt.Errorf("expires in the past: %w", ErrInvalidPunishment)
+	}
+
+	if m.repo != nil {
+		id, err := m.repo.Add(ctx, p)
+		if err != nil {
+			return Punishment{}, fmt.Errorf("storing %s of %s %s: %w", p.Type, p.Affect, p.Key, err)
+		}
+		p.ID = id
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.repo == nil {
+		m.lastID++
+		p.ID = m.lastID
+	}
+	m.active[p.ID] = p
+	return p, nil
+}
+
+// Lift removes an active punishment before it expires and returns it.
+func (m *Manager) Lift(ctx context.Context, id int64) (Punishment, error) {
+	m.mu.RLock()
+	p, ok := m.active[id]
+	m.mu.RUnlock()
+	if !ok {
+		return Punishment{}, fmt.Errorf("punishment %d: %w", id, ErrNotFound)
+	}
+
+	if m.repo != nil {
+		if err := m.repo.Remove(ctx, id); err != nil {
+			return Punishment{}, fmt.Errorf("removing punishment %d: %w", id, err)
+		}
+	}
+
+	m.mu.Lock()
+	delete(m.active, id)
+	m.mu.Unlock()
+	return p, nil
+}
+
+// Find returns the active punishment of type t on the given account, character or IP.
+// Of several punishments the one ending last is returned.
+func (m *Manager) Find(a Affect, key string, t Type) (Punishment, bool) {
+	key = NormalizeKey(a, key)
+	if key == "" {
+		return Punishment{}, false
+	}
+	now := m.now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var (
+		found Punishment
+		ok    bool
+	)
+	for _, p := range m.active {
+		if p.Affect != a || p.Type != t || p.Key != key || !p.ActiveAt(now) {
+			continue
+		}
+		if !ok || endsLater(p, found) {
+			found, ok = p, true
+		}
+	}
+	return found, ok
+}
+
+// Check returns the active punishment of type t on any of the account, the character
+// and the IP. Empty keys are not checked.
+func (m *Manager) Check(t Type, account, character, ip string) (Punishment, bool) {
+	for _, k := range [...]struct {
+		affect Affect
+		key    string
+	}{{Account, account}, {Character, character}, {IP, ip}} {
+		if p, ok := m.Find(k.affect, k.key, t); ok {
+			return p, true
+		}
+	}
+	return Punishment{}, false
+}
+
+// Active returns the active punishments ordered by ID.
+func (m *Manager) Active() []Punishment {
+	now := m.now()
+	m.mu.RLock()
+	list := slices.Collect(maps.Values(m.active))
+	m.mu.RUnlock()
+
+	list = slices.DeleteFunc(list, func(p Punishment) bool { return !p.ActiveAt(now) })
+	slices.SortFunc(list, func(a, b Punishment) int { return cmp.Compare(a.ID, b.ID) })
+	return list
+}
+
+// Expire forgets the punishments that have run out and returns them.
+func (m *Manager) Expire() []Punishment {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []Punishment
+	for id, p := range m.active {
+		if !p.ActiveAt(now) {
+			expired = append(expired, p)
+			delete(m.active, id)
+		}
+	}
+	slices.SortFunc(expired, func(a, b Punishment) int { return cmp.Compare(a.ID, b.ID) })
+	return expired
+}
+
+// Run expires punishments every interval until ctx is cancelled, calling onExpire
+// (if not nil) for each. After expiring it reloads the repository to pick up
+// punishments given by other servers and tools.
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onExpire func(Punishment)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for _, p := range m.Expire() {
+			slog.Info("punishment expired", "id", p.ID, "affect", p.Affect, "key", p.Key, "type", p.Type)
+			if onExpire != nil {
+				onExpire(p)
+			}
+		}
+		if _, err := m.Load(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("reloading punishments", "error", err)
+		}
+	}
+}
+
+func endsLater(a, b Punishment) bool {
+	if a.Permanent() || b.Permanent() {
+		return a.Permanent() && !b.Permanent()
+	}
+	return a.Expires.After(b.Expires)
+}
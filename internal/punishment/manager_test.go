@@ -0,0 +1,159 @@
+package punishment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// memRepo is a Repository that keeps punishments in a map.
+type memRepo struct {
+	rows   map[int64]Punishment
+	lastID int64
+}
+
+func (r *memRepo) LoadActive(_ context.Context, now time.Time) ([]Punishment, error) {
+	var list []Punishment
+	for _, p := range r.rows {
+		if p.ActiveAt(now) {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (r *memRepo) Add(_ context.Context, p Punishment) (int64, error) {
+	r.lastID++
+	p.ID = r.lastID
+	r.rows[p.ID] = p
+	return p.ID, nil
+}
+
+func (r *memRepo) Remove(_ context.Context, id int64) error {
+	delete(r.rows, id)
+	return nil
+}
+
+func TestManager_PunishFindLift(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager(nil)
+	m.now = func() time.Time { return now }
+
+	ban, err := m.Punish(ctx, Punishment{Affect: Account, Key: " Cheater ", Type: Ban, Expires: now.Add(time.Hour), Reason: "bot"})
+	if err != nil {
+		t.Fatalf("Punish: %v", err)
+	}
+	if ban.ID == 0 || ban.Key != "cheater" {
+		t.Errorf("Punish = %+v, want an ID and a lowercase key", ban)
+	}
+	if _, err := m.Punish(ctx, Punishment{Affect: Character, Key: "Loud", Type: ChatBan}); err != nil {
+		t.Fatalf("Punish permanent: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		typ  Type
+		acc  string
+		char string
+		ip   string
+		want bool
+	}{
+		{"banned account", Ban, "CHEATER", "", "10.0.0.1", true},
+		{"other account", Ban, "player", "", "10.0.0.1", false},
+		{"chat banned character", ChatBan, "any", "loud", "", true},
+		{"other type", TradeBan, "any", "Loud", "", false},
+	} {
+		if _, got := m.Check(tc.typ, tc.acc, tc.char, tc.ip); got != tc.want {
+			t.Errorf("%s: Check = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := m.Punish(ctx, Punishment{Affect: IP, Key: "10.0.0.2", Type: Ban, Expires: now.Add(-time.Second)}); !errors.Is(err, ErrInvalidPunishment) {
+		t.Errorf("Punish expired = %v, want ErrInvalidPunishment", err)
+	}
+	if _, err := m.Punish(ctx, Punishment{Affect: IP, Type: Ban}); !errors.Is(err, ErrInvalidPunishment) {
+		t.Errorf("Punish without key = %v, want ErrInvalidPunishment", err)
+	}
+
+	if _, err := m.Lift(ctx, ban.ID); err != nil {
+		t.Fatalf("Lift: %v", err)
+	}
+	if _, ok := m.Find(Account, "cheater", Ban); ok {
+		t.Error("lifted ban still found")
+	}
+	if _, err := m.Lift(ctx, ban.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Lift = %v, want ErrNotFound", err)
+	}
+	if got := len(m.Active()); got != 1 {
+		t.Errorf("Active = %d punishments, want 1", got)
+	}
+}
+
+func TestManager_FindLongest(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager(nil)
+	m.now = func() time.Time { return now }
+
+	m.Punish(ctx, Punishment{Affect: Character, Key: "a", Type: Jail, Expires: now.Add(time.Hour)})
+	long, _ := m.Punish(ctx, Punishment{Affect: Character, Key: "a", Type: Jail, Expires: now.Add(2 * time.Hour)})
+	if p, _ := m.Find(Character, "a", Jail); p.ID != long.ID {
+		t.Errorf("Find = %d, want the longest jail %d", p.ID, long.ID)
+	}
+	perm, _ := m.Punish(ctx, Punishment{Affect: Character, Key: "a", Type: Jail})
+	if p, _ := m.Find(Character, "a", Jail); p.ID != perm.ID {
+		t.Errorf("Find = %d, want the permanent jail %d", p.ID, perm.ID)
+	}
+}
+
+func TestManager_ExpireAndReload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memRepo{rows: map[int64]Punishment{}}
+	m := NewManager(repo)
+	m.now = func() time.Time { return now }
+
+	jail, err := m.Punish(ctx, Punishment{Affect: Character, Key: "Thief", Type: Jail, Expires: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Punish: %v", err)
+	}
+	// Given by another server
+	repo.Add(ctx, Punishment{Affect: IP, Key: "10.0.0.9", Type: Ban})
+
+	now = now.Add(2 * time.Minute)
+	if n, err := m.Load(ctx); err != nil || n != 1 {
+		t.Fatalf("Load = %d, %v; want 1 active", n, err)
+	}
+	if _, ok := m.Find(IP, "10.0.0.9", Ban); !ok {
+		t.Error("reloaded IP ban not found")
+	}
+	if _, ok := m.Find(Character, "thief", Jail); ok {
+		t.Error("expired jail still in force")
+	}
+
+	expired := m.Expire()
+	if len(expired) != 1 || expired[0].ID != jail.ID {
+		t.Fatalf("Expire = %+v, want the jail", expired)
+	}
+	if got := m.Expire(); len(got) != 0 {
+		t.Errorf("second Expire = %+v, want nothing", got)
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	for a := range affectCount {
+		if got, ok := ParseAffect(a.String()); !ok || got != a {
+			t.Errorf("ParseAffect(%q) = %v, %v", a.String(), got, ok)
+		}
+	}
+	for typ := range typeCount {
+		if got, ok := ParseType(typ.String()); !ok || got != typ {
+			t.Errorf("ParseType(%q) = %v, %v", typ.String(), got, ok)
+		}
+	}
+	if _, ok := ParseType("mute"); ok {
+		t.Error("ParseType accepted an unknown type")
+	}
+}
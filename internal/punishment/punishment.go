@@ -0,0 +1,110 @@
+package punishment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Affect is what a punishment is bound to.
+type Affect uint8
+
+const (
+	Account Affect = iota
+	Character
+	IP
+
+	affectCount
+)
+
+var affectNames = [affectCount]string{
+	Account:   "account",
+	Character: "character",
+	IP:        "ip",
+}
+
+// String returns the stored name of the affect.
+func (a Affect) String() string {
+	if a < affectCount {
+		return affectNames[a]
+	}
+	return fmt.Sprintf("Affect(%d)", uint8(a))
+}
+
+// ParseAffect returns the affect with the given stored name.
+func ParseAffect(s string) (Affect, bool) {
+	for a, name := range affectNames {
+		if name == s {
+			return Affect(a), true
+		}
+	}
+	return 0, false
+}
+
+// Type is what a punishment forbids.
+type Type uint8
+
+const (
+	Ban      Type = iota // cannot log in
+	ChatBan              // cannot talk in chat
+	Jail                 // kept in the jail
+	TradeBan             // cannot use private stores
+
+	typeCount
+)
+
+var typeNames = [typeCount]string{
+	Ban:      "ban",
+	ChatBan:  "chat_ban",
+	Jail:     "jail",
+	TradeBan: "trade_ban",
+}
+
+// String returns the stored name of the type.
+func (t Type) String() string {
+	if t < typeCount {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", uint8(t))
+}
+
+// ParseType returns the type with the given stored name.
+func ParseType(s string) (Type, bool) {
+	for t, name := range typeNames {
+		if name == s {
+			return Type(t), true
+		}
+	}
+	return 0, false
+}
+
+// Punishment is a ban, chat ban, jail or trade ban of an account, character or IP.
+type Punishment struct {
+	ID         int64
+	Affect     Affect
+	Key        string // account login, character name or IP address
+	Type       Type
+	Expires    time.Time // zero = permanent
+	Reason     string
+	PunishedBy string
+}
+
+// Permanent reports whether the punishment never expires.
+func (p Punishment) Permanent() bool {
+	return p.Expires.IsZero()
+}
+
+// ActiveAt reports whether the punishment is in force at now.
+func (p Punishment) ActiveAt(now time.Time) bool {
+	return p.Permanent() || now.Before(p.Expires)
+}
+
+// NormalizeKey returns the form keys are stored and matched in: logins and
+// character names are case-insensitive.
+func NormalizeKey(a Affect, key string) string {
+	key = strings.TrimSpace(key)
+	if a == IP {
+		return key
+	}
+	return strings.ToLower(key)
+}
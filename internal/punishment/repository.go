@@ -0,0 +1,15 @@
+package punishment
+
+import (
+	"context"
+	"time"
+)
+
+// Repository keeps punishments between restarts. Expired punishments stay stored
+// as history; LoadActive skips them.
+type Repository interface {
+	LoadActive(ctx context.Context, now time.Time) ([]Punishment, error)
+	// Add stores p and returns its ID.
+	Add(ctx context.Context, p Punishment) (int64, error)
+	Remove(ctx context.Context, id int64) error
+}
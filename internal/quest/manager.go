@@ -0,0 +1,508 @@
+package quest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxActiveQuests — максимум одновременно начатых квестов персонажа.
+const MaxActiveQuests = 25
+
+// Служебные переменные квеста.
+const (
+	VarState = "<state>"
+	VarCond  = "cond"
+)
+
+// Status — состояние квеста персонажа.
+type Status int32
+
+const (
+	StatusCreated   Status = iota // квест не начат
+	StatusStarted                 // квест в процессе
+	StatusCompleted               // одноразовый квест завершён
+)
+
+// String возвращает значение, сохраняемое в переменной VarState.
+func (s Status) String() string {
+	switch s {
+	case StatusStarted:
+		return "Started"
+	case StatusCompleted:
+		return "Completed"
+	default:
+		return "Created"
+	}
+}
+
+func parseStatus(v string) Status {
+	switch v {
+	case "Started":
+		return StatusStarted
+	case "Completed":
+		return StatusCompleted
+	default:
+		return StatusCreated
+	}
+}
+
+var (
+	ErrDuplicateQuest = errors.New("quest is already registered")
+	ErrUnknownQuest   = errors.New("unknown quest")
+	ErrNotAttached    = errors.New("player quests are not loaded")
+	ErrNotStarted     = errors.New("quest is not started")
+	ErrTooManyQuests  = errors.New("too many active quests")
+)
+
+// Result — результат обработки события квестом.
+type Result struct {
+	QuestID int32
+	HTML    string // ответ NPC (может быть пустым)
+	Changed bool   // изменились состояние, cond, предметы или опыт персонажа
+}
+
+// Entry — активный квест персонажа для окна квестов.
+type Entry struct {
+	QuestID int32
+	Cond    int32
+}
+
+// state — переменные квеста персонажа.
+type state struct {
+	vars map[string]string
+}
+
+func (s *state) status() Status {
+	if s == nil {
+		return StatusCreated
+	}
+	return parseStatus(s.vars[VarState])
+}
+
+func (s *state) cond() int32 {
+	if s == nil {
+		return 0
+	}
+	v, _ := strconv.ParseInt(s.vars[VarCond], 10, 32)
+	return int32(v)
+}
+
+type triggerKey struct {
+	typ TriggerType
+	id  int32
+}
+
+// Manager хранит описания квестов и прогресс онлайн-персонажей.
+// Прогресс загружается при входе в мир (Attach) и выгружается при выходе (Detach).
+// Thread-safe: события обрабатываются под одним mutex, изменения сохраняются
+// в repository до изменения состояния в памяти.
+type Manager struct {
+	repo Repository // nil = прогресс не сохраняется
+
+	mu        sync.Mutex
+	defs      map[int32]*Definition
+	byName    map[string]*Definition // lowercase name → quest
+	byTrigger map[triggerKey][]*Definition
+	states    map[int64]map[int32]*state // characterID → questID → state
+
+	rand func(n int) int
+}
+
+// NewManager создаёт менеджер квестов. repo может быть nil (прогресс только в памяти).
+func NewManager(repo Repository) *Manager {
+	return &Manager{
+		repo:      repo,
+		defs:      make(map[int32]*Definition),
+		byName:    make(map[string]*Definition),
+		byTrigger: make(map[triggerKey][]*Definition),
+		states:    make(map[int64]map[int32]*state),
+		rand:      rand.IntN,
+	}
+}
+
+// Register добавляет описание квеста. Описание не должно изменяться после регистрации.
+func (m *Manager) Register(def *Definition) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.defs[def.ID] != nil || m.byName[strings.ToLower(def.Name)] != nil {
+		return fmt.Errorf("%w: %d %s", ErrDuplicateQuest, def.ID, def.Name)
+	}
+	m.defs[def.ID] = def
+	m.byName[strings.ToLower(def.Name)] = def
+
+	seen := make(map[triggerKey]bool)
+	for _, s := range def.Steps {
+		typ := s.On.Type
+		if typ == TriggerEvent {
+			typ = TriggerTalk // NPC с событиями квеста показывает его при разговоре
+		}
+		key := triggerKey{typ: typ, id: s.On.ID}
+		if !seen[key] {
+			seen[key] = true
+			list := append(m.byTrigger[key], def)
+			slices.SortFunc(list, func(a, b *Definition) int { return cmp.Compare(a.ID, b.ID) })
+			m.byTrigger[key] = list
+		}
+	}
+	return nil
+}
+
+// Quest возвращает описание квеста по ID (nil если не зарегистрирован).
+func (m *Manager) Quest(id int32) *Definition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.defs[id]
+}
+
+// Count возвращает количество зарегистрированных квестов.
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.defs)
+}
+
+// Attach загружает прогресс квестов персонажа при входе в мир.
+func (m *Manager) Attach(ctx context.Context, characterID int64) error {
+	var vars []Var
+	if m.repo != nil {
+		var err error
+		if vars, err = m.repo.LoadVars(ctx, characterID); err != nil {
+			return fmt.Errorf("loading quests of character %d: %w", characterID, err)
+		}
+	}
+
+	sts := make(map[int32]*state)
+	for _, v := range vars {
+		st := sts[v.QuestID]
+		if st == nil {
+			st = &state{vars: make(map[string]string)}
+			sts[v.QuestID] = st
+		}
+		st.vars[v.Name] = v.Value
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[characterID] = sts
+	return nil
+}
+
+// Detach выгружает прогресс квестов персонажа при выходе из игры.
+func (m *Manager) Detach(characterID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, characterID)
+}
+
+// Status возвращает состояние квеста персонажа.
+func (m *Manager) Status(characterID int64, questID int32) Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[characterID][questID].status()
+}
+
+// Cond возвращает текущий cond квеста персонажа (0 если квест не начат).
+func (m *Manager) Cond(characterID int64, questID int32) int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[characterID][questID].cond()
+}
+
+// Var возвращает переменную квеста персонажа.
+func (m *Manager) Var(characterID int64, questID int32, name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if st := m.states[characterID][questID]; st != nil {
+		return st.vars[name]
+	}
+	return ""
+}
+
+// SetVar сохраняет переменную квеста персонажа (для скриптов).
+func (m *Manager) SetVar(ctx context.Context, characterID int64, questID int32, name, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states[characterID] == nil {
+		return ErrNotAttached
+	}
+	if m.defs[questID] == nil {
+		return ErrUnknownQuest
+	}
+	return m.setVarsLocked(ctx, characterID, questID, Var{Name: name, Value: value})
+}
+
+// Active возвращает начатые квесты персонажа, отсортированные по ID.
+func (m *Manager) Active(characterID int64) []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Entry
+	for id, st := range m.states[characterID] {
+		if st.status() == StatusStarted {
+			out = append(out, Entry{QuestID: id, Cond: st.cond()})
+		}
+	}
+	slices.SortFunc(out, func(a, b Entry) int { return cmp.Compare(a.QuestID, b.QuestID) })
+	return out
+}
+
+// Talk обрабатывает разговор с NPC. Если questName пуст, проверяются все квесты NPC
+// по возрастанию ID и возвращается первый ответ.
+// Пустой Result означает, что у NPC нет квестов для персонажа.
+func (m *Manager) Talk(ctx context.Context, p Player, npc Npc, questName string) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	trig := Talk(npc.TemplateID())
+	if questName != "" {
+		def := m.byName[strings.ToLower(questName)]
+		if def == nil {
+			return Result{}, ErrUnknownQuest
+		}
+		res, _, err := m.fireLocked(ctx, p, def, trig)
+		return res, err
+	}
+
+	for _, def := range m.byTrigger[triggerKey{typ: TriggerTalk, id: trig.ID}] {
+		res, handled, err := m.fireLocked(ctx, p, def, trig)
+		if err != nil || (handled && (res.HTML != "" || res.Changed)) {
+			return res, err
+		}
+	}
+	return Result{}, nil
+}
+
+// Event обрабатывает выбор ссылки event квеста questName в диалоге NPC.
+func (m *Manager) Event(ctx context.Context, p Player, npc Npc, questName, event string) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	def := m.byName[strings.ToLower(questName)]
+	if def == nil {
+		return Result{}, ErrUnknownQuest
+	}
+	res, _, err := m.fireLocked(ctx, p, def, Event(npc.TemplateID(), event))
+	return res, err
+}
+
+// Kill обрабатывает убийство NPC персонажем и возвращает изменённые квесты.
+func (m *Manager) Kill(ctx context.Context, p Player, npc Npc) ([]Result, error) {
+	return m.fireAll(ctx, p, Kill(npc.TemplateID()))
+}
+
+// Pickup обрабатывает подбор предмета itemID и возвращает изменённые квесты.
+func (m *Manager) Pickup(ctx context.Context, p Player, itemID int32) ([]Result, error) {
+	return m.fireAll(ctx, p, Pickup(itemID))
+}
+
+// Abort отменяет начатый квест: прогресс удаляется, квестовые предметы забираются.
+func (m *Manager) Abort(ctx context.Context, p Player, questID int32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	def := m.defs[questID]
+	if def == nil {
+		return ErrUnknownQuest
+	}
+	sts := m.states[p.CharacterID()]
+	if sts == nil {
+		return ErrNotAttached
+	}
+	if sts[questID].status() != StatusStarted {
+		return ErrNotStarted
+	}
+	if err := m.takeQuestItems(p, def); err != nil {
+		return err
+	}
+	return m.deleteLocked(ctx, p.CharacterID(), questID)
+}
+
+func (m *Manager) fireAll(ctx context.Context, p Player, trig Trigger) ([]Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []Result
+	for _, def := range m.byTrigger[triggerKey{typ: trig.Type, id: trig.ID}] {
+		res, handled, err := m.fireLocked(ctx, p, def, trig)
+		if err != nil {
+			return out, err
+		}
+		if handled && res.Changed {
+			out = append(out, res)
+		}
+	}
+	return out, nil
+}
+
+// fireLocked применяет к квесту def первый подходящий шаг для события trig.
+// handled=false — ни один шаг не подошёл.
+func (m *Manager) fireLocked(ctx context.Context, p Player, def *Definition, trig Trigger) (res Result, handled bool, err error) {
+	sts := m.states[p.CharacterID()]
+	if sts == nil {
+		return Result{}, false, ErrNotAttached
+	}
+	st := sts[def.ID]
+	res.QuestID = def.ID
+
+	if st.status() == StatusCompleted {
+		if trig.Type == TriggerTalk && slices.Contains(def.StartNpcs(), trig.ID) {
+			res.HTML = def.CompletedHTML
+			return res, true, nil
+		}
+		return Result{}, false, nil
+	}
+
+	cond := st.cond()
+	for i := range def.Steps {
+		step := &def.Steps[i]
+		if step.Cond != cond || step.On != trig {
+			continue
+		}
+		if !hasItems(p, step.Require) || !hasItems(p, step.Take) {
+			continue
+		}
+
+		if cond == 0 {
+			if p.Level() < def.MinLevel || (def.MaxLevel != 0 && p.Level() > def.MaxLevel) {
+				res.HTML = def.LevelHTML
+				return res, true, nil
+			}
+			if (step.Next > 0 || step.Complete) && m.activeCountLocked(p.CharacterID()) >= MaxActiveQuests {
+				return res, true, ErrTooManyQuests
+			}
+		}
+		if step.Chance > 0 && m.rand(100) >= int(step.Chance) {
+			return res, true, nil
+		}
+
+		changed, err := m.applyLocked(ctx, p, def, step)
+		if err != nil {
+			return res, true, fmt.Errorf("quest %d: %w", def.ID, err)
+		}
+		res.HTML = step.HTML
+		res.Changed = changed
+		return res, true, nil
+	}
+	return Result{}, false, nil
+}
+
+// applyLocked выполняет шаг и возвращает true, если у персонажа что-то изменилось.
+func (m *Manager) applyLocked(ctx context.Context, p Player, def *Definition, step *Step) (bool, error) {
+	changed := len(step.Take) > 0 || step.Exp != 0 || step.SP != 0
+	for _, it := range step.Take {
+		if err := p.TakeItems(it.ID, it.Count); err != nil {
+			return changed, fmt.Errorf("taking item %d: %w", it.ID, err)
+		}
+	}
+	for _, it := range step.Give {
+		count := it.Count
+		for _, u := range step.Until {
+			if u.ID == it.ID {
+				count = min(count, u.Count-p.ItemCount(it.ID))
+			}
+		}
+		if count <= 0 {
+			continue
+		}
+		if err := p.GiveItems(it.ID, count); err != nil {
+			return changed, fmt.Errorf("giving item %d: %w", it.ID, err)
+		}
+		changed = true
+	}
+	if step.Exp != 0 || step.SP != 0 {
+		p.AddExpSp(step.Exp, step.SP)
+	}
+
+	if !hasItems(p, step.Until) {
+		return changed, nil
+	}
+	characterID := p.CharacterID()
+	switch {
+	case step.Complete:
+		if err := m.takeQuestItems(p, def); err != nil {
+			return true, err
+		}
+		if err := m.deleteLocked(ctx, characterID, def.ID); err != nil {
+			return true, err
+		}
+		if def.Repeatable {
+			return true, nil
+		}
+		return true, m.setVarsLocked(ctx, characterID, def.ID, Var{Name: VarState, Value: StatusCompleted.String()})
+	case step.Next > 0:
+		return true, m.setVarsLocked(ctx, characterID, def.ID,
+			Var{Name: VarState, Value: StatusStarted.String()},
+			Var{Name: VarCond, Value: strconv.Itoa(int(step.Next))})
+	}
+	return changed, nil
+}
+
+func (m *Manager) setVarsLocked(ctx context.Context, characterID int64, questID int32, vars ...Var) error {
+	for _, v := range vars {
+		v.QuestID = questID
+		if m.repo != nil {
+			if err := m.repo.SaveVar(ctx, characterID, v); err != nil {
+				return fmt.Errorf("saving quest %d variable %q: %w", questID, v.Name, err)
+			}
+		}
+		sts := m.states[characterID]
+		st := sts[questID]
+		if st == nil {
+			st = &state{vars: make(map[string]string)}
+			sts[questID] = st
+		}
+		st.vars[v.Name] = v.Value
+	}
+	return nil
+}
+
+func (m *Manager) deleteLocked(ctx context.Context, characterID int64, questID int32) error {
+	if m.repo != nil {
+		if err := m.repo.DeleteQuest(ctx, characterID, questID); err != nil {
+			return fmt.Errorf("deleting quest %d: %w", questID, err)
+		}
+	}
+	delete(m.states[characterID], questID)
+	return nil
+}
+
+func (m *Manager) takeQuestItems(p Player, def *Definition) error {
+	for _, id := range def.QuestItems {
+		if n := p.ItemCount(id); n > 0 {
+			if err := p.TakeItems(id, n); err != nil {
+				return fmt.Errorf("taking quest item %d: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Manager) activeCountLocked(characterID int64) int {
+	n := 0
+	for _, st := range m.states[characterID] {
+		if st.status() == StatusStarted {
+			n++
+		}
+	}
+	return n
+}
+
+func hasItems(p Player, items []ItemCount) bool {
+	for _, it := range items {
+		if p.ItemCount(it.ID) < it.Count {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+package quest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const (
+	testNpc     = 30001
+	testWolf    = 20120
+	testPelt    = 1001
+	testAdena   = 57
+	testQuestID = 1
+)
+
+// fakePlayer — персонаж без мира и инвентаря model.
+type fakePlayer struct {
+	id    int64
+	level int32
+	items map[int32]int64
+	exp   int64
+	sp    int64
+}
+
+func newFakePlayer(id int64, level int32) *fakePlayer {
+	return &fakePlayer{id: id, level: level, items: make(map[int32]int64)}
+}
+
+func (p *fakePlayer) CharacterID() int64           { return p.id }
+func (p *fakePlayer) Name() string                 { return fmt.Sprintf("player%d", p.id) }
+func (p *fakePlayer) Level() int32                 { return p.level }
+func (p *fakePlayer) ItemCount(itemID int32) int64 { return p.items[itemID] }
+func (p *fakePlayer) AddExpSp(exp, sp int64)       { p.exp += exp; p.sp += sp }
+
+func (p *fakePlayer) GiveItems(itemID int32, count int64) error {
+	p.items[itemID] += count
+	return nil
+}
+
+func (p *fakePlayer) TakeItems(itemID int32, count int64) error {
+	if p.items[itemID] < count {
+		return errors.New("not enough items")
+	}
+	p.items[itemID] -= count
+	return nil
+}
+
+type fakeNpc int32
+
+func (n fakeNpc) ObjectID() uint32  { return 100000 + uint32(n) }
+func (n fakeNpc) TemplateID() int32 { return int32(n) }
+
+// memRepo хранит переменные квестов в памяти.
+type memRepo map[int64]map[int32]map[string]string
+
+func (r memRepo) LoadVars(_ context.Context, characterID int64) ([]Var, error) {
+	var out []Var
+	for questID, vars := range r[characterID] {
+		for name, value := range vars {
+			out = append(out, Var{QuestID: questID, Name: name, Value: value})
+		}
+	}
+	return out, nil
+}
+
+func (r memRepo) SaveVar(_ context.Context, characterID int64, v Var) error {
+	if r[characterID] == nil {
+		r[characterID] = make(map[int32]map[string]string)
+	}
+	if r[characterID][v.QuestID] == nil {
+		r[characterID][v.QuestID] = make(map[string]string)
+	}
+	r[characterID][v.QuestID][v.Name] = v.Value
+	return nil
+}
+
+func (r memRepo) DeleteQuest(_ context.Context, characterID int64, questID int32) error {
+	delete(r[characterID], questID)
+	return nil
+}
+
+// wolfPelts — квест: принести три шкуры волка.
+func wolfPelts(repeatable bool) *Definition {
+	return &Definition{
+		ID:            testQuestID,
+		Name:          "Q00001_WolfPelts",
+		MinLevel:      5,
+		MaxLevel:      20,
+		Repeatable:    repeatable,
+		QuestItems:    []int32{testPelt},
+		LevelHTML:     "too-low.htm",
+		CompletedHTML: "completed.htm",
+		Steps: []Step{
+			{Cond: 0, On: Talk(testNpc), HTML: "start.htm"},
+			{Cond: 0, On: Event(testNpc, "accept"), Next: 1, HTML: "accepted.htm"},
+			{Cond: 1, On: Kill(testWolf), Give: []ItemCount{{testPelt, 1}}, Until: []ItemCount{{testPelt, 3}}, Next: 2},
+			{Cond: 1, On: Talk(testNpc), HTML: "not-yet.htm"},
+			{
+				Cond: 2, On: Talk(testNpc),
+				Require:  []ItemCount{{testPelt, 3}},
+				Give:     []ItemCount{{testAdena, 100}},
+				Exp:      500,
+				Complete: true,
+				HTML:     "done.htm",
+			},
+		},
+	}
+}
+
+func newTestManager(t *testing.T, repo Repository, defs ...*Definition) *Manager {
+	t.Helper()
+	m := NewManager(repo)
+	for _, d := range defs {
+		if err := m.Register(d); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	return m
+}
+
+func talk(t *testing.T, m *Manager, p Player) string {
+	t.Helper()
+	res, err := m.Talk(context.Background(), p, fakeNpc(testNpc), "")
+	if err != nil {
+		t.Fatalf("Talk: %v", err)
+	}
+	return res.HTML
+}
+
+func TestManager_OneTimeQuest(t *testing.T) {
+	ctx := context.Background()
+	repo := memRepo{}
+	m := newTestManager(t, repo, wolfPelts(false))
+
+	low := newFakePlayer(1, 2)
+	if err := m.Attach(ctx, low.id); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if html := talk(t, m, low); html != "too-low.htm" {
+		t.Errorf("low level talk = %q", html)
+	}
+
+	p := newFakePlayer(2, 10)
+	if err := m.Attach(ctx, p.id); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if html := talk(t, m, p); html != "start.htm" {
+		t.Errorf("first talk = %q", html)
+	}
+	res, err := m.Event(ctx, p, fakeNpc(testNpc), "q00001_wolfpelts", "accept")
+	if err != nil || !res.Changed || res.HTML != "accepted.htm" {
+		t.Fatalf("accept: res=%+v err=%v", res, err)
+	}
+	if m.Status(p.id, testQuestID) != StatusStarted || m.Cond(p.id, testQuestID) != 1 {
+		t.Fatalf("quest not started: cond=%d", m.Cond(p.id, testQuestID))
+	}
+	if html := talk(t, m, p); html != "not-yet.htm" {
+		t.Errorf("talk in progress = %q", html)
+	}
+
+	for range 4 {
+		if _, err := m.Kill(ctx, p, fakeNpc(testWolf)); err != nil {
+			t.Fatalf("Kill: %v", err)
+		}
+	}
+	if p.items[testPelt] != 3 || m.Cond(p.id, testQuestID) != 2 {
+		t.Fatalf("pelts=%d cond=%d, want 3 and 2", p.items[testPelt], m.Cond(p.id, testQuestID))
+	}
+
+	// Прогресс восстанавливается после перезахода
+	m.Detach(p.id)
+	if err := m.Attach(ctx, p.id); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if got := m.Active(p.id); len(got) != 1 || got[0] != (Entry{QuestID: testQuestID, Cond: 2}) {
+		t.Errorf("Active after reattach = %v", got)
+	}
+
+	if html := talk(t, m, p); html != "done.htm" {
+		t.Errorf("final talk = %q", html)
+	}
+	if p.items[testPelt] != 0 || p.items[testAdena] != 100 || p.exp != 500 {
+		t.Errorf("rewards: pelts=%d adena=%d exp=%d", p.items[testPelt], p.items[testAdena], p.exp)
+	}
+	if m.Status(p.id, testQuestID) != StatusCompleted || len(m.Active(p.id)) != 0 {
+		t.Error("quest should be completed")
+	}
+	if html := talk(t, m, p); html != "completed.htm" {
+		t.Errorf("talk after completion = %q", html)
+	}
+	if res, _ := m.Event(ctx, p, fakeNpc(testNpc), "Q00001_WolfPelts", "accept"); res.Changed {
+		t.Error("one-time quest must not restart")
+	}
+	if got := repo[p.id][testQuestID][VarState]; got != "Completed" {
+		t.Errorf("persisted state = %q", got)
+	}
+}
+
+func TestManager_RepeatableQuest(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, nil, wolfPelts(true))
+	p := newFakePlayer(1, 10)
+	_ = m.Attach(ctx, p.id)
+
+	for round := range 2 {
+		if _, err := m.Event(ctx, p, fakeNpc(testNpc), "Q00001_WolfPelts", "accept"); err != nil {
+			t.Fatalf("round %d accept: %v", round, err)
+		}
+		p.items[testPelt] = 3
+		_ = m.SetVar(ctx, p.id, testQuestID, VarCond, "2")
+		if html := talk(t, m, p); html != "done.htm" {
+			t.Fatalf("round %d final talk = %q", round, html)
+		}
+		if m.Status(p.id, testQuestID) != StatusCreated {
+			t.Fatalf("round %d: repeatable quest should reset", round)
+		}
+	}
+	if p.items[testAdena] != 200 {
+		t.Errorf("adena = %d, want 200", p.items[testAdena])
+	}
+}
+
+func TestManager_Abort(t *testing.T) {
+	ctx := context.Background()
+	repo := memRepo{}
+	m := newTestManager(t, repo, wolfPelts(false))
+	p := newFakePlayer(1, 10)
+	_ = m.Attach(ctx, p.id)
+
+	if err := m.Abort(ctx, p, testQuestID); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("abort not started: err = %v", err)
+	}
+	_, _ = m.Event(ctx, p, fakeNpc(testNpc), "Q00001_WolfPelts", "accept")
+	_, _ = m.Kill(ctx, p, fakeNpc(testWolf))
+
+	if err := m.Abort(ctx, p, testQuestID); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if p.items[testPelt] != 0 || m.Status(p.id, testQuestID) != StatusCreated || len(repo[p.id]) != 0 {
+		t.Error("abort should remove quest items and progress")
+	}
+	if err := m.Abort(ctx, p, 99); !errors.Is(err, ErrUnknownQuest) {
+		t.Errorf("abort unknown: err = %v", err)
+	}
+}
+
+func TestManager_KillChance(t *testing.T) {
+	ctx := context.Background()
+	def := wolfPelts(false)
+	def.Steps[2].Chance = 50
+	m := newTestManager(t, nil, def)
+	roll := 0
+	m.rand = func(int) int { return roll }
+
+	p := newFakePlayer(1, 10)
+	_ = m.Attach(ctx, p.id)
+	_, _ = m.Event(ctx, p, fakeNpc(testNpc), "Q00001_WolfPelts", "accept")
+
+	roll = 70
+	if res, _ := m.Kill(ctx, p, fakeNpc(testWolf)); len(res) != 0 || p.items[testPelt] != 0 {
+		t.Error("failed roll must not give items")
+	}
+	roll = 10
+	if res, _ := m.Kill(ctx, p, fakeNpc(testWolf)); len(res) != 1 || p.items[testPelt] != 1 {
+		t.Errorf("successful roll: results=%v pelts=%d", res, p.items[testPelt])
+	}
+}
+
+func TestManager_Register(t *testing.T) {
+	m := newTestManager(t, nil, wolfPelts(false))
+	if err := m.Register(wolfPelts(false)); !errors.Is(err, ErrDuplicateQuest) {
+		t.Errorf("duplicate: err = %v", err)
+	}
+
+	noStart := &Definition{ID: 2, Name: "NoStart", Steps: []Step{{Cond: 1, On: Talk(testNpc)}}}
+	if err := m.Register(noStart); !errors.Is(err, ErrInvalidDefinition) {
+		t.Errorf("no start step: err = %v", err)
+	}
+	killStart := &Definition{ID: 3, Name: "KillStart", Steps: []Step{{On: Kill(testWolf), Next: 1}}}
+	if err := m.Register(killStart); !errors.Is(err, ErrInvalidDefinition) {
+		t.Errorf("kill start: err = %v", err)
+	}
+	if _, err := m.Talk(context.Background(), newFakePlayer(9, 10), fakeNpc(testNpc), ""); !errors.Is(err, ErrNotAttached) {
+		t.Errorf("talk without attach: err = %v", err)
+	}
+}
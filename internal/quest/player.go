@@ -0,0 +1,56 @@
+package quest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Player — персонаж с точки зрения квестов.
+// Интерфейс позволяет проверять квесты без мира и сети (фейковые игроки в тестах).
+type Player interface {
+	CharacterID() int64
+	Name() string
+	Level() int32
+	ItemCount(itemID int32) int64
+	GiveItems(itemID int32, count int64) error
+	TakeItems(itemID int32, count int64) error
+	AddExpSp(exp, sp int64)
+}
+
+// Npc — NPC с точки зрения квестов.
+type Npc interface {
+	ObjectID() uint32
+	TemplateID() int32
+}
+
+// PlayerOf адаптирует model.Player к Player.
+func PlayerOf(p *model.Player) Player {
+	return modelPlayer{p}
+}
+
+type modelPlayer struct {
+	*model.Player
+}
+
+func (p modelPlayer) ItemCount(itemID int32) int64 {
+	return p.Inventory().CountOf(itemID)
+}
+
+func (p modelPlayer) GiveItems(itemID int32, count int64) error {
+	if count > math.MaxInt32 {
+		return fmt.Errorf("item count %d is too large", count)
+	}
+	_, err := p.Inventory().AddByType(itemID, int32(count))
+	return err
+}
+
+func (p modelPlayer) TakeItems(itemID int32, count int64) error {
+	return p.Inventory().DestroyByType(itemID, count)
+}
+
+func (p modelPlayer) AddExpSp(exp, sp int64) {
+	p.AddExperience(exp)
+	p.AddSP(sp)
+}
@@ -0,0 +1,138 @@
+package quest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TriggerType — тип события, на которое реагирует шаг квеста.
+type TriggerType int32
+
+const (
+	TriggerTalk   TriggerType = iota // разговор с NPC
+	TriggerEvent                     // ссылка в диалоге NPC (bypass "Quest <name> <event>")
+	TriggerKill                      // убийство NPC
+	TriggerPickup                    // подбор предмета
+)
+
+// Trigger — событие шага: тип, ID шаблона NPC или предмета и имя события для TriggerEvent.
+type Trigger struct {
+	Type  TriggerType
+	ID    int32
+	Event string
+}
+
+// Talk — разговор с NPC npcID.
+func Talk(npcID int32) Trigger { return Trigger{Type: TriggerTalk, ID: npcID} }
+
+// Event — выбор ссылки event в диалоге NPC npcID.
+func Event(npcID int32, event string) Trigger {
+	return Trigger{Type: TriggerEvent, ID: npcID, Event: event}
+}
+
+// Kill — убийство NPC npcID.
+func Kill(npcID int32) Trigger { return Trigger{Type: TriggerKill, ID: npcID} }
+
+// Pickup — подбор предмета itemID.
+func Pickup(itemID int32) Trigger { return Trigger{Type: TriggerPickup, ID: itemID} }
+
+// ItemCount — предмет и количество.
+type ItemCount struct {
+	ID    int32
+	Count int64
+}
+
+// Step — переход квеста. Шаг срабатывает на событие On, если текущий cond равен Cond
+// (0 — квест не начат) и у персонажа есть предметы Require.
+// Для одного события шаги проверяются по порядку, срабатывает первый подходящий.
+type Step struct {
+	Cond    int32
+	On      Trigger
+	Require []ItemCount
+
+	Chance int32       // шанс срабатывания в процентах (0 = всегда), для Kill и Pickup
+	Take   []ItemCount // забираются при срабатывании
+	Give   []ItemCount // выдаются при срабатывании (не больше Until для того же предмета)
+	Exp    int64
+	SP     int64
+
+	// Until — предметы, после накопления которых квест переходит в Next/Complete.
+	// Пустой Until — переход сразу.
+	Until    []ItemCount
+	Next     int32 // новый cond (0 = не менять)
+	Complete bool  // завершить квест
+
+	HTML string // ответ NPC
+}
+
+// Definition — описание квеста.
+type Definition struct {
+	ID         int32
+	Name       string // имя для bypass-ссылок (например "Q00001_LettersOfLove")
+	MinLevel   int32
+	MaxLevel   int32 // 0 = без ограничения
+	Repeatable bool  // после завершения квест можно взять снова
+
+	// QuestItems забираются у персонажа при отмене и завершении квеста.
+	QuestItems []int32
+
+	// LevelHTML — ответ стартового NPC, если уровень персонажа не подходит.
+	LevelHTML string
+	// CompletedHTML — ответ NPC по завершённому одноразовому квесту.
+	CompletedHTML string
+
+	Steps []Step
+}
+
+var ErrInvalidDefinition = errors.New("invalid quest definition")
+
+// Validate проверяет описание квеста.
+func (d *Definition) Validate() error {
+	if d.ID <= 0 || d.Name == "" {
+		return fmt.Errorf("%w: quest must have an ID and a name", ErrInvalidDefinition)
+	}
+	if d.MaxLevel != 0 && d.MaxLevel < d.MinLevel {
+		return fmt.Errorf("%w: quest %d: max level %d below min level %d", ErrInvalidDefinition, d.ID, d.MaxLevel, d.MinLevel)
+	}
+	started := false
+	for i, s := range d.Steps {
+		if s.Cond < 0 || s.Next < 0 {
+			return fmt.Errorf("%w: quest %d step %d: negative cond", ErrInvalidDefinition, d.ID, i)
+		}
+		if s.Chance < 0 || s.Chance > 100 {
+			return fmt.Errorf("%w: quest %d step %d: chance %d", ErrInvalidDefinition, d.ID, i, s.Chance)
+		}
+		if s.Cond == 0 {
+			if s.On.Type != TriggerTalk && s.On.Type != TriggerEvent {
+				return fmt.Errorf("%w: quest %d step %d: quest can only be started by an NPC", ErrInvalidDefinition, d.ID, i)
+			}
+			if s.Next > 0 || s.Complete {
+				started = true
+			}
+		}
+	}
+	if !started {
+		return fmt.Errorf("%w: quest %d has no start step", ErrInvalidDefinition, d.ID)
+	}
+	return nil
+}
+
+// StartNpcs возвращает шаблоны NPC, у которых можно начать квест.
+func (d *Definition) StartNpcs() []int32 {
+	var ids []int32
+	for _, s := range d.Steps {
+		if s.Cond == 0 && (s.Next > 0 || s.Complete) {
+			ids = appendUnique(ids, s.On.ID)
+		}
+	}
+	return ids
+}
+
+func appendUnique(ids []int32, id int32) []int32 {
+	for _, v := range ids {
+		if v == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,17 @@
+package quest
+
+import "context"
+
+// Var — переменная квеста персонажа (состояние, cond и произвольные переменные скриптов).
+type Var struct {
+	QuestID int32
+	Name    string
+	Value   string
+}
+
+// Repository хранит прогресс квестов персонажей.
+type Repository interface {
+	LoadVars(ctx context.Context, characterID int64) ([]Var, error)
+	SaveVar(ctx context.Context, characterID int64, v Var) error
+	DeleteQuest(ctx context.Context, characterID int64, questID int32) error
+}
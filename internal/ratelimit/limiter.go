@@ -0,0 +1,236 @@
+package ratelimit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OffenderTTL is how long a client that stops tripping limits stays on the offender list.
+// Clients are counted by IP before they log in, so the list must not keep them forever.
+const OffenderTTL = 10 * time.Minute
+
+// Offender counts the packets of one client that went over a limit.
+type Offender struct {
+	Who   string            // account name or IP address
+	Total uint64            // all trips
+	Trips map[string]uint64 // rule name → trips
+	Last  time.Time
+}
+
+// Limiter holds the current rules and counts who trips them. Thread-safe.
+type Limiter struct {
+	rules atomic.Pointer[Rules]
+
+	mu        sync.Mutex
+	offenders map[string]*Offender
+
+	now func() time.Time
+}
+
+// NewLimiter creates a limiter with the given rules.
+func NewLimiter(rules *Rules) *Limiter {
+	l := &Limiter{
+		offenders: make(map[string]*Offender),
+		now:       time.Now,
+	}
+	l.SetRules(rules)
+	return l
+}
+
+// Rules returns the rules in force.
+func (l *Limiter) Rules() *Rules {
+	return l.rules.Load()
+}
+
+// SetRules replaces the rules. Clients start over with full buckets on their next packet.
+func (l *Limiter) SetRules(rules *Rules) {
+	if rules == nil {
+		rules = &Rules{}
+	}
+	l.rules.Store(rules)
+}
+
+// NewClient returns the packet buckets of a new connection.
+func (l *Limiter) NewClient() *Client {
+	return &Client{limiter: l}
+}
+
+// Offenders returns the clients that tripped limits, most trips first.
+func (l *Limiter) Offenders() []Offender {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	list := make([]Offender, 0, len(l.offenders))
+	for _, o := range l.offenders {
+		c := *o
+		c.Trips = make(map[string]uint64, len(o.Trips))
+		for name, n := range o.Trips {
+			c.Trips[name] = n
+		}
+		list = append(list, c)
+	}
+	slices.SortFunc(list, func(a, b Offender) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Who, b.Who)
+	})
+	return list
+}
+
+// ResetOffenders forgets all counted trips.
+func (l *Limiter) ResetOffenders() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.offenders)
+}
+
+// pruneOffenders forgets the clients that tripped no limit for OffenderTTL.
+func (l *Limiter) pruneOffenders(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for who, o := range l.offenders {
+		if now.Sub(o.Last) > OffenderTTL {
+			delete(l.offenders, who)
+		}
+	}
+}
+
+func (l *Limiter) trip(who string, rule *Rule, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	o, ok := l.offenders[who]
+	if !ok {
+		o = &Offender{Who: who, Trips: make(map[string]uint64)}
+		l.offenders[who] = o
+	}
+	o.Total++
+	o.Trips[rule.Name]++
+	o.Last = now
+}
+
+// Watch reloads rules from path whenever the file changes (polling every interval);
+// a removed file brings back fallback. Broken files are logged and the old rules kept.
+// Each poll also forgets offenders older than OffenderTTL. Blocks until ctx is canceled.
+func (l *Limiter) Watch(ctx context.Context, path string, fallback *Rules, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := modTime(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			l.pruneOffenders(l.now())
+			mod := modTime(path)
+			if mod.Equal(last) {
+				continue
+			}
+			rules, err := LoadRulesFile(path, fallback)
+			if err != nil {
+				slog.Error("failed to reload rate limits", "path", path, "error", err)
+				continue
+			}
+			last = mod
+			l.SetRules(rules)
+			slog.Info("rate limits reloaded", "path", path, "rules", len(rules.ByOpcode))
+		}
+	}
+}
+
+// modTime returns the modification time of path, zero if it does not exist.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("checking rate limits file", "path", path, "error", err)
+		}
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// bucket is the token bucket of one rule.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time passed and takes a token if there is one.
+func (b *bucket) take(r *Rule, now time.Time) bool {
+	if b.last.IsZero() {
+		b.tokens = float64(r.Burst)
+	} else {
+		b.tokens = min(float64(r.Burst), b.tokens+now.Sub(b.last).Seconds()*r.Rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Client holds the buckets of one connection. Not safe for concurrent use: packets of
+// a client are handled one by one.
+type Client struct {
+	limiter *Limiter
+	rules   *Rules // the rules buckets were filled for
+	global  bucket
+	opcodes map[Key]*bucket
+}
+
+// severity orders actions from the mildest.
+func severity(a Action) int {
+	switch a {
+	case Warn:
+		return 0
+	case Drop:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Allow takes a packet with the given key from the client's buckets. It returns the
+// rule whose limit the packet broke, the one with the harshest action if both the
+// global and the opcode limit are over, or nil if the packet is within limits.
+// Trips are counted for who.
+func (c *Client) Allow(key Key, who string) *Rule {
+	rules := c.limiter.Rules()
+	if rules != c.rules {
+		c.rules = rules
+		c.global = bucket{}
+		c.opcodes = make(map[Key]*bucket, len(rules.ByOpcode))
+	}
+	now := c.limiter.now()
+
+	var tripped *Rule
+	if r := rules.Global; r != nil && !c.global.take(r, now) {
+		c.limiter.trip(who, r, now)
+		tripped = r
+	}
+	if r, ok := rules.ByOpcode[key]; ok {
+		b, ok := c.opcodes[key]
+		if !ok {
+			b = &bucket{}
+			c.opcodes[key] = b
+		}
+		if !b.take(r, now) {
+			c.limiter.trip(who, r, now)
+			if tripped == nil || severity(r.Action) > severity(tripped.Action) {
+				tripped = r
+			}
+		}
+	}
+	return tripped
+}
@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRules = `
+global: {rate: 5, burst: 6, action: disconnect}
+opcodes:
+  - {opcode: 0x38, name: Say2, rate: 1, burst: 2}
+  - {opcode: 0x14, rate: 2, action: warn}
+  - {opcode: 0xD0, sub: 0x1E, rate: 1}
+`
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if g := rules.Global; g == nil || g.Name != GlobalRule || g.Rate != 5 || g.Burst != 6 || g.Action != Disconnect {
+		t.Errorf("global = %+v", g)
+	}
+	if r := rules.ByOpcode[Opcode(0x38)]; r == nil || r.Name != "Say2" || r.Burst != 2 || r.Action != Drop {
+		t.Errorf("Say2 = %+v, want drop with burst 2", r)
+	}
+	if r := rules.ByOpcode[Opcode(0x14)]; r == nil || r.Name != "0x14" || r.Burst != 2 || r.Action != Warn {
+		t.Errorf("0x14 = %+v, want warn with burst defaulted to the rate", r)
+	}
+	if r := rules.ByOpcode[Extended(0x1E)]; r == nil || r.Name != "0xD0:0x1E" {
+		t.Errorf("0xD0:0x1E = %+v, want a rule named by opcode and sub-opcode", r)
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no opcode":      "opcodes: [{rate: 1}]",
+		"opcode range":   "opcodes: [{opcode: 256, rate: 1}]",
+		"duplicate":      "opcodes: [{opcode: 1, rate: 1}, {opcode: 0x01, rate: 2}]",
+		"zero rate":      "opcodes: [{opcode: 1, rate: 0}]",
+		"unknown action": "global: {rate: 1, action: ban}",
+		"no sub-opcode":  "opcodes: [{opcode: 0xD0, rate: 1}]",
+		"sub range":      "opcodes: [{opcode: 0xD0, sub: 0x10000, rate: 1}]",
+		"needless sub":   "opcodes: [{opcode: 0x38, sub: 1, rate: 1}]",
+		"duplicate sub":  "opcodes: [{opcode: 0xD0, sub: 1, rate: 1}, {opcode: 0xD0, sub: 0x01, rate: 2}]",
+	} {
+		if _, err := LoadRules(strings.NewReader(data)); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("%s: error = %v, want ErrInvalidRules", name, err)
+		}
+	}
+}
+
+func TestClient_Allow(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLimiter(rules)
+	l.now = func() time.Time { return now }
+	c := l.NewClient()
+
+	// The burst of Say2 passes, the next one is over
+	for i := range 2 {
+		if r := c.Allow(Opcode(0x38), "alice"); r != nil {
+			t.Fatalf("Say2 %d tripped %s", i+1, r.Name)
+		}
+	}
+	if r := c.Allow(Opcode(0x38), "alice"); r == nil || r.Name != "Say2" {
+		t.Fatalf("third Say2 tripped %v, want Say2", r)
+	}
+
+	// Tokens come back at the rate
+	now = now.Add(time.Second)
+	if r := c.Allow(Opcode(0x38), "alice"); r != nil {
+		t.Errorf("Say2 after a second tripped %s", r.Name)
+	}
+
+	// Unlimited opcodes count only towards the global cap, refilled to 6 a second ago
+	var tripped *Rule
+	for range 6 {
+		tripped = c.Allow(Opcode(0x01), "alice")
+	}
+	if tripped == nil || tripped.Action != Disconnect {
+		t.Fatalf("global cap tripped %v, want disconnect", tripped)
+	}
+
+	offenders := l.Offenders()
+	if len(offenders) != 1 || offenders[0].Who != "alice" || offenders[0].Total != 2 ||
+		offenders[0].Trips["Say2"] != 1 || offenders[0].Trips[GlobalRule] != 1 {
+		t.Errorf("offenders = %+v", offenders)
+	}
+	l.ResetOffenders()
+	if len(l.Offenders()) != 0 {
+		t.Error("ResetOffenders kept counters")
+	}
+}
+
+func TestClient_AllowExtended(t *testing.T) {
+	l := NewLimiter(&Rules{ByOpcode: map[Key]*Rule{
+		Extended(0x1E): NewRule("RequestPledgeWarList", 1, 1, Drop),
+	}})
+	c := l.NewClient()
+
+	if r := c.Allow(Extended(0x1E), "alice"); r != nil {
+		t.Fatalf("first war list tripped %s", r.Name)
+	}
+	if r := c.Allow(Extended(0x1E), "alice"); r == nil {
+		t.Error("second war list not limited")
+	}
+	// Other extended packets have their own limits
+	for range 10 {
+		if r := c.Allow(Extended(0x11), "alice"); r != nil {
+			t.Fatalf("unlimited extended packet tripped %s", r.Name)
+		}
+	}
+}
+
+func TestClient_HarshestAction(t *testing.T) {
+	l := NewLimiter(&Rules{
+		Global:   NewRule(GlobalRule, 1, 1, Warn),
+		ByOpcode: map[Key]*Rule{Opcode(0x38): NewRule("Say2", 1, 1, Disconnect)},
+	})
+	c := l.NewClient()
+	c.Allow(Opcode(0x38), "bob")
+	if r := c.Allow(Opcode(0x38), "bob"); r == nil || r.Action != Disconnect {
+		t.Errorf("tripped %v, want the disconnect of Say2 over the global warning", r)
+	}
+}
+
+func TestLimiter_SetRules(t *testing.T) {
+	l := NewLimiter(&Rules{ByOpcode: map[Key]*Rule{Opcode(0x38): NewRule("Say2", 1, 1, Drop)}})
+	c := l.NewClient()
+	c.Allow(Opcode(0x38), "alice")
+	if c.Allow(Opcode(0x38), "alice") == nil {
+		t.Fatal("limit not applied")
+	}
+
+	// New rules apply to connected clients at once
+	l.SetRules(&Rules{ByOpcode: map[Key]*Rule{Opcode(0x38): NewRule("Say2", 10, 10, Drop)}})
+	if r := c.Allow(Opcode(0x38), "alice"); r != nil {
+		t.Errorf("old limit still applied after SetRules")
+	}
+	l.SetRules(nil)
+	for range 100 {
+		if c.Allow(Opcode(0x38), "alice") != nil {
+			t.Fatal("limit applied without rules")
+		}
+	}
+}
+
+func TestLimiter_PruneOffenders(t *testing.T) {
+	l := NewLimiter(&Rules{ByOpcode: map[Key]*Rule{Opcode(0x38): NewRule("Say2", 1, 1, Drop)}})
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+
+	for _, who := range []string{"10.0.0.1", "alice"} {
+		c := l.NewClient()
+		c.Allow(Opcode(0x38), who)
+		c.Allow(Opcode(0x38), who)
+		now = now.Add(OffenderTTL / 2)
+	}
+	if n := len(l.Offenders()); n != 2 {
+		t.Fatalf("offenders = %d, want 2", n)
+	}
+
+	// Only the offender quiet for longer than OffenderTTL is forgotten
+	l.pruneOffenders(now.Add(time.Second))
+	list := l.Offenders()
+	if len(list) != 1 || list[0].Who != "alice" {
+		t.Errorf("offenders after prune = %v, want alice only", list)
+	}
+}
@@ -0,0 +1,213 @@
+// Package ratelimit limits how fast a game client may send packets: a token bucket per
+// opcode (per sub-opcode for extended packets) and one for all packets together. Rules can be replaced while clients are connected.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRules is returned for rate limit data that cannot be used
+var ErrInvalidRules = errors.New("invalid rate limits")
+
+// Action is what happens to a packet over its limit.
+type Action uint8
+
+const (
+	Drop       Action = iota // the packet is ignored
+	Warn                     // the packet is handled, the trip is only logged and counted
+	Disconnect               // the client is disconnected
+
+	actionCount
+)
+
+var actionNames = [actionCount]string{
+	Drop:       "drop",
+	Warn:       "warn",
+	Disconnect: "disconnect",
+}
+
+// String returns the name of the action used in rule files.
+func (a Action) String() string {
+	if a < actionCount {
+		return actionNames[a]
+	}
+	return fmt.Sprintf("Action(%d)", uint8(a))
+}
+
+// ParseAction returns the action with the given name.
+func ParseAction(s string) (Action, bool) {
+	for a, name := range actionNames {
+		if name == s {
+			return Action(a), true
+		}
+	}
+	return 0, false
+}
+
+// GlobalRule is the name of the rule that limits all packets of a client.
+const GlobalRule = "global"
+
+// ExtendedOpcode prefixes client packets that carry an int16 sub-opcode.
+const ExtendedOpcode = 0xD0
+
+// Key identifies the packets a rule limits. Extended packets are told apart by
+// their sub-opcode, so one of them cannot use up the limit of the others.
+type Key struct {
+	Opcode byte
+	Sub    int16 // sub-opcode of extended packets, 0 for the others
+}
+
+// Opcode returns the key of packets without a sub-opcode.
+func Opcode(opcode byte) Key {
+	return Key{Opcode: opcode}
+}
+
+// Extended returns the key of the extended packet with the given sub-opcode.
+func Extended(sub int16) Key {
+	return Key{Opcode: ExtendedOpcode, Sub: sub}
+}
+
+// String returns the key as written in logs and default rule names: 0x38 or 0xD0:0x1E.
+func (k Key) String() string {
+	if k.Opcode == ExtendedOpcode {
+		return fmt.Sprintf("0x%02X:0x%02X", k.Opcode, uint16(k.Sub))
+	}
+	return fmt.Sprintf("0x%02X", k.Opcode)
+}
+
+// Rule is a token bucket: Rate packets per second on average, up to Burst at once.
+type Rule struct {
+	Name   string
+	Rate   float64
+	Burst  int
+	Action Action
+}
+
+// Rules is a set of limits. Immutable once in use; replace it with Limiter.SetRules.
+type Rules struct {
+	Global   *Rule // nil = no cap on all packets
+	ByOpcode map[Key]*Rule
+}
+
+// NewRule returns a rule with its burst defaulted to one second of packets.
+func NewRule(name string, rate float64, burst int, action Action) *Rule {
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(rate)))
+	}
+	return &Rule{Name: name, Rate: rate, Burst: burst, Action: action}
+}
+
+// rulesFile is the YAML layout of rate limits:
+//
+//	global: {rate: 60, burst: 100, action: disconnect}
+//	opcodes:
+//	  - {opcode: 0x38, name: Say2, rate: 3, burst: 5, action: drop}
+//	  - {opcode: 0x01, name: MoveBackwardToLocation, rate: 10, action: drop}
+//	  - {opcode: 0xD0, sub: 0x1E, name: RequestPledgeWarList, rate: 2}
+type rulesFile struct {
+	Global *ruleEntry  `yaml:"global"`
+	Opcode []ruleEntry `yaml:"opcodes"`
+}
+
+type ruleEntry struct {
+	Opcode *int    `yaml:"opcode"`
+	Sub    *int    `yaml:"sub"` // sub-opcode, required for extended packets (0xD0)
+	Name   string  `yaml:"name"`
+	Rate   float64 `yaml:"rate"`
+	Burst  int     `yaml:"burst"`
+	Action string  `yaml:"action"`
+}
+
+func (e ruleEntry) rule(name string) (*Rule, error) {
+	if e.Name != "" {
+		name = e.Name
+	}
+	if e.Rate <= 0 {
+		return nil, fmt.Errorf("rule %s: rate must be positive: %w", name, ErrInvalidRules)
+	}
+	action := Drop
+	if e.Action != "" {
+		var ok bool
+		if action, ok = ParseAction(e.Action); !ok {
+			return nil, fmt.Errorf("rule %s: unknown action %q: %w", name, e.Action, ErrInvalidRules)
+		}
+	}
+	return NewRule(name, e.Rate, e.Burst, action), nil
+}
+
+// key checks the opcode and sub-opcode of the entry.
+func (e ruleEntry) key() (Key, error) {
+	if e.Opcode == nil || *e.Opcode < 0 || *e.Opcode > math.MaxUint8 {
+		return Key{}, fmt.Errorf("rule %q: opcode must be 0x00-0xFF: %w", e.Name, ErrInvalidRules)
+	}
+	opcode := byte(*e.Opcode)
+	if opcode != ExtendedOpcode {
+		if e.Sub != nil {
+			return Key{}, fmt.Errorf("rule %q: opcode 0x%02X has no sub-opcode: %w", e.Name, opcode, ErrInvalidRules)
+		}
+		return Opcode(opcode), nil
+	}
+	if e.Sub == nil || *e.Sub < 0 || *e.Sub > math.MaxUint16 {
+		return Key{}, fmt.Errorf("rule %q: extended opcode 0x%02X needs a sub-opcode 0x0000-0xFFFF: %w", e.Name, opcode, ErrInvalidRules)
+	}
+	return Extended(int16(uint16(*e.Sub))), nil
+}
+
+// LoadRules parses rate limits. Rules without an action drop packets.
+func LoadRules(r io.Reader) (*Rules, error) {
+	var f rulesFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing rate limits: %w", err)
+	}
+
+	rules := &Rules{ByOpcode: make(map[Key]*Rule, len(f.Opcode))}
+	if f.Global != nil {
+		global, err := f.Global.rule(GlobalRule)
+		if err != nil {
+			return nil, err
+		}
+		rules.Global = global
+	}
+	for _, e := range f.Opcode {
+		key, err := e.key()
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := rules.ByOpcode[key]; dup {
+			return nil, fmt.Errorf("opcode %s limited twice: %w", key, ErrInvalidRules)
+		}
+		rule, err := e.rule(key.String())
+		if err != nil {
+			return nil, err
+		}
+		rules.ByOpcode[key] = rule
+	}
+	return rules, nil
+}
+
+// LoadRulesFile loads rate limits from a YAML file.
+// A missing file returns fallback.
+func LoadRulesFile(path string, fallback *Rules) (*Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("rate limits file not found, using built-in limits", "path", path)
+			return fallback, nil
+		}
+		return nil, fmt.Errorf("opening rate limits %s: %w", path, err)
+	}
+	defer f.Close()
+
+	rules, err := LoadRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading rate limits %s: %w", path, err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,355 @@
+package script
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// globals builds the global environment of a script: the builtin functions.
+//
+//	register_npc(id, ...)            bind NPC templates to the script (top level only)
+//	spawn_npc(id, x, y, z[, heading]) spawn an NPC, returns it
+//	find_npc(objectID)               spawned NPC or nil
+//	random(n)                        integer in [0, n)
+//	floor(x), tostring(v), tonumber(v)
+//	log(...)                         write to the server log
+func (rt *Runtime) globals(s *Script) map[string]Value {
+	g := make(map[string]Value, 16)
+	def := func(name string, fn func(c *Call, args []Value) (Value, error)) {
+		g[name] = &Builtin{Name: name, Fn: fn}
+	}
+
+	def("register_npc", func(c *Call, args []Value) (Value, error) {
+		if c.loading != s {
+			return nil, fmt.Errorf("register_npc: %w", ErrNotLoading)
+		}
+		for i := range args {
+			id, err := argInt32(args, i, "register_npc")
+			if err != nil {
+				return nil, err
+			}
+			s.npcs = append(s.npcs, id)
+		}
+		return nil, nil
+	})
+
+	def("spawn_npc", func(c *Call, args []Value) (Value, error) {
+		if rt.world == nil {
+			return nil, fmt.Errorf("spawn_npc: no world")
+		}
+		var coords [4]int32 // x, y, z, heading (optional)
+		for i := range coords {
+			if i == 3 && len(args) < 5 {
+				break
+			}
+			v, err := argInt32(args, i+1, "spawn_npc")
+			if err != nil {
+				return nil, err
+			}
+			coords[i] = v
+		}
+		id, err := argInt32(args, 0, "spawn_npc")
+		if err != nil {
+			return nil, err
+		}
+		loc := model.NewLocation(coords[0], coords[1], coords[2], uint16(coords[3]))
+		npc, err := rt.world.SpawnNpc(c.Ctx, id, loc)
+		if err != nil {
+			return nil, fmt.Errorf("spawn_npc: %w", err)
+		}
+		return rt.npc(npc), nil
+	})
+
+	def("find_npc", func(c *Call, args []Value) (Value, error) {
+		id, err := argNumber(args, 0, "find_npc")
+		if err != nil {
+			return nil, err
+		}
+		if rt.world == nil || id < 0 || id > math.MaxUint32 {
+			return nil, nil
+		}
+		npc, ok := rt.world.Npc(uint32(id))
+		if !ok {
+			return nil, nil
+		}
+		return rt.npc(npc), nil
+	})
+
+	def("random", func(c *Call, args []Value) (Value, error) {
+		n, err := argInt32(args, 0, "random")
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("random: bound must be positive")
+		}
+		return float64(rand.Int32N(n)), nil
+	})
+
+	def("floor", func(c *Call, args []Value) (Value, error) {
+		n, err := argNumber(args, 0, "floor")
+		if err != nil {
+			return nil, err
+		}
+		return math.Floor(n), nil
+	})
+
+	def("tostring", func(c *Call, args []Value) (Value, error) {
+		return ToString(arg(args, 0)), nil
+	})
+
+	def("tonumber", func(c *Call, args []Value) (Value, error) {
+		switch v := arg(args, 0).(type) {
+		case float64:
+			return v, nil
+		case string:
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, nil
+			}
+			return n, nil
+		}
+		return nil, nil
+	})
+
+	def("log", func(c *Call, args []Value) (Value, error) {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = ToString(a)
+		}
+		slog.Info("script: "+strings.Join(parts, " "), "script", s.name)
+		return nil, nil
+	})
+
+	return g
+}
+
+// Player is a player as seen by scripts.
+//
+//	name() object_id() level() x() y() z() hp() max_hp() is_dead()
+//	item_count(id)  give_item(id, count)  take_item(id, count) → bool
+//	add_exp(exp, sp)  message(text)  show_html(html[, npc])
+type Player struct {
+	p  *model.Player
+	rt *Runtime
+}
+
+func (rt *Runtime) player(p *model.Player) Value {
+	if p == nil {
+		return nil
+	}
+	return Player{p: p, rt: rt}
+}
+
+// TypeName implements Object.
+func (Player) TypeName() string { return "player" }
+
+// CallMethod implements Object.
+func (o Player) CallMethod(c *Call, method string, args []Value) (Value, error) {
+	p := o.p
+	switch method {
+	case "name":
+		return p.Name(), nil
+	case "object_id":
+		return float64(p.ObjectID()), nil
+	case "level":
+		return float64(p.Level()), nil
+	case "x":
+		return float64(p.X()), nil
+	case "y":
+		return float64(p.Y()), nil
+	case "z":
+		return float64(p.Z()), nil
+	case "hp":
+		return float64(p.CurrentHP()), nil
+	case "max_hp":
+		return float64(p.MaxHP()), nil
+	case "is_dead":
+		return p.IsDead(), nil
+
+	case "item_count":
+		id, err := argInt32(args, 0, "item_count")
+		if err != nil {
+			return nil, err
+		}
+		return float64(p.Inventory().CountOf(id)), nil
+
+	case "give_item":
+		id, count, err := itemArgs(args, "give_item")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.Inventory().AddByType(id, count); err != nil {
+			return nil, fmt.Errorf("give_item: %w", err)
+		}
+		o.inventoryChanged(c)
+		return true, nil
+
+	case "take_item":
+		id, count, err := itemArgs(args, "take_item")
+		if err != nil {
+			return nil, err
+		}
+		if p.Inventory().CountOf(id) < int64(count) {
+			return false, nil
+		}
+		if err := p.Inventory().DestroyByType(id, int64(count)); err != nil {
+			return false, nil
+		}
+		o.inventoryChanged(c)
+		return true, nil
+
+	case "add_exp":
+		exp, err := argNumber(args, 0, "add_exp")
+		if err != nil {
+			return nil, err
+		}
+		sp, err := argNumber(args, 1, "add_exp")
+		if err != nil {
+			return nil, err
+		}
+		if exp < 0 || sp < 0 {
+			return nil, fmt.Errorf("add_exp: negative value")
+		}
+		p.AddExperience(int64(exp))
+		p.AddSP(int64(sp))
+		o.rt.output().ExpGained(p, int64(exp))
+		return nil, nil
+
+	case "message":
+		o.rt.output().SendMessage(p, ToString(arg(args, 0)))
+		return nil, nil
+
+	case "show_html":
+		html, err := argString(args, 0, "show_html")
+		if err != nil {
+			return nil, err
+		}
+		var npc *model.Npc
+		if n, ok := arg(args, 1).(Npc); ok {
+			npc = n.n
+		}
+		o.rt.output().ShowHTML(p, npc, html)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("player has no method %q", method)
+}
+
+// inventoryChanged notifies the messenger once per invocation, after the script returns.
+func (o Player) inventoryChanged(c *Call) {
+	c.DeferOnce(o.p, func() { o.rt.output().InventoryChanged(c.Ctx, o.p) })
+}
+
+// Npc is an NPC as seen by scripts.
+//
+//	name() object_id() npc_id() level() x() y() z() hp() max_hp() is_dead()
+//	say(text)  despawn()
+type Npc struct {
+	n  *model.Npc
+	rt *Runtime
+}
+
+func (rt *Runtime) npc(n *model.Npc) Value {
+	if n == nil {
+		return nil
+	}
+	return Npc{n: n, rt: rt}
+}
+
+// TypeName implements Object.
+func (Npc) TypeName() string { return "npc" }
+
+// CallMethod implements Object.
+func (o Npc) CallMethod(c *Call, method string, args []Value) (Value, error) {
+	n := o.n
+	switch method {
+	case "name":
+		return n.Name(), nil
+	case "object_id":
+		return float64(n.ObjectID()), nil
+	case "npc_id":
+		return float64(n.TemplateID()), nil
+	case "level":
+		return float64(n.Level()), nil
+	case "x":
+		return float64(n.X()), nil
+	case "y":
+		return float64(n.Y()), nil
+	case "z":
+		return float64(n.Z()), nil
+	case "hp":
+		return float64(n.CurrentHP()), nil
+	case "max_hp":
+		return float64(n.MaxHP()), nil
+	case "is_dead":
+		return n.IsDead(), nil
+
+	case "say":
+		o.rt.output().NpcSay(n, ToString(arg(args, 0)))
+		return nil, nil
+
+	case "despawn":
+		if o.rt.world == nil {
+			return nil, fmt.Errorf("despawn: no world")
+		}
+		o.rt.world.DespawnNpc(n)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("npc has no method %q", method)
+}
+
+func arg(args []Value, i int) Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+func argNumber(args []Value, i int, fn string) (float64, error) {
+	n, ok := arg(args, i).(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s: argument #%d must be a number, got %s", fn, i+1, typeName(arg(args, i)))
+	}
+	return n, nil
+}
+
+func argInt32(args []Value, i int, fn string) (int32, error) {
+	n, err := argNumber(args, i, fn)
+	if err != nil {
+		return 0, err
+	}
+	if n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%s: argument #%d must be an integer", fn, i+1)
+	}
+	return int32(n), nil
+}
+
+func argString(args []Value, i int, fn string) (string, error) {
+	s, ok := arg(args, i).(string)
+	if !ok {
+		return "", fmt.Errorf("%s: argument #%d must be a string, got %s", fn, i+1, typeName(arg(args, i)))
+	}
+	return s, nil
+}
+
+// itemArgs reads (itemID, count) with a positive count.
+func itemArgs(args []Value, fn string) (int32, int32, error) {
+	id, err := argInt32(args, 0, fn)
+	if err != nil {
+		return 0, 0, err
+	}
+	count, err := argInt32(args, 1, fn)
+	if err != nil {
+		return 0, 0, err
+	}
+	if count <= 0 {
+		return 0, 0, fmt.Errorf("%s: count must be positive", fn)
+	}
+	return id, count, nil
+}
@@ -0,0 +1,51 @@
+package script
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Controller is the AI of NPCs that may be scripted: BasicNpcAI plus the on_spawn
+// and on_think hooks of the NPC script. The script is looked up on every tick,
+// so reloaded or newly added scripts apply to already spawned NPCs.
+type Controller struct {
+	*ai.BasicNpcAI
+	rt      *Runtime
+	npc     *model.Npc
+	spawned atomic.Bool
+	stopped atomic.Bool
+}
+
+// NewController creates a scripted AI controller for npc.
+// Matches spawn.ControllerFactory.
+func (rt *Runtime) NewController(npc *model.Npc) ai.Controller {
+	return &Controller{
+		BasicNpcAI: ai.NewBasicNpcAI(npc),
+		rt:         rt,
+		npc:        npc,
+	}
+}
+
+// Stop stops AI controller.
+func (c *Controller) Stop() {
+	c.stopped.Store(true)
+	c.BasicNpcAI.Stop()
+}
+
+// Tick performs AI tick. on_spawn runs on the first tick rather than in Start:
+// Start is called while spawning, possibly from spawn_npc inside a running script.
+func (c *Controller) Tick() {
+	c.BasicNpcAI.Tick()
+	if c.stopped.Load() || c.npc.IsDead() {
+		return
+	}
+
+	ctx := context.Background()
+	if !c.spawned.Swap(true) {
+		c.rt.Spawned(ctx, c.npc)
+	}
+	c.rt.Think(ctx, c.npc)
+}
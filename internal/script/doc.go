@@ -0,0 +1,22 @@
+// Package script is a sandboxed scripting runtime for NPC dialogs, quests and AI.
+//
+// Scripts are written in a small subset of Lua: local variables, functions and
+// closures, if/elseif/else, while, numeric for, break/return, numbers, strings,
+// booleans and nil, arithmetic, comparison, "..", "#", and/or/not, and
+// obj:method(...) calls on game objects. There are no tables, standard library,
+// file or network access; the only way to affect the game is the curated API
+// (see globals, Player and Npc).
+//
+// A script binds itself to NPC templates at load time and defines hooks:
+//
+//	register_npc(30001)
+//
+//	function on_talk(player, npc)
+//	    return "<html><body>Hello, " .. player:name() .. "!</body></html>"
+//	end
+//
+// Every invocation (loading a file or running a hook) is bounded by Limits:
+// a step budget, a wall time deadline and a call depth. A script that exceeds
+// its limits several times in a row is disabled until its file changes, so a
+// runaway script cannot stall the AI tick loop.
+package script
@@ -0,0 +1,545 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxStringLen bounds strings built by scripts so that a loop of concatenations
+// cannot eat the server memory.
+const maxStringLen = 64 * 1024
+
+// checkEvery is how often (in steps) the wall clock and context are checked.
+const checkEvery = 256
+
+var (
+	// ErrLimitExceeded is wrapped by all sandbox limit errors.
+	ErrLimitExceeded = errors.New("script limit exceeded")
+
+	ErrStepLimit     = fmt.Errorf("%w: step limit", ErrLimitExceeded)
+	ErrTimeLimit     = fmt.Errorf("%w: time limit", ErrLimitExceeded)
+	ErrStackOverflow = fmt.Errorf("%w: call depth", ErrLimitExceeded)
+	ErrStringTooLong = fmt.Errorf("%w: string length", ErrLimitExceeded)
+)
+
+// Limits bounds a single script invocation (one hook call or the load of a script).
+type Limits struct {
+	Steps int           // statements, loop iterations and calls
+	Time  time.Duration // wall time
+	Depth int           // nested script function calls
+}
+
+// DefaultLimits returns limits suitable for hooks run from the AI tick loop.
+func DefaultLimits() Limits {
+	return Limits{
+		Steps: 100_000,
+		Time:  50 * time.Millisecond,
+		Depth: 64,
+	}
+}
+
+// lineError attaches a script line to a runtime error.
+type lineError struct {
+	line int
+	err  error
+}
+
+func (e *lineError) Error() string { return fmt.Sprintf("line %d: %v", e.line, e.err) }
+func (e *lineError) Unwrap() error { return e.err }
+
+func atLine(line int, err error) error {
+	var le *lineError
+	if errors.As(err, &le) {
+		return err
+	}
+	return &lineError{line: line, err: err}
+}
+
+type flow int
+
+const (
+	flowNormal flow = iota
+	flowBreak
+	flowReturn
+)
+
+// Call is the state of one sandboxed invocation. Builtins receive it to reach
+// the invocation context.
+type Call struct {
+	Ctx context.Context
+
+	globals  map[string]Value
+	limits   Limits
+	steps    int
+	depth    int
+	deadline time.Time
+	deferred []func()
+	deferKey map[any]bool
+	loading  *Script // script whose top level is running (register_npc)
+}
+
+func newCall(ctx context.Context, globals map[string]Value, limits Limits) *Call {
+	return &Call{
+		Ctx:      ctx,
+		globals:  globals,
+		limits:   limits,
+		deadline: time.Now().Add(limits.Time),
+	}
+}
+
+// step charges one unit of work against the limits.
+func (c *Call) step() error {
+	c.steps++
+	if c.limits.Steps > 0 && c.steps > c.limits.Steps {
+		return ErrStepLimit
+	}
+	if c.steps%checkEvery == 0 {
+		if c.limits.Time > 0 && time.Now().After(c.deadline) {
+			return ErrTimeLimit
+		}
+		if err := c.Ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Defer schedules fn to run after the invocation finishes, outside of its limits
+// (e.g. persisting items given by a script).
+func (c *Call) Defer(fn func()) {
+	c.deferred = append(c.deferred, fn)
+}
+
+// DeferOnce is Defer that schedules fn only once per key.
+func (c *Call) DeferOnce(key any, fn func()) {
+	if c.deferKey[key] {
+		return
+	}
+	if c.deferKey == nil {
+		c.deferKey = make(map[any]bool)
+	}
+	c.deferKey[key] = true
+	c.Defer(fn)
+}
+
+// finish runs deferred functions.
+func (c *Call) finish() {
+	for _, fn := range c.deferred {
+		fn()
+	}
+	c.deferred = nil
+}
+
+// Steps returns the number of steps spent so far.
+func (c *Call) Steps() int {
+	return c.steps
+}
+
+func (c *Call) execBlock(body []stmt, env *scope) (flow, Value, error) {
+	for _, s := range body {
+		if err := c.step(); err != nil {
+			return flowNormal, nil, atLine(s.stmtLine(), err)
+		}
+		fl, v, err := c.exec(s, env)
+		if err != nil {
+			return flowNormal, nil, atLine(s.stmtLine(), err)
+		}
+		if fl != flowNormal {
+			return fl, v, nil
+		}
+	}
+	return flowNormal, nil, nil
+}
+
+func (c *Call) exec(s stmt, env *scope) (flow, Value, error) {
+	switch s := s.(type) {
+	case *localStmt:
+		values := make([]Value, len(s.names))
+		for i, x := range s.exprs {
+			v, err := c.eval(x, env)
+			if err != nil {
+				return flowNormal, nil, err
+			}
+			if i < len(values) {
+				values[i] = v
+			}
+		}
+		for i, name := range s.names {
+			env.vars[name] = values[i]
+		}
+
+	case *assignStmt:
+		v, err := c.eval(s.x, env)
+		if err != nil {
+			return flowNormal, nil, err
+		}
+		if owner := env.lookup(s.name); owner != nil {
+			owner.vars[s.name] = v
+		} else {
+			c.globals[s.name] = v
+		}
+
+	case *exprStmt:
+		if _, err := c.eval(s.x, env); err != nil {
+			return flowNormal, nil, err
+		}
+
+	case *ifStmt:
+		for i, cond := range s.conds {
+			v, err := c.eval(cond, env)
+			if err != nil {
+				return flowNormal, nil, err
+			}
+			if truthy(v) {
+				return c.execBlock(s.blocks[i], newScope(env))
+			}
+		}
+		if s.els != nil {
+			return c.execBlock(s.els, newScope(env))
+		}
+
+	case *whileStmt:
+		for {
+			v, err := c.eval(s.cond, env)
+			if err != nil {
+				return flowNormal, nil, err
+			}
+			if !truthy(v) {
+				break
+			}
+			fl, ret, err := c.execBlock(s.body, newScope(env))
+			if err != nil || fl == flowReturn {
+				return fl, ret, err
+			}
+			if fl == flowBreak {
+				break
+			}
+			if err := c.step(); err != nil {
+				return flowNormal, nil, err
+			}
+		}
+
+	case *forStmt:
+		return c.execFor(s, env)
+
+	case *doStmt:
+		return c.execBlock(s.body, newScope(env))
+
+	case *breakStmt:
+		return flowBreak, nil, nil
+
+	case *returnStmt:
+		if s.x == nil {
+			return flowReturn, nil, nil
+		}
+		v, err := c.eval(s.x, env)
+		if err != nil {
+			return flowNormal, nil, err
+		}
+		return flowReturn, v, nil
+
+	default:
+		return flowNormal, nil, fmt.Errorf("unknown statement %T", s)
+	}
+	return flowNormal, nil, nil
+}
+
+func (c *Call) execFor(s *forStmt, env *scope) (flow, Value, error) {
+	start, err := c.evalNumber(s.start, env, "'for' initial value")
+	if err != nil {
+		return flowNormal, nil, err
+	}
+	stop, err := c.evalNumber(s.stop, env, "'for' limit")
+	if err != nil {
+		return flowNormal, nil, err
+	}
+	step := 1.0
+	if s.step != nil {
+		if step, err = c.evalNumber(s.step, env, "'for' step"); err != nil {
+			return flowNormal, nil, err
+		}
+		if step == 0 {
+			return flowNormal, nil, errors.New("'for' step is zero")
+		}
+	}
+
+	for i := start; (step > 0 && i <= stop) || (step < 0 && i >= stop); i += step {
+		loop := newScope(env)
+		loop.vars[s.name] = i
+		fl, ret, err := c.execBlock(s.body, loop)
+		if err != nil || fl == flowReturn {
+			return fl, ret, err
+		}
+		if fl == flowBreak {
+			break
+		}
+		if err := c.step(); err != nil {
+			return flowNormal, nil, err
+		}
+	}
+	return flowNormal, nil, nil
+}
+
+func (c *Call) evalNumber(x expr, env *scope, what string) (float64, error) {
+	v, err := c.eval(x, env)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s must be a number", what)
+	}
+	return n, nil
+}
+
+func (c *Call) eval(x expr, env *scope) (Value, error) {
+	switch x := x.(type) {
+	case *constExpr:
+		return x.value, nil
+
+	case *nameExpr:
+		if owner := env.lookup(x.name); owner != nil {
+			return owner.vars[x.name], nil
+		}
+		return c.globals[x.name], nil
+
+	case *funcExpr:
+		return &closure{fn: x, env: env}, nil
+
+	case *unaryExpr:
+		v, err := c.eval(x.x, env)
+		if err != nil {
+			return nil, err
+		}
+		return unary(x.op, v)
+
+	case *binaryExpr:
+		l, err := c.eval(x.l, env)
+		if err != nil {
+			return nil, err
+		}
+		// Short-circuit operators return one of the operands.
+		switch x.op {
+		case "and":
+			if !truthy(l) {
+				return l, nil
+			}
+			return c.eval(x.r, env)
+		case "or":
+			if truthy(l) {
+				return l, nil
+			}
+			return c.eval(x.r, env)
+		}
+		r, err := c.eval(x.r, env)
+		if err != nil {
+			return nil, err
+		}
+		return binary(x.op, l, r)
+
+	case *callExpr:
+		fn, err := c.eval(x.fn, env)
+		if err != nil {
+			return nil, err
+		}
+		args, err := c.evalArgs(x.args, env)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			if name, ok := x.fn.(*nameExpr); ok {
+				return nil, fmt.Errorf("attempt to call undefined function %q", name.name)
+			}
+		}
+		return c.Invoke(fn, args...)
+
+	case *methodExpr:
+		recv, err := c.eval(x.recv, env)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := recv.(Object)
+		if !ok {
+			return nil, fmt.Errorf("attempt to call method %q on a %s value", x.name, typeName(recv))
+		}
+		args, err := c.evalArgs(x.args, env)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.step(); err != nil {
+			return nil, err
+		}
+		return obj.CallMethod(c, x.name, args)
+
+	default:
+		return nil, fmt.Errorf("unknown expression %T", x)
+	}
+}
+
+func (c *Call) evalArgs(exprs []expr, env *scope) ([]Value, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	args := make([]Value, len(exprs))
+	for i, x := range exprs {
+		v, err := c.eval(x, env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// Invoke calls a script function or builtin with args.
+func (c *Call) Invoke(fn Value, args ...Value) (Value, error) {
+	if err := c.step(); err != nil {
+		return nil, err
+	}
+	switch fn := fn.(type) {
+	case *closure:
+		if c.limits.Depth > 0 && c.depth >= c.limits.Depth {
+			return nil, ErrStackOverflow
+		}
+		c.depth++
+		defer func() { c.depth-- }()
+
+		env := newScope(fn.env)
+		for i, name := range fn.fn.params {
+			var v Value
+			if i < len(args) {
+				v = args[i]
+			}
+			env.vars[name] = v
+		}
+		_, ret, err := c.execBlock(fn.fn.body, env)
+		return ret, err
+	case *Builtin:
+		return fn.Fn(c, args)
+	default:
+		return nil, fmt.Errorf("attempt to call a %s value", typeName(fn))
+	}
+}
+
+func unary(op string, v Value) (Value, error) {
+	switch op {
+	case "not":
+		return !truthy(v), nil
+	case "-":
+		n, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("attempt to negate a %s value", typeName(v))
+		}
+		return -n, nil
+	case "#":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("attempt to get length of a %s value", typeName(v))
+		}
+		return float64(len(s)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func binary(op string, l, r Value) (Value, error) {
+	switch op {
+	case "==":
+		return l == r, nil
+	case "~=":
+		return l != r, nil
+	case "..":
+		if !concatenable(l) || !concatenable(r) {
+			return nil, fmt.Errorf("attempt to concatenate a %s value", typeName(pickBad(l, r, concatenable)))
+		}
+		ls, rs := ToString(l), ToString(r)
+		if len(ls)+len(rs) > maxStringLen {
+			return nil, ErrStringTooLong
+		}
+		return ls + rs, nil
+	case "<", "<=", ">", ">=":
+		return compare(op, l, r)
+	}
+
+	a, aok := l.(float64)
+	b, bok := r.(float64)
+	if !aok || !bok {
+		return nil, fmt.Errorf("attempt to perform arithmetic on a %s value", typeName(pickBad(l, r, isNumber)))
+	}
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		return a / b, nil
+	case "%":
+		return a - math.Floor(a/b)*b, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func compare(op string, l, r Value) (Value, error) {
+	var cmp int
+	switch a := l.(type) {
+	case float64:
+		b, ok := r.(float64)
+		if !ok {
+			return nil, fmt.Errorf("attempt to compare number with %s", typeName(r))
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	case string:
+		b, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("attempt to compare string with %s", typeName(r))
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	default:
+		return nil, fmt.Errorf("attempt to compare two %s values", typeName(l))
+	}
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func isNumber(v Value) bool {
+	_, ok := v.(float64)
+	return ok
+}
+
+func concatenable(v Value) bool {
+	switch v.(type) {
+	case string, float64:
+		return true
+	}
+	return false
+}
+
+// pickBad returns the operand that fails ok (for error messages).
+func pickBad(l, r Value, ok func(Value) bool) Value {
+	if !ok(l) {
+		return l
+	}
+	return r
+}
@@ -0,0 +1,145 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// run executes src as a script top level and returns the value of global "result".
+func run(t *testing.T, src string, limits Limits) (Value, error) {
+	t.Helper()
+	body, err := parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	globals := make(map[string]Value)
+	c := newCall(context.Background(), globals, limits)
+	_, _, err = c.execBlock(body, newScope(nil))
+	return globals["result"], err
+}
+
+func TestInterp_Language(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Value
+	}{
+		{"arithmetic", "result = 1 + 2 * 3 - 4 / 2", 5.0},
+		{"modulo", "result = -7 % 3", 2.0},
+		{"concat", `result = "lvl " .. 20 .. "!"`, "lvl 20!"},
+		{"length", `result = #"wolf"`, 4.0},
+		{"and/or", "result = nil or (false and 1) or 7", 7.0},
+		{"not", "result = not nil == true", true},
+		{"comparison", `result = 1 < 2 and "a" <= "b" and 3 ~= 4`, true},
+		{"if/elseif", `
+			local x = 15
+			if x < 10 then result = "low"
+			elseif x < 20 then result = "mid"
+			else result = "high" end`, "mid"},
+		{"while/break", `
+			local i = 0
+			while true do
+				i = i + 1
+				if i == 5 then break end
+			end
+			result = i`, 5.0},
+		{"numeric for", `
+			local sum = 0
+			for i = 10, 1, -3 do sum = sum + i end
+			result = sum`, 22.0},
+		{"recursion", `
+			local function fact(n)
+				if n <= 1 then return 1 end
+				return n * fact(n - 1)
+			end
+			result = fact(10)`, 3628800.0},
+		{"closure", `
+			local function counter()
+				local n = 0
+				return function() n = n + 1; return n end
+			end
+			local c = counter()
+			c(); c()
+			result = c()`, 3.0},
+		{"scopes", `
+			local x = 1
+			do local x = 2 end
+			result = x`, 1.0},
+		{"long comment", "--[[ ignored\n result = 1 ]] result = 2 -- trailing", 2.0},
+		{"hex and escapes", `result = 0x10 .. "\t"`, "16\t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(t, tt.src, DefaultLimits())
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("result = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterp_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"arithmetic on nil", "local x\nresult = x + 1", "line 2: attempt to perform arithmetic on a nil value"},
+		{"undefined function", "missing()", `attempt to call undefined function "missing"`},
+		{"compare mixed", `result = 1 < "2"`, "attempt to compare number with string"},
+		{"method on number", "local x = 1\nx:name()", `attempt to call method "name" on a number value`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.src, DefaultLimits())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	for _, src := range []string{"if x then", "local = 1", "x + 1", `result = "open`, "return 1 result = 2"} {
+		if _, err := parse(src); err == nil {
+			t.Errorf("parse(%q) should fail", src)
+		}
+	}
+}
+
+func TestInterp_Limits(t *testing.T) {
+	// Step budget stops an endless loop.
+	_, err := run(t, "while true do end", Limits{Steps: 10_000})
+	if !errors.Is(err, ErrStepLimit) {
+		t.Errorf("endless loop: err = %v, want ErrStepLimit", err)
+	}
+
+	// Wall time stops a loop even without a step budget.
+	start := time.Now()
+	_, err = run(t, "while true do end", Limits{Time: 20 * time.Millisecond})
+	if !errors.Is(err, ErrTimeLimit) {
+		t.Errorf("slow loop: err = %v, want ErrTimeLimit", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("time limit enforced after %v", elapsed)
+	}
+
+	// Unbounded recursion hits the depth limit instead of the Go stack.
+	_, err = run(t, "local function f() return f() end\nf()", Limits{Depth: 50})
+	if !errors.Is(err, ErrStackOverflow) {
+		t.Errorf("recursion: err = %v, want ErrStackOverflow", err)
+	}
+
+	// Strings cannot grow without bound.
+	_, err = run(t, `local s = "x" while true do s = s .. s end`, DefaultLimits())
+	if !errors.Is(err, ErrStringTooLong) {
+		t.Errorf("string growth: err = %v, want ErrStringTooLong", err)
+	}
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Error("limit errors must wrap ErrLimitExceeded")
+	}
+}
@@ -0,0 +1,169 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokNumber
+	tokString
+	tokKeyword
+	tokOp
+)
+
+// token is a lexical token with the line it starts on.
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	line int
+}
+
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true,
+	"end": true, "false": true, "for": true, "function": true, "if": true,
+	"local": true, "nil": true, "not": true, "or": true, "return": true,
+	"then": true, "true": true, "while": true,
+}
+
+// Operators ordered so that longer ones match first.
+var operators = []string{
+	"..", "==", "~=", "<=", ">=",
+	"+", "-", "*", "/", "%", "<", ">", "=", "(", ")", ",", ":", ";", "#",
+}
+
+// lex splits source into tokens. The last token is always tokEOF.
+func lex(src string) ([]token, error) {
+	var (
+		toks []token
+		line = 1
+		i    = 0
+	)
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			if strings.HasPrefix(src[i:], "--[[") {
+				end := strings.Index(src[i:], "]]")
+				if end < 0 {
+					return nil, fmt.Errorf("line %d: unfinished long comment", line)
+				}
+				line += strings.Count(src[i:i+end], "\n")
+				i += end + 2
+				continue
+			}
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case isLetter(c):
+			start := i
+			for i < len(src) && (isLetter(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			word := src[start:i]
+			kind := tokName
+			if keywords[word] {
+				kind = tokKeyword
+			}
+			toks = append(toks, token{kind: kind, text: word, line: line})
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.' || src[i] == 'x' || isHexLetter(src[i])) {
+				i++
+			}
+			text := src[start:i]
+			var (
+				n   float64
+				err error
+			)
+			if strings.HasPrefix(text, "0x") {
+				var u uint64
+				u, err = strconv.ParseUint(text[2:], 16, 53)
+				n = float64(u)
+			} else {
+				n, err = strconv.ParseFloat(text, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: malformed number %q", line, text)
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, num: n, line: line})
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src[i:], line)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{kind: tokString, text: s, line: line})
+			i += n
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("line %d: unexpected symbol %q", line, c)
+			}
+			toks = append(toks, token{kind: tokOp, text: op, line: line})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "<eof>", line: line}), nil
+}
+
+// lexString reads a quoted string literal and returns its value and length in src.
+func lexString(src string, line int) (string, int, error) {
+	quote := src[0]
+	var sb strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		switch c {
+		case quote:
+			return sb.String(), i + 1, nil
+		case '\n':
+			return "", 0, fmt.Errorf("line %d: unfinished string", line)
+		case '\\':
+			i++
+			if i == len(src) {
+				return "", 0, fmt.Errorf("line %d: unfinished string", line)
+			}
+			switch src[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case '\\', '"', '\'':
+				sb.WriteByte(src[i])
+			default:
+				return "", 0, fmt.Errorf("line %d: invalid escape \\%c", line, src[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("line %d: unfinished string", line)
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexLetter(c byte) bool {
+	return (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
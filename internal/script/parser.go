@@ -0,0 +1,596 @@
+package script
+
+import (
+	"fmt"
+)
+
+// Expressions.
+type (
+	expr interface{ exprLine() int }
+
+	constExpr struct {
+		line  int
+		value Value
+	}
+	nameExpr struct {
+		line int
+		name string
+	}
+	unaryExpr struct {
+		line int
+		op   string
+		x    expr
+	}
+	binaryExpr struct {
+		line int
+		op   string
+		l, r expr
+	}
+	callExpr struct {
+		line int
+		fn   expr
+		args []expr
+	}
+	methodExpr struct {
+		line int
+		recv expr
+		name string
+		args []expr
+	}
+	funcExpr struct {
+		line   int
+		name   string
+		params []string
+		body   []stmt
+	}
+)
+
+func (e *constExpr) exprLine() int  { return e.line }
+func (e *nameExpr) exprLine() int   { return e.line }
+func (e *unaryExpr) exprLine() int  { return e.line }
+func (e *binaryExpr) exprLine() int { return e.line }
+func (e *callExpr) exprLine() int   { return e.line }
+func (e *methodExpr) exprLine() int { return e.line }
+func (e *funcExpr) exprLine() int   { return e.line }
+
+// Statements.
+type (
+	stmt interface{ stmtLine() int }
+
+	localStmt struct {
+		line  int
+		names []string
+		exprs []expr
+	}
+	assignStmt struct {
+		line int
+		name string
+		x    expr
+	}
+	exprStmt struct {
+		line int
+		x    expr // call or method call
+	}
+	ifStmt struct {
+		line   int
+		conds  []expr
+		blocks [][]stmt
+		els    []stmt
+	}
+	whileStmt struct {
+		line int
+		cond expr
+		body []stmt
+	}
+	forStmt struct {
+		line              int
+		name              string
+		start, stop, step expr
+		body              []stmt
+	}
+	doStmt struct {
+		line int
+		body []stmt
+	}
+	breakStmt struct {
+		line int
+	}
+	returnStmt struct {
+		line int
+		x    expr // nil for a bare return
+	}
+)
+
+func (s *localStmt) stmtLine() int  { return s.line }
+func (s *assignStmt) stmtLine() int { return s.line }
+func (s *exprStmt) stmtLine() int   { return s.line }
+func (s *ifStmt) stmtLine() int     { return s.line }
+func (s *whileStmt) stmtLine() int  { return s.line }
+func (s *forStmt) stmtLine() int    { return s.line }
+func (s *doStmt) stmtLine() int     { return s.line }
+func (s *breakStmt) stmtLine() int  { return s.line }
+func (s *returnStmt) stmtLine() int { return s.line }
+
+// Binary operator precedence (higher binds tighter).
+var binaryPriority = map[string]int{
+	"or":  1,
+	"and": 2,
+	"<":   3, ">": 3, "<=": 3, ">=": 3, "~=": 3, "==": 3,
+	"..": 4,
+	"+":  5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+const unaryPriority = 7
+
+// parser is a recursive descent parser over the token stream.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// parse compiles source into the top-level statement list.
+func parse(src string) ([]stmt, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return body, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.toks[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// is reports whether the next token is the keyword or operator text.
+func (p *parser) is(text string) bool {
+	tok := p.peek()
+	return (tok.kind == tokKeyword || tok.kind == tokOp) && tok.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return p.errorf(tok, "%q expected near %q", text, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokName {
+		return "", p.errorf(tok, "name expected near %q", tok.text)
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", tok.line, fmt.Sprintf(format, args...))
+}
+
+// blockEnd reports whether the next token closes the current block.
+func (p *parser) blockEnd() bool {
+	tok := p.peek()
+	if tok.kind == tokEOF {
+		return true
+	}
+	return tok.kind == tokKeyword && (tok.text == "end" || tok.text == "else" || tok.text == "elseif")
+}
+
+func (p *parser) block() ([]stmt, error) {
+	var body []stmt
+	for !p.blockEnd() {
+		if p.accept(";") {
+			continue
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, s)
+		if _, ok := s.(*returnStmt); ok {
+			p.accept(";")
+			if !p.blockEnd() {
+				tok := p.peek()
+				return nil, p.errorf(tok, "'end' expected after return near %q", tok.text)
+			}
+		}
+	}
+	return body, nil
+}
+
+func (p *parser) statement() (stmt, error) {
+	tok := p.peek()
+	line := tok.line
+	if tok.kind == tokKeyword {
+		switch tok.text {
+		case "local":
+			p.next()
+			if p.accept("function") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				fn, err := p.funcBody(line, name)
+				if err != nil {
+					return nil, err
+				}
+				return &localStmt{line: line, names: []string{name}, exprs: []expr{fn}}, nil
+			}
+			return p.localStatement(line)
+		case "function":
+			p.next()
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			fn, err := p.funcBody(line, name)
+			if err != nil {
+				return nil, err
+			}
+			return &assignStmt{line: line, name: name, x: fn}, nil
+		case "if":
+			p.next()
+			return p.ifStatement(line)
+		case "while":
+			p.next()
+			cond, err := p.expression(0)
+			if err != nil {
+				return nil, err
+			}
+			body, err := p.doBlock()
+			if err != nil {
+				return nil, err
+			}
+			return &whileStmt{line: line, cond: cond, body: body}, nil
+		case "for":
+			p.next()
+			return p.forStatement(line)
+		case "do":
+			p.next()
+			body, err := p.block()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("end"); err != nil {
+				return nil, err
+			}
+			return &doStmt{line: line, body: body}, nil
+		case "break":
+			p.next()
+			return &breakStmt{line: line}, nil
+		case "return":
+			p.next()
+			ret := &returnStmt{line: line}
+			if !p.blockEnd() && !p.is(";") {
+				x, err := p.expression(0)
+				if err != nil {
+					return nil, err
+				}
+				ret.x = x
+			}
+			return ret, nil
+		}
+	}
+
+	x, err := p.suffixed()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("=") {
+		target, ok := x.(*nameExpr)
+		if !ok {
+			return nil, p.errorf(tok, "cannot assign to expression")
+		}
+		val, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		return &assignStmt{line: line, name: target.name, x: val}, nil
+	}
+	switch x.(type) {
+	case *callExpr, *methodExpr:
+		return &exprStmt{line: line, x: x}, nil
+	}
+	return nil, p.errorf(tok, "syntax error near %q", p.peek().text)
+}
+
+func (p *parser) localStatement(line int) (stmt, error) {
+	s := &localStmt{line: line}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		s.names = append(s.names, name)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("=") {
+		exprs, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		s.exprs = exprs
+	}
+	return s, nil
+}
+
+func (p *parser) ifStatement(line int) (stmt, error) {
+	s := &ifStmt{line: line}
+	for {
+		cond, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.conds = append(s.conds, cond)
+		s.blocks = append(s.blocks, body)
+		if !p.accept("elseif") {
+			break
+		}
+	}
+	if p.accept("else") {
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		s.els = body
+	}
+	if err := p.expect("end"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) forStatement(line int) (stmt, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	s := &forStmt{line: line, name: name}
+	if s.start, err = p.expression(0); err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	if s.stop, err = p.expression(0); err != nil {
+		return nil, err
+	}
+	if p.accept(",") {
+		if s.step, err = p.expression(0); err != nil {
+			return nil, err
+		}
+	}
+	if s.body, err = p.doBlock(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// doBlock parses "do block end".
+func (p *parser) doBlock() ([]stmt, error) {
+	if err := p.expect("do"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("end"); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// funcBody parses "(params) block end".
+func (p *parser) funcBody(line int, name string) (*funcExpr, error) {
+	fn := &funcExpr{line: line, name: name}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if !p.accept(")") {
+		for {
+			param, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			fn.params = append(fn.params, param)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("end"); err != nil {
+		return nil, err
+	}
+	fn.body = body
+	return fn, nil
+}
+
+func (p *parser) exprList() ([]expr, error) {
+	var list []expr
+	for {
+		x, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, x)
+		if !p.accept(",") {
+			return list, nil
+		}
+	}
+}
+
+// expression parses a binary expression whose operators bind tighter than limit.
+func (p *parser) expression(limit int) (expr, error) {
+	var (
+		left expr
+		err  error
+	)
+	tok := p.peek()
+	if (tok.kind == tokKeyword && tok.text == "not") || (tok.kind == tokOp && (tok.text == "-" || tok.text == "#")) {
+		p.next()
+		x, err := p.expression(unaryPriority)
+		if err != nil {
+			return nil, err
+		}
+		left = &unaryExpr{line: tok.line, op: tok.text, x: x}
+	} else if left, err = p.simple(); err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op.kind != tokOp && op.kind != tokKeyword {
+			return left, nil
+		}
+		prio, ok := binaryPriority[op.text]
+		if !ok || prio <= limit {
+			return left, nil
+		}
+		p.next()
+		// ".." is right associative.
+		next := prio
+		if op.text == ".." {
+			next = prio - 1
+		}
+		right, err := p.expression(next)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{line: op.line, op: op.text, l: left, r: right}
+	}
+}
+
+func (p *parser) simple() (expr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		return &constExpr{line: tok.line, value: tok.num}, nil
+	case tokString:
+		p.next()
+		return &constExpr{line: tok.line, value: tok.text}, nil
+	case tokKeyword:
+		switch tok.text {
+		case "nil":
+			p.next()
+			return &constExpr{line: tok.line}, nil
+		case "true", "false":
+			p.next()
+			return &constExpr{line: tok.line, value: tok.text == "true"}, nil
+		case "function":
+			p.next()
+			return p.funcBody(tok.line, "")
+		}
+	}
+	return p.suffixed()
+}
+
+// suffixed parses a primary expression followed by calls and method calls.
+func (p *parser) suffixed() (expr, error) {
+	tok := p.peek()
+	var x expr
+	switch {
+	case tok.kind == tokName:
+		p.next()
+		x = &nameExpr{line: tok.line, name: tok.text}
+	case p.accept("("):
+		inner, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		x = inner
+	default:
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	for {
+		line := p.peek().line
+		switch {
+		case p.accept("("):
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			x = &callExpr{line: line, fn: x, args: args}
+		case p.accept(":"):
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			x = &methodExpr{line: line, recv: x, name: name, args: args}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// callArgs parses call arguments after the opening parenthesis.
+func (p *parser) callArgs() ([]expr, error) {
+	if p.accept(")") {
+		return nil, nil
+	}
+	args, err := p.exprList()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
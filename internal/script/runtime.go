@@ -0,0 +1,386 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Extension is the file extension of scripts in the datapack directory.
+const Extension = ".lua"
+
+// maxViolations is the number of limit violations in a row after which a script
+// is disabled until its file changes.
+const maxViolations = 3
+
+// Hook names looked up in script globals.
+const (
+	HookTalk  = "on_talk"  // on_talk(player, npc) → html
+	HookEvent = "on_event" // on_event(player, npc, event) → html
+	HookKill  = "on_kill"  // on_kill(player, npc)
+	HookSpawn = "on_spawn" // on_spawn(npc)
+	HookThink = "on_think" // on_think(npc), every AI tick
+)
+
+// ErrNotLoading is returned by builtins that may only be called while a script loads.
+var ErrNotLoading = errors.New("only allowed at script load")
+
+// World is the part of the game world exposed to scripts (implemented by spawn.Manager).
+type World interface {
+	Npc(objectID uint32) (*model.Npc, bool)
+	SpawnNpc(ctx context.Context, templateID int32, loc model.Location) (*model.Npc, error)
+	DespawnNpc(npc *model.Npc)
+}
+
+// Messenger delivers script output to game clients (implemented by the game handler).
+type Messenger interface {
+	NpcSay(npc *model.Npc, text string)
+	SendMessage(player *model.Player, text string)
+	ShowHTML(player *model.Player, npc *model.Npc, html string)
+	InventoryChanged(ctx context.Context, player *model.Player)
+	ExpGained(player *model.Player, exp int64)
+}
+
+// Script is a loaded script file. Calls into one script are serialized.
+type Script struct {
+	name    string
+	modTime time.Time
+	npcs    []int32
+
+	mu         sync.Mutex
+	globals    map[string]Value
+	violations int
+	disabled   bool
+}
+
+// Name returns the script path relative to the datapack directory.
+func (s *Script) Name() string {
+	return s.name
+}
+
+// Npcs returns NPC template IDs bound with register_npc.
+func (s *Script) Npcs() []int32 {
+	return s.npcs
+}
+
+// Disabled reports whether the script was disabled for exceeding its limits.
+func (s *Script) Disabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.disabled
+}
+
+// Runtime loads scripts from a datapack directory and dispatches game events to them.
+type Runtime struct {
+	dir    string
+	limits Limits
+	world  World
+
+	mu        sync.RWMutex
+	scripts   map[string]*Script // name → script
+	byNpc     map[int32]*Script  // NPC template ID → script
+	messenger Messenger
+}
+
+// NewRuntime creates a runtime for scripts in dir. world may be nil (spawn builtins fail).
+func NewRuntime(dir string, world World, limits Limits) *Runtime {
+	return &Runtime{
+		dir:       dir,
+		limits:    limits,
+		world:     world,
+		scripts:   make(map[string]*Script),
+		byNpc:     make(map[int32]*Script),
+		messenger: nopMessenger{},
+	}
+}
+
+// SetMessenger sets where chat, dialogs and inventory changes of scripts go.
+func (rt *Runtime) SetMessenger(m Messenger) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if m == nil {
+		m = nopMessenger{}
+	}
+	rt.messenger = m
+}
+
+func (rt *Runtime) output() Messenger {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return rt.messenger
+}
+
+// Count returns the number of loaded scripts.
+func (rt *Runtime) Count() int {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return len(rt.scripts)
+}
+
+// Script returns a loaded script by name (path relative to the datapack directory).
+func (rt *Runtime) Script(name string) (*Script, bool) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	s, ok := rt.scripts[name]
+	return s, ok
+}
+
+// ScriptFor returns the script bound to an NPC template.
+func (rt *Runtime) ScriptFor(templateID int32) (*Script, bool) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	s, ok := rt.byNpc[templateID]
+	return s, ok
+}
+
+// Load (re)loads changed scripts, adds new ones and drops deleted ones.
+// A script that fails to compile or load is logged and its previous version kept.
+// Returns the number of scripts (re)loaded.
+func (rt *Runtime) Load(ctx context.Context) (int, error) {
+	files, err := rt.scan()
+	if err != nil {
+		return 0, err
+	}
+
+	rt.mu.RLock()
+	current := make(map[string]*Script, len(rt.scripts))
+	for name, s := range rt.scripts {
+		current[name] = s
+	}
+	rt.mu.RUnlock()
+
+	loaded := 0
+	next := make(map[string]*Script, len(files))
+	for name, modTime := range files {
+		if old, ok := current[name]; ok && old.modTime.Equal(modTime) {
+			next[name] = old
+			continue
+		}
+		s, err := rt.compile(ctx, name, modTime)
+		if err != nil {
+			slog.Error("failed to load script", "script", name, "error", err)
+			if old, ok := current[name]; ok {
+				// Keep the old version but do not retry until the file changes again.
+				old.modTime = modTime
+				next[name] = old
+			}
+			continue
+		}
+		next[name] = s
+		loaded++
+	}
+	for name := range current {
+		if _, ok := next[name]; !ok {
+			slog.Info("script removed", "script", name)
+		}
+	}
+
+	byNpc := make(map[int32]*Script)
+	names := make([]string, 0, len(next))
+	for name := range next {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := next[name]
+		for _, id := range s.npcs {
+			if prev, ok := byNpc[id]; ok {
+				slog.Warn("NPC bound by several scripts", "npc", id, "script", name, "overrides", prev.name)
+			}
+			byNpc[id] = s
+		}
+	}
+
+	rt.mu.Lock()
+	rt.scripts = next
+	rt.byNpc = byNpc
+	rt.mu.Unlock()
+
+	if loaded > 0 {
+		slog.Info("scripts loaded", "loaded", loaded, "total", len(next))
+	}
+	return loaded, nil
+}
+
+// Watch reloads scripts whenever their files change (polling every interval).
+// Blocks until ctx is canceled.
+func (rt *Runtime) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := rt.Load(ctx); err != nil {
+				slog.Error("failed to reload scripts", "dir", rt.dir, "error", err)
+			}
+		}
+	}
+}
+
+// scan lists script files with their modification time.
+func (rt *Runtime) scan() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+	err := filepath.WalkDir(rt.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), Extension) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(rt.dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.ModTime()
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning scripts in %s: %w", rt.dir, err)
+	}
+	return files, nil
+}
+
+// compile parses a script file and runs its top level (where register_npc is allowed).
+func (rt *Runtime) compile(ctx context.Context, name string, modTime time.Time) (*Script, error) {
+	src, err := os.ReadFile(filepath.Join(rt.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+	return rt.compileSource(ctx, name, string(src), modTime)
+}
+
+func (rt *Runtime) compileSource(ctx context.Context, name, src string, modTime time.Time) (*Script, error) {
+	body, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Script{name: name, modTime: modTime}
+	s.globals = rt.globals(s)
+
+	c := newCall(ctx, s.globals, rt.limits)
+	c.loading = s
+	_, _, err = c.execBlock(body, newScope(nil))
+	c.loading = nil
+	c.finish()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// call runs a hook of s. Returns handled=false if the script does not define it,
+// is disabled or failed.
+func (rt *Runtime) call(ctx context.Context, s *Script, hook string, args ...Value) (Value, bool) {
+	s.mu.Lock()
+	if s.disabled {
+		s.mu.Unlock()
+		return nil, false
+	}
+	fn, ok := s.globals[hook]
+	if !ok || fn == nil {
+		s.mu.Unlock()
+		return nil, false
+	}
+
+	c := newCall(ctx, s.globals, rt.limits)
+	ret, err := c.Invoke(fn, args...)
+	if err != nil {
+		if errors.Is(err, ErrLimitExceeded) {
+			s.violations++
+			if s.violations >= maxViolations {
+				s.disabled = true
+			}
+		}
+	} else {
+		s.violations = 0
+	}
+	disabled := s.disabled
+	s.mu.Unlock()
+
+	// Deferred work (DB writes) runs outside the script lock and limits.
+	c.finish()
+
+	if err != nil {
+		slog.Error("script hook failed", "script", s.name, "hook", hook, "steps", c.Steps(), "error", err)
+		if disabled {
+			slog.Error("script disabled after repeated limit violations", "script", s.name)
+		}
+		return nil, false
+	}
+	return ret, true
+}
+
+// dialog runs a hook that returns NPC dialog HTML.
+func (rt *Runtime) dialog(ctx context.Context, npc *model.Npc, hook string, args ...Value) (string, bool) {
+	s, ok := rt.ScriptFor(npc.TemplateID())
+	if !ok {
+		return "", false
+	}
+	ret, ok := rt.call(ctx, s, hook, args...)
+	if !ok {
+		return "", false
+	}
+	html, ok := ret.(string)
+	return html, ok && html != ""
+}
+
+// Talk runs on_talk of the NPC script. handled is false if there is no script dialog
+// and the default NPC behavior should be used.
+func (rt *Runtime) Talk(ctx context.Context, player *model.Player, npc *model.Npc) (html string, handled bool) {
+	return rt.dialog(ctx, npc, HookTalk, rt.player(player), rt.npc(npc))
+}
+
+// Event runs on_event of the NPC script (bypass "npc_<objectID>_Script <event>").
+func (rt *Runtime) Event(ctx context.Context, player *model.Player, npc *model.Npc, event string) (html string, handled bool) {
+	return rt.dialog(ctx, npc, HookEvent, rt.player(player), rt.npc(npc), event)
+}
+
+// Kill runs on_kill of the script of a killed NPC.
+func (rt *Runtime) Kill(ctx context.Context, killer *model.Player, npc *model.Npc) {
+	if s, ok := rt.ScriptFor(npc.TemplateID()); ok {
+		rt.call(ctx, s, HookKill, rt.player(killer), rt.npc(npc))
+	}
+}
+
+// Spawned runs on_spawn of the NPC script.
+func (rt *Runtime) Spawned(ctx context.Context, npc *model.Npc) {
+	if s, ok := rt.ScriptFor(npc.TemplateID()); ok {
+		rt.call(ctx, s, HookSpawn, rt.npc(npc))
+	}
+}
+
+// Think runs on_think of the NPC script.
+func (rt *Runtime) Think(ctx context.Context, npc *model.Npc) {
+	if s, ok := rt.ScriptFor(npc.TemplateID()); ok {
+		rt.call(ctx, s, HookThink, rt.npc(npc))
+	}
+}
+
+type nopMessenger struct{}
+
+func (nopMessenger) NpcSay(*model.Npc, string)                       {}
+func (nopMessenger) SendMessage(*model.Player, string)               {}
+func (nopMessenger) ShowHTML(*model.Player, *model.Npc, string)      {}
+func (nopMessenger) InventoryChanged(context.Context, *model.Player) {}
+func (nopMessenger) ExpGained(*model.Player, int64)                  {}
@@ -0,0 +1,327 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+type fakeWorld struct {
+	npcs    map[uint32]*model.Npc
+	nextID  uint32
+	removed []uint32
+}
+
+func newFakeWorld() *fakeWorld {
+	return &fakeWorld{npcs: make(map[uint32]*model.Npc), nextID: 500000}
+}
+
+func (w *fakeWorld) Npc(objectID uint32) (*model.Npc, bool) {
+	npc, ok := w.npcs[objectID]
+	return npc, ok
+}
+
+func (w *fakeWorld) SpawnNpc(_ context.Context, templateID int32, loc model.Location) (*model.Npc, error) {
+	w.nextID++
+	npc := newTestNpc(w.nextID, templateID)
+	npc.SetLocation(loc)
+	w.npcs[npc.ObjectID()] = npc
+	return npc, nil
+}
+
+func (w *fakeWorld) DespawnNpc(npc *model.Npc) {
+	delete(w.npcs, npc.ObjectID())
+	w.removed = append(w.removed, npc.ObjectID())
+}
+
+type fakeMessenger struct {
+	said     []string
+	messages []string
+	html     []string
+	changed  int
+	exp      int64
+}
+
+func (m *fakeMessenger) NpcSay(npc *model.Npc, text string) { m.said = append(m.said, text) }
+func (m *fakeMessenger) SendMessage(p *model.Player, text string) {
+	m.messages = append(m.messages, text)
+}
+func (m *fakeMessenger) ShowHTML(p *model.Player, npc *model.Npc, html string) {
+	m.html = append(m.html, html)
+}
+func (m *fakeMessenger) InventoryChanged(ctx context.Context, p *model.Player) { m.changed++ }
+func (m *fakeMessenger) ExpGained(p *model.Player, exp int64)                  { m.exp += exp }
+
+func newTestNpc(objectID uint32, templateID int32) *model.Npc {
+	tmpl := model.NewNpcTemplate(templateID, "Guide", "", 10, 1000, 500,
+		100, 50, 80, 40, 0, 120, 253, 30, 60)
+	return model.NewNpc(objectID, templateID, tmpl)
+}
+
+func newTestPlayer(t *testing.T) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(1, 1, "Scripter", 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	return p
+}
+
+// writeScript writes a script file with a distinct modification time.
+func writeScript(t *testing.T, dir, name, src string, mod time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const guideScript = `
+register_npc(30001)
+
+local visits = 0
+
+function on_talk(player, npc)
+	visits = visits + 1
+	return "<html>Hello " .. player:name() .. ", visit " .. visits .. "</html>"
+end
+
+function on_event(player, npc, event)
+	if event == "reward" and player:item_count(57) == 0 then
+		player:give_item(57, 100)
+		player:add_exp(1000, 50)
+		npc:say("Take it, " .. player:name())
+		return "<html>rewarded</html>"
+	end
+	if event == "pay" then
+		if player:take_item(57, 30) then return "<html>paid</html>" end
+		return "<html>not enough</html>"
+	end
+end
+
+function on_kill(player, npc)
+	player:message("killed " .. npc:npc_id())
+end
+`
+
+func TestRuntime_Hooks(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeScript(t, dir, "npc/guide.lua", guideScript, time.Now())
+
+	msg := &fakeMessenger{}
+	rt := NewRuntime(dir, newFakeWorld(), DefaultLimits())
+	rt.SetMessenger(msg)
+	if n, err := rt.Load(ctx); err != nil || n != 1 {
+		t.Fatalf("Load = %d, %v", n, err)
+	}
+	s, ok := rt.ScriptFor(30001)
+	if !ok || s.Name() != "npc/guide.lua" {
+		t.Fatalf("ScriptFor(30001) = %v, %v", s, ok)
+	}
+
+	player := newTestPlayer(t)
+	npc := newTestNpc(100001, 30001)
+
+	html, ok := rt.Talk(ctx, player, npc)
+	if !ok || html != "<html>Hello Scripter, visit 1</html>" {
+		t.Errorf("Talk = %q, %v", html, ok)
+	}
+	// Script globals persist between calls
+	if html, _ := rt.Talk(ctx, player, npc); !strings.Contains(html, "visit 2") {
+		t.Errorf("second Talk = %q", html)
+	}
+
+	html, ok = rt.Event(ctx, player, npc, "reward")
+	if !ok || html != "<html>rewarded</html>" {
+		t.Errorf("Event(reward) = %q, %v", html, ok)
+	}
+	if player.Inventory().CountOf(57) != 100 || player.Experience() != 1000 || player.SP() != 50 {
+		t.Errorf("reward: adena=%d exp=%d sp=%d", player.Inventory().CountOf(57), player.Experience(), player.SP())
+	}
+	if msg.changed != 1 || msg.exp != 1000 || len(msg.said) != 1 || msg.said[0] != "Take it, Scripter" {
+		t.Errorf("messenger: changed=%d exp=%d said=%v", msg.changed, msg.exp, msg.said)
+	}
+
+	for _, want := range []string{"paid", "paid", "paid", "not enough"} {
+		if html, _ := rt.Event(ctx, player, npc, "pay"); !strings.Contains(html, want) {
+			t.Errorf("Event(pay) = %q, want %q", html, want)
+		}
+	}
+	if player.Inventory().CountOf(57) != 10 {
+		t.Errorf("adena after paying = %d, want 10", player.Inventory().CountOf(57))
+	}
+
+	// Unknown event falls through to the default behavior
+	if _, ok := rt.Event(ctx, player, npc, "unknown"); ok {
+		t.Error("event without dialog should not be handled")
+	}
+	// NPC without a script
+	if _, ok := rt.Talk(ctx, player, newTestNpc(100002, 30002)); ok {
+		t.Error("NPC without script should not be handled")
+	}
+
+	rt.Kill(ctx, player, npc)
+	if len(msg.messages) != 1 || msg.messages[0] != "killed 30001" {
+		t.Errorf("messages = %v", msg.messages)
+	}
+}
+
+func TestRuntime_Reload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	mod := time.Now().Add(-time.Hour)
+	writeScript(t, dir, "a.lua", `register_npc(1) function on_talk(p, n) return "v1" end`, mod)
+
+	rt := NewRuntime(dir, nil, DefaultLimits())
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	player := newTestPlayer(t)
+	npc := newTestNpc(1, 1)
+
+	// Unchanged files are not reloaded
+	if n, _ := rt.Load(ctx); n != 0 {
+		t.Errorf("reload of unchanged dir loaded %d scripts", n)
+	}
+
+	mod = mod.Add(time.Minute)
+	writeScript(t, dir, "a.lua", `register_npc(1) function on_talk(p, n) return "v2" end`, mod)
+	if n, _ := rt.Load(ctx); n != 1 {
+		t.Errorf("reload loaded %d scripts, want 1", n)
+	}
+	if html, _ := rt.Talk(ctx, player, npc); html != "v2" {
+		t.Errorf("after reload Talk = %q, want v2", html)
+	}
+
+	// A broken version keeps the previous one running
+	mod = mod.Add(time.Minute)
+	writeScript(t, dir, "a.lua", `register_npc(1) function on_talk(p, n) return "v3" `, mod)
+	if n, _ := rt.Load(ctx); n != 0 {
+		t.Errorf("broken script counted as loaded")
+	}
+	if html, _ := rt.Talk(ctx, player, npc); html != "v2" {
+		t.Errorf("after broken reload Talk = %q, want v2", html)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "a.lua")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if rt.Count() != 0 {
+		t.Errorf("Count after delete = %d", rt.Count())
+	}
+	if _, ok := rt.Talk(ctx, player, npc); ok {
+		t.Error("deleted script should not handle talk")
+	}
+
+	// register_npc is only allowed at load time
+	writeScript(t, dir, "b.lua", `function on_talk(p, n) register_npc(2) return "x" end register_npc(1)`, mod)
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := rt.Talk(ctx, player, npc); ok {
+		t.Error("register_npc in a hook should fail")
+	}
+}
+
+func TestRuntime_RunawayScriptDisabled(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeScript(t, dir, "loop.lua", `
+		register_npc(7)
+		function on_think(npc) while true do end end
+	`, time.Now())
+
+	rt := NewRuntime(dir, nil, Limits{Steps: 5_000, Time: 10 * time.Millisecond, Depth: 16})
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	npc := newTestNpc(7, 7)
+
+	start := time.Now()
+	for range maxViolations {
+		rt.Think(ctx, npc)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("runaway hooks took %v", elapsed)
+	}
+	s, _ := rt.Script("loop.lua")
+	if !s.Disabled() {
+		t.Fatal("script should be disabled after repeated limit violations")
+	}
+
+	// A runaway top level fails the load
+	writeScript(t, dir, "bad.lua", "while true do end", time.Now())
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := rt.Script("bad.lua"); ok {
+		t.Error("script with runaway top level should not be loaded")
+	}
+}
+
+func TestController(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeScript(t, dir, "spawner.lua", `
+		register_npc(40001)
+		local ticks = 0
+		function on_spawn(npc)
+			npc:say("spawned " .. npc:object_id())
+		end
+		function on_think(npc)
+			ticks = ticks + 1
+			if ticks == 2 then
+				local guard = spawn_npc(40002, npc:x() + 50, npc:y(), npc:z())
+				guard:say("guard " .. guard:npc_id())
+			end
+			if ticks == 3 then
+				find_npc(500001):despawn()
+			end
+		end
+	`, time.Now())
+
+	world := newFakeWorld()
+	msg := &fakeMessenger{}
+	rt := NewRuntime(dir, world, DefaultLimits())
+	rt.SetMessenger(msg)
+	if _, err := rt.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	npc := newTestNpc(100, 40001)
+	npc.SetLocation(model.NewLocation(1000, 2000, -100, 0))
+	ctrl := rt.NewController(npc)
+	ctrl.Start()
+	for range 3 {
+		ctrl.Tick()
+	}
+
+	want := []string{"spawned 100", "guard 40002"}
+	if strings.Join(msg.said, "|") != strings.Join(want, "|") {
+		t.Errorf("said = %v, want %v", msg.said, want)
+	}
+	if len(world.removed) != 1 || world.removed[0] != 500001 {
+		t.Errorf("despawned = %v", world.removed)
+	}
+
+	ctrl.Stop()
+	ctrl.Tick()
+	if len(msg.said) != 2 {
+		t.Error("stopped controller should not run hooks")
+	}
+}
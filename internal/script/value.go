@@ -0,0 +1,108 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Value is a script value: nil, bool, float64, string, *closure, *Builtin or Object.
+type Value any
+
+// Builtin is a Go function callable from scripts.
+type Builtin struct {
+	Name string
+	Fn   func(c *Call, args []Value) (Value, error)
+}
+
+// Object is a Go value exposed to scripts with methods (obj:method(...)).
+type Object interface {
+	TypeName() string
+	CallMethod(c *Call, method string, args []Value) (Value, error)
+}
+
+// closure is a script function with its defining scope.
+type closure struct {
+	fn  *funcExpr
+	env *scope
+}
+
+// scope is a lexical variable scope.
+type scope struct {
+	vars   map[string]Value
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: make(map[string]Value, 4), parent: parent}
+}
+
+// lookup finds the scope that declares name (nil if it is not a local).
+func (s *scope) lookup(name string) *scope {
+	for ; s != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// typeName returns the script type of v.
+func typeName(v Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *closure, *Builtin:
+		return "function"
+	case Object:
+		return v.TypeName()
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// truthy reports whether v counts as true: everything except nil and false.
+func truthy(v Value) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// ToString formats v the way tostring() and concatenation do.
+func ToString(v Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', 14, 64)
+	case string:
+		return v
+	case *closure:
+		if v.fn.name != "" {
+			return "function: " + v.fn.name
+		}
+		return "function"
+	case *Builtin:
+		return "builtin: " + v.Name
+	case Object:
+		return v.TypeName()
+	default:
+		return fmt.Sprint(v)
+	}
+}
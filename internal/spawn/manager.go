@@ -2,6 +2,7 @@ package spawn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand/v2"
@@ -9,10 +10,21 @@ import (
 	"sync/atomic"
 
 	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/geo"
 	"github.com/udisondev/la2go/internal/model"
 	"github.com/udisondev/la2go/internal/world"
 )
 
+var (
+	// ErrUnknownTerritory is returned when a spawn refers to a territory that is not loaded
+	ErrUnknownTerritory = errors.New("unknown spawn territory")
+	// ErrNoSpawnPoint is returned when no valid point of a territory was found
+	ErrNoSpawnPoint = errors.New("no valid spawn point in territory")
+)
+
+// territoryAttempts is how many random points of a territory are tried against geodata
+const territoryAttempts = 10
+
 // NpcRepository interface for loading NPC templates
 type NpcRepository interface {
 	LoadTemplate(ctx context.Context, templateID int32) (*model.NpcTemplate, error)
@@ -23,16 +35,30 @@ type SpawnRepository interface {
 	LoadAll(ctx context.Context) ([]*model.Spawn, error)
 }
 
+// ControllerFactory creates the AI controller of a spawned NPC.
+type ControllerFactory func(npc *model.Npc) ai.Controller
+
+// MonsterControllerFactory creates the AI controller of a spawned monster.
+type MonsterControllerFactory func(monster *model.Monster) ai.Controller
+
 // Manager manages NPC spawns and respawns
 type Manager struct {
 	spawns    sync.Map // map[int64]*model.Spawn — spawnID → spawn
+	npcs      sync.Map // map[uint32]*model.Npc — objectID → spawned NPC
 	npcRepo   NpcRepository
 	spawnRepo SpawnRepository
 	world     *world.World
 	aiManager *ai.TickManager
+	newAI     atomic.Pointer[ControllerFactory]
+	monsterAI atomic.Pointer[MonsterControllerFactory]
+	geoData   atomic.Pointer[geo.Engine]
+	walking   atomic.Pointer[ai.WalkConfig]
+	routes    atomic.Pointer[map[string]*model.Route]
+	territory atomic.Pointer[map[string]*model.Territory]
 
 	objectIDCounter atomic.Uint32 // for generating unique objectIDs
 	spawnCount      atomic.Int32  // cached count of spawns (O(1) access)
+	adHocSpawnID    atomic.Int64  // negative IDs of spawns created by SpawnNpc
 }
 
 // NewManager creates new spawn manager
@@ -89,17 +115,28 @@ func (m *Manager) DoSpawn(ctx context.Context, spawn *model.Spawn) (*model.Npc,
 		return nil, fmt.Errorf("loading template %d for spawn %d: %w", spawn.TemplateID(), spawn.SpawnID(), err)
 	}
 
+	loc, err := m.spawnLocation(spawn)
+	if err != nil {
+		return nil, fmt.Errorf("placing NPC of spawn %d: %w", spawn.SpawnID(), err)
+	}
+
 	// Generate unique objectID
 	objectID := m.objectIDCounter.Add(1)
 
-	// Create NPC
+	// Create NPC; templates with aggro range are aggressive monsters
+	var monster *model.Monster
 	npc := model.NewNpc(objectID, spawn.TemplateID(), template)
+	if template.AggroRange() > 0 {
+		monster = model.NewMonster(objectID, spawn.TemplateID(), template)
+		npc = monster.Npc
+	}
 
 	// Set spawn reference
 	npc.SetSpawn(spawn)
 
-	// Set location from spawn
-	npc.SetLocation(spawn.Location())
+	// Set location from spawn, standing on the ground
+	npc.SetLocation(loc)
+	npc.SetSpawnLocation(loc)
 
 	// Increase spawn count
 	spawn.IncreaseCount()
@@ -115,20 +152,144 @@ func (m *Manager) DoSpawn(ctx context.Context, spawn *model.Spawn) (*model.Npc,
 		return nil, fmt.Errorf("adding NPC to world: %w", err)
 	}
 
+	m.npcs.Store(objectID, npc)
+
 	// Create and register AI
-	npcAI := ai.NewBasicNpcAI(npc)
-	m.aiManager.Register(objectID, npcAI)
+	ctrl := m.newController(npc, monster)
+	m.attachWalker(ctrl, npc)
+	m.aiManager.Register(objectID, ctrl)
 
 	slog.Info("NPC spawned",
 		"objectID", objectID,
 		"name", npc.Name(),
 		"templateID", template.TemplateID(),
 		"spawnID", spawn.SpawnID(),
-		"location", spawn.Location())
+		"location", loc)
 
 	return npc, nil
 }
 
+// SetControllerFactory replaces the AI of NPCs spawned from now on (BasicNpcAI by default).
+func (m *Manager) SetControllerFactory(f ControllerFactory) {
+	m.newAI.Store(&f)
+}
+
+// SetMonsterControllerFactory sets the AI of monsters spawned from now on;
+// without it monsters get the same AI as other NPCs.
+func (m *Manager) SetMonsterControllerFactory(f MonsterControllerFactory) {
+	m.monsterAI.Store(&f)
+}
+
+// SetWalking makes NPCs spawned from now on random walk around their spawn points
+// or patrol their routes.
+func (m *Manager) SetWalking(cfg ai.WalkConfig) {
+	m.walking.Store(&cfg)
+}
+
+// SetRoutes replaces the patrol routes that spawns refer to by name.
+func (m *Manager) SetRoutes(routes map[string]*model.Route) {
+	m.routes.Store(&routes)
+}
+
+// attachWalker gives a walking AI its patrol route or random walk.
+func (m *Manager) attachWalker(ctrl ai.Controller, npc *model.Npc) {
+	cfg := m.walking.Load()
+	w, ok := ctrl.(ai.Walkable)
+	if cfg == nil || !ok {
+		return
+	}
+
+	var route *model.Route
+	if name := npc.Spawn().Route(); name != "" {
+		if routes := m.routes.Load(); routes != nil {
+			route = (*routes)[name]
+		}
+		if route == nil {
+			slog.Warn("unknown patrol route, NPC will random walk",
+				"route", name,
+				"spawnID", npc.Spawn().SpawnID())
+		}
+	}
+	if route == nil && cfg.Radius <= 0 {
+		return
+	}
+	w.SetWalker(ai.NewWalker(npc, route, *cfg))
+}
+
+// SetTerritories replaces the territories that spawns refer to by name.
+func (m *Manager) SetTerritories(territories map[string]*model.Territory) {
+	m.territory.Store(&territories)
+}
+
+// spawnLocation picks where the next NPC of a spawn appears: the spawn point on the ground,
+// or a random allowed point of the spawn territory.
+func (m *Manager) spawnLocation(spawn *model.Spawn) (model.Location, error) {
+	name := spawn.Territory()
+	if name == "" {
+		return m.groundLocation(spawn.Location()), nil
+	}
+
+	var t *model.Territory
+	if territories := m.territory.Load(); territories != nil {
+		t = (*territories)[name]
+	}
+	if t == nil {
+		return model.Location{}, fmt.Errorf("territory %q: %w", name, ErrUnknownTerritory)
+	}
+
+	g := m.geoData.Load()
+	for range territoryAttempts {
+		x, y, ok := t.RandomPoint()
+		if !ok {
+			break
+		}
+		// Without geodata NPCs appear at the top of the range and clients drop them to the ground
+		z := t.MaxZ()
+		if g != nil {
+			// The ground may lie outside the range (a cave below, a cliff above)
+			if z = g.GetHeight(x, y, t.MaxZ()); !t.ContainsZ(z) {
+				continue
+			}
+		}
+		return model.NewLocation(x, y, z, uint16(rand.IntN(65536))), nil
+	}
+	return model.Location{}, fmt.Errorf("territory %q: %w", name, ErrNoSpawnPoint)
+}
+
+// SetGeo enables geodata: NPCs spawn on the ground nearest to the spawn point height.
+func (m *Manager) SetGeo(g *geo.Engine) {
+	m.geoData.Store(g)
+}
+
+// groundLocation snaps loc to the geodata ground (unchanged without geodata).
+func (m *Manager) groundLocation(loc model.Location) model.Location {
+	if g := m.geoData.Load(); g != nil {
+		loc.Z = g.GetHeight(loc.X, loc.Y, loc.Z)
+	}
+	return loc
+}
+
+// newController creates AI controller for a freshly spawned NPC (monster is nil for other NPCs)
+func (m *Manager) newController(npc *model.Npc, monster *model.Monster) ai.Controller {
+	if monster != nil {
+		if f := m.monsterAI.Load(); f != nil && *f != nil {
+			return (*f)(monster)
+		}
+	}
+	if f := m.newAI.Load(); f != nil && *f != nil {
+		return (*f)(npc)
+	}
+	return ai.NewBasicNpcAI(npc)
+}
+
+// SpawnNpc spawns a single NPC outside of the spawn table (scripts, GM commands).
+// The NPC is not respawned after despawn.
+func (m *Manager) SpawnNpc(ctx context.Context, templateID int32, loc model.Location) (*model.Npc, error) {
+	spawnID := m.adHocSpawnID.Add(-1)
+	spawn := model.NewSpawn(spawnID, templateID, loc.X, loc.Y, loc.Z, loc.Heading, 1, false)
+	return m.DoSpawn(ctx, spawn)
+}
+
 // DespawnNpc despawns NPC (removes from world)
 func (m *Manager) DespawnNpc(npc *model.Npc) {
 	spawn := npc.Spawn()
@@ -142,6 +303,7 @@ func (m *Manager) DespawnNpc(npc *model.Npc) {
 
 	// Remove from world
 	m.world.RemoveObject(npc.ObjectID())
+	m.npcs.Delete(npc.ObjectID())
 
 	// Remove from spawn's NPC list
 	spawn.RemoveNpc(npc)
@@ -170,6 +332,15 @@ func (m *Manager) GetSpawn(spawnID int64) (*model.Spawn, bool) {
 	return value.(*model.Spawn), true
 }
 
+// Npc returns a spawned NPC by objectID
+func (m *Manager) Npc(objectID uint32) (*model.Npc, bool) {
+	value, ok := m.npcs.Load(objectID)
+	if !ok {
+		return nil, false
+	}
+	return value.(*model.Npc), true
+}
+
 // SpawnCount returns total number of spawns (O(1) cached count)
 // IMPORTANT: Count is cached atomically and updated when spawns are loaded.
 // This is a performance optimization to avoid O(N) Range() on sync.Map.
@@ -1,10 +1,14 @@
 package spawn
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/udisondev/la2go/internal/ai"
+	"github.com/udisondev/la2go/internal/geo"
 	"github.com/udisondev/la2go/internal/model"
 	"github.com/udisondev/la2go/internal/world"
 )
@@ -107,6 +111,9 @@ func TestManager_DoSpawn(t *testing.T) {
 	if !ok {
 		t.Error("NPC not found in world")
 	}
+	if got, ok := mgr.Npc(npc.ObjectID()); !ok || got != npc {
+		t.Error("Npc() should return the spawned NPC")
+	}
 
 	// Cleanup
 	mgr.DespawnNpc(npc)
@@ -158,6 +165,9 @@ func TestManager_DespawnNpc(t *testing.T) {
 	if ok {
 		t.Error("NPC still in world after despawn")
 	}
+	if _, ok := mgr.Npc(npc.ObjectID()); ok {
+		t.Error("Npc() should not return a despawned NPC")
+	}
 }
 
 func TestManager_DoSpawn_SpawnFull(t *testing.T) {
@@ -201,6 +211,266 @@ func TestManager_DoSpawn_SpawnFull(t *testing.T) {
 	mgr.DespawnNpc(npc1)
 }
 
+func TestManager_SpawnNpc(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	aiMgr := ai.NewTickManager()
+	mgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), aiMgr)
+
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1003, "Guard", "", 20, 3000, 1000,
+		200, 100, 100, 50, 0, 100, 253, 0, 0,
+	))
+
+	// Custom AI factory is used for every spawn after it is set
+	var created []*model.Npc
+	mgr.SetControllerFactory(func(npc *model.Npc) ai.Controller {
+		created = append(created, npc)
+		return ai.NewBasicNpcAI(npc)
+	})
+
+	ctx := context.Background()
+	npc, err := mgr.SpawnNpc(ctx, 1003, model.NewLocation(17100, 170100, -3500, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	if npc.X() != 17100 || npc.Y() != 170100 {
+		t.Errorf("NPC location = %v", npc.Location())
+	}
+	if npc.Spawn().SpawnID() >= 0 || npc.Spawn().DoRespawn() {
+		t.Errorf("ad-hoc spawn: id=%d doRespawn=%v", npc.Spawn().SpawnID(), npc.Spawn().DoRespawn())
+	}
+	if len(created) != 1 || created[0] != npc {
+		t.Errorf("controller factory called %d times", len(created))
+	}
+	if _, err := aiMgr.GetController(npc.ObjectID()); err != nil {
+		t.Errorf("AI not registered: %v", err)
+	}
+
+	// Second ad-hoc spawn gets its own spawn point
+	other, err := mgr.SpawnNpc(ctx, 1003, model.NewLocation(17200, 170100, -3500, 0))
+	if err != nil {
+		t.Fatalf("second SpawnNpc() error = %v", err)
+	}
+	if other.Spawn() == npc.Spawn() {
+		t.Error("ad-hoc spawns must not share a spawn point")
+	}
+
+	if _, err := mgr.SpawnNpc(ctx, 99999, npc.Location()); err == nil {
+		t.Error("SpawnNpc() with unknown template should fail")
+	}
+
+	mgr.DespawnNpc(npc)
+	mgr.DespawnNpc(other)
+}
+
+func TestManager_SpawnOnGround(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	mgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), ai.NewTickManager())
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1004, "Wolf", "", 5, 200, 50,
+		20, 10, 10, 5, 0, 120, 253, 0, 0,
+	))
+
+	// Регион 20_18 (начинается в мировых координатах 0,0) — плоская земля на высоте -200
+	region := bytes.Repeat([]byte{0, 0x38, 0xFF}, geo.RegionBlocks*geo.RegionBlocks)
+	geoData := geo.NewEngine()
+	if err := geoData.LoadRegion(20, 18, bytes.NewReader(region)); err != nil {
+		t.Fatalf("LoadRegion() error = %v", err)
+	}
+	mgr.SetGeo(geoData)
+
+	ctx := context.Background()
+	npc, err := mgr.SpawnNpc(ctx, 1004, model.NewLocation(1000, 1000, 0, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	defer mgr.DespawnNpc(npc)
+	if npc.Z() != -200 {
+		t.Errorf("NPC Z = %d, want ground height -200", npc.Z())
+	}
+
+	// Вне загруженной геодаты высота точки спавна сохраняется
+	outside, err := mgr.SpawnNpc(ctx, 1004, model.NewLocation(-1000, -1000, 300, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	defer mgr.DespawnNpc(outside)
+	if outside.Z() != 300 {
+		t.Errorf("NPC Z without geodata = %d, want 300", outside.Z())
+	}
+}
+
+func TestManager_SpawnMonster(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	aiMgr := ai.NewTickManager()
+	mgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), aiMgr)
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1005, "Orc", "", 10, 1000, 200,
+		50, 40, 20, 20, 300, 100, 253, 0, 0,
+	))
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1006, "Merchant", "", 70, 3000, 1000,
+		0, 0, 0, 0, 0, 100, 253, 0, 0,
+	))
+
+	var monsters []*model.Monster
+	mgr.SetMonsterControllerFactory(func(monster *model.Monster) ai.Controller {
+		monsters = append(monsters, monster)
+		return ai.NewAttackableAI(monster, ai.AttackableConfig{})
+	})
+
+	ctx := context.Background()
+	orc, err := mgr.SpawnNpc(ctx, 1005, model.NewLocation(17300, 170300, -3500, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	defer mgr.DespawnNpc(orc)
+	merchant, err := mgr.SpawnNpc(ctx, 1006, model.NewLocation(17400, 170300, -3500, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	defer mgr.DespawnNpc(merchant)
+
+	// Шаблон с радиусом агрессии становится монстром с AI монстров
+	if len(monsters) != 1 || monsters[0].Npc != orc || !monsters[0].IsAggressive() {
+		t.Fatalf("monster factory calls = %d", len(monsters))
+	}
+	ctrl, err := aiMgr.GetController(orc.ObjectID())
+	if err != nil {
+		t.Fatalf("AI not registered: %v", err)
+	}
+	if _, ok := ctrl.(*ai.AttackableAI); !ok {
+		t.Errorf("monster controller = %T, want *ai.AttackableAI", ctrl)
+	}
+
+	// Остальные NPC получают обычный AI
+	ctrl, err = aiMgr.GetController(merchant.ObjectID())
+	if err != nil {
+		t.Fatalf("AI not registered: %v", err)
+	}
+	if _, ok := ctrl.(*ai.BasicNpcAI); !ok {
+		t.Errorf("NPC controller = %T, want *ai.BasicNpcAI", ctrl)
+	}
+}
+
+func TestManager_WalkingNpcs(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	aiMgr := ai.NewTickManager()
+	mgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), aiMgr)
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1007, "Guard", "", 20, 3000, 1000,
+		0, 0, 0, 0, 0, 100, 253, 0, 0,
+	))
+
+	mgr.SetWalking(ai.WalkConfig{TickInterval: time.Second})
+	mgr.SetRoutes(map[string]*model.Route{
+		"gate": {Name: "gate", Points: []model.RoutePoint{
+			{Location: model.NewLocation(18000, 171000, -3500, 0)},
+			{Location: model.NewLocation(18500, 171000, -3500, 0)},
+		}},
+	})
+
+	ctx := context.Background()
+	spawnNpc := func(id int64, route string) *model.Npc {
+		t.Helper()
+		s := model.NewSpawn(id, 1007, 18000, 171000, -3500, 0, 1, false)
+		s.SetRoute(route)
+		npc, err := mgr.DoSpawn(ctx, s)
+		if err != nil {
+			t.Fatalf("DoSpawn() error = %v", err)
+		}
+		t.Cleanup(func() { mgr.DespawnNpc(npc) })
+		return npc
+	}
+	tick := func(npc *model.Npc) {
+		t.Helper()
+		ctrl, err := aiMgr.GetController(npc.ObjectID())
+		if err != nil {
+			t.Fatalf("AI not registered: %v", err)
+		}
+		ctrl.Start()
+		ctrl.Tick()
+	}
+
+	// NPC с маршрутом идёт к следующей точке
+	patrol := spawnNpc(-70001, "gate")
+	tick(patrol)
+	if patrol.X() != 18100 {
+		t.Errorf("patrolling NPC at %v, want on the way to the second point", patrol.Location())
+	}
+
+	// Неизвестный маршрут при нулевом радиусе — NPC стоит на месте
+	lost := spawnNpc(-70002, "nowhere")
+	tick(lost)
+	if lost.X() != 18000 || lost.Y() != 171000 {
+		t.Errorf("NPC with unknown route moved to %v", lost.Location())
+	}
+}
+
+func TestManager_TerritorySpawn(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	mgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), ai.NewTickManager())
+	npcRepo.AddTemplate(model.NewNpcTemplate(
+		1008, "Wolf", "", 5, 200, 50,
+		20, 10, 10, 5, 0, 120, 253, 0, 0,
+	))
+
+	// Регион 20_18 — плоская земля на высоте -200
+	region := bytes.Repeat([]byte{0, 0x38, 0xFF}, geo.RegionBlocks*geo.RegionBlocks)
+	geoData := geo.NewEngine()
+	if err := geoData.LoadRegion(20, 18, bytes.NewReader(region)); err != nil {
+		t.Fatalf("LoadRegion() error = %v", err)
+	}
+	mgr.SetGeo(geoData)
+
+	square := []model.TerritoryPoint{{X: 1000, Y: 1000}, {X: 2000, Y: 1000}, {X: 2000, Y: 2000}, {X: 1000, Y: 2000}}
+	meadow, err := model.NewTerritory("meadow", square, -300, -100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plateau, err := model.NewTerritory("plateau", square, 500, 800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr.SetTerritories(map[string]*model.Territory{"meadow": meadow, "plateau": plateau})
+
+	ctx := context.Background()
+	s := model.NewSpawn(-80001, 1008, 0, 0, 0, 0, 5, false)
+	s.SetTerritory("meadow")
+	for range s.MaximumCount() {
+		npc, err := mgr.DoSpawn(ctx, s)
+		if err != nil {
+			t.Fatalf("DoSpawn() error = %v", err)
+		}
+		defer mgr.DespawnNpc(npc)
+
+		// Каждый NPC стоит на земле внутри территории и помнит свою точку появления
+		loc := npc.Location()
+		if !meadow.Contains(loc.X, loc.Y) || loc.Z != -200 {
+			t.Errorf("NPC at %v, want inside the meadow on the ground", loc)
+		}
+		if npc.SpawnLocation() != loc {
+			t.Errorf("SpawnLocation() = %v, want %v", npc.SpawnLocation(), loc)
+		}
+	}
+
+	// Земля ниже диапазона высот территории — точку найти нельзя
+	high := model.NewSpawn(-80002, 1008, 0, 0, 0, 0, 1, false)
+	high.SetTerritory("plateau")
+	if _, err := mgr.DoSpawn(ctx, high); !errors.Is(err, ErrNoSpawnPoint) {
+		t.Errorf("DoSpawn() on the plateau error = %v, want ErrNoSpawnPoint", err)
+	}
+	if high.CurrentCount() != 0 {
+		t.Errorf("failed spawn counted: %d", high.CurrentCount())
+	}
+
+	unknown := model.NewSpawn(-80003, 1008, 0, 0, 0, 0, 1, false)
+	unknown.SetTerritory("swamp")
+	if _, err := mgr.DoSpawn(ctx, unknown); !errors.Is(err, ErrUnknownTerritory) {
+		t.Errorf("DoSpawn() in unknown territory error = %v, want ErrUnknownTerritory", err)
+	}
+}
+
 func TestCalculateRespawnDelay(t *testing.T) {
 	template := model.NewNpcTemplate(
 		1003, "Test", "", 1, 1000, 500,
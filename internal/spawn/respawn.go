@@ -83,6 +83,17 @@ func (m *RespawnTaskManager) ScheduleRespawn(spawn *model.Spawn, delaySeconds in
 		"respawnTime", respawnTime.Format(time.RFC3339))
 }
 
+// OnNpcDeath removes a killed NPC from the world and schedules its respawn;
+// NPCs of spawns without respawn (scripts, GM commands) are gone for good.
+func (m *RespawnTaskManager) OnNpcDeath(npc *model.Npc) {
+	spawn := npc.Spawn()
+	m.spawnManager.DespawnNpc(npc)
+	if spawn == nil || !spawn.DoRespawn() {
+		return
+	}
+	m.ScheduleRespawn(spawn, CalculateRespawnDelay(npc.Template()))
+}
+
 // CancelRespawn cancels scheduled respawn
 func (m *RespawnTaskManager) CancelRespawn(spawnID int64) {
 	m.mu.Lock()
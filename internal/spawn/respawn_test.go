@@ -186,3 +186,47 @@ func TestRespawnTaskManager_MultipleTasksprocessing(t *testing.T) {
 
 	cancel()
 }
+
+func TestRespawnTaskManager_OnNpcDeath(t *testing.T) {
+	npcRepo := newMockNpcRepository()
+	spawnMgr := NewManager(npcRepo, newMockSpawnRepository(), world.Instance(), ai.NewTickManager())
+	respawnMgr := NewRespawnTaskManager(spawnMgr)
+
+	template := model.NewNpcTemplate(
+		2002, "DeathTest", "", 1, 1000, 500,
+		0, 0, 0, 0, 0, 80, 253, 30, 30,
+	)
+	npcRepo.AddTemplate(template)
+
+	spawn := model.NewSpawn(400, 2002, 17000, 170000, -3500, 0, 1, true)
+	npc, err := spawnMgr.DoSpawn(context.Background(), spawn)
+	if err != nil {
+		t.Fatalf("DoSpawn() error = %v", err)
+	}
+
+	respawnMgr.OnNpcDeath(npc)
+
+	if _, ok := spawnMgr.Npc(npc.ObjectID()); ok {
+		t.Error("killed NPC is still spawned")
+	}
+	if spawn.CurrentCount() != 0 {
+		t.Errorf("CurrentCount() = %d, want 0", spawn.CurrentCount())
+	}
+	task, ok := respawnMgr.GetTask(400)
+	if !ok {
+		t.Fatal("respawn of killed NPC is not scheduled")
+	}
+	if d := time.Until(task.RespawnTime); d < 29*time.Second || d > 30*time.Second {
+		t.Errorf("respawn in %v, want 30s", d)
+	}
+
+	// NPCs spawned outside the spawn table are not respawned
+	adHoc, err := spawnMgr.SpawnNpc(context.Background(), 2002, model.NewLocation(17100, 170000, -3500, 0))
+	if err != nil {
+		t.Fatalf("SpawnNpc() error = %v", err)
+	}
+	respawnMgr.OnNpcDeath(adHoc)
+	if respawnMgr.TaskCount() != 1 {
+		t.Errorf("TaskCount() = %d, want 1", respawnMgr.TaskCount())
+	}
+}
@@ -0,0 +1,99 @@
+package spawn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// ErrInvalidRoute is returned for route data that cannot be walked
+var ErrInvalidRoute = errors.New("invalid patrol route")
+
+// routeFile is the YAML layout of patrol routes:
+//
+//	routes:
+//	  - name: gludin_gate
+//	    mode: reverse          # loop (default) or reverse
+//	    points:
+//	      - {x: -80826, y: 149775, z: -3043, pause: 5}   # pause in seconds
+type routeFile struct {
+	Routes []struct {
+		Name   string `yaml:"name"`
+		Mode   string `yaml:"mode"`
+		Points []struct {
+			X     int32 `yaml:"x"`
+			Y     int32 `yaml:"y"`
+			Z     int32 `yaml:"z"`
+			Pause int   `yaml:"pause"`
+		} `yaml:"points"`
+	} `yaml:"routes"`
+}
+
+// LoadRoutes parses patrol routes, keyed by name
+func LoadRoutes(r io.Reader) (map[string]*model.Route, error) {
+	var f routeFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing routes: %w", err)
+	}
+
+	routes := make(map[string]*model.Route, len(f.Routes))
+	for i, fr := range f.Routes {
+		if fr.Name == "" {
+			return nil, fmt.Errorf("route #%d has no name: %w", i+1, ErrInvalidRoute)
+		}
+		if _, dup := routes[fr.Name]; dup {
+			return nil, fmt.Errorf("route %q defined twice: %w", fr.Name, ErrInvalidRoute)
+		}
+		if len(fr.Points) < 2 {
+			return nil, fmt.Errorf("route %q needs at least 2 points: %w", fr.Name, ErrInvalidRoute)
+		}
+
+		route := &model.Route{Name: fr.Name, Points: make([]model.RoutePoint, 0, len(fr.Points))}
+		switch fr.Mode {
+		case "", "loop":
+			route.Mode = model.RouteLoop
+		case "reverse":
+			route.Mode = model.RouteReverse
+		default:
+			return nil, fmt.Errorf("route %q has unknown mode %q: %w", fr.Name, fr.Mode, ErrInvalidRoute)
+		}
+		for _, p := range fr.Points {
+			if p.Pause < 0 {
+				return nil, fmt.Errorf("route %q has negative pause: %w", fr.Name, ErrInvalidRoute)
+			}
+			route.Points = append(route.Points, model.RoutePoint{
+				Location: model.NewLocation(p.X, p.Y, p.Z, 0),
+				Pause:    time.Duration(p.Pause) * time.Second,
+			})
+		}
+		routes[fr.Name] = route
+	}
+	return routes, nil
+}
+
+// LoadRoutesFile loads patrol routes from a YAML file.
+// A missing file means no routes: NPCs only random walk.
+func LoadRoutesFile(path string) (map[string]*model.Route, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("routes file not found, NPCs will not patrol", "path", path)
+			return map[string]*model.Route{}, nil
+		}
+		return nil, fmt.Errorf("opening routes %s: %w", path, err)
+	}
+	defer f.Close()
+
+	routes, err := LoadRoutes(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading routes %s: %w", path, err)
+	}
+	return routes, nil
+}
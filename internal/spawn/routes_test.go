@@ -0,0 +1,90 @@
+package spawn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestLoadRoutes(t *testing.T) {
+	data := `
+routes:
+  - name: gate
+    mode: reverse
+    points:
+      - {x: 100, y: 200, z: -50, pause: 5}
+      - {x: 300, y: 200, z: -50}
+  - name: square
+    points:
+      - {x: 0, y: 0, z: 0}
+      - {x: 100, y: 0, z: 0}
+      - {x: 100, y: 100, z: 0}
+`
+	routes, err := LoadRoutes(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("loaded %d routes, want 2", len(routes))
+	}
+
+	gate := routes["gate"]
+	if gate == nil || gate.Mode != model.RouteReverse || len(gate.Points) != 2 {
+		t.Fatalf("gate = %+v", gate)
+	}
+	if gate.Points[0].Location != model.NewLocation(100, 200, -50, 0) || gate.Points[0].Pause != 5*time.Second {
+		t.Errorf("first point = %+v", gate.Points[0])
+	}
+	// Режим по умолчанию — петля
+	if routes["square"].Mode != model.RouteLoop {
+		t.Errorf("square mode = %v, want loop", routes["square"].Mode)
+	}
+}
+
+func TestLoadRoutes_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no name", "routes:\n  - points: [{x: 0}, {x: 1}]\n"},
+		{"one point", "routes:\n  - name: a\n    points: [{x: 0}]\n"},
+		{"duplicate", "routes:\n  - name: a\n    points: [{x: 0}, {x: 1}]\n  - name: a\n    points: [{x: 0}, {x: 1}]\n"},
+		{"bad mode", "routes:\n  - name: a\n    mode: zigzag\n    points: [{x: 0}, {x: 1}]\n"},
+		{"negative pause", "routes:\n  - name: a\n    points: [{x: 0, pause: -1}, {x: 1}]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadRoutes(strings.NewReader(tt.data)); !errors.Is(err, ErrInvalidRoute) {
+				t.Errorf("LoadRoutes() error = %v, want ErrInvalidRoute", err)
+			}
+		})
+	}
+
+	if _, err := LoadRoutes(strings.NewReader("routes: [")); err == nil {
+		t.Error("LoadRoutes() with broken YAML should fail")
+	}
+}
+
+func TestLoadRoutesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// Отсутствующий файл — маршрутов нет, но это не ошибка
+	routes, err := LoadRoutesFile(filepath.Join(dir, "routes.yaml"))
+	if err != nil || len(routes) != 0 {
+		t.Fatalf("missing file: routes=%d err=%v", len(routes), err)
+	}
+
+	path := filepath.Join(dir, "routes.yaml")
+	if err := os.WriteFile(path, []byte("routes:\n  - name: a\n    points: [{x: 0}, {x: 1}]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	routes, err = LoadRoutesFile(path)
+	if err != nil || routes["a"] == nil {
+		t.Errorf("LoadRoutesFile() = %v, %v", routes, err)
+	}
+}
@@ -0,0 +1,104 @@
+package spawn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// territoryFile is the YAML layout of spawn territories:
+//
+//	territories:
+//	  - name: elven_forest_wolves
+//	    min_z: -3600
+//	    max_z: -3300
+//	    points:                # polygon corners, at least 3
+//	      - {x: 21000, y: 51000}
+//	      - {x: 23500, y: 51000}
+//	      - {x: 23000, y: 54000}
+//	    banned: [elven_lake]   # territories cut out of this one
+type territoryFile struct {
+	Territories []struct {
+		Name   string `yaml:"name"`
+		MinZ   int32  `yaml:"min_z"`
+		MaxZ   int32  `yaml:"max_z"`
+		Points []struct {
+			X int32 `yaml:"x"`
+			Y int32 `yaml:"y"`
+		} `yaml:"points"`
+		Banned []string `yaml:"banned"`
+	} `yaml:"territories"`
+}
+
+// LoadTerritories parses spawn territories, keyed by name
+func LoadTerritories(r io.Reader) (map[string]*model.Territory, error) {
+	var f territoryFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing territories: %w", err)
+	}
+
+	territories := make(map[string]*model.Territory, len(f.Territories))
+	for _, ft := range f.Territories {
+		points := make([]model.TerritoryPoint, 0, len(ft.Points))
+		for _, p := range ft.Points {
+			points = append(points, model.TerritoryPoint{X: p.X, Y: p.Y})
+		}
+		t, err := model.NewTerritory(ft.Name, points, ft.MinZ, ft.MaxZ)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := territories[t.Name()]; dup {
+			return nil, fmt.Errorf("territory %q defined twice: %w", t.Name(), model.ErrInvalidTerritory)
+		}
+		territories[t.Name()] = t
+	}
+
+	// Banned areas may be defined after the territories that refer to them
+	for _, ft := range f.Territories {
+		if err := banTerritories(territories, ft.Name, ft.Banned); err != nil {
+			return nil, err
+		}
+	}
+	return territories, nil
+}
+
+// banTerritories cuts the named banned territories out of territory name.
+func banTerritories(territories map[string]*model.Territory, name string, banned []string) error {
+	t := territories[name]
+	for _, bn := range banned {
+		b, ok := territories[bn]
+		if !ok {
+			return fmt.Errorf("territory %q bans unknown territory %q: %w", name, bn, model.ErrInvalidTerritory)
+		}
+		if err := t.AddBanned(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadTerritoriesFile loads spawn territories from a YAML file.
+// A missing file means no territories to import.
+func LoadTerritoriesFile(path string) (map[string]*model.Territory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("territories file not found, nothing to import", "path", path)
+			return map[string]*model.Territory{}, nil
+		}
+		return nil, fmt.Errorf("opening territories %s: %w", path, err)
+	}
+	defer f.Close()
+
+	territories, err := LoadTerritories(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading territories %s: %w", path, err)
+	}
+	return territories, nil
+}
@@ -0,0 +1,89 @@
+package spawn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestLoadTerritories(t *testing.T) {
+	data := `
+territories:
+  - name: forest
+    min_z: -3600
+    max_z: -3300
+    points:
+      - {x: 0, y: 0}
+      - {x: 1000, y: 0}
+      - {x: 1000, y: 1000}
+      - {x: 0, y: 1000}
+    banned: [lake]
+  - name: lake
+    min_z: -3600
+    max_z: -3300
+    points:
+      - {x: 400, y: 400}
+      - {x: 600, y: 400}
+      - {x: 500, y: 600}
+`
+	territories, err := LoadTerritories(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadTerritories() error = %v", err)
+	}
+	forest := territories["forest"]
+	if forest == nil || len(forest.Points()) != 4 || forest.MinZ() != -3600 || forest.MaxZ() != -3300 {
+		t.Fatalf("forest = %+v", forest)
+	}
+	// Запрещённая зона может быть описана после ссылающейся на неё территории
+	if len(forest.Banned()) != 1 || forest.Banned()[0] != territories["lake"] {
+		t.Errorf("forest banned = %v, want the lake", forest.Banned())
+	}
+	if forest.IsAllowed(500, 450) {
+		t.Error("point in the lake should not be allowed")
+	}
+}
+
+func TestLoadTerritories_Invalid(t *testing.T) {
+	square := "points: [{x: 0, y: 0}, {x: 10, y: 0}, {x: 10, y: 10}]"
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"two points", "territories:\n  - name: a\n    points: [{x: 0, y: 0}, {x: 1, y: 1}]\n"},
+		{"duplicate", "territories:\n  - name: a\n    " + square + "\n  - name: a\n    " + square + "\n"},
+		{"unknown ban", "territories:\n  - name: a\n    " + square + "\n    banned: [b]\n"},
+		{"self ban", "territories:\n  - name: a\n    " + square + "\n    banned: [a]\n"},
+		{"inverted Z", "territories:\n  - name: a\n    min_z: 10\n    max_z: 0\n    " + square + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadTerritories(strings.NewReader(tt.data)); !errors.Is(err, model.ErrInvalidTerritory) {
+				t.Errorf("LoadTerritories() error = %v, want ErrInvalidTerritory", err)
+			}
+		})
+	}
+}
+
+func TestLoadTerritoriesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "territories.yaml")
+
+	// Отсутствующий файл — импортировать нечего
+	territories, err := LoadTerritoriesFile(path)
+	if err != nil || len(territories) != 0 {
+		t.Fatalf("missing file: territories=%d err=%v", len(territories), err)
+	}
+
+	data := "territories:\n  - name: a\n    points: [{x: 0, y: 0}, {x: 10, y: 0}, {x: 10, y: 10}]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	territories, err = LoadTerritoriesFile(path)
+	if err != nil || territories["a"] == nil {
+		t.Errorf("LoadTerritoriesFile() = %v, %v", territories, err)
+	}
+}
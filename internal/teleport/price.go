@@ -0,0 +1,26 @@
+package teleport
+
+import "time"
+
+// Discounts lower the price of gatekeeper teleports.
+type Discounts struct {
+	FreeLevel    int32 // players up to this level teleport for free; 0 = nobody
+	WeekendNight bool  // half price on Saturday and Sunday from 20:00 to midnight (L2J)
+}
+
+// Price returns what a player of the given level pays at now for a destination.
+func (d Discounts) Price(dest Destination, level int32, now time.Time) int64 {
+	if level <= d.FreeLevel {
+		return 0
+	}
+	price := dest.Price
+	if d.WeekendNight && isWeekendNight(now) {
+		price /= 2
+	}
+	return price
+}
+
+func isWeekendNight(t time.Time) bool {
+	day := t.Weekday()
+	return (day == time.Saturday || day == time.Sunday) && t.Hour() >= 20
+}
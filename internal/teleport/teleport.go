@@ -0,0 +1,132 @@
+package teleport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// ErrInvalidList is returned for teleport data that cannot be used
+var ErrInvalidList = errors.New("invalid teleport list")
+
+// Destination is a place a gatekeeper sends players to.
+type Destination struct {
+	ID    int32
+	Name  string
+	Loc   model.Location
+	Price int64 // adena before discounts
+}
+
+// Table holds the teleport lists of gatekeeper NPC templates. Immutable after loading.
+type Table struct {
+	lists map[int32][]Destination // NPC template ID → destinations
+}
+
+// Destinations returns the teleport list of a gatekeeper template (nil for other NPCs).
+func (t *Table) Destinations(npcID int32) []Destination {
+	if t == nil {
+		return nil
+	}
+	return t.lists[npcID]
+}
+
+// Destination returns a destination of a gatekeeper template.
+func (t *Table) Destination(npcID, id int32) (Destination, bool) {
+	for _, d := range t.Destinations(npcID) {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Destination{}, false
+}
+
+// Len returns the number of gatekeeper templates.
+func (t *Table) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.lists)
+}
+
+// tableFile is the YAML layout of teleport lists:
+//
+//	gatekeepers:
+//	  - npc_id: 30006            # Gatekeeper Roxxy, Talking Island
+//	    destinations:
+//	      - {id: 1, name: Elven Village, x: 46934, y: 51467, z: -2977, price: 7100}
+type tableFile struct {
+	Gatekeepers []struct {
+		NpcID        int32 `yaml:"npc_id"`
+		Destinations []struct {
+			ID    int32  `yaml:"id"`
+			Name  string `yaml:"name"`
+			X     int32  `yaml:"x"`
+			Y     int32  `yaml:"y"`
+			Z     int32  `yaml:"z"`
+			Price int64  `yaml:"price"`
+		} `yaml:"destinations"`
+	} `yaml:"gatekeepers"`
+}
+
+// LoadTable parses teleport lists.
+func LoadTable(r io.Reader) (*Table, error) {
+	var f tableFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing teleports: %w", err)
+	}
+
+	t := &Table{lists: make(map[int32][]Destination, len(f.Gatekeepers))}
+	for _, g := range f.Gatekeepers {
+		if _, dup := t.lists[g.NpcID]; dup {
+			return nil, fmt.Errorf("gatekeeper %d defined twice: %w", g.NpcID, ErrInvalidList)
+		}
+		if len(g.Destinations) == 0 {
+			return nil, fmt.Errorf("gatekeeper %d has no destinations: %w", g.NpcID, ErrInvalidList)
+		}
+		list := make([]Destination, 0, len(g.Destinations))
+		seen := make(map[int32]bool, len(g.Destinations))
+		for _, d := range g.Destinations {
+			if seen[d.ID] {
+				return nil, fmt.Errorf("gatekeeper %d has destination %d twice: %w", g.NpcID, d.ID, ErrInvalidList)
+			}
+			if d.Price < 0 {
+				return nil, fmt.Errorf("gatekeeper %d destination %d has negative price: %w", g.NpcID, d.ID, ErrInvalidList)
+			}
+			seen[d.ID] = true
+			list = append(list, Destination{
+				ID:    d.ID,
+				Name:  d.Name,
+				Loc:   model.NewLocation(d.X, d.Y, d.Z, 0),
+				Price: d.Price,
+			})
+		}
+		t.lists[g.NpcID] = list
+	}
+	return t, nil
+}
+
+// LoadTableFile loads teleport lists from a YAML file.
+// A missing file means gatekeepers teleport nowhere.
+func LoadTableFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("teleports file not found, gatekeepers have no destinations", "path", path)
+			return &Table{}, nil
+		}
+		return nil, fmt.Errorf("opening teleports %s: %w", path, err)
+	}
+	defer f.Close()
+
+	t, err := LoadTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading teleports %s: %w", path, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,91 @@
+package teleport
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTable(t *testing.T) {
+	data := `
+gatekeepers:
+  - npc_id: 30006
+    destinations:
+      - {id: 1, name: Elven Village, x: 46934, y: 51467, z: -2977, price: 7100}
+      - {id: 2, name: Dark Elven Village, x: 9745, y: 15606, z: -4574, price: 11000}
+`
+	table, err := LoadTable(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadTable() error = %v", err)
+	}
+	if table.Len() != 1 || len(table.Destinations(30006)) != 2 {
+		t.Fatalf("table = %+v", table)
+	}
+	d, ok := table.Destination(30006, 2)
+	if !ok || d.Name != "Dark Elven Village" || d.Loc.Z != -4574 || d.Price != 11000 {
+		t.Errorf("Destination(30006, 2) = %+v, %v", d, ok)
+	}
+	if _, ok := table.Destination(30006, 3); ok {
+		t.Error("unknown destination found")
+	}
+	if table.Destinations(30007) != nil {
+		t.Error("NPC without list has destinations")
+	}
+}
+
+func TestLoadTable_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"duplicate gatekeeper", "gatekeepers:\n  - {npc_id: 1, destinations: [{id: 1}]}\n  - {npc_id: 1, destinations: [{id: 1}]}\n"},
+		{"empty list", "gatekeepers:\n  - {npc_id: 1}\n"},
+		{"duplicate destination", "gatekeepers:\n  - {npc_id: 1, destinations: [{id: 1}, {id: 1}]}\n"},
+		{"negative price", "gatekeepers:\n  - {npc_id: 1, destinations: [{id: 1, price: -5}]}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadTable(strings.NewReader(tt.data)); !errors.Is(err, ErrInvalidList) {
+				t.Errorf("LoadTable() error = %v, want ErrInvalidList", err)
+			}
+		})
+	}
+}
+
+func TestLoadTableFile_Missing(t *testing.T) {
+	table, err := LoadTableFile(filepath.Join(t.TempDir(), "teleports.yaml"))
+	if err != nil || table.Len() != 0 {
+		t.Errorf("LoadTableFile() = %v, %v; want an empty table", table, err)
+	}
+}
+
+func TestDiscounts_Price(t *testing.T) {
+	dest := Destination{Price: 1000}
+	saturdayNight := time.Date(2024, 6, 1, 21, 0, 0, 0, time.UTC)
+	saturdayNoon := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mondayNight := time.Date(2024, 6, 3, 21, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		d     Discounts
+		level int32
+		now   time.Time
+		want  int64
+	}{
+		{"full price", Discounts{}, 20, saturdayNight, 1000},
+		{"weekend night", Discounts{WeekendNight: true}, 20, saturdayNight, 500},
+		{"weekend day", Discounts{WeekendNight: true}, 20, saturdayNoon, 1000},
+		{"weekday night", Discounts{WeekendNight: true}, 20, mondayNight, 1000},
+		{"newbie", Discounts{FreeLevel: 20}, 20, mondayNight, 0},
+		{"above free level", Discounts{FreeLevel: 20}, 21, mondayNight, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Price(dest, tt.level, tt.now); got != tt.want {
+				t.Errorf("Price() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -423,16 +423,20 @@ func (c *GSClient) ReadPlayerAuthResponse() (account string, result bool, err er
 	return account, result, nil
 }
 
-// SendPlayerInGame отправляет пакет PlayerInGame (opcode 0x03).
+// SendPlayerInGame отправляет пакет PlayerInGame (opcode 0x02) с одним аккаунтом.
 func (c *GSClient) SendPlayerInGame(account string) error {
 	c.t.Helper()
 
 	payload := c.writeBuf[2:]
 	pos := 0
 
-	payload[pos] = 0x03 // PlayerInGame opcode
+	payload[pos] = 0x02 // PlayerInGame opcode
 	pos++
 
+	// Count
+	binary.LittleEndian.PutUint16(payload[pos:], 1)
+	pos += 2
+
 	// Account (UTF-16LE null-terminated)
 	accountRunes := utf16.Encode([]rune(account))
 	for _, r := range accountRunes {
@@ -448,14 +452,14 @@ func (c *GSClient) SendPlayerInGame(account string) error {
 	return c.sendEncryptedPacket(payload[:pos])
 }
 
-// SendPlayerLogout отправляет пакет PlayerLogout (opcode 0x04).
+// SendPlayerLogout отправляет пакет PlayerLogout (opcode 0x03).
 func (c *GSClient) SendPlayerLogout(account string) error {
 	c.t.Helper()
 
 	payload := c.writeBuf[2:]
 	pos := 0
 
-	payload[pos] = 0x04 // PlayerLogout opcode
+	payload[pos] = 0x03 // PlayerLogout opcode
 	pos++
 
 	// Account (UTF-16LE null-terminated)
@@ -473,6 +477,32 @@ func (c *GSClient) SendPlayerLogout(account string) error {
 	return c.sendEncryptedPacket(payload[:pos])
 }
 
+// ReadKickPlayer читает пакет KickPlayer (opcode 0x04) и возвращает account.
+func (c *GSClient) ReadKickPlayer() (string, error) {
+	c.t.Helper()
+
+	payload, err := c.readEncryptedPacket()
+	if err != nil {
+		return "", fmt.Errorf("read KickPlayer: %w", err)
+	}
+
+	if len(payload) < 1 || payload[0] != 0x04 {
+		return "", fmt.Errorf("expected KickPlayer opcode 0x04, got % x", payload)
+	}
+
+	// Parse account (UTF-16LE null-terminated)
+	accountRunes := []uint16{}
+	for pos := 1; pos+1 < len(payload); pos += 2 {
+		r := binary.LittleEndian.Uint16(payload[pos : pos+2])
+		if r == 0 {
+			break
+		}
+		accountRunes = append(accountRunes, r)
+	}
+
+	return string(utf16.Decode(accountRunes)), nil
+}
+
 // SendServerStatus отправляет пакет ServerStatus (opcode 0x06).
 // attributes — map[attributeID]value (например, map[0x01]maxPlayers).
 func (c *GSClient) SendServerStatus(serverID byte, attributes map[int]int32) error {
@@ -0,0 +1,284 @@
+// Package trade — обмен предметами между двумя игроками (окно трейда L2J):
+// запрос, ответ, выкладывание предметов, подтверждение обеими сторонами.
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	// RequestTimeout — время ожидания ответа на запрос трейда (L2J REQUEST_TIMEOUT).
+	RequestTimeout = 15 * time.Second
+
+	// Range — дистанция, с которой можно предложить трейд.
+	Range = 150
+)
+
+var (
+	ErrSelfTrade       = errors.New("cannot trade with yourself")
+	ErrAlreadyTrading  = errors.New("player is already trading")
+	ErrTargetBusy      = errors.New("player is answering another trade request")
+	ErrNoRequest       = errors.New("no pending trade request")
+	ErrRequestExpired  = errors.New("trade request expired")
+	ErrNotTrading      = errors.New("player is not trading")
+	ErrConfirmed       = errors.New("trade is already confirmed")
+	ErrItemNotTradable = errors.New("item cannot be traded")
+	ErrItemOverflow    = errors.New("too many items for the receiving inventory")
+)
+
+// request — ожидающий ответа запрос трейда.
+type request struct {
+	requester *model.Player
+	expires   time.Time
+}
+
+// side — половина сделки: игрок, выложенные им предметы и его подтверждение.
+type side struct {
+	player    *model.Player
+	items     []model.TradeItem
+	confirmed bool
+}
+
+// Trade — открытое окно трейда между двумя игроками.
+// Изменяется только под mutex Manager.
+type Trade struct {
+	sides [2]*side
+}
+
+// own возвращает сторону игрока и сторону партнёра.
+func (t *Trade) own(player *model.Player) (own, partner *side) {
+	if t.sides[0].player == player {
+		return t.sides[0], t.sides[1]
+	}
+	return t.sides[1], t.sides[0]
+}
+
+// Manager управляет запросами и открытыми трейдами.
+// Thread-safe: игрок может участвовать только в одном трейде.
+type Manager struct {
+	mu       sync.Mutex
+	byPlayer map[uint32]*Trade  // objectID → trade
+	requests map[uint32]request // target objectID → request
+
+	now func() time.Time
+}
+
+// NewManager создаёт менеджер трейдов.
+func NewManager() *Manager {
+	return &Manager{
+		byPlayer: make(map[uint32]*Trade),
+		requests: make(map[uint32]request),
+		now:      time.Now,
+	}
+}
+
+// IsTrading возвращает true если у игрока открыто окно трейда.
+func (m *Manager) IsTrading(player *model.Player) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.byPlayer[player.ObjectID()] != nil
+}
+
+// Request регистрирует запрос трейда от requester к target.
+func (m *Manager) Request(requester, target *model.Player) error {
+	if requester == target {
+		return ErrSelfTrade
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.byPlayer[requester.ObjectID()] != nil || m.byPlayer[target.ObjectID()] != nil {
+		return ErrAlreadyTrading
+	}
+	if req, ok := m.requests[target.ObjectID()]; ok && m.now().Before(req.expires) {
+		return ErrTargetBusy
+	}
+
+	m.requests[target.ObjectID()] = request{
+		requester: requester,
+		expires:   m.now().Add(RequestTimeout),
+	}
+	return nil
+}
+
+// Answer обрабатывает ответ target на запрос трейда. При accept=true открывает трейд.
+// requester возвращается всегда, если запрос существовал.
+func (m *Manager) Answer(target *model.Player, accept bool) (requester *model.Player, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	req, ok := m.requests[target.ObjectID()]
+	if !ok {
+		return nil, ErrNoRequest
+	}
+	delete(m.requests, target.ObjectID())
+
+	if !accept {
+		return req.requester, nil
+	}
+	if m.now().After(req.expires) {
+		return req.requester, ErrRequestExpired
+	}
+	if m.byPlayer[target.ObjectID()] != nil || m.byPlayer[req.requester.ObjectID()] != nil {
+		return req.requester, ErrAlreadyTrading
+	}
+
+	t := &Trade{sides: [2]*side{{player: req.requester}, {player: target}}}
+	m.byPlayer[req.requester.ObjectID()] = t
+	m.byPlayer[target.ObjectID()] = t
+	return req.requester, nil
+}
+
+// AddItem выкладывает count предметов objectID из инвентаря игрока.
+// Возвращает добавленную позицию и партнёра по трейду.
+func (m *Manager) AddItem(player *model.Player, objectID uint32, count int32) (model.TradeItem, *model.Player, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.byPlayer[player.ObjectID()]
+	if t == nil {
+		return model.TradeItem{}, nil, ErrNotTrading
+	}
+	own, partner := t.own(player)
+	if own.confirmed || partner.confirmed {
+		return model.TradeItem{}, nil, ErrConfirmed
+	}
+
+	item := player.Inventory().ItemByObjectID(objectID)
+	if item == nil || item.IsEquipped() || count <= 0 {
+		return model.TradeItem{}, nil, ErrItemNotTradable
+	}
+	for i := range own.items {
+		if own.items[i].ObjectID == objectID {
+			if int64(own.items[i].Count)+int64(count) > int64(item.Count()) {
+				return model.TradeItem{}, nil, ErrItemNotTradable
+			}
+			own.items[i].Count += count
+			added := own.items[i]
+			added.Count = count
+			return added, partner.player, nil
+		}
+	}
+	if count > item.Count() {
+		return model.TradeItem{}, nil, ErrItemNotTradable
+	}
+
+	added := model.TradeItem{
+		ObjectID: objectID,
+		ItemType: item.ItemType(),
+		Enchant:  item.Enchant(),
+		Count:    count,
+	}
+	own.items = append(own.items, added)
+	return added, partner.player, nil
+}
+
+// Items возвращает копию предметов, выложенных игроком.
+func (m *Manager) Items(player *model.Player) []model.TradeItem {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.byPlayer[player.ObjectID()]
+	if t == nil {
+		return nil
+	}
+	own, _ := t.own(player)
+	items := make([]model.TradeItem, len(own.items))
+	copy(items, own.items)
+	return items
+}
+
+// ConfirmResult — итог подтверждения трейда.
+type ConfirmResult struct {
+	Partner *model.Player
+	Done    bool  // обе стороны подтвердили, трейд закрыт
+	Err     error // обмен не удался, трейд закрыт без изменений инвентарей
+}
+
+// Confirm подтверждает трейд со стороны игрока. Когда подтвердили оба,
+// предметы обмениваются одной атомарной операцией и трейд закрывается.
+func (m *Manager) Confirm(player *model.Player) (ConfirmResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.byPlayer[player.ObjectID()]
+	if t == nil {
+		return ConfirmResult{}, ErrNotTrading
+	}
+	own, partner := t.own(player)
+	own.confirmed = true
+	res := ConfirmResult{Partner: partner.player}
+	if !partner.confirmed {
+		return res, nil
+	}
+
+	m.closeLocked(t)
+	res.Done = true
+	res.Err = exchange(t)
+	return res, nil
+}
+
+// exchange переносит выложенные предметы обеих сторон.
+func exchange(t *Trade) error {
+	a, b := t.sides[0], t.sides[1]
+	transfers := make([]model.ItemTransfer, 0, len(a.items)+len(b.items))
+	for _, s := range []struct{ from, to *side }{{a, b}, {b, a}} {
+		for _, it := range s.from.items {
+			transfers = append(transfers, model.ItemTransfer{
+				From:     s.from.player.Inventory(),
+				To:       s.to.player.Inventory(),
+				ObjectID: it.ObjectID,
+				Count:    it.Count,
+			})
+		}
+	}
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	err := model.Exchange(a.player.Inventory(), b.player.Inventory(), transfers)
+	if errors.Is(err, model.ErrCountOverflow) {
+		return fmt.Errorf("%w: %w", ErrItemOverflow, err)
+	}
+	if err != nil {
+		return fmt.Errorf("exchanging items: %w", err)
+	}
+	return nil
+}
+
+// Cancel закрывает трейд игрока без обмена. Возвращает партнёра (nil если трейда не было).
+func (m *Manager) Cancel(player *model.Player) *model.Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.byPlayer[player.ObjectID()]
+	if t == nil {
+		return nil
+	}
+	m.closeLocked(t)
+	_, partner := t.own(player)
+	return partner.player
+}
+
+// CancelRequests удаляет запросы от и для игрока (выход из игры).
+func (m *Manager) CancelRequests(player *model.Player) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.requests, player.ObjectID())
+	for target, req := range m.requests {
+		if req.requester == player {
+			delete(m.requests, target)
+		}
+	}
+}
+
+// closeLocked убирает трейд у обоих игроков. Вызывается под m.mu.
+func (m *Manager) closeLocked(t *Trade) {
+	for _, s := range t.sides {
+		delete(m.byPlayer, s.player.ObjectID())
+	}
+}
@@ -0,0 +1,179 @@
+package trade
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+const (
+	itemSword  int32 = 100
+	itemArrows int32 = 17
+)
+
+var nextItemID int64 = 5000
+
+func newPlayer(t *testing.T, id int64) *model.Player {
+	t.Helper()
+	p, err := model.NewPlayer(id, 1, "Trader"+string(rune('A'+id)), 20, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	return p
+}
+
+func give(t *testing.T, p *model.Player, itemType, count int32) *model.Item {
+	t.Helper()
+	item, err := model.NewItem(p.CharacterID(), itemType, count)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	nextItemID++
+	item.SetItemID(nextItemID)
+	p.Inventory().AddItem(item)
+	return item
+}
+
+// open открывает трейд между a и b.
+func open(t *testing.T, m *Manager, a, b *model.Player) {
+	t.Helper()
+	if err := m.Request(a, b); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if _, err := m.Answer(b, true); err != nil {
+		t.Fatalf("Answer: %v", err)
+	}
+}
+
+func TestManager_Request(t *testing.T) {
+	m := NewManager()
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+	a, b, c := newPlayer(t, 1), newPlayer(t, 2), newPlayer(t, 3)
+
+	if err := m.Request(a, a); !errors.Is(err, ErrSelfTrade) {
+		t.Errorf("self: err = %v, want ErrSelfTrade", err)
+	}
+	if err := m.Request(a, b); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if err := m.Request(c, b); !errors.Is(err, ErrTargetBusy) {
+		t.Errorf("second request: err = %v, want ErrTargetBusy", err)
+	}
+
+	// Отказ закрывает запрос, трейд не открывается
+	if requester, err := m.Answer(b, false); err != nil || requester != a {
+		t.Fatalf("decline: requester=%v err=%v", requester, err)
+	}
+	if m.IsTrading(a) || m.IsTrading(b) {
+		t.Error("declined request opened a trade")
+	}
+	if _, err := m.Answer(b, true); !errors.Is(err, ErrNoRequest) {
+		t.Errorf("answer without request: err = %v, want ErrNoRequest", err)
+	}
+
+	// Просроченный запрос не открывает трейд
+	if err := m.Request(c, b); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	now = now.Add(RequestTimeout + time.Second)
+	if _, err := m.Answer(b, true); !errors.Is(err, ErrRequestExpired) {
+		t.Errorf("expired: err = %v, want ErrRequestExpired", err)
+	}
+
+	open(t, m, a, b)
+	if !m.IsTrading(a) || !m.IsTrading(b) {
+		t.Fatal("accepted request did not open a trade")
+	}
+	if err := m.Request(c, a); !errors.Is(err, ErrAlreadyTrading) {
+		t.Errorf("request to a trading player: err = %v, want ErrAlreadyTrading", err)
+	}
+	if partner := m.Cancel(b); partner != a || m.IsTrading(a) {
+		t.Errorf("Cancel: partner=%v, trade still open=%v", partner, m.IsTrading(a))
+	}
+}
+
+func TestManager_Trade(t *testing.T) {
+	m := NewManager()
+	a, b := newPlayer(t, 1), newPlayer(t, 2)
+	sword := give(t, a, itemSword, 1)
+	arrows := give(t, a, itemArrows, 100)
+	adena := give(t, b, model.AdenaItemID, 1000)
+	open(t, m, a, b)
+
+	if _, _, err := m.AddItem(a, sword.ObjectID(), 2); !errors.Is(err, ErrItemNotTradable) {
+		t.Errorf("over count: err = %v, want ErrItemNotTradable", err)
+	}
+	if _, _, err := m.AddItem(a, adena.ObjectID(), 1); !errors.Is(err, ErrItemNotTradable) {
+		t.Errorf("partner's item: err = %v, want ErrItemNotTradable", err)
+	}
+	if _, partner, err := m.AddItem(a, sword.ObjectID(), 1); err != nil || partner != b {
+		t.Fatalf("AddItem sword: partner=%v err=%v", partner, err)
+	}
+	// Один стак можно выкладывать частями, но не больше, чем есть
+	for range 2 {
+		if _, _, err := m.AddItem(a, arrows.ObjectID(), 40); err != nil {
+			t.Fatalf("AddItem arrows: %v", err)
+		}
+	}
+	if _, _, err := m.AddItem(a, arrows.ObjectID(), 40); !errors.Is(err, ErrItemNotTradable) {
+		t.Errorf("arrows over stack: err = %v, want ErrItemNotTradable", err)
+	}
+	if items := m.Items(a); len(items) != 2 || items[1].Count != 80 {
+		t.Errorf("items = %+v, want sword and 80 arrows", items)
+	}
+	if _, _, err := m.AddItem(b, adena.ObjectID(), 500); err != nil {
+		t.Fatalf("AddItem adena: %v", err)
+	}
+
+	res, err := m.Confirm(a)
+	if err != nil || res.Done || res.Partner != b {
+		t.Fatalf("first confirm: %+v err=%v", res, err)
+	}
+	if _, _, err := m.AddItem(b, adena.ObjectID(), 1); !errors.Is(err, ErrConfirmed) {
+		t.Errorf("add after confirm: err = %v, want ErrConfirmed", err)
+	}
+
+	res, err = m.Confirm(b)
+	if err != nil || !res.Done || res.Err != nil {
+		t.Fatalf("second confirm: %+v err=%v", res, err)
+	}
+	if m.IsTrading(a) || m.IsTrading(b) {
+		t.Error("trade still open after the exchange")
+	}
+	if b.Inventory().CountOf(itemSword) != 1 || b.Inventory().CountOf(itemArrows) != 80 || b.Inventory().Adena() != 500 {
+		t.Errorf("b: sword=%d arrows=%d adena=%d", b.Inventory().CountOf(itemSword), b.Inventory().CountOf(itemArrows), b.Inventory().Adena())
+	}
+	if a.Inventory().CountOf(itemSword) != 0 || a.Inventory().CountOf(itemArrows) != 20 || a.Inventory().Adena() != 500 {
+		t.Errorf("a: sword=%d arrows=%d adena=%d", a.Inventory().CountOf(itemSword), a.Inventory().CountOf(itemArrows), a.Inventory().Adena())
+	}
+}
+
+func TestManager_TradeOverflow(t *testing.T) {
+	m := NewManager()
+	a, b := newPlayer(t, 1), newPlayer(t, 2)
+	sword := give(t, a, itemSword, 1)
+	adena := give(t, a, model.AdenaItemID, 100)
+	give(t, b, model.AdenaItemID, math.MaxInt32-10)
+	open(t, m, a, b)
+
+	if _, _, err := m.AddItem(a, sword.ObjectID(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.AddItem(a, adena.ObjectID(), 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Confirm(a); err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Confirm(b)
+	if err != nil || !res.Done || !errors.Is(res.Err, ErrItemOverflow) {
+		t.Fatalf("confirm: %+v err=%v, want a closed trade with ErrItemOverflow", res, err)
+	}
+	if a.Inventory().CountOf(itemSword) != 1 || a.Inventory().Adena() != 100 || b.Inventory().CountOf(itemSword) != 0 {
+		t.Error("failed exchange must not move items")
+	}
+}
@@ -116,6 +116,25 @@ func (w *World) RemoveObject(objectID uint32) {
 	}
 }
 
+// MoveObject sets the location of a moving object and moves it to another region
+// when it crosses a region border. Objects outside the world only get the new location.
+func (w *World) MoveObject(obj *model.WorldObject, loc model.Location) {
+	old := obj.Location()
+	obj.SetLocation(loc)
+
+	if _, ok := w.objects.Load(obj.ObjectID()); !ok {
+		return
+	}
+	from, to := w.GetRegion(old.X, old.Y), w.GetRegion(loc.X, loc.Y)
+	if from == to || to == nil {
+		return
+	}
+	if from != nil {
+		from.RemoveVisibleObject(obj.ObjectID())
+	}
+	to.AddVisibleObject(obj)
+}
+
 // GetObject returns object by ID
 func (w *World) GetObject(objectID uint32) (*model.WorldObject, bool) {
 	value, ok := w.objects.Load(objectID)
@@ -128,6 +128,46 @@ func TestWorld_AddRemoveObject(t *testing.T) {
 	}
 }
 
+func TestWorld_MoveObject(t *testing.T) {
+	w := Instance()
+
+	obj := model.NewWorldObject(9998, "Walker", model.NewLocation(17000, 170000, -3500, 0))
+	if err := w.AddObject(obj); err != nil {
+		t.Fatalf("AddObject() error = %v", err)
+	}
+	defer w.RemoveObject(9998)
+
+	inRegion := func(x, y int32) bool {
+		found := false
+		w.GetRegion(x, y).ForEachVisibleObject(func(o *model.WorldObject) bool {
+			found = o.ObjectID() == 9998
+			return !found
+		})
+		return found
+	}
+
+	// Inside the same region only the location changes
+	w.MoveObject(obj, model.NewLocation(17100, 170100, -3500, 0))
+	if obj.X() != 17100 || !inRegion(17000, 170000) {
+		t.Error("object should stay in its region")
+	}
+
+	// Crossing a region border moves the object to the new region
+	w.MoveObject(obj, model.NewLocation(17000+RegionSize, 170000, -3500, 0))
+	if inRegion(17000, 170000) {
+		t.Error("object still in the old region")
+	}
+	if !inRegion(17000+RegionSize, 170000) {
+		t.Error("object not found in the new region")
+	}
+
+	// RemoveObject finds the object in its current region
+	w.RemoveObject(9998)
+	if inRegion(17000+RegionSize, 170000) {
+		t.Error("object still in region after RemoveObject()")
+	}
+}
+
 func TestWorld_AddObject_InvalidCoordinates(t *testing.T) {
 	w := Instance()
 
@@ -0,0 +1,168 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+type filePoint struct {
+	X int32 `yaml:"x"`
+	Y int32 `yaml:"y"`
+	Z int32 `yaml:"z"`
+}
+
+// zoneFile is the YAML layout of zones:
+//
+//	zones:
+//	  - id: 11020
+//	    name: talking_island_town
+//	    type: town              # peace, pvp, town, no_landing, swamp, damage, effect, siege, water, no_restart, jail
+//	    shape: polygon          # cylinder (1 point + radius), cuboid (2 corners) or polygon (3+ corners)
+//	    min_z: -4000
+//	    max_z: -1000
+//	    points:
+//	      - {x: -85000, y: 240000}
+//	      - {x: -81000, y: 240000}
+//	      - {x: -81000, y: 246000}
+//	    town_id: 1              # town: map regions refer to it
+//	    restart: {x: -84318, y: 244579, z: -3730}   # town, jail
+//	    move_speed: 50          # swamp, percent of normal speed
+//	    damage_hp: 200          # damage, HP per effect tick
+//	    skill_id: 4079          # effect
+//	    skill_level: 1
+type zoneFile struct {
+	Zones []struct {
+		ID         int32       `yaml:"id"`
+		Name       string      `yaml:"name"`
+		Type       string      `yaml:"type"`
+		Shape      string      `yaml:"shape"`
+		MinZ       int32       `yaml:"min_z"`
+		MaxZ       int32       `yaml:"max_z"`
+		Radius     int32       `yaml:"radius"`
+		Points     []filePoint `yaml:"points"`
+		TownID     int32       `yaml:"town_id"`
+		Restart    *filePoint  `yaml:"restart"`
+		MoveSpeed  int32       `yaml:"move_speed"`
+		DamageHP   int32       `yaml:"damage_hp"`
+		SkillID    int32       `yaml:"skill_id"`
+		SkillLevel int32       `yaml:"skill_level"`
+	} `yaml:"zones"`
+}
+
+// Load parses zones and adds them to the manager. Returns the number of zones loaded.
+func (m *Manager) Load(r io.Reader) (int, error) {
+	var f zoneFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("parsing zones: %w", err)
+	}
+
+	for _, fz := range f.Zones {
+		t, ok := ParseType(fz.Type)
+		if !ok {
+			return 0, fmt.Errorf("zone %d has unknown type %q: %w", fz.ID, fz.Type, ErrInvalidZone)
+		}
+		if fz.MinZ > fz.MaxZ {
+			return 0, fmt.Errorf("zone %d has min Z %d above max Z %d: %w", fz.ID, fz.MinZ, fz.MaxZ, ErrInvalidZone)
+		}
+		shape, err := parseShape(fz.Shape, fz.Points, fz.Radius, fz.MinZ, fz.MaxZ)
+		if err != nil {
+			return 0, fmt.Errorf("zone %d: %w", fz.ID, err)
+		}
+
+		z := &Zone{
+			ID:         fz.ID,
+			Name:       fz.Name,
+			Type:       t,
+			Shape:      shape,
+			MoveSpeed:  fz.MoveSpeed,
+			DamageHP:   fz.DamageHP,
+			SkillID:    fz.SkillID,
+			SkillLevel: fz.SkillLevel,
+		}
+		switch t {
+		case Town:
+			if fz.Restart == nil {
+				return 0, fmt.Errorf("town zone %d has no restart point: %w", fz.ID, ErrInvalidZone)
+			}
+			z.TownID = fz.TownID
+			z.RestartPoint = model.NewLocation(fz.Restart.X, fz.Restart.Y, fz.Restart.Z, 0)
+		case Jail:
+			if fz.Restart == nil {
+				return 0, fmt.Errorf("jail zone %d has no restart point: %w", fz.ID, ErrInvalidZone)
+			}
+			z.RestartPoint = model.NewLocation(fz.Restart.X, fz.Restart.Y, fz.Restart.Z, 0)
+		case Swamp:
+			if fz.MoveSpeed <= 0 {
+				return 0, fmt.Errorf("swamp zone %d needs a positive move_speed: %w", fz.ID, ErrInvalidZone)
+			}
+		case Damage:
+			if fz.DamageHP <= 0 {
+				return 0, fmt.Errorf("damage zone %d needs a positive damage_hp: %w", fz.ID, ErrInvalidZone)
+			}
+		}
+		if err := m.Add(z); err != nil {
+			return 0, err
+		}
+	}
+	return len(f.Zones), nil
+}
+
+func parseShape(kind string, points []filePoint, radius, minZ, maxZ int32) (Shape, error) {
+	switch kind {
+	case "cylinder":
+		if len(points) != 1 || radius <= 0 {
+			return nil, fmt.Errorf("cylinder needs 1 point and a positive radius: %w", ErrInvalidZone)
+		}
+		return Cylinder{X: points[0].X, Y: points[0].Y, Radius: radius, MinZ: minZ, MaxZ: maxZ}, nil
+
+	case "cuboid":
+		if len(points) != 2 {
+			return nil, fmt.Errorf("cuboid needs 2 corners: %w", ErrInvalidZone)
+		}
+		a, b := points[0], points[1]
+		return Cuboid{
+			MinX: min(a.X, b.X), MinY: min(a.Y, b.Y), MinZ: minZ,
+			MaxX: max(a.X, b.X), MaxY: max(a.Y, b.Y), MaxZ: maxZ,
+		}, nil
+
+	case "polygon":
+		corners := make([]Point, 0, len(points))
+		for _, p := range points {
+			corners = append(corners, Point{X: p.X, Y: p.Y})
+		}
+		prism, ok := NewPrism(corners, minZ, maxZ)
+		if !ok {
+			return nil, fmt.Errorf("polygon needs at least 3 corners: %w", ErrInvalidZone)
+		}
+		return prism, nil
+
+	default:
+		return nil, fmt.Errorf("unknown shape %q: %w", kind, ErrInvalidZone)
+	}
+}
+
+// LoadFile loads zones from a YAML file. A missing file means a world without zones.
+func (m *Manager) LoadFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("zones file not found, the world has no zones", "path", path)
+			return 0, nil
+		}
+		return 0, fmt.Errorf("opening zones %s: %w", path, err)
+	}
+	defer f.Close()
+
+	n, err := m.Load(f)
+	if err != nil {
+		return 0, fmt.Errorf("loading zones %s: %w", path, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,98 @@
+package zone
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_Load(t *testing.T) {
+	data := `
+zones:
+  - id: 1
+    name: gludio_town
+    type: town
+    shape: polygon
+    min_z: -4000
+    max_z: -2000
+    points: [{x: -16000, y: 120000}, {x: -12000, y: 120000}, {x: -12000, y: 126000}, {x: -16000, y: 126000}]
+    town_id: 4
+    restart: {x: -14225, y: 123540, z: -3121}
+  - id: 2
+    name: gludio_arena
+    type: pvp
+    shape: cylinder
+    min_z: -4000
+    max_z: -2000
+    radius: 300
+    points: [{x: -14000, y: 124000}]
+  - id: 3
+    name: swamp
+    type: swamp
+    shape: cuboid
+    min_z: -4000
+    max_z: -2000
+    points: [{x: -10000, y: 120000}, {x: -11000, y: 121000}]
+    move_speed: 50
+  - id: 4
+    name: jail
+    type: jail
+    shape: cuboid
+    min_z: -3500
+    max_z: -2500
+    points: [{x: -115600, y: -250700}, {x: -112500, y: -247600}]
+    restart: {x: -114356, y: -249645, z: -2984}
+`
+	m := NewManager()
+	n, err := m.Load(strings.NewReader(data))
+	if err != nil || n != 4 {
+		t.Fatalf("Load() = %d, %v", n, err)
+	}
+
+	town, _ := m.Zone(1)
+	if town.Type != Town || town.TownID != 4 || town.RestartPoint.X != -14225 {
+		t.Errorf("town = %+v", town)
+	}
+	if !m.IsInside(town.RestartPoint, Town) {
+		t.Error("restart point is outside the town")
+	}
+	swamp, _ := m.Zone(3)
+	if swamp.MoveSpeed != 50 || !swamp.Shape.Contains(-10500, 120500, -3000) {
+		t.Errorf("swamp = %+v", swamp)
+	}
+	if jail, ok := m.Jail(); !ok || jail.ID != 4 || !m.IsInside(jail.RestartPoint, Jail) {
+		t.Errorf("Jail() = %+v, %v; want zone 4 holding its cell", jail, ok)
+	}
+}
+
+func TestManager_LoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown type", "zones:\n  - {id: 1, type: lava, shape: cylinder, radius: 1, points: [{x: 0, y: 0}]}\n"},
+		{"unknown shape", "zones:\n  - {id: 1, type: peace, shape: sphere, points: [{x: 0, y: 0}]}\n"},
+		{"cylinder without radius", "zones:\n  - {id: 1, type: peace, shape: cylinder, points: [{x: 0, y: 0}]}\n"},
+		{"cuboid with 3 corners", "zones:\n  - {id: 1, type: peace, shape: cuboid, points: [{x: 0, y: 0}, {x: 1, y: 1}, {x: 2, y: 2}]}\n"},
+		{"town without restart", "zones:\n  - {id: 1, type: town, town_id: 1, shape: cylinder, radius: 1, points: [{x: 0, y: 0}]}\n"},
+		{"jail without restart", "zones:\n  - {id: 1, type: jail, shape: cylinder, radius: 1, points: [{x: 0, y: 0}]}\n"},
+		{"town without ID", "zones:\n  - {id: 1, type: town, shape: cylinder, radius: 1, points: [{x: 0, y: 0}], restart: {x: 0, y: 0}}\n"},
+		{"damage without damage", "zones:\n  - {id: 1, type: damage, shape: cylinder, radius: 1, points: [{x: 0, y: 0}]}\n"},
+		{"inverted Z", "zones:\n  - {id: 1, type: peace, shape: cylinder, radius: 1, min_z: 5, max_z: 0, points: [{x: 0, y: 0}]}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewManager().Load(strings.NewReader(tt.data)); !errors.Is(err, ErrInvalidZone) {
+				t.Errorf("Load() error = %v, want ErrInvalidZone", err)
+			}
+		})
+	}
+}
+
+func TestManager_LoadFileMissing(t *testing.T) {
+	n, err := NewManager().LoadFile(filepath.Join(t.TempDir(), "zones.yaml"))
+	if err != nil || n != 0 {
+		t.Errorf("LoadFile() = %d, %v; want no zones", n, err)
+	}
+}
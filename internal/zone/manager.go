@@ -0,0 +1,295 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/udisondev/la2go/internal/model"
+	"github.com/udisondev/la2go/internal/world"
+)
+
+// ErrInvalidZone is returned for zone data that cannot be used
+var ErrInvalidZone = errors.New("invalid zone")
+
+// Listener is notified when a tracked object enters or leaves a zone.
+type Listener interface {
+	ZoneEntered(obj *model.WorldObject, z *Zone)
+	ZoneExited(obj *model.WorldObject, z *Zone)
+}
+
+// Manager holds the zones of the world indexed by world region and tracks which zones
+// every object is in. Zones are added at startup; membership is updated by Revalidate
+// as objects move.
+type Manager struct {
+	mu         sync.RWMutex
+	zones      map[int32]*Zone
+	regions    map[int32][]*Zone // region key → zones overlapping the region
+	towns      map[int32]*Zone   // town ID → town zone
+	jail       *Zone             // first jail zone, nil = no jail
+	mapRegions *MapRegions
+	listeners  []Listener
+
+	membersMu sync.RWMutex
+	members   map[uint32][]*Zone // objectID → zones the object is in
+}
+
+// NewManager creates an empty zone manager.
+func NewManager() *Manager {
+	return &Manager{
+		zones:   make(map[int32]*Zone),
+		regions: make(map[int32][]*Zone),
+		towns:   make(map[int32]*Zone),
+		members: make(map[uint32][]*Zone),
+	}
+}
+
+func regionKey(rx, ry int32) int32 {
+	return rx*world.RegionsY + ry
+}
+
+// Add registers a zone in every world region it overlaps.
+func (m *Manager) Add(z *Zone) error {
+	if z.Shape == nil {
+		return fmt.Errorf("zone %d has no shape: %w", z.ID, ErrInvalidZone)
+	}
+	if z.Type >= typeCount {
+		return fmt.Errorf("zone %d has unknown type %d: %w", z.ID, z.Type, ErrInvalidZone)
+	}
+	if z.Type == Town && z.TownID <= 0 {
+		return fmt.Errorf("town zone %d has no town ID: %w", z.ID, ErrInvalidZone)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, dup := m.zones[z.ID]; dup {
+		return fmt.Errorf("zone %d defined twice: %w", z.ID, ErrInvalidZone)
+	}
+	if z.Type == Town {
+		if _, dup := m.towns[z.TownID]; dup {
+			return fmt.Errorf("town %d has two zones: %w", z.TownID, ErrInvalidZone)
+		}
+		m.towns[z.TownID] = z
+	}
+	if z.Type == Jail && m.jail == nil {
+		m.jail = z
+	}
+	m.zones[z.ID] = z
+
+	minX, minY, maxX, maxY := z.Shape.Bounds()
+	rx0, ry0 := world.CoordToRegionIndex(minX, minY)
+	rx1, ry1 := world.CoordToRegionIndex(maxX, maxY)
+	for rx := max(rx0, 0); rx <= min(rx1, world.RegionsX-1); rx++ {
+		for ry := max(ry0, 0); ry <= min(ry1, world.RegionsY-1); ry++ {
+			key := regionKey(rx, ry)
+			m.regions[key] = append(m.regions[key], z)
+		}
+	}
+	return nil
+}
+
+// AddListener subscribes l to zone enter and exit events (at startup only).
+func (m *Manager) AddListener(l Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, l)
+}
+
+// SetMapRegions sets the table of towns players return to from every part of the world.
+func (m *Manager) SetMapRegions(r *MapRegions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mapRegions = r
+}
+
+// Town returns the zone of a town by town ID.
+func (m *Manager) Town(townID int32) (*Zone, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	z, ok := m.towns[townID]
+	return z, ok
+}
+
+// Jail returns the zone jailed players are kept in.
+func (m *Manager) Jail() (*Zone, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.jail, m.jail != nil
+}
+
+// Zone returns a zone by ID.
+func (m *Manager) Zone(id int32) (*Zone, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	z, ok := m.zones[id]
+	return z, ok
+}
+
+// Count returns the number of zones.
+func (m *Manager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.zones)
+}
+
+// ZonesAt returns the zones containing a point.
+func (m *Manager) ZonesAt(x, y, z int32) []*Zone {
+	rx, ry := world.CoordToRegionIndex(x, y)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var found []*Zone
+	for _, zn := range m.regions[regionKey(rx, ry)] {
+		if zn.Shape.Contains(x, y, z) {
+			found = append(found, zn)
+		}
+	}
+	return found
+}
+
+// IsInside reports whether a point is inside a zone of type t, e.g. to check a target
+// location before an object gets there.
+func (m *Manager) IsInside(loc model.Location, t Type) bool {
+	for _, z := range m.ZonesAt(loc.X, loc.Y, loc.Z) {
+		if z.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Revalidate updates the zones of an object at its current location and notifies
+// listeners of the zones it left and entered. Returns whether the zones changed.
+func (m *Manager) Revalidate(obj *model.WorldObject) bool {
+	loc := obj.Location()
+	now := m.ZonesAt(loc.X, loc.Y, loc.Z)
+
+	m.membersMu.Lock()
+	old := m.members[obj.ObjectID()]
+	if len(now) == 0 {
+		delete(m.members, obj.ObjectID())
+	} else {
+		m.members[obj.ObjectID()] = now
+	}
+	m.membersMu.Unlock()
+
+	var exited, entered []*Zone
+	for _, z := range old {
+		if !slices.Contains(now, z) {
+			exited = append(exited, z)
+		}
+	}
+	for _, z := range now {
+		if !slices.Contains(old, z) {
+			entered = append(entered, z)
+		}
+	}
+	if len(exited) == 0 && len(entered) == 0 {
+		return false
+	}
+
+	m.mu.RLock()
+	listeners := m.listeners
+	m.mu.RUnlock()
+	for _, l := range listeners {
+		for _, z := range exited {
+			l.ZoneExited(obj, z)
+		}
+		for _, z := range entered {
+			l.ZoneEntered(obj, z)
+		}
+	}
+	return true
+}
+
+// Forget stops tracking an object that left the world; no exit events are fired.
+func (m *Manager) Forget(objectID uint32) {
+	m.membersMu.Lock()
+	defer m.membersMu.Unlock()
+	delete(m.members, objectID)
+}
+
+// InsideZone reports whether a tracked object is inside a zone of type t
+// (as of its last Revalidate).
+func (m *Manager) InsideZone(obj *model.WorldObject, t Type) bool {
+	m.membersMu.RLock()
+	defer m.membersMu.RUnlock()
+	for _, z := range m.members[obj.ObjectID()] {
+		if z.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ZonesOf returns the zones a tracked object is in.
+func (m *Manager) ZonesOf(obj *model.WorldObject) []*Zone {
+	m.membersMu.RLock()
+	defer m.membersMu.RUnlock()
+	return slices.Clone(m.members[obj.ObjectID()])
+}
+
+// ForEachInside calls fn for every tracked object inside a zone of type t
+// (once per zone the object is in). fn runs outside the manager locks.
+func (m *Manager) ForEachInside(t Type, fn func(objectID uint32, z *Zone)) {
+	type hit struct {
+		objectID uint32
+		zone     *Zone
+	}
+	var hits []hit
+	m.membersMu.RLock()
+	for id, zones := range m.members {
+		for _, z := range zones {
+			if z.Type == t {
+				hits = append(hits, hit{id, z})
+			}
+		}
+	}
+	m.membersMu.RUnlock()
+
+	for _, h := range hits {
+		fn(h.objectID, h.zone)
+	}
+}
+
+// ClosestTown returns the town players at loc return to: the town around loc, otherwise
+// the town of the map region of loc, otherwise the town with the nearest restart point.
+// ok is false without towns.
+func (m *Manager) ClosestTown(loc model.Location) (*Zone, bool) {
+	for _, z := range m.ZonesAt(loc.X, loc.Y, loc.Z) {
+		if z.Type == Town {
+			return z, true
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if id, ok := m.mapRegions.TownAt(loc.X, loc.Y); ok {
+		if z, ok := m.towns[id]; ok {
+			return z, true
+		}
+	}
+
+	var (
+		best     *Zone
+		bestDist = math.Inf(1)
+	)
+	for _, z := range m.towns {
+		dx, dy := float64(z.RestartPoint.X-loc.X), float64(z.RestartPoint.Y-loc.Y)
+		if d := dx*dx + dy*dy; d < bestDist {
+			best, bestDist = z, d
+		}
+	}
+	return best, best != nil
+}
+
+// RestartPoint returns where a player at loc returns to village: the restart point
+// of ClosestTown.
+func (m *Manager) RestartPoint(loc model.Location) (model.Location, bool) {
+	z, ok := m.ClosestTown(loc)
+	if !ok {
+		return model.Location{}, false
+	}
+	return z.RestartPoint, true
+}
@@ -0,0 +1,133 @@
+package zone
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// eventRecorder records zone events as "+name" and "-name".
+type eventRecorder struct {
+	events []string
+}
+
+func (r *eventRecorder) ZoneEntered(_ *model.WorldObject, z *Zone) {
+	r.events = append(r.events, "+"+z.Name)
+}
+
+func (r *eventRecorder) ZoneExited(_ *model.WorldObject, z *Zone) {
+	r.events = append(r.events, "-"+z.Name)
+}
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	m := NewManager()
+	zones := []*Zone{
+		// The town spans a region border (x = 0)
+		{ID: 1, Name: "town", Type: Town, TownID: 1, Shape: Cuboid{MinX: -3000, MinY: 0, MinZ: -1000, MaxX: 3000, MaxY: 3000, MaxZ: 1000},
+			RestartPoint: model.NewLocation(100, 100, 0, 0)},
+		{ID: 2, Name: "square", Type: Peace, Shape: Cylinder{X: 1000, Y: 1000, Radius: 500, MinZ: -1000, MaxZ: 1000}},
+		{ID: 3, Name: "arena", Type: PvP, Shape: Cuboid{MinX: 20000, MinY: 20000, MinZ: -1000, MaxX: 21000, MaxY: 21000, MaxZ: 1000}},
+		{ID: 4, Name: "far_town", Type: Town, TownID: 2, Shape: Cuboid{MinX: 50000, MinY: 50000, MinZ: -1000, MaxX: 51000, MaxY: 51000, MaxZ: 1000},
+			RestartPoint: model.NewLocation(50500, 50500, 0, 0)},
+	}
+	for _, z := range zones {
+		if err := m.Add(z); err != nil {
+			t.Fatalf("Add(%s) error = %v", z.Name, err)
+		}
+	}
+	return m
+}
+
+func TestManager_Revalidate(t *testing.T) {
+	m := newTestManager(t)
+	rec := &eventRecorder{}
+	m.AddListener(rec)
+	obj := model.NewWorldObject(100, "Walker", model.NewLocation(-2000, 500, 0, 0))
+
+	// West of the region border, in the town only
+	if !m.Revalidate(obj) || !m.InsideZone(obj, Town) || m.InsideZone(obj, Peace) {
+		t.Fatalf("at %v: zones = %v", obj.Location(), m.ZonesOf(obj))
+	}
+
+	// Into the square: still in the town
+	obj.SetLocation(model.NewLocation(1000, 1200, 0, 0))
+	m.Revalidate(obj)
+	if !m.InsideZone(obj, Peace) || !m.InsideZone(obj, Town) {
+		t.Errorf("in the square: zones = %v", m.ZonesOf(obj))
+	}
+	if m.Revalidate(obj) {
+		t.Error("Revalidate() without moving reported a change")
+	}
+
+	// Out of both at once
+	obj.SetLocation(model.NewLocation(10000, 10000, 0, 0))
+	m.Revalidate(obj)
+	if m.InsideZone(obj, Town) || m.InsideZone(obj, Peace) {
+		t.Errorf("outside: zones = %v", m.ZonesOf(obj))
+	}
+
+	want := []string{"+town", "+square", "-town", "-square"}
+	if len(rec.events) != len(want) {
+		t.Fatalf("events = %v, want %v", rec.events, want)
+	}
+	for i := range want {
+		if rec.events[i] != want[i] {
+			t.Errorf("events = %v, want %v", rec.events, want)
+			break
+		}
+	}
+}
+
+func TestManager_ForgetAndForEachInside(t *testing.T) {
+	m := newTestManager(t)
+	a := model.NewWorldObject(101, "A", model.NewLocation(20500, 20500, 0, 0))
+	b := model.NewWorldObject(102, "B", model.NewLocation(20600, 20600, 0, 0))
+	m.Revalidate(a)
+	m.Revalidate(b)
+
+	inside := map[uint32]bool{}
+	m.ForEachInside(PvP, func(id uint32, z *Zone) { inside[id] = z.Name == "arena" })
+	if !inside[101] || !inside[102] || len(inside) != 2 {
+		t.Errorf("ForEachInside(PvP) = %v", inside)
+	}
+
+	m.Forget(a.ObjectID())
+	if m.InsideZone(a, PvP) {
+		t.Error("forgotten object still inside the arena")
+	}
+}
+
+func TestManager_RestartPoint(t *testing.T) {
+	m := newTestManager(t)
+	tests := []struct {
+		name string
+		loc  model.Location
+		want model.Location
+	}{
+		{"inside town", model.NewLocation(2500, 2500, 0, 0), model.NewLocation(100, 100, 0, 0)},
+		{"near far town", model.NewLocation(45000, 45000, 0, 0), model.NewLocation(50500, 50500, 0, 0)},
+		{"near town", model.NewLocation(10000, 10000, 0, 0), model.NewLocation(100, 100, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.RestartPoint(tt.loc)
+			if !ok || got != tt.want {
+				t.Errorf("RestartPoint(%v) = %v, %v; want %v", tt.loc, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := NewManager().RestartPoint(model.NewLocation(0, 0, 0, 0)); ok {
+		t.Error("RestartPoint() without towns reported a point")
+	}
+}
+
+func TestManager_AddDuplicate(t *testing.T) {
+	m := newTestManager(t)
+	err := m.Add(&Zone{ID: 1, Type: Peace, Shape: Cylinder{Radius: 1}})
+	if !errors.Is(err, ErrInvalidZone) {
+		t.Errorf("Add() duplicate error = %v, want ErrInvalidZone", err)
+	}
+}
@@ -0,0 +1,101 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Map region tiles are 32768 units wide; tile (0, 0) starts at
+// (-9 << 15, -10 << 15) as in L2J MapRegionTable.
+const (
+	mapRegionShift   = 15
+	mapRegionOffsetX = 9
+	mapRegionOffsetY = 10
+)
+
+// MapRegionTile returns the map region tile of a point.
+func MapRegionTile(x, y int32) (tx, ty int32) {
+	return (x >> mapRegionShift) + mapRegionOffsetX, (y >> mapRegionShift) + mapRegionOffsetY
+}
+
+// MapRegions assigns map region tiles to the towns players of the tile return to.
+// Immutable after loading.
+type MapRegions struct {
+	towns map[[2]int32]int32 // tile → town ID
+}
+
+// TownAt returns the town ID of the tile containing (x, y).
+func (r *MapRegions) TownAt(x, y int32) (int32, bool) {
+	if r == nil {
+		return 0, false
+	}
+	tx, ty := MapRegionTile(x, y)
+	id, ok := r.towns[[2]int32{tx, ty}]
+	return id, ok
+}
+
+// Len returns the number of mapped tiles.
+func (r *MapRegions) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.towns)
+}
+
+// mapRegionFile is the YAML layout of map regions:
+//
+//	regions:
+//	  - {x: 6, y: 17, town: 1}   # tile indexes, see MapRegionTile
+type mapRegionFile struct {
+	Regions []struct {
+		X    int32 `yaml:"x"`
+		Y    int32 `yaml:"y"`
+		Town int32 `yaml:"town"`
+	} `yaml:"regions"`
+}
+
+// LoadMapRegions parses a map region table.
+func LoadMapRegions(r io.Reader) (*MapRegions, error) {
+	var f mapRegionFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing map regions: %w", err)
+	}
+
+	regions := &MapRegions{towns: make(map[[2]int32]int32, len(f.Regions))}
+	for _, fr := range f.Regions {
+		tile := [2]int32{fr.X, fr.Y}
+		if fr.Town <= 0 {
+			return nil, fmt.Errorf("map region %v has no town: %w", tile, ErrInvalidZone)
+		}
+		if _, dup := regions.towns[tile]; dup {
+			return nil, fmt.Errorf("map region %v defined twice: %w", tile, ErrInvalidZone)
+		}
+		regions.towns[tile] = fr.Town
+	}
+	return regions, nil
+}
+
+// LoadMapRegionsFile loads a map region table from a YAML file.
+// A missing file means players return to the nearest town.
+func LoadMapRegionsFile(path string) (*MapRegions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("map regions file not found, players return to the nearest town", "path", path)
+			return &MapRegions{}, nil
+		}
+		return nil, fmt.Errorf("opening map regions %s: %w", path, err)
+	}
+	defer f.Close()
+
+	regions, err := LoadMapRegions(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading map regions %s: %w", path, err)
+	}
+	return regions, nil
+}
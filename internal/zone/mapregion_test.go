@@ -0,0 +1,58 @@
+package zone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+func TestMapRegionTile(t *testing.T) {
+	tests := []struct {
+		x, y   int32
+		tx, ty int32
+	}{
+		{0, 0, 9, 10},
+		{32767, 32767, 9, 10},
+		{32768, -1, 10, 9},
+		{-84000, 244000, 6, 17},
+	}
+	for _, tt := range tests {
+		if tx, ty := MapRegionTile(tt.x, tt.y); tx != tt.tx || ty != tt.ty {
+			t.Errorf("MapRegionTile(%d, %d) = (%d, %d), want (%d, %d)", tt.x, tt.y, tx, ty, tt.tx, tt.ty)
+		}
+	}
+}
+
+func TestManager_ClosestTownByMapRegion(t *testing.T) {
+	m := newTestManager(t)
+	// (100000, 100000) is closer to far_town, but its map region belongs to the first town
+	regions, err := LoadMapRegions(strings.NewReader("regions:\n  - {x: 12, y: 13, town: 1}\n"))
+	if err != nil {
+		t.Fatalf("LoadMapRegions() error = %v", err)
+	}
+	m.SetMapRegions(regions)
+
+	z, ok := m.ClosestTown(model.NewLocation(100000, 100000, 0, 0))
+	if !ok || z.TownID != 1 {
+		t.Errorf("ClosestTown() = %+v, want town 1 of the map region", z)
+	}
+
+	// Unmapped tiles fall back to the nearest town
+	z, ok = m.ClosestTown(model.NewLocation(60000, 60000, 0, 0))
+	if !ok || z.TownID != 2 {
+		t.Errorf("ClosestTown() = %+v, want the nearest town 2", z)
+	}
+}
+
+func TestLoadMapRegions_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"regions:\n  - {x: 1, y: 1}\n",
+		"regions:\n  - {x: 1, y: 1, town: 1}\n  - {x: 1, y: 1, town: 2}\n",
+	} {
+		if _, err := LoadMapRegions(strings.NewReader(data)); !errors.Is(err, ErrInvalidZone) {
+			t.Errorf("LoadMapRegions(%q) error = %v, want ErrInvalidZone", data, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package zone
+
+// Shape is the volume of a zone.
+type Shape interface {
+	Contains(x, y, z int32) bool
+	// Bounds returns the bounding rectangle, used to index the zone by world region.
+	Bounds() (minX, minY, maxX, maxY int32)
+}
+
+// Cylinder is a vertical cylinder around (X, Y).
+type Cylinder struct {
+	X, Y       int32
+	Radius     int32
+	MinZ, MaxZ int32
+}
+
+// Contains implements Shape.
+func (c Cylinder) Contains(x, y, z int32) bool {
+	if z < c.MinZ || z > c.MaxZ {
+		return false
+	}
+	dx, dy := int64(x-c.X), int64(y-c.Y)
+	return dx*dx+dy*dy <= int64(c.Radius)*int64(c.Radius)
+}
+
+// Bounds implements Shape.
+func (c Cylinder) Bounds() (minX, minY, maxX, maxY int32) {
+	return c.X - c.Radius, c.Y - c.Radius, c.X + c.Radius, c.Y + c.Radius
+}
+
+// Cuboid is an axis-aligned box.
+type Cuboid struct {
+	MinX, MinY, MinZ int32
+	MaxX, MaxY, MaxZ int32
+}
+
+// Contains implements Shape.
+func (c Cuboid) Contains(x, y, z int32) bool {
+	return x >= c.MinX && x <= c.MaxX &&
+		y >= c.MinY && y <= c.MaxY &&
+		z >= c.MinZ && z <= c.MaxZ
+}
+
+// Bounds implements Shape.
+func (c Cuboid) Bounds() (minX, minY, maxX, maxY int32) {
+	return c.MinX, c.MinY, c.MaxX, c.MaxY
+}
+
+// Point is a corner of a prism base.
+type Point struct {
+	X, Y int32
+}
+
+// Prism is a polygon extruded between two heights (L2J NPoly zone form).
+type Prism struct {
+	points                 []Point
+	minZ, maxZ             int32
+	minX, minY, maxX, maxY int32
+}
+
+// NewPrism creates a prism; the polygon needs at least 3 corners.
+func NewPrism(points []Point, minZ, maxZ int32) (*Prism, bool) {
+	if len(points) < 3 || minZ > maxZ {
+		return nil, false
+	}
+	p := &Prism{
+		points: append([]Point(nil), points...),
+		minZ:   minZ,
+		maxZ:   maxZ,
+		minX:   points[0].X,
+		minY:   points[0].Y,
+		maxX:   points[0].X,
+		maxY:   points[0].Y,
+	}
+	for _, pt := range points[1:] {
+		p.minX, p.maxX = min(p.minX, pt.X), max(p.maxX, pt.X)
+		p.minY, p.maxY = min(p.minY, pt.Y), max(p.maxY, pt.Y)
+	}
+	return p, true
+}
+
+// Contains implements Shape (even-odd rule).
+func (p *Prism) Contains(x, y, z int32) bool {
+	if z < p.minZ || z > p.maxZ || x < p.minX || x > p.maxX || y < p.minY || y > p.maxY {
+		return false
+	}
+	inside := false
+	px, py := float64(x), float64(y)
+	for i, j := 0, len(p.points)-1; i < len(p.points); j, i = i, i+1 {
+		ax, ay := float64(p.points[i].X), float64(p.points[i].Y)
+		bx, by := float64(p.points[j].X), float64(p.points[j].Y)
+		if (ay > py) != (by > py) && px < (bx-ax)*(py-ay)/(by-ay)+ax {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Bounds implements Shape.
+func (p *Prism) Bounds() (minX, minY, maxX, maxY int32) {
+	return p.minX, p.minY, p.maxX, p.maxY
+}
@@ -0,0 +1,42 @@
+package zone
+
+import "testing"
+
+func TestShapes_Contains(t *testing.T) {
+	prism, ok := NewPrism([]Point{{0, 0}, {1000, 0}, {0, 1000}}, -100, 100)
+	if !ok {
+		t.Fatal("NewPrism() failed")
+	}
+	tests := []struct {
+		name    string
+		shape   Shape
+		x, y, z int32
+		want    bool
+	}{
+		{"cylinder center", Cylinder{X: 500, Y: 500, Radius: 100, MinZ: -100, MaxZ: 100}, 500, 500, 0, true},
+		{"cylinder edge", Cylinder{X: 500, Y: 500, Radius: 100, MinZ: -100, MaxZ: 100}, 600, 500, 0, true},
+		{"cylinder corner of bounds", Cylinder{X: 500, Y: 500, Radius: 100, MinZ: -100, MaxZ: 100}, 590, 590, 0, false},
+		{"cylinder above", Cylinder{X: 500, Y: 500, Radius: 100, MinZ: -100, MaxZ: 100}, 500, 500, 101, false},
+		{"cuboid inside", Cuboid{MinX: 0, MinY: 0, MinZ: 0, MaxX: 10, MaxY: 10, MaxZ: 10}, 10, 0, 5, true},
+		{"cuboid outside", Cuboid{MinX: 0, MinY: 0, MinZ: 0, MaxX: 10, MaxY: 10, MaxZ: 10}, 11, 0, 5, false},
+		{"prism inside", prism, 100, 100, 0, true},
+		{"prism beyond hypotenuse", prism, 600, 600, 0, false},
+		{"prism below", prism, 100, 100, -200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.Contains(tt.x, tt.y, tt.z); got != tt.want {
+				t.Errorf("Contains(%d, %d, %d) = %v, want %v", tt.x, tt.y, tt.z, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPrism_Invalid(t *testing.T) {
+	if _, ok := NewPrism([]Point{{0, 0}, {1, 1}}, 0, 0); ok {
+		t.Error("prism with 2 corners accepted")
+	}
+	if _, ok := NewPrism([]Point{{0, 0}, {1, 0}, {0, 1}}, 10, 0); ok {
+		t.Error("prism with inverted heights accepted")
+	}
+}
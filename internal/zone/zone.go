@@ -0,0 +1,74 @@
+package zone
+
+import (
+	"fmt"
+
+	"github.com/udisondev/la2go/internal/model"
+)
+
+// Type is the kind of a zone; it decides what the zone changes for objects inside.
+type Type uint8
+
+const (
+	Peace     Type = iota // no attacking, monsters leave players alone
+	PvP                   // arena: fighting players gives no karma
+	Town                  // restart point of players killed nearby
+	NoLanding             // wyverns cannot land
+	Swamp                 // slows movement down
+	Damage                // takes HP of players inside every effect tick
+	Effect                // casts a skill on players inside
+	Siege                 // castle siege battlefield
+	Water                 // swimming
+	NoRestart             // players logging in inside are moved to the nearest town
+	Jail                  // where jailed players are kept
+
+	typeCount
+)
+
+var typeNames = [typeCount]string{
+	Peace:     "peace",
+	PvP:       "pvp",
+	Town:      "town",
+	NoLanding: "no_landing",
+	Swamp:     "swamp",
+	Damage:    "damage",
+	Effect:    "effect",
+	Siege:     "siege",
+	Water:     "water",
+	NoRestart: "no_restart",
+	Jail:      "jail",
+}
+
+// String returns the data file name of the zone type.
+func (t Type) String() string {
+	if t < typeCount {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", uint8(t))
+}
+
+// ParseType returns the zone type with the given data file name.
+func ParseType(s string) (Type, bool) {
+	for t, name := range typeNames {
+		if name == s {
+			return Type(t), true
+		}
+	}
+	return 0, false
+}
+
+// Zone is an area of the world with special rules. Immutable after loading;
+// settings not used by its type are zero.
+type Zone struct {
+	ID    int32
+	Name  string
+	Type  Type
+	Shape Shape
+
+	TownID       int32          // Town: the town map regions refer to
+	RestartPoint model.Location // Town: where players of the town's map regions return to; Jail: the cell
+	MoveSpeed    int32          // Swamp: move speed inside, percent of normal
+	DamageHP     int32          // Damage: HP taken every effect tick
+	SkillID      int32          // Effect: skill cast on players inside
+	SkillLevel   int32
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/udisondev/la2go/internal/gameserver"
 	"github.com/udisondev/la2go/internal/gslistener"
 	"github.com/udisondev/la2go/internal/login"
+	"github.com/udisondev/la2go/internal/login/serverpackets"
 	"github.com/udisondev/la2go/internal/testutil"
 )
 
@@ -49,6 +50,7 @@ func (s *CrossServerSuite) SetupSuite() {
 			{ID: 1, Name: "TestServer1", Host: "127.0.0.1", Port: 7777},
 			{ID: 2, Name: "TestServer2", Host: "127.0.0.1", Port: 7777},
 			{ID: 3, Name: "TestServer3", Host: "127.0.0.1", Port: 7777},
+			{ID: 4, Name: "TestServer4", Host: "127.0.0.1", Port: 7777},
 			// Дополнительные серверы для TestConcurrentPlayerAuthRequests
 			{ID: 101, Name: "ConcurrentTestServer1", Host: "127.0.0.1", Port: 7777},
 			{ID: 102, Name: "ConcurrentTestServer2", Host: "127.0.0.1", Port: 7777},
@@ -68,6 +70,7 @@ func (s *CrossServerSuite) SetupSuite() {
 	if err != nil {
 		s.T().Fatalf("failed to create gslistener: %v", err)
 	}
+	s.loginServer.AddKicker(s.gsListener)
 
 	// Запускаем LoginServer
 	lsListener, lsAddr := testutil.ListenTCP(s.T())
@@ -448,6 +451,85 @@ func (s *CrossServerSuite) TestConcurrentPlayerAuthRequests() {
 	s.Equal(0, count, "session should be removed after validation")
 }
 
+// authLogin подключает клиента к LoginServer и отправляет RequestAuthLogin.
+func (s *CrossServerSuite) authLogin(account string) *testutil.LoginClient {
+	lsClient, err := testutil.NewLoginClient(s.T(), s.lsAddr)
+	s.Require().NoError(err)
+	s.Require().NoError(lsClient.SendAuthGameGuard())
+	s.Require().NoError(lsClient.ReadGGAuth())
+	s.Require().NoError(lsClient.SendRequestAuthLogin(account, "password"))
+	return lsClient
+}
+
+// syncGS дожидается, пока gslistener обработает отправленные GameServer пакеты:
+// ответ на PlayerAuthRequest приходит после них.
+func (s *CrossServerSuite) syncGS(gsClient *testutil.GSClient) {
+	s.Require().NoError(gsClient.SendPlayerAuthRequest("sync", login.SessionKey{}))
+	_, _, err := gsClient.ReadPlayerAuthResponse()
+	s.Require().NoError(err)
+}
+
+// TestDuplicateLoginClosesLoginSession: второй вход аккаунта, пока первый клиент
+// подключён к LoginServer, закрывает первого, а второй получает AccountInUse.
+func (s *CrossServerSuite) TestDuplicateLoginClosesLoginSession() {
+	account := "dup_login"
+	defer s.sessionMgr.Remove(account)
+
+	first := s.authLogin(account)
+	defer first.Close()
+	_, _, err := first.ReadLoginOk()
+	s.Require().NoError(err)
+
+	second := s.authLogin(account)
+	defer second.Close()
+	reason, err := second.ReadLoginFail()
+	s.Require().NoError(err)
+	s.Equal(serverpackets.ReasonAccountInUse, reason)
+
+	_, err = first.ReadPacket()
+	s.Error(err, "first client should be disconnected")
+
+	// Повторный вход проходит
+	retry := s.authLogin(account)
+	defer retry.Close()
+	_, _, err = retry.ReadLoginOk()
+	s.NoError(err)
+}
+
+// TestDuplicateLoginKicksGameServer: вход аккаунта, который в игре, отправляет
+// KickPlayer его GameServer; после PlayerLogout вход проходит.
+func (s *CrossServerSuite) TestDuplicateLoginKicksGameServer() {
+	account := "dup_ingame"
+	defer s.sessionMgr.Remove(account)
+
+	gsClient, err := testutil.NewGSClient(s.T(), s.gsAddr)
+	s.Require().NoError(err)
+	defer gsClient.Close()
+	s.Require().NoError(gsClient.CompleteRegistration(4, "kick_test_hex_id_000000000000"))
+
+	s.Require().NoError(gsClient.SendPlayerInGame(account))
+	s.syncGS(gsClient)
+
+	lsClient := s.authLogin(account)
+	defer lsClient.Close()
+	reason, err := lsClient.ReadLoginFail()
+	s.Require().NoError(err)
+	s.Equal(serverpackets.ReasonAccountInUse, reason)
+
+	kicked, err := gsClient.ReadKickPlayer()
+	s.Require().NoError(err)
+	s.Equal(account, kicked)
+
+	// GameServer выгнал игрока
+	s.Require().NoError(gsClient.SendPlayerLogout(account))
+	s.syncGS(gsClient)
+
+	retry := s.authLogin(account)
+	defer retry.Close()
+	_, _, err = retry.ReadLoginOk()
+	s.NoError(err)
+}
+
 // TestCrossServerSuite запускает CrossServerSuite.
 func TestCrossServerSuite(t *testing.T) {
 	if testing.Short() {